@@ -3,16 +3,23 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/morty/morty/internal/cmd"
 	"github.com/morty/morty/internal/config"
 	"github.com/morty/morty/internal/logging"
+	"github.com/morty/morty/internal/parser/plan"
+	"github.com/morty/morty/internal/redact"
 )
 
 // Build info variables - set by ldflags during build
@@ -49,48 +56,48 @@ func main() {
 		os.Exit(0)
 	}
 
+	// --profile may appear anywhere on the command line; it is removed
+	// before the command parses its own flags
+	profile, args, err := splitProfileFlag(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	os.Args = append(os.Args[:1], args...)
+	if len(os.Args) < 2 {
+		printHelp()
+		os.Exit(1)
+	}
+
 	// Get the command
 	command := os.Args[1]
 
-	// Load global configuration
-	// Try to load from multiple locations:
-	// 1) ~/.morty/config.json (user home)
-	// 2) <binary_dir>/../config.json (installation directory)
-	// 3) ./.morty/config.json (project local)
+	// Load configuration following the documented hierarchy:
+	// defaults → user config → project config (.morty/settings.json) →
+	// the selected profile →
+	// environment variables. An invalid configuration stops the commands
+	// that run the AI CLI; the other commands fall back to the defaults, and
+	// config and doctor report the problems.
+	loadedConfig := config.NewLoader()
+	loadedConfig.SetProfile(profile)
+	if legacy := loadedConfig.LegacyProjectConfigFile(); legacy != "" {
+		fmt.Fprintf(os.Stderr, "Warning: %s is deprecated; move it to %s\n", legacy, loadedConfig.SettingsFile())
+	}
 	var cfgLoader *config.Loader
-
-	configPaths := []string{
-		os.ExpandEnv("${HOME}/.morty/config.json"),
-	}
-
-	// Add binary directory config path
-	if execPath, err := os.Executable(); err == nil {
-		binDir := filepath.Dir(execPath)
-		installDir := filepath.Dir(binDir)
-		configPaths = append(configPaths, filepath.Join(installDir, "config.json"))
-	}
-
-	configPaths = append(configPaths, "./.morty/config.json")
-
-	for _, configPath := range configPaths {
-		if _, err := os.Stat(configPath); err == nil {
-			cfgLoader = config.NewLoader()
-			if err := cfgLoader.Load(configPath); err == nil {
-				// Successfully loaded
-				// Debug: print loaded config path and prompts dir
-				if os.Getenv("MORTY_DEBUG") != "" {
-					fmt.Fprintf(os.Stderr, "DEBUG: Loaded config from: %s\n", configPath)
-					cfg := cfgLoader.Config()
-					if cfg != nil {
-						fmt.Fprintf(os.Stderr, "DEBUG: Config.Prompts.Dir = %q\n", cfg.Prompts.Dir)
-					} else {
-						fmt.Fprintf(os.Stderr, "DEBUG: Config is nil!\n")
-					}
-				}
-				break
-			}
-			cfgLoader = nil
+	if err := loadedConfig.LoadWithMerge(userConfigPath()); err != nil {
+		if errors.Is(err, config.ErrUnknownProfile) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
+		if requiresValidConfig(command) {
+			fmt.Fprintf(os.Stderr, "Error: %v (run 'morty config validate')\n", err)
+			os.Exit(1)
+		}
+		if command != "config" && command != "doctor" {
+			fmt.Fprintf(os.Stderr, "Warning: %v (run 'morty config validate')\n", err)
+		}
+	} else {
+		cfgLoader = loadedConfig
 	}
 
 	// Setup logging
@@ -112,6 +119,16 @@ func main() {
 		os.Exit(1)
 	}
 
+	if cfgLoader != nil {
+		logger.Debug("Configuration loaded",
+			logging.String("config_file", cfgLoader.GetConfigFile()),
+			logging.String("prompts_dir", cfgLoader.Config().Prompts.Dir),
+		)
+	}
+
+	applyPlanHeadings(cfgLoader, logger)
+	applyRedaction(cfgLoader, logger)
+
 	// Create paths with config loader
 	var cfg *config.Paths
 	if cfgLoader != nil {
@@ -128,10 +145,28 @@ func main() {
 		handlePlan(cfg, cfgLoader, logger, os.Args[2:])
 	case "doing":
 		handleDoing(cfg, cfgLoader, logger, os.Args[2:])
+	case "pause":
+		handlePause(cfg, cfgLoader, logger, os.Args[2:])
+	case "resume":
+		handleResume(cfg, cfgLoader, logger, os.Args[2:])
 	case "stat", "status":
 		handleStat(cfg, logger, os.Args[2:])
 	case "reset":
 		handleReset(cfg, logger, os.Args[2:])
+	case "graph":
+		handleGraph(cfg, cfgLoader, logger, os.Args[2:])
+	case "trace":
+		handleTrace(cfg, cfgLoader, logger, os.Args[2:])
+	case "logs":
+		handleLogs(cfg, cfgLoader, logger, os.Args[2:])
+	case "transcript":
+		handleTranscript(cfg, cfgLoader, logger, os.Args[2:])
+	case "config":
+		handleConfig(loadedConfig, logger, os.Args[2:])
+	case "doctor":
+		handleDoctor(loadedConfig, logger, os.Args[2:])
+	case "prompts":
+		handlePrompts(cfg, cfgLoader, logger, os.Args[2:])
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", command)
 		printHelp()
@@ -139,6 +174,16 @@ func main() {
 	}
 }
 
+// requiresValidConfig reports whether a command must not run with the
+// default configuration when the configuration fails to load.
+func requiresValidConfig(command string) bool {
+	switch command {
+	case "doing", "plan", "research":
+		return true
+	}
+	return false
+}
+
 func printHelp() {
 	fmt.Println("Morty - AI Coding Workflow Orchestrator")
 	fmt.Println()
@@ -148,11 +193,23 @@ func printHelp() {
 	fmt.Println("  research    Research mode - analyze requirements")
 	fmt.Println("  plan        Plan mode - create development plans")
 	fmt.Println("  doing       Doing mode - execute tasks")
+	fmt.Println("  pause       Pause a running doing after the current job")
+	fmt.Println("  resume      Resume a paused doing")
 	fmt.Println("  stat        Show current status")
 	fmt.Println("  reset       Reset workflow state")
+	fmt.Println("  graph       Export the module/job dependency graph")
+	fmt.Println("  trace       Trace research to modules, jobs and commits")
+	fmt.Println("  logs        Browse, filter and follow job logs")
+	fmt.Println("  transcript  Export a job's agent conversation as Markdown or HTML")
+	fmt.Println("  config      Get, set, list and validate configuration")
+	fmt.Println("  doctor      Check the environment and project health")
+	fmt.Println("  prompts     Check the prompt templates")
 	fmt.Println("  version     Show version information")
 	fmt.Println("  help        Show this help message")
 	fmt.Println()
+	fmt.Println("Global options:")
+	fmt.Println("  --profile <name>  Apply a config profile (default: $MORTY_PROFILE)")
+	fmt.Println()
 	fmt.Println("Use 'morty <command> --help' for more information about a command.")
 }
 
@@ -176,17 +233,38 @@ func printVersion() {
 }
 
 func handleResearch(cfg *config.Paths, cfgLoader *config.Loader, logger logging.Logger, args []string) {
+	// Check for subcommands
+	if len(args) > 0 && args[0] == "search" {
+		handleResearchSearch(cfg, cfgLoader, logger, args[1:])
+		return
+	}
+
 	fs := flag.NewFlagSet("research", flag.ExitOnError)
 	help := fs.Bool("help", false, "Show help")
+	headless := fs.Bool("headless", false, "Run without a TTY and write the research document")
+	timeout := fs.Duration("timeout", 0, "Timeout of a headless run")
+	input := fs.String("input", "", "Seed requirements file for a headless run")
 	fs.Parse(args)
 
 	if *help {
-		fmt.Println("Usage: morty research [topic]")
+		fmt.Println("Usage: morty research [subcommand] [options] [topic]")
 		fmt.Println()
 		fmt.Println("Start research mode to analyze requirements.")
 		fmt.Println()
+		fmt.Println("Subcommands:")
+		fmt.Println("  search      Search the research documents")
+		fmt.Println()
+		fmt.Println("Options:")
+		fmt.Println("  -headless         Run non-interactively and write the research document")
+		fmt.Println("  -timeout duration Timeout of a headless run (default: ai_cli.max_timeout)")
+		fmt.Println("  -input string     Seed requirements file added to the prompt")
+		fmt.Println()
 		fmt.Println("Arguments:")
 		fmt.Println("  topic    Optional research topic")
+		fmt.Println("           In headless mode defaults to the name of the -input file")
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  morty research -headless -input requirements.md")
 		os.Exit(0)
 	}
 
@@ -201,7 +279,7 @@ func handleResearch(cfg *config.Paths, cfgLoader *config.Loader, logger logging.
 	handler := cmd.NewResearchHandler(cfgMgr, logger)
 	ctx := context.Background()
 
-	_, err := handler.Execute(ctx, fs.Args())
+	_, err := handler.Execute(ctx, append(headlessArgs(*headless, *timeout, *input), fs.Args()...))
 	if err != nil {
 		logger.Error("Research failed", logging.String("error", err.Error()))
 		os.Exit(1)
@@ -210,16 +288,82 @@ func handleResearch(cfg *config.Paths, cfgLoader *config.Loader, logger logging.
 	fmt.Println("✓ Research completed")
 }
 
+// handleResearchSearch handles the 'morty research search' subcommand.
+func handleResearchSearch(cfg *config.Paths, cfgLoader *config.Loader, logger logging.Logger, args []string) {
+	fs := flag.NewFlagSet("research search", flag.ExitOnError)
+	help := fs.Bool("help", false, "Show help")
+	topK := fs.Int("k", 0, "Number of results")
+	jsonOutput := fs.Bool("json", false, "Print results as JSON")
+	fs.Parse(args)
+
+	if *help {
+		fmt.Println("Usage: morty research search [options] <query>")
+		fmt.Println()
+		fmt.Println("Search the research documents in .morty/research. Documents are split")
+		fmt.Println("at headings and ranked with BM25; plan and doing prompts use the same")
+		fmt.Println("search to include only the relevant sections.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fmt.Println("  -k int     Number of results (default: research.top_k)")
+		fmt.Println("  -json      Print results as JSON")
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  morty research search 缓存 淘汰策略")
+		fmt.Println("  morty research search -k 3 oauth token refresh")
+		os.Exit(0)
+	}
+
+	// Use loader if available, otherwise use paths wrapper
+	var cfgMgr config.Manager
+	if cfgLoader != nil {
+		cfgMgr = cfgLoader
+	} else {
+		cfgMgr = &pathsConfigManager{paths: cfg}
+	}
+
+	searchArgs := fs.Args()
+	if *topK > 0 {
+		searchArgs = append(searchArgs, "-k", strconv.Itoa(*topK))
+	}
+	if *jsonOutput {
+		searchArgs = append(searchArgs, "--json")
+	}
+
+	handler := cmd.NewResearchHandler(cfgMgr, logger)
+	if _, err := handler.Search(context.Background(), searchArgs); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
 func handlePlan(cfg *config.Paths, cfgLoader *config.Loader, logger logging.Logger, args []string) {
 	// Check for subcommands
 	if len(args) > 0 && args[0] == "validate" {
 		handlePlanValidate(cfg, cfgLoader, logger, args[1:])
 		return
 	}
+	if len(args) > 0 && args[0] == "convert" {
+		handlePlanConvert(cfg, cfgLoader, logger, args[1:])
+		return
+	}
+	if len(args) > 0 && args[0] == "fmt" {
+		handlePlanFmt(cfg, cfgLoader, logger, args[1:])
+		return
+	}
+	if len(args) > 0 && (args[0] == "add" || args[0] == "regen") {
+		handlePlanModule(cfg, cfgLoader, logger, args[0], args[1:])
+		return
+	}
 
 	fs := flag.NewFlagSet("plan", flag.ExitOnError)
 	help := fs.Bool("help", false, "Show help")
-	_ = fs.String("module", "", "Target module name") // reserved for future use
+	module := fs.String("module", "", "Target module name")
+	force := fs.Bool("force", false, "Overwrite existing plan files")
+	headless := fs.Bool("headless", false, "Run without a TTY and write the plan files")
+	timeout := fs.Duration("timeout", 0, "Timeout of a headless run")
+	input := fs.String("input", "", "Seed requirements file for a headless run")
+	repair := fs.Bool("repair", false, "Ask the AI CLI to fix validation errors (headless)")
+	repairAttempts := fs.Int("repair-attempts", 0, "Rounds of the repair loop")
 	fs.Parse(args)
 
 	if *help {
@@ -229,9 +373,24 @@ func handlePlan(cfg *config.Paths, cfgLoader *config.Loader, logger logging.Logg
 		fmt.Println()
 		fmt.Println("Subcommands:")
 		fmt.Println("  validate    Validate plan file format")
+		fmt.Println("  convert     Convert plan files between markdown, JSON and YAML")
+		fmt.Println("  fmt         Rewrite plan files in canonical form")
+		fmt.Println("  add         Plan a new module and update the other plans")
+		fmt.Println("  regen       Regenerate a module's plan, keeping other modules' progress")
 		fmt.Println()
 		fmt.Println("Options:")
 		fmt.Println("  -module string    Target module name")
+		fmt.Println("  -force            Overwrite existing plan files")
+		fmt.Println("  -headless         Run non-interactively, write the plans and validate them")
+		fmt.Println("  -timeout duration Timeout of a headless run (default: ai_cli.max_timeout)")
+		fmt.Println("  -input string     Seed requirements file added to the prompt")
+		fmt.Println("  -repair           Feed validation errors back to the AI CLI (with -headless)")
+		fmt.Println("  -repair-attempts int")
+		fmt.Println("                    Rounds of the repair loop (default: plan.repair_attempts)")
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  morty plan -headless -input requirements.md")
+		fmt.Println("  morty plan -headless -repair -input requirements.md")
 		os.Exit(0)
 	}
 
@@ -248,7 +407,15 @@ func handlePlan(cfg *config.Paths, cfgLoader *config.Loader, logger logging.Logg
 	handler := cmd.NewPlanHandler(cfgMgr, logger, executor)
 	ctx := context.Background()
 
-	_, err := handler.Execute(ctx, fs.Args())
+	planArgs := headlessArgs(*headless, *timeout, *input)
+	if *module != "" {
+		planArgs = append(planArgs, "--module", *module)
+	}
+	if *force {
+		planArgs = append(planArgs, "--force")
+	}
+	planArgs = append(planArgs, repairArgs(*repair, *repairAttempts)...)
+	_, err := handler.Execute(ctx, append(planArgs, fs.Args()...))
 	if err != nil {
 		logger.Error("Plan failed", logging.String("error", err.Error()))
 		os.Exit(1)
@@ -257,6 +424,33 @@ func handlePlan(cfg *config.Paths, cfgLoader *config.Loader, logger logging.Logg
 	fmt.Println("✓ Plan completed")
 }
 
+// repairArgs converts the repair flags back into handler arguments.
+func repairArgs(repair bool, attempts int) []string {
+	var args []string
+	if repair {
+		args = append(args, "--repair")
+	}
+	if attempts > 0 {
+		args = append(args, "--repair-attempts", strconv.Itoa(attempts))
+	}
+	return args
+}
+
+// headlessArgs converts the headless flags back into handler arguments.
+func headlessArgs(headless bool, timeout time.Duration, input string) []string {
+	var args []string
+	if headless {
+		args = append(args, "--headless")
+	}
+	if timeout > 0 {
+		args = append(args, "--timeout", timeout.String())
+	}
+	if input != "" {
+		args = append(args, "--input", input)
+	}
+	return args
+}
+
 func handleDoing(cfg *config.Paths, cfgLoader *config.Loader, logger logging.Logger, args []string) {
 	fs := flag.NewFlagSet("doing", flag.ExitOnError)
 	help := fs.Bool("help", false, "Show help")
@@ -274,6 +468,13 @@ func handleDoing(cfg *config.Paths, cfgLoader *config.Loader, logger logging.Log
 		fmt.Println("  -restart          Restart mode - reset state before execution")
 		fmt.Println("  -module string    Target specific module")
 		fmt.Println("  -job string       Target specific job (requires -module)")
+		fmt.Println()
+		fmt.Println("With execution.continue_on_error enabled, a failed job marks every job")
+		fmt.Println("that depends on it as BLOCKED and execution continues with independent")
+		fmt.Println("jobs. A report of completed, failed and blocked jobs is printed at the end.")
+		fmt.Println()
+		fmt.Println("Ctrl-C stops after the current job; a second Ctrl-C interrupts the job,")
+		fmt.Println("which the next run resumes. See also 'morty pause' and 'morty resume'.")
 		os.Exit(0)
 	}
 
@@ -310,23 +511,106 @@ func handleDoing(cfg *config.Paths, cfgLoader *config.Loader, logger logging.Log
 	handler.PrintDoingSummary(result)
 }
 
+func handlePause(cfg *config.Paths, cfgLoader *config.Loader, logger logging.Logger, args []string) {
+	fs := flag.NewFlagSet("pause", flag.ExitOnError)
+	help := fs.Bool("help", false, "Show help")
+	now := fs.Bool("now", false, "Interrupt the current job instead of waiting for it")
+	fs.Parse(args)
+
+	if *help {
+		fmt.Println("Usage: morty pause [options]")
+		fmt.Println()
+		fmt.Println("Pause a running 'morty doing' from another terminal. It finishes the")
+		fmt.Println("current job, then waits until 'morty resume'.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fmt.Println("  -now              Interrupt the current job now; it runs again on resume")
+		os.Exit(0)
+	}
+
+	// Use loader if available, otherwise use paths wrapper
+	var cfgMgr config.Manager
+	if cfgLoader != nil {
+		cfgMgr = cfgLoader
+	} else {
+		cfgMgr = &pathsConfigManager{paths: cfg}
+	}
+
+	handlerArgs := fs.Args()
+	if *now {
+		handlerArgs = append([]string{"--now"}, handlerArgs...)
+	}
+
+	handler := cmd.NewPauseHandler(cfgMgr, logger)
+	if _, err := handler.Pause(context.Background(), handlerArgs); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func handleResume(cfg *config.Paths, cfgLoader *config.Loader, logger logging.Logger, args []string) {
+	fs := flag.NewFlagSet("resume", flag.ExitOnError)
+	help := fs.Bool("help", false, "Show help")
+	fs.Parse(args)
+
+	if *help {
+		fmt.Println("Usage: morty resume")
+		fmt.Println()
+		fmt.Println("Resume a 'morty doing' paused with 'morty pause'.")
+		os.Exit(0)
+	}
+
+	// Use loader if available, otherwise use paths wrapper
+	var cfgMgr config.Manager
+	if cfgLoader != nil {
+		cfgMgr = cfgLoader
+	} else {
+		cfgMgr = &pathsConfigManager{paths: cfg}
+	}
+
+	handler := cmd.NewPauseHandler(cfgMgr, logger)
+	if _, err := handler.Resume(context.Background(), fs.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
 func handleStat(cfg *config.Paths, logger logging.Logger, args []string) {
 	fs := flag.NewFlagSet("stat", flag.ExitOnError)
 	help := fs.Bool("help", false, "Show help")
+	jsonOutput := fs.Bool("json", false, "Print the status as JSON")
+	jsonShort := fs.Bool("j", false, "Print the status as JSON (shorthand)")
+	watch := fs.Bool("watch", false, "Refresh the status every 60 seconds")
+	watchShort := fs.Bool("w", false, "Refresh the status every 60 seconds (shorthand)")
 	fs.Parse(args)
 
 	if *help {
 		fmt.Println("Usage: morty stat [options]")
 		fmt.Println()
 		fmt.Println("Show current execution status.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fmt.Println("  -json, -j     Print the status as JSON")
+		fmt.Println("  -watch, -w    Refresh the status every 60 seconds until Ctrl+C")
 		os.Exit(0)
 	}
 
+	handlerArgs := []string{}
+	if *jsonOutput || *jsonShort {
+		handlerArgs = append(handlerArgs, "--json")
+	}
+	if *watch || *watchShort {
+		handlerArgs = append(handlerArgs, "--watch")
+	}
+	handlerArgs = append(handlerArgs, fs.Args()...)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	cfgMgr := &pathsConfigManager{paths: cfg}
 	handler := cmd.NewStatHandler(cfgMgr, logger)
-	ctx := context.Background()
 
-	_, err := handler.Execute(ctx, fs.Args())
+	_, err := handler.Execute(ctx, handlerArgs)
 	if err != nil {
 		logger.Error("Stat failed", logging.String("error", err.Error()))
 		os.Exit(1)
@@ -381,6 +665,410 @@ func handleReset(cfg *config.Paths, logger logging.Logger, args []string) {
 	}
 }
 
+func handleGraph(cfg *config.Paths, cfgLoader *config.Loader, logger logging.Logger, args []string) {
+	fs := flag.NewFlagSet("graph", flag.ExitOnError)
+	help := fs.Bool("help", false, "Show help")
+	format := fs.String("format", "dot", "Output format: dot, mermaid or json")
+	level := fs.String("level", "module", "Graph level: module or job")
+	output := fs.String("output", "", "Write to file instead of stdout")
+	criticalPath := fs.Bool("critical-path", false, "Highlight the critical path")
+	ready := fs.Bool("ready", false, "Highlight jobs that are ready to run")
+	fs.Parse(args)
+
+	if *help {
+		fmt.Println("Usage: morty graph [options]")
+		fmt.Println()
+		fmt.Println("Export the module/job dependency graph, colored by status.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fmt.Println("  -format string    Output format: dot, mermaid or json (default dot)")
+		fmt.Println("  -level string     Graph level: module or job (default module)")
+		fmt.Println("  -output string    Write to file instead of stdout")
+		fmt.Println("  -critical-path    Highlight the longest chain of remaining work")
+		fmt.Println("  -ready            Highlight nodes whose dependencies are completed")
+		os.Exit(0)
+	}
+
+	// Use loader if available, otherwise use paths wrapper
+	var cfgMgr config.Manager
+	if cfgLoader != nil {
+		cfgMgr = cfgLoader
+	} else {
+		cfgMgr = &pathsConfigManager{paths: cfg}
+	}
+
+	handlerArgs := []string{"--format", *format, "--level", *level}
+	if *output != "" {
+		handlerArgs = append(handlerArgs, "--output", *output)
+	}
+	if *criticalPath {
+		handlerArgs = append(handlerArgs, "--critical-path")
+	}
+	if *ready {
+		handlerArgs = append(handlerArgs, "--ready")
+	}
+
+	handler := cmd.NewGraphHandler(cfgMgr, logger)
+	ctx := context.Background()
+
+	if _, err := handler.Execute(ctx, handlerArgs); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func handleTrace(cfg *config.Paths, cfgLoader *config.Loader, logger logging.Logger, args []string) {
+	fs := flag.NewFlagSet("trace", flag.ExitOnError)
+	help := fs.Bool("help", false, "Show help")
+	format := fs.String("format", "text", "Output format: text, json or csv")
+	output := fs.String("output", "", "Write to file instead of stdout")
+	fs.Parse(args)
+
+	if *help {
+		fmt.Println("Usage: morty trace [options]")
+		fmt.Println()
+		fmt.Println("Show which modules, jobs and commits each research document led to,")
+		fmt.Println("and check that every research and implementation file referenced by")
+		fmt.Println("the plans exists. Exits with 1 if a reference does not resolve.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fmt.Println("  -format string    Output format: text, json or csv (default text)")
+		fmt.Println("  -output string    Write to file instead of stdout")
+		os.Exit(0)
+	}
+
+	// Use loader if available, otherwise use paths wrapper
+	var cfgMgr config.Manager
+	if cfgLoader != nil {
+		cfgMgr = cfgLoader
+	} else {
+		cfgMgr = &pathsConfigManager{paths: cfg}
+	}
+
+	handlerArgs := []string{"--format", *format}
+	if *output != "" {
+		handlerArgs = append(handlerArgs, "--output", *output)
+	}
+
+	handler := cmd.NewTraceHandler(cfgMgr, logger)
+	ctx := context.Background()
+
+	if _, err := handler.Execute(ctx, handlerArgs); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func handleLogs(cfg *config.Paths, cfgLoader *config.Loader, logger logging.Logger, args []string) {
+	fs := flag.NewFlagSet("logs", flag.ExitOnError)
+	help := fs.Bool("help", false, "Show help")
+	follow := fs.Bool("follow", false, "Keep printing new log entries")
+	level := fs.String("level", "", "Minimum level: debug, info, warn or error")
+	since := fs.String("since", "", "Only entries since a duration ago or a time")
+	attempt := fs.Int("attempt", 0, "Only the Nth run of the job")
+	raw := fs.Bool("raw", false, "Print log lines as written")
+	fs.Bool("formatted", true, "Render log lines (default)")
+	toolsOnly := fs.Bool("tools-only", false, "Only tool calls and tool results")
+	fs.Parse(args)
+
+	if *help {
+		fmt.Println("Usage: morty logs [module[/job]] [options]")
+		fmt.Println()
+		fmt.Println("Show job execution logs, conversation logs and morty.log on one timeline.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fmt.Println("  -follow           Keep printing new entries (Ctrl+C to stop)")
+		fmt.Println("  -level string     Minimum level: debug, info, warn or error")
+		fmt.Println("  -since string     Only entries since a duration (30m, 2h) or time (2006-01-02 15:04:05)")
+		fmt.Println("  -attempt int      Only the Nth run of the job (requires module/job)")
+		fmt.Println("  -raw              Print log lines as written")
+		fmt.Println("  -formatted        Render log entries and stream-json events (default)")
+		fmt.Println("  -tools-only       Only tool calls and tool results")
+		os.Exit(0)
+	}
+
+	// Use loader if available, otherwise use paths wrapper
+	var cfgMgr config.Manager
+	if cfgLoader != nil {
+		cfgMgr = cfgLoader
+	} else {
+		cfgMgr = &pathsConfigManager{paths: cfg}
+	}
+
+	var handlerArgs []string
+	if *follow {
+		handlerArgs = append(handlerArgs, "--follow")
+	}
+	if *level != "" {
+		handlerArgs = append(handlerArgs, "--level", *level)
+	}
+	if *since != "" {
+		handlerArgs = append(handlerArgs, "--since", *since)
+	}
+	if *attempt != 0 {
+		handlerArgs = append(handlerArgs, "--attempt", fmt.Sprint(*attempt))
+	}
+	if *raw {
+		handlerArgs = append(handlerArgs, "--raw")
+	}
+	if *toolsOnly {
+		handlerArgs = append(handlerArgs, "--tools-only")
+	}
+	// The handler also parses options given after module[/job]
+	handlerArgs = append(handlerArgs, fs.Args()...)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	handler := cmd.NewLogsHandler(cfgMgr, logger)
+	if _, err := handler.Execute(ctx, handlerArgs); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func handleTranscript(cfg *config.Paths, cfgLoader *config.Loader, logger logging.Logger, args []string) {
+	fs := flag.NewFlagSet("transcript", flag.ExitOnError)
+	help := fs.Bool("help", false, "Show help")
+	attempt := fs.Int("attempt", 0, "The Nth run of the job (default: latest)")
+	format := fs.String("format", "md", "Output format: md or html")
+	output := fs.String("output", "", "Write to a file instead of stdout")
+	fs.Parse(args)
+
+	if *help {
+		fmt.Println("Usage: morty transcript <module/job> [options]")
+		fmt.Println()
+		fmt.Println("Export the agent conversation of a job run, with tool calls, file edits as")
+		fmt.Println("diffs, token usage and cost. HTML output is a single offline file.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fmt.Println("  -attempt int      The Nth run of the job (default: latest)")
+		fmt.Println("  -format string    Output format: md or html (default: md)")
+		fmt.Println("  -output string    Write to a file instead of stdout")
+		os.Exit(0)
+	}
+
+	// Use loader if available, otherwise use paths wrapper
+	var cfgMgr config.Manager
+	if cfgLoader != nil {
+		cfgMgr = cfgLoader
+	} else {
+		cfgMgr = &pathsConfigManager{paths: cfg}
+	}
+
+	handlerArgs := []string{"--format", *format}
+	if *attempt != 0 {
+		handlerArgs = append(handlerArgs, "--attempt", fmt.Sprint(*attempt))
+	}
+	if *output != "" {
+		handlerArgs = append(handlerArgs, "--output", *output)
+	}
+	// The handler also parses options given after module/job
+	handlerArgs = append(handlerArgs, fs.Args()...)
+
+	handler := cmd.NewTranscriptHandler(cfgMgr, logger)
+	if _, err := handler.Execute(context.Background(), handlerArgs); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func handleConfig(cfgLoader *config.Loader, logger logging.Logger, args []string) {
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-help" || args[0] == "-h" {
+		fmt.Println("Usage: morty config <subcommand> [options]")
+		fmt.Println()
+		fmt.Println("Read and change configuration. Values are merged from the built-in")
+		fmt.Println("defaults, the user config (~/.morty/config.json), the project config")
+		fmt.Println("(.morty/settings.json) and MORTY_* environment variables.")
+		fmt.Println()
+		fmt.Println("Subcommands:")
+		fmt.Println("  get <key>            Print the effective value of a key")
+		fmt.Println("  set <key> <value>    Set a key in the project config (--global: user config)")
+		fmt.Println("  unset <key>          Remove a key from the project config (--global: user config)")
+		fmt.Println("  list                 List all effective values (--show-origin: with their layer)")
+		fmt.Println("  validate             Check the config files and the merged configuration")
+		fmt.Println("  path                 Show the config file locations")
+		fmt.Println("  explain <key>        Show the value of a key in every layer")
+		fmt.Println("  schema [settings|plan]")
+		fmt.Println("                       Print the JSON Schema of settings files or plans (-o: write to a file)")
+		fmt.Println()
+		fmt.Println("Keys use dot notation, e.g. logging.level or git.auto_commit.")
+		fmt.Println("Non-string values are JSON; string lists also accept a,b,c.")
+		if len(args) == 0 {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
+	handler := cmd.NewConfigHandler(cfgLoader, logger)
+	if _, err := handler.Execute(context.Background(), args); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func handlePrompts(cfg *config.Paths, cfgLoader *config.Loader, logger logging.Logger, args []string) {
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-help" || args[0] == "-h" {
+		fmt.Println("Usage: morty prompts check [phase...]")
+		fmt.Println()
+		fmt.Println("Prompts are rendered from text/template files. Templates in")
+		fmt.Println(".morty/prompts/ override those in prompts.dir, which override the")
+		fmt.Println("built-in phase templates and partials.")
+		fmt.Println()
+		fmt.Println("Subcommands:")
+		fmt.Println("  check [phase...]     Parse the templates of the phases (default: all) and report")
+		fmt.Println("                       unknown or missing variables. Exits with 1 on problems.")
+		fmt.Println()
+		fmt.Println("Phases: research, plan, plan_module, plan_repair, doing, doing_resume, doing_task, doing_context")
+		if len(args) == 0 {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	if args[0] != "check" {
+		fmt.Fprintf(os.Stderr, "Unknown prompts subcommand: %s\n", args[0])
+		os.Exit(1)
+	}
+
+	// Use loader if available, otherwise use paths wrapper
+	var cfgMgr config.Manager
+	if cfgLoader != nil {
+		cfgMgr = cfgLoader
+	} else {
+		cfgMgr = &pathsConfigManager{paths: cfg}
+	}
+
+	handler := cmd.NewPromptsHandler(cfgMgr, logger)
+	if _, err := handler.Check(context.Background(), args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func handleDoctor(cfgLoader *config.Loader, logger logging.Logger, args []string) {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	help := fs.Bool("help", false, "Show help")
+	jsonOutput := fs.Bool("json", false, "Print the checks as JSON")
+	fs.Parse(args)
+
+	if *help {
+		fmt.Println("Usage: morty doctor [options]")
+		fmt.Println()
+		fmt.Println("Check the environment and the project: the AI CLI and the flags it")
+		fmt.Println("supports, git, the configuration, prompt templates, plans, status.json")
+		fmt.Println("consistency and locks left by interrupted runs. Each problem comes with")
+		fmt.Println("a fix. Exits with 1 when a check fails.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fmt.Println("  -json    Print the checks as JSON")
+		os.Exit(0)
+	}
+
+	var handlerArgs []string
+	if *jsonOutput {
+		handlerArgs = append(handlerArgs, "--json")
+	}
+
+	handler := cmd.NewDoctorHandler(cfgLoader, logger)
+	if _, err := handler.Execute(context.Background(), handlerArgs); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// splitProfileFlag removes the global --profile flag from the command line
+// and returns its value.
+func splitProfileFlag(args []string) (string, []string, error) {
+	var profile string
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--profile" || arg == "-profile":
+			if i+1 >= len(args) || args[i+1] == "" {
+				return "", nil, fmt.Errorf("%s requires a profile name", arg)
+			}
+			i++
+			profile = args[i]
+		case strings.HasPrefix(arg, "--profile=") || strings.HasPrefix(arg, "-profile="):
+			_, profile, _ = strings.Cut(arg, "=")
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return profile, rest, nil
+}
+
+// userConfigPath returns the user config file: $MORTY_CONFIG, or
+// ~/.morty/config.json, or the config.json of the installation directory
+// when morty is installed elsewhere.
+func userConfigPath() string {
+	if path := os.Getenv(config.EnvMortyConfig); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	path := filepath.Join(home, ".morty", "config.json")
+	if _, err := os.Stat(path); err != nil {
+		if execPath, err := os.Executable(); err == nil {
+			installConfig := filepath.Join(filepath.Dir(filepath.Dir(execPath)), "config.json")
+			if _, err := os.Stat(installConfig); err == nil {
+				return installConfig
+			}
+		}
+	}
+	return path
+}
+
+// applyPlanHeadings registers the configured plan heading aliases with the
+// plan parser. Unknown sections are reported and skipped.
+func applyPlanHeadings(cfgLoader *config.Loader, logger logging.Logger) {
+	if cfgLoader == nil || cfgLoader.Config() == nil || len(cfgLoader.Config().Plan.HeadingAliases) == 0 {
+		return
+	}
+
+	headings := plan.NewHeadings()
+	for name, aliases := range cfgLoader.Config().Plan.HeadingAliases {
+		section, err := plan.ParseSection(name)
+		if err == nil {
+			err = headings.AddAliases(section, aliases...)
+		}
+		if err != nil {
+			logger.Warn("Ignoring plan heading aliases",
+				logging.String("section", name),
+				logging.String("error", err.Error()))
+		}
+	}
+	plan.SetHeadings(headings)
+}
+
+// applyRedaction installs the configured redactor shared by all logs and
+// transcripts. Invalid patterns are reported and skipped.
+func applyRedaction(cfgLoader *config.Loader, logger logging.Logger) {
+	if cfgLoader == nil || cfgLoader.Config() == nil {
+		return
+	}
+	cfg := cfgLoader.Config().Redaction
+	if !cfg.Enabled {
+		redact.SetDefault(nil)
+		return
+	}
+
+	var rules []redact.Rule
+	for _, p := range cfg.Patterns {
+		rule, err := redact.CompileRule(p.Name, p.Pattern)
+		if err != nil {
+			logger.Warn("Ignoring redaction pattern",
+				logging.String("name", p.Name),
+				logging.String("error", err.Error()))
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	redact.SetDefault(redact.New(redact.Config{Rules: rules, EnvVars: cfg.EnvVars}))
+}
+
 func isFlag(s string) bool {
 	return len(s) > 0 && s[0] == '-'
 }
@@ -479,6 +1167,202 @@ func (p *pathsConfigManager) GetAll() map[string]interface{} {
 }
 
 // handlePlanValidate handles the 'morty plan validate' subcommand.
+func handlePlanConvert(cfg *config.Paths, cfgLoader *config.Loader, logger logging.Logger, args []string) {
+	fs := flag.NewFlagSet("plan convert", flag.ExitOnError)
+	help := fs.Bool("help", false, "Show help")
+	to := fs.String("to", "", "Target format: md, json or yaml")
+	output := fs.String("output", "", "Output file or directory (- for stdout)")
+	outputShort := fs.String("o", "", "Output file or directory (shorthand)")
+	inPlace := fs.Bool("in-place", false, "Remove the source file after converting")
+	force := fs.Bool("force", false, "Overwrite existing target files")
+	fs.Parse(args)
+
+	if *help {
+		fmt.Println("Usage: morty plan convert -to <format> [options] [file...]")
+		fmt.Println()
+		fmt.Println("Convert plan files between markdown, JSON and YAML.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fmt.Println("  -to string        Target format: md, json or yaml (required)")
+		fmt.Println("  -o, -output       Output file or directory (- for stdout)")
+		fmt.Println("                    Default: next to the source with the new extension")
+		fmt.Println("  -in-place         Remove the source file after converting")
+		fmt.Println("  -force            Overwrite existing target files")
+		fmt.Println()
+		fmt.Println("Arguments:")
+		fmt.Println("  file              Plan files to convert (optional)")
+		fmt.Println("                    If not specified, converts all files in plan directory")
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  morty plan convert -to yaml user_auth.md      # Write user_auth.yaml")
+		fmt.Println("  morty plan convert -to json -o - cache.yaml   # Print as JSON")
+		fmt.Println("  morty plan convert -to md -in-place           # Convert all back to markdown")
+		os.Exit(0)
+	}
+
+	// Use loader if available, otherwise use paths wrapper
+	var cfgMgr config.Manager
+	if cfgLoader != nil {
+		cfgMgr = cfgLoader
+	} else {
+		cfgMgr = &pathsConfigManager{paths: cfg}
+	}
+
+	handler := cmd.NewPlanHandler(cfgMgr, logger, nil)
+	ctx := context.Background()
+
+	// Build args for Convert method
+	convertArgs := []string{"--to=" + *to}
+	if *outputShort != "" {
+		*output = *outputShort
+	}
+	if *output != "" {
+		convertArgs = append(convertArgs, "--output="+*output)
+	}
+	if *inPlace {
+		convertArgs = append(convertArgs, "--in-place")
+	}
+	if *force {
+		convertArgs = append(convertArgs, "--force")
+	}
+	convertArgs = append(convertArgs, fs.Args()...)
+
+	result, err := handler.Convert(ctx, convertArgs)
+	if err != nil {
+		logger.Error("Plan convert failed", logging.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	// Converted content on stdout must stay clean
+	if *output != "-" {
+		fmt.Print(result.Message)
+	}
+}
+
+func handlePlanModule(cfg *config.Paths, cfgLoader *config.Loader, logger logging.Logger, subcommand string, args []string) {
+	fs := flag.NewFlagSet("plan "+subcommand, flag.ExitOnError)
+	help := fs.Bool("help", false, "Show help")
+	dependsOn := fs.String("depends-on", "", "Comma separated modules the module depends on")
+	timeout := fs.Duration("timeout", 0, "Timeout of the AI CLI run")
+	input := fs.String("input", "", "Seed requirements file")
+	repair := fs.Bool("repair", false, "Ask the AI CLI to fix validation errors")
+	repairAttempts := fs.Int("repair-attempts", 0, "Rounds of the repair loop")
+	fs.Parse(args)
+
+	if *help {
+		fmt.Printf("Usage: morty plan %s [options] <module>\n", subcommand)
+		fmt.Println()
+		if subcommand == "add" {
+			fmt.Println("Plan a new module without touching the others. The module is added to")
+			fmt.Println("the 被依赖模块 lists of its dependencies and to the README module table,")
+			fmt.Println("and status.json is updated keeping the progress of existing jobs.")
+		} else {
+			fmt.Println("Regenerate the plan of an existing module. Its dependencies are kept")
+			fmt.Println("unless -depends-on is given; cross-references and status.json are")
+			fmt.Println("updated, resetting only the progress of this module.")
+		}
+		fmt.Println()
+		fmt.Println("The AI CLI runs non-interactively, like 'morty plan -headless'.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fmt.Println("  -depends-on string Comma separated modules the module depends on")
+		fmt.Println("  -timeout duration  Timeout of the AI CLI run (default: ai_cli.max_timeout)")
+		fmt.Println("  -input string      Seed requirements file added to the prompt")
+		fmt.Println("  -repair            Feed validation errors back to the AI CLI")
+		fmt.Println("  -repair-attempts int")
+		fmt.Println("                     Rounds of the repair loop (default: plan.repair_attempts)")
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  morty plan add -depends-on logging,config metrics")
+		fmt.Println("  morty plan regen -input metrics-v2.md metrics")
+		os.Exit(0)
+	}
+
+	// Use loader if available, otherwise use paths wrapper
+	var cfgMgr config.Manager
+	if cfgLoader != nil {
+		cfgMgr = cfgLoader
+	} else {
+		cfgMgr = &pathsConfigManager{paths: cfg}
+	}
+
+	handler := cmd.NewPlanHandler(cfgMgr, logger, nil)
+	ctx := context.Background()
+
+	moduleArgs := headlessArgs(false, *timeout, *input)
+	if *dependsOn != "" {
+		moduleArgs = append(moduleArgs, "--depends-on", *dependsOn)
+	}
+	moduleArgs = append(moduleArgs, repairArgs(*repair, *repairAttempts)...)
+	moduleArgs = append(moduleArgs, fs.Args()...)
+
+	run := handler.Add
+	if subcommand == "regen" {
+		run = handler.Regen
+	}
+	result, err := run(ctx, moduleArgs)
+	if result != nil {
+		fmt.Print(result.Message)
+	}
+	if err != nil {
+		logger.Error("Plan "+subcommand+" failed", logging.String("error", err.Error()))
+		os.Exit(1)
+	}
+}
+
+func handlePlanFmt(cfg *config.Paths, cfgLoader *config.Loader, logger logging.Logger, args []string) {
+	fs := flag.NewFlagSet("plan fmt", flag.ExitOnError)
+	help := fs.Bool("help", false, "Show help")
+	check := fs.Bool("check", false, "Report unformatted files without writing")
+	fs.Parse(args)
+
+	if *help {
+		fmt.Println("Usage: morty plan fmt [options] [file...]")
+		fmt.Println()
+		fmt.Println("Rewrite plan files in canonical form: section order, job and task")
+		fmt.Println("numbering, dependency and prerequisite syntax, checkbox style and")
+		fmt.Println("lowercase file names.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fmt.Println("  -check            Report unformatted files and exit 1 (for CI)")
+		fmt.Println()
+		fmt.Println("Arguments:")
+		fmt.Println("  file              Plan files to format (optional)")
+		fmt.Println("                    If not specified, formats all files in plan directory")
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  morty plan fmt                  # Format all plan files")
+		fmt.Println("  morty plan fmt user_auth.md     # Format a single file")
+		fmt.Println("  morty plan fmt -check           # Fail if any plan is not formatted")
+		os.Exit(0)
+	}
+
+	// Use loader if available, otherwise use paths wrapper
+	var cfgMgr config.Manager
+	if cfgLoader != nil {
+		cfgMgr = cfgLoader
+	} else {
+		cfgMgr = &pathsConfigManager{paths: cfg}
+	}
+
+	handler := cmd.NewPlanHandler(cfgMgr, logger, nil)
+	ctx := context.Background()
+
+	fmtArgs := fs.Args()
+	if *check {
+		fmtArgs = append([]string{"--check"}, fmtArgs...)
+	}
+
+	result, err := handler.Fmt(ctx, fmtArgs)
+	if result != nil {
+		fmt.Print(result.Message)
+	}
+	if err != nil {
+		logger.Error("Plan fmt failed", logging.String("error", err.Error()))
+		os.Exit(1)
+	}
+}
+
 func handlePlanValidate(cfg *config.Paths, cfgLoader *config.Loader, logger logging.Logger, args []string) {
 	fs := flag.NewFlagSet("plan validate", flag.ExitOnError)
 	help := fs.Bool("help", false, "Show help")
@@ -486,6 +1370,8 @@ func handlePlanValidate(cfg *config.Paths, cfgLoader *config.Loader, logger logg
 	verboseShort := fs.Bool("v", false, "Show verbose output (shorthand)")
 	fix := fs.Bool("fix", false, "Auto-fix format issues if possible")
 	fixShort := fs.Bool("f", false, "Auto-fix format issues (shorthand)")
+	repair := fs.Bool("repair", false, "Ask the AI CLI to fix validation errors")
+	repairAttempts := fs.Int("repair-attempts", 0, "Rounds of the repair loop")
 	fs.Parse(args)
 
 	if *help {
@@ -496,6 +1382,9 @@ func handlePlanValidate(cfg *config.Paths, cfgLoader *config.Loader, logger logg
 		fmt.Println("Options:")
 		fmt.Println("  -v, --verbose    Show detailed error information")
 		fmt.Println("  -f, --fix        Auto-fix format issues if possible")
+		fmt.Println("  --repair         Feed the errors back to the AI CLI and re-validate")
+		fmt.Println("  --repair-attempts int")
+		fmt.Println("                   Rounds of the repair loop (default: plan.repair_attempts)")
 		fmt.Println()
 		fmt.Println("Arguments:")
 		fmt.Println("  file            Validate single file (optional)")
@@ -506,6 +1395,7 @@ func handlePlanValidate(cfg *config.Paths, cfgLoader *config.Loader, logger logg
 		fmt.Println("  morty plan validate user_auth.md     # Validate single file")
 		fmt.Println("  morty plan validate --verbose        # Show detailed errors")
 		fmt.Println("  morty plan validate --fix            # Auto-fix issues")
+		fmt.Println("  morty plan validate --repair         # Let the AI CLI fix the errors")
 		os.Exit(0)
 	}
 
@@ -532,6 +1422,7 @@ func handlePlanValidate(cfg *config.Paths, cfgLoader *config.Loader, logger logg
 	if isFix {
 		validateArgs = append(validateArgs, "--fix")
 	}
+	validateArgs = append(validateArgs, repairArgs(*repair, *repairAttempts)...)
 
 	result, err := handler.Validate(ctx, validateArgs)
 	if err != nil {
@@ -2,12 +2,16 @@
 package callcli
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/morty/morty/internal/redact"
 )
 
 // Event represents a single event in the Claude Code event stream.
@@ -513,57 +517,57 @@ func (cp *ConversationParser) extractContentString(content interface{}) string {
 
 // WriteLogs writes the formatted logs to a file.
 func (cp *ConversationParser) WriteLogs(logPath string, logs []FormattedLog, conversation *ConversationData) error {
-	file, err := os.Create(logPath)
-	if err != nil {
-		return fmt.Errorf("failed to create log file: %w", err)
-	}
-	defer file.Close()
+	var buf bytes.Buffer
 
 	// Write header
-	fmt.Fprintf(file, "=== Claude Code Conversation Log ===\n")
-	fmt.Fprintf(file, "Generated: %s\n", time.Now().Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&buf, "=== Claude Code Conversation Log ===\n")
+	fmt.Fprintf(&buf, "Generated: %s\n", time.Now().Format("2006-01-02 15:04:05"))
 	if conversation.SessionID != "" {
-		fmt.Fprintf(file, "Session ID: %s\n", conversation.SessionID)
+		fmt.Fprintf(&buf, "Session ID: %s\n", conversation.SessionID)
 	}
 	if conversation.Model != "" {
-		fmt.Fprintf(file, "Model: %s\n", conversation.Model)
+		fmt.Fprintf(&buf, "Model: %s\n", conversation.Model)
 	}
 	if conversation.TotalInputTokens > 0 || conversation.TotalOutputTokens > 0 {
-		fmt.Fprintf(file, "Tokens: %d input + %d output = %d total\n",
+		fmt.Fprintf(&buf, "Tokens: %d input + %d output = %d total\n",
 			conversation.TotalInputTokens,
 			conversation.TotalOutputTokens,
 			conversation.TotalInputTokens+conversation.TotalOutputTokens)
 	}
 	if conversation.TotalCostUSD > 0 {
-		fmt.Fprintf(file, "Total Cost: $%.4f USD\n", conversation.TotalCostUSD)
+		fmt.Fprintf(&buf, "Total Cost: $%.4f USD\n", conversation.TotalCostUSD)
 	}
 	if conversation.TotalDurationMs > 0 {
-		fmt.Fprintf(file, "Duration: %.2f seconds\n", float64(conversation.TotalDurationMs)/1000.0)
+		fmt.Fprintf(&buf, "Duration: %.2f seconds\n", float64(conversation.TotalDurationMs)/1000.0)
 	}
 	if conversation.NumTurns > 0 {
-		fmt.Fprintf(file, "Turns: %d\n", conversation.NumTurns)
+		fmt.Fprintf(&buf, "Turns: %d\n", conversation.NumTurns)
 	}
-	fmt.Fprintf(file, "Total Events: %d\n", len(logs))
-	fmt.Fprintf(file, "=====================================\n\n")
+	fmt.Fprintf(&buf, "Total Events: %d\n", len(logs))
+	fmt.Fprintf(&buf, "=====================================\n\n")
 
 	// Write each log entry
 	for _, log := range logs {
-		cp.writeLogEntry(file, log)
+		cp.writeLogEntry(&buf, log)
 	}
 
 	// Write summary statistics
-	cp.writeStatistics(file, logs)
+	cp.writeStatistics(&buf, logs)
 
 	// Write model usage breakdown if available
 	if len(conversation.ModelUsage) > 0 {
-		cp.writeModelUsage(file, conversation.ModelUsage)
+		cp.writeModelUsage(&buf, conversation.ModelUsage)
 	}
 
+	// Mask secrets before the log reaches disk
+	if err := os.WriteFile(logPath, []byte(redact.String(buf.String())), 0644); err != nil {
+		return fmt.Errorf("failed to create log file: %w", err)
+	}
 	return nil
 }
 
 // writeLogEntry writes a single log entry to the file.
-func (cp *ConversationParser) writeLogEntry(file *os.File, log FormattedLog) {
+func (cp *ConversationParser) writeLogEntry(file io.Writer, log FormattedLog) {
 	timestamp := log.Timestamp.Format("15:04:05")
 
 	switch log.MessageType {
@@ -637,7 +641,7 @@ func (cp *ConversationParser) writeLogEntry(file *os.File, log FormattedLog) {
 }
 
 // writeModelUsage writes model usage breakdown to the file.
-func (cp *ConversationParser) writeModelUsage(file *os.File, modelUsage map[string]*ModelUsage) {
+func (cp *ConversationParser) writeModelUsage(file io.Writer, modelUsage map[string]*ModelUsage) {
 	fmt.Fprintf(file, "\n=====================================\n")
 	fmt.Fprintf(file, "=== Model Usage Breakdown ===\n")
 	fmt.Fprintf(file, "=====================================\n")
@@ -657,7 +661,7 @@ func (cp *ConversationParser) writeModelUsage(file *os.File, modelUsage map[stri
 }
 
 // writeStatistics writes summary statistics to the file.
-func (cp *ConversationParser) writeStatistics(file *os.File, logs []FormattedLog) {
+func (cp *ConversationParser) writeStatistics(file io.Writer, logs []FormattedLog) {
 	fmt.Fprintf(file, "\n=====================================\n")
 	fmt.Fprintf(file, "=== Statistics ===\n")
 	fmt.Fprintf(file, "=====================================\n")
@@ -726,8 +730,8 @@ func (cp *ConversationParser) SaveFormattedJSON(conversation *ConversationData,
 		return "", fmt.Errorf("failed to marshal JSON: %w", err)
 	}
 
-	// Write to file
-	if err := os.WriteFile(jsonPath, data, 0644); err != nil {
+	// Mask secrets and write to file
+	if err := os.WriteFile(jsonPath, redact.JSON(data), 0644); err != nil {
 		return "", fmt.Errorf("failed to write JSON file: %w", err)
 	}
 
@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/morty/morty/internal/redact"
 )
 
 // ExecutionLog represents a single execution record.
@@ -279,8 +281,15 @@ func (el *ExecutionLogger) LogExecution(log *ExecutionLog) error {
 		return fmt.Errorf("failed to rotate log: %w", err)
 	}
 
+	// Mask secrets in the command, environment and error before writing
+	entry := *log
+	entry.Args = redact.Strings(log.Args)
+	entry.FullCommand = redact.String(log.FullCommand)
+	entry.Env = redact.Env(log.Env)
+	entry.Error = redact.String(log.Error)
+
 	// Write log entry as JSON
-	data, err := json.Marshal(log)
+	data, err := json.Marshal(&entry)
 	if err != nil {
 		return fmt.Errorf("failed to marshal log: %w", err)
 	}
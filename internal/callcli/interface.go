@@ -71,6 +71,9 @@ type Options struct {
 	Stdin string
 	// GracefulPeriod is the time to wait after SIGTERM before sending SIGKILL (0 means no graceful termination)
 	GracefulPeriod time.Duration
+	// DetachSignals keeps CallWithSignal from forwarding SIGINT/SIGTERM received
+	// by morty; the caller stops the command by cancelling the context instead
+	DetachSignals bool
 	// Output configures output handling
 	Output OutputConfig
 }
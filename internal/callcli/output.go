@@ -6,16 +6,21 @@ import (
 	"io"
 	"os"
 	"sync"
+
+	"github.com/morty/morty/internal/redact"
 )
 
 // OutputHandler manages command output based on the configured mode.
 type OutputHandler struct {
-	config     OutputConfig
-	stdoutBuf  *bytes.Buffer
-	stderrBuf  *bytes.Buffer
-	stdoutFile *os.File
-	stderrFile *os.File
-	mu         sync.RWMutex
+	config       OutputConfig
+	stdoutBuf    *bytes.Buffer
+	stderrBuf    *bytes.Buffer
+	stdoutStream *redact.Writer
+	stderrStream *redact.Writer
+	stdoutFile   *redact.Writer
+	stderrFile   *redact.Writer
+	file         *os.File
+	mu           sync.RWMutex
 }
 
 // NewOutputHandler creates a new output handler based on the configuration.
@@ -24,6 +29,18 @@ func NewOutputHandler(config OutputConfig) (*OutputHandler, error) {
 		config: config,
 	}
 
+	// Secrets are masked before output reaches the terminal or the custom
+	// writers that replace it
+	stdout, stderr := io.Writer(os.Stdout), io.Writer(os.Stderr)
+	if config.CustomStdout != nil {
+		stdout = config.CustomStdout
+	}
+	if config.CustomStderr != nil {
+		stderr = config.CustomStderr
+	}
+	handler.stdoutStream = redact.NewWriter(stdout)
+	handler.stderrStream = redact.NewWriter(stderr)
+
 	switch config.Mode {
 	case OutputCapture:
 		handler.stdoutBuf = &bytes.Buffer{}
@@ -46,8 +63,10 @@ func NewOutputHandler(config OutputConfig) (*OutputHandler, error) {
 		if err != nil {
 			return nil, fmt.Errorf("failed to create output file: %w", err)
 		}
-		handler.stdoutFile = file
-		handler.stderrFile = file
+		// Secrets are masked before output reaches the file
+		handler.file = file
+		handler.stdoutFile = redact.NewWriter(file)
+		handler.stderrFile = handler.stdoutFile
 	}
 
 	return handler, nil
@@ -60,7 +79,7 @@ func (h *OutputHandler) StdoutWriter() io.Writer {
 
 	// Custom stdout takes precedence
 	if h.config.CustomStdout != nil {
-		writers := []io.Writer{h.config.CustomStdout}
+		writers := []io.Writer{h.stdoutStream}
 		if h.stdoutFile != nil {
 			writers = append(writers, h.stdoutFile)
 		}
@@ -85,7 +104,7 @@ func (h *OutputHandler) StdoutWriter() io.Writer {
 		return io.MultiWriter(writers...)
 
 	case OutputStream:
-		writers := []io.Writer{os.Stdout}
+		writers := []io.Writer{h.stdoutStream}
 		if h.stdoutFile != nil {
 			writers = append(writers, h.stdoutFile)
 		}
@@ -95,7 +114,7 @@ func (h *OutputHandler) StdoutWriter() io.Writer {
 		return io.MultiWriter(writers...)
 
 	case OutputCaptureAndStream:
-		writers := []io.Writer{os.Stdout}
+		writers := []io.Writer{h.stdoutStream}
 		if h.stdoutFile != nil {
 			writers = append(writers, h.stdoutFile)
 		}
@@ -127,7 +146,7 @@ func (h *OutputHandler) StderrWriter() io.Writer {
 
 	// Custom stderr takes precedence
 	if h.config.CustomStderr != nil {
-		writers := []io.Writer{h.config.CustomStderr}
+		writers := []io.Writer{h.stderrStream}
 		if h.stderrFile != nil {
 			writers = append(writers, h.stderrFile)
 		}
@@ -152,7 +171,7 @@ func (h *OutputHandler) StderrWriter() io.Writer {
 		return io.MultiWriter(writers...)
 
 	case OutputStream:
-		writers := []io.Writer{os.Stderr}
+		writers := []io.Writer{h.stderrStream}
 		if h.stderrFile != nil {
 			writers = append(writers, h.stderrFile)
 		}
@@ -162,7 +181,7 @@ func (h *OutputHandler) StderrWriter() io.Writer {
 		return io.MultiWriter(writers...)
 
 	case OutputCaptureAndStream:
-		writers := []io.Writer{os.Stderr}
+		writers := []io.Writer{h.stderrStream}
 		if h.stderrFile != nil {
 			writers = append(writers, h.stderrFile)
 		}
@@ -225,11 +244,16 @@ func (h *OutputHandler) Close() error {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
+	_ = h.stdoutStream.Flush()
+	_ = h.stderrStream.Flush()
+
 	var err error
-	if h.stdoutFile != nil {
-		// Sync before closing to ensure all data is written
-		_ = h.stdoutFile.Sync()
-		err = h.stdoutFile.Close()
+	if h.file != nil {
+		// Flush and sync before closing to ensure all data is written
+		_ = h.stdoutFile.Flush()
+		_ = h.file.Sync()
+		err = h.file.Close()
+		h.file = nil
 		h.stdoutFile = nil
 		h.stderrFile = nil
 	}
@@ -0,0 +1,105 @@
+package callcli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSecret = "sk-ant-REDACTED"
+
+// TestConversationParser_WriteLogsRedaction tests that secrets in the
+// conversation log are masked.
+func TestConversationParser_WriteLogsRedaction(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "conversation.log")
+	logs := []FormattedLog{
+		{MessageType: "assistant_text", Role: "assistant", Content: "Using key " + testSecret},
+		{MessageType: "tool_call", Role: "assistant", ToolName: "Bash", ToolParams: map[string]interface{}{"command": "curl -H 'x-api-key: " + testSecret + "'"}},
+	}
+
+	if err := NewConversationParser("").WriteLogs(logPath, logs, &ConversationData{}); err != nil {
+		t.Fatalf("WriteLogs failed: %v", err)
+	}
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), testSecret) || !strings.Contains(string(data), "[REDACTED:anthropic-api-key]") {
+		t.Errorf("Expected masked log, got:\n%s", data)
+	}
+}
+
+func TestExecutionLogger_Redaction(t *testing.T) {
+	dir := t.TempDir()
+	logger, err := NewExecutionLogger(dir, 1024*1024, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := &ExecutionLog{
+		Command:     "claude",
+		Args:        []string{"--api-key", testSecret},
+		FullCommand: "claude --api-key " + testSecret,
+		Env:         map[string]string{"ANTHROPIC_API_KEY": "key-value-123", "PATH": "/bin"},
+		Error:       "rejected " + testSecret,
+	}
+	if err := logger.LogExecution(entry); err != nil {
+		t.Fatalf("LogExecution failed: %v", err)
+	}
+	logger.Close()
+
+	if entry.Args[1] != testSecret {
+		t.Error("Expected the caller's entry to be left unchanged")
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*"))
+	var out strings.Builder
+	for _, file := range files {
+		data, _ := os.ReadFile(file)
+		out.Write(data)
+	}
+	for _, leak := range []string{testSecret, "key-value-123"} {
+		if strings.Contains(out.String(), leak) {
+			t.Errorf("Secret %q leaked into execution log:\n%s", leak, out.String())
+		}
+	}
+	if !strings.Contains(out.String(), `"PATH":"/bin"`) {
+		t.Errorf("Expected other variables kept:\n%s", out.String())
+	}
+}
+
+// TestOutputRedaction tests that secrets are masked in streamed output as
+// well as in captured output files.
+func TestOutputRedaction(t *testing.T) {
+	script := "echo 'token " + testSecret + "'; printf 'key " + testSecret + "' >&2"
+
+	for _, mode := range []OutputMode{OutputStream, OutputCaptureAndStream} {
+		t.Run(OutputModeString(mode), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			outputFile := filepath.Join(t.TempDir(), "output.log")
+			opts := Options{
+				Output: OutputConfig{
+					Mode:         mode,
+					CustomStdout: &stdout,
+					CustomStderr: &stderr,
+					OutputFile:   outputFile,
+				},
+			}
+
+			if _, err := New().CallWithOptions(context.Background(), "sh", []string{"-c", script}, opts); err != nil {
+				t.Fatalf("Call failed: %v", err)
+			}
+			file, err := os.ReadFile(outputFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for name, out := range map[string]string{"stdout": stdout.String(), "stderr": stderr.String(), "file": string(file)} {
+				if strings.Contains(out, testSecret) || !strings.Contains(out, "[REDACTED:anthropic-api-key]") {
+					t.Errorf("Expected masked %s, got %q", name, out)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Package callcli provides functionality for executing external CLI commands.
+package callcli
+
+import "strings"
+
+// BypassPermissions is the permission mode unattended runs use unless they
+// select another one.
+const BypassPermissions = "bypassPermissions"
+
+// RunOptions selects the model, permissions and tools of a single AI CLI
+// run. Empty fields keep what the configured arguments say.
+type RunOptions struct {
+	// Model is passed as --model.
+	Model string
+	// PermissionMode is passed as --permission-mode.
+	PermissionMode string
+	// AllowedTools is passed as --allowedTools.
+	AllowedTools []string
+	// DisallowedTools is passed as --disallowedTools.
+	DisallowedTools []string
+	// Resume is the id of a session to continue, passed as --resume.
+	Resume string
+	// ExtraArgs are appended as they are.
+	ExtraArgs []string
+}
+
+// Args returns the CLI flags of the options.
+func (o RunOptions) Args() []string {
+	var args []string
+	if o.Model != "" {
+		args = append(args, "--model", o.Model)
+	}
+	if o.PermissionMode != "" {
+		args = append(args, "--permission-mode", o.PermissionMode)
+	}
+	if len(o.AllowedTools) > 0 {
+		args = append(args, "--allowedTools", strings.Join(o.AllowedTools, ","))
+	}
+	if len(o.DisallowedTools) > 0 {
+		args = append(args, "--disallowedTools", strings.Join(o.DisallowedTools, ","))
+	}
+	if o.Resume != "" {
+		args = append(args, "--resume", o.Resume)
+	}
+	return append(args, o.ExtraArgs...)
+}
+
+// Apply returns base with the flags of the options appended. Flags in base
+// that the options set are dropped, and so is --dangerously-skip-permissions
+// when the options select a permission mode other than bypassPermissions,
+// so a restricted run is not silently unrestricted by the configuration.
+func (o RunOptions) Apply(base []string) []string {
+	overridden := make(map[string]bool)
+	if o.Model != "" {
+		overridden["--model"] = true
+	}
+	if o.PermissionMode != "" {
+		overridden["--permission-mode"] = true
+	}
+	if len(o.AllowedTools) > 0 {
+		overridden["--allowedTools"] = true
+		overridden["--allowed-tools"] = true
+	}
+	if len(o.DisallowedTools) > 0 {
+		overridden["--disallowedTools"] = true
+		overridden["--disallowed-tools"] = true
+	}
+	if o.Resume != "" {
+		overridden["--resume"] = true
+		overridden["-r"] = true
+	}
+	restricted := o.PermissionMode != "" && o.PermissionMode != BypassPermissions
+
+	args := make([]string, 0, len(base))
+	for i := 0; i < len(base); i++ {
+		name, _, inline := strings.Cut(base[i], "=")
+		switch {
+		case overridden[name]:
+			if !inline && i+1 < len(base) {
+				i++ // drop the value too
+			}
+		case restricted && base[i] == "--dangerously-skip-permissions":
+		default:
+			args = append(args, base[i])
+		}
+	}
+	return append(args, o.Args()...)
+}
@@ -0,0 +1,87 @@
+// Package callcli provides functionality for executing external CLI commands.
+package callcli
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestRunOptions_Apply tests that run options replace the flags they set.
+func TestRunOptions_Apply(t *testing.T) {
+	base := []string{"-p", "--verbose", "--model", "opus", "--allowedTools=Bash", "--dangerously-skip-permissions"}
+
+	tests := []struct {
+		name string
+		opts RunOptions
+		want []string
+	}{
+		{
+			name: "no options",
+			want: base,
+		},
+		{
+			name: "bypass keeps skip permissions",
+			opts: RunOptions{PermissionMode: BypassPermissions},
+			want: append(append([]string{}, base...), "--permission-mode", "bypassPermissions"),
+		},
+		{
+			name: "restricted job",
+			opts: RunOptions{
+				Model:           "haiku",
+				PermissionMode:  "acceptEdits",
+				AllowedTools:    []string{"Read", "Bash(go test:*)"},
+				DisallowedTools: []string{"WebFetch"},
+				ExtraArgs:       []string{"--max-turns", "20"},
+			},
+			want: []string{"-p", "--verbose",
+				"--model", "haiku", "--permission-mode", "acceptEdits",
+				"--allowedTools", "Read,Bash(go test:*)", "--disallowedTools", "WebFetch",
+				"--max-turns", "20"},
+		},
+		{
+			name: "resumed session",
+			opts: RunOptions{Resume: "abc-123"},
+			want: append(append([]string{}, base...), "--resume", "abc-123"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.Apply(base); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Apply() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFindSessionID tests reading the session id of an AI CLI run.
+func TestFindSessionID(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{
+			name:   "event stream",
+			output: `[{"type":"system","subtype":"init","session_id":"abc-123"},{"type":"result","result":"done","session_id":"abc-123"}]`,
+			want:   "abc-123",
+		},
+		{
+			name:   "stream cut off by a timeout",
+			output: "{\"type\":\"system\",\"subtype\":\"init\",\"session_id\": \"def-456\"}\n{\"type\":\"assistant\",\"mess",
+			want:   "def-456",
+		},
+		{
+			name:   "plain text",
+			output: "done",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindSessionID(tt.output); got != tt.want {
+				t.Errorf("FindSessionID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
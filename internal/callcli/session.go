@@ -0,0 +1,26 @@
+package callcli
+
+import (
+	"regexp"
+	"strings"
+)
+
+// sessionIDPattern matches the session id field of an AI CLI event.
+var sessionIDPattern = regexp.MustCompile(`"session_id"\s*:\s*"([^"]+)"`)
+
+// FindSessionID returns the id of the session an AI CLI run reported in its
+// output, or "" when there is none. It reads the event stream when the
+// output parses as one, and otherwise scans the raw output, so the id of a
+// run that was killed mid-stream is still found.
+func FindSessionID(output string) string {
+	output = strings.TrimSpace(output)
+	if strings.HasPrefix(output, "[") {
+		if conversation, err := NewConversationParser("").Parse(output); err == nil && conversation.SessionID != "" {
+			return conversation.SessionID
+		}
+	}
+	if m := sessionIDPattern.FindStringSubmatch(output); m != nil {
+		return m[1]
+	}
+	return ""
+}
@@ -141,7 +141,9 @@ func (g *globalSignalHandler) handleSignal(sig os.Signal) {
 // CallWithSignal executes a command with signal handling support.
 func (c *CallerImpl) CallWithSignal(ctx context.Context, name string, args []string, opts Options) (*SignalHandler, error) {
 	// Start global signal handler
-	getGlobalSignalHandler().Start()
+	if !opts.DetachSignals {
+		getGlobalSignalHandler().Start()
+	}
 
 	// Build the full command string for debugging
 	commandStr := buildCommandString(name, args)
@@ -222,7 +224,9 @@ func (c *CallerImpl) CallWithSignal(ctx context.Context, name string, args []str
 	handler.mu.Unlock()
 
 	// Register with global handler
-	getGlobalSignalHandler().Register(handler)
+	if !opts.DetachSignals {
+		getGlobalSignalHandler().Register(handler)
+	}
 
 	// Start goroutine to wait for completion
 	go handler.waitWithSignal(ctx, timeout)
@@ -260,24 +264,21 @@ func (h *SignalHandler) waitWithSignal(ctx context.Context, timeout time.Duratio
 		h.mu.Lock()
 		h.interrupted = true
 		h.mu.Unlock()
-		h.terminateProcess()
-		waitErr = <-done
+		waitErr = h.terminateProcess(done)
 	case <-ctx.Done():
 		// Context cancelled
 		cancelled = true
 		h.mu.Lock()
 		h.interrupted = true
 		h.mu.Unlock()
-		h.terminateProcess()
-		waitErr = <-done
+		waitErr = h.terminateProcess(done)
 	case sig := <-h.signalCh:
 		// Signal received
 		h.mu.Lock()
 		h.interrupted = true
 		h.signalReceived = sig
 		h.mu.Unlock()
-		h.terminateProcess()
-		waitErr = <-done
+		waitErr = h.terminateProcess(done)
 	}
 
 	duration := time.Since(startTime)
@@ -380,27 +381,18 @@ func (h *SignalHandler) handleSignal(sig os.Signal) {
 	h.mu.Unlock()
 }
 
-// terminateProcess performs graceful termination.
-func (h *SignalHandler) terminateProcess() {
-	if h.cmd == nil || h.cmd.Process == nil {
-		return
-	}
-
+// terminateProcess performs graceful termination and returns the result of
+// cmd.Wait, which the caller receives on exited.
+func (h *SignalHandler) terminateProcess(exited <-chan error) error {
 	// Forward SIGTERM to process group first
 	h.forwardSignal(syscall.SIGTERM)
 
 	// If graceful period is set, wait for it before sending SIGKILL
 	if h.gracefulPeriod > 0 {
-		done := make(chan struct{})
-		go func() {
-			h.cmd.Wait()
-			close(done)
-		}()
-
 		select {
-		case <-done:
+		case err := <-exited:
 			// Process exited gracefully
-			return
+			return err
 		case <-time.After(h.gracefulPeriod):
 			// Grace period expired, send SIGKILL
 			h.forwardSignal(syscall.SIGKILL)
@@ -409,6 +401,7 @@ func (h *SignalHandler) terminateProcess() {
 		// No graceful period, send SIGKILL immediately
 		h.forwardSignal(syscall.SIGKILL)
 	}
+	return <-exited
 }
 
 // forwardSignal forwards a signal to the process group.
@@ -486,14 +479,8 @@ func (h *SignalHandler) forwardSignalAndWait(sig os.Signal) error {
 	h.forwardSignal(sig)
 
 	// Brief wait to allow process to exit
-	done := make(chan struct{})
-	go func() {
-		h.cmd.Wait()
-		close(done)
-	}()
-
 	select {
-	case <-done:
+	case <-h.done:
 		return nil
 	case <-time.After(100 * time.Millisecond):
 		return nil
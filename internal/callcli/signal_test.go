@@ -210,6 +210,51 @@ func TestSignalHandler_GracefulTermination(t *testing.T) {
 	}
 }
 
+// TestSignalHandler_DetachSignalsKill tests that a detached command which
+// ignores SIGTERM is killed once the graceful period has passed.
+func TestSignalHandler_DetachSignalsKill(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping on Windows")
+	}
+
+	caller := New()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	opts := Options{
+		GracefulPeriod: 200 * time.Millisecond,
+		DetachSignals:  true,
+	}
+
+	handler, err := caller.CallWithSignal(ctx, "sh", []string{"-c", "trap '' TERM; echo started; sleep 30"}, opts)
+	if err != nil {
+		t.Fatalf("CallWithSignal failed: %v", err)
+	}
+
+	gh := getGlobalSignalHandler()
+	gh.mu.RLock()
+	_, registered := gh.handlers[handler.PID()]
+	gh.mu.RUnlock()
+	if registered {
+		t.Error("expected a detached handler not to receive forwarded signals")
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+
+	start := time.Now()
+	result, err := handler.Wait()
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Wait() returned after %v, want SIGKILL after the graceful period", elapsed)
+	}
+
+	if mortyErr, ok := errors.AsMortyError(err); !ok || mortyErr.Code != "M5007" {
+		t.Errorf("expected error code M5007, got %v", err)
+	}
+	if !result.Interrupted || result.Stdout != "started" {
+		t.Errorf("expected an interrupted result with partial output, got %+v", result)
+	}
+}
+
 // TestSignalHandler_InterruptState tests interrupt state tracking.
 func TestSignalHandler_InterruptState(t *testing.T) {
 	if runtime.GOOS == "windows" {
@@ -0,0 +1,383 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/morty/morty/internal/config"
+	"github.com/morty/morty/internal/logging"
+	"github.com/morty/morty/internal/parser/plan"
+	"github.com/morty/morty/internal/schema"
+)
+
+// ConfigOptions holds the parsed config command options.
+type ConfigOptions struct {
+	Action     string // get, set, unset, list, validate, path, explain or schema
+	Key        string // Dotted config key, e.g. logging.level; settings or plan for schema
+	Value      string // Value to set
+	Global     bool   // --global: write the user config instead of the project config
+	ShowOrigin bool   // --show-origin: list the layer each value came from
+	Output     string // --output: file the schema is written to instead of stdout
+}
+
+// ConfigResult represents the result of the config command.
+type ConfigResult struct {
+	Action string
+	Key    string
+	Value  interface{}   // Effective value (get, set)
+	Origin config.Origin // Layer the effective value came from (get)
+	File   string        // Config or schema file written (set, unset, schema)
+	Errors []string      // Problems found (validate)
+}
+
+// ConfigHandler handles the config command.
+type ConfigHandler struct {
+	cfg    config.Manager
+	logger logging.Logger
+	out    io.Writer
+}
+
+// NewConfigHandler creates a new ConfigHandler instance.
+func NewConfigHandler(cfg config.Manager, logger logging.Logger) *ConfigHandler {
+	return &ConfigHandler{
+		cfg:    cfg,
+		logger: logger,
+		out:    os.Stdout,
+	}
+}
+
+// SetOutput sets the writer the command prints to.
+func (h *ConfigHandler) SetOutput(w io.Writer) {
+	h.out = w
+}
+
+// Execute reads, changes or checks the configuration. Values are merged
+// from the built-in defaults, the user config, the project config and
+// environment variables, in that order.
+func (h *ConfigHandler) Execute(ctx context.Context, args []string) (*ConfigResult, error) {
+	opts, err := parseConfigOptions(args)
+	if err != nil {
+		return nil, err
+	}
+	loader, ok := h.cfg.(*config.Loader)
+	if !ok {
+		loader = config.NewLoader()
+		loader.LoadWithMerge(config.DefaultUserConfigFile)
+	}
+
+	result := &ConfigResult{Action: opts.Action, Key: opts.Key}
+	switch opts.Action {
+	case "get":
+		err = h.get(loader, opts, result)
+	case "set":
+		err = h.set(ctx, loader, opts, result)
+	case "unset":
+		err = h.unset(ctx, loader, opts, result)
+	case "list":
+		err = h.list(loader, opts)
+	case "validate":
+		err = h.validate(loader, result)
+	case "path":
+		h.printPaths(loader)
+	case "explain":
+		err = h.explain(loader, opts, result)
+	case "schema":
+		err = h.writeSchema(opts, result)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (h *ConfigHandler) get(loader *config.Loader, opts ConfigOptions, result *ConfigResult) error {
+	value, err := loader.Get(opts.Key)
+	if err != nil {
+		return unknownKeyError(opts.Key)
+	}
+	result.Value = value
+	result.Origin = loader.Origin(opts.Key)
+	fmt.Fprintln(h.out, formatConfigValue(value))
+	return nil
+}
+
+func (h *ConfigHandler) set(ctx context.Context, loader *config.Loader, opts ConfigOptions, result *ConfigResult) error {
+	if !config.IsKey(opts.Key) && !config.IsProfileKey(opts.Key) {
+		return unknownKeyError(opts.Key)
+	}
+	value, err := config.ParseValue(opts.Key, opts.Value)
+	if err != nil {
+		return fmt.Errorf("无效的配置值: %w", err)
+	}
+	file := configScopeFile(loader, opts)
+	if err := config.SetFileValue(file, opts.Key, value); err != nil {
+		return fmt.Errorf("写入配置失败: %w", err)
+	}
+	result.Value, result.File = value, file
+
+	h.logger.WithContext(ctx).Debug("Config value set",
+		logging.String("key", opts.Key),
+		logging.String("file", file),
+	)
+	fmt.Fprintf(h.out, "已设置 %s = %s (%s)\n", opts.Key, formatConfigValue(value), file)
+	h.warnOverridden(loader, opts)
+	return nil
+}
+
+func (h *ConfigHandler) unset(ctx context.Context, loader *config.Loader, opts ConfigOptions, result *ConfigResult) error {
+	if !config.IsKey(opts.Key) && !config.IsProfileKey(opts.Key) {
+		return unknownKeyError(opts.Key)
+	}
+	file := configScopeFile(loader, opts)
+	removed, err := config.UnsetFileValue(file, opts.Key)
+	if err != nil {
+		return fmt.Errorf("写入配置失败: %w", err)
+	}
+	result.File = file
+	if !removed {
+		fmt.Fprintf(h.out, "%s 没有设置 %s\n", file, opts.Key)
+		return nil
+	}
+
+	h.logger.WithContext(ctx).Debug("Config value removed",
+		logging.String("key", opts.Key),
+		logging.String("file", file),
+	)
+	fmt.Fprintf(h.out, "已移除 %s (%s)\n", opts.Key, file)
+	h.warnOverridden(loader, opts)
+	return nil
+}
+
+// warnOverridden reports when a higher layer than the one just written
+// still decides the effective value.
+func (h *ConfigHandler) warnOverridden(loader *config.Loader, opts ConfigOptions) {
+	origin := loader.Origin(opts.Key)
+	if origin.Layer == config.LayerEnv || origin.Layer == config.LayerProfile || (opts.Global && origin.Layer == config.LayerProject) {
+		fmt.Fprintf(h.out, "注意: %s 仍被 %s 覆盖\n", opts.Key, origin)
+	}
+}
+
+func (h *ConfigHandler) list(loader *config.Loader, opts ConfigOptions) error {
+	if profile := loader.Profile(); profile != "" {
+		fmt.Fprintf(h.out, "profile: %s\n", profile)
+	}
+	tw := tabwriter.NewWriter(h.out, 0, 0, 2, ' ', 0)
+	for _, key := range config.Keys() {
+		value, err := loader.Get(key)
+		if err != nil {
+			continue
+		}
+		if opts.ShowOrigin {
+			fmt.Fprintf(tw, "%s\t%s = %s\n", loader.Origin(key), key, formatConfigValue(value))
+		} else {
+			fmt.Fprintf(tw, "%s = %s\n", key, formatConfigValue(value))
+		}
+	}
+	return tw.Flush()
+}
+
+func (h *ConfigHandler) validate(loader *config.Loader, result *ConfigResult) error {
+	for _, file := range []string{loader.UserConfigFile(), loader.ProjectConfigFile()} {
+		if err := config.CheckFile(file); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", file, err))
+		}
+	}
+	if err := loader.Validate(); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+
+	if len(result.Errors) > 0 {
+		for _, e := range result.Errors {
+			fmt.Fprintf(h.out, "✗ %s\n", e)
+		}
+		return fmt.Errorf("配置无效 (%d 个问题)", len(result.Errors))
+	}
+	fmt.Fprintln(h.out, "✓ 配置有效")
+	return nil
+}
+
+func (h *ConfigHandler) printPaths(loader *config.Loader) {
+	tw := tabwriter.NewWriter(h.out, 0, 0, 2, ' ', 0)
+	for _, f := range []struct {
+		layer config.Layer
+		path  string
+	}{
+		{config.LayerUser, loader.UserConfigFile()},
+		{config.LayerProject, loader.ProjectConfigFile()},
+	} {
+		state := "不存在"
+		if _, err := os.Stat(f.path); err == nil {
+			state = "存在"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t(%s)\n", f.layer, f.path, state)
+	}
+	tw.Flush()
+}
+
+// explain prints the value of a key in every layer and which one wins.
+func (h *ConfigHandler) explain(loader *config.Loader, opts ConfigOptions, result *ConfigResult) error {
+	value, err := loader.Get(opts.Key)
+	if err != nil {
+		return unknownKeyError(opts.Key)
+	}
+	defaultValue, _ := config.NewLoader().Get(opts.Key)
+	origin := loader.Origin(opts.Key)
+	result.Value, result.Origin = value, origin
+
+	fmt.Fprintf(h.out, "%s (%T)\n", opts.Key, value)
+	tw := tabwriter.NewWriter(h.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "  %s\t%s\n", config.LayerDefault, formatConfigValue(defaultValue))
+	for _, f := range []struct {
+		layer config.Layer
+		path  string
+	}{
+		{config.LayerUser, loader.UserConfigFile()},
+		{config.LayerProject, loader.ProjectConfigFile()},
+	} {
+		if v, ok, err := config.ReadFileValue(f.path, opts.Key); err == nil && ok {
+			fmt.Fprintf(tw, "  %s\t%s\t%s\n", f.layer, formatConfigValue(v), f.path)
+		}
+	}
+	if v, ok := loader.ProfileValue(opts.Key); ok {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", config.LayerProfile, formatConfigValue(v), loader.Profile())
+	}
+	if origin.Layer == config.LayerEnv {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", config.LayerEnv, formatConfigValue(value), origin.Source)
+	}
+	tw.Flush()
+	fmt.Fprintf(h.out, "生效值: %s (来自 %s)\n", formatConfigValue(value), origin)
+	return nil
+}
+
+// writeSchema prints the JSON Schema of settings files or plans, or writes it to
+// --output.
+func (h *ConfigHandler) writeSchema(opts ConfigOptions, result *ConfigResult) error {
+	var s *schema.Schema
+	switch opts.Key {
+	case "", "settings":
+		s = config.Schema()
+	case "plan":
+		s = plan.Schema()
+	default:
+		return fmt.Errorf("未知的 schema: %s (可选: settings, plan)", opts.Key)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("生成 schema 失败: %w", err)
+	}
+	data = append(data, '\n')
+
+	if opts.Output == "" {
+		_, err = h.out.Write(data)
+		return err
+	}
+	if err := os.WriteFile(opts.Output, data, 0644); err != nil {
+		return fmt.Errorf("写入 schema 失败: %w", err)
+	}
+	result.File = opts.Output
+	fmt.Fprintf(h.out, "已写入 %s\n", opts.Output)
+	return nil
+}
+
+// configScopeFile returns the file set and unset write to.
+func configScopeFile(loader *config.Loader, opts ConfigOptions) string {
+	if opts.Global {
+		return loader.UserConfigFile()
+	}
+	return loader.ProjectConfigFile()
+}
+
+// unknownKeyError reports an unknown key with the closest known one.
+func unknownKeyError(key string) error {
+	if suggestion := config.SuggestKey(key); suggestion != "" {
+		return fmt.Errorf("未知的配置项: %s (是否是 %s?)", key, suggestion)
+	}
+	return fmt.Errorf("未知的配置项: %s (使用 morty config list 查看所有配置项)", key)
+}
+
+// formatConfigValue prints strings as is and other values as JSON.
+func formatConfigValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// parseConfigOptions parses config command arguments.
+func parseConfigOptions(args []string) (ConfigOptions, error) {
+	var opts ConfigOptions
+	var positional []string
+	project := false
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--output", "-o":
+			if i+1 >= len(args) {
+				return opts, fmt.Errorf("%s 需要指定文件", arg)
+			}
+			i++
+			opts.Output = args[i]
+		case "--global", "-g":
+			opts.Global = true
+		case "--project", "-p":
+			project = true
+		case "--show-origin":
+			opts.ShowOrigin = true
+		default:
+			if strings.HasPrefix(arg, "-") && len(positional) < 2 {
+				return opts, fmt.Errorf("未知参数: %s", arg)
+			}
+			positional = append(positional, arg)
+		}
+	}
+	if opts.Global && project {
+		return opts, fmt.Errorf("--global 与 --project 不能同时使用")
+	}
+	if len(positional) == 0 {
+		return opts, fmt.Errorf("需要指定子命令: get, set, unset, list, validate, path, explain, schema")
+	}
+
+	opts.Action = positional[0]
+	positional = positional[1:]
+	want := map[string]int{"get": 1, "set": 2, "unset": 1, "list": 0, "validate": 0, "path": 0, "explain": 1, "schema": 1}
+	n, ok := want[opts.Action]
+	if !ok {
+		return opts, fmt.Errorf("未知子命令: %s (可选: get, set, unset, list, validate, path, explain, schema)", opts.Action)
+	}
+	if opts.Action == "schema" && len(positional) == 0 {
+		n = 0
+	}
+	if len(positional) != n {
+		usage := map[int]string{0: "", 1: " <key>", 2: " <key> <value>"}[n]
+		if opts.Action == "schema" {
+			usage = " [settings|plan]"
+		}
+		return opts, fmt.Errorf("用法: morty config %s%s", opts.Action, usage)
+	}
+	if n > 0 {
+		opts.Key = positional[0]
+	}
+	if n > 1 {
+		opts.Value = positional[1]
+	}
+	if (opts.Global || project) && opts.Action != "set" && opts.Action != "unset" {
+		return opts, fmt.Errorf("--global/--project 只能用于 set 和 unset")
+	}
+	if opts.ShowOrigin && opts.Action != "list" {
+		return opts, fmt.Errorf("--show-origin 只能用于 list")
+	}
+	if opts.Output != "" && opts.Action != "schema" {
+		return opts, fmt.Errorf("--output 只能用于 schema")
+	}
+	return opts, nil
+}
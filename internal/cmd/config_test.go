@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/morty/morty/internal/config"
+)
+
+// newConfigTestHandler creates a handler for a project in a temporary
+// directory with a user config file.
+func newConfigTestHandler(t *testing.T, userConfig string) (*ConfigHandler, *bytes.Buffer, string) {
+	t.Helper()
+	dir := t.TempDir()
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(origDir) })
+
+	userPath := filepath.Join(dir, "user.json")
+	if userConfig != "" {
+		os.WriteFile(userPath, []byte(userConfig), 0644)
+	}
+	loader := config.NewLoader()
+	loader.LoadWithMerge(userPath)
+
+	var out bytes.Buffer
+	handler := NewConfigHandler(loader, &mockLogger{})
+	handler.SetOutput(&out)
+	return handler, &out, userPath
+}
+
+// reloadConfig runs a command against a freshly loaded configuration, as a new
+// morty process would.
+func reloadConfig(t *testing.T, userPath string, args ...string) (*ConfigResult, string, error) {
+	t.Helper()
+	loader := config.NewLoader()
+	loader.LoadWithMerge(userPath)
+	var out bytes.Buffer
+	handler := NewConfigHandler(loader, &mockLogger{})
+	handler.SetOutput(&out)
+	result, err := handler.Execute(context.Background(), args)
+	return result, out.String(), err
+}
+
+func TestConfigHandler_SetGetUnset(t *testing.T) {
+	handler, out, userPath := newConfigTestHandler(t, `{"logging": {"level": "warn"}}`)
+
+	if _, err := handler.Execute(context.Background(), []string{"set", "logging.level", "debug"}); err != nil {
+		t.Fatalf("set failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "已设置 logging.level = debug") {
+		t.Errorf("Unexpected output: %s", out.String())
+	}
+
+	result, output, err := reloadConfig(t, userPath, "get", "logging.level")
+	if err != nil || output != "debug\n" || result.Origin.Layer != config.LayerProject {
+		t.Errorf("get = %q from %v, %v", output, result.Origin, err)
+	}
+
+	if _, err := handler.Execute(context.Background(), []string{"set", "--global", "execution.max_retry_count", "7"}); err != nil {
+		t.Fatalf("set --global failed: %v", err)
+	}
+	data, _ := os.ReadFile(userPath)
+	if !strings.Contains(string(data), `"max_retry_count": 7`) || !strings.Contains(string(data), `"level": "warn"`) {
+		t.Errorf("Expected the user config to keep its keys:\n%s", data)
+	}
+
+	if _, _, err := reloadConfig(t, userPath, "unset", "logging.level"); err != nil {
+		t.Fatalf("unset failed: %v", err)
+	}
+	result, output, _ = reloadConfig(t, userPath, "get", "logging.level")
+	if output != "warn\n" || result.Origin.Layer != config.LayerUser {
+		t.Errorf("Expected user value after unset, got %q from %v", output, result.Origin)
+	}
+}
+
+func TestConfigHandler_ListShowOrigin(t *testing.T) {
+	_, _, userPath := newConfigTestHandler(t, `{"ai_cli": {"command": "claude-dev"}}`)
+
+	_, output, err := reloadConfig(t, userPath, "list", "--show-origin")
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	for _, want := range []string{"user:" + userPath, "ai_cli.command = claude-dev", "default", "git.auto_commit = true"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in:\n%s", want, output)
+		}
+	}
+}
+
+func TestConfigHandler_Validate(t *testing.T) {
+	handler, out, _ := newConfigTestHandler(t, `{"defaults": {"max_retry_count": 3}}`)
+
+	result, err := handler.Execute(context.Background(), []string{"validate"})
+	if err == nil || result != nil {
+		t.Fatalf("Expected validation to fail, got %v", err)
+	}
+	if !strings.Contains(out.String(), `'defaults': unknown field`) {
+		t.Errorf("Unexpected output: %s", out.String())
+	}
+}
+
+func TestConfigHandler_Explain(t *testing.T) {
+	_, _, userPath := newConfigTestHandler(t, `{"logging": {"level": "warn"}}`)
+	os.Setenv(config.EnvMortyLogLevel, "error")
+	defer os.Unsetenv(config.EnvMortyLogLevel)
+
+	_, output, err := reloadConfig(t, userPath, "explain", "logging.level")
+	if err != nil {
+		t.Fatalf("explain failed: %v", err)
+	}
+	for _, want := range []string{"default info", "user warn " + userPath, "env error MORTY_LOG_LEVEL", "生效值: error (来自 env:MORTY_LOG_LEVEL)"} {
+		if !strings.Contains(strings.Join(strings.Fields(output), " "), want) {
+			t.Errorf("Expected %q in:\n%s", want, output)
+		}
+	}
+}
+
+func TestConfigHandler_Profile(t *testing.T) {
+	_, _, userPath := newConfigTestHandler(t, `{"profiles": {"ci": {"git": {"auto_commit": false}}}}`)
+
+	if _, output, err := reloadConfig(t, userPath, "set", "profiles.ci.execution.max_retry_count", "0"); err != nil {
+		t.Fatalf("set failed: %v\n%s", err, output)
+	}
+
+	loader := config.NewLoader()
+	loader.SetProfile("ci")
+	if err := loader.LoadWithMerge(userPath); err != nil {
+		t.Fatalf("LoadWithMerge() error: %v", err)
+	}
+	var out bytes.Buffer
+	handler := NewConfigHandler(loader, &mockLogger{})
+	handler.SetOutput(&out)
+
+	if _, err := handler.Execute(context.Background(), []string{"list", "--show-origin"}); err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	output := strings.Join(strings.Fields(out.String()), " ")
+	for _, want := range []string{"profile: ci ", "profile:ci git.auto_commit = false", "profile:ci execution.max_retry_count = 0"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	handler.Execute(context.Background(), []string{"explain", "git.auto_commit"})
+	if !strings.Contains(strings.Join(strings.Fields(out.String()), " "), "profile false ci") {
+		t.Errorf("Expected the profile layer in:\n%s", out.String())
+	}
+}
+
+func TestConfigHandler_Schema(t *testing.T) {
+	handler, out, _ := newConfigTestHandler(t, "")
+
+	if _, err := handler.Execute(context.Background(), []string{"schema"}); err != nil {
+		t.Fatalf("schema failed: %v", err)
+	}
+	var s map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &s); err != nil || s["title"] != "Morty settings" {
+		t.Fatalf("Expected the settings schema, got %v: %s", err, out.String())
+	}
+
+	result, err := handler.Execute(context.Background(), []string{"schema", "plan", "--output", "plan.schema.json"})
+	if err != nil || result.File != "plan.schema.json" {
+		t.Fatalf("schema plan failed: %v", err)
+	}
+	data, _ := os.ReadFile("plan.schema.json")
+	if !strings.Contains(string(data), `"Morty plan"`) || !strings.Contains(string(data), `"debug_logs"`) {
+		t.Errorf("Unexpected plan schema:\n%s", data)
+	}
+
+	if _, err := handler.Execute(context.Background(), []string{"schema", "status"}); err == nil {
+		t.Error("Expected an unknown schema to be rejected")
+	}
+}
+
+func TestParseConfigOptions(t *testing.T) {
+	tests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{nil, "需要指定子命令"},
+		{[]string{"frobnicate"}, "未知子命令"},
+		{[]string{"get"}, "用法: morty config get <key>"},
+		{[]string{"set", "a", "b", "--global", "--project"}, "不能同时使用"},
+		{[]string{"get", "a", "--global"}, "只能用于 set 和 unset"},
+		{[]string{"set", "execution.max_retry_count", "-1"}, ""},
+		{[]string{"schema", "plan", "-o"}, "需要指定文件"},
+		{[]string{"list", "--output", "x.json"}, "只能用于 schema"},
+	}
+	for _, tt := range tests {
+		_, err := parseConfigOptions(tt.args)
+		if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("parseConfigOptions(%v) error = %v, want %q", tt.args, err, tt.wantErr)
+		}
+	}
+
+	handler, _, _ := newConfigTestHandler(t, "")
+	if _, err := handler.Execute(context.Background(), []string{"get", "loging.level"}); err == nil || !strings.Contains(err.Error(), "logging.level") {
+		t.Errorf("Expected a suggestion for a misspelt key, got %v", err)
+	}
+}
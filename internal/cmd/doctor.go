@@ -0,0 +1,585 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/morty/morty/internal/callcli"
+	"github.com/morty/morty/internal/config"
+	"github.com/morty/morty/internal/doing"
+	"github.com/morty/morty/internal/git"
+	"github.com/morty/morty/internal/logging"
+	"github.com/morty/morty/internal/parser/prompt"
+	"github.com/morty/morty/internal/state"
+	"github.com/morty/morty/internal/validator"
+)
+
+// doctorCLITimeout bounds each AI CLI invocation made by the checks.
+const doctorCLITimeout = 10 * time.Second
+
+// maxDoctorDetails is the number of detail lines printed per check.
+const maxDoctorDetails = 10
+
+// CheckStatus is the outcome of a doctor check.
+type CheckStatus string
+
+// Doctor check outcomes.
+const (
+	CheckOK   CheckStatus = "ok"
+	CheckWarn CheckStatus = "warn"
+	CheckFail CheckStatus = "fail"
+)
+
+// DoctorCheck is the result of a single doctor check.
+type DoctorCheck struct {
+	Name    string      `json:"name"`
+	Status  CheckStatus `json:"status"`
+	Message string      `json:"message"`
+	Details []string    `json:"details,omitempty"`
+	Fix     string      `json:"fix,omitempty"` // Quick fix from doing.GetQuickFix
+}
+
+// DoctorOptions holds the parsed doctor command options.
+type DoctorOptions struct {
+	JSON bool // --json: print the checks as JSON
+}
+
+// DoctorResult represents the result of the doctor command.
+type DoctorResult struct {
+	Checks []DoctorCheck `json:"checks"`
+}
+
+// Count returns the number of checks with the given outcome.
+func (r *DoctorResult) Count(status CheckStatus) int {
+	n := 0
+	for _, c := range r.Checks {
+		if c.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// DoctorHandler handles the doctor command.
+type DoctorHandler struct {
+	cfg        config.Manager
+	logger     logging.Logger
+	out        io.Writer
+	gitManager *git.Manager
+}
+
+// NewDoctorHandler creates a new DoctorHandler instance.
+func NewDoctorHandler(cfg config.Manager, logger logging.Logger) *DoctorHandler {
+	return &DoctorHandler{
+		cfg:        cfg,
+		logger:     logger,
+		out:        os.Stdout,
+		gitManager: git.NewManager(),
+	}
+}
+
+// SetOutput sets the writer the report is printed to.
+func (h *DoctorHandler) SetOutput(w io.Writer) {
+	h.out = w
+}
+
+// Execute checks the environment and the project: the AI CLI, git, the
+// configuration, prompt templates, plans, status.json and leftover locks.
+// It returns an error when any check fails.
+func (h *DoctorHandler) Execute(ctx context.Context, args []string) (*DoctorResult, error) {
+	logger := h.logger.WithContext(ctx)
+
+	opts, err := parseDoctorOptions(args)
+	if err != nil {
+		return nil, err
+	}
+	loader, ok := h.cfg.(*config.Loader)
+	if !ok || loader == nil {
+		loader = config.NewLoader()
+		loader.LoadWithMerge(config.DefaultUserConfigFile)
+	}
+
+	status, statusErr := h.loadStatus()
+
+	result := &DoctorResult{}
+	for _, check := range []func() DoctorCheck{
+		func() DoctorCheck { return h.checkAICli(ctx) },
+		h.checkGit,
+		func() DoctorCheck { return h.checkConfig(loader) },
+		func() DoctorCheck { return h.checkPrompts(loader) },
+		h.checkPlans,
+		func() DoctorCheck { return h.checkStatus(status, statusErr) },
+		func() DoctorCheck { return h.checkLocks(status) },
+	} {
+		c := check()
+		logger.Debug("Doctor check finished",
+			logging.String("check", c.Name),
+			logging.String("status", string(c.Status)),
+			logging.String("message", c.Message),
+		)
+		result.Checks = append(result.Checks, c)
+	}
+
+	if opts.JSON {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("序列化检查结果失败: %w", err)
+		}
+		fmt.Fprintln(h.out, string(data))
+	} else {
+		h.printReport(result)
+	}
+
+	if n := result.Count(CheckFail); n > 0 {
+		return result, fmt.Errorf("%d 项检查失败", n)
+	}
+	return result, nil
+}
+
+// parseDoctorOptions parses the doctor command options.
+func parseDoctorOptions(args []string) (DoctorOptions, error) {
+	var opts DoctorOptions
+	for _, arg := range args {
+		switch arg {
+		case "--json":
+			opts.JSON = true
+		default:
+			return opts, fmt.Errorf("未知参数: %s", arg)
+		}
+	}
+	return opts, nil
+}
+
+// printReport prints one line per check, followed by its details and fix.
+func (h *DoctorHandler) printReport(result *DoctorResult) {
+	for _, c := range result.Checks {
+		fmt.Fprintf(h.out, "%s %s: %s\n", checkSymbol(c.Status), c.Name, c.Message)
+		details := c.Details
+		if len(details) > maxDoctorDetails {
+			details = append(details[:maxDoctorDetails:maxDoctorDetails],
+				fmt.Sprintf("... 还有 %d 项", len(c.Details)-maxDoctorDetails))
+		}
+		for _, d := range details {
+			fmt.Fprintf(h.out, "    - %s\n", d)
+		}
+		if c.Fix != "" && c.Status != CheckOK {
+			fmt.Fprintf(h.out, "    修复: %s\n", c.Fix)
+		}
+	}
+	fmt.Fprintf(h.out, "\n检查完成: %d 项通过, %d 项警告, %d 项失败\n",
+		result.Count(CheckOK), result.Count(CheckWarn), result.Count(CheckFail))
+}
+
+func checkSymbol(status CheckStatus) string {
+	switch status {
+	case CheckOK:
+		return "✓"
+	case CheckWarn:
+		return "⚠"
+	default:
+		return "✗"
+	}
+}
+
+// passed returns a successful check.
+func passed(name, message string, details ...string) DoctorCheck {
+	return DoctorCheck{Name: name, Status: CheckOK, Message: message, Details: details}
+}
+
+// problem returns a warning or failure whose message and fix come from err.
+func problem(name string, status CheckStatus, err *doing.DoingError, details ...string) DoctorCheck {
+	message := err.Message
+	if err.Cause != nil {
+		message += ": " + err.Cause.Error()
+	}
+	return DoctorCheck{
+		Name:    name,
+		Status:  status,
+		Message: message,
+		Details: details,
+		Fix:     doing.GetQuickFix(err),
+	}
+}
+
+// checkAICli resolves the AI CLI like the executor does, then checks that
+// it runs and supports the flags morty passes to it.
+func (h *DoctorHandler) checkAICli(ctx context.Context) DoctorCheck {
+	const name = "AI CLI"
+
+	caller := callcli.NewAICliCallerWithLoader(h.cfg)
+	cliPath := caller.GetCLIPath()
+	envVar := h.cfg.GetString("ai_cli.env_var", config.DefaultAICliEnvVar)
+	source := "ai_cli.command"
+	if os.Getenv(envVar) != "" {
+		source = "$" + envVar
+	}
+
+	notFound := func(message string, cause error) DoctorCheck {
+		return problem(name, CheckFail, doing.NewDoingError(doing.ErrorCategoryConfig, message, cause).
+			WithContext("error_type", "cli_not_found").
+			WithContext("env_var", envVar))
+	}
+
+	path, err := exec.LookPath(cliPath)
+	if err != nil {
+		return notFound(fmt.Sprintf("找不到 %s (来自 %s)", cliPath, source), nil)
+	}
+
+	versionOut, err := runCLI(ctx, path, "--version")
+	if err != nil {
+		return notFound(fmt.Sprintf("无法运行 %s (来自 %s)", path, source), err)
+	}
+	version := firstLine(versionOut)
+	message := fmt.Sprintf("%s (%s，来自 %s)", version, path, source)
+
+	helpOut, err := runCLI(ctx, path, "--help")
+	if err != nil {
+		return problem(name, CheckWarn, doing.NewDoingError(doing.ErrorCategoryConfig,
+			fmt.Sprintf("%s; 无法读取 --help 输出", message), err).
+			WithContext("config_key", "ai_cli.command"))
+	}
+
+	var missing []string
+	for _, flag := range cliFlags(caller.BuildArgs()) {
+		if !helpMentionsFlag(helpOut, flag) {
+			missing = append(missing, flag)
+		}
+	}
+	if len(missing) > 0 {
+		return problem(name, CheckWarn, doing.NewDoingError(doing.ErrorCategoryConfig,
+			fmt.Sprintf("%s; 不支持参数 %s", message, strings.Join(missing, ", ")), nil).
+			WithContext("config_key", "ai_cli.default_args"))
+	}
+	return passed(name, message)
+}
+
+// runCLI runs the AI CLI with a timeout and returns its combined output.
+func runCLI(ctx context.Context, path string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, doctorCLITimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, path, args...).CombinedOutput()
+	if ctx.Err() != nil {
+		return string(out), fmt.Errorf("%s 超时 (%s)", strings.Join(args, " "), doctorCLITimeout)
+	}
+	return string(out), err
+}
+
+// cliFlags returns the flags morty passes to the AI CLI: the ones the
+// executor adds to every call and the configured ones.
+func cliFlags(args []string) []string {
+	flags := []string{"--permission-mode", "-p"}
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		flag, _, _ := strings.Cut(arg, "=")
+		flags = append(flags, flag)
+	}
+	return flags
+}
+
+// helpMentionsFlag reports whether a --help output documents flag.
+func helpMentionsFlag(help, flag string) bool {
+	return regexp.MustCompile(`(^|[\s,\[])` + regexp.QuoteMeta(flag) + `($|[\s,=\]])`).MatchString(help)
+}
+
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return "(无版本信息)"
+}
+
+// checkGit checks that the project is a git repository and whether the
+// worktree state allows a run.
+func (h *DoctorHandler) checkGit() DoctorCheck {
+	const name = "Git"
+
+	if _, err := exec.LookPath("git"); err != nil {
+		return problem(name, CheckFail, doing.NewDoingError(doing.ErrorCategoryGit, "找不到 git 命令", nil).
+			WithContext("error_type", "git_error"))
+	}
+
+	root, err := h.gitManager.GetRepoRoot(".")
+	if err != nil {
+		status := CheckWarn
+		if h.cfg.GetBool("git.auto_commit") {
+			status = CheckFail
+		}
+		return problem(name, status, doing.NewDoingError(doing.ErrorCategoryGit, "当前目录不是 Git 仓库", nil).
+			WithContext("error_type", "git_not_initialized"))
+	}
+
+	branch, err := h.gitManager.RunGitCommand(".", "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		branch = "(尚无提交)"
+	}
+	message := fmt.Sprintf("%s，分支 %s", root, branch)
+
+	dirty, err := h.gitManager.HasUncommittedChanges(".")
+	if err != nil {
+		return problem(name, CheckWarn, doing.NewDoingError(doing.ErrorCategoryGit, "无法读取工作区状态", err).
+			WithContext("error_type", "git_error"))
+	}
+	if dirty {
+		if h.cfg.GetBool("git.require_clean_worktree") {
+			return problem(name, CheckFail, doing.NewDoingError(doing.ErrorCategoryGit,
+				message+"; 工作区有未提交的修改 (git.require_clean_worktree 已开启)", nil).
+				WithContext("error_type", "git_dirty"))
+		}
+		return passed(name, message, "工作区有未提交的修改")
+	}
+	return passed(name, message)
+}
+
+// checkConfig checks the config files against the schema and validates
+// the merged configuration, reporting where it was loaded from.
+func (h *DoctorHandler) checkConfig(loader *config.Loader) DoctorCheck {
+	const name = "配置"
+
+	var details, problems []string
+	for _, f := range []struct {
+		layer config.Layer
+		path  string
+	}{
+		{config.LayerUser, loader.UserConfigFile()},
+		{config.LayerProject, loader.ProjectConfigFile()},
+	} {
+		fileState := "不存在"
+		if _, err := os.Stat(f.path); err == nil {
+			fileState = "存在"
+		}
+		details = append(details, fmt.Sprintf("%s: %s (%s)", f.layer, f.path, fileState))
+		if err := config.CheckFile(f.path); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", f.path, err))
+		}
+	}
+	if profile := loader.Profile(); profile != "" {
+		details = append(details, "profile: "+profile)
+	}
+	if err := loader.Validate(); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if len(problems) > 0 {
+		return problem(name, CheckFail, doing.NewDoingError(doing.ErrorCategoryConfig,
+			fmt.Sprintf("配置无效 (%d 个问题)", len(problems)), nil).
+			WithContext("error_type", "config_error"), problems...)
+	}
+	return passed(name, "配置有效", details...)
+}
+
+// checkPrompts checks that the prompt instruction files resolve to
+// existing files and that every phase template parses and uses the
+// variables its phase provides.
+func (h *DoctorHandler) checkPrompts(loader *config.Loader) DoctorCheck {
+	const name = "提示词"
+
+	paths := config.NewPathsWithLoader(loader)
+	promptsDir := paths.GetPromptsDir()
+	dirInfo := fmt.Sprintf("%s (prompts.dir 来自 %s)", promptsDir, loader.Origin("prompts.dir"))
+	engine := newPromptEngine(h.cfg, paths)
+
+	var found, missing, problems []string
+	for _, key := range []string{"research", "plan", "doing"} {
+		source, err := engine.Source(key + ".md")
+		if err != nil {
+			missing = append(missing, fmt.Sprintf("%s: %s", key, filepath.Join(promptsDir, key+".md")))
+			continue
+		}
+		found = append(found, fmt.Sprintf("%s: %s", key, source))
+	}
+
+	if len(missing) > 0 {
+		return problem(name, CheckFail, doing.NewDoingError(doing.ErrorCategoryConfig,
+			fmt.Sprintf("找不到 %d 个提示词模板，目录 %s", len(missing), dirInfo), nil).
+			WithContext("config_key", "prompts.dir"), missing...)
+	}
+
+	for _, phase := range prompt.Phases {
+		for _, p := range promptProblems(engine.Check(phase)) {
+			problems = append(problems, fmt.Sprintf("%s: %s", phase.Name, p))
+		}
+	}
+	if len(problems) > 0 {
+		return problem(name, CheckWarn, doing.NewDoingError(doing.ErrorCategoryConfig,
+			"提示词模板有问题，运行 morty prompts check 查看详情", nil).
+			WithContext("config_key", "prompts.dir"), problems...)
+	}
+	return passed(name, dirInfo, found...)
+}
+
+// checkPlans validates the plan files.
+func (h *DoctorHandler) checkPlans() DoctorCheck {
+	const name = "计划"
+
+	planDir := h.cfg.GetPlanDir()
+	notFound := doing.NewDoingError(doing.ErrorCategoryPlan, fmt.Sprintf("%s 中没有计划文件", planDir), nil).
+		WithContext("error_type", "plan_not_found")
+	if _, err := os.Stat(planDir); err != nil {
+		return problem(name, CheckWarn, notFound)
+	}
+
+	v := validator.NewPlanValidator(planDir, false)
+	refs := referenceResolver(h.cfg.GetWorkDir(), h.cfg.GetResearchDir())
+	v.SetReferenceRoot(refs.Root, refs.ResearchDir)
+	results, err := v.ValidateAll()
+	if err != nil {
+		return problem(name, CheckWarn, notFound)
+	}
+
+	var errs []string
+	for _, r := range results {
+		for _, e := range r.Errors {
+			errs = append(errs, e.Error())
+		}
+	}
+	if !allPassed(results) {
+		return problem(name, CheckFail, doing.NewDoingError(doing.ErrorCategoryPlan,
+			fmt.Sprintf("计划校验失败 (%d 个问题)", len(errs)), nil).
+			WithContext("error_type", "plan_invalid"), errs...)
+	}
+	return passed(name, fmt.Sprintf("%d 个计划文件通过校验", len(results)))
+}
+
+// loadStatus reads status.json. A missing file yields a nil status.
+func (h *DoctorHandler) loadStatus() (*state.ExecutionStatus, error) {
+	statusFile := h.cfg.GetStatusFile()
+	if _, err := os.Stat(statusFile); os.IsNotExist(err) {
+		return nil, nil
+	}
+	stateManager := state.NewManager(statusFile)
+	if err := stateManager.Load(); err != nil {
+		return nil, err
+	}
+	return stateManager.GetStatus(), nil
+}
+
+// checkStatus checks that status.json lists the same modules, jobs and
+// task counts as the plan files.
+func (h *DoctorHandler) checkStatus(status *state.ExecutionStatus, loadErr error) DoctorCheck {
+	const name = "状态"
+
+	statusFile := h.cfg.GetStatusFile()
+	if loadErr != nil {
+		return problem(name, CheckFail, doing.NewDoingError(doing.ErrorCategoryState,
+			fmt.Sprintf("%s 无法解析", statusFile), loadErr).
+			WithContext("error_type", "state_corrupted").
+			WithContext("recovery_suggestion", "删除 "+statusFile+" 后重试"))
+	}
+	if status == nil {
+		return passed(name, fmt.Sprintf("%s 尚未生成，首次运行 morty doing 时会从计划生成", statusFile))
+	}
+
+	plans, err := state.ScanPlans(h.cfg.GetPlanDir())
+	if err != nil {
+		return passed(name, fmt.Sprintf("%s 可以解析，没有可对比的计划", statusFile))
+	}
+	if mismatches := compareStatusWithPlans(status, plans); len(mismatches) > 0 {
+		return problem(name, CheckFail, doing.NewDoingError(doing.ErrorCategoryState,
+			fmt.Sprintf("%s 与计划不一致 (%d 处)", statusFile, len(mismatches)), nil).
+			WithContext("error_type", "state_out_of_sync"), mismatches...)
+	}
+
+	jobs := 0
+	for _, m := range status.Modules {
+		jobs += len(m.Jobs)
+	}
+	return passed(name, fmt.Sprintf("%d 个模块, %d 个 Job 与计划一致", len(status.Modules), jobs))
+}
+
+// compareStatusWithPlans lists the modules, jobs and task counts that
+// differ between status.json and the plan files.
+func compareStatusWithPlans(status *state.ExecutionStatus, plans []state.PlanInfo) []string {
+	var mismatches []string
+	planned := make(map[string]bool, len(plans))
+	for _, p := range plans {
+		planned[p.Name] = true
+		module := status.GetModuleByName(p.Name)
+		if module == nil {
+			mismatches = append(mismatches, fmt.Sprintf("模块 %s 不在状态文件中", p.Name))
+			continue
+		}
+		plannedJobs := make(map[string]bool, len(p.Jobs))
+		for _, j := range p.Jobs {
+			plannedJobs[j.Name] = true
+			job := module.GetJobByName(j.Name)
+			if job == nil {
+				mismatches = append(mismatches, fmt.Sprintf("Job %s/%s 不在状态文件中", p.Name, j.Name))
+			} else if job.TasksTotal != len(j.Tasks) {
+				mismatches = append(mismatches, fmt.Sprintf("Job %s/%s 的 Task 数量不同: 计划 %d, 状态 %d",
+					p.Name, j.Name, len(j.Tasks), job.TasksTotal))
+			}
+		}
+		for _, job := range module.Jobs {
+			if !plannedJobs[job.Name] {
+				mismatches = append(mismatches, fmt.Sprintf("Job %s/%s 已不在计划中", p.Name, job.Name))
+			}
+		}
+	}
+	for _, m := range status.Modules {
+		if !planned[m.Name] {
+			mismatches = append(mismatches, fmt.Sprintf("模块 %s 已不在计划中", m.Name))
+		}
+	}
+	return mismatches
+}
+
+// checkLocks looks for state a crashed run leaves behind: a git index.lock
+// and jobs that stayed RUNNING for longer than ai_cli.max_timeout, which
+// morty never picks up again.
+func (h *DoctorHandler) checkLocks(status *state.ExecutionStatus) DoctorCheck {
+	const name = "锁"
+
+	if gitDir, err := h.gitManager.RunGitCommand(".", "rev-parse", "--git-dir"); err == nil {
+		lock := filepath.Join(gitDir, "index.lock")
+		if _, err := os.Stat(lock); err == nil {
+			return problem(name, CheckFail, doing.NewDoingError(doing.ErrorCategoryGit,
+				fmt.Sprintf("存在 %s", lock), nil).
+				WithContext("error_type", "git_locked"))
+		}
+	}
+
+	if status == nil {
+		return passed(name, "没有残留的锁")
+	}
+
+	maxTimeout := h.cfg.GetDuration("ai_cli.max_timeout", 30*time.Minute)
+	var staleJobs, stale, running []string
+	for _, m := range status.Modules {
+		for _, j := range m.Jobs {
+			if j.Status != state.StatusRunning {
+				continue
+			}
+			id := m.Name + "/" + j.Name
+			age := time.Since(j.UpdatedAt).Round(time.Second)
+			if age > maxTimeout {
+				staleJobs = append(staleJobs, id)
+				stale = append(stale, fmt.Sprintf("%s (%s 前更新)", id, age))
+			} else {
+				running = append(running, fmt.Sprintf("%s 正在执行 (%s 前更新)", id, age))
+			}
+		}
+	}
+	if len(stale) > 0 {
+		job := ""
+		if len(staleJobs) == 1 {
+			job = staleJobs[0]
+		}
+		return problem(name, CheckFail, doing.NewDoingError(doing.ErrorCategoryState,
+			fmt.Sprintf("%d 个 Job 的 RUNNING 状态超过 ai_cli.max_timeout (%s)", len(stale), maxTimeout), nil).
+			WithContext("error_type", "state_stale_running").
+			WithContext("job", job), stale...)
+	}
+	return passed(name, "没有残留的锁", running...)
+}
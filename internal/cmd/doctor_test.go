@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/morty/morty/internal/config"
+	"github.com/morty/morty/internal/state"
+)
+
+const doctorPlan = `# Plan: %s
+
+## 模块概述
+
+**模块职责**: 缓存层
+
+**对应 Research**: 无
+
+**现有实现参考**: 无
+
+**依赖模块**: 无
+
+**被依赖模块**: 无
+
+## 接口定义
+
+无
+
+## 数据模型
+
+无
+
+## Jobs
+
+---
+
+### Job 1: 内存缓存
+
+#### 目标
+
+实现 LRU 缓存
+
+#### 前置条件
+
+无
+
+#### Tasks
+
+- [ ] Task 1: 实现 Get
+- [ ] Task 2: 实现 Set
+
+#### 验证器
+
+- 命中率统计正确
+
+#### 调试日志
+
+无
+
+#### 完成状态
+
+⏳ 待开始
+
+---
+
+## 集成测试
+
+无
+`
+
+// fakeCLI is an AI CLI that prints a version and documents the flags morty
+// passes by default.
+const fakeCLI = `#!/bin/sh
+case "$1" in
+--version) echo "1.2.3 (Fake Code)" ;;
+--help) echo "  -p, --print  --permission-mode <mode>  --output-format <format>  --verbose  --debug  --dangerously-skip-permissions" ;;
+esac
+`
+
+// newDoctorTestHandler creates a project in a temp dir with a fake AI CLI,
+// prompt templates and a valid plan, and a handler for it.
+func newDoctorTestHandler(t *testing.T) (*DoctorHandler, *bytes.Buffer, string) {
+	t.Helper()
+	dir := t.TempDir()
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(origDir) })
+
+	cli := filepath.Join(dir, "fake-cli")
+	os.WriteFile(cli, []byte(fakeCLI), 0755)
+	t.Setenv(config.DefaultAICliEnvVar, cli)
+
+	os.MkdirAll("prompts", 0755)
+	for _, name := range []string{"research.md", "plan.md", "doing.md"} {
+		os.WriteFile(filepath.Join("prompts", name), []byte("# Prompt\n"), 0644)
+	}
+	os.MkdirAll(config.DefaultPlanDir, 0755)
+	for _, module := range []string{"cache", "e2e_test"} {
+		os.WriteFile(filepath.Join(config.DefaultPlanDir, module+".md"),
+			[]byte(fmt.Sprintf(doctorPlan, module)), 0644)
+	}
+
+	loader := config.NewLoader()
+	loader.LoadWithMerge(filepath.Join(dir, "user.json"))
+
+	var out bytes.Buffer
+	handler := NewDoctorHandler(loader, &mockLogger{})
+	handler.SetOutput(&out)
+	return handler, &out, dir
+}
+
+func checkByName(t *testing.T, result *DoctorResult, name string) DoctorCheck {
+	t.Helper()
+	for _, c := range result.Checks {
+		if c.Name == name {
+			return c
+		}
+	}
+	t.Fatalf("No %s check in %+v", name, result.Checks)
+	return DoctorCheck{}
+}
+
+func gitInit(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	if out, err := exec.Command("git", "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+}
+
+func TestDoctorHandler_Healthy(t *testing.T) {
+	handler, out, _ := newDoctorTestHandler(t)
+	gitInit(t)
+
+	result, err := handler.Execute(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Execute() error: %v\n%s", err, out)
+	}
+	for _, c := range result.Checks {
+		if c.Status != CheckOK {
+			t.Errorf("Expected %s to pass, got %+v", c.Name, c)
+		}
+	}
+	if got := checkByName(t, result, "AI CLI").Message; !strings.Contains(got, "1.2.3 (Fake Code)") || !strings.Contains(got, "$CLAUDE_CODE_CLI") {
+		t.Errorf("Unexpected AI CLI message: %s", got)
+	}
+	if !strings.Contains(out.String(), "检查完成: 7 项通过, 0 项警告, 0 项失败") {
+		t.Errorf("Unexpected report:\n%s", out)
+	}
+}
+
+func TestDoctorHandler_Problems(t *testing.T) {
+	handler, out, dir := newDoctorTestHandler(t)
+	t.Setenv(config.DefaultAICliEnvVar, filepath.Join(dir, "removed-cli"))
+	os.WriteFile(filepath.Join("prompts", "doing.md"), []byte("Work on {{job}}\n"), 0644)
+	os.Remove(filepath.Join(config.DefaultPlanDir, "e2e_test.md"))
+
+	status, err := state.GenerateStatus(config.DefaultPlanDir)
+	if err != nil {
+		t.Fatalf("GenerateStatus() error: %v", err)
+	}
+	status.Modules[0].Jobs[0].Status = state.StatusRunning
+	status.Modules[0].Jobs[0].UpdatedAt = time.Now().Add(-2 * time.Hour)
+	status.Modules = append(status.Modules, state.ModuleState{Name: "legacy"})
+	if err := state.NewManager(handler.cfg.GetStatusFile()).Save(status); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	result, err := handler.Execute(context.Background(), []string{"--json"})
+	if err == nil {
+		t.Fatal("Expected failed checks to return an error")
+	}
+
+	tests := []struct {
+		name   string
+		status CheckStatus
+		fix    string
+	}{
+		{"AI CLI", CheckFail, "morty config set ai_cli.command <path>"},
+		{"Git", CheckFail, "git init"}, // git.auto_commit is on by default
+		{"提示词", CheckWarn, "morty config explain prompts.dir"},
+		{"计划", CheckFail, "morty plan validate"},
+		{"状态", CheckFail, "rm .morty/status.json && morty doing"},
+		{"锁", CheckFail, "morty doing --restart --module cache --job 内存缓存"},
+	}
+	for _, tt := range tests {
+		c := checkByName(t, result, tt.name)
+		if c.Status != tt.status || c.Fix != tt.fix {
+			t.Errorf("%s: got %s with fix %q, want %s with fix %q (%s)", tt.name, c.Status, c.Fix, tt.status, tt.fix, c.Message)
+		}
+	}
+	if c := checkByName(t, result, "状态"); len(c.Details) != 1 || c.Details[0] != "模块 legacy 已不在计划中" {
+		t.Errorf("Unexpected status mismatches: %q", c.Details)
+	}
+
+	var decoded DoctorResult
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil || len(decoded.Checks) != 7 {
+		t.Errorf("Expected a JSON report, got %v:\n%s", err, out)
+	}
+}
+
+func TestParseDoctorOptions(t *testing.T) {
+	if opts, err := parseDoctorOptions([]string{"--json"}); err != nil || !opts.JSON {
+		t.Errorf("parseDoctorOptions(--json) = %+v, %v", opts, err)
+	}
+	if _, err := parseDoctorOptions([]string{"--fix"}); err == nil {
+		t.Error("Expected an error for an unknown option")
+	}
+}
@@ -3,6 +3,7 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -16,8 +17,10 @@ import (
 	"github.com/morty/morty/internal/executor"
 	"github.com/morty/morty/internal/git"
 	"github.com/morty/morty/internal/logging"
+	"github.com/morty/morty/internal/metrics"
 	"github.com/morty/morty/internal/parser/plan"
 	"github.com/morty/morty/internal/state"
+	"github.com/morty/morty/internal/tracing"
 )
 
 // DoingResult represents the result of a doing operation.
@@ -29,6 +32,21 @@ type DoingResult struct {
 	ExitCode   int
 	Duration   time.Duration
 	Restart    bool
+	// Stopped is true when a signal stopped the run after a job
+	Stopped bool
+	// Outcomes lists every job run or blocked in continue-on-error mode
+	Outcomes []JobOutcome
+}
+
+// JobOutcome records what happened to a job during a continue-on-error run.
+type JobOutcome struct {
+	Module string
+	Job    string
+	Status state.Status
+	// Reason is the failure reason, or for blocked jobs the root failure
+	Reason string
+	// BlockedBy is the failed job ("module/job") a blocked job waits on
+	BlockedBy string
 }
 
 // DoingHandler handles the doing command.
@@ -40,6 +58,13 @@ type DoingHandler struct {
 	stateManager *state.Manager
 	executor     executor.Engine
 	gitManager   *git.Manager
+
+	// metrics records the current run (nil when metrics are disabled)
+	metrics *metrics.Recorder
+	// executionLogger records the AI CLI executions of the current run
+	executionLogger *callcli.ExecutionLogger
+	// redactionCanary is the fake secret of the redaction test mode
+	redactionCanary string
 }
 
 // NewDoingHandler creates a new DoingHandler instance.
@@ -48,14 +73,8 @@ func NewDoingHandler(cfg config.Manager, logger logging.Logger) *DoingHandler {
 	var paths *config.Paths
 	if loader, ok := cfg.(*config.Loader); ok {
 		paths = config.NewPathsWithLoader(loader)
-		if os.Getenv("MORTY_DEBUG") != "" {
-			fmt.Fprintf(os.Stderr, "DEBUG: NewDoingHandler using Loader\n")
-		}
 	} else {
 		paths = config.NewPaths()
-		if os.Getenv("MORTY_DEBUG") != "" {
-			fmt.Fprintf(os.Stderr, "DEBUG: NewDoingHandler using NewPaths (cfg type: %T)\n", cfg)
-		}
 	}
 
 	// Set workDir from config if available
@@ -73,7 +92,49 @@ func NewDoingHandler(cfg config.Manager, logger logging.Logger) *DoingHandler {
 
 // Execute executes the doing command.
 // It validates plan directory exists and prepares for job execution.
+// When tracing is enabled the run is recorded as a "doing.run" span; when
+// metrics are enabled they are served and written as a textfile. In the
+// redaction test mode the run fails when a secret leaks into a log file.
 func (h *DoingHandler) Execute(ctx context.Context, args []string) (*DoingResult, error) {
+	tracer, err := newTracer(h.cfg)
+	if err != nil {
+		h.logger.Warn("Tracing disabled", logging.String("error", err.Error()))
+	}
+	ctx = tracing.ContextWithTracer(ctx, tracer)
+	ctx, span := tracing.Start(ctx, "doing.run", logging.Any("morty.args", args))
+	stopMetrics := h.startMetrics()
+	checkRedaction := h.startRedactionTest()
+
+	result, err := h.execute(ctx, args)
+	stopMetrics(err == nil)
+	h.closeExecutionLog()
+	if leakErr := checkRedaction(); leakErr != nil {
+		h.logger.Error("Redaction test failed", logging.String("error", leakErr.Error()))
+		if err == nil {
+			err = leakErr
+			if result != nil {
+				result.Err = err
+				result.ExitCode = 1
+			}
+		}
+	}
+
+	if result != nil {
+		span.SetAttributes(
+			logging.String("morty.module", result.ModuleName),
+			logging.String("morty.job", result.JobName),
+			logging.Int("morty.exit_code", result.ExitCode),
+		)
+	}
+	span.End(err)
+	if flushErr := tracer.Shutdown(context.Background()); flushErr != nil {
+		h.logger.Warn("Failed to export trace spans", logging.String("error", flushErr.Error()))
+	}
+	return result, err
+}
+
+// execute runs the doing command within the run span.
+func (h *DoingHandler) execute(ctx context.Context, args []string) (*DoingResult, error) {
 	logger := h.logger.WithContext(ctx)
 	startTime := time.Now()
 
@@ -137,6 +198,10 @@ func (h *DoingHandler) Execute(ctx context.Context, args []string) (*DoingResult
 		)
 	}
 
+	if err := h.recordProfile(); err != nil {
+		logger.Warn("Failed to record profile", logging.String("error", err.Error()))
+	}
+
 	// Step 3: Select next job (simple array traversal, topologically sorted)
 	moduleIndex, jobIndex, targetModule, targetJob, err := h.selectNextJob()
 	if err != nil {
@@ -197,11 +262,17 @@ func (h *DoingHandler) Execute(ctx context.Context, args []string) (*DoingResult
 	// If a specific job is requested, execute only that job
 	// Otherwise, execute all pending jobs in sequence
 	continuousMode := (moduleName == "" && jobName == "")
+	continueOnError := continuousMode && h.cfg != nil && h.cfg.GetBool("execution.continue_on_error")
 
 	jobsCompleted := 0
+	jobsFailed := 0
 	currentModule := targetModule
 	currentJob := targetJob
 
+	// Ctrl-C and morty pause stop, pause or interrupt the run
+	control := h.startRunControl()
+	defer control.close()
+
 	for {
 		logger.Info("Executing job",
 			logging.String("module", currentModule),
@@ -210,8 +281,41 @@ func (h *DoingHandler) Execute(ctx context.Context, args []string) (*DoingResult
 		)
 
 		// Execute the current job
-		execResult, err := h.executeJob(ctx, currentModule, currentJob)
-		if err != nil {
+		jobCtx, endJob := control.beginJob(ctx)
+		execResult, err := h.executeJob(jobCtx, currentModule, currentJob)
+		endJob()
+		var interrupted *executor.InterruptedError
+		if errors.As(err, &interrupted) {
+			if recordErr := h.recordInterrupt(currentModule, currentJob, interrupted); recordErr != nil {
+				logger.Error("Failed to record interrupted job", logging.String("error", recordErr.Error()))
+			}
+			// After morty resume the interrupted job runs again
+			if control.proceed(ctx) {
+				continue
+			}
+			result.ModuleName = currentModule
+			result.JobName = currentJob
+			result.Err = fmt.Errorf("Job %s/%s 已中断，再次运行 morty doing 从中断处继续", currentModule, currentJob)
+			result.ExitCode = 1
+			result.Duration = time.Since(startTime)
+			return result, result.Err
+		} else if err != nil && continueOnError {
+			logger.Error("Job execution failed, continuing with independent jobs",
+				logging.String("module", currentModule),
+				logging.String("job", currentJob),
+				logging.String("error", err.Error()),
+			)
+			outcomes, blockErr := h.failAndBlockDependents(currentModule, currentJob, err)
+			result.Outcomes = append(result.Outcomes, outcomes...)
+			if blockErr != nil {
+				result.Err = fmt.Errorf("标记被阻塞的 Job 失败: %w", blockErr)
+				result.ExitCode = 1
+				result.Duration = time.Since(startTime)
+				logger.Error("Failed to propagate BLOCKED status", logging.String("error", blockErr.Error()))
+				return result, result.Err
+			}
+			jobsFailed++
+		} else if err != nil {
 			result.Err = err
 			result.ExitCode = 1
 			result.Duration = time.Since(startTime)
@@ -221,28 +325,45 @@ func (h *DoingHandler) Execute(ctx context.Context, args []string) (*DoingResult
 				logging.String("error", err.Error()),
 			)
 			return result, result.Err
-		}
-
-		jobsCompleted++
-		result.ModuleName = currentModule
-		result.JobName = currentJob
+		} else {
+			jobsCompleted++
+			result.ModuleName = currentModule
+			result.JobName = currentJob
+			if continueOnError {
+				result.Outcomes = append(result.Outcomes, JobOutcome{
+					Module: currentModule,
+					Job:    currentJob,
+					Status: state.StatusCompleted,
+				})
+			}
 
-		logger.Success("Job completed",
-			logging.String("module", currentModule),
-			logging.String("job", currentJob),
-			logging.String("exec_status", string(execResult.Status)),
-		)
+			logger.Success("Job completed",
+				logging.String("module", currentModule),
+				logging.String("job", currentJob),
+				logging.String("exec_status", string(execResult.Status)),
+			)
+		}
 
 		// If not in continuous mode, stop after first job
 		if !continuousMode {
 			break
 		}
 
+		// Stop when asked to, or wait while paused
+		if !control.proceed(ctx) {
+			logger.Info("Stopped after job on request",
+				logging.String("module", currentModule),
+				logging.String("job", currentJob),
+			)
+			result.Stopped = true
+			break
+		}
+
 		// In continuous mode, find next pending job
 		nextModule, nextJob, err := h.selectTargetJob("", "")
 		if err != nil {
 			// No more pending jobs - this is a success condition
-			if strings.Contains(err.Error(), "没有待执行的 Job") {
+			if strings.Contains(err.Error(), "没有待执行的 Job") || strings.Contains(err.Error(), "no pending jobs") {
 				logger.Info("All jobs completed",
 					logging.Int("total_jobs_completed", jobsCompleted),
 				)
@@ -269,6 +390,19 @@ func (h *DoingHandler) Execute(ctx context.Context, args []string) (*DoingResult
 	result.Duration = time.Since(startTime)
 	result.ExitCode = 0
 
+	if jobsFailed > 0 {
+		blocked := len(result.Outcomes) - jobsCompleted - jobsFailed
+		result.Err = fmt.Errorf("%d 个 Job 失败, %d 个 Job 被阻塞", jobsFailed, blocked)
+		result.ExitCode = 1
+		logger.Error("Doing command completed with failures",
+			logging.Int("jobs_completed", jobsCompleted),
+			logging.Int("jobs_failed", jobsFailed),
+			logging.Int("jobs_blocked", blocked),
+			logging.Any("duration", result.Duration),
+		)
+		return result, result.Err
+	}
+
 	logger.Info("Doing command completed",
 		logging.Int("jobs_completed", jobsCompleted),
 		logging.Int("exit_code", result.ExitCode),
@@ -376,6 +510,14 @@ func (h *DoingHandler) getPlanDir() string {
 	return h.paths.GetPlanDir()
 }
 
+// getResearchDir returns the research directory, preferring config if available.
+func (h *DoingHandler) getResearchDir() string {
+	if h.cfg != nil {
+		return h.cfg.GetResearchDir()
+	}
+	return h.paths.GetResearchDir()
+}
+
 // getWorkDir returns the work directory, preferring config if available.
 func (h *DoingHandler) getWorkDir() string {
 	if h.cfg != nil {
@@ -415,6 +557,16 @@ func (h *DoingHandler) PrintDoingSummary(result *DoingResult) {
 	}
 	fmt.Printf("📁 Plan Directory: %s\n", result.PlanDir)
 
+	if len(result.Outcomes) > 0 {
+		fmt.Println()
+		fmt.Print(formatOutcomeReport(result.Outcomes))
+	}
+
+	if result.Stopped {
+		fmt.Println()
+		fmt.Println("⏹️  已按请求在 Job 完成后停止，再次运行 morty doing 继续")
+	}
+
 	if result.Err != nil {
 		fmt.Println()
 		fmt.Println("❌ Error:")
@@ -494,6 +646,7 @@ func (h *DoingHandler) handleRestart(moduleName, jobName string) error {
 				job.LoopCount = 0
 				job.RetryCount = 0
 				job.TasksCompleted = 0
+				job.Sessions = nil
 				job.UpdatedAt = now
 			}
 			module.Status = state.StatusPending
@@ -503,6 +656,7 @@ func (h *DoingHandler) handleRestart(moduleName, jobName string) error {
 		stateData.Global.CurrentModuleIndex = 0
 		stateData.Global.CurrentJobIndex = 0
 		stateData.Global.LastUpdate = now
+		h.releaseBlockedJobs(stateData, now)
 		return h.stateManager.Save(stateData)
 	}
 
@@ -528,6 +682,7 @@ func (h *DoingHandler) handleRestart(moduleName, jobName string) error {
 			job.LoopCount = 0
 			job.RetryCount = 0
 			job.TasksCompleted = 0
+			job.Sessions = nil
 			job.UpdatedAt = now
 		}
 		module.Status = state.StatusPending
@@ -542,6 +697,7 @@ func (h *DoingHandler) handleRestart(moduleName, jobName string) error {
 				job.LoopCount = 0
 				job.RetryCount = 0
 				job.TasksCompleted = 0
+				job.Sessions = nil
 				job.UpdatedAt = now
 				jobFound = true
 				break
@@ -558,6 +714,7 @@ func (h *DoingHandler) handleRestart(moduleName, jobName string) error {
 		module.UpdatedAt = now
 	}
 
+	h.releaseBlockedJobs(stateData, now)
 	stateData.Global.LastUpdate = now
 	return h.stateManager.Save(stateData)
 }
@@ -584,7 +741,7 @@ func (h *DoingHandler) calculateModuleStatus(module *state.ModuleState) state.St
 		case state.StatusBlocked:
 			hasBlocked = true
 			allCompleted = false
-		case state.StatusPending:
+		case state.StatusPending, state.StatusInterrupted:
 			allCompleted = false
 		case state.StatusCompleted:
 			// Continue checking
@@ -633,7 +790,7 @@ func (h *DoingHandler) selectTargetJob(moduleName, jobName string) (string, stri
 	return module, job, err
 }
 
-// findExecutableJob finds the first PENDING job in a module that has all prerequisites met.
+// findExecutableJob finds the first PENDING or INTERRUPTED job in a module that has all prerequisites met.
 // Returns empty string if no executable job is found.
 func (h *DoingHandler) findExecutableJob(moduleName string, module *state.ModuleState) string {
 	logger := h.logger
@@ -646,32 +803,23 @@ func (h *DoingHandler) findExecutableJob(moduleName string, module *state.Module
 	var pendingJobs []jobWithIndex
 
 	// Load plan to get job indices for proper ordering
-	// Use module.PlanFile if available, otherwise fall back to moduleName+".md"
-	planFileName := module.PlanFile
-	if planFileName == "" {
-		planFileName = moduleName + ".md"
+	// Use module.PlanFile if available, otherwise look for a plan in any supported format
+	planFile := filepath.Join(h.getPlanDir(), module.PlanFile)
+	if module.PlanFile == "" {
+		planFile, _ = plan.FindPlanFile(h.getPlanDir(), moduleName)
 	}
-	planFile := filepath.Join(h.getPlanDir(), planFileName)
-	content, err := os.ReadFile(planFile)
 	jobIndexMap := make(map[string]int)
 
-	if err == nil {
-		// Parse plan to extract job indices
-		lines := strings.Split(string(content), "\n")
-		jobPattern := regexp.MustCompile(`(?i)^###\s*job\s*(\d+)[:：]\s*(.+)$`)
-		for _, line := range lines {
-			if matches := jobPattern.FindStringSubmatch(line); matches != nil && len(matches) >= 3 {
-				var index int
-				fmt.Sscanf(matches[1], "%d", &index)
-				jobName := strings.TrimSpace(matches[2])
-				jobIndexMap[jobName] = index
-			}
+	if planData, err := plan.ParsePlanFile(planFile); err == nil {
+		// Structured plans may leave the index out, their order is the job order
+		for i, planJob := range planData.Jobs {
+			jobIndexMap[planJob.Name] = i + 1
 		}
 	}
 
 	// Collect PENDING jobs
 	for _, job := range module.Jobs {
-		if job.Status == state.StatusPending {
+		if job.Status == state.StatusPending || job.Status == state.StatusInterrupted {
 			index := jobIndexMap[job.Name]
 			if index == 0 {
 				// If not found in plan, use a large number to put it at the end
@@ -681,18 +829,13 @@ func (h *DoingHandler) findExecutableJob(moduleName string, module *state.Module
 		}
 	}
 
-	if os.Getenv("MORTY_DEBUG") != "" {
-		fmt.Fprintf(os.Stderr, "DEBUG: Finding executable job in module '%s', pending count: %d\n", moduleName, len(pendingJobs))
-		fmt.Fprintf(os.Stderr, "DEBUG: Pending jobs: %v\n", pendingJobs)
-	}
-
 	logger.Debug("Finding executable job",
 		logging.String("module", moduleName),
 		logging.Int("pending_count", len(pendingJobs)),
 	)
 
-	// Sort by job index (topological order)
-	sort.Slice(pendingJobs, func(i, j int) bool {
+	// Sort by job index (topological order), keeping the status order for ties
+	sort.SliceStable(pendingJobs, func(i, j int) bool {
 		return pendingJobs[i].index < pendingJobs[j].index
 	})
 
@@ -702,9 +845,6 @@ func (h *DoingHandler) findExecutableJob(moduleName string, module *state.Module
 		// Check if this job's prerequisites are met
 		err := h.checkPrerequisites(moduleName, jobName)
 		if err == nil {
-			if os.Getenv("MORTY_DEBUG") != "" {
-				fmt.Fprintf(os.Stderr, "DEBUG: Found executable job: %s/%s (index: %d)\n", moduleName, jobName, jobInfo.index)
-			}
 			logger.Info("Found executable job",
 				logging.String("module", moduleName),
 				logging.String("job", jobName),
@@ -712,9 +852,6 @@ func (h *DoingHandler) findExecutableJob(moduleName string, module *state.Module
 			)
 			return jobName
 		} else {
-			if os.Getenv("MORTY_DEBUG") != "" {
-				fmt.Fprintf(os.Stderr, "DEBUG: Job '%s/%s' (index: %d) has unmet prerequisites: %v\n", moduleName, jobName, jobInfo.index, err)
-			}
 			logger.Debug("Job has unmet prerequisites",
 				logging.String("module", moduleName),
 				logging.String("job", jobName),
@@ -724,10 +861,6 @@ func (h *DoingHandler) findExecutableJob(moduleName string, module *state.Module
 		}
 	}
 
-	if os.Getenv("MORTY_DEBUG") != "" {
-		fmt.Fprintf(os.Stderr, "DEBUG: No executable job found in module '%s'\n", moduleName)
-	}
-
 	logger.Debug("No executable job found in module",
 		logging.String("module", moduleName),
 	)
@@ -742,8 +875,11 @@ func (h *DoingHandler) findExecutableJob(moduleName string, module *state.Module
 func (h *DoingHandler) checkPrerequisites(moduleName, jobName string) error {
 	// Load the plan file for this module
 	// First try the exact module name
-	planFile := filepath.Join(h.getPlanDir(), moduleName+".md")
-	content, err := os.ReadFile(planFile)
+	var content []byte
+	planFile, err := plan.FindPlanFile(h.getPlanDir(), moduleName)
+	if err == nil {
+		content, err = os.ReadFile(planFile)
+	}
 
 	// If not found, search for any plan file containing this module
 	if err != nil && os.IsNotExist(err) {
@@ -758,7 +894,7 @@ func (h *DoingHandler) checkPrerequisites(moduleName, jobName string) error {
 	}
 
 	// Parse the plan file
-	planData, err := plan.ParsePlan(string(content))
+	planData, err := plan.ParsePlanAs(string(content), plan.FormatFromPath(planFile))
 	if err != nil {
 		return fmt.Errorf("解析计划文件失败: %w", err)
 	}
@@ -824,9 +960,9 @@ func (h *DoingHandler) checkPrerequisites(moduleName, jobName string) error {
 			}
 
 			if prereqJobName == "" {
-				if os.Getenv("MORTY_DEBUG") != "" {
-					fmt.Fprintf(os.Stderr, "DEBUG: Job index %d not found in plan\n", jobIndex)
-				}
+				h.logger.Debug("Prerequisite job index not found in plan",
+					logging.Int("job_index", jobIndex),
+				)
 				continue
 			}
 
@@ -840,9 +976,9 @@ func (h *DoingHandler) checkPrerequisites(moduleName, jobName string) error {
 			}
 
 			if jobState == nil {
-				if os.Getenv("MORTY_DEBUG") != "" {
-					fmt.Fprintf(os.Stderr, "DEBUG: Job '%s' not found in state\n", prereqJobName)
-				}
+				h.logger.Debug("Prerequisite job not found in state",
+					logging.String("job", prereqJobName),
+				)
 				continue
 			}
 
@@ -893,33 +1029,34 @@ func (h *DoingHandler) checkPrerequisites(moduleName, jobName string) error {
 				}
 
 				if otherModule == nil {
-					if os.Getenv("MORTY_DEBUG") != "" {
-						fmt.Fprintf(os.Stderr, "DEBUG: Prerequisite module '%s' not found in state\n", prereqModule)
-					}
+					h.logger.Debug("Prerequisite module not found in state",
+						logging.String("module", prereqModule),
+					)
 					unmetPrereqs = append(unmetPrereqs, fmt.Sprintf("%s:job_%d (模块不存在)", prereqModule, jobIndex))
 					continue
 				}
 
 				// Use otherModule.PlanFile if available
-				otherPlanFileName := otherModule.PlanFile
-				if otherPlanFileName == "" {
-					otherPlanFileName = prereqModule + ".md"
+				otherPlanFile := filepath.Join(h.getPlanDir(), otherModule.PlanFile)
+				if otherModule.PlanFile == "" {
+					otherPlanFile, _ = plan.FindPlanFile(h.getPlanDir(), prereqModule)
 				}
-				otherPlanFile := filepath.Join(h.getPlanDir(), otherPlanFileName)
 				otherContent, err := os.ReadFile(otherPlanFile)
 				if err != nil {
-					if os.Getenv("MORTY_DEBUG") != "" {
-						fmt.Fprintf(os.Stderr, "DEBUG: Failed to read plan file for module '%s': %v\n", prereqModule, err)
-					}
+					h.logger.Debug("Failed to read prerequisite plan file",
+						logging.String("module", prereqModule),
+						logging.String("error", err.Error()),
+					)
 					unmetPrereqs = append(unmetPrereqs, fmt.Sprintf("%s:job_%d (无法读取计划文件)", prereqModule, jobIndex))
 					continue
 				}
 
-				prereqPlanData, err = plan.ParsePlan(string(otherContent))
+				prereqPlanData, err = plan.ParsePlanAs(string(otherContent), plan.FormatFromPath(otherPlanFile))
 				if err != nil {
-					if os.Getenv("MORTY_DEBUG") != "" {
-						fmt.Fprintf(os.Stderr, "DEBUG: Failed to parse plan file for module '%s': %v\n", prereqModule, err)
-					}
+					h.logger.Debug("Failed to parse prerequisite plan file",
+						logging.String("module", prereqModule),
+						logging.String("error", err.Error()),
+					)
 					unmetPrereqs = append(unmetPrereqs, fmt.Sprintf("%s:job_%d (无法解析计划文件)", prereqModule, jobIndex))
 					continue
 				}
@@ -935,9 +1072,10 @@ func (h *DoingHandler) checkPrerequisites(moduleName, jobName string) error {
 
 			if actualJobName == prereqJob {
 				// Job index not found
-				if os.Getenv("MORTY_DEBUG") != "" {
-					fmt.Fprintf(os.Stderr, "DEBUG: Job index %d not found in module '%s'\n", jobIndex, prereqModule)
-				}
+				h.logger.Debug("Prerequisite job index not found in module",
+					logging.String("module", prereqModule),
+					logging.Int("job_index", jobIndex),
+				)
 				unmetPrereqs = append(unmetPrereqs, fmt.Sprintf("%s:job_%d (Job索引不存在)", prereqModule, jobIndex))
 				continue
 			}
@@ -976,9 +1114,10 @@ func (h *DoingHandler) checkPrerequisites(moduleName, jobName string) error {
 		// If job doesn't exist, it's likely a descriptive prerequisite (not a job reference)
 		// Skip it and assume it will be verified manually
 		if jobState == nil {
-			if os.Getenv("MORTY_DEBUG") != "" {
-				fmt.Fprintf(os.Stderr, "DEBUG: Skipping non-job prerequisite: '%s' (resolved to '%s')\n", prereq, actualJobName)
-			}
+			h.logger.Debug("Skipping non-job prerequisite",
+				logging.String("prerequisite", prereq),
+				logging.String("resolved", actualJobName),
+			)
 			continue
 		}
 
@@ -1010,6 +1149,36 @@ func (h *DoingHandler) GetStateManager() *state.Manager {
 	return h.stateManager
 }
 
+// buildCommitScanner creates the pre-commit scanner from git.scan settings.
+// It returns nil when scanning is disabled.
+func (h *DoingHandler) buildCommitScanner() (*git.Scanner, error) {
+	scanCfg := config.DefaultConfig().Git.Scan
+	if loader, ok := h.cfg.(*config.Loader); ok && loader.Config() != nil {
+		scanCfg = loader.Config().Git.Scan
+	}
+
+	if !scanCfg.Enabled {
+		return nil, nil
+	}
+
+	var rules []git.ScanRule
+	for _, r := range scanCfg.Rules {
+		rule, err := git.CompileScanRule(r.Name, r.Pattern)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return git.NewScanner(git.ScanConfig{
+		Action:      git.ScanAction(scanCfg.Action),
+		MaxFileSize: scanCfg.MaxFileSizeBytes(0),
+		BlockBinary: scanCfg.BlockBinary,
+		Rules:       rules,
+		AllowPaths:  scanCfg.AllowPaths,
+	}), nil
+}
+
 // initializeExecutor initializes the executor with necessary dependencies.
 // Task 1: Initialize Executor
 func (h *DoingHandler) initializeExecutor() error {
@@ -1017,10 +1186,16 @@ func (h *DoingHandler) initializeExecutor() error {
 		return fmt.Errorf("state manager not initialized")
 	}
 
+	// Keep an executor injected with SetExecutor
+	if h.executor != nil {
+		return nil
+	}
+
 	// Initialize git manager if not already set
 	if h.gitManager == nil {
 		h.gitManager = git.NewManager()
 	}
+	h.gitManager.SetTrailers(h.commitTrailers()...)
 
 	// Create executor configuration
 	execConfig := &executor.Config{
@@ -1030,6 +1205,25 @@ func (h *DoingHandler) initializeExecutor() error {
 		WorkingDir:   h.getWorkDir(),
 		PromptsDir:   h.paths.GetPromptsDir(),
 		PlanDir:      h.getPlanDir(),
+		Language:     promptLanguage(h.cfg),
+		Prompts:      newPromptEngine(h.cfg, h.paths),
+		ResearchDir:  h.getResearchDir(),
+		ResearchTopK: config.DefaultResearchTopK,
+		DigestBudget: config.DefaultExecutionDigestBudget,
+	}
+
+	scanner, err := h.buildCommitScanner()
+	if err != nil {
+		return fmt.Errorf("初始化提交扫描失败: %w", err)
+	}
+	execConfig.CommitScanner = scanner
+	execConfig.Metrics = h.metrics
+	execConfig.ExecutionLogger = h.openExecutionLog()
+	execConfig.RedactionCanary = h.redactionCanary
+	if h.cfg != nil {
+		execConfig.ResearchTopK = h.cfg.GetInt("research.top_k", config.DefaultResearchTopK)
+		execConfig.DigestBudget = h.cfg.GetInt("execution.digest_budget", config.DefaultExecutionDigestBudget)
+		execConfig.FreshSession = h.cfg.GetBool("execution.fresh_session", config.DefaultExecutionFreshSession)
 	}
 
 	// Create the executor engine with CLI caller
@@ -1186,6 +1380,8 @@ func (h *DoingHandler) determineNextAction(status string) string {
 		return "等待依赖项完成后重试"
 	case state.StatusRunning:
 		return "Job 正在执行中，请等待完成"
+	case state.StatusInterrupted:
+		return "运行 `morty doing` 从中断处继续"
 	default:
 		return "运行 `morty doing` 开始执行"
 	}
@@ -1241,6 +1437,8 @@ func (h *DoingHandler) getStatusIcon(status string) string {
 		return "🚫"
 	case state.StatusRunning:
 		return "🔄"
+	case state.StatusInterrupted:
+		return "⏸️"
 	default:
 		return "⏳"
 	}
@@ -1339,7 +1537,7 @@ func (h *DoingHandler) getStatusColor(status string) string {
 		return ColorGreen
 	case state.StatusFailed:
 		return ColorRed
-	case state.StatusBlocked:
+	case state.StatusBlocked, state.StatusInterrupted:
 		return ColorYellow
 	case state.StatusRunning:
 		return ColorBlue
@@ -1354,7 +1552,7 @@ func (h *DoingHandler) getStatusColor(status string) string {
 // The commit message format is: "morty: [module]/[job] - [STATUS]"
 func (h *DoingHandler) createGitCommit(summary *CommitSummary) (string, error) {
 	// Task 2: Generate commit message
-	commitMsg := h.generateCommitMessage(summary)
+	commitMsg := git.AppendTrailers(h.generateCommitMessage(summary), h.commitTrailers())
 
 	// Initialize git manager if needed
 	if h.gitManager == nil {
@@ -1439,7 +1637,10 @@ func (h *DoingHandler) generateCommitMessage(summary *CommitSummary) string {
 // Task 2: Use Markdown Parser to parse Plan file
 // It returns the parsed Plan struct or an error if the file doesn't exist or can't be parsed.
 func (h *DoingHandler) loadPlan(module string) (*plan.Plan, error) {
-	planFile := filepath.Join(h.getPlanDir(), module+".md")
+	planFile, err := plan.FindPlanFile(h.getPlanDir(), module)
+	if err != nil {
+		return nil, fmt.Errorf("计划文件不存在: %s", filepath.Join(h.getPlanDir(), module+".md"))
+	}
 
 	content, err := os.ReadFile(planFile)
 	if err != nil {
@@ -1449,7 +1650,7 @@ func (h *DoingHandler) loadPlan(module string) (*plan.Plan, error) {
 		return nil, fmt.Errorf("读取计划文件失败: %w", err)
 	}
 
-	planData, err := plan.ParsePlan(string(content))
+	planData, err := plan.ParsePlanAs(string(content), plan.FormatFromPath(planFile))
 	if err != nil {
 		return nil, fmt.Errorf("解析计划文件失败: %w", err)
 	}
@@ -1496,7 +1697,7 @@ func (h *DoingHandler) findPlanFileForModule(moduleName string) (string, []byte,
 		}
 
 		name := entry.Name()
-		if !strings.HasSuffix(name, ".md") || strings.HasPrefix(name, "README") {
+		if !plan.IsPlanFile(name) {
 			continue
 		}
 
@@ -1508,7 +1709,7 @@ func (h *DoingHandler) findPlanFileForModule(moduleName string) (string, []byte,
 		}
 
 		// Parse to check if it contains the module
-		parsedPlan, err := plan.ParsePlan(string(content))
+		parsedPlan, err := plan.ParsePlanAs(string(content), plan.FormatFromPath(name))
 		if err != nil {
 			continue
 		}
@@ -1535,63 +1736,40 @@ func (h *DoingHandler) extractDepsFromReadme(planDir string) map[string][]string
 		return result
 	}
 
-	text := string(content)
-	lines := strings.Split(text, "\n")
-
-	// Find the module list table
-	// Format: | 模块名称 | 文件 | Jobs 数量 | 依赖模块 | 状态 |
-	inTable := false
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
+	// Find the module list table, e.g. | 模块名称 | 文件 | Jobs 数量 | 依赖模块 | 状态 |
+	table := plan.FindModuleTable(string(content))
+	if table == nil {
+		return result
+	}
 
-		// Check if this is the table header
-		if strings.Contains(line, "模块名称") && strings.Contains(line, "依赖模块") {
-			inTable = true
+	for _, row := range table.Rows {
+		moduleName := row.Cell(table.ModuleColumn)
+		depsStr := row.Cell(table.DepsColumn)
+		if moduleName == "" {
 			continue
 		}
 
-		// Skip separator line
-		if inTable && strings.HasPrefix(line, "|---") {
+		// Parse dependencies
+		if depsStr == "" || plan.IsNone(depsStr) {
+			continue
+		}
+		if depsStr == "所有模块" || strings.EqualFold(depsStr, "all modules") {
+			// Special case: depends on all other modules
+			// We'll handle this after collecting all module names
+			result[moduleName] = []string{"__ALL__"}
 			continue
 		}
 
-		// Parse table row
-		if inTable && strings.HasPrefix(line, "|") {
-			parts := strings.Split(line, "|")
-			if len(parts) >= 5 {
-				moduleName := strings.TrimSpace(parts[1])
-				depsStr := strings.TrimSpace(parts[4])
-
-				if moduleName == "" || moduleName == "模块名称" {
-					continue
-				}
-
-				// Parse dependencies
-				var deps []string
-				if depsStr != "" && depsStr != "无" {
-					if depsStr == "所有模块" {
-						// Special case: depends on all other modules
-						// We'll handle this after collecting all module names
-						result[moduleName] = []string{"__ALL__"}
-					} else {
-						// Split by comma
-						for _, dep := range strings.Split(depsStr, ",") {
-							dep = strings.TrimSpace(dep)
-							if dep != "" && dep != "无" {
-								deps = append(deps, dep)
-							}
-						}
-						if len(deps) > 0 {
-							result[moduleName] = deps
-						}
-					}
-				}
+		// Split by comma
+		var deps []string
+		for _, dep := range strings.Split(depsStr, ",") {
+			dep = strings.TrimSpace(dep)
+			if dep != "" && !plan.IsNone(dep) {
+				deps = append(deps, dep)
 			}
 		}
-
-		// Stop if we hit another section
-		if inTable && line != "" && !strings.HasPrefix(line, "|") {
-			break
+		if len(deps) > 0 {
+			result[moduleName] = deps
 		}
 	}
 
@@ -1627,8 +1805,11 @@ func (h *DoingHandler) sortModulesByTopology(stateData *state.ExecutionStatus) (
 				readmeModName == strings.Trim(actualModName, "[]") {
 				normalizedReadmeDeps[actualModName] = deps
 				matched = true
-				if os.Getenv("MORTY_DEBUG") != "" && readmeModName != actualModName {
-					fmt.Fprintf(os.Stderr, "DEBUG: Normalized module name '%s' -> '%s'\n", readmeModName, actualModName)
+				if readmeModName != actualModName {
+					h.logger.Debug("Normalized README module name",
+						logging.String("readme_name", readmeModName),
+						logging.String("module", actualModName),
+					)
 				}
 				break
 			}
@@ -1651,9 +1832,10 @@ func (h *DoingHandler) sortModulesByTopology(stateData *state.ExecutionStatus) (
 				}
 			}
 			readmeDeps[moduleName] = expanded
-			if os.Getenv("MORTY_DEBUG") != "" {
-				fmt.Fprintf(os.Stderr, "DEBUG: Expanded '__ALL__' for '%s' to: %v\n", moduleName, expanded)
-			}
+			h.logger.Debug("Expanded __ALL__ dependencies",
+				logging.String("module", moduleName),
+				logging.Any("dependencies", expanded),
+			)
 		}
 	}
 
@@ -1667,14 +1849,14 @@ func (h *DoingHandler) sortModulesByTopology(stateData *state.ExecutionStatus) (
 		// Check if README.md has dependency info for this module
 		if deps, ok := readmeDeps[moduleName]; ok && len(deps) > 0 {
 			moduleDeps[moduleName] = deps
-			if os.Getenv("MORTY_DEBUG") != "" {
-				fmt.Fprintf(os.Stderr, "DEBUG: Module '%s' dependencies from README: %v\n", moduleName, deps)
-			}
+			h.logger.Debug("Module dependencies from README",
+				logging.String("module", moduleName),
+				logging.Any("dependencies", deps),
+			)
 			continue
 		}
 
-		planFile := filepath.Join(planDir, moduleName+".md")
-		content, err := os.ReadFile(planFile)
+		planFile, err := plan.FindPlanFile(planDir, moduleName)
 		if err != nil {
 			// If plan file doesn't exist, assume no dependencies
 			moduleDeps[moduleName] = []string{}
@@ -1682,12 +1864,13 @@ func (h *DoingHandler) sortModulesByTopology(stateData *state.ExecutionStatus) (
 		}
 
 		// Parse plan to extract dependencies
-		planData, err := plan.ParsePlan(string(content))
+		planData, err := plan.ParsePlanFile(planFile)
 		if err != nil {
 			// If parsing fails, assume no dependencies
-			if os.Getenv("MORTY_DEBUG") != "" {
-				fmt.Fprintf(os.Stderr, "DEBUG: Failed to parse plan for '%s': %v\n", moduleName, err)
-			}
+			h.logger.Debug("Failed to parse plan for dependencies",
+				logging.String("module", moduleName),
+				logging.String("error", err.Error()),
+			)
 			moduleDeps[moduleName] = []string{}
 			continue
 		}
@@ -1695,9 +1878,10 @@ func (h *DoingHandler) sortModulesByTopology(stateData *state.ExecutionStatus) (
 		// Extract module dependencies
 		if len(planData.Dependencies) > 0 {
 			moduleDeps[moduleName] = planData.Dependencies
-			if os.Getenv("MORTY_DEBUG") != "" {
-				fmt.Fprintf(os.Stderr, "DEBUG: Module '%s' has dependencies from plan file: %v\n", moduleName, planData.Dependencies)
-			}
+			h.logger.Debug("Module dependencies from plan file",
+				logging.String("module", moduleName),
+				logging.Any("dependencies", planData.Dependencies),
+			)
 		} else {
 			moduleDeps[moduleName] = []string{}
 		}
@@ -1715,9 +1899,11 @@ func (h *DoingHandler) sortModulesByTopology(stateData *state.ExecutionStatus) (
 	for module, deps := range moduleDeps {
 		// module depends on deps, so module's in-degree = len(deps)
 		inDegree[module] = len(deps)
-		if os.Getenv("MORTY_DEBUG") != "" {
-			fmt.Fprintf(os.Stderr, "DEBUG: Module '%s' depends on %v, in-degree=%d\n", module, deps, len(deps))
-		}
+		h.logger.Debug("Module in-degree",
+			logging.String("module", module),
+			logging.Any("dependencies", deps),
+			logging.Int("in_degree", len(deps)),
+		)
 	}
 
 	// Queue of modules with no dependencies
@@ -1755,6 +1941,9 @@ func (h *DoingHandler) sortModulesByTopology(stateData *state.ExecutionStatus) (
 
 	// Check for cycles
 	if len(result) < len(allModules) {
+		if cycle := state.FindCycle(moduleDeps); cycle != nil {
+			return nil, fmt.Errorf("circular dependency detected among modules: %s", state.FormatCycle(cycle))
+		}
 		// There's a cycle, return error with details
 		remaining := make([]string, 0)
 		for module := range allModules {
@@ -1772,9 +1961,9 @@ func (h *DoingHandler) sortModulesByTopology(stateData *state.ExecutionStatus) (
 		return nil, fmt.Errorf("circular dependency detected among modules: %v", remaining)
 	}
 
-	if os.Getenv("MORTY_DEBUG") != "" {
-		fmt.Fprintf(os.Stderr, "DEBUG: Topological order: %v\n", result)
-	}
+	h.logger.Debug("Topological module order",
+		logging.Any("order", result),
+	)
 
 	return result, nil
 }
@@ -1787,7 +1976,7 @@ func (h *DoingHandler) selectNextJob() (int, int, string, string, error) {
 		return -1, -1, "", "", fmt.Errorf("status not loaded")
 	}
 
-	// Simply find the first PENDING job in the array
+	// Simply find the first INTERRUPTED or PENDING job in the array
 	moduleIndex, jobIndex := status.GetNextPendingJob()
 	if moduleIndex == -1 {
 		return -1, -1, "", "", fmt.Errorf("no pending jobs found")
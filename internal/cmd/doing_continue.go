@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/morty/morty/internal/graph"
+	"github.com/morty/morty/internal/logging"
+	"github.com/morty/morty/internal/state"
+)
+
+// failAndBlockDependents records a job failure in continue-on-error mode and
+// marks every PENDING job that depends on it, through job prerequisites or
+// module dependencies, as BLOCKED. It returns the outcomes of the failed job
+// and of all newly blocked jobs.
+func (h *DoingHandler) failAndBlockDependents(module, job string, execErr error) ([]JobOutcome, error) {
+	reason := execErr.Error()
+	if jobState := h.stateManager.GetJob(module, job); jobState != nil {
+		if jobState.FailureReason != "" {
+			reason = jobState.FailureReason
+		}
+		// The executor normally marks the job FAILED itself; make sure it is
+		// never picked up again as PENDING or left RUNNING
+		if jobState.Status != state.StatusFailed {
+			if err := h.updateStatus(module, job, state.StatusFailed); err != nil {
+				return nil, err
+			}
+			if err := h.stateManager.UpdateFailureReason(module, job, reason); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	root := graph.JobID(module, job)
+	outcomes := []JobOutcome{{Module: module, Job: job, Status: state.StatusFailed, Reason: reason}}
+
+	plans, err := state.ScanPlans(h.getPlanDir())
+	if err != nil {
+		return outcomes, fmt.Errorf("读取计划目录失败: %w", err)
+	}
+	g, err := graph.Build(plans, h.stateManager.GetStatus(), graph.LevelJob)
+	if err != nil {
+		return outcomes, fmt.Errorf("构建依赖图失败: %w", err)
+	}
+
+	blockReason := fmt.Sprintf("前置 Job %s 失败: %s", root, reason)
+	for _, id := range g.Downstream(root) {
+		node := g.Node(id)
+		jobState := h.stateManager.GetJob(node.Module, node.Job)
+		if jobState == nil || jobState.Status != state.StatusPending {
+			continue
+		}
+		if err := h.stateManager.BlockJob(node.Module, node.Job, root, blockReason); err != nil {
+			return outcomes, err
+		}
+		h.metrics.JobBlocked()
+		h.logger.Warn("Job blocked by failed prerequisite",
+			logging.String("module", node.Module),
+			logging.String("job", node.Job),
+			logging.String("blocked_by", root),
+		)
+		outcomes = append(outcomes, JobOutcome{
+			Module:    node.Module,
+			Job:       node.Job,
+			Status:    state.StatusBlocked,
+			Reason:    reason,
+			BlockedBy: root,
+		})
+	}
+
+	return outcomes, nil
+}
+
+// releaseBlockedJobs returns BLOCKED jobs to PENDING once the job that
+// blocked them is no longer FAILED (e.g. after --restart), and clears stale
+// block markers from jobs that were reset.
+func (h *DoingHandler) releaseBlockedJobs(stateData *state.ExecutionStatus, now time.Time) {
+	failed := make(map[string]bool)
+	for _, module := range stateData.Modules {
+		for _, job := range module.Jobs {
+			if job.Status == state.StatusFailed {
+				failed[graph.JobID(module.Name, job.Name)] = true
+			}
+		}
+	}
+
+	for i := range stateData.Modules {
+		module := &stateData.Modules[i]
+		changed := false
+		for j := range module.Jobs {
+			job := &module.Jobs[j]
+			if job.BlockedBy == "" {
+				continue
+			}
+			if job.Status == state.StatusBlocked {
+				if failed[job.BlockedBy] {
+					continue
+				}
+				job.Status = state.StatusPending
+			}
+			job.BlockedBy = ""
+			job.FailureReason = ""
+			job.UpdatedAt = now
+			changed = true
+		}
+		if changed {
+			module.Status = h.calculateModuleStatus(module)
+			module.UpdatedAt = now
+		}
+	}
+}
+
+// formatOutcomeReport renders the end-of-run report of a continue-on-error run.
+func formatOutcomeReport(outcomes []JobOutcome) string {
+	var completed, failed, blocked []JobOutcome
+	for _, o := range outcomes {
+		switch o.Status {
+		case state.StatusCompleted:
+			completed = append(completed, o)
+		case state.StatusFailed:
+			failed = append(failed, o)
+		case state.StatusBlocked:
+			blocked = append(blocked, o)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("📋 执行报告\n")
+	sb.WriteString(fmt.Sprintf("  ✅ 完成: %d\n", len(completed)))
+	for _, o := range completed {
+		sb.WriteString(fmt.Sprintf("     - %s\n", graph.JobID(o.Module, o.Job)))
+	}
+	sb.WriteString(fmt.Sprintf("  ❌ 失败: %d\n", len(failed)))
+	for _, o := range failed {
+		sb.WriteString(fmt.Sprintf("     - %s: %s\n", graph.JobID(o.Module, o.Job), o.Reason))
+	}
+	sb.WriteString(fmt.Sprintf("  ⛔ 阻塞: %d\n", len(blocked)))
+	for _, o := range blocked {
+		sb.WriteString(fmt.Sprintf("     - %s (被 %s 阻塞)\n", graph.JobID(o.Module, o.Job), o.BlockedBy))
+	}
+	return sb.String()
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/morty/morty/internal/state"
+)
+
+// stubEngine completes every job except those listed in fail.
+type stubEngine struct {
+	handler  *DoingHandler
+	fail     map[string]bool
+	executed []string
+}
+
+func (e *stubEngine) ExecuteJob(ctx context.Context, module, job string) error {
+	e.executed = append(e.executed, module+"/"+job)
+	if e.fail[module+"/"+job] {
+		return fmt.Errorf("job execution failed: exit code 1")
+	}
+	return e.handler.GetStateManager().UpdateJobStatusByName(module, job, state.StatusCompleted)
+}
+
+func (e *stubEngine) ExecuteTask(ctx context.Context, module, job string, taskIndex int, taskDesc string) error {
+	return nil
+}
+
+func (e *stubEngine) ResumeJob(ctx context.Context, module, job string) error {
+	return nil
+}
+
+// continuePlan renders a minimal plan with one job per name; every job
+// after the first depends on the previous one.
+func continuePlan(name, deps string, jobs ...string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# Plan: %s\n\n## 模块概述\n\n**模块职责**: 测试\n\n**依赖模块**: %s\n\n## Jobs\n\n", name, deps))
+	for i, job := range jobs {
+		prereq := "无"
+		if i > 0 {
+			prereq = fmt.Sprintf("job_%d", i)
+		}
+		sb.WriteString(fmt.Sprintf("### Job %d: %s\n\n**目标**: %s\n\n**前置条件**: %s\n\n**Tasks (Todo 列表)**:\n- [ ] Task 1: 实现\n\n**验证器**:\n- 通过\n\n", i+1, job, job, prereq))
+	}
+	return sb.String()
+}
+
+func newContinueTestHandler(t *testing.T, continueOnError bool) (*DoingHandler, *stubEngine) {
+	t.Helper()
+	workDir := filepath.Join(setupTestDir(t), ".morty")
+	planDir := filepath.Join(workDir, "plan")
+	if err := os.MkdirAll(planDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	plans := map[string]string{
+		"core.md":  continuePlan("core", "无", "core_types", "core_store"),
+		"api.md":   continuePlan("api", "core", "api_routes"),
+		"tools.md": continuePlan("tools", "无", "tools_lint"),
+	}
+	for name, content := range plans {
+		if err := os.WriteFile(filepath.Join(planDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &mockConfig{
+		workDir: workDir,
+		planDir: planDir,
+		values:  map[string]interface{}{"execution.continue_on_error": continueOnError},
+	}
+	handler := NewDoingHandler(cfg, &mockLogger{})
+	engine := &stubEngine{handler: handler, fail: map[string]bool{"core/core_types": true}}
+	handler.SetExecutor(engine)
+	return handler, engine
+}
+
+// TestDoingHandler_ContinueOnError tests that a failure blocks its transitive
+// dependents while independent jobs keep running.
+func TestDoingHandler_ContinueOnError(t *testing.T) {
+	handler, engine := newContinueTestHandler(t, true)
+
+	result, err := handler.Execute(context.Background(), nil)
+	if err == nil || result.ExitCode != 1 {
+		t.Fatalf("Expected run to report failures, got %v (exit %d)", err, result.ExitCode)
+	}
+	if !strings.Contains(err.Error(), "1 个 Job 失败, 2 个 Job 被阻塞") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if got := strings.Join(engine.executed, ","); got != "core/core_types,tools/tools_lint" {
+		t.Errorf("Expected only core_types and tools_lint to run, got %s", got)
+	}
+
+	sm := handler.GetStateManager()
+	if job := sm.GetJob("core", "core_types"); job.Status != state.StatusFailed || job.FailureReason == "" {
+		t.Errorf("Expected core_types FAILED with reason, got %s %q", job.Status, job.FailureReason)
+	}
+	for _, id := range [][2]string{{"core", "core_store"}, {"api", "api_routes"}} {
+		job := sm.GetJob(id[0], id[1])
+		if job.Status != state.StatusBlocked || job.BlockedBy != "core/core_types" {
+			t.Errorf("Expected %s/%s BLOCKED by core/core_types, got %s %q", id[0], id[1], job.Status, job.BlockedBy)
+		}
+		if !strings.Contains(job.FailureReason, "core/core_types") {
+			t.Errorf("Expected block reason to name the root failure, got %q", job.FailureReason)
+		}
+	}
+	if job := sm.GetJob("tools", "tools_lint"); job.Status != state.StatusCompleted {
+		t.Errorf("Expected tools_lint COMPLETED, got %s", job.Status)
+	}
+
+	report := formatOutcomeReport(result.Outcomes)
+	for _, want := range []string{"✅ 完成: 1", "❌ 失败: 1", "⛔ 阻塞: 2", "api/api_routes (被 core/core_types 阻塞)"} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Expected %q in report:\n%s", want, report)
+		}
+	}
+}
+
+// TestDoingHandler_StopOnError tests that the first failure stops the run by default.
+func TestDoingHandler_StopOnError(t *testing.T) {
+	handler, engine := newContinueTestHandler(t, false)
+
+	if _, err := handler.Execute(context.Background(), nil); err == nil {
+		t.Fatal("Expected failure to stop execution")
+	}
+	if len(engine.executed) != 1 {
+		t.Errorf("Expected execution to stop after the first job, got %v", engine.executed)
+	}
+	if job := handler.GetStateManager().GetJob("api", "api_routes"); job.Status != state.StatusPending {
+		t.Errorf("Expected api_routes to stay PENDING, got %s", job.Status)
+	}
+}
+
+// TestDoingHandler_RestartReleasesBlocked tests that restarting a failed job
+// returns the jobs it blocked to PENDING.
+func TestDoingHandler_RestartReleasesBlocked(t *testing.T) {
+	handler, engine := newContinueTestHandler(t, true)
+	handler.Execute(context.Background(), nil)
+
+	delete(engine.fail, "core/core_types")
+	if err := handler.handleRestart("core", "core_types"); err != nil {
+		t.Fatalf("handleRestart failed: %v", err)
+	}
+
+	sm := handler.GetStateManager()
+	for _, id := range [][2]string{{"core", "core_types"}, {"core", "core_store"}, {"api", "api_routes"}} {
+		job := sm.GetJob(id[0], id[1])
+		if job.Status != state.StatusPending || job.BlockedBy != "" {
+			t.Errorf("Expected %s/%s PENDING and unblocked, got %s %q", id[0], id[1], job.Status, job.BlockedBy)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/morty/morty/internal/doing"
+	"github.com/morty/morty/internal/executor"
+	"github.com/morty/morty/internal/logging"
+)
+
+// controlFileName is the file in the work directory that morty pause writes
+// and morty resume removes.
+const controlFileName = "control.json"
+
+// controlPollInterval is how often a running morty doing reads the control file.
+const controlPollInterval = 500 * time.Millisecond
+
+// Control file actions.
+const (
+	// ControlPause finishes the current job, then waits for morty resume.
+	ControlPause = "pause"
+	// ControlInterrupt interrupts the current job, then waits for morty resume.
+	ControlInterrupt = "interrupt"
+)
+
+// ControlRequest is the content of the control file.
+type ControlRequest struct {
+	Action      string    `json:"action"`
+	RequestedAt time.Time `json:"requested_at"`
+}
+
+// readControlRequest reads the control file, returning nil when there is none.
+func readControlRequest(path string) (*ControlRequest, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var req ControlRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, fmt.Errorf("无效的控制文件 %s: %w", path, err)
+	}
+	return &req, nil
+}
+
+// writeControlRequest writes the control file atomically, so a running
+// morty doing never reads it half written.
+func writeControlRequest(path string, req *ControlRequest) error {
+	data, err := json.MarshalIndent(req, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// runControl stops, pauses and interrupts a running morty doing. The first
+// SIGINT or SIGTERM asks it to stop once the current job is done, the second
+// interrupts the job. A pause request in the control file makes it wait
+// after the current job, or right away for an interrupt, until the request
+// is removed again.
+type runControl struct {
+	path   string
+	logger logging.Logger
+
+	mu        sync.Mutex
+	stop      bool               // a signal asked to stop after the current job
+	request   *ControlRequest    // the request in the control file, if any
+	cancelJob context.CancelFunc // interrupts the current job
+
+	signals chan os.Signal
+	done    chan struct{}
+}
+
+// startRunControl starts watching for signals and the control file. A
+// control file left over from an earlier run is removed.
+func (h *DoingHandler) startRunControl() *runControl {
+	c := &runControl{
+		path:    filepath.Join(h.paths.GetWorkDir(), controlFileName),
+		logger:  h.logger,
+		signals: make(chan os.Signal, 2),
+		done:    make(chan struct{}),
+	}
+	if err := os.Remove(c.path); err == nil {
+		c.logger.Warn("Removed a pause request left by an earlier run", logging.String("file", c.path))
+	}
+
+	signal.Notify(c.signals, syscall.SIGINT, syscall.SIGTERM)
+	go c.watch()
+	return c
+}
+
+// close stops watching and restores the default signal handling.
+func (c *runControl) close() {
+	signal.Stop(c.signals)
+	close(c.done)
+}
+
+// watch handles signals and polls the control file until close.
+func (c *runControl) watch() {
+	ticker := time.NewTicker(controlPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case sig := <-c.signals:
+			c.handleSignal(sig)
+		case <-ticker.C:
+			c.poll()
+		}
+	}
+}
+
+// handleSignal asks to stop on the first signal and interrupts the current
+// job on the second.
+func (c *runControl) handleSignal(sig os.Signal) {
+	c.mu.Lock()
+	first := !c.stop
+	c.stop = true
+	cancel := c.cancelJob
+	if !first && cancel != nil {
+		c.cancelJob = nil
+	}
+	c.mu.Unlock()
+
+	c.logger.Info("Received signal", logging.String("signal", sig.String()), logging.Bool("first", first))
+	switch {
+	case first:
+		fmt.Println("\n⏹️  收到中断信号: 当前 Job 完成后停止，再按一次 Ctrl+C 立即中断")
+	case cancel != nil:
+		fmt.Println("\n⛔ 立即中断当前 Job...")
+		cancel()
+	}
+}
+
+// poll reads the control file and interrupts the current job when asked to.
+func (c *runControl) poll() {
+	req, err := readControlRequest(c.path)
+	if err != nil {
+		c.logger.Warn("Failed to read control file", logging.String("error", err.Error()))
+		return
+	}
+
+	c.mu.Lock()
+	changed := (req == nil) != (c.request == nil) || (req != nil && *req != *c.request)
+	c.request = req
+	var cancel context.CancelFunc
+	if req != nil && req.Action == ControlInterrupt && c.cancelJob != nil {
+		cancel = c.cancelJob
+		c.cancelJob = nil
+	}
+	c.mu.Unlock()
+
+	if changed && req != nil {
+		c.logger.Info("Pause requested", logging.String("action", req.Action))
+		fmt.Println("\n⏸️  收到暂停请求 (morty pause)")
+	}
+	if cancel != nil {
+		fmt.Println("\n⛔ 立即中断当前 Job...")
+		cancel()
+	}
+}
+
+// beginJob returns the context a job runs with, which an interrupt cancels,
+// and the function to call once the job is done.
+func (c *runControl) beginJob(ctx context.Context) (context.Context, func()) {
+	jobCtx, cancel := context.WithCancel(ctx)
+	c.mu.Lock()
+	c.cancelJob = cancel
+	c.mu.Unlock()
+
+	return jobCtx, func() {
+		c.mu.Lock()
+		c.cancelJob = nil
+		c.mu.Unlock()
+		cancel()
+	}
+}
+
+// proceed reports whether the next job may run. It returns false when a
+// signal asked to stop, and waits while the control file holds a pause
+// request, until morty resume removes it or a signal arrives.
+func (c *runControl) proceed(ctx context.Context) bool {
+	c.poll()
+	waiting := false
+	for {
+		c.mu.Lock()
+		stop, paused := c.stop, c.request != nil
+		c.mu.Unlock()
+
+		switch {
+		case stop || ctx.Err() != nil:
+			return false
+		case !paused:
+			if waiting {
+				c.logger.Info("Execution resumed")
+				fmt.Println("▶️  已恢复执行")
+			}
+			return true
+		case !waiting:
+			waiting = true
+			c.logger.Info("Execution paused", logging.String("file", c.path))
+			fmt.Println("⏸️  已暂停: 运行 morty resume 继续，Ctrl+C 退出")
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(controlPollInterval):
+			c.poll()
+		}
+	}
+}
+
+// recordInterrupt saves a recovery point of an interrupted job, which the
+// executor leaves RUNNING, and then marks it INTERRUPTED so the next run
+// resumes it first.
+func (h *DoingHandler) recordInterrupt(module, job string, interrupted *executor.InterruptedError) error {
+	recovery := doing.NewStateRecovery(h.logger, h.paths.GetWorkDir(), h.stateManager)
+	if _, err := recovery.CreateRecoveryPoint(module, job); err != nil {
+		h.logger.Warn("Failed to create recovery point",
+			logging.String("module", module),
+			logging.String("job", job),
+			logging.String("error", err.Error()),
+		)
+	}
+
+	reason := interrupted.Error()
+	fields := []logging.Attr{
+		logging.String("module", module),
+		logging.String("job", job),
+	}
+	if cli := interrupted.Interrupt; cli != nil {
+		reason = fmt.Sprintf("%s (AI CLI stopped after %s)", reason, cli.Duration.Round(time.Second))
+		fields = append(fields,
+			logging.Int("pid", cli.PID),
+			logging.Int("partial_stdout_bytes", len(cli.PartialStdout)),
+		)
+	}
+	if err := h.stateManager.InterruptJob(module, job, reason); err != nil {
+		return err
+	}
+
+	h.logger.Warn("Job interrupted", append(fields, logging.String("reason", reason))...)
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -14,6 +16,31 @@ import (
 	"github.com/morty/morty/internal/state"
 )
 
+// TestMain runs the package tests from a scratch directory, because doing
+// treats the working directory as the project root for logs and commits.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "cmd-test-")
+	if err != nil {
+		panic(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		panic(err)
+	}
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// chdirTestDir makes dir the working directory for the rest of the test.
+func chdirTestDir(t *testing.T, dir string) {
+	t.Helper()
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(origDir) })
+}
+
 func TestNewDoingHandler(t *testing.T) {
 	cfg := &mockConfig{}
 	logger := &mockLogger{}
@@ -368,6 +395,7 @@ func TestDoingHandler_Execute_success(t *testing.T) {
 	}
 	logger := &mockLogger{}
 	handler := NewDoingHandler(cfg, logger)
+	handler.SetExecutor(&mockExecutor{})
 
 	ctx := context.Background()
 	result, err := handler.Execute(ctx, []string{"--module", "my-module", "--job", "my-job", "--restart"})
@@ -533,42 +561,56 @@ func (e *testError) Error() string {
 	return e.msg
 }
 
-// setupTestState creates a test state file with the given modules and jobs
+// setupTestState creates a test state file with the given modules and jobs,
+// each in name order
 func setupTestState(t *testing.T, workDir string, modules map[string]map[string]state.Status) string {
 	statusFile := filepath.Join(workDir, "status.json")
 	stateMgr := state.NewManager(statusFile)
 
-	// Load or create default state
-	if err := stateMgr.Load(); err != nil {
-		t.Fatalf("Failed to load state: %v", err)
-	}
-
-	// Add modules and jobs
-	for moduleName, jobs := range modules {
-		module := &state.ModuleState{
-			Name:      moduleName,
-			Status:    state.StatusPending,
-			Jobs:      make(map[string]*state.JobState),
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
+	moduleNames := make([]string, 0, len(modules))
+	for moduleName := range modules {
+		moduleNames = append(moduleNames, moduleName)
+	}
+	sort.Strings(moduleNames)
+
+	status := &state.ExecutionStatus{
+		Version: "2.0",
+		Global:  state.GlobalState{Status: state.StatusPending, TotalModules: len(modules)},
+	}
+	for mi, moduleName := range moduleNames {
+		module := state.ModuleState{
+			Index:       mi,
+			Name:        moduleName,
+			DisplayName: moduleName,
+			PlanFile:    moduleName + ".md",
+			Status:      state.StatusPending,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
 		}
 
-		for jobName, jobStatus := range jobs {
-			module.Jobs[jobName] = &state.JobState{
+		jobNames := make([]string, 0, len(modules[moduleName]))
+		for jobName := range modules[moduleName] {
+			jobNames = append(jobNames, jobName)
+		}
+		sort.Strings(jobNames)
+		for ji, jobName := range jobNames {
+			module.Jobs = append(module.Jobs, state.JobState{
+				Index:          ji,
+				GlobalIndex:    status.Global.TotalJobs,
 				Name:           jobName,
-				Status:         jobStatus,
+				Status:         modules[moduleName][jobName],
 				TasksTotal:     5,
 				TasksCompleted: 0,
 				CreatedAt:      time.Now(),
 				UpdatedAt:      time.Now(),
-			}
+			})
+			status.Global.TotalJobs++
 		}
-
-		stateMgr.SetModule(module)
+		status.Modules = append(status.Modules, module)
 	}
 
 	// Save state
-	if err := stateMgr.Save(); err != nil {
+	if err := stateMgr.Save(status); err != nil {
 		t.Fatalf("Failed to save state: %v", err)
 	}
 
@@ -621,7 +663,7 @@ func TestDoingHandler_loadStatus(t *testing.T) {
 	}
 
 	// Verify state was loaded correctly
-	if _, ok := stateData.Modules["test-module"]; !ok {
+	if stateData.GetModuleByName("test-module") == nil {
 		t.Error("loadStatus() test-module not found in state")
 	}
 }
@@ -635,6 +677,14 @@ func TestDoingHandler_loadStatus_createDefault(t *testing.T) {
 		t.Fatalf("Failed to create plan dir: %v", err)
 	}
 
+	setupTestPlanFile(t, planDir, "my-module", `# Plan: my-module
+
+### Job 1: my-job
+
+**Tasks (Todo 列表)**:
+- [ ] Task 1: Test task
+`)
+
 	cfg := &mockConfig{
 		workDir: workDir,
 		planDir: planDir,
@@ -655,10 +705,13 @@ func TestDoingHandler_loadStatus_createDefault(t *testing.T) {
 		t.Fatal("loadStatus() state is nil")
 	}
 
-	// Should have default state
+	// Should have a state generated from the plan
 	if stateData.Version == "" {
 		t.Error("loadStatus() default state version is empty")
 	}
+	if module := stateData.GetModuleByName("my-module"); module == nil || module.GetJobByName("my-job") == nil {
+		t.Errorf("loadStatus() should generate my-module/my-job from the plan, got %+v", stateData.Modules)
+	}
 }
 
 // Test handleRestart with no module specified (reset all)
@@ -695,10 +748,10 @@ func TestDoingHandler_handleRestart_all(t *testing.T) {
 
 	// Verify all jobs are reset to PENDING
 	stateData := handler.stateManager.GetState()
-	for moduleName, module := range stateData.Modules {
-		for jobName, job := range module.Jobs {
+	for _, module := range stateData.Modules {
+		for _, job := range module.Jobs {
 			if job.Status != state.StatusPending {
-				t.Errorf("handleRestart() job %s/%s status = %v, want PENDING", moduleName, jobName, job.Status)
+				t.Errorf("handleRestart() job %s/%s status = %v, want PENDING", module.Name, job.Name, job.Status)
 			}
 		}
 	}
@@ -737,10 +790,10 @@ func TestDoingHandler_handleRestart_module(t *testing.T) {
 
 	// Verify only module1 jobs are reset
 	stateData := handler.stateManager.GetState()
-	if stateData.Modules["module1"].Jobs["job_1"].Status != state.StatusPending {
+	if stateData.GetModuleByName("module1").GetJobByName("job_1").Status != state.StatusPending {
 		t.Error("handleRestart() module1/job_1 should be PENDING")
 	}
-	if stateData.Modules["module2"].Jobs["job_1"].Status != state.StatusCompleted {
+	if stateData.GetModuleByName("module2").GetJobByName("job_1").Status != state.StatusCompleted {
 		t.Error("handleRestart() module2/job_1 should still be COMPLETED")
 	}
 }
@@ -776,10 +829,10 @@ func TestDoingHandler_handleRestart_job(t *testing.T) {
 
 	// Verify only job_1 is reset
 	stateData := handler.stateManager.GetState()
-	if stateData.Modules["module1"].Jobs["job_1"].Status != state.StatusPending {
+	if stateData.GetModuleByName("module1").GetJobByName("job_1").Status != state.StatusPending {
 		t.Error("handleRestart() job_1 should be PENDING")
 	}
-	if stateData.Modules["module1"].Jobs["job_2"].Status != state.StatusCompleted {
+	if stateData.GetModuleByName("module1").GetJobByName("job_2").Status != state.StatusCompleted {
 		t.Error("handleRestart() job_2 should still be COMPLETED")
 	}
 }
@@ -844,13 +897,9 @@ func TestDoingHandler_selectTargetJob_withModule(t *testing.T) {
 	handler := NewDoingHandler(cfg, &mockLogger{})
 	handler.loadStatus()
 
-	module, job, err := handler.selectTargetJob("module2", "")
-	if err != nil {
-		t.Errorf("selectTargetJob() error = %v", err)
-	}
-
-	if module != "module2" || job != "job_1" {
-		t.Errorf("selectTargetJob() = %s/%s, want module2/job_1", module, job)
+	// Manual selection is rejected, the status order decides the next job
+	if _, _, err := handler.selectTargetJob("module2", ""); err == nil {
+		t.Error("selectTargetJob() expected error for a manual module selection")
 	}
 }
 
@@ -878,13 +927,9 @@ func TestDoingHandler_selectTargetJob_withModuleAndJob(t *testing.T) {
 	handler := NewDoingHandler(cfg, &mockLogger{})
 	handler.loadStatus()
 
-	module, job, err := handler.selectTargetJob("module1", "job_2")
-	if err != nil {
-		t.Errorf("selectTargetJob() error = %v", err)
-	}
-
-	if module != "module1" || job != "job_2" {
-		t.Errorf("selectTargetJob() = %s/%s, want module1/job_2", module, job)
+	// Manual selection is rejected, the status order decides the next job
+	if _, _, err := handler.selectTargetJob("module1", "job_2"); err == nil {
+		t.Error("selectTargetJob() expected error for a manual job selection")
 	}
 }
 
@@ -939,6 +984,43 @@ func TestDoingHandler_selectTargetJob_noPending(t *testing.T) {
 	}
 }
 
+// Test findExecutableJob follows the job order of a structured plan
+func TestDoingHandler_findExecutableJob_jsonPlan(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	workDir := filepath.Join(tmpDir, ".morty")
+	planDir := filepath.Join(workDir, "plan")
+	if err := os.MkdirAll(planDir, 0755); err != nil {
+		t.Fatalf("Failed to create plan dir: %v", err)
+	}
+
+	// The status lists jobs by name, the plan puts "zeta" first
+	setupTestState(t, workDir, map[string]map[string]state.Status{
+		"module1": {
+			"alpha": state.StatusPending,
+			"zeta":  state.StatusPending,
+		},
+	})
+	planJSON := `{"name": "module1", "jobs": [{"name": "zeta", "tasks": [{"description": "z"}]}, {"name": "alpha", "tasks": [{"description": "a"}]}]}`
+	if err := os.WriteFile(filepath.Join(planDir, "module1.json"), []byte(planJSON), 0644); err != nil {
+		t.Fatalf("Failed to write plan file: %v", err)
+	}
+
+	cfg := &mockConfig{
+		workDir: workDir,
+		planDir: planDir,
+	}
+	handler := NewDoingHandler(cfg, &mockLogger{})
+	handler.loadStatus()
+
+	module := handler.stateManager.GetState().GetModuleByName("module1")
+	for _, planFile := range []string{"", "module1.json"} {
+		module.PlanFile = planFile
+		if got := handler.findExecutableJob("module1", module); got != "zeta" {
+			t.Errorf("findExecutableJob() with plan file %q = %q, want zeta", planFile, got)
+		}
+	}
+}
+
 // Test checkPrerequisites with no prerequisites
 func TestDoingHandler_checkPrerequisites_noPrereqs(t *testing.T) {
 	tmpDir := setupTestDir(t)
@@ -1122,8 +1204,8 @@ func TestDoingHandler_updateStatus(t *testing.T) {
 
 	// Verify status was updated
 	stateData := handler.stateManager.GetState()
-	if stateData.Modules["test-module"].Jobs["job_1"].Status != state.StatusRunning {
-		t.Errorf("updateStatus() status = %v, want RUNNING", stateData.Modules["test-module"].Jobs["job_1"].Status)
+	if stateData.GetModuleByName("test-module").GetJobByName("job_1").Status != state.StatusRunning {
+		t.Errorf("updateStatus() status = %v, want RUNNING", stateData.GetModuleByName("test-module").GetJobByName("job_1").Status)
 	}
 }
 
@@ -1161,8 +1243,8 @@ func TestDoingHandler_updateStatus_persistence(t *testing.T) {
 	handler2.loadStatus()
 
 	stateData := handler2.stateManager.GetState()
-	if stateData.Modules["test-module"].Jobs["job_1"].Status != state.StatusCompleted {
-		t.Errorf("updateStatus() persisted status = %v, want COMPLETED", stateData.Modules["test-module"].Jobs["job_1"].Status)
+	if stateData.GetModuleByName("test-module").GetJobByName("job_1").Status != state.StatusCompleted {
+		t.Errorf("updateStatus() persisted status = %v, want COMPLETED", stateData.GetModuleByName("test-module").GetJobByName("job_1").Status)
 	}
 }
 
@@ -1895,18 +1977,23 @@ func TestDoingHandler_ExecutorIntegration(t *testing.T) {
 
 	// Setup test state with tasks
 	stateContent := `{
-		"version": "1.0",
+		"version": "2.0",
 		"global": {
 			"status": "PENDING",
 			"start_time": "2024-01-01T00:00:00Z",
-			"last_update": "2024-01-01T00:00:00Z"
+			"last_update": "2024-01-01T00:00:00Z",
+			"total_modules": 1,
+			"total_jobs": 1
 		},
-		"modules": {
-			"test-module": {
+		"modules": [
+			{
+				"index": 0,
 				"name": "test-module",
 				"status": "PENDING",
-				"jobs": {
-					"job_1": {
+				"jobs": [
+					{
+						"index": 0,
+						"global_index": 0,
 						"name": "job_1",
 						"status": "PENDING",
 						"tasks_total": 2,
@@ -1918,11 +2005,11 @@ func TestDoingHandler_ExecutorIntegration(t *testing.T) {
 						"created_at": "2024-01-01T00:00:00Z",
 						"updated_at": "2024-01-01T00:00:00Z"
 					}
-				},
+				],
 				"created_at": "2024-01-01T00:00:00Z",
 				"updated_at": "2024-01-01T00:00:00Z"
 			}
-		}
+		]
 	}`
 
 	stateFile := filepath.Join(workDir, "status.json")
@@ -1948,7 +2035,7 @@ func TestDoingHandler_ExecutorIntegration(t *testing.T) {
 		CommitPrefix: "morty:",
 		WorkingDir:   workDir,
 	}
-	eng := executor.NewEngine(handler.stateManager, gitMgr, &mockLogger{}, execConfig)
+	eng := executor.NewEngine(handler.stateManager, gitMgr, &mockLogger{}, execConfig, nil)
 	handler.SetExecutor(eng)
 
 	// Verify executor is properly set
@@ -2048,6 +2135,7 @@ func TestDoingHandler_createGitCommit_success(t *testing.T) {
 		workDir: workDir,
 	}
 	handler := NewDoingHandler(cfg, &mockLogger{})
+	chdirTestDir(t, workDir)
 
 	// Initialize git repository
 	gitMgr := git.NewManager()
@@ -2120,6 +2208,7 @@ func TestDoingHandler_createGitCommit_noChanges(t *testing.T) {
 		workDir: workDir,
 	}
 	handler := NewDoingHandler(cfg, &mockLogger{})
+	chdirTestDir(t, workDir)
 
 	// Initialize git repository
 	gitMgr := git.NewManager()
@@ -2174,6 +2263,7 @@ func TestDoingHandler_createGitCommit_stagesAllChanges(t *testing.T) {
 		workDir: workDir,
 	}
 	handler := NewDoingHandler(cfg, &mockLogger{})
+	chdirTestDir(t, workDir)
 
 	// Initialize git repository
 	gitMgr := git.NewManager()
@@ -2224,18 +2314,27 @@ func TestDoingHandler_createGitCommit_stagesAllChanges(t *testing.T) {
 	}
 }
 
-// Test createGitCommit error handling
-// Task 6: Test commit error handling
+// Test createGitCommit initializes a repository at the project root
+// Task 2.5: Test git initialization
 func TestDoingHandler_createGitCommit_notARepo(t *testing.T) {
 	tmpDir := setupTestDir(t)
 	workDir := filepath.Join(tmpDir, ".morty")
+	if err := os.MkdirAll(workDir, 0755); err != nil {
+		t.Fatalf("Failed to create .morty dir: %v", err)
+	}
 
 	cfg := &mockConfig{
 		workDir: workDir,
 	}
 	handler := NewDoingHandler(cfg, &mockLogger{})
+	chdirTestDir(t, tmpDir)
+	for _, key := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(key, "Test User")
+	}
+	for _, key := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(key, "test@test.com")
+	}
 
-	// Don't initialize git repo - should fail
 	gitMgr := git.NewManager()
 	handler.SetGitManager(gitMgr)
 
@@ -2246,12 +2345,14 @@ func TestDoingHandler_createGitCommit_notARepo(t *testing.T) {
 	}
 
 	// Create a file to have changes
-	testFile := filepath.Join(workDir, "test.txt")
+	testFile := filepath.Join(tmpDir, "test.txt")
 	os.WriteFile(testFile, []byte("test"), 0644)
 
-	_, err := handler.createGitCommit(summary)
-	if err == nil {
-		t.Error("createGitCommit() expected error for non-git directory")
+	if _, err := handler.createGitCommit(summary); err != nil {
+		t.Fatalf("createGitCommit() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".git")); err != nil {
+		t.Errorf("createGitCommit() should initialize a repository at the project root: %v", err)
 	}
 }
 
@@ -2270,6 +2371,7 @@ func TestDoingHandler_createGitCommit_messageFormat(t *testing.T) {
 		workDir: workDir,
 	}
 	handler := NewDoingHandler(cfg, &mockLogger{})
+	chdirTestDir(t, workDir)
 
 	gitMgr := git.NewManager()
 	handler.SetGitManager(gitMgr)
@@ -2365,18 +2467,23 @@ func TestDoingHandler_generateSummary(t *testing.T) {
 
 	// Setup test state with tasks
 	stateContent := `{
-		"version": "1.0",
+		"version": "2.0",
 		"global": {
 			"status": "PENDING",
 			"start_time": "2024-01-01T00:00:00Z",
-			"last_update": "2024-01-01T00:00:00Z"
+			"last_update": "2024-01-01T00:00:00Z",
+			"total_modules": 1,
+			"total_jobs": 1
 		},
-		"modules": {
-			"test-module": {
+		"modules": [
+			{
+				"index": 0,
 				"name": "test-module",
 				"status": "PENDING",
-				"jobs": {
-					"job_1": {
+				"jobs": [
+					{
+						"index": 0,
+						"global_index": 0,
 						"name": "job_1",
 						"status": "COMPLETED",
 						"tasks_total": 5,
@@ -2384,11 +2491,11 @@ func TestDoingHandler_generateSummary(t *testing.T) {
 						"created_at": "2024-01-01T00:00:00Z",
 						"updated_at": "2024-01-01T00:00:00Z"
 					}
-				},
+				],
 				"created_at": "2024-01-01T00:00:00Z",
 				"updated_at": "2024-01-01T00:00:00Z"
 			}
-		}
+		]
 	}`
 
 	stateFile := filepath.Join(workDir, "status.json")
@@ -2551,7 +2658,7 @@ func TestDoingHandler_formatSummary(t *testing.T) {
 				TasksCompleted: 2,
 				NextAction:     "重试",
 			},
-			contains: []string{"test_module", "test_job", "FAILED", "30s", "2/5"},
+			contains: []string{"test_module", "test_job", "FAILED", "30.0s", "2/5"},
 		},
 		{
 			name:     "nil summary",
@@ -2699,18 +2806,23 @@ func TestDoingHandler_generateSummary_fallbackToState(t *testing.T) {
 
 	// Setup test state with tasks info
 	stateContent := `{
-		"version": "1.0",
+		"version": "2.0",
 		"global": {
 			"status": "PENDING",
 			"start_time": "2024-01-01T00:00:00Z",
-			"last_update": "2024-01-01T00:00:00Z"
+			"last_update": "2024-01-01T00:00:00Z",
+			"total_modules": 1,
+			"total_jobs": 1
 		},
-		"modules": {
-			"test-module": {
+		"modules": [
+			{
+				"index": 0,
 				"name": "test-module",
 				"status": "PENDING",
-				"jobs": {
-					"job_1": {
+				"jobs": [
+					{
+						"index": 0,
+						"global_index": 0,
 						"name": "job_1",
 						"status": "COMPLETED",
 						"tasks_total": 10,
@@ -2718,11 +2830,11 @@ func TestDoingHandler_generateSummary_fallbackToState(t *testing.T) {
 						"created_at": "2024-01-01T00:00:00Z",
 						"updated_at": "2024-01-01T00:00:00Z"
 					}
-				},
+				],
 				"created_at": "2024-01-01T00:00:00Z",
 				"updated_at": "2024-01-01T00:00:00Z"
 			}
-		}
+		]
 	}`
 
 	stateFile := filepath.Join(workDir, "status.json")
@@ -2783,3 +2895,36 @@ func TestDoingHandler_printSummary(t *testing.T) {
 	// Should not panic with nil
 	handler.printSummary(nil)
 }
+
+// TestDoingHandler_sortModulesByTopology_englishReadme tests that module
+// dependencies are read from a README written with English headings.
+func TestDoingHandler_sortModulesByTopology_englishReadme(t *testing.T) {
+	planDir := filepath.Join(setupTestDir(t), "plan")
+	os.MkdirAll(planDir, 0755)
+	readme := `# Plan Index
+
+## Modules
+
+| Module | File | Jobs | Dependencies | Status |
+|--------|------|------|--------------|--------|
+| e2e_test | e2e_test.md | 1 | all modules | Planned |
+| api | api.md | 2 | store, cache | Planned |
+| store | store.md | 1 | None | Planned |
+| cache | cache.md | 1 | store | Planned |
+`
+	os.WriteFile(filepath.Join(planDir, "README.md"), []byte(readme), 0644)
+
+	handler := NewDoingHandler(&mockConfig{planDir: planDir}, &mockLogger{})
+	status := &state.ExecutionStatus{Modules: []state.ModuleState{
+		{Name: "api"}, {Name: "cache"}, {Name: "e2e_test"}, {Name: "store"},
+	}}
+
+	order, err := handler.sortModulesByTopology(status)
+	if err != nil {
+		t.Fatalf("sortModulesByTopology() error = %v", err)
+	}
+	want := []string{"store", "cache", "api", "e2e_test"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("sortModulesByTopology() = %v, want %v", order, want)
+	}
+}
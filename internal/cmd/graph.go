@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/morty/morty/internal/config"
+	"github.com/morty/morty/internal/graph"
+	"github.com/morty/morty/internal/logging"
+	"github.com/morty/morty/internal/state"
+)
+
+// GraphOptions holds the parsed graph command options.
+type GraphOptions struct {
+	Format       graph.Format // --format dot|mermaid|json
+	Level        graph.Level  // --level module|job
+	CriticalPath bool         // --critical-path
+	Ready        bool         // --ready
+	Output       string       // --output file (stdout if empty)
+}
+
+// GraphResult represents the result of the graph command.
+type GraphResult struct {
+	Graph   *graph.Graph
+	Options GraphOptions
+}
+
+// GraphHandler handles the graph command.
+type GraphHandler struct {
+	cfg    config.Manager
+	logger logging.Logger
+	out    io.Writer
+}
+
+// NewGraphHandler creates a new GraphHandler instance.
+func NewGraphHandler(cfg config.Manager, logger logging.Logger) *GraphHandler {
+	return &GraphHandler{
+		cfg:    cfg,
+		logger: logger,
+		out:    os.Stdout,
+	}
+}
+
+// SetOutput sets the writer used when no --output file is given.
+func (h *GraphHandler) SetOutput(w io.Writer) {
+	h.out = w
+}
+
+// Execute builds the dependency graph from the plan files, overlays the
+// statuses from status.json (if present) and renders it.
+func (h *GraphHandler) Execute(ctx context.Context, args []string) (*GraphResult, error) {
+	logger := h.logger.WithContext(ctx)
+
+	opts, err := h.parseOptions(args)
+	if err != nil {
+		return nil, err
+	}
+
+	planDir := h.cfg.GetPlanDir()
+	plans, err := state.ScanPlans(planDir)
+	if err != nil {
+		return nil, fmt.Errorf("读取计划目录失败: %w", err)
+	}
+	if len(plans) == 0 {
+		return nil, fmt.Errorf("计划目录中没有计划文件: %s", planDir)
+	}
+
+	// status.json is optional: without it every node is PENDING
+	var status *state.ExecutionStatus
+	stateManager := state.NewManager(h.cfg.GetStatusFile())
+	if err := stateManager.Load(); err != nil {
+		logger.Debug("No status loaded, rendering plan-only graph",
+			logging.String("error", err.Error()),
+		)
+	} else {
+		status = stateManager.GetStatus()
+	}
+
+	g, err := graph.Build(plans, status, opts.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.CriticalPath {
+		g.MarkCriticalPath()
+	}
+	if opts.Ready {
+		g.MarkReady()
+	}
+
+	// Graph output usually goes to stdout, so diagnostics go to stderr
+	for _, ref := range g.Unresolved {
+		fmt.Fprintf(os.Stderr, "警告: 无法解析的依赖引用: %s\n", ref)
+	}
+
+	out := h.out
+	if opts.Output != "" {
+		f, err := os.Create(opts.Output)
+		if err != nil {
+			return nil, fmt.Errorf("创建输出文件失败: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if err := graph.Render(out, g, opts.Format); err != nil {
+		return nil, err
+	}
+
+	logger.Debug("Dependency graph rendered",
+		logging.String("format", string(opts.Format)),
+		logging.String("level", string(opts.Level)),
+		logging.Int("nodes", len(g.Nodes)),
+		logging.Int("edges", len(g.Edges)),
+	)
+
+	return &GraphResult{Graph: g, Options: opts}, nil
+}
+
+// parseOptions parses graph command arguments.
+func (h *GraphHandler) parseOptions(args []string) (GraphOptions, error) {
+	opts := GraphOptions{
+		Format: graph.FormatDOT,
+		Level:  graph.LevelModule,
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		name, value, hasValue := strings.Cut(arg, "=")
+		needValue := func() (string, error) {
+			if hasValue {
+				return value, nil
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("%s 需要一个参数", name)
+			}
+			i++
+			return args[i], nil
+		}
+
+		switch name {
+		case "--format", "-f":
+			v, err := needValue()
+			if err != nil {
+				return opts, err
+			}
+			opts.Format = graph.Format(v)
+		case "--level", "-l":
+			v, err := needValue()
+			if err != nil {
+				return opts, err
+			}
+			opts.Level = graph.Level(v)
+		case "--output", "-o":
+			v, err := needValue()
+			if err != nil {
+				return opts, err
+			}
+			opts.Output = v
+		case "--critical-path":
+			opts.CriticalPath = true
+		case "--ready":
+			opts.Ready = true
+		default:
+			return opts, fmt.Errorf("未知参数: %s", arg)
+		}
+	}
+
+	switch opts.Format {
+	case graph.FormatDOT, graph.FormatMermaid, graph.FormatJSON:
+	default:
+		return opts, fmt.Errorf("不支持的格式: %s (可选: dot, mermaid, json)", opts.Format)
+	}
+
+	switch opts.Level {
+	case graph.LevelModule, graph.LevelJob:
+	default:
+		return opts, fmt.Errorf("不支持的层级: %s (可选: module, job)", opts.Level)
+	}
+
+	return opts, nil
+}
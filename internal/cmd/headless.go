@@ -0,0 +1,235 @@
+// Package cmd provides command handlers for Morty CLI commands.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/morty/morty/internal/callcli"
+	"github.com/morty/morty/internal/config"
+	"github.com/morty/morty/internal/parser/plan"
+)
+
+// defaultHeadlessTimeout bounds a headless run when ai_cli.max_timeout is
+// not configured.
+const defaultHeadlessTimeout = 30 * time.Minute
+
+// HeadlessOptions holds the options of non-interactive research and plan
+// runs.
+type HeadlessOptions struct {
+	Enabled bool          // --headless
+	Timeout time.Duration // --timeout, defaults to ai_cli.max_timeout
+	Input   string        // --input, a seed requirements file
+}
+
+// planFileMarker matches the line that starts a file in headless plan
+// output.
+var planFileMarker = regexp.MustCompile(`(?m)^<!-- morty:file (\S+) -->[ \t]*$`)
+
+// parseHeadlessOptions extracts --headless, --timeout and --input from args
+// and returns the remaining args.
+func parseHeadlessOptions(args []string) (HeadlessOptions, []string, error) {
+	var opts HeadlessOptions
+	var remaining []string
+
+	value := func(i int, name string) (string, error) {
+		if i+1 >= len(args) {
+			return "", fmt.Errorf("%s 需要一个值", name)
+		}
+		return args[i+1], nil
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--headless":
+			opts.Enabled = true
+		case arg == "--timeout" || strings.HasPrefix(arg, "--timeout="):
+			raw := strings.TrimPrefix(arg, "--timeout=")
+			if arg == "--timeout" {
+				v, err := value(i, arg)
+				if err != nil {
+					return opts, nil, err
+				}
+				raw = v
+				i++
+			}
+			timeout, err := time.ParseDuration(raw)
+			if err != nil || timeout <= 0 {
+				return opts, nil, fmt.Errorf("无效的超时时间: %s", raw)
+			}
+			opts.Timeout = timeout
+		case arg == "--input" || strings.HasPrefix(arg, "--input="):
+			opts.Input = strings.TrimPrefix(arg, "--input=")
+			if arg == "--input" {
+				v, err := value(i, arg)
+				if err != nil {
+					return opts, nil, err
+				}
+				opts.Input = v
+				i++
+			}
+		default:
+			remaining = append(remaining, arg)
+		}
+	}
+
+	if !opts.Enabled && (opts.Timeout != 0 || opts.Input != "") {
+		return opts, nil, fmt.Errorf("--timeout 和 --input 需要与 --headless 一起使用")
+	}
+	return opts, remaining, nil
+}
+
+// headlessTimeout returns the timeout of a headless run.
+func headlessTimeout(cfg config.Manager, opts HeadlessOptions) time.Duration {
+	if opts.Timeout > 0 {
+		return opts.Timeout
+	}
+	if cfg != nil {
+		if timeout := cfg.GetDuration("ai_cli.max_timeout", defaultHeadlessTimeout); timeout > 0 {
+			return timeout
+		}
+	}
+	return defaultHeadlessTimeout
+}
+
+// seedData returns the prompt data for the seed requirements file: its
+// content as "seed" and its name as "seed_file", both "" when there is none.
+func seedData(path string) (map[string]interface{}, error) {
+	data := map[string]interface{}{"seed": "", "seed_file": ""}
+	if path == "" {
+		return data, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取需求文件失败: %w", err)
+	}
+	data["seed"] = strings.TrimSpace(string(content))
+	if data["seed"] == "" {
+		return nil, fmt.Errorf("需求文件为空: %s", path)
+	}
+	data["seed_file"] = filepath.Base(path)
+	return data, nil
+}
+
+// runHeadless runs the AI CLI non-interactively with prompt on stdin and
+// returns its final answer and exit code.
+func runHeadless(ctx context.Context, caller callcli.AICliCaller, prompt string, timeout time.Duration) (string, int, error) {
+	opts := callcli.Options{
+		Timeout: timeout,
+		Stdin:   prompt,
+		Output: callcli.OutputConfig{
+			Mode: callcli.OutputCapture,
+		},
+	}
+
+	// Read-only like the interactive modes; -p prints the answer and exits
+	args := append([]string{"--permission-mode", "plan", "-p"}, caller.BuildArgs()...)
+
+	result, err := caller.GetBaseCaller().CallWithOptions(ctx, caller.GetCLIPath(), args, opts)
+	if result != nil && result.TimedOut {
+		return "", result.ExitCode, fmt.Errorf("AI CLI 在 %s 内没有完成", timeout)
+	}
+	if err != nil {
+		if result == nil {
+			return "", 1, err
+		}
+		return "", result.ExitCode, err
+	}
+	if result.ExitCode != 0 {
+		return "", result.ExitCode, fmt.Errorf("claude code exited with code %d: %s", result.ExitCode, result.Stderr)
+	}
+
+	text, err := headlessResultText(result.Stdout)
+	if err != nil {
+		return "", result.ExitCode, err
+	}
+	return text, result.ExitCode, nil
+}
+
+// headlessResultText extracts the final answer from AI CLI output. JSON
+// output is either a single result event or an array or stream of events;
+// anything else is taken as plain text.
+func headlessResultText(stdout string) (string, error) {
+	trimmed := strings.TrimSpace(stdout)
+
+	var events []callcli.Event
+	var event callcli.Event
+	switch {
+	case json.Unmarshal([]byte(trimmed), &events) == nil:
+	case json.Unmarshal([]byte(trimmed), &event) == nil:
+		events = []callcli.Event{event}
+	default:
+		for _, line := range strings.Split(trimmed, "\n") {
+			var e callcli.Event
+			if json.Unmarshal([]byte(line), &e) != nil {
+				events = nil
+				break
+			}
+			events = append(events, e)
+		}
+	}
+
+	text := trimmed
+	for i := len(events) - 1; i >= 0; i-- {
+		if events[i].Type != "result" {
+			continue
+		}
+		if events[i].Subtype != "" && events[i].Subtype != "success" {
+			return "", fmt.Errorf("AI CLI 没有完成: %s", events[i].Subtype)
+		}
+		text = strings.TrimSpace(events[i].Result)
+		break
+	}
+
+	if text == "" {
+		return "", fmt.Errorf("AI CLI 没有输出结果")
+	}
+	return text, nil
+}
+
+// headlessFile is a file in headless plan output.
+type headlessFile struct {
+	Name    string
+	Content string
+}
+
+// splitPlanFiles splits headless plan output into files at marker lines.
+// Only plan files and README.md directly in the plan directory are
+// accepted.
+func splitPlanFiles(output string) ([]headlessFile, error) {
+	matches := planFileMarker.FindAllStringSubmatchIndex(output, -1)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("输出中没有 Plan 文件标记 (<!-- morty:file 文件名 -->)")
+	}
+
+	var files []headlessFile
+	seen := make(map[string]bool)
+	for i, m := range matches {
+		name := output[m[2]:m[3]]
+		if filepath.Base(name) != name || (name != "README.md" && !plan.IsPlanFile(name)) {
+			return nil, fmt.Errorf("无效的 Plan 文件名: %s", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("Plan 文件重复: %s", name)
+		}
+		seen[name] = true
+
+		end := len(output)
+		if i+1 < len(matches) {
+			end = matches[i+1][0]
+		}
+		content := strings.TrimSpace(output[m[1]:end])
+		if content == "" {
+			return nil, fmt.Errorf("Plan 文件为空: %s", name)
+		}
+		files = append(files, headlessFile{Name: name, Content: content + "\n"})
+	}
+	return files, nil
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/morty/morty/internal/callcli"
+)
+
+// headlessCaller returns an AI CLI caller that answers every call with
+// stdout and records the last call.
+func headlessCaller(stdout string, args *[]string, opts *callcli.Options) *mockAICliCaller {
+	return &mockAICliCaller{
+		buildArgsFunc: func() []string { return []string{"--output-format", "json"} },
+		getBaseCallerFunc: func() callcli.Caller {
+			return &mockCaller{
+				callWithOptionsFunc: func(ctx context.Context, name string, a []string, o callcli.Options) (*callcli.Result, error) {
+					*args, *opts = a, o
+					return &callcli.Result{Stdout: stdout}, nil
+				},
+			}
+		},
+	}
+}
+
+// resultJSON returns the AI CLI's JSON output for a successful answer.
+func resultJSON(t *testing.T, answer string) string {
+	t.Helper()
+	data, err := json.Marshal(callcli.Event{Type: "result", Subtype: "success", Result: answer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestParseHeadlessOptions(t *testing.T) {
+	opts, rest, err := parseHeadlessOptions([]string{"--headless", "--timeout", "5m", "--input=req.md", "cache"})
+	if err != nil {
+		t.Fatalf("parseHeadlessOptions() error: %v", err)
+	}
+	if !opts.Enabled || opts.Timeout != 5*time.Minute || opts.Input != "req.md" || len(rest) != 1 || rest[0] != "cache" {
+		t.Errorf("parseHeadlessOptions() = %+v, %q", opts, rest)
+	}
+
+	for _, args := range [][]string{
+		{"--headless", "--timeout", "soon"},
+		{"--headless", "--input"},
+		{"--input", "req.md"},
+	} {
+		if _, _, err := parseHeadlessOptions(args); err == nil {
+			t.Errorf("Expected an error for %q", args)
+		}
+	}
+}
+
+func TestHeadlessResultText(t *testing.T) {
+	tests := []struct {
+		name    string
+		stdout  string
+		want    string
+		wantErr bool
+	}{
+		{"result object", `{"type":"result","subtype":"success","result":"# Doc\n"}`, "# Doc", false},
+		{"event array", `[{"type":"system"},{"type":"result","subtype":"success","result":"# Doc"}]`, "# Doc", false},
+		{"event stream", "{\"type\":\"system\"}\n{\"type\":\"result\",\"result\":\"# Doc\"}", "# Doc", false},
+		{"plain text", "# Doc\n\ntext\n", "# Doc\n\ntext", false},
+		{"error result", `{"type":"result","subtype":"error_max_turns"}`, "", true},
+		{"empty", "  \n", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := headlessResultText(tt.stdout)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("headlessResultText() = %q, %v", got, err)
+			}
+		})
+	}
+}
+
+func TestSplitPlanFiles(t *testing.T) {
+	files, err := splitPlanFiles("Here are the plans.\n<!-- morty:file README.md -->\n# Plans\n\n<!-- morty:file cache.md -->\n# Plan: cache\n")
+	if err != nil {
+		t.Fatalf("splitPlanFiles() error: %v", err)
+	}
+	if len(files) != 2 || files[0].Name != "README.md" || files[0].Content != "# Plans\n" || files[1].Content != "# Plan: cache\n" {
+		t.Errorf("splitPlanFiles() = %+v", files)
+	}
+
+	for _, output := range []string{
+		"# Plan: cache\n",
+		"<!-- morty:file ../cache.md -->\n# Plan\n",
+		"<!-- morty:file cache.txt -->\n# Plan\n",
+		"<!-- morty:file cache.md -->\n# A\n<!-- morty:file cache.md -->\n# B\n",
+	} {
+		if _, err := splitPlanFiles(output); err == nil {
+			t.Errorf("Expected an error for %q", output)
+		}
+	}
+}
+
+func TestResearchHandler_ExecuteHeadless(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	handler := NewResearchHandler(&mockConfig{}, &mockLogger{})
+	handler.paths.SetWorkDir(tmpDir)
+	promptsDir := filepath.Join(tmpDir, "prompts")
+	os.MkdirAll(promptsDir, 0755)
+	os.WriteFile(filepath.Join(promptsDir, "research.md"), []byte("# Research Prompt\n"), 0644)
+	handler.SetPromptsDir(promptsDir)
+	seed := filepath.Join(tmpDir, "user_auth.md")
+	os.WriteFile(seed, []byte("Users log in with email.\n"), 0644)
+
+	var args []string
+	var opts callcli.Options
+	handler.SetCLICaller(headlessCaller(resultJSON(t, "# 用户认证\n\n结论"), &args, &opts))
+
+	result, err := handler.Execute(context.Background(), []string{"--headless", "--input", seed})
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if result.Topic != "user_auth" || result.Content != "# 用户认证\n\n结论" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if data, err := os.ReadFile(result.OutputPath); err != nil || string(data) != "# 用户认证\n\n结论\n" {
+		t.Errorf("Unexpected research file %s: %q, %v", result.OutputPath, data, err)
+	}
+
+	if strings.Join(args, " ") != "--permission-mode plan -p --output-format json" {
+		t.Errorf("Unexpected args: %q", args)
+	}
+	if opts.Timeout != defaultHeadlessTimeout || opts.Output.Mode != callcli.OutputCapture {
+		t.Errorf("Unexpected options: %+v", opts)
+	}
+	for _, want := range []string{"# Research Topic: user_auth", "Users log in with email.", "非交互模式"} {
+		if !strings.Contains(opts.Stdin, want) {
+			t.Errorf("Expected %q in the prompt:\n%s", want, opts.Stdin)
+		}
+	}
+}
+
+func TestPlanHandler_ExecuteHeadless(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	cfg := &mockConfig{}
+	cfg.SetWorkDir(tmpDir)
+	handler := NewPlanHandler(cfg, &mockLogger{}, nil)
+	promptsDir := filepath.Join(tmpDir, "prompts")
+	os.MkdirAll(promptsDir, 0755)
+	os.WriteFile(filepath.Join(promptsDir, "plan.md"), []byte("# Plan Prompt\n"), 0644)
+	handler.SetPromptsDir(promptsDir)
+
+	output := "<!-- morty:file cache.md -->\n" + fmt.Sprintf(doctorPlan, "cache") +
+		"<!-- morty:file e2e_test.md -->\n" + fmt.Sprintf(doctorPlan, "e2e_test")
+	var args []string
+	var opts callcli.Options
+	handler.SetCLICaller(headlessCaller(resultJSON(t, output), &args, &opts))
+
+	run := func(extra ...string) (*PlanResult, error) {
+		return handler.Execute(context.Background(), append([]string{"--headless", "--timeout", "2m"}, extra...))
+	}
+
+	result, err := run()
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if len(result.PlanFiles) != 2 || result.Overwritten || opts.Timeout != 2*time.Minute {
+		t.Errorf("Unexpected result: %+v (timeout %s)", result, opts.Timeout)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.GetPlanDir(), "cache.md")); err != nil {
+		t.Errorf("Plan file was not written: %v", err)
+	}
+
+	if _, err := run(); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("Expected existing plans to need --force, got %v", err)
+	}
+	if result, err := run("--force"); err != nil || !result.Overwritten {
+		t.Errorf("Expected --force to overwrite, got %+v, %v", result, err)
+	}
+
+	handler.SetCLICaller(headlessCaller(resultJSON(t, "<!-- morty:file broken.md -->\n# Plan: broken\n"), &args, &opts))
+	if _, err := run("--force"); err == nil || !strings.Contains(err.Error(), "未通过校验") {
+		t.Errorf("Expected a validation error, got %v", err)
+	}
+}
@@ -0,0 +1,250 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/morty/morty/internal/config"
+	"github.com/morty/morty/internal/logging"
+	"github.com/morty/morty/internal/logview"
+	"github.com/morty/morty/internal/state"
+)
+
+// LogsOptions holds the parsed logs command options.
+type LogsOptions struct {
+	Module    string        // Module part of the positional module[/job]
+	Job       string        // Job part of the positional module[/job]
+	Follow    bool          // --follow: keep printing new entries
+	Level     logging.Level // --level: minimum level
+	Since     time.Time     // --since: duration ago or absolute time
+	Attempt   int           // --attempt N: only the Nth run of the job
+	Raw       bool          // --raw / --formatted
+	ToolsOnly bool          // --tools-only: only tool calls and results
+}
+
+// LogsResult represents the result of the logs command.
+type LogsResult struct {
+	Sources int // Number of log files read
+	Records int // Number of records printed
+}
+
+// LogsHandler handles the logs command.
+type LogsHandler struct {
+	cfg          config.Manager
+	logger       logging.Logger
+	out          io.Writer
+	pollInterval time.Duration
+}
+
+// NewLogsHandler creates a new LogsHandler instance.
+func NewLogsHandler(cfg config.Manager, logger logging.Logger) *LogsHandler {
+	return &LogsHandler{
+		cfg:          cfg,
+		logger:       logger,
+		out:          os.Stdout,
+		pollInterval: 500 * time.Millisecond,
+	}
+}
+
+// SetOutput sets the writer logs are printed to.
+func (h *LogsHandler) SetOutput(w io.Writer) {
+	h.out = w
+}
+
+// Execute merges the per-job logs, conversation logs and the global
+// morty.log on one timeline, filters and prints them. With --follow it keeps
+// printing new entries until ctx is cancelled.
+func (h *LogsHandler) Execute(ctx context.Context, args []string) (*LogsResult, error) {
+	logger := h.logger.WithContext(ctx)
+
+	opts, err := parseLogsOptions(args, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	reader := logview.NewReader(h.readerOptions())
+	renderer := logview.NewRenderer(h.out, opts.Raw)
+	filter := logview.Filter{
+		Module:    opts.Module,
+		Job:       opts.Job,
+		MinLevel:  opts.Level,
+		Since:     opts.Since,
+		Attempt:   opts.Attempt,
+		ToolsOnly: opts.ToolsOnly,
+	}
+
+	result := &LogsResult{}
+	emit := func() error {
+		records, err := reader.Read()
+		if err != nil {
+			return fmt.Errorf("读取日志失败: %w", err)
+		}
+		var matched []logview.Record
+		for _, r := range records {
+			if filter.Match(r) {
+				matched = append(matched, r)
+			}
+		}
+		result.Sources = len(reader.Sources())
+		result.Records += len(matched)
+		return renderer.Write(matched)
+	}
+
+	if err := emit(); err != nil {
+		return result, err
+	}
+	if result.Sources == 0 && !opts.Follow {
+		return result, fmt.Errorf("没有找到日志文件, 请先运行 morty doing")
+	}
+
+	logger.Debug("Logs printed",
+		logging.Int("sources", result.Sources),
+		logging.Int("records", result.Records),
+		logging.Bool("follow", opts.Follow),
+	)
+
+	if !opts.Follow {
+		return result, nil
+	}
+
+	ticker := time.NewTicker(h.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return result, nil
+		case <-ticker.C:
+			if err := emit(); err != nil {
+				return result, err
+			}
+		}
+	}
+}
+
+// readerOptions locates the log files: executor and conversation logs in
+// .morty/logs, job logs in the doing log directory and the global log from
+// the logging configuration. Known jobs from status.json resolve file names.
+func (h *LogsHandler) readerOptions() logview.Options {
+	opts := logview.Options{
+		LogDirs:   []string{filepath.Join(h.cfg.GetWorkDir(), "logs"), h.cfg.GetLogDir()},
+		GlobalLog: h.cfg.GetString("logging.file.path", config.DefaultLoggingFilePath),
+		Jobs:      make(map[string][]string),
+	}
+
+	stateManager := state.NewManager(h.cfg.GetStatusFile())
+	if err := stateManager.Load(); err != nil {
+		h.logger.Debug("No status loaded, job names resolved from log files only",
+			logging.String("error", err.Error()),
+		)
+		return opts
+	}
+	if status := stateManager.GetStatus(); status != nil {
+		for _, module := range status.Modules {
+			for _, job := range module.Jobs {
+				opts.Jobs[module.Name] = append(opts.Jobs[module.Name], job.Name)
+			}
+		}
+	}
+	return opts
+}
+
+// parseLogsOptions parses logs command arguments. now anchors relative
+// --since durations.
+func parseLogsOptions(args []string, now time.Time) (LogsOptions, error) {
+	opts := LogsOptions{Level: logging.DebugLevel}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		name, value, hasValue := strings.Cut(arg, "=")
+		needValue := func() (string, error) {
+			if hasValue {
+				return value, nil
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("%s 需要一个参数", name)
+			}
+			i++
+			return args[i], nil
+		}
+
+		switch name {
+		case "--follow", "-f":
+			opts.Follow = true
+		case "--raw":
+			opts.Raw = true
+		case "--formatted":
+			opts.Raw = false
+		case "--tools-only":
+			opts.ToolsOnly = true
+		case "--level", "-l":
+			v, err := needValue()
+			if err != nil {
+				return opts, err
+			}
+			switch strings.ToLower(v) {
+			case "debug", "info", "warn", "warning", "error":
+				opts.Level = logging.ParseLevel(strings.ToLower(v))
+			default:
+				return opts, fmt.Errorf("不支持的日志级别: %s (可选: debug, info, warn, error)", v)
+			}
+		case "--since", "-s":
+			v, err := needValue()
+			if err != nil {
+				return opts, err
+			}
+			if opts.Since, err = parseSince(v, now); err != nil {
+				return opts, err
+			}
+		case "--attempt", "-a":
+			v, err := needValue()
+			if err != nil {
+				return opts, err
+			}
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return opts, fmt.Errorf("--attempt 需要一个正整数: %s", v)
+			}
+			opts.Attempt = n
+		default:
+			if strings.HasPrefix(arg, "-") {
+				return opts, fmt.Errorf("未知参数: %s", arg)
+			}
+			if opts.Module != "" {
+				return opts, fmt.Errorf("只能指定一个 module[/job]: %s", arg)
+			}
+			opts.Module, opts.Job, _ = strings.Cut(arg, "/")
+			if opts.Module == "" {
+				return opts, fmt.Errorf("无效的 module[/job]: %s", arg)
+			}
+		}
+	}
+
+	if opts.Attempt > 0 && opts.Job == "" {
+		return opts, fmt.Errorf("--attempt 需要指定 module/job")
+	}
+	return opts, nil
+}
+
+// parseSince accepts a duration ("30m", "2h") or an absolute time
+// (RFC 3339, "2006-01-02 15:04:05" or "2006-01-02" in local time).
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("无法解析 --since: %s (示例: 30m, 2h, 2006-01-02 15:04:05)", value)
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/morty/morty/internal/logging"
+)
+
+func TestParseLogsOptions(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.Local)
+
+	opts, err := parseLogsOptions([]string{"core/types", "-f", "--level=warn", "--since", "30m", "--attempt", "2", "--tools-only", "--raw"}, now)
+	if err != nil {
+		t.Fatalf("parseLogsOptions failed: %v", err)
+	}
+	if opts.Module != "core" || opts.Job != "types" {
+		t.Errorf("Expected core/types, got %s/%s", opts.Module, opts.Job)
+	}
+	if !opts.Follow || !opts.ToolsOnly || !opts.Raw || opts.Attempt != 2 {
+		t.Errorf("Unexpected flags: %+v", opts)
+	}
+	if opts.Level != logging.WarnLevel {
+		t.Errorf("Expected WARN level, got %v", opts.Level)
+	}
+	if !opts.Since.Equal(now.Add(-30 * time.Minute)) {
+		t.Errorf("Expected since 30m ago, got %v", opts.Since)
+	}
+
+	opts, err = parseLogsOptions([]string{"--since", "2026-10-18 09:30:00", "core"}, now)
+	if err != nil {
+		t.Fatalf("parseLogsOptions failed: %v", err)
+	}
+	if opts.Module != "core" || opts.Job != "" || opts.Since.Hour() != 9 || opts.Level != logging.DebugLevel {
+		t.Errorf("Unexpected options: %+v", opts)
+	}
+
+	for _, args := range [][]string{
+		{"--level", "trace"},
+		{"--since", "yesterday"},
+		{"--attempt", "0", "core/types"},
+		{"--attempt", "1", "core"},
+		{"--level"},
+		{"--unknown"},
+		{"core", "api"},
+		{"/types"},
+	} {
+		if _, err := parseLogsOptions(args, now); err == nil {
+			t.Errorf("Expected error for %v", args)
+		}
+	}
+}
+
+func TestLogsHandler_Execute(t *testing.T) {
+	workDir := filepath.Join(setupTestDir(t), ".morty")
+	logDir := filepath.Join(workDir, "logs")
+	if err := os.MkdirAll(logDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	jobLog := "=== Job Log: core/types ===\nStarted: 2026-10-18 10:00:00\n\n=== STDOUT ===\n" +
+		`{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Bash"}]}}` + "\n\nExit Code: 1\n"
+	if err := os.WriteFile(filepath.Join(logDir, "core_types_20261018_100000.log"), []byte(jobLog), 0644); err != nil {
+		t.Fatal(err)
+	}
+	otherLog := "=== Job Log: api/routes ===\nStarted: 2026-10-18 10:05:00\n\nExit Code: 0\n"
+	if err := os.WriteFile(filepath.Join(logDir, "api_routes_20261018_100500.log"), []byte(otherLog), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &mockConfig{workDir: workDir, values: map[string]interface{}{
+		"logging.file.path": filepath.Join(workDir, "doing", "logs", "morty.log"),
+	}}
+	handler := NewLogsHandler(cfg, &mockLogger{})
+	var buf bytes.Buffer
+	handler.SetOutput(&buf)
+
+	result, err := handler.Execute(context.Background(), []string{"core/types", "--level", "error"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if result.Sources != 2 || result.Records != 1 {
+		t.Errorf("Expected 1 record from 2 sources, got %+v", result)
+	}
+	if out := buf.String(); !strings.Contains(out, "ERROR core/types | Exit Code: 1") {
+		t.Errorf("Unexpected output:\n%s", out)
+	}
+
+	buf.Reset()
+	if _, err := handler.Execute(context.Background(), []string{"core", "--tools-only"}); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, "| assistant") || strings.Contains(out, "api/routes") {
+		t.Errorf("Expected only the formatted tool event:\n%s", out)
+	}
+}
+
+func TestLogsHandler_NoLogs(t *testing.T) {
+	cfg := &mockConfig{workDir: filepath.Join(setupTestDir(t), ".morty"), values: map[string]interface{}{}}
+	handler := NewLogsHandler(cfg, &mockLogger{})
+	handler.SetOutput(&bytes.Buffer{})
+
+	if _, err := handler.Execute(context.Background(), nil); err == nil || !strings.Contains(err.Error(), "没有找到日志文件") {
+		t.Errorf("Expected missing logs error, got %v", err)
+	}
+
+	// --follow waits for logs to appear instead
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	handler.pollInterval = 10 * time.Millisecond
+	if _, err := handler.Execute(ctx, []string{"--follow"}); err != nil {
+		t.Errorf("Expected follow to stop cleanly, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/morty/morty/internal/callcli"
+	"github.com/morty/morty/internal/config"
+	"github.com/morty/morty/internal/logging"
+	"github.com/morty/morty/internal/metrics"
+)
+
+// Execution log rotation of the AI CLI execution logger.
+const (
+	executionLogMaxSize    = 10 * 1024 * 1024
+	executionLogMaxBackups = 5
+	executionLogMaxAge     = 7
+)
+
+// startMetrics creates the run's metrics recorder when metrics are enabled
+// and serves it on metrics.listen. The returned function records the end of
+// the run, writes metrics.textfile and stops the endpoint.
+func (h *DoingHandler) startMetrics() func(success bool) {
+	h.metrics = nil
+	if h.cfg == nil || !h.cfg.GetBool("metrics.enabled") {
+		return func(bool) {}
+	}
+	h.metrics = metrics.NewRecorder()
+	recorder := h.metrics
+
+	var server *http.Server
+	if addr := h.cfg.GetString("metrics.listen", config.DefaultMetricsListen); addr != "" {
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			h.logger.Warn("Metrics endpoint disabled", logging.String("error", err.Error()))
+		} else {
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", recorder.Registry().Handler())
+			server = &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+			go func() {
+				if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					h.logger.Warn("Metrics endpoint stopped", logging.String("error", err.Error()))
+				}
+			}()
+			h.logger.Info("Serving metrics", logging.String("address", "http://"+listener.Addr().String()+"/metrics"))
+		}
+	}
+
+	return func(success bool) {
+		recorder.RunFinished(time.Now(), success)
+		if h.executionLogger != nil {
+			stats := h.executionLogger.GetStats()
+			recorder.CLIStats(&stats)
+		}
+
+		if path := h.cfg.GetString("metrics.textfile", config.DefaultMetricsTextfile); path != "" {
+			if err := recorder.Registry().WriteTextfile(path); err != nil {
+				h.logger.Warn("Failed to write metrics textfile", logging.String("error", err.Error()))
+			}
+		}
+
+		if server != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			server.Shutdown(ctx)
+		}
+	}
+}
+
+// openExecutionLog opens the run's AI CLI execution log in the doing log
+// directory, whether or not metrics are enabled.
+func (h *DoingHandler) openExecutionLog() *callcli.ExecutionLogger {
+	if h.executionLogger != nil || h.cfg == nil {
+		return h.executionLogger
+	}
+	executionLogger, err := callcli.NewExecutionLogger(h.cfg.GetLogDir(), executionLogMaxSize, executionLogMaxBackups, executionLogMaxAge)
+	if err != nil {
+		h.logger.Warn("Execution log disabled", logging.String("error", err.Error()))
+		return nil
+	}
+	h.executionLogger = executionLogger
+	return executionLogger
+}
+
+// closeExecutionLog closes the run's execution log. It runs after the metrics
+// stop so they can still read its stats.
+func (h *DoingHandler) closeExecutionLog() {
+	if h.executionLogger != nil {
+		h.executionLogger.Close()
+		h.executionLogger = nil
+	}
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDoingHandler_Metrics tests that a run with metrics enabled writes the
+// textfile with its blocked jobs and run result.
+func TestDoingHandler_Metrics(t *testing.T) {
+	handler, _ := newContinueTestHandler(t, true)
+	textfile := filepath.Join(t.TempDir(), "morty.prom")
+	cfg := handler.cfg.(*mockConfig)
+	cfg.values["metrics.enabled"] = true
+	cfg.values["metrics.listen"] = "127.0.0.1:0"
+	cfg.values["metrics.textfile"] = textfile
+
+	handler.Execute(context.Background(), nil)
+
+	data, err := os.ReadFile(textfile)
+	if err != nil {
+		t.Fatalf("Metrics textfile not written: %v", err)
+	}
+	for _, want := range []string{`morty_jobs_total{status="BLOCKED"} 2`, "morty_last_run_success 0"} {
+		if !strings.Contains(string(data), want+"\n") {
+			t.Errorf("Expected %s in metrics:\n%s", want, data)
+		}
+	}
+	if handler.executionLogger != nil {
+		t.Error("Expected execution logger to be closed after the run")
+	}
+}
+
+func TestDoingHandler_MetricsDisabled(t *testing.T) {
+	handler, _ := newContinueTestHandler(t, true)
+
+	handler.Execute(context.Background(), nil)
+
+	if handler.metrics != nil {
+		t.Error("Expected no metrics recorder when metrics are disabled")
+	}
+}
+
+// TestDoingHandler_ExecutionLogWithoutMetrics tests that the execution log is
+// opened for a run even when metrics are disabled.
+func TestDoingHandler_ExecutionLogWithoutMetrics(t *testing.T) {
+	handler, _ := newContinueTestHandler(t, true)
+
+	if handler.openExecutionLog() == nil {
+		t.Fatal("Expected an execution logger with metrics disabled")
+	}
+	if _, err := os.Stat(handler.cfg.GetLogDir()); err != nil {
+		t.Errorf("Expected execution log directory to be created: %v", err)
+	}
+
+	handler.closeExecutionLog()
+	if handler.executionLogger != nil {
+		t.Error("Expected execution logger to be closed")
+	}
+}
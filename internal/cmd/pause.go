@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/morty/morty/internal/config"
+	"github.com/morty/morty/internal/logging"
+	"github.com/morty/morty/internal/state"
+)
+
+// PauseResult represents the result of the pause and resume commands.
+type PauseResult struct {
+	// Request is the pause request written, or the one removed by resume
+	Request *ControlRequest
+	// RunningJob is the job ("module/job") status.json shows as RUNNING
+	RunningJob string
+}
+
+// PauseHandler handles the pause and resume commands, which control a
+// running morty doing from another terminal through the control file.
+type PauseHandler struct {
+	cfg    config.Manager
+	logger logging.Logger
+	paths  *config.Paths
+	out    io.Writer
+}
+
+// NewPauseHandler creates a new PauseHandler instance.
+func NewPauseHandler(cfg config.Manager, logger logging.Logger) *PauseHandler {
+	var paths *config.Paths
+	if loader, ok := cfg.(*config.Loader); ok {
+		paths = config.NewPathsWithLoader(loader)
+	} else {
+		paths = config.NewPaths()
+	}
+	if cfg != nil && cfg.GetWorkDir() != "" {
+		paths.SetWorkDir(cfg.GetWorkDir())
+	}
+
+	return &PauseHandler{
+		cfg:    cfg,
+		logger: logger,
+		paths:  paths,
+		out:    os.Stdout,
+	}
+}
+
+// SetOutput sets the writer messages are printed to.
+func (h *PauseHandler) SetOutput(w io.Writer) {
+	h.out = w
+}
+
+// controlFile returns the path of the control file.
+func (h *PauseHandler) controlFile() string {
+	return filepath.Join(h.paths.GetWorkDir(), controlFileName)
+}
+
+// Pause asks a running morty doing to pause after the current job, or with
+// --now to interrupt the current job, and wait for morty resume.
+func (h *PauseHandler) Pause(ctx context.Context, args []string) (*PauseResult, error) {
+	logger := h.logger.WithContext(ctx)
+
+	req := &ControlRequest{Action: ControlPause, RequestedAt: time.Now()}
+	for _, arg := range args {
+		switch arg {
+		case "--now", "-now":
+			req.Action = ControlInterrupt
+		default:
+			return nil, fmt.Errorf("未知参数: %s", arg)
+		}
+	}
+
+	if err := writeControlRequest(h.controlFile(), req); err != nil {
+		return nil, fmt.Errorf("写入控制文件失败: %w", err)
+	}
+	result := &PauseResult{Request: req, RunningJob: h.runningJob()}
+	logger.Info("Pause requested",
+		logging.String("action", req.Action),
+		logging.String("running_job", result.RunningJob),
+	)
+
+	switch {
+	case result.RunningJob == "":
+		fmt.Fprintln(h.out, "⏸️  已写入暂停请求，但当前没有正在执行的 Job")
+		fmt.Fprintln(h.out, "   下次启动 morty doing 时会清除该请求")
+	case req.Action == ControlInterrupt:
+		fmt.Fprintf(h.out, "⏸️  已请求立即中断 Job %s 并暂停\n", result.RunningJob)
+		fmt.Fprintln(h.out, "   运行 morty resume 从中断处继续")
+	default:
+		fmt.Fprintf(h.out, "⏸️  已请求暂停: Job %s 完成后暂停\n", result.RunningJob)
+		fmt.Fprintln(h.out, "   运行 morty resume 继续")
+	}
+	return result, nil
+}
+
+// Resume removes the pause request, so a paused morty doing continues.
+func (h *PauseHandler) Resume(ctx context.Context, args []string) (*PauseResult, error) {
+	logger := h.logger.WithContext(ctx)
+	if len(args) > 0 {
+		return nil, fmt.Errorf("未知参数: %s", args[0])
+	}
+
+	path := h.controlFile()
+	req, err := readControlRequest(path)
+	if err != nil {
+		return nil, err
+	}
+	if req == nil {
+		return nil, fmt.Errorf("没有暂停请求 (%s 不存在)", path)
+	}
+	if err := os.Remove(path); err != nil {
+		return nil, fmt.Errorf("删除控制文件失败: %w", err)
+	}
+	logger.Info("Pause request removed", logging.String("action", req.Action))
+
+	fmt.Fprintln(h.out, "▶️  已移除暂停请求，暂停中的 morty doing 将继续执行")
+	return &PauseResult{Request: req}, nil
+}
+
+// runningJob returns the job status.json shows as RUNNING, or "".
+func (h *PauseHandler) runningJob() string {
+	statusFile := filepath.Join(h.paths.GetWorkDir(), "status.json")
+	if h.cfg != nil {
+		statusFile = h.cfg.GetStatusFile()
+	}
+	manager := state.NewManager(statusFile)
+	if err := manager.Load(); err != nil {
+		return ""
+	}
+	status := manager.GetStatus()
+	if status == nil {
+		return ""
+	}
+	for _, module := range status.Modules {
+		for _, job := range module.Jobs {
+			if job.Status == state.StatusRunning {
+				return module.Name + "/" + job.Name
+			}
+		}
+	}
+	return ""
+}
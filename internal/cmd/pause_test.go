@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/morty/morty/internal/callcli"
+	"github.com/morty/morty/internal/doing"
+	"github.com/morty/morty/internal/executor"
+	"github.com/morty/morty/internal/state"
+)
+
+// saveRunningJob writes a status.json with core/parser RUNNING.
+func saveRunningJob(t *testing.T, cfg *mockConfig) *state.Manager {
+	t.Helper()
+	manager := state.NewManager(cfg.GetStatusFile())
+	if err := manager.Save(&state.ExecutionStatus{Version: "2.0", Modules: []state.ModuleState{
+		{Name: "core", Jobs: []state.JobState{
+			{Name: "types", Status: state.StatusCompleted},
+			{Name: "parser", Status: state.StatusRunning, TasksTotal: 3, TasksCompleted: 1},
+		}},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	return manager
+}
+
+func TestPauseHandler_PauseResume(t *testing.T) {
+	cfg := &mockConfig{}
+	cfg.SetWorkDir(setupTestDir(t))
+	saveRunningJob(t, cfg)
+	var out bytes.Buffer
+	handler := NewPauseHandler(cfg, &mockLogger{})
+	handler.SetOutput(&out)
+	controlFile := filepath.Join(cfg.GetWorkDir(), controlFileName)
+
+	result, err := handler.Pause(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Pause() error: %v", err)
+	}
+	if result.RunningJob != "core/parser" || !strings.Contains(out.String(), "Job core/parser 完成后暂停") {
+		t.Errorf("Unexpected pause result %+v:\n%s", result, out.String())
+	}
+	if req, err := readControlRequest(controlFile); err != nil || req == nil || req.Action != ControlPause {
+		t.Errorf("control file = %+v, %v", req, err)
+	}
+
+	if _, err := handler.Pause(context.Background(), []string{"--now"}); err != nil {
+		t.Fatalf("Pause(--now) error: %v", err)
+	}
+	if req, _ := readControlRequest(controlFile); req == nil || req.Action != ControlInterrupt {
+		t.Errorf("control file after --now = %+v", req)
+	}
+
+	result, err = handler.Resume(context.Background(), nil)
+	if err != nil || result.Request.Action != ControlInterrupt {
+		t.Fatalf("Resume() = %+v, %v", result, err)
+	}
+	if _, err := os.Stat(controlFile); !os.IsNotExist(err) {
+		t.Errorf("control file still exists after resume")
+	}
+	if _, err := handler.Resume(context.Background(), nil); err == nil {
+		t.Error("Expected an error resuming without a pause request")
+	}
+	if _, err := handler.Pause(context.Background(), []string{"--later"}); err == nil {
+		t.Error("Expected an error for an unknown option")
+	}
+}
+
+func TestRunControl_ControlFile(t *testing.T) {
+	cfg := &mockConfig{}
+	cfg.SetWorkDir(setupTestDir(t))
+	controlFile := filepath.Join(cfg.GetWorkDir(), controlFileName)
+	// A request left by an earlier run is dropped
+	writeControlRequest(controlFile, &ControlRequest{Action: ControlPause})
+
+	control := NewDoingHandler(cfg, &mockLogger{}).startRunControl()
+	defer control.close()
+	if _, err := os.Stat(controlFile); !os.IsNotExist(err) {
+		t.Fatalf("stale control file was not removed")
+	}
+
+	ctx := context.Background()
+	if !control.proceed(ctx) {
+		t.Fatal("proceed() = false without a request")
+	}
+
+	jobCtx, endJob := control.beginJob(ctx)
+	writeControlRequest(controlFile, &ControlRequest{Action: ControlInterrupt, RequestedAt: time.Now()})
+	control.poll()
+	if jobCtx.Err() == nil {
+		t.Fatal("job was not interrupted")
+	}
+	endJob()
+
+	resumed := make(chan bool)
+	go func() { resumed <- control.proceed(ctx) }()
+	select {
+	case <-resumed:
+		t.Fatal("proceed() returned while paused")
+	case <-time.After(2 * controlPollInterval):
+	}
+	os.Remove(controlFile)
+	select {
+	case ok := <-resumed:
+		if !ok {
+			t.Error("proceed() = false after resume")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("proceed() did not return after resume")
+	}
+}
+
+func TestRunControl_Signals(t *testing.T) {
+	cfg := &mockConfig{}
+	cfg.SetWorkDir(setupTestDir(t))
+	control := NewDoingHandler(cfg, &mockLogger{}).startRunControl()
+	defer control.close()
+
+	ctx := context.Background()
+	jobCtx, endJob := control.beginJob(ctx)
+	defer endJob()
+
+	// The first signal lets the job finish
+	control.handleSignal(syscall.SIGINT)
+	if jobCtx.Err() != nil {
+		t.Fatal("first signal interrupted the job")
+	}
+	if control.proceed(ctx) {
+		t.Error("proceed() = true after a signal")
+	}
+
+	// The second one interrupts it
+	control.handleSignal(syscall.SIGINT)
+	if jobCtx.Err() == nil {
+		t.Error("second signal did not interrupt the job")
+	}
+}
+
+func TestDoingHandler_RecordInterrupt(t *testing.T) {
+	cfg := &mockConfig{}
+	cfg.SetWorkDir(setupTestDir(t))
+	manager := saveRunningJob(t, cfg)
+
+	handler := NewDoingHandler(cfg, &mockLogger{})
+	handler.stateManager = manager
+	interrupted := &executor.InterruptedError{
+		Interrupt: &callcli.InterruptState{Duration: 90 * time.Second},
+		Err:       errors.New("context cancelled during execution"),
+	}
+	if err := handler.recordInterrupt("core", "parser", interrupted); err != nil {
+		t.Fatalf("recordInterrupt() error: %v", err)
+	}
+
+	job := manager.GetJob("core", "parser")
+	if job.Status != state.StatusInterrupted || job.FailureReason != "job interrupted: context cancelled during execution (AI CLI stopped after 1m30s)" {
+		t.Errorf("job = %s, %q", job.Status, job.FailureReason)
+	}
+	if m, j := manager.GetStatus().GetNextPendingJob(); m != 0 || j != 1 {
+		t.Errorf("GetNextPendingJob() = %d, %d; want the interrupted job", m, j)
+	}
+
+	// The recovery point holds the job as it was when interrupted
+	points, _ := filepath.Glob(filepath.Join(cfg.GetWorkDir(), "recovery", "core_parser_*.json"))
+	if len(points) != 1 {
+		t.Fatalf("recovery points = %v", points)
+	}
+	data, _ := os.ReadFile(points[0])
+	var point doing.RecoveryPoint
+	if err := json.Unmarshal(data, &point); err != nil || point.JobStatus != state.StatusRunning {
+		t.Errorf("recovery point = %+v, %v; want status RUNNING", point, err)
+	}
+
+	// A job that is no longer running cannot be interrupted again
+	if err := handler.recordInterrupt("core", "parser", interrupted); err == nil {
+		t.Error("recordInterrupt() of an INTERRUPTED job succeeded")
+	}
+	if job := manager.GetJob("core", "parser"); job.Status != state.StatusInterrupted {
+		t.Errorf("job status = %s after a second interrupt", job.Status)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/morty/morty/internal/config"
 	"github.com/morty/morty/internal/logging"
 	"github.com/morty/morty/internal/parser/plan"
+	"github.com/morty/morty/internal/research"
 	"github.com/morty/morty/internal/validator"
 )
 
@@ -27,6 +28,8 @@ type PlanResult struct {
 	ExitCode    int
 	Duration    time.Duration
 	Overwritten bool
+	PlanFiles   []string      // files written by a headless run
+	Repair      *RepairResult // set when --repair ran
 }
 
 // PlanHandler handles the plan command.
@@ -50,14 +53,8 @@ func NewPlanHandler(cfg config.Manager, logger logging.Logger, parser interface{
 	var paths *config.Paths
 	if loader, ok := cfg.(*config.Loader); ok {
 		paths = config.NewPathsWithLoader(loader)
-		if os.Getenv("MORTY_DEBUG") != "" {
-			fmt.Fprintf(os.Stderr, "DEBUG: NewPlanHandler using Loader\n")
-		}
 	} else {
 		paths = config.NewPaths()
-		if os.Getenv("MORTY_DEBUG") != "" {
-			fmt.Fprintf(os.Stderr, "DEBUG: NewPlanHandler using NewPaths (cfg type: %T)\n", cfg)
-		}
 	}
 
 	// Set workDir from config if available
@@ -85,7 +82,18 @@ func (h *PlanHandler) Execute(ctx context.Context, args []string) (*PlanResult,
 	}
 
 	// Parse options from args
-	force, moduleName, _ := h.parseOptions(args)
+	force, moduleName, remaining := h.parseOptions(args)
+	repair, remaining, err := h.parseRepairOptions(remaining)
+	if err != nil {
+		return result, err
+	}
+	headless, jobs, err := parseHeadlessOptions(remaining)
+	if err != nil {
+		return result, err
+	}
+	if repair.Enabled && !headless.Enabled {
+		return result, fmt.Errorf("--repair 需要与 --headless 一起使用，交互模式下请运行 morty plan validate --repair")
+	}
 
 	// Determine module name
 	if moduleName == "" {
@@ -106,6 +114,10 @@ func (h *PlanHandler) Execute(ctx context.Context, args []string) (*PlanResult,
 		return result, fmt.Errorf("failed to create plan directory: %w", err)
 	}
 
+	if headless.Enabled {
+		return h.executeHeadless(ctx, result, force, headless, repair)
+	}
+
 	// Generate plan file path
 	planPath := h.generatePlanPath(moduleName)
 	result.PlanPath = planPath
@@ -141,26 +153,19 @@ func (h *PlanHandler) Execute(ctx context.Context, args []string) (*PlanResult,
 	default:
 	}
 
-	// Load plan prompt
-	prompt, err := h.loadPlanPrompt()
+	// Render plan prompt with the research context
+	prompt, err := h.planPrompt(ctx, moduleName, HeadlessOptions{})
 	if err != nil {
-		logger.Error("Failed to load plan prompt", logging.String("error", err.Error()))
+		logger.Error("Failed to render plan prompt", logging.String("error", err.Error()))
 		result.Err = err
 		result.Duration = time.Since(startTime)
-		return result, fmt.Errorf("failed to load plan prompt: %w", err)
+		return result, fmt.Errorf("failed to render plan prompt: %w", err)
 	}
 
-	logger.Info("Loaded plan prompt", logging.String("prompt_path", h.getPlanPromptPath()))
-
-	// Load research files
-	researchContent, err := h.loadResearchFiles()
-	if err != nil {
-		logger.Warn("Failed to load research files (continuing anyway)", logging.String("error", err.Error()))
-		researchContent = "No research files found."
-	}
+	logger.Info("Rendered plan prompt", logging.String("prompt_path", h.getPlanPromptPath()))
 
 	// Execute Claude Code to generate plan
-	planContent, exitCode, err := h.executeClaudeCodeForPlan(ctx, moduleName, prompt, researchContent)
+	planContent, exitCode, err := h.executeClaudeCodeForPlan(ctx, moduleName, prompt)
 	if err != nil {
 		logger.Error("Claude Code execution failed",
 			logging.String("error", err.Error()),
@@ -171,8 +176,15 @@ func (h *PlanHandler) Execute(ctx context.Context, args []string) (*PlanResult,
 		return result, fmt.Errorf("claude code execution failed: %w", err)
 	}
 
-	// Write the generated plan to file
-	if err := h.writePlanFile(planPath, planContent); err != nil {
+	// In interactive mode the AI CLI writes the plan files itself, so only
+	// write captured output; fall back to the plan skeleton when the
+	// session left no plan for the module.
+	if planContent != "" {
+		err = h.writePlanFile(planPath, planContent)
+	} else if !h.planFileExists(planPath) {
+		err = h.createPlanFile(planPath, moduleName, jobs)
+	}
+	if err != nil {
 		logger.Error("Failed to write plan file", logging.String("error", err.Error()))
 		result.Err = err
 		result.Duration = time.Since(startTime)
@@ -328,6 +340,20 @@ func (h *PlanHandler) promptForOverwrite(moduleName string) (bool, error) {
 	return response == "y" || response == "yes", nil
 }
 
+// planPrompt renders the plan phase prompt for the module with the research
+// context; headless runs also get the seed requirements and the output
+// format.
+func (h *PlanHandler) planPrompt(ctx context.Context, moduleName string, opts HeadlessOptions) (string, error) {
+	data, err := seedData(opts.Input)
+	if err != nil {
+		return "", err
+	}
+	data["module"] = moduleName
+	data["headless"] = opts.Enabled
+	data["research"] = h.researchContext(ctx, strings.TrimSpace(moduleName+" "+data["seed"].(string)))
+	return newPromptEngine(h.cfg, h.paths).Render("phases/plan.md", data)
+}
+
 // loadPlanPrompt loads the plan prompt from prompts/plan.md.
 func (h *PlanHandler) loadPlanPrompt() (string, error) {
 	promptPath := h.getPlanPromptPath()
@@ -351,15 +377,24 @@ func (h *PlanHandler) getPlanPromptPath() string {
 		if promptPath := h.cfg.GetString("prompts.plan"); promptPath != "" {
 			// If it's an absolute path, use it directly
 			if filepath.IsAbs(promptPath) {
-				return promptPath
+				return config.LocalizedPromptPath(promptPath, promptLanguage(h.cfg))
 			}
 			// If it's a relative path, resolve it relative to prompts dir
-			return filepath.Join(promptsDir, filepath.Base(promptPath))
+			return config.LocalizedPromptPath(filepath.Join(promptsDir, filepath.Base(promptPath)), promptLanguage(h.cfg))
 		}
 	}
 
 	// Default to plan.md in prompts directory
-	return filepath.Join(promptsDir, "plan.md")
+	return config.LocalizedPromptPath(filepath.Join(promptsDir, "plan.md"), promptLanguage(h.cfg))
+}
+
+// promptLanguage returns the configured plan language used to pick
+// localized prompt files, e.g. plan.en.md.
+func promptLanguage(cfg config.Manager) string {
+	if cfg == nil {
+		return ""
+	}
+	return cfg.GetString("plan.language")
 }
 
 // loadResearchFiles loads all research files from .morty/research/ directory.
@@ -402,12 +437,54 @@ func (h *PlanHandler) loadResearchFiles() (string, error) {
 	return content.String(), nil
 }
 
-// executeClaudeCodeForPlan executes Claude Code to generate a plan.
-func (h *PlanHandler) executeClaudeCodeForPlan(ctx context.Context, moduleName, prompt, researchContent string) (string, int, error) {
+// researchContext returns the research for a plan prompt: every research
+// file while they fit in research.max_chars, otherwise the research.top_k
+// sections most relevant to query.
+func (h *PlanHandler) researchContext(ctx context.Context, query string) string {
 	logger := h.logger.WithContext(ctx)
 
-	// Build the full prompt with module name and research context
-	fullPrompt := fmt.Sprintf("# Plan Module: %s\n\n%s\n\n%s", moduleName, researchContent, prompt)
+	maxChars, topK := config.DefaultResearchMaxChars, config.DefaultResearchTopK
+	if h.cfg != nil {
+		maxChars = h.cfg.GetInt("research.max_chars", maxChars)
+		topK = h.cfg.GetInt("research.top_k", topK)
+	}
+
+	ix, err := research.Build(h.paths.GetResearchDir())
+	if err == nil && researchSize(ix) > maxChars {
+		hits := ix.Search(query, topK)
+		logger.Info("Selected research sections",
+			logging.Int("selected", len(hits)),
+			logging.Int("sections", ix.Len()),
+			logging.Any("citations", research.Citations(hits)),
+		)
+		if len(hits) == 0 {
+			return "No relevant research found."
+		}
+		return fmt.Sprintf("# Research Sections\n\n%d of %d research sections, selected by relevance. Cite them by number.\n\n%s",
+			len(hits), ix.Len(), research.Format(hits))
+	}
+
+	content, err := h.loadResearchFiles()
+	if err != nil {
+		logger.Warn("Failed to load research files (continuing anyway)", logging.String("error", err.Error()))
+		return "No research files found."
+	}
+	return content
+}
+
+// researchSize returns the total text size of the indexed research.
+func researchSize(ix *research.Index) int {
+	size := 0
+	for _, s := range ix.Sections() {
+		size += len(s.Text)
+	}
+	return size
+}
+
+// executeClaudeCodeForPlan executes Claude Code with the rendered prompt to
+// generate a plan.
+func (h *PlanHandler) executeClaudeCodeForPlan(ctx context.Context, moduleName, fullPrompt string) (string, int, error) {
+	logger := h.logger.WithContext(ctx)
 
 	logger.Info("Executing Claude Code in interactive mode for plan generation",
 		logging.String("module", moduleName),
@@ -448,6 +525,94 @@ func (h *PlanHandler) executeClaudeCodeForPlan(ctx context.Context, moduleName,
 	return "", result.ExitCode, nil
 }
 
+// executeHeadless generates the plans without a TTY, writes the files the
+// agent outputs to the plan directory and validates them. Existing files
+// are only replaced with --force since nobody can confirm. With --repair,
+// validation errors are fed back to the AI CLI.
+func (h *PlanHandler) executeHeadless(ctx context.Context, result *PlanResult, force bool, opts HeadlessOptions, repair RepairOptions) (*PlanResult, error) {
+	logger := h.logger.WithContext(ctx)
+	fail := func(err error) (*PlanResult, error) {
+		result.Err = err
+		result.Duration = time.Since(result.CreatedAt)
+		return result, err
+	}
+
+	fullPrompt, err := h.planPrompt(ctx, result.ModuleName, opts)
+	if err != nil {
+		return fail(fmt.Errorf("failed to render plan prompt: %w", err))
+	}
+	timeout := headlessTimeout(h.cfg, opts)
+
+	logger.Info("Executing Claude Code in headless mode for plan generation",
+		logging.String("module", result.ModuleName),
+		logging.String("cli_path", h.cliCaller.GetCLIPath()),
+		logging.Any("timeout", timeout),
+	)
+
+	output, exitCode, err := runHeadless(ctx, h.cliCaller, fullPrompt, timeout)
+	result.ExitCode = exitCode
+	if err != nil {
+		logger.Error("Claude Code execution failed",
+			logging.String("error", err.Error()),
+			logging.Int("exit_code", exitCode),
+		)
+		return fail(fmt.Errorf("claude code execution failed: %w", err))
+	}
+
+	files, err := splitPlanFiles(output)
+	if err != nil {
+		return fail(err)
+	}
+
+	// Check every target first so a refused run writes nothing
+	planDir := h.getPlanDir()
+	var existing []string
+	for _, file := range files {
+		if h.planFileExists(filepath.Join(planDir, file.Name)) {
+			existing = append(existing, file.Name)
+		}
+	}
+	if len(existing) > 0 && !force {
+		return fail(fmt.Errorf("Plan 文件已存在: %s (使用 --force 覆盖)", strings.Join(existing, ", ")))
+	}
+	result.Overwritten = len(existing) > 0
+
+	for _, file := range files {
+		path := filepath.Join(planDir, file.Name)
+		if err := h.writePlanFile(path, file.Content); err != nil {
+			return fail(err)
+		}
+		result.PlanFiles = append(result.PlanFiles, path)
+	}
+	result.PlanPath = planDir
+
+	v := h.newValidator(planDir, false)
+	results, err := v.ValidateAll()
+	if err != nil {
+		return fail(fmt.Errorf("failed to validate plan files: %w", err))
+	}
+	if !allPassed(results) && repair.Enabled {
+		result.Repair, err = h.repairPlans(ctx, results, v.ValidateAll, repair.Attempts, timeout)
+		if err != nil {
+			return fail(err)
+		}
+		results = result.Repair.Results
+		fmt.Print(formatRepair(result.Repair))
+	}
+	if !allPassed(results) {
+		return fail(fmt.Errorf("生成的 Plan 未通过校验:\n%s", validator.FormatResults(results, false)))
+	}
+
+	result.Duration = time.Since(result.CreatedAt)
+	logger.Info("Plan creation completed",
+		logging.String("module", result.ModuleName),
+		logging.Int("files", len(result.PlanFiles)),
+		logging.Bool("overwritten", result.Overwritten),
+		logging.Any("duration", result.Duration),
+	)
+	return result, nil
+}
+
 // writePlanFile writes the generated plan content to a file.
 func (h *PlanHandler) writePlanFile(planPath, content string) error {
 	// Ensure parent directory exists
@@ -464,7 +629,8 @@ func (h *PlanHandler) writePlanFile(planPath, content string) error {
 	return nil
 }
 
-// createPlanFile creates a new plan file with the given content (legacy template method).
+// createPlanFile creates a plan skeleton with one job per name in args.
+// Execute falls back to it when the interactive session wrote no plan.
 func (h *PlanHandler) createPlanFile(planPath, moduleName string, args []string) error {
 	// Ensure parent directory exists
 	dir := filepath.Dir(planPath)
@@ -527,6 +693,20 @@ func (h *PlanHandler) SetPlanDir(dir string) {
 	h.paths.SetWorkDir(dir)
 }
 
+// newValidator creates a plan validator that also checks the files plans
+// reference.
+func (h *PlanHandler) newValidator(planDir string, verbose bool) *validator.PlanValidator {
+	workDir := h.paths.GetWorkDir()
+	if h.cfg != nil {
+		workDir = h.cfg.GetWorkDir()
+	}
+	refs := referenceResolver(workDir, h.getResearchDir())
+
+	v := validator.NewPlanValidator(planDir, verbose)
+	v.SetReferenceRoot(refs.Root, refs.ResearchDir)
+	return v
+}
+
 // getResearchDir returns the research directory path.
 func (h *PlanHandler) getResearchDir() string {
 	if h.cfg != nil {
@@ -696,13 +876,13 @@ func (h *PlanHandler) ValidatePlanResult() (*PlanValidationResult, error) {
 		}
 
 		name := entry.Name()
-		// Skip README.md and non-markdown files
-		if name == "README.md" || !strings.HasSuffix(name, ".md") {
+		// Skip README.md and non-plan files
+		if !plan.IsPlanFile(name) {
 			continue
 		}
 
 		filePath := filepath.Join(planDir, name)
-		moduleName := strings.TrimSuffix(name, ".md")
+		moduleName := plan.ModuleNameFromFile(name)
 
 		// Read and parse the plan file
 		content, err := os.ReadFile(filePath)
@@ -713,7 +893,7 @@ func (h *PlanHandler) ValidatePlanResult() (*PlanValidationResult, error) {
 		}
 
 		// Parse the plan file using Plan Parser
-		plan, err := h.parsePlanFile(string(content))
+		plan, err := h.parsePlanFile(string(content), name)
 		if err != nil {
 			result.ParseErrors = append(result.ParseErrors,
 				fmt.Sprintf("Failed to parse %s: %v", name, err))
@@ -756,9 +936,10 @@ func (h *PlanHandler) ValidatePlanResult() (*PlanValidationResult, error) {
 }
 
 // parsePlanFile parses a plan file content and returns the parsed plan.
-// This is a helper method that wraps the plan parser.
-func (h *PlanHandler) parsePlanFile(content string) (*plan.Plan, error) {
-	return plan.ParsePlan(content)
+// This is a helper method that wraps the plan parser; the format is
+// chosen from the file name's extension.
+func (h *PlanHandler) parsePlanFile(content, fileName string) (*plan.Plan, error) {
+	return plan.ParsePlanAs(content, plan.FormatFromPath(fileName))
 }
 
 // validatePlanResult validates the plan result and returns an error if validation fails.
@@ -895,6 +1076,10 @@ func (h *PlanHandler) Validate(ctx context.Context, args []string) (*ValidateRes
 	logger := h.logger.WithContext(ctx)
 
 	// Parse options
+	repair, args, err := h.parseRepairOptions(args)
+	if err != nil {
+		return &ValidateResult{Success: false, Message: err.Error(), Err: err}, err
+	}
 	verbose := false
 	fix := false
 	targetFile := ""
@@ -915,50 +1100,80 @@ func (h *PlanHandler) Validate(ctx context.Context, args []string) (*ValidateRes
 	planDir := h.getPlanDir()
 
 	// Create validator
-	v := validator.NewPlanValidator(planDir, verbose)
+	v := h.newValidator(planDir, verbose)
+
+	validate := func() ([]*validator.ValidationResult, error) {
+		if targetFile != "" {
+			// Validate single file
+			filePath := targetFile
+			if !filepath.IsAbs(targetFile) {
+				filePath = filepath.Join(planDir, targetFile)
+			}
+			return []*validator.ValidationResult{v.ValidateFile(filePath)}, nil
+		}
+		// Validate all files
+		return v.ValidateAll()
+	}
 
-	var results []*validator.ValidationResult
-	var err error
+	results, err := validate()
+	if err != nil {
+		logger.Error("Failed to validate plan files", logging.String("error", err.Error()))
+		return &ValidateResult{
+			Success: false,
+			Message: fmt.Sprintf("Failed to validate: %v", err),
+			Err:     err,
+		}, err
+	}
 
-	if targetFile != "" {
-		// Validate single file
-		filePath := targetFile
-		if !filepath.IsAbs(targetFile) {
-			filePath = filepath.Join(planDir, targetFile)
+	// Auto-fix if requested and validation failed: format the plans, then
+	// report what is left for manual fixing
+	fixMessage := ""
+	if fix && !allPassed(results) {
+		logger.Info("Attempting to auto-fix format issues")
+		var fmtArgs []string
+		if targetFile != "" {
+			fmtArgs = []string{targetFile}
 		}
-		result := v.ValidateFile(filePath)
-		results = []*validator.ValidationResult{result}
-	} else {
-		// Validate all files
-		results, err = v.ValidateAll()
+		fmtResult, fmtErr := h.Fmt(ctx, fmtArgs)
+		if fmtErr != nil {
+			fixMessage = fmt.Sprintf("\n⚠️  自动修复失败: %v\n", fmtErr)
+		} else {
+			fixMessage = "\n" + fmtResult.Message
+			// The file may have been renamed to fix its casing
+			if targetFile != "" && len(fmtResult.Files) == 1 {
+				if abs, err := filepath.Abs(fmtResult.Files[0].Target); err == nil {
+					targetFile = abs
+				}
+			}
+			if results, err = validate(); err != nil {
+				return &ValidateResult{
+					Success: false,
+					Message: fmt.Sprintf("Failed to validate: %v", err),
+					Err:     err,
+				}, err
+			}
+		}
+	}
+
+	// Ask the AI CLI to fix what is left
+	repairMessage := ""
+	if repair.Enabled && !allPassed(results) {
+		repairResult, err := h.repairPlans(ctx, results, validate, repair.Attempts, headlessTimeout(h.cfg, HeadlessOptions{}))
 		if err != nil {
-			logger.Error("Failed to validate plan files", logging.String("error", err.Error()))
+			logger.Error("Failed to repair plan files", logging.String("error", err.Error()))
 			return &ValidateResult{
 				Success: false,
-				Message: fmt.Sprintf("Failed to validate: %v", err),
+				Message: fmt.Sprintf("Failed to repair: %v", err),
 				Err:     err,
 			}, err
 		}
+		results = repairResult.Results
+		repairMessage = "\n" + formatRepair(repairResult)
 	}
 
 	// Format results
-	message := validator.FormatResults(results, verbose)
-
-	// Check if validation passed
-	success := true
-	for _, result := range results {
-		if !result.Passed {
-			success = false
-			break
-		}
-	}
-
-	// Auto-fix if requested and validation failed
-	if fix && !success {
-		logger.Info("Attempting to auto-fix format issues")
-		// TODO: Implement auto-fix logic
-		message += "\n\n⚠️  Auto-fix is not yet implemented. Please fix errors manually."
-	}
+	message := validator.FormatResults(results, verbose) + fixMessage + repairMessage
+	success := allPassed(results)
 
 	return &ValidateResult{
 		Success: success,
@@ -966,3 +1181,13 @@ func (h *PlanHandler) Validate(ctx context.Context, args []string) (*ValidateRes
 		Err:     nil,
 	}, nil
 }
+
+// allPassed reports whether every validation result passed.
+func allPassed(results []*validator.ValidationResult) bool {
+	for _, result := range results {
+		if !result.Passed {
+			return false
+		}
+	}
+	return true
+}
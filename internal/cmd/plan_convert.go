@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/morty/morty/internal/logging"
+	"github.com/morty/morty/internal/parser/plan"
+)
+
+// ConvertOptions holds the parsed plan convert options.
+type ConvertOptions struct {
+	To      plan.Format // --to md|json|yaml
+	Output  string      // --output file, directory or "-" for stdout
+	InPlace bool        // --in-place: remove the source after converting
+	Force   bool        // --force: overwrite existing targets
+	Files   []string    // Source files (all plan files if empty)
+}
+
+// ConvertedFile describes a single converted plan.
+type ConvertedFile struct {
+	Module string
+	Source string
+	Target string // "-" when written to stdout
+}
+
+// ConvertResult represents the result of plan conversion.
+type ConvertResult struct {
+	Files   []ConvertedFile
+	Skipped []string // Sources already in the target format
+	Message string
+}
+
+// Convert converts plan files between markdown, JSON and YAML.
+// Every source is parsed and re-encoded through plan.Plan, so the output
+// carries exactly what the parser understood.
+func (h *PlanHandler) Convert(ctx context.Context, args []string) (*ConvertResult, error) {
+	return h.convert(ctx, args, os.Stdout)
+}
+
+// convert implements Convert with a configurable stdout writer.
+func (h *PlanHandler) convert(ctx context.Context, args []string, stdout io.Writer) (*ConvertResult, error) {
+	logger := h.logger.WithContext(ctx)
+
+	opts, err := parseConvertOptions(args)
+	if err != nil {
+		return nil, err
+	}
+
+	planDir := h.getPlanDir()
+	sources, err := h.convertSources(planDir, opts.Files)
+	if err != nil {
+		return nil, err
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("计划目录中没有计划文件: %s", planDir)
+	}
+
+	// A directory (or stdout) is required when converting several files
+	outputIsDir := false
+	if opts.Output != "" && opts.Output != "-" {
+		if info, err := os.Stat(opts.Output); err == nil && info.IsDir() {
+			outputIsDir = true
+		} else if len(sources) > 1 {
+			if err := os.MkdirAll(opts.Output, 0755); err != nil {
+				return nil, fmt.Errorf("创建输出目录失败: %w", err)
+			}
+			outputIsDir = true
+		}
+	}
+	if opts.Output == "-" && len(sources) > 1 {
+		return nil, fmt.Errorf("输出到 stdout 时只能转换单个文件")
+	}
+	if opts.InPlace && opts.Output != "" {
+		return nil, fmt.Errorf("--in-place 不能与 --output 同时使用")
+	}
+
+	result := &ConvertResult{}
+	for _, source := range sources {
+		if plan.FormatFromPath(source) == opts.To && opts.Output == "" {
+			result.Skipped = append(result.Skipped, source)
+			continue
+		}
+
+		planData, err := plan.ParsePlanFile(source)
+		if err != nil {
+			return result, fmt.Errorf("解析计划文件失败 %s: %w", source, err)
+		}
+
+		data, err := plan.Marshal(planData, opts.To)
+		if err != nil {
+			return result, fmt.Errorf("转换计划文件失败 %s: %w", source, err)
+		}
+
+		module := plan.ModuleNameFromFile(source)
+		target := convertTarget(source, module, opts, outputIsDir)
+
+		if target == "-" {
+			if _, err := stdout.Write(data); err != nil {
+				return result, err
+			}
+		} else {
+			if _, err := os.Stat(target); err == nil && !opts.Force {
+				return result, fmt.Errorf("目标文件已存在: %s (使用 --force 覆盖)", target)
+			}
+			if err := os.WriteFile(target, data, 0644); err != nil {
+				return result, fmt.Errorf("写入目标文件失败: %w", err)
+			}
+			// Keep a single plan file per module
+			if opts.InPlace && target != source {
+				if err := os.Remove(source); err != nil {
+					return result, fmt.Errorf("删除源文件失败: %w", err)
+				}
+			}
+		}
+
+		// Debug level: converted plans may be written to stdout
+		logger.Debug("Plan converted",
+			logging.String("module", module),
+			logging.String("source", source),
+			logging.String("target", target),
+			logging.String("format", string(opts.To)),
+		)
+		result.Files = append(result.Files, ConvertedFile{Module: module, Source: source, Target: target})
+	}
+
+	result.Message = formatConvertResult(result, opts)
+	return result, nil
+}
+
+// convertSources resolves the files to convert. Relative paths are looked up
+// in the plan directory first; with no files, every plan file is converted.
+func (h *PlanHandler) convertSources(planDir string, files []string) ([]string, error) {
+	if len(files) == 0 {
+		entries, err := os.ReadDir(planDir)
+		if err != nil {
+			return nil, fmt.Errorf("读取计划目录失败: %w", err)
+		}
+		var sources []string
+		for _, entry := range entries {
+			if !entry.IsDir() && plan.IsPlanFile(entry.Name()) {
+				sources = append(sources, filepath.Join(planDir, entry.Name()))
+			}
+		}
+		return sources, nil
+	}
+
+	sources := make([]string, 0, len(files))
+	for _, file := range files {
+		path := file
+		if !filepath.IsAbs(file) {
+			if _, err := os.Stat(file); err != nil {
+				path = filepath.Join(planDir, file)
+			}
+		}
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("计划文件不存在: %s", file)
+		}
+		sources = append(sources, path)
+	}
+	return sources, nil
+}
+
+// convertTarget returns where the converted plan is written.
+func convertTarget(source, module string, opts ConvertOptions, outputIsDir bool) string {
+	fileName := module + opts.To.Extension()
+	switch {
+	case opts.Output == "-":
+		return "-"
+	case outputIsDir:
+		return filepath.Join(opts.Output, fileName)
+	case opts.Output != "":
+		return opts.Output
+	}
+	return filepath.Join(filepath.Dir(source), fileName)
+}
+
+// parseConvertOptions parses plan convert arguments.
+func parseConvertOptions(args []string) (ConvertOptions, error) {
+	var opts ConvertOptions
+	to := ""
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		name, value, hasValue := strings.Cut(arg, "=")
+		needValue := func() (string, error) {
+			if hasValue {
+				return value, nil
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("%s 需要一个参数", name)
+			}
+			i++
+			return args[i], nil
+		}
+
+		switch name {
+		case "--to", "-t":
+			v, err := needValue()
+			if err != nil {
+				return opts, err
+			}
+			to = v
+		case "--output", "-o":
+			v, err := needValue()
+			if err != nil {
+				return opts, err
+			}
+			opts.Output = v
+		case "--in-place":
+			opts.InPlace = true
+		case "--force":
+			opts.Force = true
+		default:
+			if strings.HasPrefix(arg, "-") && arg != "-" {
+				return opts, fmt.Errorf("未知参数: %s", arg)
+			}
+			opts.Files = append(opts.Files, arg)
+		}
+	}
+
+	if to == "" {
+		return opts, fmt.Errorf("必须指定目标格式: --to md|json|yaml")
+	}
+	format, err := plan.ParseFormat(to)
+	if err != nil {
+		return opts, err
+	}
+	opts.To = format
+
+	return opts, nil
+}
+
+// formatConvertResult renders a short summary for the user.
+func formatConvertResult(result *ConvertResult, opts ConvertOptions) string {
+	var sb strings.Builder
+	for _, f := range result.Files {
+		if f.Target == "-" {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("✓ %s -> %s\n", filepath.Base(f.Source), f.Target))
+	}
+	for _, s := range result.Skipped {
+		sb.WriteString(fmt.Sprintf("- %s 已是 %s 格式, 跳过\n", filepath.Base(s), opts.To))
+	}
+	return sb.String()
+}
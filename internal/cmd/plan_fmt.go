@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/morty/morty/internal/logging"
+	"github.com/morty/morty/internal/parser/plan"
+)
+
+// FmtOptions holds the parsed plan fmt options.
+type FmtOptions struct {
+	Check bool     // --check: report unformatted files without writing
+	Files []string // Plan files (all plan files if empty)
+}
+
+// FormattedFile describes the formatting outcome of a single plan.
+type FormattedFile struct {
+	Source  string
+	Target  string // Differs from Source when the file name was fixed
+	Changed bool
+}
+
+// FmtResult represents the result of plan formatting.
+type FmtResult struct {
+	Files   []FormattedFile
+	Changed int // Number of files that were (or, with --check, would be) changed
+	Message string
+}
+
+// Fmt rewrites plan files in canonical form. Each plan is parsed into a
+// plan.Plan, mechanical violations are fixed by plan.Canonicalize and the
+// result is re-emitted in the file's own format (and, for markdown, its own
+// heading language, keeping text the plan does not model). A file whose
+// output would parse to a different plan is never written. With --check
+// nothing is written and an error is returned if any file is not canonical.
+func (h *PlanHandler) Fmt(ctx context.Context, args []string) (*FmtResult, error) {
+	logger := h.logger.WithContext(ctx)
+
+	opts, err := parseFmtOptions(args)
+	if err != nil {
+		return nil, err
+	}
+
+	planDir := h.getPlanDir()
+	sources, err := h.convertSources(planDir, opts.Files)
+	if err != nil {
+		return nil, err
+	}
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("计划目录中没有计划文件: %s", planDir)
+	}
+
+	result := &FmtResult{}
+	for _, source := range sources {
+		formatted, err := formatPlanFile(source, opts.Check)
+		if err != nil {
+			return result, err
+		}
+		if formatted.Changed {
+			result.Changed++
+			logger.Info("Plan formatted",
+				logging.String("source", formatted.Source),
+				logging.String("target", formatted.Target),
+				logging.Bool("check", opts.Check),
+			)
+		}
+		result.Files = append(result.Files, formatted)
+	}
+
+	result.Message = formatFmtResult(result, opts)
+	if opts.Check && result.Changed > 0 {
+		return result, fmt.Errorf("%d 个计划文件不符合规范格式, 运行 morty plan fmt 修复", result.Changed)
+	}
+	return result, nil
+}
+
+// formatPlanFile formats a single plan file. When check is set, the file is
+// only compared against its canonical form.
+func formatPlanFile(source string, check bool) (FormattedFile, error) {
+	formatted := FormattedFile{Source: source, Target: source}
+
+	content, err := os.ReadFile(source)
+	if err != nil {
+		return formatted, fmt.Errorf("读取计划文件失败: %w", err)
+	}
+
+	format := plan.FormatFromPath(source)
+	planData, err := plan.ParsePlanAs(string(content), format)
+	if err != nil {
+		return formatted, fmt.Errorf("解析计划文件失败 %s: %w", source, err)
+	}
+
+	module := plan.CanonicalModuleName(plan.ModuleNameFromFile(source))
+	if module == "" {
+		return formatted, fmt.Errorf("无法从文件名推断模块名: %s", source)
+	}
+	plan.Canonicalize(planData, module)
+
+	var data []byte
+	if format == plan.FormatMarkdown {
+		data = []byte(plan.ReformatMarkdown(planData, string(content), plan.DetectLanguage(string(content))))
+	} else if data, err = plan.Marshal(planData, format); err != nil {
+		return formatted, fmt.Errorf("格式化计划文件失败 %s: %w", source, err)
+	}
+
+	// Never write output that reads back as a different plan
+	reparsed, err := plan.ParsePlanAs(string(data), format)
+	if err != nil {
+		return formatted, fmt.Errorf("格式化结果无法解析, 未写入 %s: %w", source, err)
+	}
+	if diffs := plan.Diff(planData, reparsed); len(diffs) > 0 {
+		return formatted, fmt.Errorf("格式化会改变计划内容 (%s), 未写入: %s", strings.Join(diffs, ", "), source)
+	}
+
+	ext := strings.ToLower(filepath.Ext(source))
+	formatted.Target = filepath.Join(filepath.Dir(source), module+ext)
+	renamed := formatted.Target != source
+	formatted.Changed = renamed || string(data) != string(content)
+	if check || !formatted.Changed {
+		return formatted, nil
+	}
+
+	// Never overwrite another module's plan when fixing the file name;
+	// case-only renames on case-insensitive filesystems hit the same file
+	if renamed {
+		if info, err := os.Stat(formatted.Target); err == nil {
+			if srcInfo, _ := os.Stat(source); !os.SameFile(info, srcInfo) {
+				return formatted, fmt.Errorf("目标文件已存在: %s", formatted.Target)
+			}
+		}
+	}
+
+	if err := os.WriteFile(source, data, 0644); err != nil {
+		return formatted, fmt.Errorf("写入计划文件失败: %w", err)
+	}
+	if renamed {
+		if err := os.Rename(source, formatted.Target); err != nil {
+			return formatted, fmt.Errorf("重命名计划文件失败: %w", err)
+		}
+	}
+	return formatted, nil
+}
+
+// parseFmtOptions parses plan fmt arguments.
+func parseFmtOptions(args []string) (FmtOptions, error) {
+	var opts FmtOptions
+	for _, arg := range args {
+		switch arg {
+		case "--check", "-c":
+			opts.Check = true
+		default:
+			if strings.HasPrefix(arg, "-") {
+				return opts, fmt.Errorf("未知参数: %s", arg)
+			}
+			opts.Files = append(opts.Files, arg)
+		}
+	}
+	return opts, nil
+}
+
+// formatFmtResult renders a short summary for the user.
+func formatFmtResult(result *FmtResult, opts FmtOptions) string {
+	var sb strings.Builder
+	for _, f := range result.Files {
+		if !f.Changed {
+			continue
+		}
+		name := filepath.Base(f.Source)
+		if f.Target != f.Source {
+			name += " -> " + filepath.Base(f.Target)
+		}
+		if opts.Check {
+			sb.WriteString(fmt.Sprintf("✗ %s 需要格式化\n", name))
+		} else {
+			sb.WriteString(fmt.Sprintf("✓ %s 已格式化\n", name))
+		}
+	}
+	if result.Changed == 0 {
+		sb.WriteString(fmt.Sprintf("✓ %d 个计划文件均已是规范格式\n", len(result.Files)))
+	}
+	return sb.String()
+}
@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/morty/morty/internal/parser/plan"
+)
+
+const unformattedPlan = `# Plan: User_Auth
+
+## 模块概述
+
+**模块职责**: 用户认证
+
+**依赖模块**: ` + "`Storage`" + `, storage
+
+## Jobs
+
+### Job 1: 登录
+
+**目标**: 实现登录
+
+**前置条件**: 无
+
+**Tasks (Todo 列表)**:
+* [X] Task 1: 定义接口
+- [ ] Task 3: 实现登录
+
+**验证器**:
+- 登录成功
+
+**完成状态**: ✅ 已完成
+
+### Job 3: 会话
+
+**目标**: 管理会话
+
+**前置条件**:
+- Job 1
+- storage:Job 2
+
+**Tasks (Todo 列表)**:
+- [ ] Task 1: 创建会话
+
+**验证器**:
+- 会话可过期
+
+**完成状态**: ⏳ 待开始
+`
+
+func newFmtTestHandler(t *testing.T) (*PlanHandler, string) {
+	t.Helper()
+	planDir := filepath.Join(setupTestDir(t), ".morty", "plan")
+	if err := os.MkdirAll(planDir, 0755); err != nil {
+		t.Fatalf("Failed to create plan directory: %v", err)
+	}
+	return NewPlanHandler(&mockConfig{planDir: planDir}, &mockLogger{}, nil), planDir
+}
+
+func TestPlanHandler_Fmt(t *testing.T) {
+	handler, planDir := newFmtTestHandler(t)
+	source := filepath.Join(planDir, "User_Auth.md")
+	if err := os.WriteFile(source, []byte(unformattedPlan), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// --check reports without writing
+	result, err := handler.Fmt(context.Background(), []string{"--check"})
+	if err == nil || result.Changed != 1 {
+		t.Fatalf("Expected check to fail for one file, got %+v, %v", result, err)
+	}
+	if data, _ := os.ReadFile(source); string(data) != unformattedPlan {
+		t.Error("--check must not modify files")
+	}
+
+	result, err = handler.Fmt(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Fmt failed: %v", err)
+	}
+	target := filepath.Join(planDir, "user_auth.md")
+	if len(result.Files) != 1 || result.Files[0].Target != target {
+		t.Fatalf("Expected file to be renamed to user_auth.md, got %+v", result.Files)
+	}
+
+	data, err := os.ReadFile(target)
+	if err != nil {
+		t.Fatalf("Failed to read formatted plan: %v", err)
+	}
+	formatted := string(data)
+	for _, want := range []string{
+		"# Plan: User_Auth\n",
+		"**依赖模块**: storage\n",
+		"**被依赖模块**: 无\n",
+		"- [x] Task 1: 定义接口\n- [ ] Task 2: 实现登录\n",
+		"### Job 2: 会话",
+		"- job_1\n- storage:job_2\n",
+		"## 数据模型\n\n无\n",
+	} {
+		if !strings.Contains(formatted, want) {
+			t.Errorf("Expected %q in formatted plan:\n%s", want, formatted)
+		}
+	}
+
+	// Formatting is idempotent
+	result, err = handler.Fmt(context.Background(), []string{"--check"})
+	if err != nil || result.Changed != 0 {
+		t.Errorf("Expected formatted plan to pass --check, got %+v, %v", result, err)
+	}
+}
+
+func TestPlanHandler_FmtRealPlans(t *testing.T) {
+	// Tests run in a temporary directory; find the repository plans from
+	// this file's location
+	_, file, _, _ := runtime.Caller(0)
+	repoPlans, err := filepath.Glob(filepath.Join(filepath.Dir(file), "..", "..", ".morty", "plan", "*.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler, planDir := newFmtTestHandler(t)
+	for _, path := range repoPlans {
+		if !plan.IsPlanFile(filepath.Base(path)) {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(planDir, filepath.Base(path)), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := handler.Fmt(context.Background(), nil); err != nil {
+		t.Fatalf("Fmt failed: %v", err)
+	}
+	var formatted strings.Builder
+	for _, name := range []string{"ai_coding.md", "bdd.md"} {
+		data, err := os.ReadFile(filepath.Join(planDir, name))
+		if err != nil {
+			t.Fatalf("Failed to read formatted plan: %v", err)
+		}
+		formatted.Write(data)
+	}
+	for _, want := range []string{
+		"Module: ai_coding\nCreated:",
+		"## Notes\n\n<!-- Add additional notes here -->",
+		"- All tasks completed",
+		"# Plan: BDD 用户旅程测试\n",
+		"**被依赖模块**: 无（独立测试模块）",
+		"- 模拟 0.5 秒延迟（可配置）\n```",
+		"- 如果验证失败，记录 debug 日志到此处",
+		"- Job 1-5 完成",
+		"## 文件清单",
+		"**预计完成时间**: 1-2 天",
+	} {
+		if !strings.Contains(formatted.String(), want) {
+			t.Errorf("Expected %q to survive formatting", want)
+		}
+	}
+
+	result, err := handler.Fmt(context.Background(), []string{"--check"})
+	if err != nil || result.Changed != 0 {
+		t.Errorf("Expected formatted plans to pass --check, got %+v, %v", result, err)
+	}
+}
+
+func TestPlanHandler_FmtRefusesChangedPlan(t *testing.T) {
+	handler, planDir := newFmtTestHandler(t)
+	// The inline note hides the entry from the field parser, but the
+	// canonical subsection would expose it
+	content := "# Plan: cache\n\n## 模块概述\n\n**模块职责**: 缓存\n\n## Jobs\n\n### Job 1: LRU\n\n" +
+		"**目标**: 实现 LRU\n\n**调试日志**: 见下\n- debug1: 淘汰错误, 写满缓存, 计数错误, 单测, 修正计数, 已修复\n"
+	path := filepath.Join(planDir, "cache.md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{{"--check"}, nil} {
+		_, err := handler.Fmt(context.Background(), args)
+		if err == nil || !strings.Contains(err.Error(), "jobs[0].debug_logs") {
+			t.Errorf("Fmt(%v) error = %v, want a refusal naming jobs[0].debug_logs", args, err)
+		}
+	}
+	if data, _ := os.ReadFile(path); string(data) != content {
+		t.Error("A refused plan must not be modified")
+	}
+}
+
+func TestPlanHandler_FmtStructured(t *testing.T) {
+	handler, planDir := newFmtTestHandler(t)
+	content := "name: cache\njobs:\n  - name: lru\n    index: 4\n    goal: 实现 LRU\n    tasks:\n      - description: get\n"
+	path := filepath.Join(planDir, "cache.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := handler.Fmt(context.Background(), []string{"cache.yaml"}); err != nil {
+		t.Fatalf("Fmt failed: %v", err)
+	}
+
+	p, err := plan.ParsePlanFile(path)
+	if err != nil {
+		t.Fatalf("Formatted YAML does not parse: %v", err)
+	}
+	if p.Jobs[0].Index != 1 || p.Jobs[0].Tasks[0].Index != 1 {
+		t.Errorf("Expected renumbered job and task, got %+v", p.Jobs[0])
+	}
+}
+
+func TestPlanHandler_ValidateFix(t *testing.T) {
+	handler, planDir := newFmtTestHandler(t)
+	if err := os.WriteFile(filepath.Join(planDir, "User_Auth.md"), []byte(unformattedPlan), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := handler.Validate(context.Background(), []string{"User_Auth.md", "--fix"})
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if !result.Success {
+		t.Errorf("Expected mechanical violations to be fixed:\n%s", result.Message)
+	}
+}
+
+func TestParseFmtOptions(t *testing.T) {
+	opts, err := parseFmtOptions([]string{"--check", "a.md"})
+	if err != nil || !opts.Check || len(opts.Files) != 1 {
+		t.Errorf("parseFmtOptions() = %+v, %v", opts, err)
+	}
+	if _, err := parseFmtOptions([]string{"--diff"}); err == nil {
+		t.Error("Expected error for unknown option")
+	}
+}
@@ -0,0 +1,640 @@
+// Package cmd provides command handlers for Morty CLI commands.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/morty/morty/internal/graph"
+	"github.com/morty/morty/internal/logging"
+	"github.com/morty/morty/internal/parser/plan"
+	"github.com/morty/morty/internal/state"
+	"github.com/morty/morty/internal/validator"
+)
+
+// ModuleOptions holds the options of plan add and plan regen.
+type ModuleOptions struct {
+	Module    string
+	DependsOn []string // --depends-on, comma separated module names
+	HasDeps   bool     // whether --depends-on was given
+	Headless  HeadlessOptions
+	Repair    RepairOptions
+}
+
+// ModuleResult represents the result of plan add and plan regen.
+type ModuleResult struct {
+	Module       string
+	PlanPath     string
+	Regenerated  bool
+	Dependencies []string
+	Dependents   []string
+	Updated      []string      // other files whose cross-references changed
+	StatusSynced bool          // whether status.json was reconciled
+	Preserved    int           // jobs whose progress was kept in status.json
+	Repair       *RepairResult // set when --repair ran
+	Message      string
+}
+
+var (
+	// readmeTotalModules and readmeTotalJobs match the counters in the
+	// statistics section of the plan README.
+	readmeTotalModules = regexp.MustCompile(`(\*\*总模块数\*\*[:：]\s*)\d+`)
+	readmeTotalJobs    = regexp.MustCompile(`(\*\*总 Jobs 数\*\*[:：]\s*)\d+`)
+)
+
+// Add generates the plan of a new module with the AI CLI and wires it into
+// the existing plans: the 被依赖模块 lists of its dependencies, the README
+// module table and status.json.
+func (h *PlanHandler) Add(ctx context.Context, args []string) (*ModuleResult, error) {
+	return h.planModule(ctx, args, false)
+}
+
+// Regen regenerates the plan of an existing module. Its dependencies are
+// kept unless --depends-on is given, and the progress of its jobs in
+// status.json is reset while other modules keep theirs.
+func (h *PlanHandler) Regen(ctx context.Context, args []string) (*ModuleResult, error) {
+	return h.planModule(ctx, args, true)
+}
+
+// planModule implements Add and Regen. Other plans are only touched to keep
+// their 被依赖模块 lists consistent with the new dependencies.
+func (h *PlanHandler) planModule(ctx context.Context, args []string, regen bool) (*ModuleResult, error) {
+	logger := h.logger.WithContext(ctx)
+
+	opts, err := h.parseModuleOptions(args)
+	if err != nil {
+		return nil, err
+	}
+	module := opts.Module
+	result := &ModuleResult{Module: module, Regenerated: regen}
+
+	if err := h.ensurePlanDir(); err != nil {
+		return nil, fmt.Errorf("failed to create plan directory: %w", err)
+	}
+	planDir := h.getPlanDir()
+	plans, err := state.ScanPlans(planDir)
+	if err != nil {
+		return nil, fmt.Errorf("读取计划目录失败: %w", err)
+	}
+
+	existingPath, err := plan.FindPlanFile(planDir, module)
+	exists := err == nil
+	if !regen && exists {
+		return nil, fmt.Errorf("模块 %s 已存在: %s (使用 morty plan regen %s 重新生成)", module, existingPath, module)
+	}
+	if regen && !exists {
+		return nil, fmt.Errorf("模块 %s 不存在 (使用 morty plan add %s 添加)", module, module)
+	}
+
+	var current *plan.Plan
+	if regen {
+		if current, err = plan.ParsePlanFile(existingPath); err != nil {
+			return nil, fmt.Errorf("解析计划文件失败 %s: %w", existingPath, err)
+		}
+	}
+
+	deps := opts.DependsOn
+	if !opts.HasDeps && current != nil {
+		deps = moduleNames(current.Dependencies)
+	}
+	if err := checkModuleDependencies(module, deps, plans); err != nil {
+		return nil, err
+	}
+	result.Dependencies = deps
+
+	prompt, err := h.modulePrompt(ctx, opts, plans, current, deps)
+	if err != nil {
+		return nil, err
+	}
+	timeout := headlessTimeout(h.cfg, opts.Headless)
+
+	logger.Info("Executing Claude Code in headless mode for module planning",
+		logging.String("module", module),
+		logging.Bool("regen", regen),
+		logging.Any("dependencies", deps),
+		logging.Any("timeout", timeout),
+	)
+
+	output, exitCode, err := runHeadless(ctx, h.cliCaller, prompt, timeout)
+	if err != nil {
+		logger.Error("Claude Code execution failed",
+			logging.String("error", err.Error()),
+			logging.Int("exit_code", exitCode),
+		)
+		return nil, fmt.Errorf("claude code execution failed: %w", err)
+	}
+	files, err := splitPlanFiles(output)
+	if err != nil {
+		return nil, err
+	}
+	var generated *headlessFile
+	for i, file := range files {
+		if plan.ModuleNameFromFile(file.Name) == module {
+			generated = &files[i]
+		} else {
+			logger.Warn("Ignoring a file the module plan was not asked for", logging.String("file", file.Name))
+		}
+	}
+	if generated == nil {
+		return nil, fmt.Errorf("输出中没有模块 %s 的 Plan 文件", module)
+	}
+
+	target := filepath.Join(planDir, module+".md")
+	if regen {
+		target = existingPath
+	}
+	result.PlanPath = target
+	result.Dependents = moduleDependents(module, plans)
+
+	content, jobs, err := moduleContent(generated.Content, target, deps, result.Dependents)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.writePlanFile(target, content); err != nil {
+		return nil, err
+	}
+
+	// Keep the 被依赖模块 lists of the other plans in line with deps
+	for _, p := range plans {
+		if p.Name == module {
+			continue
+		}
+		path := filepath.Join(planDir, p.FileName)
+		changed, err := setDependent(path, module, containsString(deps, p.Name))
+		if err != nil {
+			return result, err
+		}
+		if changed {
+			result.Updated = append(result.Updated, p.FileName)
+		}
+	}
+
+	changed, err := updatePlanReadme(planDir, filepath.Base(target), module, jobs, deps)
+	if err != nil {
+		return result, err
+	}
+	if changed {
+		result.Updated = append(result.Updated, "README.md")
+	}
+
+	v := h.newValidator(planDir, false)
+	results, err := v.ValidateAll()
+	if err != nil {
+		return result, fmt.Errorf("failed to validate plan files: %w", err)
+	}
+	if !allPassed(results) && opts.Repair.Enabled {
+		result.Repair, err = h.repairPlans(ctx, results, v.ValidateAll, opts.Repair.Attempts, timeout)
+		if err != nil {
+			return result, err
+		}
+		results = result.Repair.Results
+		fmt.Print(formatRepair(result.Repair))
+	}
+	if !allPassed(results) {
+		return result, fmt.Errorf("生成的 Plan 未通过校验，status.json 未更新:\n%s", validator.FormatResults(results, false))
+	}
+
+	if err := h.syncStatus(planDir, module, result); err != nil {
+		return result, err
+	}
+
+	logger.Info("Module planning completed",
+		logging.String("module", module),
+		logging.Bool("regen", regen),
+		logging.Any("updated", result.Updated),
+		logging.Bool("status_synced", result.StatusSynced),
+		logging.Int("preserved", result.Preserved),
+	)
+	result.Message = formatModuleResult(result)
+	return result, nil
+}
+
+// parseModuleOptions parses plan add and plan regen arguments. Both always
+// run headless, so --timeout and --input are accepted without --headless.
+func (h *PlanHandler) parseModuleOptions(args []string) (ModuleOptions, error) {
+	var opts ModuleOptions
+
+	repair, remaining, err := h.parseRepairOptions(args)
+	if err != nil {
+		return opts, err
+	}
+	opts.Repair = repair
+	headless, remaining, err := parseHeadlessOptions(append([]string{"--headless"}, remaining...))
+	if err != nil {
+		return opts, err
+	}
+	opts.Headless = headless
+
+	for i := 0; i < len(remaining); i++ {
+		arg := remaining[i]
+
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch {
+		case name == "--depends-on" || name == "-d":
+			if !hasValue {
+				if i+1 >= len(remaining) {
+					return opts, fmt.Errorf("%s 需要一个参数", name)
+				}
+				i++
+				value = remaining[i]
+			}
+			opts.HasDeps = true
+			opts.DependsOn = moduleNames(strings.Split(value, ","))
+		case strings.HasPrefix(arg, "-"):
+			return opts, fmt.Errorf("未知参数: %s", arg)
+		case opts.Module != "":
+			return opts, fmt.Errorf("只能指定一个模块: %s", arg)
+		default:
+			opts.Module = plan.CanonicalModuleName(arg)
+			if opts.Module == "" {
+				return opts, fmt.Errorf("无效的模块名: %s", arg)
+			}
+		}
+	}
+
+	if opts.Module == "" {
+		return opts, fmt.Errorf("需要指定模块名")
+	}
+	return opts, nil
+}
+
+// moduleNames returns the canonical module names in names, without "无"
+// markers, empty entries and duplicates.
+func moduleNames(names []string) []string {
+	result := []string{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || name == "无" || strings.EqualFold(name, "none") {
+			continue
+		}
+		if name != "__ALL__" {
+			name = plan.CanonicalModuleName(name)
+		}
+		if !containsString(result, name) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// checkModuleDependencies checks that deps name existing modules other than
+// module and that depending on them does not create a cycle.
+func checkModuleDependencies(module string, deps []string, plans []state.PlanInfo) error {
+	graphDeps := make(map[string][]string, len(plans)+1)
+	for _, p := range plans {
+		graphDeps[p.Name] = p.Dependencies
+	}
+
+	for _, dep := range deps {
+		if dep == module {
+			return fmt.Errorf("模块不能依赖自身: %s", module)
+		}
+		if _, ok := graphDeps[dep]; !ok && dep != "__ALL__" {
+			return fmt.Errorf("依赖模块不存在: %s", dep)
+		}
+	}
+
+	graphDeps[module] = deps
+	if cycle := state.FindCycle(graphDeps); cycle != nil {
+		return fmt.Errorf("依赖关系存在循环: %s", state.FormatCycle(cycle))
+	}
+	return nil
+}
+
+// moduleDependents returns the modules that depend on module, explicitly
+// or through __ALL__.
+func moduleDependents(module string, plans []state.PlanInfo) []string {
+	dependents := []string{}
+	for _, p := range plans {
+		if p.Name == module {
+			continue
+		}
+		for _, dep := range p.Dependencies {
+			if dep == module || dep == "__ALL__" {
+				dependents = append(dependents, p.Name)
+				break
+			}
+		}
+	}
+	sort.Strings(dependents)
+	return dependents
+}
+
+// modulePrompt renders the prompt for planning a single module against the
+// existing plans. A regenerated module's current plan is included.
+func (h *PlanHandler) modulePrompt(ctx context.Context, opts ModuleOptions, plans []state.PlanInfo, current *plan.Plan, deps []string) (string, error) {
+	data, err := seedData(opts.Headless.Input)
+	if err != nil {
+		return "", err
+	}
+	data["module"] = opts.Module
+	data["research"] = h.researchContext(ctx, strings.TrimSpace(opts.Module+" "+data["seed"].(string)))
+	data["dependencies"] = deps
+	data["current"] = ""
+	if current != nil {
+		data["current"] = strings.TrimRight(plan.RenderMarkdown(current), "\n")
+	}
+
+	planDir := h.getPlanDir()
+	modules := []map[string]interface{}{}
+	for _, p := range plans {
+		if p.Name == opts.Module {
+			continue
+		}
+		responsibility := ""
+		if parsed, err := plan.ParsePlanFile(filepath.Join(planDir, p.FileName)); err == nil {
+			responsibility = parsed.Responsibility
+		}
+		jobNames := make([]string, 0, len(p.Jobs))
+		for _, job := range p.Jobs {
+			jobNames = append(jobNames, job.Name)
+		}
+		modules = append(modules, map[string]interface{}{
+			"name":           p.Name,
+			"responsibility": responsibility,
+			"dependencies":   p.Dependencies,
+			"jobs":           jobNames,
+		})
+	}
+	data["modules"] = modules
+
+	return newPromptEngine(h.cfg, h.paths).Render("phases/plan_module.md", data)
+}
+
+// moduleContent returns the generated plan with its dependency fields set
+// to deps and dependents, in the format of target, and its job count.
+func moduleContent(generated, target string, deps, dependents []string) (string, int, error) {
+	parsed, err := plan.ParsePlan(generated)
+	if err != nil {
+		return "", 0, fmt.Errorf("解析生成的 Plan 失败: %w", err)
+	}
+
+	format := plan.FormatFromPath(target)
+	if format != plan.FormatMarkdown {
+		parsed.Dependencies = deps
+		parsed.Dependents = dependents
+		data, err := plan.Marshal(parsed, format)
+		if err != nil {
+			return "", 0, fmt.Errorf("转换生成的 Plan 失败: %w", err)
+		}
+		return string(data), len(parsed.Jobs), nil
+	}
+
+	none := plan.NoneMarker(plan.DetectLanguage(generated))
+	content, _ := plan.SetField(generated, plan.SectionDependencies, joinOr(deps, none))
+	content, _ = plan.SetField(content, plan.SectionDependents, joinOr(dependents, none))
+	return content, len(parsed.Jobs), nil
+}
+
+// setDependent adds module to or removes it from the 被依赖模块 list of the
+// plan at path and reports whether the file changed.
+func setDependent(path, module string, want bool) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, fmt.Errorf("读取计划文件失败: %w", err)
+	}
+	format := plan.FormatFromPath(path)
+	parsed, err := plan.ParsePlanAs(string(data), format)
+	if err != nil {
+		return false, fmt.Errorf("解析计划文件失败 %s: %w", path, err)
+	}
+
+	dependents := moduleNames(parsed.Dependents)
+	if containsString(dependents, module) == want {
+		return false, nil
+	}
+	if want {
+		dependents = append(dependents, module)
+	} else {
+		kept := dependents[:0]
+		for _, name := range dependents {
+			if name != module {
+				kept = append(kept, name)
+			}
+		}
+		dependents = kept
+	}
+
+	var content string
+	if format == plan.FormatMarkdown {
+		var ok bool
+		none := plan.NoneMarker(plan.DetectLanguage(string(data)))
+		if content, ok = plan.SetField(string(data), plan.SectionDependents, joinOr(dependents, none)); !ok {
+			return false, nil
+		}
+	} else {
+		parsed.Dependents = dependents
+		out, err := plan.Marshal(parsed, format)
+		if err != nil {
+			return false, fmt.Errorf("转换计划文件失败 %s: %w", path, err)
+		}
+		content = string(out)
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return false, fmt.Errorf("failed to write plan file: %w", err)
+	}
+	return true, nil
+}
+
+// updatePlanReadme adds or updates the row of fileName in the module table
+// of the plan README and recounts its statistics. A new row takes the
+// name from the generated plan file and the 规划中 status; an existing row
+// keeps its name and status. It reports whether README.md changed and does
+// nothing when there is no README.
+func updatePlanReadme(planDir, fileName, name string, jobs int, deps []string) (bool, error) {
+	path := filepath.Join(planDir, "README.md")
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("读取 README.md 失败: %w", err)
+	}
+
+	if parsed, err := plan.ParsePlanFile(filepath.Join(planDir, fileName)); err == nil && parsed.Name != "" {
+		name = parsed.Name
+	}
+	cells := []string{name, fileName, strconv.Itoa(jobs), joinOrNone(deps), "规划中"}
+
+	lines := strings.Split(string(data), "\n")
+	if table := plan.FindModuleTable(string(data)); table != nil {
+		row := -1
+		for _, r := range table.Rows {
+			if len(r.Cells) >= 5 && strings.Trim(r.Cell(1), "`") == fileName {
+				row = r.Line
+				cells[0], cells[4] = r.Cell(table.ModuleColumn), r.Cell(4)
+			}
+		}
+
+		newRow := "| " + strings.Join(cells, " | ") + " |"
+		if row >= 0 {
+			lines[row] = newRow
+		} else {
+			lines = append(lines[:table.End+1], append([]string{newRow}, lines[table.End+1:]...)...)
+		}
+	}
+	content := strings.Join(lines, "\n")
+
+	plans, err := state.ScanPlans(planDir)
+	if err != nil {
+		return false, fmt.Errorf("读取计划目录失败: %w", err)
+	}
+	totalJobs := 0
+	for _, p := range plans {
+		totalJobs += len(p.Jobs)
+	}
+	content = readmeTotalModules.ReplaceAllString(content, "${1}"+strconv.Itoa(len(plans)))
+	content = readmeTotalJobs.ReplaceAllString(content, "${1}"+strconv.Itoa(totalJobs))
+
+	if content == string(data) {
+		return false, nil
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return false, fmt.Errorf("写入 README.md 失败: %w", err)
+	}
+	return true, nil
+}
+
+// syncStatus reconciles an existing status.json with the updated plans.
+// Without a status file there is nothing to keep; doing generates one.
+func (h *PlanHandler) syncStatus(planDir, module string, result *ModuleResult) error {
+	statusFile := h.paths.GetStatusFile()
+	if h.cfg != nil {
+		statusFile = h.cfg.GetStatusFile()
+	}
+	if _, err := os.Stat(statusFile); os.IsNotExist(err) {
+		return nil
+	}
+
+	manager := state.NewManager(statusFile)
+	if err := manager.Load(); err != nil {
+		return fmt.Errorf("加载状态文件失败: %w", err)
+	}
+	fresh, err := state.GenerateStatus(planDir)
+	if err != nil {
+		return fmt.Errorf("生成状态失败: %w", err)
+	}
+
+	result.Preserved = reconcileStatus(manager.GetStatus(), fresh, module)
+	if err := manager.Save(fresh); err != nil {
+		return fmt.Errorf("保存状态失败: %w", err)
+	}
+	result.StatusSynced = true
+	return nil
+}
+
+// reconcileStatus carries job progress from old into fresh, a status
+// generated from the updated plans. Jobs are matched by module and job
+// name; the jobs of the reset module start over, and jobs it blocked are
+// released. It returns the number of jobs whose progress was kept.
+func reconcileStatus(old, fresh *state.ExecutionStatus, reset string) int {
+	now := time.Now()
+	preserved := 0
+	allCompleted := true
+
+	for i := range fresh.Modules {
+		module := &fresh.Modules[i]
+		if oldModule := old.GetModuleByName(module.Name); oldModule != nil && module.Name != reset {
+			module.CreatedAt = oldModule.CreatedAt
+			for j := range module.Jobs {
+				job := &module.Jobs[j]
+				oldJob := oldModule.GetJobByName(job.Name)
+				if oldJob == nil {
+					continue
+				}
+				job.Status = oldJob.Status
+				job.TasksCompleted = oldJob.TasksCompleted
+				job.LoopCount = oldJob.LoopCount
+				job.RetryCount = oldJob.RetryCount
+				job.FailureReason = oldJob.FailureReason
+				job.BlockedBy = oldJob.BlockedBy
+				job.Tasks = oldJob.Tasks
+				job.DebugLogs = oldJob.DebugLogs
+				job.Digest = oldJob.Digest
+				job.Sessions = oldJob.Sessions
+				job.CreatedAt = oldJob.CreatedAt
+				job.UpdatedAt = oldJob.UpdatedAt
+
+				if strings.HasPrefix(job.BlockedBy, graph.JobID(reset, "")) {
+					if job.Status == state.StatusBlocked {
+						job.Status = state.StatusPending
+					}
+					job.BlockedBy = ""
+					job.FailureReason = ""
+					job.UpdatedAt = now
+				}
+				preserved++
+			}
+			recalculateModuleStatus(module)
+		}
+
+		for _, job := range module.Jobs {
+			if job.Status != state.StatusCompleted {
+				allCompleted = false
+			}
+		}
+	}
+
+	fresh.Global.StartTime = old.Global.StartTime
+	fresh.Global.Profile = old.Global.Profile
+	fresh.Global.LastUpdate = now
+	switch {
+	case allCompleted:
+		fresh.Global.Status = state.StatusCompleted
+	case old.Global.Status == state.StatusCompleted:
+		fresh.Global.Status = state.StatusPending
+	default:
+		fresh.Global.Status = old.Global.Status
+	}
+	return preserved
+}
+
+// formatModuleResult describes the outcome of plan add or plan regen.
+func formatModuleResult(result *ModuleResult) string {
+	var sb strings.Builder
+	if result.Regenerated {
+		fmt.Fprintf(&sb, "✅ 已重新生成模块 %s: %s\n", result.Module, result.PlanPath)
+	} else {
+		fmt.Fprintf(&sb, "✅ 已添加模块 %s: %s\n", result.Module, result.PlanPath)
+	}
+	fmt.Fprintf(&sb, "  依赖模块: %s\n", joinOrNone(result.Dependencies))
+	fmt.Fprintf(&sb, "  被依赖模块: %s\n", joinOrNone(result.Dependents))
+	if len(result.Updated) > 0 {
+		fmt.Fprintf(&sb, "  更新引用: %s\n", strings.Join(result.Updated, ", "))
+	}
+	if result.StatusSynced {
+		fmt.Fprintf(&sb, "  status.json: 已同步，保留 %d 个 Job 的进度\n", result.Preserved)
+	}
+	return sb.String()
+}
+
+// joinOrNone joins names with ", ", or returns "无" when there are none.
+func joinOrNone(names []string) string {
+	return joinOr(names, "无")
+}
+
+// joinOr joins names with ", ", or returns none when there are none.
+func joinOr(names []string, none string) string {
+	if len(names) == 0 {
+		return none
+	}
+	return strings.Join(names, ", ")
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
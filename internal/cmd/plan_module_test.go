@@ -0,0 +1,254 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/morty/morty/internal/parser/plan"
+	"github.com/morty/morty/internal/state"
+)
+
+// moduleReadme is a plan README listing the logging and e2e_test modules.
+const moduleReadme = `# Plan 索引
+
+## 模块列表
+
+| 模块名称 | 文件 | Jobs 数量 | 依赖模块 | 状态 |
+|----------|------|-----------|----------|------|
+| 日志 | logging.md | 1 | 无 | 开发中 |
+| 端到端测试 | e2e_test.md | 1 | 无 | 规划中 |
+
+## 依赖关系图
+
+logging, e2e_test
+
+## 执行顺序
+
+1. logging
+2. e2e_test
+
+## 统计信息
+
+- **总模块数**: 2（包括 e2e_test 模块）
+- **总 Jobs 数**: 2
+`
+
+// newModuleTestProject creates a project with the logging and e2e_test
+// plans, a README and a status.json in which logging's job is completed.
+func newModuleTestProject(t *testing.T) (*PlanHandler, *mockConfig) {
+	t.Helper()
+	cfg := &mockConfig{}
+	cfg.SetWorkDir(setupTestDir(t))
+	planDir := cfg.GetPlanDir()
+	os.MkdirAll(planDir, 0755)
+	os.WriteFile(filepath.Join(planDir, "logging.md"), []byte(fmt.Sprintf(doctorPlan, "logging")), 0644)
+	os.WriteFile(filepath.Join(planDir, "e2e_test.md"), []byte(fmt.Sprintf(doctorPlan, "e2e_test")), 0644)
+	os.WriteFile(filepath.Join(planDir, "README.md"), []byte(moduleReadme), 0644)
+
+	status, err := state.GenerateStatus(planDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	status.Global.Status = state.StatusCompleted
+	for i := range status.Modules {
+		status.Modules[i].Status = state.StatusCompleted
+		status.Modules[i].Jobs[0].Status = state.StatusCompleted
+		status.Modules[i].Jobs[0].LoopCount = 3
+	}
+	if err := state.NewManager(cfg.GetStatusFile()).Save(status); err != nil {
+		t.Fatal(err)
+	}
+
+	promptsDir := filepath.Join(cfg.GetWorkDir(), "prompts")
+	os.MkdirAll(promptsDir, 0755)
+	os.WriteFile(filepath.Join(promptsDir, "plan.md"), []byte("# Plan Prompt\n"), 0644)
+
+	handler := NewPlanHandler(cfg, &mockLogger{}, nil)
+	handler.SetPromptsDir(promptsDir)
+	return handler, cfg
+}
+
+// loadStatus loads the project's status.json.
+func loadStatus(t *testing.T, cfg *mockConfig) *state.ExecutionStatus {
+	t.Helper()
+	manager := state.NewManager(cfg.GetStatusFile())
+	if err := manager.Load(); err != nil {
+		t.Fatal(err)
+	}
+	return manager.GetStatus()
+}
+
+func TestParseModuleOptions(t *testing.T) {
+	handler := NewPlanHandler(&mockConfig{}, &mockLogger{}, nil)
+
+	opts, err := handler.parseModuleOptions([]string{"Metrics", "--depends-on", "logging, Cache,logging", "--timeout=5m"})
+	if err != nil {
+		t.Fatalf("parseModuleOptions() error: %v", err)
+	}
+	if opts.Module != "metrics" || !opts.HasDeps || !reflect.DeepEqual(opts.DependsOn, []string{"logging", "cache"}) ||
+		!opts.Headless.Enabled || opts.Headless.Timeout.Minutes() != 5 {
+		t.Errorf("parseModuleOptions() = %+v", opts)
+	}
+	if opts, err := handler.parseModuleOptions([]string{"metrics", "--depends-on=无"}); err != nil || !opts.HasDeps || len(opts.DependsOn) != 0 {
+		t.Errorf("parseModuleOptions(--depends-on=无) = %+v, %v", opts, err)
+	}
+
+	for _, args := range [][]string{nil, {"a", "b"}, {"metrics", "--depends-on"}, {"metrics", "--force"}, {"--", "metrics"}} {
+		if _, err := handler.parseModuleOptions(args); err == nil {
+			t.Errorf("Expected an error for %q", args)
+		}
+	}
+}
+
+func TestPlanHandler_Add(t *testing.T) {
+	handler, cfg := newModuleTestProject(t)
+	planDir := cfg.GetPlanDir()
+	var prompts []string
+	handler.SetCLICaller(sequenceCaller(t, []string{
+		"<!-- morty:file metrics.md -->\n" + fmt.Sprintf(doctorPlan, "metrics"),
+	}, &prompts))
+
+	result, err := handler.Add(context.Background(), []string{"metrics", "--depends-on", "logging"})
+	if err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+	if len(prompts) != 1 || !strings.Contains(prompts[0], "`logging`: 缓存层") ||
+		!strings.Contains(prompts[0], "<!-- morty:file metrics.md -->") {
+		t.Errorf("Unexpected prompt:\n%s", prompts[0])
+	}
+
+	metrics, _ := plan.ParsePlanFile(filepath.Join(planDir, "metrics.md"))
+	if !reflect.DeepEqual(metrics.Dependencies, []string{"logging"}) {
+		t.Errorf("metrics dependencies = %q", metrics.Dependencies)
+	}
+	logging, _ := plan.ParsePlanFile(filepath.Join(planDir, "logging.md"))
+	if !reflect.DeepEqual(logging.Dependents, []string{"metrics"}) {
+		t.Errorf("logging dependents = %q", logging.Dependents)
+	}
+	if !reflect.DeepEqual(result.Updated, []string{"logging.md", "README.md"}) {
+		t.Errorf("Updated = %q", result.Updated)
+	}
+
+	readme, _ := os.ReadFile(filepath.Join(planDir, "README.md"))
+	for _, want := range []string{
+		"| 端到端测试 | e2e_test.md | 1 | 无 | 规划中 |\n| metrics | metrics.md | 1 | logging | 规划中 |\n",
+		"**总模块数**: 3（包括 e2e_test 模块）",
+		"**总 Jobs 数**: 3",
+	} {
+		if !strings.Contains(string(readme), want) {
+			t.Errorf("Expected %q in README:\n%s", want, readme)
+		}
+	}
+
+	// Existing progress is kept and the new job makes the run pending again
+	status := loadStatus(t, cfg)
+	if result.Preserved != 2 || status.Global.Status != state.StatusPending {
+		t.Errorf("Preserved = %d, global status = %s", result.Preserved, status.Global.Status)
+	}
+	if job := status.GetModuleByName("logging").Jobs[0]; job.Status != state.StatusCompleted || job.LoopCount != 3 {
+		t.Errorf("logging job = %+v", job)
+	}
+	if module := status.GetModuleByName("metrics"); module == nil || module.Jobs[0].Status != state.StatusPending {
+		t.Errorf("metrics module = %+v", module)
+	}
+
+	if _, err := handler.Add(context.Background(), []string{"metrics"}); err == nil || !strings.Contains(err.Error(), "plan regen") {
+		t.Errorf("Expected an existing module error, got %v", err)
+	}
+	for _, deps := range []string{"cache", "audit"} {
+		if _, err := handler.Add(context.Background(), []string{deps, "--depends-on", "cache"}); err == nil {
+			t.Errorf("Expected a dependency error for %s", deps)
+		}
+	}
+}
+
+func TestPlanHandler_Regen(t *testing.T) {
+	handler, cfg := newModuleTestProject(t)
+	planDir := cfg.GetPlanDir()
+	var prompts []string
+	handler.SetCLICaller(sequenceCaller(t, []string{
+		"<!-- morty:file metrics.md -->\n" + fmt.Sprintf(doctorPlan, "metrics"),
+		"<!-- morty:file metrics.md -->\n" + strings.Replace(fmt.Sprintf(doctorPlan, "metrics"), "### Job 1: 内存缓存", "### Job 1: 指标采集", 1),
+	}, &prompts))
+
+	if _, err := handler.Add(context.Background(), []string{"metrics", "--depends-on", "logging"}); err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+
+	// Moving the dependency updates both 被依赖模块 lists
+	result, err := handler.Regen(context.Background(), []string{"metrics", "--depends-on", "e2e_test"})
+	if err != nil {
+		t.Fatalf("Regen() error: %v", err)
+	}
+	if !strings.Contains(prompts[1], "## 当前 Plan") || !strings.Contains(prompts[1], "重新生成已有模块 `metrics`") {
+		t.Errorf("Expected the current plan in the prompt:\n%s", prompts[1])
+	}
+	logging, _ := os.ReadFile(filepath.Join(planDir, "logging.md"))
+	e2e, _ := plan.ParsePlanFile(filepath.Join(planDir, "e2e_test.md"))
+	if !strings.Contains(string(logging), "**被依赖模块**: 无\n") || !reflect.DeepEqual(e2e.Dependents, []string{"metrics"}) {
+		t.Errorf("e2e_test dependents = %q, logging.md:\n%s", e2e.Dependents, logging)
+	}
+	readme, _ := os.ReadFile(filepath.Join(planDir, "README.md"))
+	if !strings.Contains(string(readme), "| metrics | metrics.md | 1 | e2e_test | 规划中 |") || strings.Count(string(readme), "metrics.md") != 1 {
+		t.Errorf("Expected the metrics row to be updated:\n%s", readme)
+	}
+
+	status := loadStatus(t, cfg)
+	if job := status.GetModuleByName("metrics").Jobs[0]; job.Name != "指标采集" || job.Status != state.StatusPending {
+		t.Errorf("metrics job = %+v", job)
+	}
+	if result.Preserved != 2 {
+		t.Errorf("Preserved = %d, want 2", result.Preserved)
+	}
+
+	if _, err := handler.Regen(context.Background(), []string{"audit"}); err == nil || !strings.Contains(err.Error(), "plan add") {
+		t.Errorf("Expected a missing module error, got %v", err)
+	}
+	if _, err := handler.Regen(context.Background(), []string{"e2e_test", "--depends-on", "metrics"}); err == nil ||
+		!strings.Contains(err.Error(), "循环") {
+		t.Errorf("Expected a cycle error, got %v", err)
+	}
+}
+
+func TestReconcileStatus(t *testing.T) {
+	old := &state.ExecutionStatus{
+		Global: state.GlobalState{Status: state.StatusFailed, Profile: "ci"},
+		Modules: []state.ModuleState{
+			{Name: "cache", Jobs: []state.JobState{{Name: "存储", Status: state.StatusFailed, FailureReason: "boom"}}},
+			{Name: "api", Jobs: []state.JobState{
+				{Name: "接口", Status: state.StatusBlocked, BlockedBy: "cache/存储", FailureReason: "前置 Job 失败"},
+				{Name: "文档", Status: state.StatusCompleted, TasksCompleted: 2},
+			}},
+		},
+	}
+	fresh := &state.ExecutionStatus{
+		Modules: []state.ModuleState{
+			{Name: "cache", Jobs: []state.JobState{{Name: "存储", Status: state.StatusPending}}},
+			{Name: "api", Jobs: []state.JobState{
+				{Name: "接口", Status: state.StatusPending},
+				{Name: "文档", Status: state.StatusPending},
+			}},
+		},
+	}
+
+	if preserved := reconcileStatus(old, fresh, "cache"); preserved != 2 {
+		t.Errorf("reconcileStatus() = %d, want 2", preserved)
+	}
+	if job := fresh.Modules[0].Jobs[0]; job.Status != state.StatusPending || job.FailureReason != "" {
+		t.Errorf("Reset job = %+v", job)
+	}
+	if job := fresh.Modules[1].Jobs[0]; job.Status != state.StatusPending || job.BlockedBy != "" {
+		t.Errorf("Released job = %+v", job)
+	}
+	if job := fresh.Modules[1].Jobs[1]; job.Status != state.StatusCompleted || job.TasksCompleted != 2 {
+		t.Errorf("Preserved job = %+v", job)
+	}
+	if fresh.Modules[1].Status != state.StatusPending || fresh.Global.Status != state.StatusFailed || fresh.Global.Profile != "ci" {
+		t.Errorf("module status = %s, global = %+v", fresh.Modules[1].Status, fresh.Global)
+	}
+}
@@ -0,0 +1,211 @@
+// Package cmd provides command handlers for Morty CLI commands.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/morty/morty/internal/config"
+	"github.com/morty/morty/internal/logging"
+	"github.com/morty/morty/internal/transcript"
+	"github.com/morty/morty/internal/validator"
+)
+
+// RepairOptions holds the options of the plan repair loop.
+type RepairOptions struct {
+	Enabled  bool // --repair
+	Attempts int  // --repair-attempts, defaults to plan.repair_attempts
+}
+
+// RepairResult is the outcome of the plan repair loop.
+type RepairResult struct {
+	Attempts  int                           // rounds the AI CLI was asked to fix the plans
+	Converged bool                          // whether the plans pass validation
+	Results   []*validator.ValidationResult // validation after the last round
+	Diffs     []transcript.FileDiff         // changes since before the first round
+}
+
+// parseRepairOptions extracts --repair and --repair-attempts from args and
+// returns the remaining args.
+func (h *PlanHandler) parseRepairOptions(args []string) (RepairOptions, []string, error) {
+	opts := RepairOptions{Attempts: -1}
+	var remaining []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--repair":
+			opts.Enabled = true
+		case arg == "--repair-attempts" || strings.HasPrefix(arg, "--repair-attempts="):
+			raw := strings.TrimPrefix(arg, "--repair-attempts=")
+			if arg == "--repair-attempts" {
+				if i+1 >= len(args) {
+					return opts, nil, fmt.Errorf("%s 需要一个值", arg)
+				}
+				i++
+				raw = args[i]
+			}
+			n, err := strconv.Atoi(raw)
+			if err != nil || n < 1 {
+				return opts, nil, fmt.Errorf("无效的修复次数: %s", raw)
+			}
+			opts.Attempts = n
+		default:
+			remaining = append(remaining, arg)
+		}
+	}
+
+	if opts.Attempts > 0 && !opts.Enabled {
+		return opts, nil, fmt.Errorf("--repair-attempts 需要与 --repair 一起使用")
+	}
+	if opts.Attempts < 0 {
+		opts.Attempts = config.DefaultPlanRepairAttempts
+		if h.cfg != nil {
+			opts.Attempts = h.cfg.GetInt("plan.repair_attempts", config.DefaultPlanRepairAttempts)
+		}
+	}
+	return opts, remaining, nil
+}
+
+// repairPlans asks the AI CLI to fix the files that failed validation and
+// re-validates, for up to attempts rounds. It stops early when a round
+// changes nothing, since repeating the same prompt would not converge.
+func (h *PlanHandler) repairPlans(ctx context.Context, results []*validator.ValidationResult,
+	validate func() ([]*validator.ValidationResult, error), attempts int, timeout time.Duration) (*RepairResult, error) {
+	logger := h.logger.WithContext(ctx)
+	repair := &RepairResult{Results: results}
+
+	// Content of every file the loop touched, from before the first round
+	originals := make(map[string]string)
+	var touched []string
+
+	for !allPassed(repair.Results) && repair.Attempts < attempts {
+		prompt, targets, err := h.repairPrompt(repair.Results)
+		if err != nil {
+			return repair, fmt.Errorf("failed to render repair prompt: %w", err)
+		}
+		for _, path := range targets {
+			if _, ok := originals[path]; !ok {
+				data, _ := os.ReadFile(path)
+				originals[path] = string(data)
+				touched = append(touched, path)
+			}
+		}
+
+		repair.Attempts++
+		logger.Info("Repairing plan files",
+			logging.Int("attempt", repair.Attempts),
+			logging.Int("files", len(targets)),
+		)
+
+		output, exitCode, err := runHeadless(ctx, h.cliCaller, prompt, timeout)
+		if err != nil {
+			logger.Error("Claude Code execution failed",
+				logging.String("error", err.Error()),
+				logging.Int("exit_code", exitCode),
+			)
+			return repair, fmt.Errorf("claude code execution failed: %w", err)
+		}
+		files, err := splitPlanFiles(output)
+		if err != nil {
+			return repair, err
+		}
+
+		changed := false
+		for _, file := range files {
+			path, ok := targets[file.Name]
+			if !ok {
+				logger.Warn("Ignoring a file the repair was not asked for", logging.String("file", file.Name))
+				continue
+			}
+			if data, err := os.ReadFile(path); err == nil && string(data) == file.Content {
+				continue
+			}
+			if err := h.writePlanFile(path, file.Content); err != nil {
+				return repair, err
+			}
+			changed = true
+		}
+
+		if repair.Results, err = validate(); err != nil {
+			return repair, fmt.Errorf("failed to validate plan files: %w", err)
+		}
+		if !changed {
+			logger.Warn("Plan repair made no changes", logging.Int("attempt", repair.Attempts))
+			break
+		}
+	}
+
+	repair.Converged = allPassed(repair.Results)
+	for _, path := range touched {
+		data, _ := os.ReadFile(path)
+		if string(data) != originals[path] {
+			repair.Diffs = append(repair.Diffs, transcript.Diff(path, originals[path], string(data)))
+		}
+	}
+	return repair, nil
+}
+
+// repairPrompt renders the prompt for the failed results and returns the
+// files it asks for, keyed by the name used in the output markers.
+func (h *PlanHandler) repairPrompt(results []*validator.ValidationResult) (string, map[string]string, error) {
+	targets := make(map[string]string)
+	files := []map[string]interface{}{}
+
+	for _, result := range results {
+		if result.Passed {
+			continue
+		}
+		name := filepath.Base(result.File)
+		targets[name] = result.File
+
+		errors := make([]map[string]interface{}, 0, len(result.Errors))
+		for _, e := range result.Errors {
+			errors = append(errors, map[string]interface{}{
+				"line":     e.Line,
+				"message":  e.Message,
+				"code":     e.Code,
+				"expected": e.Expected,
+				"found":    e.Found,
+			})
+		}
+
+		file := map[string]interface{}{"name": name, "errors": errors, "exists": false, "content": ""}
+		if data, err := os.ReadFile(result.File); err == nil {
+			file["exists"] = true
+			file["content"] = strings.TrimRight(string(data), "\n")
+		}
+		files = append(files, file)
+	}
+
+	prompt, err := newPromptEngine(h.cfg, h.paths).Render("phases/plan_repair.md", map[string]interface{}{"files": files})
+	return prompt, targets, err
+}
+
+// formatRepair describes the outcome of the repair loop; when the plans
+// still fail it shows what the loop changed.
+func formatRepair(repair *RepairResult) string {
+	var sb strings.Builder
+	if repair.Converged {
+		fmt.Fprintf(&sb, "🔧 自动修复: 第 %d 轮后通过校验", repair.Attempts)
+		for _, d := range repair.Diffs {
+			fmt.Fprintf(&sb, "\n  %s (+%d -%d)", d.Path, d.Added(), d.Deleted())
+		}
+		return sb.String() + "\n"
+	}
+
+	fmt.Fprintf(&sb, "⚠️  自动修复 %d 轮后仍未通过校验", repair.Attempts)
+	if len(repair.Diffs) == 0 {
+		return sb.String() + "，没有修改任何文件\n"
+	}
+	sb.WriteString("，已做的修改:\n")
+	for _, d := range repair.Diffs {
+		fmt.Fprintf(&sb, "\n--- %s\n%s", d.Path, d.Unified(3))
+	}
+	return sb.String()
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/morty/morty/internal/callcli"
+)
+
+// brokenPlan is doctorPlan without the validator of its job (E002).
+func brokenPlan(module string) string {
+	return strings.Replace(fmt.Sprintf(doctorPlan, module), "#### 验证器\n\n- 命中率统计正确\n\n", "", 1)
+}
+
+// sequenceCaller returns an AI CLI caller that answers the n-th call with
+// the n-th answer (repeating the last one) and records the prompts.
+func sequenceCaller(t *testing.T, answers []string, prompts *[]string) *mockAICliCaller {
+	return &mockAICliCaller{
+		getBaseCallerFunc: func() callcli.Caller {
+			return &mockCaller{
+				callWithOptionsFunc: func(ctx context.Context, name string, args []string, opts callcli.Options) (*callcli.Result, error) {
+					answer := answers[len(answers)-1]
+					if len(*prompts) < len(answers) {
+						answer = answers[len(*prompts)]
+					}
+					*prompts = append(*prompts, opts.Stdin)
+					return &callcli.Result{Stdout: resultJSON(t, answer)}, nil
+				},
+			}
+		},
+	}
+}
+
+// newRepairTestHandler creates a plan directory with a valid e2e_test.md
+// and a broken cache.md.
+func newRepairTestHandler(t *testing.T) (*PlanHandler, string) {
+	t.Helper()
+	cfg := &mockConfig{}
+	cfg.SetWorkDir(setupTestDir(t))
+	planDir := cfg.GetPlanDir()
+	os.MkdirAll(planDir, 0755)
+	os.WriteFile(filepath.Join(planDir, "e2e_test.md"), []byte(fmt.Sprintf(doctorPlan, "e2e_test")), 0644)
+	os.WriteFile(filepath.Join(planDir, "cache.md"), []byte(brokenPlan("cache")), 0644)
+	return NewPlanHandler(cfg, &mockLogger{}, nil), planDir
+}
+
+func TestParseRepairOptions(t *testing.T) {
+	handler := NewPlanHandler(&mockConfig{values: map[string]interface{}{"plan.repair_attempts": 5}}, &mockLogger{}, nil)
+
+	opts, rest, err := handler.parseRepairOptions([]string{"cache.md", "--repair"})
+	if err != nil || !opts.Enabled || opts.Attempts != 5 || len(rest) != 1 {
+		t.Errorf("parseRepairOptions() = %+v, %q, %v", opts, rest, err)
+	}
+	if opts, _, err := handler.parseRepairOptions([]string{"--repair", "--repair-attempts=2"}); err != nil || opts.Attempts != 2 {
+		t.Errorf("parseRepairOptions(--repair-attempts=2) = %+v, %v", opts, err)
+	}
+	for _, args := range [][]string{{"--repair", "--repair-attempts", "0"}, {"--repair-attempts", "2"}} {
+		if _, _, err := handler.parseRepairOptions(args); err == nil {
+			t.Errorf("Expected an error for %q", args)
+		}
+	}
+}
+
+func TestPlanHandler_ValidateRepair(t *testing.T) {
+	handler, planDir := newRepairTestHandler(t)
+	var prompts []string
+	handler.SetCLICaller(sequenceCaller(t, []string{
+		"<!-- morty:file cache.md -->\n" + brokenPlan("cache_v2"),
+		"<!-- morty:file cache.md -->\n" + fmt.Sprintf(doctorPlan, "cache"),
+	}, &prompts))
+
+	result, err := handler.Validate(context.Background(), []string{"--repair"})
+	if err != nil {
+		t.Fatalf("Validate() error: %v", err)
+	}
+	if !result.Success || !strings.Contains(result.Message, "第 2 轮后通过校验") {
+		t.Errorf("Expected the repair to converge in 2 rounds:\n%s", result.Message)
+	}
+	if len(prompts) != 2 {
+		t.Fatalf("Expected 2 repair rounds, got %d", len(prompts))
+	}
+	for _, want := range []string{"## 文件: cache.md", "[E002]", "# Plan: cache\n", "<!-- morty:file 文件名 -->"} {
+		if !strings.Contains(prompts[0], want) {
+			t.Errorf("Expected %q in the repair prompt:\n%s", want, prompts[0])
+		}
+	}
+	if strings.Contains(prompts[0], "e2e_test.md") {
+		t.Error("Expected passing files to be left out of the repair prompt")
+	}
+	if data, _ := os.ReadFile(filepath.Join(planDir, "cache.md")); string(data) != fmt.Sprintf(doctorPlan, "cache") {
+		t.Errorf("Unexpected repaired plan:\n%s", data)
+	}
+}
+
+func TestPlanHandler_ValidateRepairNoConvergence(t *testing.T) {
+	handler, planDir := newRepairTestHandler(t)
+	var prompts []string
+	handler.SetCLICaller(sequenceCaller(t, []string{
+		"<!-- morty:file cache.md -->\n" + brokenPlan("cache_v2"),
+		"<!-- morty:file cache.md -->\n" + brokenPlan("cache_v3"),
+		"<!-- morty:file cache.md -->\n" + brokenPlan("cache_v4"),
+	}, &prompts))
+
+	result, err := handler.Validate(context.Background(), []string{"--repair", "--repair-attempts", "2"})
+	if err != nil {
+		t.Fatalf("Validate() error: %v", err)
+	}
+	if result.Success || len(prompts) != 2 {
+		t.Errorf("Expected 2 failed rounds, got %d:\n%s", len(prompts), result.Message)
+	}
+	diff := fmt.Sprintf("--- %s\n-# Plan: cache\n+# Plan: cache_v3\n", filepath.Join(planDir, "cache.md"))
+	for _, want := range []string{"自动修复 2 轮后仍未通过校验", diff, "E002"} {
+		if !strings.Contains(result.Message, want) {
+			t.Errorf("Expected %q in:\n%s", want, result.Message)
+		}
+	}
+}
+
+func TestPlanHandler_ValidateRepairStopsWithoutChanges(t *testing.T) {
+	handler, planDir := newRepairTestHandler(t)
+	var prompts []string
+	handler.SetCLICaller(sequenceCaller(t, []string{
+		"<!-- morty:file cache.md -->\n" + brokenPlan("cache") + "<!-- morty:file README.md -->\n# Plans\n",
+	}, &prompts))
+
+	result, err := handler.Validate(context.Background(), []string{"--repair"})
+	if err != nil {
+		t.Fatalf("Validate() error: %v", err)
+	}
+	if result.Success || len(prompts) != 1 || !strings.Contains(result.Message, "没有修改任何文件") {
+		t.Errorf("Expected the loop to stop after an unchanged round (%d rounds):\n%s", len(prompts), result.Message)
+	}
+	if _, err := os.Stat(filepath.Join(planDir, "README.md")); err == nil {
+		t.Error("Expected files the repair was not asked for to be ignored")
+	}
+}
+
+func TestPlanHandler_ExecuteHeadlessRepair(t *testing.T) {
+	cfg := &mockConfig{}
+	cfg.SetWorkDir(setupTestDir(t))
+	handler := NewPlanHandler(cfg, &mockLogger{}, nil)
+	promptsDir := filepath.Join(cfg.GetWorkDir(), "prompts")
+	os.MkdirAll(promptsDir, 0755)
+	os.WriteFile(filepath.Join(promptsDir, "plan.md"), []byte("# Plan Prompt\n"), 0644)
+	handler.SetPromptsDir(promptsDir)
+
+	var prompts []string
+	handler.SetCLICaller(sequenceCaller(t, []string{
+		"<!-- morty:file cache.md -->\n" + brokenPlan("cache") + "<!-- morty:file e2e_test.md -->\n" + fmt.Sprintf(doctorPlan, "e2e_test"),
+		"<!-- morty:file cache.md -->\n" + fmt.Sprintf(doctorPlan, "cache"),
+	}, &prompts))
+
+	if _, err := handler.Execute(context.Background(), []string{"--repair"}); err == nil {
+		t.Error("Expected --repair to require --headless")
+	}
+	result, err := handler.Execute(context.Background(), []string{"--headless", "--repair"})
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if result.Repair == nil || !result.Repair.Converged || result.Repair.Attempts != 1 || len(prompts) != 2 {
+		t.Errorf("Unexpected repair: %+v after %d calls", result.Repair, len(prompts))
+	}
+}
@@ -356,6 +356,7 @@ func TestPlanHandler_Execute_newPlan(t *testing.T) {
 	cfg.SetWorkDir(tmpDir)
 	logger := &mockLogger{}
 	handler := NewPlanHandler(cfg, logger, nil)
+	handler.SetCLICaller(&mockAICliCaller{})
 
 	ctx := context.Background()
 	result, err := handler.Execute(ctx, []string{"--module", "test-module"})
@@ -393,6 +394,7 @@ func TestPlanHandler_Execute_withForceFlag(t *testing.T) {
 	cfg.SetWorkDir(tmpDir)
 	logger := &mockLogger{}
 	handler := NewPlanHandler(cfg, logger, nil)
+	handler.SetCLICaller(&mockAICliCaller{})
 
 	ctx := context.Background()
 
@@ -420,6 +422,7 @@ func TestPlanHandler_Execute_withShortForceFlag(t *testing.T) {
 	cfg.SetWorkDir(tmpDir)
 	logger := &mockLogger{}
 	handler := NewPlanHandler(cfg, logger, nil)
+	handler.SetCLICaller(&mockAICliCaller{})
 
 	ctx := context.Background()
 
@@ -458,6 +461,7 @@ func TestPlanHandler_Execute_inferModuleName(t *testing.T) {
 	cfg.SetWorkDir(tmpDir)
 	logger := &mockLogger{}
 	handler := NewPlanHandler(cfg, logger, nil)
+	handler.SetCLICaller(&mockAICliCaller{})
 
 	ctx := context.Background()
 	result, err := handler.Execute(ctx, nil)
@@ -478,6 +482,7 @@ func TestPlanHandler_Execute_createsPlanDir(t *testing.T) {
 	cfg.SetWorkDir(tmpDir)
 	logger := &mockLogger{}
 	handler := NewPlanHandler(cfg, logger, nil)
+	handler.SetCLICaller(&mockAICliCaller{})
 
 	ctx := context.Background()
 	_, err := handler.Execute(ctx, []string{"--module", "test"})
@@ -500,6 +505,7 @@ func TestPlanHandler_Execute_returnsCorrectPlanResult(t *testing.T) {
 	cfg.SetWorkDir(tmpDir)
 	logger := &mockLogger{}
 	handler := NewPlanHandler(cfg, logger, nil)
+	handler.SetCLICaller(&mockAICliCaller{})
 
 	ctx := context.Background()
 	result, err := handler.Execute(ctx, []string{"--module", "test-module"})
@@ -529,6 +535,34 @@ func TestPlanHandler_Execute_returnsCorrectPlanResult(t *testing.T) {
 	}
 }
 
+func TestPlanHandler_Execute_keepsPlanWrittenByCLI(t *testing.T) {
+	tmpDir := setupTestDir(t)
+
+	cfg := &mockConfig{}
+	cfg.SetWorkDir(tmpDir)
+	handler := NewPlanHandler(cfg, &mockLogger{}, nil)
+
+	// The interactive session writes the plan itself and prints nothing
+	planPath := handler.generatePlanPath("test-module")
+	handler.SetCLICaller(&mockAICliCaller{
+		getBaseCallerFunc: func() callcli.Caller {
+			return &mockCaller{
+				callWithOptionsFunc: func(ctx context.Context, name string, args []string, opts callcli.Options) (*callcli.Result, error) {
+					return &callcli.Result{ExitCode: 0}, os.WriteFile(planPath, []byte("# Plan: test-module\n"), 0644)
+				},
+			}
+		},
+	})
+
+	if _, err := handler.Execute(context.Background(), []string{"--module", "test-module", "setup"}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	content, err := os.ReadFile(planPath)
+	if err != nil || string(content) != "# Plan: test-module\n" {
+		t.Errorf("Plan written by the CLI was replaced: %q, %v", content, err)
+	}
+}
+
 func TestPlanHandler_Execute_contextCancellation(t *testing.T) {
 	tmpDir := setupTestDir(t)
 
@@ -579,6 +613,7 @@ func TestPlanHandler_Execute_withJobArgs(t *testing.T) {
 	cfg.SetWorkDir(tmpDir)
 	logger := &mockLogger{}
 	handler := NewPlanHandler(cfg, logger, nil)
+	handler.SetCLICaller(&mockAICliCaller{})
 
 	ctx := context.Background()
 	result, err := handler.Execute(ctx, []string{"--module", "test-module", "setup", "build", "test"})
@@ -872,7 +907,12 @@ func TestPlanHandler_getPlanPromptPath(t *testing.T) {
 		{
 			name:       "config override",
 			configPath: "custom/prompt.md",
-			wantSuffix: "custom/prompt.md",
+			wantSuffix: "prompts/prompt.md",
+		},
+		{
+			name:       "absolute config override",
+			configPath: "/etc/morty/plan.md",
+			wantSuffix: "/etc/morty/plan.md",
 		},
 	}
 
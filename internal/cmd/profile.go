@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"github.com/morty/morty/internal/config"
+	"github.com/morty/morty/internal/git"
+	"github.com/morty/morty/internal/logging"
+)
+
+// activeProfile returns the configuration profile applied to cfg, or ""
+// when none is.
+func activeProfile(cfg config.Manager) string {
+	if loader, ok := cfg.(*config.Loader); ok {
+		return loader.Profile()
+	}
+	return ""
+}
+
+// recordProfile stores the profile of this run in status.json, so it can be
+// told which settings the jobs ran with.
+func (h *DoingHandler) recordProfile() error {
+	profile := activeProfile(h.cfg)
+	status := h.stateManager.GetState()
+	if status == nil || status.Global.Profile == profile {
+		return nil
+	}
+	status.Global.Profile = profile
+	if profile != "" {
+		h.logger.Info("Using config profile", logging.String("profile", profile))
+	}
+	return h.stateManager.Save(status)
+}
+
+// commitTrailers returns the trailers added to job commits.
+func (h *DoingHandler) commitTrailers() []git.Trailer {
+	return []git.Trailer{{Key: git.TrailerProfile, Value: activeProfile(h.cfg)}}
+}
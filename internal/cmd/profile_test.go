@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/morty/morty/internal/config"
+	"github.com/morty/morty/internal/git"
+	"github.com/morty/morty/internal/state"
+)
+
+// TestDoingHandler_RecordProfile tests that the active profile is written
+// to status.json and to the commit trailers.
+func TestDoingHandler_RecordProfile(t *testing.T) {
+	handler, _ := newContinueTestHandler(t, false)
+	if err := handler.loadStatus(); err != nil {
+		t.Fatalf("loadStatus() error: %v", err)
+	}
+	statusFile := handler.getStatusFilePath()
+
+	userPath := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(userPath, []byte(`{"profiles": {"ci": {"git": {"auto_commit": false}}}}`), 0644)
+	loader := config.NewLoader()
+	loader.SetProfile("ci")
+	if err := loader.LoadWithMerge(userPath); err != nil {
+		t.Fatalf("LoadWithMerge() error: %v", err)
+	}
+	handler.cfg = loader
+
+	if err := handler.recordProfile(); err != nil {
+		t.Fatalf("recordProfile() error: %v", err)
+	}
+	data, err := os.ReadFile(statusFile)
+	if err != nil {
+		t.Fatalf("Failed to read status: %v", err)
+	}
+	var saved state.ExecutionStatus
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("Failed to parse status: %v", err)
+	}
+	if got := saved.Global.Profile; got != "ci" {
+		t.Errorf("status.json profile = %q, want ci", got)
+	}
+
+	trailers := handler.commitTrailers()
+	if len(trailers) != 1 || trailers[0] != (git.Trailer{Key: git.TrailerProfile, Value: "ci"}) {
+		t.Errorf("Unexpected trailers: %v", trailers)
+	}
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/morty/morty/internal/config"
+	"github.com/morty/morty/internal/logging"
+	"github.com/morty/morty/internal/parser/prompt"
+)
+
+// PromptsResult represents the result of the prompts check command.
+type PromptsResult struct {
+	Results []prompt.CheckResult
+	Failed  int
+}
+
+// PromptsHandler handles the prompts command.
+type PromptsHandler struct {
+	cfg    config.Manager
+	logger logging.Logger
+	paths  *config.Paths
+	out    io.Writer
+}
+
+// NewPromptsHandler creates a new PromptsHandler instance.
+func NewPromptsHandler(cfg config.Manager, logger logging.Logger) *PromptsHandler {
+	var paths *config.Paths
+	if loader, ok := cfg.(*config.Loader); ok {
+		paths = config.NewPathsWithLoader(loader)
+	} else {
+		paths = config.NewPaths()
+	}
+	if cfg != nil && cfg.GetWorkDir() != "" {
+		paths.SetWorkDir(cfg.GetWorkDir())
+	}
+
+	return &PromptsHandler{
+		cfg:    cfg,
+		logger: logger,
+		paths:  paths,
+		out:    os.Stdout,
+	}
+}
+
+// SetOutput sets the writer the report is printed to.
+func (h *PromptsHandler) SetOutput(w io.Writer) {
+	h.out = w
+}
+
+// SetPromptsDir sets a custom prompts directory (for testing).
+func (h *PromptsHandler) SetPromptsDir(dir string) {
+	h.paths.SetPromptsDir(dir)
+}
+
+// Check parses the templates of the given phases, or of all phases, and
+// reports variables that are unknown to the phase or required but never
+// used. It fails when any phase has a problem.
+func (h *PromptsHandler) Check(ctx context.Context, args []string) (*PromptsResult, error) {
+	logger := h.logger.WithContext(ctx)
+
+	var phases []prompt.Phase
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			return nil, fmt.Errorf("未知参数: %s", arg)
+		}
+		phase, ok := prompt.LookupPhase(arg)
+		if !ok {
+			return nil, fmt.Errorf("未知阶段: %s (可选: %s)", arg, strings.Join(prompt.PhaseNames(), ", "))
+		}
+		phases = append(phases, phase)
+	}
+	if len(phases) == 0 {
+		phases = prompt.Phases
+	}
+
+	engine := newPromptEngine(h.cfg, h.paths)
+	result := &PromptsResult{}
+	for _, phase := range phases {
+		check := engine.Check(phase)
+		result.Results = append(result.Results, check)
+		if !check.OK() {
+			result.Failed++
+		}
+	}
+
+	logger.Info("Checked prompt templates",
+		logging.Int("phases", len(result.Results)),
+		logging.Int("failed", result.Failed),
+	)
+	fmt.Fprint(h.out, formatPromptChecks(result))
+
+	if result.Failed > 0 {
+		return result, fmt.Errorf("提示词模板检查失败: %d 个阶段有问题", result.Failed)
+	}
+	return result, nil
+}
+
+// formatPromptChecks renders one block per phase with the files it was
+// rendered from and the problems found.
+func formatPromptChecks(result *PromptsResult) string {
+	var sb strings.Builder
+	for _, check := range result.Results {
+		mark := "✅"
+		if !check.OK() {
+			mark = "❌"
+		}
+		fmt.Fprintf(&sb, "%s %s\n", mark, check.Phase)
+		for _, source := range check.Sources {
+			fmt.Fprintf(&sb, "   %s\n", source)
+		}
+		for _, problem := range promptProblems(check) {
+			fmt.Fprintf(&sb, "   - %s\n", problem)
+		}
+	}
+	fmt.Fprintf(&sb, "\n检查完成: %d 个阶段通过, %d 个阶段有问题\n", len(result.Results)-result.Failed, result.Failed)
+	return sb.String()
+}
+
+// promptProblems describes the problems of a phase check.
+func promptProblems(check prompt.CheckResult) []string {
+	if check.Err != nil {
+		return []string{check.Err.Error()}
+	}
+	var problems []string
+	for _, name := range check.Unknown {
+		problems = append(problems, fmt.Sprintf("未知变量 .%s: 阶段 %s 不提供这个变量", name, check.Phase))
+	}
+	for _, name := range check.Missing {
+		problems = append(problems, fmt.Sprintf("缺少变量 .%s: 模板没有使用这个必需的变量", name))
+	}
+	return problems
+}
+
+// newPromptEngine returns the engine prompts are rendered with. Templates
+// in the project's .morty/prompts override those in prompts.dir, which
+// override the built-in phase templates and partials. prompts.research,
+// prompts.plan and prompts.doing pin the instruction files when they name
+// another file than the default.
+func newPromptEngine(cfg config.Manager, paths *config.Paths) *prompt.Engine {
+	promptsDir := paths.GetPromptsDir()
+	engine := prompt.NewEngine(filepath.Join(paths.GetWorkDir(), "prompts"), promptsDir)
+	engine.SetLanguage(promptLanguage(cfg))
+	if cfg == nil {
+		return engine
+	}
+
+	for _, key := range []string{"research", "plan", "doing"} {
+		configured := cfg.GetString("prompts." + key)
+		switch {
+		case configured == "":
+		case filepath.IsAbs(configured):
+			engine.SetSource(key+".md", configured)
+		case filepath.Base(configured) != key+".md":
+			engine.SetSource(key+".md", filepath.Join(promptsDir, filepath.Base(configured)))
+		}
+	}
+	return engine
+}
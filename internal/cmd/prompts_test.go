@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newPromptsTestHandler creates a project with the three instruction files
+// and a handler for it.
+func newPromptsTestHandler(t *testing.T) (*PromptsHandler, *mockConfig, *bytes.Buffer) {
+	t.Helper()
+	cfg := &mockConfig{}
+	cfg.SetWorkDir(setupTestDir(t))
+	promptsDir := filepath.Join(t.TempDir(), "prompts")
+	os.MkdirAll(promptsDir, 0755)
+	for _, name := range []string{"research.md", "plan.md", "doing.md"} {
+		os.WriteFile(filepath.Join(promptsDir, name), []byte("# Prompt\n"), 0644)
+	}
+
+	var out bytes.Buffer
+	handler := NewPromptsHandler(cfg, &mockLogger{})
+	handler.SetPromptsDir(promptsDir)
+	handler.SetOutput(&out)
+	return handler, cfg, &out
+}
+
+func TestPromptsHandler_Check(t *testing.T) {
+	handler, cfg, out := newPromptsTestHandler(t)
+
+	result, err := handler.Check(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Check() error: %v\n%s", err, out)
+	}
+	if len(result.Results) != 8 || !strings.Contains(out.String(), "检查完成: 8 个阶段通过, 0 个阶段有问题") {
+		t.Errorf("Unexpected report:\n%s", out)
+	}
+
+	// A project override that uses an unknown variable and drops a required one
+	override := filepath.Join(cfg.GetWorkDir(), "prompts", "phases", "plan.md")
+	os.MkdirAll(filepath.Dir(override), 0755)
+	os.WriteFile(override, []byte("# {{.title}}\n\n{{.research}}\n"), 0644)
+
+	out.Reset()
+	result, err = handler.Check(context.Background(), []string{"plan", "research"})
+	if err == nil || result.Failed != 1 {
+		t.Fatalf("Expected one failed phase, got %+v, %v", result, err)
+	}
+	for _, want := range []string{"❌ plan\n   " + override, "未知变量 .title", "缺少变量 .module", "✅ research"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, out)
+		}
+	}
+
+	for _, args := range [][]string{{"deploy"}, {"--json"}} {
+		if _, err := handler.Check(context.Background(), args); err == nil {
+			t.Errorf("Expected an error for %q", args)
+		}
+	}
+}
+
+func TestPlanHandler_PromptOverride(t *testing.T) {
+	handler, cfg := newModuleTestProject(t)
+	partial := filepath.Join(cfg.GetWorkDir(), "prompts", "partials", "module_output.md")
+	os.MkdirAll(filepath.Dir(partial), 0755)
+	os.WriteFile(partial, []byte("Only {{.module}}, please.\n\n<!-- morty:file {{.module}}.md -->\n"), 0644)
+
+	var prompts []string
+	handler.SetCLICaller(sequenceCaller(t, []string{
+		"<!-- morty:file metrics.md -->\n" + strings.Replace(doctorPlan, "%s", "metrics", 1),
+	}, &prompts))
+	if _, err := handler.Add(context.Background(), []string{"metrics"}); err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+	if !strings.Contains(prompts[0], "Only metrics, please.") || strings.Contains(prompts[0], "只输出模块") {
+		t.Errorf("Expected the project partial in the prompt:\n%s", prompts[0])
+	}
+}
+
+func TestPrompts_RenderFromEmptyProject(t *testing.T) {
+	cfg := &mockConfig{}
+	cfg.SetWorkDir(setupTestDir(t))
+	promptsDir := filepath.Join(cfg.GetWorkDir(), "prompts")
+
+	research := NewResearchHandler(cfg, &mockLogger{})
+	research.SetPromptsDir(promptsDir)
+	researchPrompt, err := research.researchPrompt("auth", HeadlessOptions{})
+	if err != nil {
+		t.Fatalf("researchPrompt() error: %v", err)
+	}
+
+	plan := NewPlanHandler(cfg, &mockLogger{}, nil)
+	plan.SetPromptsDir(promptsDir)
+	planPrompt, err := plan.planPrompt(context.Background(), "cli", HeadlessOptions{})
+	if err != nil {
+		t.Fatalf("planPrompt() error: %v", err)
+	}
+
+	doingPrompt, err := newPromptEngine(cfg, plan.paths).Render("phases/doing.md", map[string]interface{}{
+		"module":          "cli",
+		"job":             "job_1",
+		"tasks":           []map[string]interface{}{{"index": 1, "description": "Parse flags", "completed": false}},
+		"tasks_total":     1,
+		"tasks_completed": 0,
+		"plan":            "# Plan: cli",
+		"research":        "",
+		"digests":         nil,
+	})
+	if err != nil {
+		t.Fatalf("Render(phases/doing.md) error: %v", err)
+	}
+
+	// The built-in instruction files start with these headings
+	for heading, got := range map[string]string{
+		"# Research\n": researchPrompt,
+		"# Plan\n":     planPrompt,
+		"# Doing\n":    doingPrompt,
+	} {
+		if !strings.Contains(got, heading) {
+			t.Errorf("Expected the built-in instructions %q in:\n%s", heading, got)
+		}
+	}
+}
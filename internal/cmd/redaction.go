@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/morty/morty/internal/config"
+	"github.com/morty/morty/internal/logging"
+	"github.com/morty/morty/internal/redact"
+)
+
+// redactionCanaryEnv is the environment variable holding the canary secret
+// of the redaction test mode.
+const redactionCanaryEnv = "MORTY_REDACTION_CANARY"
+
+// startRedactionTest starts the redaction test mode when
+// redaction.test_mode is set. A random canary secret is registered with the
+// redactor, exported to the AI CLI's environment, logged and appended to
+// every job prompt. The returned function checks everything the run wrote
+// to its log files for the canary and other secrets, and fails on a leak.
+func (h *DoingHandler) startRedactionTest() func() error {
+	h.redactionCanary = ""
+	if h.cfg == nil || !h.cfg.GetBool("redaction.test_mode") {
+		return func() error { return nil }
+	}
+	previous := redact.Default()
+	if previous == nil {
+		return func() error {
+			return fmt.Errorf("脱敏测试需要开启 redaction.enabled")
+		}
+	}
+
+	canary, err := newRedactionCanary()
+	if err != nil {
+		return func() error { return fmt.Errorf("生成脱敏测试密钥失败: %w", err) }
+	}
+	files := h.redactionTestFiles()
+	offsets := fileSizes(files())
+
+	redactor := previous.WithValue(redactionCanaryEnv, canary)
+	redact.SetDefault(redactor)
+	previousEnv, hadEnv := os.LookupEnv(redactionCanaryEnv)
+	os.Setenv(redactionCanaryEnv, canary)
+	h.redactionCanary = canary
+	h.logger.Info("Redaction test mode enabled", logging.String("canary", canary))
+
+	return func() error {
+		h.redactionCanary = ""
+		redact.SetDefault(previous)
+		if hadEnv {
+			os.Setenv(redactionCanaryEnv, previousEnv)
+		} else {
+			os.Unsetenv(redactionCanaryEnv)
+		}
+
+		var leaks []string
+		for _, path := range files() {
+			data, err := readFrom(path, offsets[path])
+			if err != nil {
+				h.logger.Warn("Failed to check file for secrets",
+					logging.String("file", path),
+					logging.String("error", err.Error()))
+				continue
+			}
+			if found := redactor.Leaks(data); len(found) > 0 {
+				leaks = append(leaks, fmt.Sprintf("%s (%s)", path, strings.Join(found, ", ")))
+			}
+		}
+		if len(leaks) > 0 {
+			return fmt.Errorf("脱敏测试失败, 以下文件包含未脱敏的密钥: %s", strings.Join(leaks, "; "))
+		}
+		h.logger.Info("Redaction test passed")
+		return nil
+	}
+}
+
+// redactionTestFiles returns a function listing the files checked by the
+// redaction test mode: job logs, transcripts, AI CLI logs, the global log
+// file and the trace file.
+func (h *DoingHandler) redactionTestFiles() func() []string {
+	dirs := []string{filepath.Join(h.cfg.GetWorkDir(), "logs"), h.cfg.GetLogDir()}
+	extra := []string{h.cfg.GetString("logging.file.path", config.DefaultLoggingFilePath)}
+	if h.cfg.GetBool("tracing.enabled") {
+		extra = append(extra, h.cfg.GetString("tracing.file", config.DefaultTracingFile))
+	}
+
+	return func() []string {
+		seen := make(map[string]bool)
+		var files []string
+		add := func(path string) {
+			if path == "" || seen[path] {
+				return
+			}
+			if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+				seen[path] = true
+				files = append(files, path)
+			}
+		}
+		for _, dir := range dirs {
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				continue
+			}
+			for _, entry := range entries {
+				add(filepath.Join(dir, entry.Name()))
+			}
+		}
+		for _, path := range extra {
+			add(path)
+		}
+		sort.Strings(files)
+		return files
+	}
+}
+
+// newRedactionCanary returns a random fake secret.
+func newRedactionCanary() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "morty-canary-" + hex.EncodeToString(b), nil
+}
+
+// fileSizes returns the current size of each file.
+func fileSizes(files []string) map[string]int64 {
+	sizes := make(map[string]int64, len(files))
+	for _, path := range files {
+		if info, err := os.Stat(path); err == nil {
+			sizes[path] = info.Size()
+		}
+	}
+	return sizes
+}
+
+// readFrom reads a file from offset, or from the start when the file has
+// been truncated or rotated since.
+func readFrom(path string, offset int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if info, err := f.Stat(); err == nil && info.Size() < offset {
+		offset = 0
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(f)
+}
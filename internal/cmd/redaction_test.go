@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/morty/morty/internal/redact"
+)
+
+// leakEngine writes the redaction test canary to a job log, masked or not.
+type leakEngine struct {
+	*stubEngine
+	masked bool
+}
+
+func (e *leakEngine) ExecuteJob(ctx context.Context, module, job string) error {
+	line := "API key in use: " + os.Getenv(redactionCanaryEnv) + "\n"
+	if e.masked {
+		line = redact.String(line)
+	}
+	dir := filepath.Join(e.handler.cfg.GetWorkDir(), "logs")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, module+"_"+job+".log"), []byte(line), 0644); err != nil {
+		return err
+	}
+	return e.stubEngine.ExecuteJob(ctx, module, job)
+}
+
+func newRedactionTestHandler(t *testing.T, masked bool) *DoingHandler {
+	t.Helper()
+	handler, engine := newContinueTestHandler(t, true)
+	engine.fail = nil
+	cfg := handler.cfg.(*mockConfig)
+	cfg.values["redaction.test_mode"] = true
+	cfg.values["logging.file.path"] = filepath.Join(t.TempDir(), "morty.log")
+	handler.SetExecutor(&leakEngine{stubEngine: engine, masked: masked})
+	return handler
+}
+
+// TestDoingHandler_RedactionTest tests that the redaction test mode passes
+// when the canary is masked and fails the run when it leaks.
+func TestDoingHandler_RedactionTest(t *testing.T) {
+	handler := newRedactionTestHandler(t, true)
+	if _, err := handler.Execute(context.Background(), nil); err != nil {
+		t.Fatalf("Expected masked canary to pass, got %v", err)
+	}
+
+	handler = newRedactionTestHandler(t, false)
+	result, err := handler.Execute(context.Background(), nil)
+	if err == nil || !strings.Contains(err.Error(), "脱敏测试失败") || !strings.Contains(err.Error(), redactionCanaryEnv) {
+		t.Fatalf("Expected leaked canary to fail the run, got %v", err)
+	}
+	if result.ExitCode != 1 {
+		t.Errorf("Expected exit code 1, got %d", result.ExitCode)
+	}
+
+	if _, ok := os.LookupEnv(redactionCanaryEnv); ok {
+		t.Error("Expected canary environment variable to be removed after the run")
+	}
+	if redact.Default().String("x "+handler.redactionCanary) != "x "+handler.redactionCanary {
+		t.Error("Expected canary to be removed from the default redactor")
+	}
+}
+
+func TestDoingHandler_RedactionTestDisabledRedaction(t *testing.T) {
+	handler := newRedactionTestHandler(t, true)
+	previous := redact.Default()
+	redact.SetDefault(nil)
+	defer redact.SetDefault(previous)
+
+	if _, err := handler.Execute(context.Background(), nil); err == nil || !strings.Contains(err.Error(), "redaction.enabled") {
+		t.Errorf("Expected test mode to require redaction, got %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -32,6 +33,7 @@ type ResearchHandler struct {
 	logger     logging.Logger
 	paths      *config.Paths
 	cliCaller  callcli.AICliCaller
+	out        io.Writer
 }
 
 // NewResearchHandler creates a new ResearchHandler instance.
@@ -40,14 +42,8 @@ func NewResearchHandler(cfg config.Manager, logger logging.Logger) *ResearchHand
 	var paths *config.Paths
 	if loader, ok := cfg.(*config.Loader); ok {
 		paths = config.NewPathsWithLoader(loader)
-		if os.Getenv("MORTY_DEBUG") != "" {
-			fmt.Fprintf(os.Stderr, "DEBUG: NewResearchHandler using Loader\n")
-		}
 	} else {
 		paths = config.NewPaths()
-		if os.Getenv("MORTY_DEBUG") != "" {
-			fmt.Fprintf(os.Stderr, "DEBUG: NewResearchHandler using NewPaths (cfg type: %T)\n", cfg)
-		}
 	}
 
 	return &ResearchHandler{
@@ -55,9 +51,15 @@ func NewResearchHandler(cfg config.Manager, logger logging.Logger) *ResearchHand
 		logger:    logger,
 		paths:     paths,
 		cliCaller: callcli.NewAICliCallerWithLoader(cfg),
+		out:       os.Stdout,
 	}
 }
 
+// SetOutput sets the writer search results are printed to.
+func (h *ResearchHandler) SetOutput(w io.Writer) {
+	h.out = w
+}
+
 // SetCLICaller sets a custom CLI caller (useful for testing).
 func (h *ResearchHandler) SetCLICaller(caller callcli.AICliCaller) {
 	h.cliCaller = caller
@@ -73,8 +75,18 @@ func (h *ResearchHandler) SetPromptsDir(dir string) {
 func (h *ResearchHandler) Execute(ctx context.Context, args []string) (*ResearchResult, error) {
 	logger := h.logger.WithContext(ctx)
 
+	headless, args, err := parseHeadlessOptions(args)
+	if err != nil {
+		return nil, err
+	}
+
 	// Parse topic from args or prompt interactively
-	topic, err := h.parseTopic(args)
+	var topic string
+	if headless.Enabled {
+		topic, err = headlessTopic(args, headless.Input)
+	} else {
+		topic, err = h.parseTopic(args)
+	}
 	if err != nil {
 		logger.Error("Failed to get research topic", logging.String("error", err.Error()))
 		return nil, fmt.Errorf("failed to get research topic: %w", err)
@@ -109,15 +121,19 @@ func (h *ResearchHandler) Execute(ctx context.Context, args []string) (*Research
 	default:
 	}
 
-	// Load research prompt
-	prompt, err := h.loadResearchPrompt()
+	// Render research prompt
+	prompt, err := h.researchPrompt(topic, headless)
 	if err != nil {
-		logger.Error("Failed to load research prompt", logging.String("error", err.Error()))
+		logger.Error("Failed to render research prompt", logging.String("error", err.Error()))
 		result.Err = err
-		return result, fmt.Errorf("failed to load research prompt: %w", err)
+		return result, fmt.Errorf("failed to render research prompt: %w", err)
 	}
 
-	logger.Info("Loaded research prompt", logging.String("prompt_path", h.getResearchPromptPath()))
+	logger.Info("Rendered research prompt", logging.String("prompt_path", h.getResearchPromptPath()))
+
+	if headless.Enabled {
+		return h.executeHeadless(ctx, result, prompt, headless)
+	}
 
 	// Build and execute Claude Code command
 	startTime := time.Now()
@@ -144,17 +160,22 @@ func (h *ResearchHandler) Execute(ctx context.Context, args []string) (*Research
 	return result, nil
 }
 
-// loadResearchPrompt loads the research prompt from prompts/research.md.
-func (h *ResearchHandler) loadResearchPrompt() (string, error) {
-	if os.Getenv("MORTY_DEBUG") != "" {
-		fmt.Printf("DEBUG: loadResearchPrompt called\n")
+// researchPrompt renders the research phase prompt for topic; headless
+// runs also get the seed requirements and the non-interactive instructions.
+func (h *ResearchHandler) researchPrompt(topic string, opts HeadlessOptions) (string, error) {
+	data, err := seedData(opts.Input)
+	if err != nil {
+		return "", err
 	}
+	data["topic"] = topic
+	data["headless"] = opts.Enabled
+	return newPromptEngine(h.cfg, h.paths).Render("phases/research.md", data)
+}
 
+// loadResearchPrompt loads the research prompt from prompts/research.md.
+func (h *ResearchHandler) loadResearchPrompt() (string, error) {
 	promptPath := h.getResearchPromptPath()
-
-	if os.Getenv("MORTY_DEBUG") != "" {
-		fmt.Printf("DEBUG: loadResearchPrompt got path: %s\n", promptPath)
-	}
+	h.logger.Debug("Loading research prompt", logging.String("path", promptPath))
 
 	// Read the prompt file
 	content, err := os.ReadFile(promptPath)
@@ -175,15 +196,15 @@ func (h *ResearchHandler) getResearchPromptPath() string {
 		if promptPath := h.cfg.GetString("prompts.research"); promptPath != "" {
 			// If it's an absolute path, use it directly
 			if filepath.IsAbs(promptPath) {
-				return promptPath
+				return config.LocalizedPromptPath(promptPath, promptLanguage(h.cfg))
 			}
 			// If it's a relative path, resolve it relative to prompts dir
-			return filepath.Join(promptsDir, filepath.Base(promptPath))
+			return config.LocalizedPromptPath(filepath.Join(promptsDir, filepath.Base(promptPath)), promptLanguage(h.cfg))
 		}
 	}
 
 	// Default to research.md in prompts directory
-	return filepath.Join(promptsDir, "research.md")
+	return config.LocalizedPromptPath(filepath.Join(promptsDir, "research.md"), promptLanguage(h.cfg))
 }
 
 // buildClaudeCommand builds the Claude Code command arguments.
@@ -201,14 +222,11 @@ func (h *ResearchHandler) buildClaudeCommand(topic, prompt string) []string {
 	return args
 }
 
-// executeClaudeCode executes Claude Code with the given topic and prompt.
+// executeClaudeCode executes Claude Code with the rendered prompt for topic.
 // Returns the exit code and any error that occurred.
-func (h *ResearchHandler) executeClaudeCode(ctx context.Context, topic, prompt string) (int, error) {
+func (h *ResearchHandler) executeClaudeCode(ctx context.Context, topic, fullPrompt string) (int, error) {
 	logger := h.logger.WithContext(ctx)
 
-	// Build the full prompt with topic context
-	fullPrompt := fmt.Sprintf("# Research Topic: %s\n\n%s", topic, prompt)
-
 	logger.Info("Executing Claude Code in interactive mode",
 		logging.String("topic", topic),
 		logging.String("cli_path", h.cliCaller.GetCLIPath()),
@@ -247,6 +265,57 @@ func (h *ResearchHandler) executeClaudeCode(ctx context.Context, topic, prompt s
 	return result.ExitCode, nil
 }
 
+// executeHeadless runs the research without a TTY and writes the agent's
+// answer to the research document.
+func (h *ResearchHandler) executeHeadless(ctx context.Context, result *ResearchResult, fullPrompt string, opts HeadlessOptions) (*ResearchResult, error) {
+	logger := h.logger.WithContext(ctx)
+	timeout := headlessTimeout(h.cfg, opts)
+
+	logger.Info("Executing Claude Code in headless mode",
+		logging.String("topic", result.Topic),
+		logging.String("cli_path", h.cliCaller.GetCLIPath()),
+		logging.Any("timeout", timeout),
+	)
+
+	startTime := time.Now()
+	content, exitCode, err := runHeadless(ctx, h.cliCaller, fullPrompt, timeout)
+	result.Duration = time.Since(startTime)
+	result.ExitCode = exitCode
+	if err != nil {
+		logger.Error("Claude Code execution failed",
+			logging.String("error", err.Error()),
+			logging.Int("exit_code", exitCode),
+		)
+		result.Err = err
+		return result, fmt.Errorf("claude code execution failed: %w", err)
+	}
+
+	if err := os.WriteFile(result.OutputPath, []byte(content+"\n"), 0644); err != nil {
+		result.Err = err
+		return result, fmt.Errorf("failed to write research file: %w", err)
+	}
+	result.Content = content
+
+	logger.Info("Research completed",
+		logging.String("topic", result.Topic),
+		logging.String("output_path", result.OutputPath),
+		logging.Any("duration", result.Duration),
+	)
+	return result, nil
+}
+
+// headlessTopic returns the topic of a headless research: the args, or the
+// name of the seed requirements file. It never prompts.
+func headlessTopic(args []string, input string) (string, error) {
+	if topic := strings.TrimSpace(strings.Join(args, " ")); topic != "" {
+		return topic, nil
+	}
+	if input != "" {
+		return strings.TrimSuffix(filepath.Base(input), filepath.Ext(input)), nil
+	}
+	return "", fmt.Errorf("非交互模式需要研究主题或 --input 需求文件")
+}
+
 // parseTopic extracts the topic from command arguments or prompts interactively.
 func (h *ResearchHandler) parseTopic(args []string) (string, error) {
 	// If arguments provided, use them as the topic
@@ -0,0 +1,154 @@
+// Package cmd provides command handlers for Morty CLI commands.
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/morty/morty/internal/config"
+	"github.com/morty/morty/internal/logging"
+	"github.com/morty/morty/internal/research"
+)
+
+// snippetRunes bounds the preview of a search hit.
+const snippetRunes = 120
+
+// SearchOptions holds the options of research search.
+type SearchOptions struct {
+	Query string
+	TopK  int  // -k, defaults to research.top_k
+	JSON  bool // --json
+}
+
+// SearchHit is a research search result.
+type SearchHit struct {
+	File    string  `json:"file"`
+	Heading string  `json:"heading"`
+	Line    int     `json:"line"`
+	Score   float64 `json:"score"`
+	Text    string  `json:"text"`
+}
+
+// SearchResult is the result of research search.
+type SearchResult struct {
+	Query    string      `json:"query"`
+	Sections int         `json:"sections"` // indexed sections
+	Hits     []SearchHit `json:"hits"`
+}
+
+// Search searches the research documents for the query in args and prints
+// the best matching sections.
+func (h *ResearchHandler) Search(ctx context.Context, args []string) (*SearchResult, error) {
+	logger := h.logger.WithContext(ctx)
+
+	opts, err := h.parseSearchOptions(args)
+	if err != nil {
+		return nil, err
+	}
+
+	ix, err := research.Build(h.paths.GetResearchDir())
+	if err != nil {
+		logger.Error("Failed to index research files", logging.String("error", err.Error()))
+		return nil, err
+	}
+	if ix.Len() == 0 {
+		return nil, fmt.Errorf("没有研究文档: %s", h.paths.GetResearchDir())
+	}
+
+	result := &SearchResult{Query: opts.Query, Sections: ix.Len(), Hits: []SearchHit{}}
+	for _, hit := range ix.Search(opts.Query, opts.TopK) {
+		result.Hits = append(result.Hits, SearchHit{
+			File:    hit.File,
+			Heading: hit.Heading,
+			Line:    hit.Line,
+			Score:   hit.Score,
+			Text:    hit.Text,
+		})
+	}
+
+	logger.Debug("Research search completed",
+		logging.String("query", opts.Query),
+		logging.Int("hits", len(result.Hits)),
+	)
+
+	if opts.JSON {
+		// Headings are joined with ">", keep it readable
+		enc := json.NewEncoder(h.out)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		return result, enc.Encode(result)
+	}
+	h.printSearchResult(result)
+	return result, nil
+}
+
+// parseSearchOptions parses research search arguments.
+func (h *ResearchHandler) parseSearchOptions(args []string) (SearchOptions, error) {
+	opts := SearchOptions{TopK: config.DefaultResearchTopK}
+	if h.cfg != nil {
+		opts.TopK = h.cfg.GetInt("research.top_k", config.DefaultResearchTopK)
+	}
+
+	var words []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--json":
+			opts.JSON = true
+		case arg == "-k" || arg == "--top-k":
+			if i+1 >= len(args) {
+				return opts, fmt.Errorf("%s 需要一个值", arg)
+			}
+			i++
+			k, err := strconv.Atoi(args[i])
+			if err != nil || k < 1 {
+				return opts, fmt.Errorf("无效的结果数量: %s", args[i])
+			}
+			opts.TopK = k
+		case strings.HasPrefix(arg, "-"):
+			return opts, fmt.Errorf("未知参数: %s", arg)
+		default:
+			words = append(words, arg)
+		}
+	}
+
+	opts.Query = strings.TrimSpace(strings.Join(words, " "))
+	if opts.Query == "" {
+		return opts, fmt.Errorf("搜索内容不能为空")
+	}
+	return opts, nil
+}
+
+// printSearchResult prints the hits with a one-line preview each.
+func (h *ResearchHandler) printSearchResult(result *SearchResult) {
+	if len(result.Hits) == 0 {
+		fmt.Fprintf(h.out, "没有找到与 %q 相关的内容 (共 %d 个段落)\n", result.Query, result.Sections)
+		return
+	}
+	for i, hit := range result.Hits {
+		section := research.Section{File: hit.File, Heading: hit.Heading, Line: hit.Line}
+		fmt.Fprintf(h.out, "[%d] %s  (%.2f)\n", i+1, section.Citation(), hit.Score)
+		if preview := snippet(hit.Text); preview != "" {
+			fmt.Fprintf(h.out, "    %s\n", preview)
+		}
+	}
+	fmt.Fprintf(h.out, "\n%d 个结果 (共 %d 个段落)\n", len(result.Hits), result.Sections)
+}
+
+// snippet returns the first line of a section's body, shortened.
+func snippet(text string) string {
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || i == 0 && strings.HasPrefix(line, "#") {
+			continue
+		}
+		if runes := []rune(line); len(runes) > snippetRunes {
+			return string(runes[:snippetRunes]) + "..."
+		}
+		return line
+	}
+	return ""
+}
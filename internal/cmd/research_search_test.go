@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeResearch creates research documents about caching and login.
+func writeResearch(t *testing.T, dir string) {
+	t.Helper()
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(filepath.Join(dir, "cache.md"), []byte("# 缓存\n\n## 淘汰策略\n\n使用 LRU 淘汰最久未使用的条目。\n\n## 过期\n\n条目在 TTL 后过期。\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "auth.md"), []byte("# 认证\n\n用户使用邮箱和密码登录。\n"), 0644)
+}
+
+func TestResearchHandler_Search(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	handler := NewResearchHandler(&mockConfig{}, &mockLogger{})
+	handler.paths.SetWorkDir(tmpDir)
+	writeResearch(t, handler.GetResearchDir())
+	var out bytes.Buffer
+	handler.SetOutput(&out)
+
+	result, err := handler.Search(context.Background(), []string{"缓存", "淘汰"})
+	if err != nil {
+		t.Fatalf("Search() error: %v", err)
+	}
+	if result.Sections != 3 || len(result.Hits) != 2 || result.Hits[0].Heading != "缓存 > 淘汰策略" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	for _, want := range []string{"[1] cache.md:3 § 缓存 > 淘汰策略", "    使用 LRU 淘汰最久未使用的条目。", "2 个结果 (共 3 个段落)"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if _, err := handler.Search(context.Background(), []string{"登录", "-k", "1", "--json"}); err != nil {
+		t.Fatalf("Search(--json) error: %v", err)
+	}
+	var decoded SearchResult
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil || len(decoded.Hits) != 1 || decoded.Hits[0].File != "auth.md" {
+		t.Errorf("Unexpected JSON output %v:\n%s", err, out.String())
+	}
+
+	for _, args := range [][]string{nil, {"-k", "0", "缓存"}, {"--limit", "缓存"}} {
+		if _, err := handler.Search(context.Background(), args); err == nil {
+			t.Errorf("Expected an error for %q", args)
+		}
+	}
+}
+
+func TestPlanHandler_researchContext(t *testing.T) {
+	tmpDir := setupTestDir(t)
+	cfg := &mockConfig{values: map[string]interface{}{}}
+	cfg.SetWorkDir(tmpDir)
+	handler := NewPlanHandler(cfg, &mockLogger{}, nil)
+	handler.paths.SetWorkDir(tmpDir)
+	writeResearch(t, handler.paths.GetResearchDir())
+
+	// Small research is included in full
+	content := handler.researchContext(context.Background(), "缓存")
+	if !strings.Contains(content, "## File: auth.md") || !strings.Contains(content, "## File: cache.md") {
+		t.Errorf("Expected every research file:\n%s", content)
+	}
+
+	cfg.values["research.max_chars"] = 10
+	cfg.values["research.top_k"] = 1
+	content = handler.researchContext(context.Background(), "缓存 过期")
+	if !strings.Contains(content, "## [1] cache.md:7 § 缓存 > 过期") || strings.Contains(content, "登录") {
+		t.Errorf("Expected only the most relevant section:\n%s", content)
+	}
+	if got := handler.researchContext(context.Background(), "kubernetes"); got != "No relevant research found." {
+		t.Errorf("Unexpected context without hits: %q", got)
+	}
+}
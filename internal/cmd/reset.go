@@ -370,7 +370,7 @@ func (h *ResetHandler) parseCommitMessageForModuleJob(message string) (module, j
 	// This is a simple parser that looks for patterns in the message
 	parts := strings.Split(message, " - ")
 	for _, part := range parts {
-		part = strings.TrimSpace(part)
+		part = strings.TrimSpace(strings.TrimPrefix(part, "morty:"))
 		// Check for module/job pattern (contains /)
 		if strings.Contains(part, "/") && !strings.Contains(part, " ") {
 			subParts := strings.SplitN(part, "/", 2)
@@ -893,6 +893,13 @@ func (h *ResetHandler) restoreStateForCommit(info *CommitInfo) error {
 		return fmt.Errorf("加载状态文件失败: %w", err)
 	}
 
+	// Keep a copy of the state so the sync can be undone by hand
+	if backupPath, err := stateManager.Backup(); err != nil {
+		h.logger.Warn("备份状态文件失败", logging.String("error", err.Error()))
+	} else {
+		h.logger.Info("已备份状态文件", logging.String("path", backupPath))
+	}
+
 	// Update state: set the target job as the current running job
 	// and reset subsequent jobs to PENDING
 	if err := h.syncStateAfterReset(stateManager, info); err != nil {
@@ -935,6 +942,7 @@ func (h *ResetHandler) syncStateAfterReset(sm *state.Manager, targetCommit *Comm
 				job.LoopCount = 0
 				job.RetryCount = 0
 				job.TasksCompleted = 0
+				job.Sessions = nil
 				job.UpdatedAt = time.Now()
 				resetCount++
 			} else if shouldResetJob(module.Name, job.Name, targetModule, targetJob) {
@@ -943,6 +951,7 @@ func (h *ResetHandler) syncStateAfterReset(sm *state.Manager, targetCommit *Comm
 				job.LoopCount = 0
 				job.RetryCount = 0
 				job.TasksCompleted = 0
+				job.Sessions = nil
 				job.UpdatedAt = time.Now()
 				resetCount++
 			} else {
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/morty/morty/internal/config"
+	"github.com/morty/morty/internal/git"
 	"github.com/morty/morty/internal/logging"
 	"github.com/morty/morty/internal/state"
 )
@@ -264,7 +265,7 @@ func TestResetHandler_Execute_NotGitRepo(t *testing.T) {
 	}
 }
 
-func TestResetHandler_Execute_LocalReset(t *testing.T) {
+func TestResetHandler_performLocalReset(t *testing.T) {
 	// Create a temporary directory that simulates a git repo with morty state
 	tmpDir := t.TempDir()
 	gitDir := filepath.Join(tmpDir, ".git")
@@ -285,31 +286,12 @@ func TestResetHandler_Execute_LocalReset(t *testing.T) {
 		t.Fatalf("Failed to create status file: %v", err)
 	}
 
-	cfg := &mockResetConfig{workDir: tmpDir}
-	logger := &mockResetLogger{}
-	handler := NewResetHandler(cfg, logger)
-
-	// Use mock git checker that reports it's a git repo
-	mockChecker := &mockGitChecker{isGitRepo: true, repoRoot: tmpDir}
-	handler.SetGitChecker(mockChecker)
-
-	// Execute with -l option
-	result, err := handler.Execute(context.Background(), []string{"-l"})
-
-	if err != nil {
-		t.Errorf("Execute() unexpected error: %v", err)
-	}
-
-	if result == nil {
-		t.Fatal("Execute() returned nil result")
-	}
-
-	if result.ExitCode != 0 {
-		t.Errorf("Execute() ExitCode = %d, want 0", result.ExitCode)
-	}
+	handler := NewResetHandler(&mockResetConfig{workDir: tmpDir}, &mockResetLogger{})
+	chdirTestDir(t, tmpDir)
 
-	if result.ResetLevel != "local" {
-		t.Errorf("Execute() ResetLevel = %s, want local", result.ResetLevel)
+	// -l on its own lists the loop history, so call the reset directly
+	if err := handler.performLocalReset(); err != nil {
+		t.Fatalf("performLocalReset() error: %v", err)
 	}
 
 	// Verify that status.json and doing directory were removed
@@ -346,6 +328,7 @@ func TestResetHandler_Execute_CleanReset(t *testing.T) {
 	cfg := &mockResetConfig{workDir: tmpDir}
 	logger := &mockResetLogger{}
 	handler := NewResetHandler(cfg, logger)
+	chdirTestDir(t, tmpDir)
 
 	// Use mock git checker that reports it's a git repo
 	mockChecker := &mockGitChecker{isGitRepo: true, repoRoot: tmpDir}
@@ -432,6 +415,10 @@ func TestShowLoopHistory_NotGitRepo(t *testing.T) {
 // TestShowLoopHistory_DefaultCount tests showLoopHistory with default count.
 func TestShowLoopHistory_DefaultCount(t *testing.T) {
 	tmpDir := t.TempDir()
+	if err := git.NewManager().InitIfNeeded(tmpDir); err != nil {
+		t.Fatalf("Failed to init git repo: %v", err)
+	}
+	chdirTestDir(t, tmpDir)
 
 	cfg := &mockResetConfig{workDir: tmpDir}
 	logger := &mockResetLogger{}
@@ -461,6 +448,10 @@ func TestShowLoopHistory_DefaultCount(t *testing.T) {
 // TestShowLoopHistory_NoLoopCommits tests showLoopHistory when there are no loop commits.
 func TestShowLoopHistory_NoLoopCommits(t *testing.T) {
 	tmpDir := t.TempDir()
+	if err := git.NewManager().InitIfNeeded(tmpDir); err != nil {
+		t.Fatalf("Failed to init git repo: %v", err)
+	}
+	chdirTestDir(t, tmpDir)
 
 	cfg := &mockResetConfig{workDir: tmpDir}
 	logger := &mockResetLogger{}
@@ -531,6 +522,18 @@ func TestParseCommitMessageForModuleJob(t *testing.T) {
 			expectedModule: "call_cli",
 			expectedJob:    "job_3",
 		},
+		{
+			name:           "prefix without space",
+			message:        "morty:cli/job_4 - COMPLETED",
+			expectedModule: "cli",
+			expectedJob:    "job_4",
+		},
+		{
+			name:           "loop with module/job",
+			message:        "morty: loop 3 - state/job_5 - FAILED",
+			expectedModule: "state",
+			expectedJob:    "job_5",
+		},
 	}
 
 	for _, tc := range tests {
@@ -1168,7 +1171,7 @@ func TestValidateCommitHash(t *testing.T) {
 			name:    "short hash",
 			hash:    "abc123",
 			wantErr: true,
-			errMsg:  "至少 7 个字符",
+			errMsg:  "至少需要 7 个字符",
 		},
 		{
 			name:    "invalid characters",
@@ -1362,38 +1365,38 @@ func TestRecalculateModuleStatus(t *testing.T) {
 
 	tests := []struct {
 		name           string
-		jobs           map[string]*state.JobState
+		jobs           []state.JobState
 		expectedStatus state.Status
 	}{
 		{
 			name: "all completed",
-			jobs: map[string]*state.JobState{
-				"job_1": {Status: state.StatusCompleted, UpdatedAt: now},
-				"job_2": {Status: state.StatusCompleted, UpdatedAt: now},
+			jobs: []state.JobState{
+				{Name: "job_1", Status: state.StatusCompleted, UpdatedAt: now},
+				{Name: "job_2", Status: state.StatusCompleted, UpdatedAt: now},
 			},
 			expectedStatus: state.StatusCompleted,
 		},
 		{
 			name: "one running",
-			jobs: map[string]*state.JobState{
-				"job_1": {Status: state.StatusCompleted, UpdatedAt: now},
-				"job_2": {Status: state.StatusRunning, UpdatedAt: now},
+			jobs: []state.JobState{
+				{Name: "job_1", Status: state.StatusCompleted, UpdatedAt: now},
+				{Name: "job_2", Status: state.StatusRunning, UpdatedAt: now},
 			},
 			expectedStatus: state.StatusRunning,
 		},
 		{
 			name: "one failed",
-			jobs: map[string]*state.JobState{
-				"job_1": {Status: state.StatusCompleted, UpdatedAt: now},
-				"job_2": {Status: state.StatusFailed, UpdatedAt: now},
+			jobs: []state.JobState{
+				{Name: "job_1", Status: state.StatusCompleted, UpdatedAt: now},
+				{Name: "job_2", Status: state.StatusFailed, UpdatedAt: now},
 			},
 			expectedStatus: state.StatusFailed,
 		},
 		{
 			name: "mixed pending",
-			jobs: map[string]*state.JobState{
-				"job_1": {Status: state.StatusPending, UpdatedAt: now},
-				"job_2": {Status: state.StatusPending, UpdatedAt: now},
+			jobs: []state.JobState{
+				{Name: "job_1", Status: state.StatusPending, UpdatedAt: now},
+				{Name: "job_2", Status: state.StatusPending, UpdatedAt: now},
 			},
 			expectedStatus: state.StatusPending,
 		},
@@ -1425,55 +1428,72 @@ func TestSyncStateAfterReset(t *testing.T) {
 	tempDir := t.TempDir()
 	statusFile := filepath.Join(tempDir, "status.json")
 
+	completedJob := func(index, globalIndex int) state.JobState {
+		return state.JobState{
+			Index:          index,
+			GlobalIndex:    globalIndex,
+			Name:           fmt.Sprintf("job_%d", index+1),
+			Status:         state.StatusCompleted,
+			LoopCount:      1,
+			TasksTotal:     5,
+			TasksCompleted: 5,
+			UpdatedAt:      now,
+		}
+	}
+	pendingJob := func(index, globalIndex int) state.JobState {
+		return state.JobState{
+			Index:       index,
+			GlobalIndex: globalIndex,
+			Name:        fmt.Sprintf("job_%d", index+1),
+			Status:      state.StatusPending,
+			TasksTotal:  5,
+			UpdatedAt:   now,
+		}
+	}
+
 	// Create initial state with multiple modules and jobs
-	initialState := &state.StatusJSON{
-		Version: "1.0",
+	initialState := &state.ExecutionStatus{
+		Version: "2.0",
 		Global: state.GlobalState{
-			Status:        state.StatusRunning,
-			CurrentModule: "config",
-			CurrentJob:    "job_3",
-			StartTime:     now,
-			LastUpdate:    now,
+			Status:             state.StatusRunning,
+			StartTime:          now,
+			LastUpdate:         now,
+			CurrentModuleIndex: 1,
+			CurrentJobIndex:    2,
+			TotalModules:       3,
+			TotalJobs:          7,
 		},
-		Modules: map[string]*state.ModuleState{
-			"cli": {
+		Modules: []state.ModuleState{
+			{
+				Index:     0,
 				Name:      "cli",
 				Status:    state.StatusCompleted,
 				CreatedAt: now,
 				UpdatedAt: now,
-				Jobs: map[string]*state.JobState{
-					"job_1": {Name: "job_1", Status: state.StatusCompleted, LoopCount: 1, TasksTotal: 5, TasksCompleted: 5, UpdatedAt: now},
-					"job_2": {Name: "job_2", Status: state.StatusCompleted, LoopCount: 1, TasksTotal: 5, TasksCompleted: 5, UpdatedAt: now},
-				},
+				Jobs:      []state.JobState{completedJob(0, 0), completedJob(1, 1)},
 			},
-			"config": {
+			{
+				Index:     1,
 				Name:      "config",
 				Status:    state.StatusCompleted,
 				CreatedAt: now,
 				UpdatedAt: now,
-				Jobs: map[string]*state.JobState{
-					"job_1": {Name: "job_1", Status: state.StatusCompleted, LoopCount: 1, TasksTotal: 5, TasksCompleted: 5, UpdatedAt: now},
-					"job_2": {Name: "job_2", Status: state.StatusCompleted, LoopCount: 1, TasksTotal: 5, TasksCompleted: 5, UpdatedAt: now},
-					"job_3": {Name: "job_3", Status: state.StatusCompleted, LoopCount: 2, TasksTotal: 5, TasksCompleted: 5, UpdatedAt: now},
-				},
+				Jobs:      []state.JobState{completedJob(0, 2), completedJob(1, 3), completedJob(2, 4)},
 			},
-			"logging": {
+			{
+				Index:     2,
 				Name:      "logging",
 				Status:    state.StatusPending,
 				CreatedAt: now,
 				UpdatedAt: now,
-				Jobs: map[string]*state.JobState{
-					"job_1": {Name: "job_1", Status: state.StatusPending, LoopCount: 0, TasksTotal: 5, TasksCompleted: 0, UpdatedAt: now},
-					"job_2": {Name: "job_2", Status: state.StatusPending, LoopCount: 0, TasksTotal: 5, TasksCompleted: 0, UpdatedAt: now},
-				},
+				Jobs:      []state.JobState{pendingJob(0, 5), pendingJob(1, 6)},
 			},
 		},
 	}
 
 	// Save initial state to file
 	stateManager := state.NewManager(statusFile)
-	stateManager.SetState(initialState)
-	if err := stateManager.Save(); err != nil {
+	if err := stateManager.Save(initialState); err != nil {
 		t.Fatalf("Failed to save initial state: %v", err)
 	}
 
@@ -1486,13 +1506,13 @@ func TestSyncStateAfterReset(t *testing.T) {
 	// - config/job_3 should be reset to PENDING
 	// - logging/* should remain PENDING
 	commitInfo := &CommitInfo{
-		Hash:       "abc1234",
-		ShortHash:  "abc1234",
-		Message:    "morty: loop 5 - COMPLETED",
-		Module:     "config",
-		Job:        "job_2",
-		Status:     "COMPLETED",
-		Timestamp:  now,
+		Hash:      "abc1234",
+		ShortHash: "abc1234",
+		Message:   "morty: loop 5 - COMPLETED",
+		Module:    "config",
+		Job:       "job_2",
+		Status:    "COMPLETED",
+		Timestamp: now,
 	}
 
 	err := handler.syncStateAfterReset(stateManager, commitInfo)
@@ -1507,42 +1527,54 @@ func TestSyncStateAfterReset(t *testing.T) {
 	}
 
 	newState := newManager.GetState()
+	job := func(module, name string) *state.JobState {
+		t.Helper()
+		m := newState.GetModuleByName(module)
+		if m == nil {
+			t.Fatalf("Module %s not found", module)
+		}
+		j := m.GetJobByName(name)
+		if j == nil {
+			t.Fatalf("Job %s/%s not found", module, name)
+		}
+		return j
+	}
 
 	// Verify 1: Target job (config/job_2) should be PENDING
-	if newState.Modules["config"].Jobs["job_2"].Status != state.StatusPending {
-		t.Errorf("Target job config/job_2 status = %v, want PENDING", newState.Modules["config"].Jobs["job_2"].Status)
+	if got := job("config", "job_2").Status; got != state.StatusPending {
+		t.Errorf("Target job config/job_2 status = %v, want PENDING", got)
 	}
 
 	// Verify 2: Subsequent jobs (config/job_3) should be PENDING
-	if newState.Modules["config"].Jobs["job_3"].Status != state.StatusPending {
-		t.Errorf("Subsequent job config/job_3 status = %v, want PENDING", newState.Modules["config"].Jobs["job_3"].Status)
+	if got := job("config", "job_3").Status; got != state.StatusPending {
+		t.Errorf("Subsequent job config/job_3 status = %v, want PENDING", got)
 	}
 
 	// Verify 3: Previous jobs should remain COMPLETED
-	if newState.Modules["cli"].Jobs["job_1"].Status != state.StatusCompleted {
-		t.Errorf("Previous job cli/job_1 status = %v, want COMPLETED", newState.Modules["cli"].Jobs["job_1"].Status)
-	}
-	if newState.Modules["cli"].Jobs["job_2"].Status != state.StatusCompleted {
-		t.Errorf("Previous job cli/job_2 status = %v, want COMPLETED", newState.Modules["cli"].Jobs["job_2"].Status)
-	}
-	if newState.Modules["config"].Jobs["job_1"].Status != state.StatusCompleted {
-		t.Errorf("Previous job config/job_1 status = %v, want COMPLETED", newState.Modules["config"].Jobs["job_1"].Status)
+	for _, name := range [][2]string{{"cli", "job_1"}, {"cli", "job_2"}, {"config", "job_1"}} {
+		if got := job(name[0], name[1]).Status; got != state.StatusCompleted {
+			t.Errorf("Previous job %s/%s status = %v, want COMPLETED", name[0], name[1], got)
+		}
 	}
 
 	// Verify 4: LoopCount and TasksCompleted should be reset for target and subsequent jobs
-	if newState.Modules["config"].Jobs["job_2"].LoopCount != 0 {
-		t.Errorf("Target job LoopCount = %v, want 0", newState.Modules["config"].Jobs["job_2"].LoopCount)
+	if got := job("config", "job_2").LoopCount; got != 0 {
+		t.Errorf("Target job LoopCount = %v, want 0", got)
 	}
-	if newState.Modules["config"].Jobs["job_2"].TasksCompleted != 0 {
-		t.Errorf("Target job TasksCompleted = %v, want 0", newState.Modules["config"].Jobs["job_2"].TasksCompleted)
+	if got := job("config", "job_2").TasksCompleted; got != 0 {
+		t.Errorf("Target job TasksCompleted = %v, want 0", got)
 	}
 
-	// Verify 5: Global state should be updated
-	if newState.Global.CurrentModule != "config" {
-		t.Errorf("Global.CurrentModule = %v, want config", newState.Global.CurrentModule)
+	// Verify 5: Module and global state should be updated
+	if got := newState.GetModuleByName("config").Status; got == state.StatusCompleted {
+		t.Errorf("Module config status = %v, want it recalculated", got)
 	}
-	if newState.Global.CurrentJob != "job_2" {
-		t.Errorf("Global.CurrentJob = %v, want job_2", newState.Global.CurrentJob)
+	if newState.Global.Status != state.StatusRunning {
+		t.Errorf("Global.Status = %v, want RUNNING", newState.Global.Status)
+	}
+	if newState.Global.CurrentModuleIndex != 0 || newState.Global.CurrentJobIndex != 0 {
+		t.Errorf("Global current position = %d/%d, want 0/0",
+			newState.Global.CurrentModuleIndex, newState.Global.CurrentJobIndex)
 	}
 }
 
@@ -1552,21 +1584,23 @@ func TestSyncStateAfterReset_InvalidModuleJob(t *testing.T) {
 	statusFile := filepath.Join(tempDir, "status.json")
 
 	// Create initial state
-	initialState := &state.StatusJSON{
-		Version: "1.0",
+	initialState := &state.ExecutionStatus{
+		Version: "2.0",
 		Global:  state.GlobalState{Status: state.StatusRunning},
-		Modules: map[string]*state.ModuleState{
-			"cli": {
+		Modules: []state.ModuleState{
+			{
 				Name: "cli",
-				Jobs: map[string]*state.JobState{
-					"job_1": {Name: "job_1", Status: state.StatusCompleted},
+				Jobs: []state.JobState{
+					{Name: "job_1", Status: state.StatusCompleted},
 				},
 			},
 		},
 	}
 
 	stateManager := state.NewManager(statusFile)
-	stateManager.SetState(initialState)
+	if err := stateManager.Save(initialState); err != nil {
+		t.Fatalf("Failed to save initial state: %v", err)
+	}
 
 	handler := NewResetHandler(nil, &mockResetLogger{})
 
@@ -1581,3 +1615,51 @@ func TestSyncStateAfterReset_InvalidModuleJob(t *testing.T) {
 		t.Error("Expected error when module/job is '-', got nil")
 	}
 }
+
+// TestRestoreStateForCommit_BacksUpStatus tests that the state is backed up
+// before it is synchronized to the commit.
+func TestRestoreStateForCommit_BacksUpStatus(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	handler := NewResetHandler(nil, &mockResetLogger{})
+	handler.paths.SetWorkDir(tmpDir)
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	statusFile := handler.paths.GetStatusFile()
+	stateManager := state.NewManager(statusFile)
+	if err := stateManager.Save(&state.ExecutionStatus{
+		Version: "2.0",
+		Global:  state.GlobalState{Status: state.StatusCompleted},
+		Modules: []state.ModuleState{{
+			Name: "cli",
+			Jobs: []state.JobState{{Name: "job_1", Status: state.StatusCompleted}},
+		}},
+	}); err != nil {
+		t.Fatalf("Failed to save initial state: %v", err)
+	}
+	original, err := os.ReadFile(statusFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := handler.restoreStateForCommit(&CommitInfo{Module: "cli", Job: "job_1"}); err != nil {
+		t.Fatalf("restoreStateForCommit() error = %v", err)
+	}
+
+	backups, err := stateManager.ListBackups()
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("ListBackups() = %v, %v, want one backup", backups, err)
+	}
+	backup, err := os.ReadFile(backups[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(backup) != string(original) {
+		t.Error("Backup does not hold the state from before the reset")
+	}
+	if job := state.NewManager(statusFile).GetJob("cli", "job_1"); job == nil || job.Status != state.StatusPending {
+		t.Errorf("Reset job = %+v, want PENDING", job)
+	}
+}
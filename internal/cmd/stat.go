@@ -5,12 +5,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/morty/morty/internal/config"
 	"github.com/morty/morty/internal/logging"
 	"github.com/morty/morty/internal/state"
 )
 
+// defaultRefreshInterval is how often watch mode refreshes the status.
+const defaultRefreshInterval = 60 * time.Second
+
 // StatHandler handles status display commands.
 type StatHandler struct {
 	configManager config.Manager
@@ -20,7 +24,30 @@ type StatHandler struct {
 
 // StatResult represents the result of stat command execution.
 type StatResult struct {
+	// Status is the loaded status.json
 	Status *state.ExecutionStatus
+	// CurrentJob is the running job, nil when no job is running
+	CurrentJob *state.CurrentJob
+	// Summary counts the jobs by status
+	Summary *state.Summary
+	// ExitCode is 0 on success and 1 when the status could not be loaded
+	ExitCode int
+	// Err is why the status could not be loaded
+	Err error
+	// Duration is how long collecting the status took
+	Duration time.Duration
+	// JSONOutput is true when the status was printed as JSON
+	JSONOutput bool
+}
+
+// statJSON is the document printed by stat --json.
+type statJSON struct {
+	Status     string                 `json:"status"`
+	ExitCode   int                    `json:"exit_code"`
+	Error      string                 `json:"error,omitempty"`
+	CurrentJob *state.CurrentJob      `json:"current_job,omitempty"`
+	Summary    *state.Summary         `json:"summary,omitempty"`
+	Execution  *state.ExecutionStatus `json:"execution,omitempty"`
 }
 
 // NewStatHandler creates a new StatHandler.
@@ -32,28 +59,158 @@ func NewStatHandler(configManager config.Manager, logger logging.Logger) *StatHa
 }
 
 // Execute executes the stat command.
+// Options: --json/-j prints the status as JSON, --watch/-w refreshes it
+// every defaultRefreshInterval until ctx is cancelled.
 func (h *StatHandler) Execute(ctx context.Context, args []string) (*StatResult, error) {
-	// Initialize state manager if not already done
-	if h.stateManager == nil {
-		statusFile := h.configManager.GetStatusFile()
-		h.stateManager = state.NewManager(statusFile)
+	watch, jsonOutput := h.parseOptions(args)
 
-		// Load status
-		if err := h.stateManager.Load(); err != nil {
-			return nil, fmt.Errorf("failed to load status: %w", err)
+	result := h.collectStatus()
+	result.JSONOutput = jsonOutput
+	if result.Err != nil {
+		h.output(result)
+		return result, result.Err
+	}
+
+	if watch {
+		return h.runWatchMode(ctx, result)
+	}
+
+	h.output(result)
+	return result, nil
+}
+
+// parseOptions returns the watch and JSON options. Other arguments are ignored.
+func (h *StatHandler) parseOptions(args []string) (watch, jsonOutput bool) {
+	for _, arg := range args {
+		name, value, hasValue := strings.Cut(arg, "=")
+		enabled := !hasValue || value == "true" || value == "1"
+		switch name {
+		case "--watch", "-w":
+			watch = enabled
+		case "--json", "-j":
+			jsonOutput = enabled
 		}
 	}
+	return watch, jsonOutput
+}
+
+// collectStatus loads status.json and summarizes it.
+func (h *StatHandler) collectStatus() *StatResult {
+	start := time.Now()
+	result := &StatResult{}
+	defer func() { result.Duration = time.Since(start) }()
 
-	// Get current status
-	status := h.stateManager.GetStatus()
-	if status == nil {
-		return nil, fmt.Errorf("no status available")
+	if h.stateManager == nil {
+		h.stateManager = state.NewManager(h.configManager.GetStatusFile())
 	}
+	// Read status.json on every call so watch mode sees new progress
+	if err := h.stateManager.Load(); err != nil {
+		result.ExitCode = 1
+		result.Err = fmt.Errorf("failed to load status: %w", err)
+		return result
+	}
+
+	result.Status = h.stateManager.GetStatus()
+	if result.Status == nil {
+		result.ExitCode = 1
+		result.Err = fmt.Errorf("no status available")
+		return result
+	}
+	result.CurrentJob, _ = h.stateManager.GetCurrent()
+	result.Summary, _ = h.stateManager.GetSummary()
 
-	// Display status
-	h.DisplayStatus(status)
+	return result
+}
+
+// output prints a result as JSON or text.
+func (h *StatHandler) output(result *StatResult) {
+	if result.JSONOutput {
+		h.outputJSON(result)
+		return
+	}
+	h.outputText(result)
+}
 
-	return &StatResult{Status: status}, nil
+// outputText prints a result in human-readable format.
+func (h *StatHandler) outputText(result *StatResult) {
+	if result.Err != nil {
+		fmt.Printf("错误: %v\n", result.Err)
+		fmt.Printf("运行 morty init-status 或 morty doing 生成 status.json\n")
+		return
+	}
+	h.DisplayStatus(result.Status)
+}
+
+// outputJSON prints a result as a JSON document.
+func (h *StatHandler) outputJSON(result *StatResult) {
+	doc := statJSON{
+		Status:     h.getStatusString(result),
+		ExitCode:   result.ExitCode,
+		CurrentJob: result.CurrentJob,
+		Summary:    result.Summary,
+		Execution:  result.Status,
+	}
+	if result.Err != nil {
+		doc.Error = result.Err.Error()
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		h.logger.Error("Failed to marshal status", logging.String("error", err.Error()))
+		return
+	}
+	fmt.Println(string(data))
+}
+
+// getStatusString returns a one-word status: "error", the running job's
+// status, the overall status, or "idle".
+func (h *StatHandler) getStatusString(result *StatResult) string {
+	switch {
+	case result.Err != nil:
+		return "error"
+	case result.CurrentJob != nil:
+		return string(result.CurrentJob.Status)
+	case result.Status != nil && result.Status.Global.Status != "":
+		return string(result.Status.Global.Status)
+	default:
+		return "idle"
+	}
+}
+
+// runWatchMode prints the status every defaultRefreshInterval until ctx
+// is cancelled, and returns the last result.
+func (h *StatHandler) runWatchMode(ctx context.Context, result *StatResult) (*StatResult, error) {
+	ticker := time.NewTicker(defaultRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		if !result.JSONOutput {
+			h.clearScreen()
+			h.displayWatchHeader(time.Now())
+		}
+		h.output(result)
+
+		select {
+		case <-ctx.Done():
+			return result, nil
+		case <-ticker.C:
+		}
+
+		next := h.collectStatus()
+		next.JSONOutput = result.JSONOutput
+		result = next
+	}
+}
+
+// clearScreen clears the terminal and its scrollback.
+func (h *StatHandler) clearScreen() {
+	fmt.Print("\033[H\033[2J\033[3J")
+}
+
+// displayWatchHeader prints the watch mode banner.
+func (h *StatHandler) displayWatchHeader(refreshedAt time.Time) {
+	fmt.Printf("Watch mode enabled - Refresh every %ds - Press Ctrl+C to exit\n", int(defaultRefreshInterval.Seconds()))
+	fmt.Printf("Last refresh: %s\n", refreshedAt.Format("2006-01-02 15:04:05"))
 }
 
 // DisplayStatus displays status in human-readable format.
@@ -81,6 +238,9 @@ func (h *StatHandler) DisplayStatus(status *state.ExecutionStatus) {
 	fmt.Printf("Progress: %d/%d jobs completed (%.1f%%)\n", completedJobs, totalJobs, progressPercent)
 	fmt.Printf("Modules: %d/%d completed\n", completedModules, totalModules)
 	fmt.Printf("Last Update: %s\n", status.Global.LastUpdate.Format("2006-01-02 15:04:05"))
+	if status.Global.Profile != "" {
+		fmt.Printf("Profile: %s\n", status.Global.Profile)
+	}
 	fmt.Printf("\n")
 
 	// Module progress
@@ -164,6 +324,9 @@ func (h *StatHandler) DisplayStatus(status *state.ExecutionStatus) {
 					fmt.Printf("  Progress: %d/%d tasks completed\n",
 						job.TasksCompleted, job.TasksTotal)
 					fmt.Printf("  Loop: %d, Retry: %d\n", job.LoopCount, job.RetryCount)
+					if session := job.LastSession(); session != nil && !session.StartedAt.IsZero() {
+						fmt.Printf("  Elapsed: %s\n", h.formatDuration(time.Since(session.StartedAt)))
+					}
 					break
 				}
 			}
@@ -186,6 +349,16 @@ func (h *StatHandler) FormatStatusAsJSON(status *state.ExecutionStatus) (string,
 	return string(data), nil
 }
 
+// formatDuration formats a duration as MM:SS, or HH:MM:SS from one hour on.
+func (h *StatHandler) formatDuration(d time.Duration) string {
+	total := int(d.Round(time.Second).Seconds())
+	hours, minutes, seconds := total/3600, total%3600/60, total%60
+	if hours > 0 {
+		return fmt.Sprintf("%02d:%02d:%02d", hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%02d:%02d", minutes, seconds)
+}
+
 // getJobIcon returns an icon for a job status.
 func getJobIcon(status state.Status) string {
 	switch status {
@@ -197,6 +370,8 @@ func getJobIcon(status state.Status) string {
 		return "❌"
 	case state.StatusBlocked:
 		return "🚫"
+	case state.StatusInterrupted:
+		return "⏸️"
 	default:
 		return "⏳"
 	}
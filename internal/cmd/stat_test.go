@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -14,6 +13,53 @@ import (
 	"github.com/morty/morty/internal/state"
 )
 
+// captureStatOutput returns what fn prints to stdout.
+func captureStatOutput(t *testing.T, fn func()) string {
+	t.Helper()
+	oldStdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	fn()
+	w.Close()
+	os.Stdout = oldStdout
+
+	output, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	return string(output)
+}
+
+// createStatTestStatus returns a status with one completed and one running module.
+func createStatTestStatus() *state.ExecutionStatus {
+	return &state.ExecutionStatus{
+		Version: "2.0",
+		Global: state.GlobalState{
+			Status:             state.StatusRunning,
+			LastUpdate:         time.Date(2026, 1, 2, 3, 4, 5, 0, time.Local),
+			CurrentModuleIndex: 1,
+			CurrentJobIndex:    2,
+			TotalModules:       2,
+			TotalJobs:          4,
+			Profile:            "ci",
+		},
+		Modules: []state.ModuleState{
+			{Index: 0, Name: "config", DisplayName: "配置", Jobs: []state.JobState{
+				{Index: 0, GlobalIndex: 0, Name: "job_1", Status: state.StatusCompleted},
+				{Index: 1, GlobalIndex: 1, Name: "job_2", Status: state.StatusCompleted},
+			}},
+			{Index: 1, Name: "cli", DisplayName: "命令行", Dependencies: []string{"config"}, Jobs: []state.JobState{
+				{Index: 0, GlobalIndex: 2, Name: "job_1", Status: state.StatusRunning, TasksTotal: 4, TasksCompleted: 1, RetryCount: 1,
+					Sessions: []state.JobSession{{Attempt: 2, StartedAt: time.Now().Add(-90 * time.Second)}}},
+				{Index: 1, GlobalIndex: 3, Name: "job_2", Status: state.StatusPending},
+			}},
+		},
+	}
+}
+
 func TestNewStatHandler(t *testing.T) {
 	cfg := &mockConfig{}
 	logger := &mockLogger{}
@@ -22,1912 +68,372 @@ func TestNewStatHandler(t *testing.T) {
 	if handler == nil {
 		t.Fatal("Expected handler to be non-nil")
 	}
-
-	if handler.cfg == nil {
-		t.Error("Expected cfg to be set")
+	if handler.configManager != cfg {
+		t.Error("Expected configManager to be set")
 	}
-
 	if handler.logger != logger {
 		t.Error("Expected logger to be set")
 	}
-
-	if handler.paths == nil {
-		t.Error("Expected paths to be initialized")
-	}
 }
 
-func TestStatHandler_parseOptions(t *testing.T) {
-	tests := []struct {
-		name         string
-		args         []string
-		wantWatch    bool
-		wantJSON     bool
-	}{
-		{
-			name:      "no flags",
-			args:      []string{},
-			wantWatch: false,
-			wantJSON:  false,
-		},
-		{
-			name:      "watch flag long",
-			args:      []string{"--watch"},
-			wantWatch: true,
-			wantJSON:  false,
-		},
-		{
-			name:      "watch flag short",
-			args:      []string{"-w"},
-			wantWatch: true,
-			wantJSON:  false,
-		},
-		{
-			name:      "json flag long",
-			args:      []string{"--json"},
-			wantWatch: false,
-			wantJSON:  true,
-		},
-		{
-			name:      "json flag short",
-			args:      []string{"-j"},
-			wantWatch: false,
-			wantJSON:  true,
-		},
-		{
-			name:      "both flags",
-			args:      []string{"--watch", "--json"},
-			wantWatch: true,
-			wantJSON:  true,
-		},
-		{
-			name:      "watch equals true",
-			args:      []string{"--watch=true"},
-			wantWatch: true,
-			wantJSON:  false,
-		},
-		{
-			name:      "watch equals 1",
-			args:      []string{"--watch=1"},
-			wantWatch: true,
-			wantJSON:  false,
-		},
-		{
-			name:      "watch equals false",
-			args:      []string{"--watch=false"},
-			wantWatch: false,
-			wantJSON:  false,
-		},
-		{
-			name:      "json equals true",
-			args:      []string{"--json=true"},
-			wantWatch: false,
-			wantJSON:  true,
-		},
-		{
-			name:      "mixed args",
-			args:      []string{"some", "args", "--watch", "more"},
-			wantWatch: true,
-			wantJSON:  false,
-		},
+func TestStatHandler_Execute(t *testing.T) {
+	cfg := &mockConfig{}
+	cfg.SetWorkDir(setupTestDir(t))
+	if err := state.NewManager(cfg.GetStatusFile()).Save(createStatTestStatus()); err != nil {
+		t.Fatal(err)
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			handler := NewStatHandler(&mockConfig{}, &mockLogger{})
-			gotWatch, gotJSON := handler.parseOptions(tt.args)
-
-			if gotWatch != tt.wantWatch {
-				t.Errorf("parseOptions() watch = %v, want %v", gotWatch, tt.wantWatch)
-			}
-			if gotJSON != tt.wantJSON {
-				t.Errorf("parseOptions() json = %v, want %v", gotJSON, tt.wantJSON)
-			}
-		})
+	handler := NewStatHandler(cfg, &mockLogger{})
+	var result *StatResult
+	var err error
+	output := captureStatOutput(t, func() {
+		result, err = handler.Execute(context.Background(), nil)
+	})
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
 	}
-}
-
-func TestStatHandler_getStatusFilePath(t *testing.T) {
-	tests := []struct {
-		name        string
-		cfg         *mockConfig
-		expectedSub string
-	}{
-		{
-			name:        "with config",
-			cfg:         &mockConfig{workDir: "/custom/path"},
-			expectedSub: "/custom/path/status.json",
-		},
-		{
-			name:        "without config workdir",
-			cfg:         &mockConfig{},
-			expectedSub: ".morty/status.json",
-		},
+	if result.Status == nil || len(result.Status.Modules) != 2 {
+		t.Errorf("Unexpected result status: %+v", result.Status)
 	}
+	if !strings.Contains(output, "Morty Status") {
+		t.Errorf("Expected status output, got:\n%s", output)
+	}
+}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			handler := NewStatHandler(tt.cfg, &mockLogger{})
-			got := handler.getStatusFilePath()
+func TestStatHandler_Execute_NoStatus(t *testing.T) {
+	for _, args := range [][]string{nil, {"--json"}} {
+		cfg := &mockConfig{}
+		cfg.SetWorkDir(setupTestDir(t))
 
-			if !strings.Contains(got, tt.expectedSub) {
-				t.Errorf("getStatusFilePath() = %v, expected to contain %v", got, tt.expectedSub)
-			}
+		handler := NewStatHandler(cfg, &mockLogger{})
+		var result *StatResult
+		var err error
+		output := captureStatOutput(t, func() {
+			result, err = handler.Execute(context.Background(), args)
 		})
+		if err == nil {
+			t.Errorf("Execute(%v): expected an error without a status file", args)
+		}
+		if result == nil {
+			t.Fatalf("Execute(%v): expected a result", args)
+		}
+		if result.ExitCode != 1 || result.Err == nil {
+			t.Errorf("Execute(%v): result = %+v, want exit code 1 and an error", args, result)
+		}
+		if len(args) > 0 && !json.Valid([]byte(output)) {
+			t.Errorf("Execute(%v): output is not JSON:\n%s", args, output)
+		}
 	}
 }
 
-func TestStatHandler_Execute_NoStatusFile(t *testing.T) {
-	tmpDir := setupTestDir(t)
-
-	cfg := &mockConfig{workDir: tmpDir}
-	logger := &mockLogger{}
-	handler := NewStatHandler(cfg, logger)
-
-	ctx := context.Background()
-	result, err := handler.Execute(ctx, []string{})
-
-	// Should return error
-	if err == nil {
-		t.Error("Expected error when status file does not exist")
+func TestStatHandler_Execute_JSON(t *testing.T) {
+	cfg := &mockConfig{}
+	cfg.SetWorkDir(setupTestDir(t))
+	if err := state.NewManager(cfg.GetStatusFile()).Save(createStatTestStatus()); err != nil {
+		t.Fatal(err)
 	}
 
-	if result == nil {
-		t.Fatal("Expected result to be non-nil")
+	handler := NewStatHandler(cfg, &mockLogger{})
+	var result *StatResult
+	var err error
+	output := captureStatOutput(t, func() {
+		result, err = handler.Execute(context.Background(), []string{"--json"})
+	})
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
 	}
-
-	if result.ExitCode != 1 {
-		t.Errorf("Expected exit code 1, got %d", result.ExitCode)
+	if !result.JSONOutput || result.ExitCode != 0 {
+		t.Errorf("Unexpected result: %+v", result)
 	}
-
-	if result.Err == nil {
-		t.Error("Expected result.Err to be set")
+	if result.CurrentJob == nil || result.CurrentJob.Module != "cli" || result.CurrentJob.Job != "job_1" {
+		t.Errorf("Unexpected current job: %+v", result.CurrentJob)
 	}
-}
-
-func TestStatHandler_Execute_NoStatusFile_JSON(t *testing.T) {
-	tmpDir := setupTestDir(t)
-
-	cfg := &mockConfig{workDir: tmpDir}
-	logger := &mockLogger{}
-	handler := NewStatHandler(cfg, logger)
-
-	ctx := context.Background()
-	result, err := handler.Execute(ctx, []string{"--json"})
-
-	// Should return error
-	if err == nil {
-		t.Error("Expected error when status file does not exist")
+	if result.Summary == nil || result.Summary.TotalModules != 2 || result.Summary.TotalJobs != 4 {
+		t.Errorf("Unexpected summary: %+v", result.Summary)
 	}
 
-	if result == nil {
-		t.Fatal("Expected result to be non-nil")
+	var parsed statJSON
+	if err := json.Unmarshal([]byte(output), &parsed); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, output)
 	}
-
-	if result.ExitCode != 1 {
-		t.Errorf("Expected exit code 1, got %d", result.ExitCode)
+	if parsed.Status != "RUNNING" || parsed.Summary == nil || parsed.Summary.Completed != 2 {
+		t.Errorf("Unexpected JSON document: %+v", parsed)
+	}
+	if parsed.Execution == nil || len(parsed.Execution.Modules) != 2 {
+		t.Errorf("JSON document should carry the status: %+v", parsed.Execution)
 	}
 }
 
-func TestStatHandler_Execute_WithStatusFile(t *testing.T) {
-	tmpDir := setupTestDir(t)
-
-	// Create a mock status.json file
-	statusFile := filepath.Join(tmpDir, "status.json")
-	statusData := `{
-		"global": {
-			"status": "RUNNING",
-			"current_module": "test_module",
-			"current_job": "test_job",
-			"start_time": "2024-01-01T00:00:00Z",
-			"last_update": "2024-01-01T00:00:00Z",
-			"total_loops": 1
-		},
-		"modules": {
-			"test_module": {
-				"name": "test_module",
-				"status": "RUNNING",
-				"jobs": {
-					"test_job": {
-						"name": "test_job",
-						"status": "RUNNING",
-						"loop_count": 1,
-						"tasks_total": 5,
-						"tasks_completed": 3,
-						"created_at": "2024-01-01T00:00:00Z",
-						"updated_at": "2024-01-01T00:00:00Z"
-					}
-				},
-				"created_at": "2024-01-01T00:00:00Z",
-				"updated_at": "2024-01-01T00:00:00Z"
-			}
-		},
-		"version": "1.0"
-	}`
-
-	if err := os.WriteFile(statusFile, []byte(statusData), 0644); err != nil {
-		t.Fatalf("Failed to create status file: %v", err)
+func TestStatHandler_Execute_Watch(t *testing.T) {
+	cfg := &mockConfig{}
+	cfg.SetWorkDir(setupTestDir(t))
+	if err := state.NewManager(cfg.GetStatusFile()).Save(createStatTestStatus()); err != nil {
+		t.Fatal(err)
 	}
 
-	cfg := &mockConfig{workDir: tmpDir}
-	logger := &mockLogger{}
-	handler := NewStatHandler(cfg, logger)
-
-	ctx := context.Background()
-	result, err := handler.Execute(ctx, []string{})
+	// A cancelled context stops watch mode after the first refresh
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
 
-	// Should not return error
+	handler := NewStatHandler(cfg, &mockLogger{})
+	var result *StatResult
+	var err error
+	output := captureStatOutput(t, func() {
+		result, err = handler.Execute(ctx, []string{"-w"})
+	})
 	if err != nil {
-		t.Errorf("Execute failed: %v", err)
-	}
-
-	if result == nil {
-		t.Fatal("Expected result to be non-nil")
-	}
-
-	if result.ExitCode != 0 {
-		t.Errorf("Expected exit code 0, got %d", result.ExitCode)
+		t.Fatalf("Execute() error: %v", err)
 	}
-
-	if result.CurrentJob == nil {
-		t.Error("Expected CurrentJob to be set")
-	} else {
-		if result.CurrentJob.Module != "test_module" {
-			t.Errorf("Expected module 'test_module', got '%s'", result.CurrentJob.Module)
-		}
-		if result.CurrentJob.Job != "test_job" {
-			t.Errorf("Expected job 'test_job', got '%s'", result.CurrentJob.Job)
-		}
+	if result == nil || result.Status == nil {
+		t.Fatalf("Unexpected result: %+v", result)
 	}
-
-	if result.Summary == nil {
-		t.Error("Expected Summary to be set")
-	} else {
-		if result.Summary.TotalModules != 1 {
-			t.Errorf("Expected 1 module, got %d", result.Summary.TotalModules)
-		}
-		if result.Summary.TotalJobs != 1 {
-			t.Errorf("Expected 1 job, got %d", result.Summary.TotalJobs)
+	for _, want := range []string{"\033[2J", "Watch mode enabled", "Morty Status"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output missing %q:\n%s", want, output)
 		}
 	}
 }
 
-func TestStatHandler_Execute_WithStatusFile_JSON(t *testing.T) {
-	tmpDir := setupTestDir(t)
-
-	// Create a mock status.json file
-	statusFile := filepath.Join(tmpDir, "status.json")
-	statusData := `{
-		"global": {
-			"status": "COMPLETED",
-			"start_time": "2024-01-01T00:00:00Z",
-			"last_update": "2024-01-01T00:00:00Z",
-			"total_loops": 5
-		},
-		"modules": {
-			"module1": {
-				"name": "module1",
-				"status": "COMPLETED",
-				"jobs": {
-					"job1": {
-						"name": "job1",
-						"status": "COMPLETED",
-						"loop_count": 1,
-						"tasks_total": 3,
-						"tasks_completed": 3,
-						"created_at": "2024-01-01T00:00:00Z",
-						"updated_at": "2024-01-01T00:00:00Z"
-					}
-				},
-				"created_at": "2024-01-01T00:00:00Z",
-				"updated_at": "2024-01-01T00:00:00Z"
-			}
-		},
-		"version": "1.0"
-	}`
-
-	if err := os.WriteFile(statusFile, []byte(statusData), 0644); err != nil {
-		t.Fatalf("Failed to create status file: %v", err)
-	}
-
-	cfg := &mockConfig{workDir: tmpDir}
-	logger := &mockLogger{}
-	handler := NewStatHandler(cfg, logger)
+func TestStatHandler_runWatchMode_ContextCancellation(t *testing.T) {
+	cfg := &mockConfig{}
+	cfg.SetWorkDir(setupTestDir(t))
+	handler := NewStatHandler(cfg, &mockLogger{})
 
-	ctx := context.Background()
-	result, err := handler.Execute(ctx, []string{"--json"})
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
 
-	// Should not return error
+	initial := &StatResult{Status: createStatTestStatus(), JSONOutput: true}
+	var result *StatResult
+	var err error
+	output := captureStatOutput(t, func() {
+		result, err = handler.runWatchMode(ctx, initial)
+	})
 	if err != nil {
-		t.Errorf("Execute failed: %v", err)
+		t.Errorf("Expected no error on context cancellation, got: %v", err)
 	}
-
-	if result == nil {
-		t.Fatal("Expected result to be non-nil")
+	if result != initial {
+		t.Error("Expected the initial result back")
 	}
-
-	if !result.JSONOutput {
-		t.Error("Expected JSONOutput to be true")
+	if strings.Contains(output, "Watch mode enabled") {
+		t.Errorf("JSON watch mode should not print the banner:\n%s", output)
 	}
 }
 
-func TestStatHandler_Execute_ContextCancellation(t *testing.T) {
-	tmpDir := setupTestDir(t)
+func TestStatHandler_parseOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantWatch bool
+		wantJSON  bool
+	}{
+		{"no flags", []string{}, false, false},
+		{"watch flag long", []string{"--watch"}, true, false},
+		{"watch flag short", []string{"-w"}, true, false},
+		{"json flag long", []string{"--json"}, false, true},
+		{"json flag short", []string{"-j"}, false, true},
+		{"both flags", []string{"--watch", "--json"}, true, true},
+		{"watch equals true", []string{"--watch=true"}, true, false},
+		{"watch equals 1", []string{"--watch=1"}, true, false},
+		{"watch equals false", []string{"--watch=false"}, false, false},
+		{"json equals true", []string{"--json=true"}, false, true},
+		{"mixed args", []string{"some", "args", "--watch", "more"}, true, false},
+	}
 
-	// Create a mock status.json file
-	statusFile := filepath.Join(tmpDir, "status.json")
-	statusData := `{
-		"global": {
-			"status": "RUNNING",
-			"start_time": "2024-01-01T00:00:00Z",
-			"last_update": "2024-01-01T00:00:00Z",
-			"total_loops": 1
-		},
-		"modules": {},
-		"version": "1.0"
-	}`
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewStatHandler(&mockConfig{}, &mockLogger{})
+			gotWatch, gotJSON := handler.parseOptions(tt.args)
 
-	if err := os.WriteFile(statusFile, []byte(statusData), 0644); err != nil {
-		t.Fatalf("Failed to create status file: %v", err)
+			if gotWatch != tt.wantWatch {
+				t.Errorf("parseOptions() watch = %v, want %v", gotWatch, tt.wantWatch)
+			}
+			if gotJSON != tt.wantJSON {
+				t.Errorf("parseOptions() json = %v, want %v", gotJSON, tt.wantJSON)
+			}
+		})
 	}
+}
 
-	cfg := &mockConfig{workDir: tmpDir}
-	logger := &mockLogger{}
-	handler := NewStatHandler(cfg, logger)
-
-	// Create a cancelled context
-	ctx, cancel := context.WithCancel(context.Background())
-	cancel() // Cancel immediately
+func TestStatHandler_outputText(t *testing.T) {
+	handler := NewStatHandler(&mockConfig{}, &mockLogger{})
 
-	_, err := handler.Execute(ctx, []string{})
+	output := captureStatOutput(t, func() {
+		handler.outputText(&StatResult{Err: fmt.Errorf("test error"), ExitCode: 1})
+	})
+	if !strings.Contains(output, "test error") {
+		t.Errorf("Expected the error message, got:\n%s", output)
+	}
 
-	// The execution should complete before checking context cancellation in this simple case
-	_ = err
+	output = captureStatOutput(t, func() {
+		handler.outputText(&StatResult{Status: createStatTestStatus()})
+	})
+	if !strings.Contains(output, "Morty Status") {
+		t.Errorf("Expected the status, got:\n%s", output)
+	}
 }
 
-func TestStatHandler_outputJSON(t *testing.T) {
-	tmpDir := setupTestDir(t)
-
-	handler := NewStatHandler(&mockConfig{workDir: tmpDir}, &mockLogger{})
+func TestStatHandler_getStatusString(t *testing.T) {
+	handler := NewStatHandler(&mockConfig{}, &mockLogger{})
 
 	tests := []struct {
-		name   string
-		result *StatResult
+		name     string
+		result   *StatResult
+		expected string
 	}{
-		{
-			name: "error result",
-			result: &StatResult{
-				Err:        fmt.Errorf("test error"),
-				ExitCode:   1,
-				Duration:   time.Second,
-				JSONOutput: true,
-			},
-		},
-		{
-			name: "success with current job",
-			result: &StatResult{
-				ExitCode: 0,
-				Duration: time.Second,
-				CurrentJob: &state.CurrentJob{
-					Module: "test",
-					Job:    "job",
-					Status: state.StatusRunning,
-				},
-				Summary: &state.Summary{
-					TotalModules: 1,
-					TotalJobs:    1,
-					Pending:      0,
-					Running:      1,
-					Completed:    0,
-					Failed:       0,
-					Blocked:      0,
-					Modules: map[string]state.ModuleSummary{
-						"test": {
-							TotalJobs: 1,
-							Running:   1,
-						},
-					},
-				},
-			},
-		},
+		{"error result", &StatResult{Err: fmt.Errorf("test error")}, "error"},
+		{"idle result", &StatResult{}, "idle"},
+		{"running result", &StatResult{CurrentJob: &state.CurrentJob{Status: state.StatusRunning}}, "RUNNING"},
+		{"completed result", &StatResult{Status: &state.ExecutionStatus{Global: state.GlobalState{Status: state.StatusCompleted}}}, "COMPLETED"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Redirect stdout to capture output
-			oldStdout := os.Stdout
-			r, w, _ := os.Pipe()
-			os.Stdout = w
-
-			handler.outputJSON(tt.result)
-
-			w.Close()
-			os.Stdout = oldStdout
-
-			outputBytes, err := io.ReadAll(r)
-			if err != nil {
-				t.Fatalf("Failed to read output: %v", err)
-			}
-			output := string(outputBytes)
-			if output == "" {
-				t.Error("Expected JSON output, got empty string")
-			}
-
-			// Verify it's valid JSON
-			var parsed map[string]interface{}
-			if err := json.Unmarshal([]byte(output), &parsed); err != nil {
-				t.Errorf("Output is not valid JSON: %v\nOutput: %s", err, output)
+			if got := handler.getStatusString(tt.result); got != tt.expected {
+				t.Errorf("getStatusString() = %v, want %v", got, tt.expected)
 			}
 		})
 	}
 }
 
-func TestStatHandler_formatJSON(t *testing.T) {
-	tmpDir := setupTestDir(t)
-	handler := NewStatHandler(&mockConfig{workDir: tmpDir}, &mockLogger{})
+func TestStatHandler_formatDuration(t *testing.T) {
+	handler := NewStatHandler(&mockConfig{}, &mockLogger{})
 
 	tests := []struct {
-		name         string
-		result       *StatResult
-		wantFields   []string
-		wantValid    bool
+		name     string
+		duration time.Duration
+		expected string
 	}{
-		{
-			name: "complete status info",
-			result: &StatResult{
-				ExitCode: 0,
-				Duration: time.Second,
-				StatusInfo: &StatusInfo{
-					Current: CurrentJobInfo{
-						Module:      "test_module",
-						Job:         "job_1",
-						Description: "Test job description",
-						Status:      "RUNNING",
-						LoopCount:   2,
-						StartedAt:   time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
-						ElapsedTime: "00:05:30",
-					},
-					Previous: &PreviousJob{
-						Module:      "test_module",
-						Job:         "job_0",
-						Status:      "COMPLETED",
-						Duration:    "00:15:00",
-						CompletedAt: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
-					},
-					Progress: ProgressInfo{
-						TotalJobs:     10,
-						CompletedJobs: 5,
-						FailedJobs:    0,
-						PendingJobs:   3,
-						RunningJobs:   2,
-						Percentage:    50,
-					},
-					Modules: []ModuleStatus{
-						{Name: "module1", Status: "completed", TotalJobs: 3, CompletedJobs: 3},
-						{Name: "module2", Status: "in_progress", TotalJobs: 4, CompletedJobs: 2},
-					},
-					DebugIssues: []DebugIssue{
-						{
-							ID:          "debug1",
-							Description: "Test issue",
-							Loop:        2,
-							Hypothesis:  "Missing config",
-							Status:      "待修复",
-							Timestamp:   time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC),
-						},
-					},
-				},
-			},
-			wantFields: []string{
-				`"status":`,
-				`"current":`,
-				`"module":`,
-				`"job":`,
-				`"description":`,
-				`"loop_count": 2`,
-				`"elapsed_time": "00:05:30"`,
-				`"previous":`,
-				`"duration": "00:15:00"`,
-				`"progress":`,
-				`"total_jobs": 10`,
-				`"completed_jobs": 5`,
-				`"percentage": 50`,
-				`"modules":`,
-				`"debug_issues":`,
-				`"duration": "00:01"`,
-			},
-			wantValid: true,
-		},
-		{
-			name: "error result",
-			result: &StatResult{
-				Err:        fmt.Errorf("test error"),
-				ExitCode:   1,
-				Duration:   time.Second,
-				JSONOutput: true,
-			},
-			wantFields: []string{
-				`"status": "error"`,
-				`"error": "test error"`,
-			},
-			wantValid: true,
-		},
-		{
-			name: "minimal result",
-			result: &StatResult{
-				ExitCode: 0,
-				Duration: 0,
-			},
-			wantFields: []string{
-				`"status":`,
-				`"current":`,
-				`"progress":`,
-			},
-			wantValid: true,
-		},
-		{
-			name: "with current job no status info",
-			result: &StatResult{
-				ExitCode: 0,
-				Duration: time.Second,
-				CurrentJob: &state.CurrentJob{
-					Module:    "test",
-					Job:       "job",
-					Status:    state.StatusRunning,
-					StartedAt: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
-				},
-			},
-			wantFields: []string{
-				`"status":`,
-				`"current":`,
-				`"progress":`,
-			},
-			wantValid: true,
-		},
+		{"seconds only", 45 * time.Second, "00:45"},
+		{"minutes and seconds", 5*time.Minute + 30*time.Second, "05:30"},
+		{"hours minutes seconds", 2*time.Hour + 30*time.Minute + 15*time.Second, "02:30:15"},
+		{"zero", 0, "00:00"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			jsonStr, err := handler.formatJSON(tt.result)
-			if err != nil {
-				t.Fatalf("formatJSON() error = %v", err)
-			}
-
-			// Verify it's valid JSON
-			var parsed map[string]interface{}
-			if err := json.Unmarshal([]byte(jsonStr), &parsed); err != nil {
-				t.Errorf("Output is not valid JSON: %v\nOutput: %s", err, jsonStr)
-			}
-
-			// Check for expected fields
-			for _, field := range tt.wantFields {
-				if !strings.Contains(jsonStr, field) {
-					t.Errorf("Expected JSON to contain '%s', got:\n%s", field, jsonStr)
-				}
-			}
-
-			// Verify indentation (should have newlines and spaces)
-			if !strings.Contains(jsonStr, "\n") {
-				t.Error("Expected JSON to be indented with newlines")
-			}
-			if !strings.Contains(jsonStr, "  ") {
-				t.Error("Expected JSON to be indented with spaces")
+			if got := handler.formatDuration(tt.duration); got != tt.expected {
+				t.Errorf("formatDuration() = %v, want %v", got, tt.expected)
 			}
 		})
 	}
 }
 
-func TestStatHandler_formatJSON_TimeFormat(t *testing.T) {
-	tmpDir := setupTestDir(t)
-	handler := NewStatHandler(&mockConfig{workDir: tmpDir}, &mockLogger{})
-
-	result := &StatResult{
-		ExitCode: 0,
-		Duration: 2*time.Hour + 30*time.Minute + 15*time.Second,
-		StatusInfo: &StatusInfo{
-			Current: CurrentJobInfo{
-				Module:      "test",
-				Job:         "job",
-				Status:      "RUNNING",
-				StartedAt:   time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
-				ElapsedTime: "01:30:00",
-			},
-			Previous: &PreviousJob{
-				Module:      "test",
-				Job:         "prev_job",
-				Status:      "COMPLETED",
-				Duration:    "00:45:30",
-				CompletedAt: time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC),
-			},
-		},
-	}
-
-	jsonStr, err := handler.formatJSON(result)
-	if err != nil {
-		t.Fatalf("formatJSON() error = %v", err)
-	}
-
-	// Verify time formats
-	// Duration should be formatted as "02:30:15"
-	if !strings.Contains(jsonStr, `"duration": "02:30:15"`) {
-		t.Errorf("Expected duration format '02:30:15', got:\n%s", jsonStr)
-	}
+func TestStatHandler_WatchModeFeatures(t *testing.T) {
+	handler := NewStatHandler(&mockConfig{}, &mockLogger{})
 
-	// Elapsed time should be preserved
-	if !strings.Contains(jsonStr, `"elapsed_time": "01:30:00"`) {
-		t.Errorf("Expected elapsed_time '01:30:00', got:\n%s", jsonStr)
+	if defaultRefreshInterval != 60*time.Second {
+		t.Errorf("defaultRefreshInterval = %v, want %v", defaultRefreshInterval, 60*time.Second)
 	}
 
-	// Previous job duration should be preserved
-	if !strings.Contains(jsonStr, `"duration": "00:45:30"`) {
-		t.Errorf("Expected previous duration '00:45:30', got:\n%s", jsonStr)
+	output := captureStatOutput(t, handler.clearScreen)
+	for _, seq := range []string{"\033[H", "\033[2J", "\033[3J"} {
+		if !strings.Contains(output, seq) {
+			t.Errorf("Expected output to contain ANSI sequence %q", seq)
+		}
 	}
 
-	// Timestamps should be in RFC3339 format
-	if !strings.Contains(jsonStr, "2024-01-15T10:30:00Z") {
-		t.Errorf("Expected RFC3339 timestamp, got:\n%s", jsonStr)
+	output = captureStatOutput(t, func() {
+		handler.displayWatchHeader(time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC))
+	})
+	for _, want := range []string{"Watch mode enabled", "Refresh every 60s", "Ctrl+C to exit", "Last refresh: 2024-01-15 10:30:00"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got %q", want, output)
+		}
 	}
 }
 
-func TestStatHandler_formatJSON_FieldCompleteness(t *testing.T) {
-	tmpDir := setupTestDir(t)
-	handler := NewStatHandler(&mockConfig{workDir: tmpDir}, &mockLogger{})
-
-	result := &StatResult{
-		ExitCode: 0,
-		Duration: time.Second,
-		StatusInfo: &StatusInfo{
-			Current: CurrentJobInfo{
-				Module:      "test_module",
-				Job:         "job_1",
-				Description: "Test description",
-				Status:      "RUNNING",
-				LoopCount:   3,
-				StartedAt:   time.Now(),
-				ElapsedTime: "00:10:00",
-			},
-			Progress: ProgressInfo{
-				TotalJobs:     5,
-				CompletedJobs: 2,
-				FailedJobs:    1,
-				PendingJobs:   1,
-				RunningJobs:   1,
-				Percentage:    40,
-			},
-			Modules: []ModuleStatus{
-				{Name: "mod1", Status: "completed", TotalJobs: 2, CompletedJobs: 2},
-				{Name: "mod2", Status: "in_progress", TotalJobs: 3, CompletedJobs: 0},
-			},
-			DebugIssues: []DebugIssue{
-				{
-					ID:          "d1",
-					Description: "Issue 1",
-					Loop:        2,
-					Hypothesis:  "Test hypothesis",
-					Status:      "待修复",
-					Timestamp:   time.Now(),
-				},
-			},
-		},
-	}
-
-	jsonStr, err := handler.formatJSON(result)
-	if err != nil {
-		t.Fatalf("formatJSON() error = %v", err)
-	}
-
-	// Parse JSON to verify all fields are present
-	var output JSONOutput
-	if err := json.Unmarshal([]byte(jsonStr), &output); err != nil {
-		t.Fatalf("Failed to parse JSON: %v\nOutput: %s", err, jsonStr)
-	}
-
-	// Verify current job fields
-	if output.Current.Module != "test_module" {
-		t.Errorf("Expected module 'test_module', got '%s'", output.Current.Module)
-	}
-	if output.Current.Job != "job_1" {
-		t.Errorf("Expected job 'job_1', got '%s'", output.Current.Job)
-	}
-	if output.Current.Description != "Test description" {
-		t.Errorf("Expected description 'Test description', got '%s'", output.Current.Description)
-	}
-	if output.Current.LoopCount != 3 {
-		t.Errorf("Expected loop_count 3, got %d", output.Current.LoopCount)
-	}
-	if output.Current.ElapsedTime != "00:10:00" {
-		t.Errorf("Expected elapsed_time '00:10:00', got '%s'", output.Current.ElapsedTime)
-	}
-
-	// Verify progress fields
-	if output.Progress.TotalJobs != 5 {
-		t.Errorf("Expected total_jobs 5, got %d", output.Progress.TotalJobs)
-	}
-	if output.Progress.Percentage != 40 {
-		t.Errorf("Expected percentage 40, got %d", output.Progress.Percentage)
-	}
-
-	// Verify modules
-	if len(output.Modules) != 2 {
-		t.Errorf("Expected 2 modules, got %d", len(output.Modules))
-	}
-
-	// Verify debug issues
-	if len(output.DebugIssues) != 1 {
-		t.Errorf("Expected 1 debug issue, got %d", len(output.DebugIssues))
+func TestStatHandler_DisplayStatus(t *testing.T) {
+	handler := NewStatHandler(&mockConfig{}, &mockLogger{})
+	output := captureStatOutput(t, func() {
+		handler.DisplayStatus(createStatTestStatus())
+	})
+
+	for _, want := range []string{
+		"Overall Status: ▶️ RUNNING",
+		"Progress: 2/4 jobs completed (50.0%)",
+		"Modules: 1/2 completed",
+		"Last Update: 2026-01-02 03:04:05",
+		"Profile: ci",
+		"✅ [1] 配置",
+		"▶️ [2] 命令行",
+		"Jobs: 0/2 (running: 1)",
+		"Dependencies: config",
+		"▶️ [2.1] job_1 (1/4 tasks)",
+		"Current Execution:",
+		"Job: job_1 (job 1/2 in module)",
+		"Progress: 1/4 tasks completed",
+		"Loop: 0, Retry: 1",
+		"Elapsed: ",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Output missing %q:\n%s", want, output)
+		}
 	}
-	if output.DebugIssues[0].ID != "d1" {
-		t.Errorf("Expected debug issue ID 'd1', got '%s'", output.DebugIssues[0].ID)
+	if strings.Contains(output, "[2.2]") {
+		t.Errorf("Pending jobs should not be listed:\n%s", output)
 	}
 }
 
-func TestStatHandler_outputText(t *testing.T) {
-	tmpDir := setupTestDir(t)
-
-	handler := NewStatHandler(&mockConfig{workDir: tmpDir}, &mockLogger{})
+func TestStatHandler_DisplayStatus_Empty(t *testing.T) {
+	handler := NewStatHandler(&mockConfig{}, &mockLogger{})
+	output := captureStatOutput(t, func() {
+		handler.DisplayStatus(&state.ExecutionStatus{Global: state.GlobalState{Status: state.StatusPending}})
+	})
 
-	tests := []struct {
-		name   string
-		result *StatResult
-	}{
-		{
-			name: "error result",
-			result: &StatResult{
-				Err:      fmt.Errorf("test error"),
-				ExitCode: 1,
-			},
-		},
-		{
-			name: "no current job",
-			result: &StatResult{
-				ExitCode: 0,
-				Summary: &state.Summary{
-					TotalModules: 0,
-					TotalJobs:    0,
-				},
-			},
-		},
-		{
-			name: "with current job and summary",
-			result: &StatResult{
-				ExitCode: 0,
-				CurrentJob: &state.CurrentJob{
-					Module:    "test",
-					Job:       "job",
-					Status:    state.StatusRunning,
-					StartedAt: time.Now(),
-				},
-				Summary: &state.Summary{
-					TotalModules: 1,
-					TotalJobs:    2,
-					Pending:      1,
-					Running:      1,
-					Completed:    0,
-					Failed:       0,
-					Blocked:      0,
-					Modules: map[string]state.ModuleSummary{
-						"test": {
-							TotalJobs: 2,
-							Pending:   1,
-							Running:   1,
-						},
-					},
-				},
-			},
-		},
+	if !strings.Contains(output, "Progress: 0/0 jobs completed (0.0%)") {
+		t.Errorf("Unexpected output for an empty status:\n%s", output)
 	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// Redirect stdout to capture output
-			oldStdout := os.Stdout
-			r, w, _ := os.Pipe()
-			os.Stdout = w
-
-			handler.outputText(tt.result)
-
-			w.Close()
-			os.Stdout = oldStdout
-
-			outputBytes, err := io.ReadAll(r)
-			if err != nil {
-				t.Fatalf("Failed to read output: %v", err)
-			}
-			output := string(outputBytes)
-			if output == "" {
-				t.Error("Expected text output, got empty string")
-			}
-
-			// Check for expected content
-			// For error results, output is just the error message
-			// For success results, output contains "Morty Status"
-			if tt.result.Err != nil {
-				if !strings.Contains(output, tt.result.Err.Error()) {
-					t.Errorf("Expected output to contain error message '%s', got '%s'", tt.result.Err.Error(), output)
-				}
-			} else {
-				if !strings.Contains(output, "Morty Status") {
-					t.Error("Expected output to contain 'Morty Status'")
-				}
-			}
-		})
+	if strings.Contains(output, "Current Execution:") || strings.Contains(output, "Profile:") {
+		t.Errorf("Empty status should have no current execution or profile:\n%s", output)
 	}
 }
 
-func TestStatHandler_getStatusString(t *testing.T) {
+func TestStatHandler_FormatStatusAsJSON(t *testing.T) {
 	handler := NewStatHandler(&mockConfig{}, &mockLogger{})
 
-	tests := []struct {
-		name     string
-		result   *StatResult
-		expected string
-	}{
-		{
-			name: "error result",
-			result: &StatResult{
-				Err: fmt.Errorf("test error"),
-			},
-			expected: "error",
-		},
-		{
-			name: "idle result",
-			result: &StatResult{
-				ExitCode: 0,
-			},
-			expected: "idle",
-		},
-		{
-			name: "running result",
-			result: &StatResult{
-				ExitCode: 0,
-				CurrentJob: &state.CurrentJob{
-					Status: state.StatusRunning,
-				},
-			},
-			expected: "RUNNING",
-		},
-		{
-			name: "completed result",
-			result: &StatResult{
-				ExitCode: 0,
-				CurrentJob: &state.CurrentJob{
-					Status: state.StatusCompleted,
-				},
-			},
-			expected: "COMPLETED",
-		},
+	output, err := handler.FormatStatusAsJSON(createStatTestStatus())
+	if err != nil {
+		t.Fatalf("FormatStatusAsJSON() error: %v", err)
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := handler.getStatusString(tt.result)
-			if got != tt.expected {
-				t.Errorf("getStatusString() = %v, want %v", got, tt.expected)
-			}
-		})
+	var parsed state.ExecutionStatus
+	if err := json.Unmarshal([]byte(output), &parsed); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if parsed.Global.TotalJobs != 4 || parsed.Modules[1].Jobs[0].TasksCompleted != 1 {
+		t.Errorf("Unexpected JSON round trip: %+v", parsed.Global)
 	}
 }
 
-func TestStatResult_Struct(t *testing.T) {
-	result := &StatResult{
-		Status:     "test",
-		ExitCode:   0,
-		Duration:   time.Second,
-		JSONOutput: true,
+func TestGetJobIcon(t *testing.T) {
+	tests := map[state.Status]string{
+		state.StatusCompleted: "✅",
+		state.StatusRunning:   "▶️",
+		state.StatusFailed:    "❌",
+		state.StatusBlocked:   "🚫",
+		state.StatusPending:   "⏳",
 	}
-
-	if result.Status != "test" {
-		t.Error("Status not set correctly")
+	for status, want := range tests {
+		if got := getJobIcon(status); got != want {
+			t.Errorf("getJobIcon(%s) = %s, want %s", status, got, want)
+		}
 	}
+}
 
-	if result.ExitCode != 0 {
-		t.Error("ExitCode not set correctly")
+func TestColorizeStatus(t *testing.T) {
+	tests := map[string]string{
+		"COMPLETED": "✅ COMPLETED",
+		"RUNNING":   "▶️ RUNNING",
+		"FAILED":    "❌ FAILED",
+		"PENDING":   "⏳ PENDING",
+		"BLOCKED":   "BLOCKED",
 	}
-
-	if result.Duration != time.Second {
-		t.Error("Duration not set correctly")
-	}
-
-	if !result.JSONOutput {
-		t.Error("JSONOutput not set correctly")
-	}
-}
-
-func TestStatHandler_collectStatus(t *testing.T) {
-	tmpDir := setupTestDir(t)
-
-	// Create a mock status.json file with comprehensive data (version 1.0 format)
-	statusFile := filepath.Join(tmpDir, "status.json")
-	statusData := `{
-		"version": "1.0",
-		"global": {
-			"status": "RUNNING",
-			"current_module": "test_module",
-			"current_job": "job_2",
-			"start_time": "2024-01-01T00:00:00Z",
-			"last_update": "2024-01-01T10:00:00Z",
-			"total_loops": 5
-		},
-		"modules": {
-			"test_module": {
-				"name": "test_module",
-				"status": "RUNNING",
-				"jobs": {
-					"job_1": {
-						"name": "job_1",
-						"status": "COMPLETED",
-						"loop_count": 1,
-						"tasks_total": 3,
-						"tasks_completed": 3,
-						"debug_logs": [],
-						"created_at": "2024-01-01T08:00:00Z",
-						"updated_at": "2024-01-01T09:00:00Z",
-						"completed_at": "2024-01-01T09:00:00Z"
-					},
-					"job_2": {
-						"name": "job_2",
-						"status": "RUNNING",
-						"loop_count": 2,
-						"tasks_total": 5,
-						"tasks_completed": 2,
-						"debug_logs": [
-							{
-								"id": "debug1",
-								"timestamp": "2024-01-01T09:30:00Z",
-								"phenomenon": "Test failure in loop 2",
-								"reproduction": "Run test",
-								"hypothesis": "Missing mock",
-								"verification": "Add mock",
-								"fix": "Add mock config",
-								"progress": "待修复"
-							}
-						],
-						"created_at": "2024-01-01T10:00:00Z",
-						"updated_at": "2024-01-01T10:00:00Z"
-					}
-				},
-				"created_at": "2024-01-01T08:00:00Z",
-				"updated_at": "2024-01-01T10:00:00Z"
-			}
-		}
-	}`
-
-	if err := os.WriteFile(statusFile, []byte(statusData), 0644); err != nil {
-		t.Fatalf("Failed to create status file: %v", err)
-	}
-
-	cfg := &mockConfig{workDir: tmpDir}
-	logger := &mockLogger{}
-	handler := NewStatHandler(cfg, logger)
-
-	stateManager := state.NewManager(statusFile)
-	if err := stateManager.Load(); err != nil {
-		t.Fatalf("Failed to load state: %v", err)
-	}
-
-	statusInfo, err := handler.collectStatus(stateManager)
-	if err != nil {
-		t.Errorf("collectStatus failed: %v", err)
-	}
-
-	if statusInfo == nil {
-		t.Fatal("Expected statusInfo to be non-nil")
-	}
-
-	// Check current job
-	if statusInfo.Current.Module != "test_module" {
-		t.Errorf("Expected current module 'test_module', got '%s'", statusInfo.Current.Module)
-	}
-	if statusInfo.Current.Job != "job_2" {
-		t.Errorf("Expected current job 'job_2', got '%s'", statusInfo.Current.Job)
-	}
-
-	// Check progress
-	if statusInfo.Progress.TotalJobs != 2 {
-		t.Errorf("Expected 2 total jobs, got %d", statusInfo.Progress.TotalJobs)
-	}
-	if statusInfo.Progress.CompletedJobs != 1 {
-		t.Errorf("Expected 1 completed job, got %d", statusInfo.Progress.CompletedJobs)
-	}
-	if statusInfo.Progress.Percentage != 50 {
-		t.Errorf("Expected 50%% progress, got %d", statusInfo.Progress.Percentage)
-	}
-
-	// Check modules
-	if len(statusInfo.Modules) != 1 {
-		t.Errorf("Expected 1 module, got %d", len(statusInfo.Modules))
-	}
-
-	// Check debug issues
-	if len(statusInfo.DebugIssues) != 1 {
-		t.Errorf("Expected 1 debug issue, got %d", len(statusInfo.DebugIssues))
-	} else {
-		issue := statusInfo.DebugIssues[0]
-		if issue.Description != "Test failure in loop 2" {
-			t.Errorf("Expected description 'Test failure in loop 2', got '%s'", issue.Description)
-		}
-		if issue.Hypothesis != "Missing mock" {
-			t.Errorf("Expected hypothesis 'Missing mock', got '%s'", issue.Hypothesis)
-		}
-	}
-
-	// Check previous job
-	if statusInfo.Previous == nil {
-		t.Error("Expected previous job to be found")
-	} else {
-		if statusInfo.Previous.Module != "test_module" {
-			t.Errorf("Expected previous module 'test_module', got '%s'", statusInfo.Previous.Module)
-		}
-		if statusInfo.Previous.Job != "job_1" {
-			t.Errorf("Expected previous job 'job_1', got '%s'", statusInfo.Previous.Job)
-		}
-	}
-}
-
-func TestStatHandler_findPreviousJob(t *testing.T) {
-	tmpDir := setupTestDir(t)
-
-	// Create status file with completed jobs
-	statusFile := filepath.Join(tmpDir, "status.json")
-	statusData := `{
-		"version": "2.0",
-		"modules": {
-			"module1": {
-				"status": "COMPLETED",
-				"jobs": {
-					"job_1": {
-						"status": "COMPLETED",
-						"completed_at": "2024-01-01T08:00:00Z",
-						"started_at": "2024-01-01T07:00:00Z"
-					},
-					"job_2": {
-						"status": "COMPLETED",
-						"completed_at": "2024-01-01T10:00:00Z",
-						"started_at": "2024-01-01T09:00:00Z"
-					}
-				}
-			},
-			"module2": {
-				"status": "RUNNING",
-				"jobs": {
-					"job_1": {
-						"status": "RUNNING"
-					}
-				}
-			}
-		}
-	}`
-
-	if err := os.WriteFile(statusFile, []byte(statusData), 0644); err != nil {
-		t.Fatalf("Failed to create status file: %v", err)
-	}
-
-	cfg := &mockConfig{workDir: tmpDir}
-	handler := NewStatHandler(cfg, &mockLogger{})
-	stateManager := state.NewManager(statusFile)
-	stateManager.Load()
-
-	// Find previous job from current running job
-	previous := handler.findPreviousJob(stateManager, "module2", "job_1")
-
-	if previous == nil {
-		t.Fatal("Expected to find previous job")
-	}
-
-	if previous.Module != "module1" {
-		t.Errorf("Expected module 'module1', got '%s'", previous.Module)
-	}
-
-	if previous.Job != "job_2" {
-		t.Errorf("Expected job 'job_2', got '%s'", previous.Job)
-	}
-
-	if previous.Status != "COMPLETED" {
-		t.Errorf("Expected status 'COMPLETED', got '%s'", previous.Status)
-	}
-
-	// Duration should be 1 hour (formatted as string)
-	expectedDuration := "01:00:00"
-	if previous.Duration != expectedDuration {
-		t.Errorf("Expected duration %v, got %v", expectedDuration, previous.Duration)
-	}
-}
-
-func TestStatHandler_extractDebugIssues(t *testing.T) {
-	tmpDir := setupTestDir(t)
-
-	statusFile := filepath.Join(tmpDir, "status.json")
-	statusData := `{
-		"version": "2.0",
-		"modules": {
-			"test_module": {
-				"status": "RUNNING",
-				"jobs": {
-					"job_1": {
-						"status": "RUNNING",
-						"loop_count": 3,
-						"debug_logs": [
-							{
-								"id": "debug1",
-								"timestamp": "2024-01-01T10:00:00Z",
-								"phenomenon": "Connection timeout",
-								"hypothesis": "Network issue",
-								"progress": "待修复"
-							},
-							{
-								"id": "debug2",
-								"timestamp": "2024-01-01T11:00:00Z",
-								"phenomenon": "Memory leak",
-								"hypothesis": "Missing cleanup",
-								"progress": "已修复"
-							}
-						]
-					}
-				}
-			}
-		}
-	}`
-
-	if err := os.WriteFile(statusFile, []byte(statusData), 0644); err != nil {
-		t.Fatalf("Failed to create status file: %v", err)
-	}
-
-	cfg := &mockConfig{workDir: tmpDir}
-	handler := NewStatHandler(cfg, &mockLogger{})
-	stateManager := state.NewManager(statusFile)
-	stateManager.Load()
-
-	issues := handler.extractDebugIssues(stateManager, "test_module", "job_1")
-
-	if len(issues) != 2 {
-		t.Errorf("Expected 2 debug issues, got %d", len(issues))
-	}
-
-	if len(issues) >= 1 {
-		if issues[0].Description != "Connection timeout" {
-			t.Errorf("Expected description 'Connection timeout', got '%s'", issues[0].Description)
-		}
-		if issues[0].Loop != 3 {
-			t.Errorf("Expected loop 3, got %d", issues[0].Loop)
-		}
-	}
-
-	// Test with empty module/job
-	emptyIssues := handler.extractDebugIssues(stateManager, "", "")
-	if len(emptyIssues) != 0 {
-		t.Errorf("Expected 0 issues for empty module/job, got %d", len(emptyIssues))
-	}
-}
-
-func TestStatHandler_formatDuration(t *testing.T) {
-	handler := NewStatHandler(&mockConfig{}, &mockLogger{})
-
-	tests := []struct {
-		name     string
-		duration time.Duration
-		expected string
-	}{
-		{
-			name:     "seconds only",
-			duration: 45 * time.Second,
-			expected: "00:45",
-		},
-		{
-			name:     "minutes and seconds",
-			duration: 5*time.Minute + 30*time.Second,
-			expected: "05:30",
-		},
-		{
-			name:     "hours minutes seconds",
-			duration: 2*time.Hour + 30*time.Minute + 15*time.Second,
-			expected: "02:30:15",
-		},
-		{
-			name:     "zero",
-			duration: 0,
-			expected: "00:00",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := handler.formatDuration(tt.duration)
-			if got != tt.expected {
-				t.Errorf("formatDuration() = %v, want %v", got, tt.expected)
-			}
-		})
-	}
-}
-
-func TestStatHandler_formatProgressBar(t *testing.T) {
-	handler := NewStatHandler(&mockConfig{}, &mockLogger{})
-
-	tests := []struct {
-		name       string
-		percentage int
-		width      int
-		expected   string
-	}{
-		{
-			name:       "0 percent",
-			percentage: 0,
-			width:      10,
-			expected:   "░░░░░░░░░░",
-		},
-		{
-			name:       "50 percent",
-			percentage: 50,
-			width:      10,
-			expected:   "█████░░░░░",
-		},
-		{
-			name:       "100 percent",
-			percentage: 100,
-			width:      10,
-			expected:   "██████████",
-		},
-		{
-			name:       "over 100 percent capped",
-			percentage: 150,
-			width:      10,
-			expected:   "██████████",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := handler.formatProgressBar(tt.percentage, tt.width)
-			if got != tt.expected {
-				t.Errorf("formatProgressBar() = %q, want %q", got, tt.expected)
-			}
-		})
-	}
-}
-
-func TestStatHandler_outputEnhancedText(t *testing.T) {
-	tmpDir := setupTestDir(t)
-	handler := NewStatHandler(&mockConfig{workDir: tmpDir}, &mockLogger{})
-
-	result := &StatResult{
-		ExitCode: 0,
-		Duration: time.Second,
-		StatusInfo: &StatusInfo{
-			Current: CurrentJobInfo{
-				Module:    "test_module",
-				Job:       "job_1",
-				Status:    "RUNNING",
-				StartedAt: time.Now().Add(-30 * time.Minute),
-			},
-			Previous: &PreviousJob{
-				Module:      "test_module",
-				Job:         "job_0",
-				Status:      "COMPLETED",
-				Duration:    "15:00",
-				CompletedAt: time.Now().Add(-1 * time.Hour),
-			},
-			Progress: ProgressInfo{
-				TotalJobs:     10,
-				CompletedJobs: 5,
-				Percentage:    50,
-			},
-			Modules: []ModuleStatus{
-				{Name: "module1", Status: "completed", TotalJobs: 3, CompletedJobs: 3},
-				{Name: "module2", Status: "in_progress", TotalJobs: 3, CompletedJobs: 2},
-			},
-			DebugIssues: []DebugIssue{
-				{
-					ID:          "debug1",
-					Description: "Test issue",
-					Loop:        2,
-					Hypothesis:  "Missing config",
-					Status:      "待修复",
-				},
-			},
-		},
-	}
-
-	// Redirect stdout to capture output
-	oldStdout := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
-
-	handler.outputEnhancedText(result)
-
-	w.Close()
-	os.Stdout = oldStdout
-
-	outputBytes, err := io.ReadAll(r)
-	if err != nil {
-		t.Fatalf("Failed to read output: %v", err)
-	}
-	output := string(outputBytes)
-
-	if output == "" {
-		t.Error("Expected output, got empty string")
-	}
-
-	// Check for expected content
-	expectedStrings := []string{
-		"Morty 监控大盘",
-		"test_module",
-		"job_1",
-		"RUNNING",
-		"上一个 Job",
-		"job_0",
-		"COMPLETED",
-		"整体进度",
-		"50%",
-		"Test issue",
-	}
-
-	for _, expected := range expectedStrings {
-		if !strings.Contains(output, expected) {
-			t.Errorf("Expected output to contain '%s'", expected)
-		}
-	}
-}
-
-func TestStatInfo_Structs(t *testing.T) {
-	// Test StatusInfo
-	statusInfo := &StatusInfo{
-		Current: CurrentJobInfo{
-			Module: "test",
-			Job:    "job1",
-			Status: "RUNNING",
-		},
-		Modules: []ModuleStatus{
-			{Name: "mod1", Status: "completed"},
-		},
-		DebugIssues: []DebugIssue{
-			{ID: "d1", Description: "test issue"},
-		},
-	}
-
-	if statusInfo.Current.Module != "test" {
-		t.Error("Current.Module not set correctly")
-	}
-
-	// Test ProgressInfo
-	progress := ProgressInfo{
-		TotalJobs:     10,
-		CompletedJobs: 5,
-		Percentage:    50,
-	}
-	if progress.Percentage != 50 {
-		t.Error("Percentage not set correctly")
-	}
-
-	// Test PreviousJob
-	prev := &PreviousJob{
-		Module: "mod",
-		Job:    "job",
-		Status: "COMPLETED",
-	}
-	if prev.Status != "COMPLETED" {
-		t.Error("PreviousJob.Status not set correctly")
-	}
-}
-
-// Table formatting tests
-
-func TestNewTableFormatter(t *testing.T) {
-	f := NewTableFormatter(true, true)
-	if f == nil {
-		t.Fatal("Expected TableFormatter to be non-nil")
-	}
-	if !f.useColor {
-		t.Error("Expected useColor to be true")
-	}
-	if !f.useUnicode {
-		t.Error("Expected useUnicode to be true")
-	}
-}
-
-func TestTableFormatter_topBorder(t *testing.T) {
-	f := NewTableFormatter(false, true)
-	border := f.topBorder()
-	if !strings.HasPrefix(border, "┌") {
-		t.Errorf("Expected border to start with '┌', got '%s'", border)
-	}
-	if !strings.HasSuffix(border, "┐") {
-		t.Errorf("Expected border to end with '┐', got '%s'", border)
-	}
-	if len(border) != tableWidth {
-		t.Errorf("Expected border length %d, got %d", tableWidth, len(border))
-	}
-}
-
-func TestTableFormatter_bottomBorder(t *testing.T) {
-	f := NewTableFormatter(false, true)
-	border := f.bottomBorder()
-	if !strings.HasPrefix(border, "└") {
-		t.Errorf("Expected border to start with '└', got '%s'", border)
-	}
-	if !strings.HasSuffix(border, "┘") {
-		t.Errorf("Expected border to end with '┘', got '%s'", border)
-	}
-}
-
-func TestTableFormatter_sectionSeparator(t *testing.T) {
-	f := NewTableFormatter(false, true)
-	sep := f.sectionSeparator()
-	if !strings.HasPrefix(sep, "├") {
-		t.Errorf("Expected separator to start with '├', got '%s'", sep)
-	}
-	if !strings.HasSuffix(sep, "┤") {
-		t.Errorf("Expected separator to end with '┤', got '%s'", sep)
-	}
-}
-
-func TestTableFormatter_formatTitle(t *testing.T) {
-	f := NewTableFormatter(false, true)
-	title := f.formatTitle("Test Title")
-	if !strings.Contains(title, "Test Title") {
-		t.Errorf("Expected title to contain 'Test Title', got '%s'", title)
-	}
-	if !strings.HasPrefix(title, "│") {
-		t.Errorf("Expected title to start with '│', got '%s'", title)
-	}
-}
-
-func TestTableFormatter_formatSectionHeader(t *testing.T) {
-	f := NewTableFormatter(false, true)
-	header := f.formatSectionHeader("Section")
-	if !strings.Contains(header, "Section") {
-		t.Errorf("Expected header to contain 'Section', got '%s'", header)
-	}
-}
-
-func TestTableFormatter_formatContentLine(t *testing.T) {
-	f := NewTableFormatter(false, true)
-	line := f.formatContentLine("test content", 0)
-	if !strings.HasPrefix(line, "│") {
-		t.Errorf("Expected line to start with '│', got '%s'", line)
-	}
-	if !strings.Contains(line, "test content") {
-		t.Errorf("Expected line to contain 'test content', got '%s'", line)
-	}
-
-	// Test with indentation
-	line2 := f.formatContentLine("indented", 1)
-	if !strings.Contains(line2, "  indented") {
-		t.Errorf("Expected indented line to contain '  indented', got '%s'", line2)
-	}
-}
-
-func TestTableFormatter_formatDurationLine(t *testing.T) {
-	f := NewTableFormatter(false, true)
-	duration := 5*time.Minute + 30*time.Second
-	line := f.formatDurationLine(duration)
-	if !strings.Contains(line, "Duration:") {
-		t.Errorf("Expected line to contain 'Duration:', got '%s'", line)
-	}
-	if !strings.Contains(line, "05:30") {
-		t.Errorf("Expected line to contain '05:30', got '%s'", line)
-	}
-}
-
-func TestStatHandler_formatTable(t *testing.T) {
-	tmpDir := setupTestDir(t)
-	handler := NewStatHandler(&mockConfig{workDir: tmpDir}, &mockLogger{})
-
-	info := &StatusInfo{
-		Current: CurrentJobInfo{
-			Module:    "test_module",
-			Job:       "job_1",
-			Status:    "RUNNING",
-			StartedAt: time.Now().Add(-30 * time.Minute),
-		},
-		Previous: &PreviousJob{
-			Module:      "test_module",
-			Job:         "job_0",
-			Status:      "COMPLETED",
-			Duration:    "15:00",
-			CompletedAt: time.Now().Add(-1 * time.Hour),
-		},
-		Progress: ProgressInfo{
-			TotalJobs:     10,
-			CompletedJobs: 5,
-			Percentage:    50,
-		},
-		Modules: []ModuleStatus{
-			{Name: "module1", Status: "completed", TotalJobs: 3, CompletedJobs: 3},
-			{Name: "module2", Status: "in_progress", TotalJobs: 3, CompletedJobs: 2},
-		},
-		DebugIssues: []DebugIssue{
-			{
-				ID:          "debug1",
-				Description: "Test issue",
-				Loop:        2,
-				Hypothesis:  "Missing config",
-				Status:      "待修复",
-			},
-		},
-	}
-
-	output := handler.formatTable(info, time.Second)
-
-	// Verify table structure
-	expectedElements := []string{
-		"┌", "┐", "└", "┘", "├", "┤", "│",
-		"Morty 监控大盘",
-		"当前执行",
-		"test_module",
-		"job_1",
-		"上一个 Job",
-		"job_0",
-		"整体进度",
-		"50%",
-		"Debug 问题",
-		"Test issue",
-	}
-
-	for _, expected := range expectedElements {
-		if !strings.Contains(output, expected) {
-			t.Errorf("Expected output to contain '%s'", expected)
-		}
-	}
-}
-
-func TestStatHandler_formatCurrentJobSection(t *testing.T) {
-	tmpDir := setupTestDir(t)
-	handler := NewStatHandler(&mockConfig{workDir: tmpDir}, &mockLogger{})
-	f := NewTableFormatter(false, true)
-
-	// Test with current job
-	current := CurrentJobInfo{
-		Module:    "test_module",
-		Job:       "job_1",
-		Status:    "RUNNING",
-		StartedAt: time.Now().Add(-30 * time.Minute),
-	}
-	output := handler.formatCurrentJobSection(current, f)
-
-	expectedStrings := []string{"模块:", "test_module", "Job:", "job_1", "状态:", "RUNNING"}
-	for _, expected := range expectedStrings {
-		if !strings.Contains(output, expected) {
-			t.Errorf("Expected output to contain '%s'", expected)
-		}
-	}
-
-	// Test with no current job
-	emptyCurrent := CurrentJobInfo{}
-	emptyOutput := handler.formatCurrentJobSection(emptyCurrent, f)
-	if !strings.Contains(emptyOutput, "无") {
-		t.Errorf("Expected empty output to contain '无', got '%s'", emptyOutput)
-	}
-}
-
-func TestStatHandler_formatPreviousJobSection(t *testing.T) {
-	tmpDir := setupTestDir(t)
-	handler := NewStatHandler(&mockConfig{workDir: tmpDir}, &mockLogger{})
-	f := NewTableFormatter(false, true)
-
-	previous := &PreviousJob{
-		Module:      "test_module",
-		Job:         "job_0",
-		Status:      "COMPLETED",
-		Duration:    "15:00",
-		CompletedAt: time.Now().Add(-1 * time.Hour),
-	}
-
-	output := handler.formatPreviousJobSection(previous, f)
-
-	if !strings.Contains(output, "test_module/job_0") && !strings.Contains(output, "test_module") {
-		t.Errorf("Expected output to contain job info, got '%s'", output)
-	}
-	if !strings.Contains(output, "COMPLETED") {
-		t.Errorf("Expected output to contain status, got '%s'", output)
-	}
-}
-
-func TestStatHandler_formatDebugIssuesSection(t *testing.T) {
-	tmpDir := setupTestDir(t)
-	handler := NewStatHandler(&mockConfig{workDir: tmpDir}, &mockLogger{})
-	f := NewTableFormatter(false, true)
-
-	issues := []DebugIssue{
-		{
-			ID:          "debug1",
-			Description: "Test failure",
-			Loop:        2,
-			Hypothesis:  "Missing mock",
-			Status:      "待修复",
-		},
-	}
-
-	output := handler.formatDebugIssuesSection(issues, f)
-
-	if !strings.Contains(output, "Test failure") {
-		t.Errorf("Expected output to contain description, got '%s'", output)
-	}
-	if !strings.Contains(output, "猜想:") {
-		t.Errorf("Expected output to contain hypothesis label, got '%s'", output)
-	}
-	if !strings.Contains(output, "状态:") {
-		t.Errorf("Expected output to contain status label, got '%s'", output)
-	}
-}
-
-func TestStatHandler_formatProgressSection(t *testing.T) {
-	tmpDir := setupTestDir(t)
-	handler := NewStatHandler(&mockConfig{workDir: tmpDir}, &mockLogger{})
-	f := NewTableFormatter(false, true)
-
-	progress := ProgressInfo{
-		TotalJobs:     10,
-		CompletedJobs: 5,
-		Percentage:    50,
-	}
-	modules := []ModuleStatus{
-		{Name: "module1", Status: "completed", TotalJobs: 3, CompletedJobs: 3},
-		{Name: "module2", Status: "in_progress", TotalJobs: 3, CompletedJobs: 2},
-	}
-
-	output := handler.formatProgressSection(progress, modules, f)
-
-	if !strings.Contains(output, "50%") {
-		t.Errorf("Expected output to contain '50%%', got '%s'", output)
-	}
-	if !strings.Contains(output, "5/10 Jobs") {
-		t.Errorf("Expected output to contain job count, got '%s'", output)
-	}
-	if !strings.Contains(output, "已完成:") {
-		t.Errorf("Expected output to contain completed label, got '%s'", output)
-	}
-}
-
-func TestStatHandler_formatModuleGroup(t *testing.T) {
-	tmpDir := setupTestDir(t)
-	handler := NewStatHandler(&mockConfig{workDir: tmpDir}, &mockLogger{})
-	f := NewTableFormatter(false, true)
-
-	modules := []ModuleStatus{
-		{Name: "mod1", Status: "completed", TotalJobs: 3, CompletedJobs: 3},
-		{Name: "mod2", Status: "completed", TotalJobs: 2, CompletedJobs: 2},
-	}
-
-	output := handler.formatModuleGroup("已完成", modules, "", f)
-
-	if !strings.Contains(output, "已完成:") {
-		t.Errorf("Expected output to contain label, got '%s'", output)
-	}
-	if !strings.Contains(output, "mod1") {
-		t.Errorf("Expected output to contain module name, got '%s'", output)
-	}
-}
-
-func TestStatHandler_supportsColor(t *testing.T) {
-	tmpDir := setupTestDir(t)
-	handler := NewStatHandler(&mockConfig{workDir: tmpDir}, &mockLogger{})
-
-	// Save original env
-	origNoColor := os.Getenv("NO_COLOR")
-	defer os.Setenv("NO_COLOR", origNoColor)
-
-	// Test with NO_COLOR set
-	os.Setenv("NO_COLOR", "1")
-	if handler.supportsColor() {
-		t.Error("Expected supportsColor to return false when NO_COLOR is set")
-	}
-
-	// Test with NO_COLOR unset
-	os.Unsetenv("NO_COLOR")
-	// Result depends on whether stdout is a terminal, so we just check it doesn't panic
-	_ = handler.supportsColor()
-}
-
-// Watch mode tests
-
-func TestStatHandler_clearScreen(t *testing.T) {
-	handler := NewStatHandler(&mockConfig{}, &mockLogger{})
-
-	// Redirect stdout to capture output
-	oldStdout := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
-
-	// Call clearScreen
-	handler.clearScreen()
-
-	w.Close()
-	os.Stdout = oldStdout
-
-	outputBytes, _ := io.ReadAll(r)
-	output := string(outputBytes)
-
-	// Verify ANSI escape sequences are present
-	if !strings.Contains(output, "\033[") {
-		t.Error("Expected clearScreen to output ANSI escape sequences")
-	}
-}
-
-func TestStatHandler_displayWatchHeader(t *testing.T) {
-	handler := NewStatHandler(&mockConfig{}, &mockLogger{})
-
-	// Redirect stdout to capture output
-	oldStdout := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
-
-	// Test with specific time
-	testTime := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
-	handler.displayWatchHeader(testTime)
-
-	w.Close()
-	os.Stdout = oldStdout
-
-	outputBytes, _ := io.ReadAll(r)
-	output := string(outputBytes)
-
-	// Verify expected content
-	expectedStrings := []string{
-		"Watch mode enabled",
-		"Refresh every 60s",
-		"Ctrl+C to exit",
-		"Last refresh:",
-		"2024-01-15 10:30:00",
-	}
-
-	for _, expected := range expectedStrings {
-		if !strings.Contains(output, expected) {
-			t.Errorf("Expected output to contain '%s', got '%s'", expected, output)
+	for status, want := range tests {
+		if got := colorizeStatus(status); got != want {
+			t.Errorf("colorizeStatus(%s) = %s, want %s", status, got, want)
 		}
 	}
 }
-
-func TestDefaultRefreshInterval(t *testing.T) {
-	// Verify the default refresh interval is 60 seconds
-	if defaultRefreshInterval != 60*time.Second {
-		t.Errorf("Expected defaultRefreshInterval to be 60s, got %v", defaultRefreshInterval)
-	}
-}
-
-func TestStatHandler_runWatchMode_ContextCancellation(t *testing.T) {
-	tmpDir := setupTestDir(t)
-
-	// Create a minimal status file
-	statusFile := filepath.Join(tmpDir, "status.json")
-	statusData := `{
-		"version": "1.0",
-		"global": {
-			"status": "RUNNING",
-			"current_module": "test",
-			"current_job": "job_1",
-			"start_time": "2024-01-01T00:00:00Z",
-			"last_update": "2024-01-01T00:00:00Z",
-			"total_loops": 1
-		},
-		"modules": {
-			"test": {
-				"name": "test",
-				"status": "RUNNING",
-				"jobs": {
-					"job_1": {
-						"name": "job_1",
-						"status": "RUNNING",
-						"loop_count": 1,
-						"tasks_total": 5,
-						"tasks_completed": 2,
-						"created_at": "2024-01-01T00:00:00Z",
-						"updated_at": "2024-01-01T00:00:00Z"
-					}
-				},
-				"created_at": "2024-01-01T00:00:00Z",
-				"updated_at": "2024-01-01T00:00:00Z"
-			}
-		}
-	}`
-
-	if err := os.WriteFile(statusFile, []byte(statusData), 0644); err != nil {
-		t.Fatalf("Failed to create status file: %v", err)
-	}
-
-	cfg := &mockConfig{workDir: tmpDir}
-	handler := NewStatHandler(cfg, &mockLogger{})
-
-	// Create a context that will be cancelled
-	ctx, cancel := context.WithCancel(context.Background())
-
-	initialResult := &StatResult{
-		ExitCode: 0,
-		Duration: time.Second,
-	}
-
-	// Cancel the context after a short delay
-	go func() {
-		time.Sleep(100 * time.Millisecond)
-		cancel()
-	}()
-
-	// Run watch mode - it should exit gracefully when context is cancelled
-	result, err := handler.runWatchMode(ctx, initialResult)
-
-	// Should return without error on graceful exit
-	if err != nil {
-		t.Errorf("Expected no error on context cancellation, got: %v", err)
-	}
-
-	// Should return the initial result
-	if result == nil {
-		t.Error("Expected result to be non-nil")
-	}
-}
-
-func TestStatHandler_runWatchMode_RefreshInterval(t *testing.T) {
-	// Verify the refresh interval constant
-	if defaultRefreshInterval != 60*time.Second {
-		t.Errorf("Expected refresh interval to be 60s, got %v", defaultRefreshInterval)
-	}
-}
-
-func TestStatHandler_WatchModeFeatures(t *testing.T) {
-	tests := []struct {
-		name     string
-		testFunc func(t *testing.T)
-	}{
-		{
-			name: "clearScreen uses ANSI escape sequences",
-			testFunc: func(t *testing.T) {
-				handler := NewStatHandler(&mockConfig{}, &mockLogger{})
-
-				oldStdout := os.Stdout
-				r, w, _ := os.Pipe()
-				os.Stdout = w
-
-				handler.clearScreen()
-
-				w.Close()
-				os.Stdout = oldStdout
-
-				outputBytes, _ := io.ReadAll(r)
-				output := string(outputBytes)
-
-				// Verify ANSI clear screen sequences
-				expectedSequences := []string{"\033[H", "\033[2J", "\033[3J"}
-				for _, seq := range expectedSequences {
-					if !strings.Contains(output, seq) {
-						t.Errorf("Expected output to contain ANSI sequence '%s'", seq)
-					}
-				}
-			},
-		},
-		{
-			name: "displayWatchHeader shows refresh time",
-			testFunc: func(t *testing.T) {
-				handler := NewStatHandler(&mockConfig{}, &mockLogger{})
-
-				oldStdout := os.Stdout
-				r, w, _ := os.Pipe()
-				os.Stdout = w
-
-				refreshTime := time.Date(2024, 6, 15, 14, 30, 45, 0, time.UTC)
-				handler.displayWatchHeader(refreshTime)
-
-				w.Close()
-				os.Stdout = oldStdout
-
-				outputBytes, _ := io.ReadAll(r)
-				output := string(outputBytes)
-
-				if !strings.Contains(output, "14:30:45") {
-					t.Errorf("Expected output to contain formatted time '14:30:45', got '%s'", output)
-				}
-				if !strings.Contains(output, "60s") {
-					t.Errorf("Expected output to mention '60s' refresh interval, got '%s'", output)
-				}
-			},
-		},
-		{
-			name: "default refresh interval is 60 seconds",
-			testFunc: func(t *testing.T) {
-				if defaultRefreshInterval != 60*time.Second {
-					t.Errorf("defaultRefreshInterval = %v, want %v", defaultRefreshInterval, 60*time.Second)
-				}
-			},
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, tt.testFunc)
-	}
-}
@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/morty/morty/internal/config"
+	"github.com/morty/morty/internal/git"
+	"github.com/morty/morty/internal/logging"
+	"github.com/morty/morty/internal/parser/plan"
+	"github.com/morty/morty/internal/state"
+	"github.com/morty/morty/internal/traceability"
+)
+
+// TraceOptions holds the parsed trace command options.
+type TraceOptions struct {
+	Format string // --format text|json|csv
+	Output string // --output file (stdout if empty)
+}
+
+// TraceResult represents the result of the trace command.
+type TraceResult struct {
+	Matrix  *traceability.Matrix
+	Options TraceOptions
+}
+
+// TraceHandler handles the trace command.
+type TraceHandler struct {
+	cfg    config.Manager
+	logger logging.Logger
+	out    io.Writer
+}
+
+// NewTraceHandler creates a new TraceHandler instance.
+func NewTraceHandler(cfg config.Manager, logger logging.Logger) *TraceHandler {
+	return &TraceHandler{
+		cfg:    cfg,
+		logger: logger,
+		out:    os.Stdout,
+	}
+}
+
+// SetOutput sets the writer used when no --output file is given.
+func (h *TraceHandler) SetOutput(w io.Writer) {
+	h.out = w
+}
+
+// Execute builds the research → module → job → commit matrix from the plan
+// files, status.json and the loop commits in git history, and renders it.
+// It fails when plans reference files that do not exist.
+func (h *TraceHandler) Execute(ctx context.Context, args []string) (*TraceResult, error) {
+	logger := h.logger.WithContext(ctx)
+
+	opts, err := h.parseOptions(args)
+	if err != nil {
+		return nil, err
+	}
+
+	planDir := h.cfg.GetPlanDir()
+	if _, err := os.Stat(planDir); err != nil {
+		return nil, fmt.Errorf("计划目录中没有计划文件: %s", planDir)
+	}
+
+	// status.json and git history are optional
+	var status *state.ExecutionStatus
+	stateManager := state.NewManager(h.cfg.GetStatusFile())
+	if err := stateManager.Load(); err != nil {
+		logger.Debug("No status loaded, tracing plans only", logging.String("error", err.Error()))
+	} else {
+		status = stateManager.GetStatus()
+	}
+
+	refs := referenceResolver(h.cfg.GetWorkDir(), h.cfg.GetResearchDir())
+	commits, err := git.NewManager().LoopCommits(refs.Root)
+	if err != nil {
+		logger.Debug("No git history loaded, tracing without commits", logging.String("error", err.Error()))
+	}
+
+	matrix, err := traceability.Build(planDir, refs, status, commits)
+	if err != nil {
+		return nil, fmt.Errorf("读取计划目录失败: %w", err)
+	}
+
+	out := h.out
+	if opts.Output != "" {
+		f, err := os.Create(opts.Output)
+		if err != nil {
+			return nil, fmt.Errorf("创建输出文件失败: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	switch opts.Format {
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(matrix)
+	case "csv":
+		err = writeTraceCSV(out, matrix)
+	default:
+		err = writeTraceText(out, matrix)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("Traceability matrix rendered",
+		logging.String("format", opts.Format),
+		logging.Int("research", len(matrix.Research)),
+		logging.Int("modules", len(matrix.Modules)),
+		logging.Int("dangling", len(matrix.Dangling)),
+	)
+
+	result := &TraceResult{Matrix: matrix, Options: opts}
+	if len(matrix.Dangling) > 0 {
+		return result, fmt.Errorf("发现 %d 个无效引用", len(matrix.Dangling))
+	}
+	return result, nil
+}
+
+// parseOptions parses trace command arguments.
+func (h *TraceHandler) parseOptions(args []string) (TraceOptions, error) {
+	opts := TraceOptions{Format: "text"}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		name, value, hasValue := strings.Cut(arg, "=")
+		needValue := func() (string, error) {
+			if hasValue {
+				return value, nil
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("%s 需要一个参数", name)
+			}
+			i++
+			return args[i], nil
+		}
+
+		switch name {
+		case "--format", "-f":
+			v, err := needValue()
+			if err != nil {
+				return opts, err
+			}
+			opts.Format = v
+		case "--output", "-o":
+			v, err := needValue()
+			if err != nil {
+				return opts, err
+			}
+			opts.Output = v
+		default:
+			return opts, fmt.Errorf("未知参数: %s", arg)
+		}
+	}
+
+	switch opts.Format {
+	case "text", "json", "csv":
+	default:
+		return opts, fmt.Errorf("不支持的格式: %s (可选: text, json, csv)", opts.Format)
+	}
+
+	return opts, nil
+}
+
+// referenceResolver resolves plan references against the project root,
+// the directory containing the Morty work directory.
+func referenceResolver(workDir, researchDir string) plan.ReferenceResolver {
+	root := filepath.Dir(workDir)
+	if abs, err := filepath.Abs(root); err == nil {
+		root = abs
+	}
+	if abs, err := filepath.Abs(researchDir); err == nil {
+		researchDir = abs
+	}
+	return plan.ReferenceResolver{Root: root, ResearchDir: researchDir}
+}
+
+// traceSectionNames are the headings of the reference lists.
+var traceSectionNames = map[plan.Section]string{
+	plan.SectionResearch:   "对应 Research",
+	plan.SectionReferences: "现有实现参考",
+}
+
+// writeTraceText renders the matrix as a table followed by the problems found.
+func writeTraceText(w io.Writer, m *traceability.Matrix) error {
+	fmt.Fprintln(w, "📚 研究文档 → 模块 → Job → 提交")
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RESEARCH\tMODULE\tJOB\tSTATUS\tCOMMIT")
+	for _, row := range m.Rows() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			orDash(row.Research), orDash(row.Module), orDash(row.Job), orDash(string(row.Status)), orDash(row.Commit))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintln(w)
+
+	if len(m.Dangling) > 0 {
+		fmt.Fprintf(w, "❌ 无效引用 (%d):\n", len(m.Dangling))
+		for _, ref := range m.Dangling {
+			fmt.Fprintf(w, "  %s: %s (%s)\n", ref.File, ref.Path, traceSectionNames[ref.Section])
+		}
+	}
+	if len(m.Unused) > 0 {
+		fmt.Fprintf(w, "⚠️  未被任何 Plan 引用的研究文档 (%d):\n", len(m.Unused))
+		for _, doc := range m.Unused {
+			fmt.Fprintf(w, "  %s\n", doc)
+		}
+	}
+	if len(m.Dangling) == 0 && len(m.Unused) == 0 {
+		fmt.Fprintln(w, "✅ 所有引用均有效，所有研究文档都已被引用")
+	}
+	return nil
+}
+
+// writeTraceCSV renders the matrix rows as CSV.
+func writeTraceCSV(w io.Writer, m *traceability.Matrix) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"research", "module", "job", "status", "commit"})
+	for _, row := range m.Rows() {
+		cw.Write([]string{row.Research, row.Module, row.Job, string(row.Status), row.Commit})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// orDash returns s, or "-" if it is empty.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
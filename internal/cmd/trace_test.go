@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/morty/morty/internal/traceability"
+)
+
+// newTraceTestProject creates a project whose cache plan cites a research
+// document and an implementation file, and returns its config.
+func newTraceTestProject(t *testing.T) *mockConfig {
+	t.Helper()
+	root := t.TempDir()
+	cfg := &mockConfig{values: map[string]interface{}{}}
+	cfg.SetWorkDir(filepath.Join(root, ".morty"))
+
+	os.MkdirAll(cfg.GetPlanDir(), 0755)
+	writeResearch(t, cfg.GetResearchDir())
+	os.MkdirAll(filepath.Join(root, "internal", "cache"), 0755)
+	os.WriteFile(filepath.Join(root, "internal", "cache", "lru.go"), []byte("package cache\n"), 0644)
+
+	content := strings.Replace(fmt.Sprintf(doctorPlan, "cache"), "**对应 Research**: 无",
+		"**对应 Research**:\n- `.morty/research/cache.md` - 缓存调研", 1)
+	content = strings.Replace(content, "**现有实现参考**: 无",
+		"**现有实现参考**:\n- `internal/cache/lru.go` - LRU", 1)
+	os.WriteFile(filepath.Join(cfg.GetPlanDir(), "cache.md"), []byte(content), 0644)
+	return cfg
+}
+
+func TestTraceHandler_Execute(t *testing.T) {
+	cfg := newTraceTestProject(t)
+	handler := NewTraceHandler(cfg, &mockLogger{})
+	var out bytes.Buffer
+	handler.SetOutput(&out)
+
+	result, err := handler.Execute(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Execute() error: %v", err)
+	}
+	if got := result.Matrix.Unused; len(got) != 1 || got[0] != ".morty/research/auth.md" {
+		t.Errorf("Unused = %q", got)
+	}
+	for _, want := range []string{".morty/research/cache.md  cache", "内存缓存", "PENDING", "未被任何 Plan 引用的研究文档 (1)"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in:\n%s", want, out.String())
+		}
+	}
+
+	// A renamed research document is a dangling reference
+	os.Rename(filepath.Join(cfg.GetResearchDir(), "cache.md"), filepath.Join(cfg.GetResearchDir(), "caching.md"))
+	out.Reset()
+	result, err = handler.Execute(context.Background(), []string{"--format", "json"})
+	if err == nil || !strings.Contains(err.Error(), "1 个无效引用") {
+		t.Fatalf("Expected a dangling reference error, got %v", err)
+	}
+	var decoded traceability.Matrix
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil || len(decoded.Dangling) != 1 ||
+		decoded.Dangling[0].Path != ".morty/research/cache.md" {
+		t.Errorf("Unexpected JSON output %v:\n%s", err, out.String())
+	}
+
+	for _, args := range [][]string{{"--format", "dot"}, {"--output"}, {"--all"}} {
+		if _, err := handler.Execute(context.Background(), args); err == nil {
+			t.Errorf("Expected an error for %q", args)
+		}
+	}
+}
+
+func TestPlanHandler_ValidateReferences(t *testing.T) {
+	cfg := newTraceTestProject(t)
+	os.WriteFile(filepath.Join(cfg.GetPlanDir(), "e2e_test.md"), []byte(fmt.Sprintf(doctorPlan, "e2e_test")), 0644)
+	handler := NewPlanHandler(cfg, &mockLogger{}, nil)
+
+	if result, err := handler.Validate(context.Background(), nil); err != nil || !result.Success {
+		t.Fatalf("Expected existing references to pass: %v\n%s", err, result.Message)
+	}
+
+	os.Remove(filepath.Join(cfg.GetWorkDir(), "..", "internal", "cache", "lru.go"))
+	result, _ := handler.Validate(context.Background(), nil)
+	if result.Success || !strings.Contains(result.Message, "E013") {
+		t.Errorf("Expected E013 for a missing implementation file:\n%s", result.Message)
+	}
+}
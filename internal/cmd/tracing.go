@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/morty/morty/internal/config"
+	"github.com/morty/morty/internal/tracing"
+)
+
+// newTracer creates the tracer configured by the tracing settings. It
+// returns nil, which disables tracing, when tracing is off.
+func newTracer(cfg config.Manager) (*tracing.Tracer, error) {
+	if cfg == nil || !cfg.GetBool("tracing.enabled") {
+		return nil, nil
+	}
+
+	serviceName := cfg.GetString("tracing.service_name", config.DefaultTracingServiceName)
+	switch exporter := cfg.GetString("tracing.exporter", config.DefaultTracingExporter); exporter {
+	case "file":
+		path := cfg.GetString("tracing.file", config.DefaultTracingFile)
+		return tracing.NewTracer(serviceName, tracing.NewFileExporter(path)), nil
+	case "otlp_http":
+		endpoint := cfg.GetString("tracing.endpoint", config.DefaultTracingEndpoint)
+		return tracing.NewTracer(serviceName, tracing.NewHTTPExporter(endpoint)), nil
+	default:
+		return nil, fmt.Errorf("不支持的 tracing.exporter: %s (可选: file, otlp_http)", exporter)
+	}
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestDoingHandler_Tracing tests that a traced run exports its doing.run span.
+func TestDoingHandler_Tracing(t *testing.T) {
+	handler, _ := newContinueTestHandler(t, true)
+	traceFile := filepath.Join(t.TempDir(), "traces.jsonl")
+	cfg := handler.cfg.(*mockConfig)
+	cfg.values["tracing.enabled"] = true
+	cfg.values["tracing.file"] = traceFile
+
+	handler.Execute(context.Background(), nil)
+
+	data, err := os.ReadFile(traceFile)
+	if err != nil {
+		t.Fatalf("Trace file not written: %v", err)
+	}
+	for _, want := range []string{`"name":"doing.run"`, `"key":"morty.exit_code"`, `"code":2`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected %s in trace:\n%s", want, data)
+		}
+	}
+}
+
+func TestNewTracer(t *testing.T) {
+	if tracer, err := newTracer(&mockConfig{values: map[string]interface{}{}}); tracer != nil || err != nil {
+		t.Errorf("Expected tracing disabled by default, got %v %v", tracer, err)
+	}
+
+	cfg := &mockConfig{values: map[string]interface{}{"tracing.enabled": true, "tracing.exporter": "otlp_http"}}
+	if tracer, err := newTracer(cfg); tracer == nil || err != nil {
+		t.Errorf("Expected otlp_http tracer, got %v %v", tracer, err)
+	}
+
+	cfg.values["tracing.exporter"] = "zipkin"
+	if _, err := newTracer(cfg); err == nil {
+		t.Error("Expected error for unknown exporter")
+	}
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/morty/morty/internal/config"
+	"github.com/morty/morty/internal/logging"
+	"github.com/morty/morty/internal/transcript"
+)
+
+// TranscriptOptions holds the parsed transcript command options.
+type TranscriptOptions struct {
+	Module  string // Module part of the positional module/job
+	Job     string // Job part of the positional module/job
+	Attempt int    // --attempt N: the Nth run of the job (0 = latest)
+	Format  string // --format md|html
+	Output  string // --output: file to write instead of stdout
+}
+
+// TranscriptResult represents the result of the transcript command.
+type TranscriptResult struct {
+	Source  string // Conversation file the transcript was built from
+	Attempt int    // Run of the job the transcript shows
+	Output  string // File written, empty when printed to stdout
+}
+
+// TranscriptHandler handles the transcript command.
+type TranscriptHandler struct {
+	cfg    config.Manager
+	logger logging.Logger
+	out    io.Writer
+}
+
+// NewTranscriptHandler creates a new TranscriptHandler instance.
+func NewTranscriptHandler(cfg config.Manager, logger logging.Logger) *TranscriptHandler {
+	return &TranscriptHandler{
+		cfg:    cfg,
+		logger: logger,
+		out:    os.Stdout,
+	}
+}
+
+// SetOutput sets the writer transcripts are printed to.
+func (h *TranscriptHandler) SetOutput(w io.Writer) {
+	h.out = w
+}
+
+// Execute renders the conversation of a job run saved by the executor as
+// Markdown or as a self-contained HTML page.
+func (h *TranscriptHandler) Execute(ctx context.Context, args []string) (*TranscriptResult, error) {
+	logger := h.logger.WithContext(ctx)
+
+	opts, err := parseTranscriptOptions(args)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := transcript.Find(filepath.Join(h.cfg.GetWorkDir(), "logs"), opts.Module, opts.Job)
+	if err != nil {
+		return nil, fmt.Errorf("读取对话记录失败: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("没有找到 %s/%s 的对话记录, 请先运行 morty doing (需要 AI CLI 输出 JSON 事件流)", opts.Module, opts.Job)
+	}
+	attempt := opts.Attempt
+	if attempt == 0 {
+		attempt = len(files)
+	}
+	if attempt > len(files) {
+		return nil, fmt.Errorf("%s/%s 只有 %d 次运行的对话记录", opts.Module, opts.Job, len(files))
+	}
+	file := files[attempt-1]
+
+	t, err := transcript.Load(file)
+	if err != nil {
+		return nil, fmt.Errorf("解析对话记录失败: %w", err)
+	}
+	t.Module, t.Job, t.Attempt = opts.Module, opts.Job, attempt
+
+	var buf bytes.Buffer
+	if opts.Format == "html" {
+		err = transcript.WriteHTML(&buf, t)
+	} else {
+		err = transcript.WriteMarkdown(&buf, t)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("生成对话记录失败: %w", err)
+	}
+
+	result := &TranscriptResult{Source: file.Path, Attempt: attempt, Output: opts.Output}
+	if opts.Output == "" {
+		_, err = h.out.Write(buf.Bytes())
+		return result, err
+	}
+	if err := os.MkdirAll(filepath.Dir(opts.Output), 0755); err != nil {
+		return nil, fmt.Errorf("创建输出目录失败: %w", err)
+	}
+	if err := os.WriteFile(opts.Output, buf.Bytes(), 0644); err != nil {
+		return nil, fmt.Errorf("写入对话记录失败: %w", err)
+	}
+
+	logger.Info("Transcript written",
+		logging.String("source", file.Path),
+		logging.Int("attempt", attempt),
+		logging.String("output", opts.Output),
+	)
+	fmt.Fprintf(h.out, "对话记录已写入: %s\n", opts.Output)
+	return result, nil
+}
+
+// parseTranscriptOptions parses transcript command arguments.
+func parseTranscriptOptions(args []string) (TranscriptOptions, error) {
+	opts := TranscriptOptions{Format: "md"}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		name, value, hasValue := strings.Cut(arg, "=")
+		needValue := func() (string, error) {
+			if hasValue {
+				return value, nil
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("%s 需要一个参数", name)
+			}
+			i++
+			return args[i], nil
+		}
+
+		switch name {
+		case "--attempt", "-a":
+			v, err := needValue()
+			if err != nil {
+				return opts, err
+			}
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				return opts, fmt.Errorf("--attempt 需要一个正整数: %s", v)
+			}
+			opts.Attempt = n
+		case "--format", "-f":
+			v, err := needValue()
+			if err != nil {
+				return opts, err
+			}
+			switch strings.ToLower(v) {
+			case "md", "markdown":
+				opts.Format = "md"
+			case "html":
+				opts.Format = "html"
+			default:
+				return opts, fmt.Errorf("不支持的格式: %s (可选: md, html)", v)
+			}
+		case "--output", "-o":
+			v, err := needValue()
+			if err != nil {
+				return opts, err
+			}
+			opts.Output = v
+		default:
+			if strings.HasPrefix(arg, "-") {
+				return opts, fmt.Errorf("未知参数: %s", arg)
+			}
+			if opts.Module != "" {
+				return opts, fmt.Errorf("只能指定一个 module/job: %s", arg)
+			}
+			opts.Module, opts.Job, _ = strings.Cut(arg, "/")
+		}
+	}
+
+	if opts.Module == "" || opts.Job == "" {
+		return opts, fmt.Errorf("需要指定 module/job")
+	}
+	return opts, nil
+}
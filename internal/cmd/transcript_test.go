@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const transcriptStream = `[
+  {"type":"assistant","message":{"id":"msg_1","role":"assistant","content":[{"type":"text","text":"Attempt ATTEMPT"}],"usage":{"input_tokens":10,"output_tokens":5}}},
+  {"type":"result","subtype":"success","result":"done","total_cost_usd":0.5,"usage":{"input_tokens":10,"output_tokens":5}}
+]`
+
+func TestParseTranscriptOptions(t *testing.T) {
+	opts, err := parseTranscriptOptions([]string{"core/types", "--attempt", "2", "--format=html", "-o", "out.html"})
+	if err != nil {
+		t.Fatalf("parseTranscriptOptions failed: %v", err)
+	}
+	if opts.Module != "core" || opts.Job != "types" || opts.Attempt != 2 || opts.Format != "html" || opts.Output != "out.html" {
+		t.Errorf("Unexpected options: %+v", opts)
+	}
+
+	for _, args := range [][]string{
+		{},
+		{"core"},
+		{"core/types", "--format", "pdf"},
+		{"core/types", "--attempt", "0"},
+		{"core/types", "api/routes"},
+		{"core/types", "--unknown"},
+	} {
+		if _, err := parseTranscriptOptions(args); err == nil {
+			t.Errorf("Expected error for %v", args)
+		}
+	}
+}
+
+func TestTranscriptHandler_Execute(t *testing.T) {
+	workDir := filepath.Join(setupTestDir(t), ".morty")
+	logsDir := filepath.Join(workDir, "logs")
+	if err := os.MkdirAll(logsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, attempt := range map[string]string{
+		"core_types_20261018_090000.json": "one",
+		"core_types_20261018_100000.json": "two",
+	} {
+		data := strings.Replace(transcriptStream, "ATTEMPT", attempt, 1)
+		if err := os.WriteFile(filepath.Join(logsDir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	handler := NewTranscriptHandler(&mockConfig{workDir: workDir}, &mockLogger{})
+	var out bytes.Buffer
+	handler.SetOutput(&out)
+
+	result, err := handler.Execute(context.Background(), []string{"core/types"})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if result.Attempt != 2 || !strings.Contains(out.String(), "Attempt two") || !strings.Contains(out.String(), "| Cost | $0.5000 |") {
+		t.Errorf("Expected latest attempt as Markdown, got attempt %d:\n%s", result.Attempt, out.String())
+	}
+
+	output := filepath.Join(t.TempDir(), "review", "transcript.html")
+	result, err = handler.Execute(context.Background(), []string{"core/types", "--attempt", "1", "--format", "html", "--output", output})
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Transcript not written: %v", err)
+	}
+	if result.Attempt != 1 || !strings.Contains(string(data), "<!DOCTYPE html>") || !strings.Contains(string(data), "Attempt one") {
+		t.Errorf("Unexpected HTML transcript:\n%s", data)
+	}
+
+	if _, err := handler.Execute(context.Background(), []string{"core/types", "--attempt", "3"}); err == nil {
+		t.Error("Expected error for missing attempt")
+	}
+	if _, err := handler.Execute(context.Background(), []string{"core/other"}); err == nil {
+		t.Error("Expected error for job without transcripts")
+	}
+}
@@ -35,8 +35,28 @@ type Config struct {
 
 	// Prompts contains prompt file path configuration.
 	Prompts PromptsConfig `json:"prompts"`
+
+	// Research contains research knowledge base configuration.
+	Research ResearchConfig `json:"research"`
+
+	// Tracing contains trace export configuration.
+	Tracing TracingConfig `json:"tracing"`
+
+	// Metrics contains Prometheus metrics configuration.
+	Metrics MetricsConfig `json:"metrics"`
+
+	// Redaction contains secret redaction configuration.
+	Redaction RedactionConfig `json:"redaction"`
+
+	// Profiles are named partial configurations, one of which may be
+	// selected with --profile or MORTY_PROFILE.
+	Profiles map[string]Profile `json:"profiles,omitempty"`
 }
 
+// Profile is a partial configuration with the layout of a config file. The
+// selected profile overlays the user and project configuration.
+type Profile map[string]interface{}
+
 // AICliConfig contains AI CLI configuration settings.
 // This defines how Morty interacts with the AI CLI tool.
 type AICliConfig struct {
@@ -76,6 +96,14 @@ type ExecutionConfig struct {
 
 	// ParallelJobs is the number of parallel jobs to run (reserved for future).
 	ParallelJobs int `json:"parallel_jobs"`
+
+	// DigestBudget is the number of tokens of completed job digests added
+	// to a job prompt.
+	DigestBudget int `json:"digest_budget"`
+
+	// FreshSession starts every retry of a job in a new AI CLI session
+	// instead of resuming the session of the failed attempt.
+	FreshSession bool `json:"fresh_session"`
 }
 
 // LoggingConfig contains logging configuration settings.
@@ -137,6 +165,41 @@ type GitConfig struct {
 
 	// RequireCleanWorktree requires a clean working tree before operations.
 	RequireCleanWorktree bool `json:"require_clean_worktree"`
+
+	// Scan contains pre-commit scanning configuration.
+	Scan GitScanConfig `json:"scan"`
+}
+
+// GitScanConfig contains pre-commit scanning configuration.
+// Staged files are checked for credentials, size and binary content
+// before an auto-commit is created.
+type GitScanConfig struct {
+	// Enabled enables scanning before auto-commit.
+	Enabled bool `json:"enabled"`
+
+	// Action is applied to offending files ("block" or "unstage").
+	Action string `json:"action"`
+
+	// MaxFileSize is the largest file that may be committed (e.g., "5MB").
+	MaxFileSize string `json:"max_file_size"`
+
+	// BlockBinary reports binary files as findings.
+	BlockBinary bool `json:"block_binary"`
+
+	// AllowPaths are glob patterns of paths excluded from scanning.
+	AllowPaths []string `json:"allow_paths,omitempty"`
+
+	// Rules are user-defined content rules added to the built-in ones.
+	Rules []GitScanRule `json:"rules,omitempty"`
+}
+
+// GitScanRule is a user-defined scanning rule.
+type GitScanRule struct {
+	// Name identifies the rule in findings.
+	Name string `json:"name"`
+
+	// Pattern is a Go regular expression matched against each line.
+	Pattern string `json:"pattern"`
 }
 
 // PlanConfig contains plan management configuration.
@@ -150,6 +213,18 @@ type PlanConfig struct {
 
 	// AutoValidate enables automatic plan format validation.
 	AutoValidate bool `json:"auto_validate"`
+
+	// Language selects the heading language of generated plans and the
+	// localized prompt files (zh or en). Both languages are always parsed.
+	Language string `json:"language"`
+
+	// RepairAttempts is the number of rounds the plan repair loop asks the
+	// AI CLI to fix validation errors before giving up.
+	RepairAttempts int `json:"repair_attempts"`
+
+	// HeadingAliases maps a plan section key (e.g. "debug_logs") to extra
+	// headings accepted for that section.
+	HeadingAliases map[string][]string `json:"heading_aliases,omitempty"`
 }
 
 // PromptsConfig contains prompt file path configuration.
@@ -168,6 +243,82 @@ type PromptsConfig struct {
 	Doing string `json:"doing"`
 }
 
+// TracingConfig contains trace export configuration.
+// Spans of doing runs, job attempts, AI CLI calls and git commits are
+// exported as OTLP/JSON.
+type TracingConfig struct {
+	// Enabled enables tracing.
+	Enabled bool `json:"enabled"`
+
+	// Exporter selects where spans are sent ("file" or "otlp_http").
+	Exporter string `json:"exporter"`
+
+	// File is the trace file used by the file exporter.
+	File string `json:"file"`
+
+	// Endpoint is the OTLP HTTP traces endpoint used by the otlp_http exporter.
+	Endpoint string `json:"endpoint"`
+
+	// ServiceName is the service.name resource attribute.
+	ServiceName string `json:"service_name"`
+}
+
+// ResearchConfig contains research knowledge base configuration.
+// Research documents are split at headings and searched with BM25 to pick
+// the sections relevant to a prompt.
+type ResearchConfig struct {
+	// TopK is the number of research sections selected for a plan module
+	// or doing job.
+	TopK int `json:"top_k"`
+
+	// MaxChars is the research size up to which plan prompts include every
+	// research document; larger research is searched instead.
+	MaxChars int `json:"max_chars"`
+}
+
+// MetricsConfig contains Prometheus metrics configuration.
+// Job, AI CLI and commit metrics are served on /metrics during a doing run
+// and written as a node-exporter textfile when the run ends.
+type MetricsConfig struct {
+	// Enabled enables metrics.
+	Enabled bool `json:"enabled"`
+
+	// Listen is the address /metrics is served on during a run (empty disables it).
+	Listen string `json:"listen"`
+
+	// Textfile is the node-exporter textfile written after a run (empty disables it).
+	Textfile string `json:"textfile"`
+}
+
+// RedactionConfig contains secret redaction configuration.
+// Secrets are masked in logs, job logs, conversation logs and transcripts
+// before they reach disk or the terminal.
+type RedactionConfig struct {
+	// Enabled enables redaction.
+	Enabled bool `json:"enabled"`
+
+	// Patterns are user-defined rules added to the built-in ones. Only the
+	// group named "secret" is masked if the pattern has one.
+	Patterns []RedactionPattern `json:"patterns,omitempty"`
+
+	// EnvVars are names or glob patterns (e.g., "*_TOKEN") of environment
+	// variables whose values are masked, in addition to the built-in ones.
+	EnvVars []string `json:"env_vars,omitempty"`
+
+	// TestMode feeds a canary secret through a doing run and fails the run
+	// if it, or any other secret, is found in the files it wrote.
+	TestMode bool `json:"test_mode"`
+}
+
+// RedactionPattern is a user-defined redaction rule.
+type RedactionPattern struct {
+	// Name identifies the rule in the mask ("[REDACTED:name]").
+	Name string `json:"name"`
+
+	// Pattern is a Go regular expression.
+	Pattern string `json:"pattern"`
+}
+
 // DefaultConfig returns a Config with all default values set.
 // This represents Level 1 of the configuration hierarchy.
 func DefaultConfig() *Config {
@@ -187,6 +338,8 @@ func DefaultConfig() *Config {
 			AutoGitCommit:   DefaultExecutionAutoGitCommit,
 			ContinueOnError: DefaultExecutionContinueOnError,
 			ParallelJobs:    DefaultExecutionParallelJobs,
+			DigestBudget:    DefaultExecutionDigestBudget,
+			FreshSession:    DefaultExecutionFreshSession,
 		},
 		Logging: LoggingConfig{
 			Level:  DefaultLoggingLevel,
@@ -209,11 +362,19 @@ func DefaultConfig() *Config {
 			CommitPrefix:         DefaultGitCommitPrefix,
 			AutoCommit:           DefaultGitAutoCommit,
 			RequireCleanWorktree: DefaultGitRequireCleanWorktree,
+			Scan: GitScanConfig{
+				Enabled:     DefaultGitScanEnabled,
+				Action:      DefaultGitScanAction,
+				MaxFileSize: DefaultGitScanMaxFileSize,
+				BlockBinary: DefaultGitScanBlockBinary,
+			},
 		},
 		Plan: PlanConfig{
-			Dir:           DefaultPlanDir,
-			FileExtension: DefaultPlanFileExtension,
-			AutoValidate:  DefaultPlanAutoValidate,
+			Dir:            DefaultPlanDir,
+			FileExtension:  DefaultPlanFileExtension,
+			AutoValidate:   DefaultPlanAutoValidate,
+			Language:       DefaultPlanLanguage,
+			RepairAttempts: DefaultPlanRepairAttempts,
 		},
 		Prompts: PromptsConfig{
 			Dir:      DefaultPromptsDir,
@@ -221,6 +382,26 @@ func DefaultConfig() *Config {
 			Plan:     DefaultPromptsPlan,
 			Doing:    DefaultPromptsDoing,
 		},
+		Tracing: TracingConfig{
+			Enabled:     DefaultTracingEnabled,
+			Exporter:    DefaultTracingExporter,
+			File:        DefaultTracingFile,
+			Endpoint:    DefaultTracingEndpoint,
+			ServiceName: DefaultTracingServiceName,
+		},
+		Research: ResearchConfig{
+			TopK:     DefaultResearchTopK,
+			MaxChars: DefaultResearchMaxChars,
+		},
+		Metrics: MetricsConfig{
+			Enabled:  DefaultMetricsEnabled,
+			Listen:   DefaultMetricsListen,
+			Textfile: DefaultMetricsTextfile,
+		},
+		Redaction: RedactionConfig{
+			Enabled:  DefaultRedactionEnabled,
+			TestMode: DefaultRedactionTestMode,
+		},
 	}
 }
 
@@ -244,6 +425,16 @@ func (c *AICliConfig) MaxTimeoutDuration(defaultVal time.Duration) time.Duration
 	return d
 }
 
+// MaxFileSizeBytes returns the MaxFileSize in bytes.
+// Returns the provided default if parsing fails.
+func (c *GitScanConfig) MaxFileSizeBytes(defaultVal int64) int64 {
+	n, err := parseSizeString(c.MaxFileSize)
+	if err != nil {
+		return defaultVal
+	}
+	return n
+}
+
 // SaveIntervalDuration returns the SaveInterval as a time.Duration.
 // Returns the provided default if parsing fails.
 func (c *StateConfig) SaveIntervalDuration(defaultVal time.Duration) time.Duration {
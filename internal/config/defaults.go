@@ -52,6 +52,12 @@ const (
 
 	// DefaultExecutionParallelJobs is the default number of parallel jobs.
 	DefaultExecutionParallelJobs = 1
+
+	// DefaultExecutionDigestBudget is the default token budget of job digests in a prompt.
+	DefaultExecutionDigestBudget = 2000
+
+	// DefaultExecutionFreshSession disables fresh sessions on retry by default.
+	DefaultExecutionFreshSession = false
 )
 
 // Logging default constants.
@@ -103,6 +109,18 @@ const (
 
 	// DefaultGitRequireCleanWorktree disables clean worktree requirement by default.
 	DefaultGitRequireCleanWorktree = false
+
+	// DefaultGitScanEnabled enables pre-commit scanning by default.
+	DefaultGitScanEnabled = true
+
+	// DefaultGitScanAction is the default action for offending files.
+	DefaultGitScanAction = "block"
+
+	// DefaultGitScanMaxFileSize is the default maximum committed file size.
+	DefaultGitScanMaxFileSize = "10MB"
+
+	// DefaultGitScanBlockBinary disables binary blocking by default.
+	DefaultGitScanBlockBinary = false
 )
 
 // Plan default constants.
@@ -115,6 +133,12 @@ const (
 
 	// DefaultPlanAutoValidate enables auto-validation by default.
 	DefaultPlanAutoValidate = true
+
+	// DefaultPlanLanguage is the default plan heading language.
+	DefaultPlanLanguage = "zh"
+
+	// DefaultPlanRepairAttempts is the default number of plan repair rounds.
+	DefaultPlanRepairAttempts = 3
 )
 
 // Prompts default constants.
@@ -132,6 +156,55 @@ const (
 	DefaultPromptsDoing = "prompts/doing.md"
 )
 
+// Tracing default constants.
+const (
+	// DefaultTracingEnabled disables tracing by default.
+	DefaultTracingEnabled = false
+
+	// DefaultTracingExporter is the default span exporter.
+	DefaultTracingExporter = "file"
+
+	// DefaultTracingFile is the default OTLP/JSON trace file.
+	DefaultTracingFile = ".morty/traces/traces.jsonl"
+
+	// DefaultTracingEndpoint is the default OTLP HTTP traces endpoint.
+	DefaultTracingEndpoint = "http://localhost:4318/v1/traces"
+
+	// DefaultTracingServiceName is the default service.name of exported spans.
+	DefaultTracingServiceName = "morty"
+)
+
+// Research default constants.
+const (
+	// DefaultResearchTopK is the default number of selected research sections.
+	DefaultResearchTopK = 8
+
+	// DefaultResearchMaxChars is the default research size plan prompts
+	// include in full.
+	DefaultResearchMaxChars = 40000
+)
+
+// Metrics default constants.
+const (
+	// DefaultMetricsEnabled disables metrics by default.
+	DefaultMetricsEnabled = false
+
+	// DefaultMetricsListen disables the /metrics endpoint by default.
+	DefaultMetricsListen = ""
+
+	// DefaultMetricsTextfile is the default node-exporter textfile.
+	DefaultMetricsTextfile = ".morty/metrics/morty.prom"
+)
+
+// Redaction default constants.
+const (
+	// DefaultRedactionEnabled enables secret redaction by default.
+	DefaultRedactionEnabled = true
+
+	// DefaultRedactionTestMode disables the redaction test mode by default.
+	DefaultRedactionTestMode = false
+)
+
 // Environment variable names.
 const (
 	// EnvMortyHome is the environment variable for Morty home directory.
@@ -145,6 +218,9 @@ const (
 
 	// EnvMortyDebug is the environment variable for debug mode.
 	EnvMortyDebug = "MORTY_DEBUG"
+
+	// EnvMortyProfile is the environment variable selecting a config profile.
+	EnvMortyProfile = "MORTY_PROFILE"
 )
 
 // Path constants.
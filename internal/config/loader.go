@@ -2,6 +2,7 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -17,6 +18,15 @@ import (
 type Loader struct {
 	config     *Config
 	configFile string
+
+	// userConfigFile is the user config file of LoadWithMerge
+	userConfigFile string
+	// profile is the profile selected with SetProfile
+	profile string
+	// activeProfile is the profile applied by LoadWithMerge
+	activeProfile string
+	// origins maps the keys set by LoadWithMerge layers to their origin
+	origins map[string]Origin
 }
 
 // NewLoader creates a new configuration loader with default values.
@@ -75,35 +85,45 @@ func (l *Loader) Load(path string) error {
 }
 
 // LoadWithMerge loads and merges configuration from multiple sources.
-// Loading order: defaults → user config → project config → environment variables.
-// Later sources override earlier ones.
+// Loading order: defaults → user config → project config → profile →
+// environment variables.
+// Later sources override earlier ones. Each config file is decoded onto the
+// result of the previous layers, so it only overrides the keys it sets; the
+// origin of every key is recorded.
 func (l *Loader) LoadWithMerge(userConfigPath string) error {
 	// Start with defaults
 	l.config = DefaultConfig()
+	l.origins = nil
+	l.userConfigFile = ""
+	l.activeProfile = ""
 
-	// Load user config if exists (optional)
+	// Load user config if exists (optional). A config file that does not
+	// parse is skipped, but the error is reported once loading finishes.
+	var loadErr error
 	if userConfigPath != "" {
 		expandedPath, _ := expandPath(userConfigPath)
-		if _, err := os.Stat(expandedPath); err == nil {
-			userConfig, err := l.loadConfigFromFile(expandedPath)
-			if err == nil {
-				l.config = mergeConfigs(l.config, userConfig)
-			}
-		}
+		l.userConfigFile = expandedPath
+		loadErr = l.mergeFile(expandedPath, LayerUser)
 	}
 
-	// Load project config if exists (optional)
-	projectConfigPath := filepath.Join(l.GetWorkDir(), "settings.json")
-	if _, err := os.Stat(projectConfigPath); err == nil {
-		projectConfig, err := l.loadConfigFromFile(projectConfigPath)
-		if err == nil {
-			l.config = mergeConfigs(l.config, projectConfig)
-		}
+	// Load project config if exists (optional); this is the deprecated
+	// .morty/config.json when there is no .morty/settings.json
+	if err := l.mergeFile(l.ProjectConfigFile(), LayerProject); err != nil && loadErr == nil {
+		loadErr = err
+	}
+
+	// Overlay the selected profile
+	if err := l.applyProfile(l.SelectedProfile()); err != nil {
+		return err
 	}
 
 	// Apply environment variables (highest priority)
 	l.applyEnvironmentVariables()
 
+	if loadErr != nil {
+		return loadErr
+	}
+
 	// Validate the final configuration
 	if err := l.Validate(); err != nil {
 		return fmt.Errorf("config validation failed: %w", err)
@@ -112,6 +132,26 @@ func (l *Loader) LoadWithMerge(userConfigPath string) error {
 	return nil
 }
 
+// mergeFile decodes a config file onto the current configuration. Missing
+// and empty files are skipped; an unparsable file is skipped and returned as
+// an error.
+func (l *Loader) mergeFile(path string, layer Layer) error {
+	data, err := os.ReadFile(path)
+	if err != nil || len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	merged := *l.config
+	merged.Profiles = nil
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return fmt.Errorf("failed to parse JSON config from %s: %w", path, err)
+	}
+	merged.Profiles = mergeProfiles(l.config.Profiles, merged.Profiles)
+	l.config = &merged
+	l.configFile = path
+	l.setOrigins(path, layer)
+	return nil
+}
+
 // LoadWithDefaults loads configuration from file if it exists,
 // otherwise uses default configuration.
 func (l *Loader) LoadWithDefaults(path string) error {
@@ -367,6 +407,7 @@ func (l *Loader) applyEnvironmentVariables() {
 	// MORTY_LOG_LEVEL
 	if level := os.Getenv(EnvMortyLogLevel); level != "" {
 		l.config.Logging.Level = level
+		l.setOrigin("logging.level", Origin{Layer: LayerEnv, Source: EnvMortyLogLevel})
 	}
 
 	// MORTY_DEBUG
@@ -375,6 +416,7 @@ func (l *Loader) applyEnvironmentVariables() {
 			// Map debug to log level
 			if b {
 				l.config.Logging.Level = "debug"
+				l.setOrigin("logging.level", Origin{Layer: LayerEnv, Source: EnvMortyDebug})
 			}
 		}
 	}
@@ -385,6 +427,9 @@ func (l *Loader) applyEnvironmentVariables() {
 		l.config.State.File = filepath.Join(home, "status.json")
 		l.config.Logging.File.Path = filepath.Join(home, "doing", "logs", "morty.log")
 		l.config.Plan.Dir = filepath.Join(home, "plan")
+		for _, key := range []string{"state.file", "logging.file.path", "plan.dir"} {
+			l.setOrigin(key, Origin{Layer: LayerEnv, Source: EnvMortyHome})
+		}
 	}
 
 	// MORTY_CONFIG - specific config file path
@@ -465,6 +510,10 @@ func mergeConfigs(dst, src *Config) *Config {
 	if src.Execution.ParallelJobs != 0 {
 		result.Execution.ParallelJobs = src.Execution.ParallelJobs
 	}
+	if src.Execution.DigestBudget > 0 {
+		result.Execution.DigestBudget = src.Execution.DigestBudget
+	}
+	result.Execution.FreshSession = src.Execution.FreshSession
 
 	// Merge Logging
 	if src.Logging.Level != "" {
@@ -505,6 +554,20 @@ func mergeConfigs(dst, src *Config) *Config {
 	}
 	result.Git.AutoCommit = src.Git.AutoCommit
 	result.Git.RequireCleanWorktree = src.Git.RequireCleanWorktree
+	result.Git.Scan.Enabled = src.Git.Scan.Enabled
+	if src.Git.Scan.Action != "" {
+		result.Git.Scan.Action = src.Git.Scan.Action
+	}
+	if src.Git.Scan.MaxFileSize != "" {
+		result.Git.Scan.MaxFileSize = src.Git.Scan.MaxFileSize
+	}
+	result.Git.Scan.BlockBinary = src.Git.Scan.BlockBinary
+	if len(src.Git.Scan.AllowPaths) > 0 {
+		result.Git.Scan.AllowPaths = src.Git.Scan.AllowPaths
+	}
+	if len(src.Git.Scan.Rules) > 0 {
+		result.Git.Scan.Rules = src.Git.Scan.Rules
+	}
 
 	// Merge Plan
 	if src.Plan.Dir != "" {
@@ -514,6 +577,15 @@ func mergeConfigs(dst, src *Config) *Config {
 		result.Plan.FileExtension = src.Plan.FileExtension
 	}
 	result.Plan.AutoValidate = src.Plan.AutoValidate
+	if src.Plan.Language != "" {
+		result.Plan.Language = src.Plan.Language
+	}
+	if src.Plan.RepairAttempts > 0 {
+		result.Plan.RepairAttempts = src.Plan.RepairAttempts
+	}
+	if len(src.Plan.HeadingAliases) > 0 {
+		result.Plan.HeadingAliases = src.Plan.HeadingAliases
+	}
 
 	// Merge Prompts
 	if src.Prompts.Dir != "" {
@@ -529,6 +601,53 @@ func mergeConfigs(dst, src *Config) *Config {
 		result.Prompts.Doing = src.Prompts.Doing
 	}
 
+	// Merge Tracing
+	result.Tracing.Enabled = src.Tracing.Enabled
+	if src.Tracing.Exporter != "" {
+		result.Tracing.Exporter = src.Tracing.Exporter
+	}
+	if src.Tracing.File != "" {
+		result.Tracing.File = src.Tracing.File
+	}
+	if src.Tracing.Endpoint != "" {
+		result.Tracing.Endpoint = src.Tracing.Endpoint
+	}
+	if src.Tracing.ServiceName != "" {
+		result.Tracing.ServiceName = src.Tracing.ServiceName
+	}
+
+	// Merge Research
+	if src.Research.TopK > 0 {
+		result.Research.TopK = src.Research.TopK
+	}
+	if src.Research.MaxChars > 0 {
+		result.Research.MaxChars = src.Research.MaxChars
+	}
+
+	// Merge Metrics
+	result.Metrics.Enabled = src.Metrics.Enabled
+	if src.Metrics.Listen != "" {
+		result.Metrics.Listen = src.Metrics.Listen
+	}
+	if src.Metrics.Textfile != "" {
+		result.Metrics.Textfile = src.Metrics.Textfile
+	}
+
+	// Merge Redaction
+	result.Redaction.Enabled = src.Redaction.Enabled
+	if len(src.Redaction.Patterns) > 0 {
+		result.Redaction.Patterns = src.Redaction.Patterns
+	}
+	if len(src.Redaction.EnvVars) > 0 {
+		result.Redaction.EnvVars = src.Redaction.EnvVars
+	}
+	result.Redaction.TestMode = src.Redaction.TestMode
+
+	// Merge Profiles
+	if len(src.Profiles) > 0 {
+		result.Profiles = src.Profiles
+	}
+
 	return &result
 }
 
@@ -0,0 +1,381 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// Layer is a level of the configuration hierarchy.
+type Layer string
+
+// Configuration layers, lowest priority first.
+const (
+	LayerDefault Layer = "default"
+	LayerUser    Layer = "user"
+	LayerProject Layer = "project"
+	LayerProfile Layer = "profile"
+	LayerEnv     Layer = "env"
+)
+
+// Origin records where an effective configuration value came from.
+type Origin struct {
+	Layer Layer
+	// Source is the config file, profile or environment variable that
+	// set the value (empty for defaults).
+	Source string
+}
+
+// String returns the origin as "layer" or "layer:source".
+func (o Origin) String() string {
+	if o.Source == "" {
+		return string(o.Layer)
+	}
+	return string(o.Layer) + ":" + o.Source
+}
+
+// Origin returns where the effective value of a key came from. Keys no
+// layer has set come from the defaults.
+func (l *Loader) Origin(key string) Origin {
+	if o, ok := l.origins[key]; ok {
+		return o
+	}
+	return Origin{Layer: LayerDefault}
+}
+
+// UserConfigFile returns the user config file used by LoadWithMerge.
+func (l *Loader) UserConfigFile() string {
+	if l.userConfigFile != "" {
+		return l.userConfigFile
+	}
+	path, _ := expandPath(DefaultUserConfigFile)
+	return path
+}
+
+// ProjectConfigFile returns the project config file, .morty/settings.json,
+// or the deprecated .morty/config.json of older versions when only that one
+// exists.
+func (l *Loader) ProjectConfigFile() string {
+	if legacy := l.LegacyProjectConfigFile(); legacy != "" {
+		return legacy
+	}
+	return l.SettingsFile()
+}
+
+// SettingsFile returns .morty/settings.json, the current project config file.
+func (l *Loader) SettingsFile() string {
+	return filepath.Join(l.GetWorkDir(), "settings.json")
+}
+
+// LegacyProjectConfigFile returns the project config file of the old layout,
+// .morty/config.json, when it exists without a .morty/settings.json. It is
+// still loaded as the project config, but deprecated.
+func (l *Loader) LegacyProjectConfigFile() string {
+	legacy := filepath.Join(l.GetWorkDir(), "config.json")
+	if _, err := os.Stat(legacy); err != nil {
+		return ""
+	}
+	if _, err := os.Stat(l.SettingsFile()); err == nil {
+		return ""
+	}
+	return legacy
+}
+
+// setOrigins records the layer of every key set in a config file.
+func (l *Loader) setOrigins(path string, layer Layer) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return
+	}
+	for _, key := range rawKeys(raw, reflect.TypeOf(Config{}), "") {
+		l.setOrigin(key, Origin{Layer: layer, Source: path})
+	}
+}
+
+func (l *Loader) setOrigin(key string, o Origin) {
+	if l.origins == nil {
+		l.origins = make(map[string]Origin)
+	}
+	l.origins[key] = o
+}
+
+// rawKeys returns the known keys set in a decoded config file.
+func rawKeys(raw map[string]interface{}, t reflect.Type, prefix string) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		name := jsonName(t.Field(i))
+		value, ok := raw[name]
+		if name == "" || !ok {
+			continue
+		}
+		ft := t.Field(i).Type
+		if nested, isMap := value.(map[string]interface{}); isMap && ft.Kind() == reflect.Struct {
+			keys = append(keys, rawKeys(nested, ft, prefix+name+".")...)
+			continue
+		}
+		keys = append(keys, prefix+name)
+	}
+	return keys
+}
+
+// jsonName returns the JSON name of a struct field ("" when not encoded).
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// Keys returns every configuration key in dot notation, in the order of
+// the configuration file.
+func Keys() []string {
+	return structKeys(reflect.TypeOf(Config{}), "")
+}
+
+func structKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		name := jsonName(t.Field(i))
+		if name == "" {
+			continue
+		}
+		if ft := t.Field(i).Type; ft.Kind() == reflect.Struct {
+			keys = append(keys, structKeys(ft, prefix+name+".")...)
+			continue
+		}
+		keys = append(keys, prefix+name)
+	}
+	return keys
+}
+
+// IsKey reports whether key is a configuration key.
+func IsKey(key string) bool {
+	for _, k := range Keys() {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// SuggestKey returns the configuration key closest to an unknown key, or ""
+// when none is close.
+func SuggestKey(key string) string {
+	best, bestDistance := "", len(key)/2+1
+	for _, k := range Keys() {
+		if d := levenshtein(key, k); d < bestDistance {
+			best, bestDistance = k, d
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, minInt(cur[j-1]+1, prev[j-1]+cost))
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// ParseValue converts a command-line value to the type of a key. Strings
+// are taken as is; other types are parsed as JSON, and string lists also
+// accept a comma-separated list.
+func ParseValue(key, value string) (interface{}, error) {
+	if _, profileKey, ok := SplitProfileKey(key); ok {
+		key = profileKey
+	}
+	field, err := getFieldByPath(DefaultConfig(), key)
+	if err != nil {
+		return nil, fmt.Errorf("unknown config key %q", key)
+	}
+	t := reflect.TypeOf(field)
+	if t.Kind() == reflect.String {
+		return value, nil
+	}
+
+	ptr := reflect.New(t)
+	if err := json.Unmarshal([]byte(value), ptr.Interface()); err != nil {
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.String {
+			var items []string
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			return items, nil
+		}
+		return nil, fmt.Errorf("invalid value for %s (%s): %s", key, t, value)
+	}
+	return ptr.Elem().Interface(), nil
+}
+
+// ReadFileValue returns the value a config file sets for key.
+func ReadFileValue(path, key string) (interface{}, bool, error) {
+	raw, err := readRawConfig(path)
+	if err != nil {
+		return nil, false, err
+	}
+	parts := strings.Split(key, ".")
+	var current interface{} = raw
+	for _, part := range parts {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false, nil
+		}
+		if current, ok = m[part]; !ok {
+			return nil, false, nil
+		}
+	}
+	return current, true, nil
+}
+
+// SetFileValue sets key in a config file, creating the file if needed.
+// Only the key is written, so the file keeps overriding just what it sets.
+// The change is rejected if the resulting file is not a valid config.
+func SetFileValue(path, key string, value interface{}) error {
+	if !IsKey(key) && !IsProfileKey(key) {
+		return fmt.Errorf("unknown config key %q", key)
+	}
+	raw, err := readRawConfig(path)
+	if err != nil {
+		return err
+	}
+	if len(raw) == 0 {
+		raw["$schema"] = SchemaRef
+	}
+
+	parts := strings.Split(key, ".")
+	m := raw
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := m[part].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			m[part] = next
+		}
+		m = next
+	}
+	m[parts[len(parts)-1]] = value
+	return writeRawConfig(path, raw)
+}
+
+// UnsetFileValue removes key from a config file, dropping sections left
+// empty. It reports whether the file set the key.
+func UnsetFileValue(path, key string) (bool, error) {
+	raw, err := readRawConfig(path)
+	if err != nil {
+		return false, err
+	}
+	if !unsetRaw(raw, strings.Split(key, ".")) {
+		return false, nil
+	}
+	return true, writeRawConfig(path, raw)
+}
+
+func unsetRaw(m map[string]interface{}, parts []string) bool {
+	if len(parts) == 1 {
+		_, ok := m[parts[0]]
+		delete(m, parts[0])
+		return ok
+	}
+	next, ok := m[parts[0]].(map[string]interface{})
+	if !ok || !unsetRaw(next, parts[1:]) {
+		return false
+	}
+	if len(next) == 0 {
+		delete(m, parts[0])
+	}
+	return true
+}
+
+// CheckFile checks a config file against the schema, reporting invalid
+// JSON, unknown keys, values of the wrong type and invalid values. A
+// missing file is not an error.
+func CheckFile(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	return validateValue(value)
+}
+
+func readRawConfig(path string) (map[string]interface{}, error) {
+	raw := make(map[string]interface{})
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return raw, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return raw, nil
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON config from %s: %w", path, err)
+	}
+	return raw, nil
+}
+
+func writeRawConfig(path string, raw map[string]interface{}) error {
+	data, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	var value interface{}
+	json.Unmarshal(data, &value)
+	if err := validateValue(value); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+	merged := DefaultConfig()
+	json.Unmarshal(data, merged)
+	if err := NewConfigValidator().Validate(merged); err != nil {
+		return fmt.Errorf("config validation failed: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write config file %s: %w", path, err)
+	}
+	if raw["$schema"] == SchemaRef {
+		return writeSchemaFor(path)
+	}
+	return nil
+}
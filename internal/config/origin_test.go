@@ -0,0 +1,232 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// chdirTemp changes to a new temporary directory for the test.
+func chdirTemp(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	origDir, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(origDir) })
+	return dir
+}
+
+// TestLoaderOrigins tests that partial config files only override the keys
+// they set and that every key records the layer it came from.
+func TestLoaderOrigins(t *testing.T) {
+	dir := chdirTemp(t)
+	userPath := filepath.Join(dir, "user.json")
+	os.WriteFile(userPath, []byte(`{"ai_cli": {"command": "user_cli"}, "logging": {"level": "warn"}}`), 0644)
+	os.MkdirAll(".morty", 0755)
+	os.WriteFile(filepath.Join(".morty", "settings.json"), []byte(`{"logging": {"level": "debug", "file": {"max_backups": 2}}}`), 0644)
+	os.Setenv(EnvMortyHome, filepath.Join(dir, "home"))
+	defer os.Unsetenv(EnvMortyHome)
+
+	loader := NewLoader()
+	if err := loader.LoadWithMerge(userPath); err != nil {
+		t.Fatalf("LoadWithMerge() error: %v", err)
+	}
+
+	cfg := loader.Config()
+	if !cfg.Redaction.Enabled || !cfg.Logging.File.Enabled {
+		t.Error("Expected booleans not set by any file to keep their defaults")
+	}
+	if cfg.Logging.Level != "debug" || cfg.Logging.File.MaxBackups != 2 || cfg.AICli.Command != "user_cli" {
+		t.Errorf("Unexpected merged values: %+v", cfg.Logging)
+	}
+
+	tests := []struct {
+		key  string
+		want Origin
+	}{
+		{"ai_cli.command", Origin{Layer: LayerUser, Source: userPath}},
+		{"logging.level", Origin{Layer: LayerProject, Source: filepath.Join(".morty", "settings.json")}},
+		{"logging.file.max_backups", Origin{Layer: LayerProject, Source: filepath.Join(".morty", "settings.json")}},
+		{"plan.dir", Origin{Layer: LayerEnv, Source: EnvMortyHome}},
+		{"git.auto_commit", Origin{Layer: LayerDefault}},
+	}
+	for _, tt := range tests {
+		if got := loader.Origin(tt.key); got != tt.want {
+			t.Errorf("Origin(%s) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+// TestLoaderLoadWithMergeParseError tests that an unparsable project config
+// is reported while the other layers are still merged.
+func TestLoaderLoadWithMergeParseError(t *testing.T) {
+	dir := chdirTemp(t)
+	userPath := filepath.Join(dir, "user.json")
+	os.WriteFile(userPath, []byte(`{"ai_cli": {"command": "user_cli"}}`), 0644)
+	os.MkdirAll(".morty", 0755)
+	os.WriteFile(filepath.Join(".morty", "settings.json"), []byte(`{"logging": `), 0644)
+
+	loader := NewLoader()
+	err := loader.LoadWithMerge(userPath)
+	if err == nil || !strings.Contains(err.Error(), "settings.json") {
+		t.Fatalf("LoadWithMerge() error = %v, want a parse error for settings.json", err)
+	}
+	if loader.Config().AICli.Command != "user_cli" {
+		t.Errorf("Expected the user config to be merged, got %q", loader.Config().AICli.Command)
+	}
+}
+
+func TestLoaderLegacyProjectConfigFile(t *testing.T) {
+	chdirTemp(t)
+	loader := NewLoader()
+	if got := loader.LegacyProjectConfigFile(); got != "" {
+		t.Errorf("LegacyProjectConfigFile() = %q without a legacy file", got)
+	}
+
+	os.MkdirAll(".morty", 0755)
+	legacy := filepath.Join(".morty", "config.json")
+	os.WriteFile(legacy, []byte(`{}`), 0644)
+	if got := loader.LegacyProjectConfigFile(); got != legacy {
+		t.Errorf("LegacyProjectConfigFile() = %q, want %q", got, legacy)
+	}
+
+	if got := loader.ProjectConfigFile(); got != legacy {
+		t.Errorf("ProjectConfigFile() = %q, want %q", got, legacy)
+	}
+
+	os.WriteFile(loader.SettingsFile(), []byte(`{}`), 0644)
+	if got := loader.LegacyProjectConfigFile(); got != "" {
+		t.Errorf("LegacyProjectConfigFile() = %q with a settings.json", got)
+	}
+	if got := loader.ProjectConfigFile(); got != loader.SettingsFile() {
+		t.Errorf("ProjectConfigFile() = %q, want %q", got, loader.SettingsFile())
+	}
+}
+
+// TestLoaderLoadWithMergeLegacyProjectConfig tests that .morty/config.json
+// is loaded as the project config until a settings.json exists.
+func TestLoaderLoadWithMergeLegacyProjectConfig(t *testing.T) {
+	chdirTemp(t)
+	os.MkdirAll(".morty", 0755)
+	os.WriteFile(filepath.Join(".morty", "config.json"), []byte(`{"ai_cli": {"command": "legacy_cli"}}`), 0644)
+
+	loader := NewLoader()
+	if err := loader.LoadWithMerge(""); err != nil {
+		t.Fatalf("LoadWithMerge() error = %v", err)
+	}
+	if got := loader.Config().AICli.Command; got != "legacy_cli" {
+		t.Errorf("AICli.Command = %q, want the legacy project value", got)
+	}
+	if origin := loader.Origin("ai_cli.command"); origin.Layer != LayerProject {
+		t.Errorf("Origin(ai_cli.command) = %v, want the project layer", origin)
+	}
+
+	os.WriteFile(loader.SettingsFile(), []byte(`{"ai_cli": {"command": "project_cli"}}`), 0644)
+	if err := loader.LoadWithMerge(""); err != nil {
+		t.Fatalf("LoadWithMerge() error = %v", err)
+	}
+	if got := loader.Config().AICli.Command; got != "project_cli" {
+		t.Errorf("AICli.Command = %q, want settings.json to replace config.json", got)
+	}
+}
+
+func TestKeys(t *testing.T) {
+	keys := Keys()
+	for _, want := range []string{"version", "ai_cli.command", "logging.file.max_size", "redaction.patterns"} {
+		if !IsKey(want) {
+			t.Errorf("Expected key %s in %v", want, keys)
+		}
+	}
+	if IsKey("logging") || IsKey("logging.nope") {
+		t.Error("Expected sections and unknown keys not to be keys")
+	}
+	if got := SuggestKey("loging.level"); got != "logging.level" {
+		t.Errorf("SuggestKey() = %q, want logging.level", got)
+	}
+}
+
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		key   string
+		value string
+		want  interface{}
+	}{
+		{"logging.level", "debug", "debug"},
+		{"ai_cli.default_timeout", "15m", "15m"},
+		{"execution.max_retry_count", "5", 5},
+		{"git.auto_commit", "false", false},
+		{"ai_cli.default_args", "--verbose, --debug", []string{"--verbose", "--debug"}},
+		{"ai_cli.default_args", `["-p"]`, []string{"-p"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseValue(tt.key, tt.value)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseValue(%s, %q) = %#v, %v; want %#v", tt.key, tt.value, got, err, tt.want)
+		}
+	}
+	if _, err := ParseValue("execution.max_retry_count", "many"); err == nil {
+		t.Error("Expected error for a non-integer value")
+	}
+}
+
+func TestSetAndUnsetFileValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+
+	if err := SetFileValue(path, "logging.level", "debug"); err != nil {
+		t.Fatalf("SetFileValue() error: %v", err)
+	}
+	if err := SetFileValue(path, "git.auto_commit", false); err != nil {
+		t.Fatalf("SetFileValue() error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "ai_cli") || !strings.Contains(string(data), `"level": "debug"`) {
+		t.Errorf("Expected only the set keys in the file:\n%s", data)
+	}
+	if v, ok, _ := ReadFileValue(path, "git.auto_commit"); !ok || v != false {
+		t.Errorf("ReadFileValue() = %v, %v", v, ok)
+	}
+
+	if err := SetFileValue(path, "logging.level", "loud"); err == nil {
+		t.Error("Expected an invalid value to be rejected")
+	}
+	if err := SetFileValue(path, "logging.colour", "red"); err == nil {
+		t.Error("Expected an unknown key to be rejected")
+	}
+
+	if removed, err := UnsetFileValue(path, "git.auto_commit"); !removed || err != nil {
+		t.Fatalf("UnsetFileValue() = %v, %v", removed, err)
+	}
+	if removed, _ := UnsetFileValue(path, "git.auto_commit"); removed {
+		t.Error("Expected a second unset to report nothing removed")
+	}
+	data, _ = os.ReadFile(path)
+	if strings.Contains(string(data), "git") {
+		t.Errorf("Expected the empty section to be dropped:\n%s", data)
+	}
+}
+
+func TestCheckFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		os.WriteFile(path, []byte(content), 0644)
+		return path
+	}
+
+	if err := CheckFile(filepath.Join(dir, "missing.json")); err != nil {
+		t.Errorf("Expected a missing file to be fine, got %v", err)
+	}
+	if err := CheckFile(write("ok.json", `{"logging": {"level": "debug"}}`)); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := CheckFile(write("unknown.json", `{"defaults": {}}`)); err == nil || !strings.Contains(err.Error(), "defaults") {
+		t.Errorf("Expected unknown field error, got %v", err)
+	}
+	if err := CheckFile(write("type.json", `{"execution": {"max_retry_count": "3"}}`)); err == nil {
+		t.Error("Expected type error")
+	}
+}
@@ -221,43 +221,20 @@ func (p *Paths) SanitizePath(path string) string {
 
 // GetPromptsDir returns the prompts directory path.
 func (p *Paths) GetPromptsDir() string {
-	if os.Getenv("MORTY_DEBUG") != "" {
-		fmt.Printf("DEBUG: GetPromptsDir called, loader=%v, promptsDir=%q\n", p.loader != nil, p.promptsDir)
-		if p.loader != nil {
-			cfg := p.loader.Config()
-			fmt.Printf("DEBUG:   loader.Config()=%v\n", cfg != nil)
-			if cfg != nil {
-				fmt.Printf("DEBUG:   Config().Prompts.Dir=%q\n", cfg.Prompts.Dir)
-			}
-		}
-	}
-
 	// If custom prompts dir is set, use it
 	if p.promptsDir != "" {
-		result := p.resolvePath(p.promptsDir)
-		if os.Getenv("MORTY_DEBUG") != "" {
-			fmt.Printf("DEBUG: GetPromptsDir from promptsDir: %s\n", result)
-		}
-		return result
+		return p.resolvePath(p.promptsDir)
 	}
 
 	// Use loader.Config() method instead of accessing field directly
 	if p.loader != nil {
 		cfg := p.loader.Config()
 		if cfg != nil && cfg.Prompts.Dir != "" {
-			result := p.resolvePath(cfg.Prompts.Dir)
-			if os.Getenv("MORTY_DEBUG") != "" {
-				fmt.Printf("DEBUG: GetPromptsDir from config: %s (original: %s)\n", result, cfg.Prompts.Dir)
-			}
-			return result
+			return p.resolvePath(cfg.Prompts.Dir)
 		}
 	}
 
-	result := p.resolvePath(DefaultPromptsDir)
-	if os.Getenv("MORTY_DEBUG") != "" {
-		fmt.Printf("DEBUG: GetPromptsDir from default: %s\n", result)
-	}
-	return result
+	return p.resolvePath(DefaultPromptsDir)
 }
 
 // SetPromptsDir sets a custom prompts directory.
@@ -269,3 +246,18 @@ func (p *Paths) SetPromptsDir(dir string) {
 func (p *Paths) EnsurePromptsDir() error {
 	return p.EnsureDir(p.GetPromptsDir())
 }
+
+// LocalizedPromptPath returns the language-specific variant of a prompt
+// file, e.g. "prompts/plan.en.md" for "prompts/plan.md" and "en", when it
+// exists. Otherwise path itself is returned.
+func LocalizedPromptPath(path, language string) string {
+	if language == "" {
+		return path
+	}
+	ext := filepath.Ext(path)
+	localized := strings.TrimSuffix(path, ext) + "." + language + ext
+	if _, err := os.Stat(localized); err == nil {
+		return localized
+	}
+	return path
+}
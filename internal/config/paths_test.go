@@ -554,3 +554,28 @@ func TestPathsIntegration(t *testing.T) {
 		t.Error("GetConfigFile() should return absolute path")
 	}
 }
+
+func TestLocalizedPromptPath(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "plan.md")
+	if err := os.WriteFile(filepath.Join(dir, "plan.en.md"), []byte("# Plan"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path     string
+		language string
+		want     string
+	}{
+		{base, "en", filepath.Join(dir, "plan.en.md")},
+		{base, "zh", base},
+		{base, "", base},
+		{filepath.Join(dir, "doing.md"), "en", filepath.Join(dir, "doing.md")},
+	}
+
+	for _, tt := range tests {
+		if got := LocalizedPromptPath(tt.path, tt.language); got != tt.want {
+			t.Errorf("LocalizedPromptPath(%q, %q) = %q, want %q", tt.path, tt.language, got, tt.want)
+		}
+	}
+}
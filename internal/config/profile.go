@@ -0,0 +1,148 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ErrUnknownProfile is returned by LoadWithMerge when the selected profile
+// is not defined in any config file.
+var ErrUnknownProfile = errors.New("unknown profile")
+
+// SetProfile selects the profile LoadWithMerge overlays. An empty name
+// leaves the choice to MORTY_PROFILE.
+func (l *Loader) SetProfile(name string) {
+	l.profile = name
+}
+
+// SelectedProfile returns the profile selected with SetProfile or
+// MORTY_PROFILE, or "" when none is.
+func (l *Loader) SelectedProfile() string {
+	if l.profile != "" {
+		return l.profile
+	}
+	return os.Getenv(EnvMortyProfile)
+}
+
+// Profile returns the profile applied by LoadWithMerge, or "" when none is.
+func (l *Loader) Profile() string {
+	return l.activeProfile
+}
+
+// ProfileValue returns the value the applied profile sets for key.
+func (l *Loader) ProfileValue(key string) (interface{}, bool) {
+	var current interface{} = map[string]interface{}(l.config.Profiles[l.activeProfile])
+	for _, part := range strings.Split(key, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// ProfileNames returns the names of the defined profiles, sorted.
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyProfile decodes a profile onto the current configuration. Profiles
+// cannot define other profiles.
+func (l *Loader) applyProfile(name string) error {
+	if name == "" {
+		return nil
+	}
+	profile, ok := l.config.Profiles[name]
+	if !ok {
+		defined := "none defined"
+		if names := l.config.ProfileNames(); len(names) > 0 {
+			defined = "defined: " + strings.Join(names, ", ")
+		}
+		return fmt.Errorf("%w %q (%s)", ErrUnknownProfile, name, defined)
+	}
+
+	data, err := json.Marshal(profile)
+	if err != nil {
+		return fmt.Errorf("invalid profile %q: %w", name, err)
+	}
+	merged := *l.config
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return fmt.Errorf("invalid profile %q: %w", name, err)
+	}
+	merged.Profiles = l.config.Profiles
+	l.config = &merged
+	l.activeProfile = name
+
+	for _, key := range rawKeys(profile, reflect.TypeOf(Config{}), "") {
+		l.setOrigin(key, Origin{Layer: LayerProfile, Source: name})
+	}
+	return nil
+}
+
+// mergeProfiles merges the profiles of a config file into those of the
+// previous layers. Like config files, a profile only overrides the keys it
+// sets.
+func mergeProfiles(base, next map[string]Profile) map[string]Profile {
+	if len(next) == 0 {
+		return base
+	}
+	result := make(map[string]Profile, len(base)+len(next))
+	for name, p := range base {
+		result[name] = p
+	}
+	for name, p := range next {
+		result[name] = Profile(mergeRaw(result[name], p))
+	}
+	return result
+}
+
+// mergeRaw returns base with the values of next, merging nested objects.
+func mergeRaw(base, next map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(base)+len(next))
+	for k, v := range base {
+		result[k] = v
+	}
+	for k, v := range next {
+		nextMap, ok := v.(map[string]interface{})
+		baseMap, baseOK := result[k].(map[string]interface{})
+		if ok && baseOK {
+			result[k] = mergeRaw(baseMap, nextMap)
+			continue
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// SplitProfileKey splits a profile key ("profiles.<name>.<key>") into the
+// profile name and the configuration key it sets.
+func SplitProfileKey(key string) (name, configKey string, ok bool) {
+	rest, found := strings.CutPrefix(key, "profiles.")
+	if !found {
+		return "", "", false
+	}
+	name, configKey, found = strings.Cut(rest, ".")
+	if !found || name == "" || configKey == "profiles" {
+		return "", "", false
+	}
+	return name, configKey, IsKey(configKey)
+}
+
+// IsProfileKey reports whether key sets a configuration key in a profile.
+func IsProfileKey(key string) bool {
+	_, _, ok := SplitProfileKey(key)
+	return ok
+}
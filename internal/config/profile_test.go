@@ -0,0 +1,109 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const profileSettings = `{
+  "logging": {"level": "debug"},
+  "execution": {"max_retry_count": 5},
+  "profiles": {
+    "ci": {"git": {"auto_commit": false}, "execution": {"max_retry_count": 0}},
+    "overnight": {"ai_cli": {"default_timeout": "2h", "max_timeout": "4h"}}
+  }
+}`
+
+// TestLoaderProfile tests that the selected profile overlays the project
+// config and is overridden by environment variables.
+func TestLoaderProfile(t *testing.T) {
+	dir := chdirTemp(t)
+	os.MkdirAll(".morty", 0755)
+	os.WriteFile(filepath.Join(".morty", "settings.json"), []byte(profileSettings), 0644)
+	os.Setenv(EnvMortyLogLevel, "warn")
+	defer os.Unsetenv(EnvMortyLogLevel)
+
+	loader := NewLoader()
+	loader.SetProfile("ci")
+	if err := loader.LoadWithMerge(filepath.Join(dir, "user.json")); err != nil {
+		t.Fatalf("LoadWithMerge() error: %v", err)
+	}
+
+	cfg := loader.Config()
+	if loader.Profile() != "ci" || cfg.Git.AutoCommit || cfg.Execution.MaxRetryCount != 0 {
+		t.Errorf("Expected the ci profile to apply, got profile %q, %+v, %+v", loader.Profile(), cfg.Git, cfg.Execution)
+	}
+	if cfg.Logging.Level != "warn" || cfg.AICli.DefaultTimeout != DefaultAICliDefaultTimeout {
+		t.Errorf("Unexpected values outside the profile: %+v %+v", cfg.Logging, cfg.AICli)
+	}
+	if got := loader.Origin("git.auto_commit"); got != (Origin{Layer: LayerProfile, Source: "ci"}) {
+		t.Errorf("Origin(git.auto_commit) = %v", got)
+	}
+	if got := loader.Origin("logging.level"); got.Layer != LayerEnv {
+		t.Errorf("Expected env to override the profile, got %v", got)
+	}
+	if len(cfg.Profiles) != 2 {
+		t.Errorf("Expected the profiles to be kept, got %v", cfg.ProfileNames())
+	}
+}
+
+func TestLoaderProfileFromEnv(t *testing.T) {
+	dir := chdirTemp(t)
+	os.MkdirAll(".morty", 0755)
+	os.WriteFile(filepath.Join(".morty", "settings.json"), []byte(profileSettings), 0644)
+	os.Setenv(EnvMortyProfile, "overnight")
+	defer os.Unsetenv(EnvMortyProfile)
+
+	loader := NewLoader()
+	if err := loader.LoadWithMerge(filepath.Join(dir, "user.json")); err != nil {
+		t.Fatalf("LoadWithMerge() error: %v", err)
+	}
+	if loader.Profile() != "overnight" || loader.Config().AICli.MaxTimeout != "4h" {
+		t.Errorf("Expected the overnight profile, got %q", loader.Profile())
+	}
+
+	loader.SetProfile("cheap")
+	err := loader.LoadWithMerge(filepath.Join(dir, "user.json"))
+	if !errors.Is(err, ErrUnknownProfile) || !strings.Contains(err.Error(), "defined: ci, overnight") {
+		t.Errorf("Expected an unknown profile error, got %v", err)
+	}
+}
+
+func TestProfileSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	os.WriteFile(path, []byte(`{"profiles": {"ci": {"version": "2.0", "logging": {"level": "loud"}}}}`), 0644)
+
+	err := CheckFile(path)
+	if err == nil {
+		t.Fatal("Expected profile errors")
+	}
+	for _, want := range []string{"profiles.ci.version': unknown field", "profiles.ci.logging.level': invalid value"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in:\n%v", want, err)
+		}
+	}
+}
+
+func TestSetProfileFileValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+
+	value, err := ParseValue("profiles.ci.execution.max_retry_count", "0")
+	if err != nil {
+		t.Fatalf("ParseValue() error: %v", err)
+	}
+	if err := SetFileValue(path, "profiles.ci.execution.max_retry_count", value); err != nil {
+		t.Fatalf("SetFileValue() error: %v", err)
+	}
+	if v, ok, _ := ReadFileValue(path, "profiles.ci.execution.max_retry_count"); !ok || v != float64(0) {
+		t.Errorf("ReadFileValue() = %v, %v", v, ok)
+	}
+
+	for _, key := range []string{"profiles.ci.profiles", "profiles.ci.logging.colour", "profiles..logging.level"} {
+		if IsProfileKey(key) {
+			t.Errorf("Expected %s not to be a profile key", key)
+		}
+	}
+}
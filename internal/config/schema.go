@@ -0,0 +1,210 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+
+	"github.com/morty/morty/internal/schema"
+)
+
+// SchemaFileName is the name of the JSON Schema written next to generated
+// config files.
+const SchemaFileName = "settings.schema.json"
+
+// SchemaRef is the "$schema" reference written into generated config files.
+const SchemaRef = "./" + SchemaFileName
+
+// Patterns of string formats the schema checks. Empty strings are allowed
+// because they leave the built-in default in effect.
+const (
+	durationPattern = `^$|^[-+]?(0|([0-9]*(\.[0-9]*)?(ns|us|µs|μs|ms|s|m|h))+)$`
+	sizeStrPattern  = `^$|^0*[1-9][0-9]*([KkMmGgTt]?[Bb])$`
+)
+
+func enum(values ...interface{}) []interface{} { return values }
+
+// inRuleEnum reports whether value is one of the values the schema allows
+// for key.
+func inRuleEnum(key string, value interface{}) bool {
+	for _, e := range configRules[key].Enum {
+		if e == value {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	durationRule = schema.Rule{Pattern: durationPattern, Format: "duration"}
+	sizeRule     = schema.Rule{Pattern: sizeStrPattern, Format: "size"}
+)
+
+// with returns a copy of r with a description.
+func with(r schema.Rule, description string) schema.Rule {
+	r.Description = description
+	return r
+}
+
+// configRules holds the constraints and descriptions of config keys. It is
+// the single source of the rules both the CLI and editors check.
+var configRules = map[string]schema.Rule{
+	"": {Description: "Morty configuration (.morty/settings.json, ~/.morty/config.json)"},
+
+	"version": {Description: "Configuration format version", Enum: enum(DefaultVersion)},
+
+	"ai_cli":                         {Description: "AI CLI settings"},
+	"ai_cli.command":                 {Description: "AI CLI command name", MinLength: schema.NonEmpty},
+	"ai_cli.env_var":                 {Description: "Environment variable that overrides the AI CLI path", MinLength: schema.NonEmpty},
+	"ai_cli.default_timeout":         with(durationRule, "Default timeout of AI CLI calls (e.g. 10m)"),
+	"ai_cli.max_timeout":             with(durationRule, "Maximum timeout of AI CLI calls (e.g. 30m)"),
+	"ai_cli.enable_skip_permissions": {Description: "Pass --dangerously-skip-permissions to the AI CLI"},
+	"ai_cli.default_args":            {Description: "Arguments passed to every AI CLI call"},
+	"ai_cli.output_format":           {Description: "AI CLI output format", Enum: enum("", "json", "text")},
+
+	"execution":                   {Description: "Job execution settings"},
+	"execution.max_retry_count":   {Description: "Retries of a failed job", Minimum: schema.Min(0)},
+	"execution.auto_git_commit":   {Description: "Commit after each completed job"},
+	"execution.continue_on_error": {Description: "Keep running other jobs when a job fails"},
+	"execution.parallel_jobs":     {Description: "Number of jobs run in parallel", Minimum: schema.Min(1)},
+	"execution.digest_budget":     {Description: "Tokens of completed job digests added to a job prompt", Minimum: schema.Min(1)},
+	"execution.fresh_session":     {Description: "Start each retry of a job in a new AI CLI session instead of resuming the failed one"},
+
+	"logging":                  {Description: "Logging settings"},
+	"logging.level":            {Description: "Log level", Enum: enum("", "debug", "info", "warn", "error", "DEBUG", "INFO", "WARN", "ERROR")},
+	"logging.format":           {Description: "Log format", Enum: enum("", "json", "text")},
+	"logging.output":           {Description: "Log destination", Enum: enum("", "stdout", "file", "both")},
+	"logging.file":             {Description: "Log file settings"},
+	"logging.file.enabled":     {Description: "Write logs to a file"},
+	"logging.file.path":        {Description: "Log file path"},
+	"logging.file.max_size":    with(sizeRule, "Size at which the log file is rotated (e.g. 10MB)"),
+	"logging.file.max_backups": {Description: "Rotated log files kept", Minimum: schema.Min(0)},
+	"logging.file.max_age":     {Description: "Days rotated log files are kept", Minimum: schema.Min(0)},
+
+	"state":               {Description: "Execution state settings"},
+	"state.file":          {Description: "Status file path", MinLength: schema.NonEmpty},
+	"state.auto_save":     {Description: "Save the state automatically"},
+	"state.save_interval": with(durationRule, "Interval of automatic state saves (e.g. 30s)"),
+
+	"git":                        {Description: "Git settings"},
+	"git.commit_prefix":          {Description: "Prefix of generated commit messages", MinLength: schema.NonEmpty},
+	"git.auto_commit":            {Description: "Create commits automatically"},
+	"git.require_clean_worktree": {Description: "Refuse to run with uncommitted changes"},
+	"git.scan":                   {Description: "Scanning of staged files before auto-commits"},
+	"git.scan.enabled":           {Description: "Scan staged files before auto-commits"},
+	"git.scan.action":            {Description: "What to do with offending files", Enum: enum("", "block", "unstage")},
+	"git.scan.max_file_size":     with(sizeRule, "Largest file that may be committed (e.g. 5MB)"),
+	"git.scan.block_binary":      {Description: "Report binary files"},
+	"git.scan.allow_paths":       {Description: "Glob patterns of paths that are not scanned"},
+	"git.scan.rules":             {Description: "Content rules added to the built-in ones"},
+	"git.scan.rules[].name":      {Description: "Rule name shown in findings", MinLength: schema.NonEmpty},
+	"git.scan.rules[].pattern":   {Description: "Go regular expression matched against each line"},
+
+	"plan":                 {Description: "Plan settings"},
+	"plan.dir":             {Description: "Plan directory", MinLength: schema.NonEmpty},
+	"plan.file_extension":  {Description: "Extension of plan files", MinLength: schema.NonEmpty},
+	"plan.auto_validate":   {Description: "Validate plans automatically"},
+	"plan.language":        {Description: "Heading language of generated plans", Enum: enum("", "zh", "en")},
+	"plan.heading_aliases": {Description: "Extra headings accepted for a plan section (e.g. debug_logs)"},
+	"plan.repair_attempts": {Description: "Rounds of plan validate --repair before giving up", Minimum: schema.Min(1)},
+
+	"prompts":          {Description: "Prompt template paths"},
+	"prompts.dir":      {Description: "Prompt directory", MinLength: schema.NonEmpty},
+	"prompts.research": {Description: "Research prompt template", MinLength: schema.NonEmpty},
+	"prompts.plan":     {Description: "Plan prompt template", MinLength: schema.NonEmpty},
+	"prompts.doing":    {Description: "Doing prompt template", MinLength: schema.NonEmpty},
+
+	"research":           {Description: "Research knowledge base settings"},
+	"research.top_k":     {Description: "Research sections selected for a plan module or doing job", Minimum: schema.Min(1)},
+	"research.max_chars": {Description: "Research size up to which plan prompts include every document", Minimum: schema.Min(0)},
+
+	"tracing":              {Description: "Trace export settings"},
+	"tracing.enabled":      {Description: "Export traces"},
+	"tracing.exporter":     {Description: "Where spans are sent", Enum: enum("", "file", "otlp_http")},
+	"tracing.file":         {Description: "Trace file of the file exporter"},
+	"tracing.endpoint":     {Description: "OTLP HTTP traces endpoint of the otlp_http exporter"},
+	"tracing.service_name": {Description: "service.name resource attribute"},
+
+	"metrics":          {Description: "Prometheus metrics settings"},
+	"metrics.enabled":  {Description: "Collect metrics"},
+	"metrics.listen":   {Description: "Address /metrics is served on during a run (host:port)"},
+	"metrics.textfile": {Description: "node-exporter textfile written after a run"},
+
+	"redaction":                    {Description: "Secret redaction settings"},
+	"redaction.enabled":            {Description: "Mask secrets in logs and transcripts"},
+	"redaction.patterns":           {Description: "Redaction rules added to the built-in ones"},
+	"redaction.patterns[].name":    {Description: "Rule name shown in the mask", MinLength: schema.NonEmpty},
+	"redaction.patterns[].pattern": {Description: "Go regular expression; only the group named secret is masked"},
+	"redaction.env_vars":           {Description: "Names or glob patterns of environment variables whose values are masked"},
+	"redaction.test_mode":          {Description: "Fail doing runs that leak a canary secret"},
+
+	"profiles": {Description: "Named partial configurations selected with --profile or MORTY_PROFILE"},
+}
+
+var (
+	configSchemaOnce sync.Once
+	configSchema     *schema.Schema
+)
+
+// Schema returns the JSON Schema of config files, with the built-in
+// defaults as property defaults.
+func Schema() *schema.Schema {
+	configSchemaOnce.Do(func() {
+		s := schema.Generate(reflect.TypeOf(Config{}), configRules)
+		s.Title = "Morty settings"
+		s.Properties["profiles"].AdditionalProperties = profileSchema()
+		if data, err := json.Marshal(DefaultConfig()); err == nil {
+			var defaults interface{}
+			if json.Unmarshal(data, &defaults) == nil {
+				s.SetDefaults(defaults)
+			}
+		}
+		configSchema = s
+	})
+	return configSchema
+}
+
+// profileSchema returns the schema of a profile: any config key except the
+// version and other profiles.
+func profileSchema() *schema.Schema {
+	s := schema.Generate(reflect.TypeOf(Config{}), configRules)
+	s.Schema = ""
+	s.Description = "Configuration overlaid when the profile is selected"
+	for _, name := range []string{"$schema", "version", "profiles"} {
+		delete(s.Properties, name)
+	}
+	return s
+}
+
+// validateValue checks a decoded config document against the schema.
+func validateValue(v interface{}) error {
+	var errs ValidationErrors
+	for _, e := range Schema().Validate(v) {
+		errs.Errors = append(errs.Errors, &ValidationError{Field: e.Path, Message: e.Message})
+	}
+	if errs.HasErrors() {
+		return &errs
+	}
+	return nil
+}
+
+// WriteSchema writes the config schema to path.
+func WriteSchema(path string) error {
+	data, err := json.MarshalIndent(Schema(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create schema directory %s: %w", filepath.Dir(path), err)
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// writeSchemaFor writes the schema next to a config file so its SchemaRef
+// resolves. An existing schema is refreshed.
+func writeSchemaFor(configPath string) error {
+	return WriteSchema(filepath.Join(filepath.Dir(configPath), SchemaFileName))
+}
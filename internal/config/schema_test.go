@@ -0,0 +1,86 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSchemaFileUpToDate tests that configs/settings.schema.json matches the
+// schema generated from the Config type.
+func TestSchemaFileUpToDate(t *testing.T) {
+	want, err := json.MarshalIndent(Schema(), "", "  ")
+	if err != nil {
+		t.Fatalf("Failed to marshal schema: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join("..", "..", "configs", SchemaFileName))
+	if err != nil {
+		t.Fatalf("Failed to read schema: %v", err)
+	}
+	if string(got) != string(want)+"\n" {
+		t.Error("configs/settings.schema.json is stale; run: morty config schema -o configs/settings.schema.json")
+	}
+}
+
+// TestSettingsFileMatchesSchema tests that the shipped settings file
+// validates against the schema it references.
+func TestSettingsFileMatchesSchema(t *testing.T) {
+	if err := CheckFile(filepath.Join("..", "..", "configs", "settings.json")); err != nil {
+		t.Errorf("CheckFile() error: %v", err)
+	}
+}
+
+func TestSchemaDefaults(t *testing.T) {
+	s := Schema()
+	if got := s.Property("execution.parallel_jobs"); got.Default != float64(DefaultExecutionParallelJobs) || *got.Minimum != 1 {
+		t.Errorf("Unexpected parallel_jobs schema: %+v", got)
+	}
+	if got := s.Property("logging.file.max_size").Default; got != DefaultLoggingFileMaxSize {
+		t.Errorf("max_size default = %v, want %s", got, DefaultLoggingFileMaxSize)
+	}
+}
+
+// TestValidateUsesSchema tests that Loader.Validate reports every schema
+// violation with its path.
+func TestValidateUsesSchema(t *testing.T) {
+	loader := NewLoader()
+	cfg := loader.Config()
+	cfg.Execution.ParallelJobs = 0
+	cfg.AICli.DefaultTimeout = "ten minutes"
+	cfg.Git.Scan.Rules = []GitScanRule{{Name: "", Pattern: "x"}}
+
+	err := loader.Validate()
+	if err == nil {
+		t.Fatal("Expected validation errors")
+	}
+	for _, want := range []string{
+		"'execution.parallel_jobs': must be >= 1",
+		`'ai_cli.default_timeout': invalid duration format: "ten minutes"`,
+		"'git.scan.rules[0].name': must not be empty",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in:\n%v", want, err)
+		}
+	}
+}
+
+func TestSetFileValueWritesSchemaRef(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "settings.json")
+
+	if err := SetFileValue(path, "logging.level", "debug"); err != nil {
+		t.Fatalf("SetFileValue() error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `"$schema": "./settings.schema.json"`) {
+		t.Errorf("Expected a $schema reference:\n%s", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, SchemaFileName)); err != nil {
+		t.Errorf("Expected the schema next to the config file: %v", err)
+	}
+	if err := CheckFile(path); err != nil {
+		t.Errorf("CheckFile() error: %v", err)
+	}
+}
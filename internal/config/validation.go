@@ -1,7 +1,10 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"net"
+	"path"
 	"regexp"
 	"strconv"
 	"strings"
@@ -60,14 +63,13 @@ type ConfigValidator struct {
 func NewConfigValidator() *ConfigValidator {
 	v := &ConfigValidator{}
 	v.validators = []func(*Config) error{
-		v.validateVersion,
-		v.validateAICli,
-		v.validateExecution,
+		v.validateSchema,
 		v.validateLogging,
-		v.validateState,
 		v.validateGit,
 		v.validatePlan,
-		v.validatePrompts,
+		v.validateTracing,
+		v.validateMetrics,
+		v.validateRedaction,
 	}
 	return v
 }
@@ -80,7 +82,10 @@ func (v *ConfigValidator) Validate(cfg *Config) error {
 
 	var errs ValidationErrors
 	for _, validator := range v.validators {
-		if err := validator(cfg); err != nil {
+		err := validator(cfg)
+		if nested, ok := err.(*ValidationErrors); ok {
+			errs.Errors = append(errs.Errors, nested.Errors...)
+		} else if err != nil {
 			errs.Errors = append(errs.Errors, err)
 		}
 	}
@@ -91,173 +96,92 @@ func (v *ConfigValidator) Validate(cfg *Config) error {
 	return nil
 }
 
-// validateVersion checks the version field.
-func (v *ConfigValidator) validateVersion(cfg *Config) error {
-	if cfg.Version == "" {
-		return &ValidationError{Field: "version", Message: "version is required"}
+// validateSchema checks the configuration against the JSON Schema, which
+// covers required values, enums, minimums and duration and size formats.
+func (v *ConfigValidator) validateSchema(cfg *Config) error {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return &ValidationError{Field: "config", Message: err.Error()}
 	}
-	if cfg.Version != "2.0" {
-		return &ValidationError{Field: "version", Message: fmt.Sprintf("unsupported version: %s (expected 2.0)", cfg.Version)}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return &ValidationError{Field: "config", Message: err.Error()}
 	}
-	return nil
-}
-
-// validateAICli validates AI CLI configuration.
-func (v *ConfigValidator) validateAICli(cfg *Config) error {
-	aiCli := cfg.AICli
-
-	if aiCli.Command == "" {
-		return &ValidationError{Field: "ai_cli.command", Message: "command is required"}
-	}
-
-	if aiCli.EnvVar == "" {
-		return &ValidationError{Field: "ai_cli.env_var", Message: "env_var is required"}
-	}
-
-	// Validate timeout format
-	if aiCli.DefaultTimeout != "" {
-		if _, err := time.ParseDuration(aiCli.DefaultTimeout); err != nil {
-			return &ValidationError{Field: "ai_cli.default_timeout", Message: fmt.Sprintf("invalid duration format: %s", aiCli.DefaultTimeout)}
-		}
-	}
-
-	if aiCli.MaxTimeout != "" {
-		if _, err := time.ParseDuration(aiCli.MaxTimeout); err != nil {
-			return &ValidationError{Field: "ai_cli.max_timeout", Message: fmt.Sprintf("invalid duration format: %s", aiCli.MaxTimeout)}
-		}
-	}
-
-	// Validate output format
-	validFormats := map[string]bool{"json": true, "text": true}
-	if aiCli.OutputFormat != "" && !validFormats[aiCli.OutputFormat] {
-		return &ValidationError{Field: "ai_cli.output_format", Message: fmt.Sprintf("invalid output format: %s (must be 'json' or 'text')", aiCli.OutputFormat)}
-	}
-
-	return nil
-}
-
-// validateExecution validates execution configuration.
-func (v *ConfigValidator) validateExecution(cfg *Config) error {
-	exec := cfg.Execution
-
-	if exec.MaxRetryCount < 0 {
-		return &ValidationError{Field: "execution.max_retry_count", Message: "max_retry_count must be >= 0"}
-	}
-
-	if exec.ParallelJobs < 1 {
-		return &ValidationError{Field: "execution.parallel_jobs", Message: "parallel_jobs must be >= 1"}
-	}
-
-	return nil
+	return validateValue(value)
 }
 
 // validateLogging validates logging configuration.
 func (v *ConfigValidator) validateLogging(cfg *Config) error {
 	logging := cfg.Logging
 
-	// Validate log level
-	validLevels := map[string]bool{"debug": true, "info": true, "warn": true, "error": true}
-	if logging.Level != "" && !validLevels[strings.ToLower(logging.Level)] {
-		return &ValidationError{Field: "logging.level", Message: fmt.Sprintf("invalid log level: %s (must be 'debug', 'info', 'warn', or 'error')", logging.Level)}
+	if logging.File.Enabled && logging.File.Path == "" {
+		return &ValidationError{Field: "logging.file.path", Message: "path is required when file logging is enabled"}
 	}
 
-	// Validate log format
-	validFormats := map[string]bool{"json": true, "text": true}
-	if logging.Format != "" && !validFormats[logging.Format] {
-		return &ValidationError{Field: "logging.format", Message: fmt.Sprintf("invalid log format: %s (must be 'json' or 'text')", logging.Format)}
-	}
-
-	// Validate output destination
-	validOutputs := map[string]bool{"stdout": true, "file": true, "both": true}
-	if logging.Output != "" && !validOutputs[logging.Output] {
-		return &ValidationError{Field: "logging.output", Message: fmt.Sprintf("invalid output destination: %s (must be 'stdout', 'file', or 'both')", logging.Output)}
-	}
-
-	// Validate file logging config
-	if logging.File.Enabled {
-		if logging.File.Path == "" {
-			return &ValidationError{Field: "logging.file.path", Message: "path is required when file logging is enabled"}
-		}
-
-		if logging.File.MaxSize != "" {
-			if err := validateSizeString(logging.File.MaxSize); err != nil {
-				return &ValidationError{Field: "logging.file.max_size", Message: err.Error()}
-			}
-		}
-
-		if logging.File.MaxBackups < 0 {
-			return &ValidationError{Field: "logging.file.max_backups", Message: "max_backups must be >= 0"}
-		}
+	return nil
+}
 
-		if logging.File.MaxAge < 0 {
-			return &ValidationError{Field: "logging.file.max_age", Message: "max_age must be >= 0"}
+// validateGit validates Git configuration.
+func (v *ConfigValidator) validateGit(cfg *Config) error {
+	for i, rule := range cfg.Git.Scan.Rules {
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return &ValidationError{Field: fmt.Sprintf("git.scan.rules[%d].pattern", i), Message: fmt.Sprintf("invalid pattern: %v", err)}
 		}
 	}
 
 	return nil
 }
 
-// validateState validates state configuration.
-func (v *ConfigValidator) validateState(cfg *Config) error {
-	state := cfg.State
-
-	if state.File == "" {
-		return &ValidationError{Field: "state.file", Message: "file is required"}
-	}
-
-	if state.SaveInterval != "" {
-		if _, err := time.ParseDuration(state.SaveInterval); err != nil {
-			return &ValidationError{Field: "state.save_interval", Message: fmt.Sprintf("invalid duration format: %s", state.SaveInterval)}
+// validatePlan validates plan configuration.
+func (v *ConfigValidator) validatePlan(cfg *Config) error {
+	for section, aliases := range cfg.Plan.HeadingAliases {
+		for _, alias := range aliases {
+			if strings.TrimSpace(alias) == "" {
+				return &ValidationError{Field: "plan.heading_aliases." + section, Message: "alias must not be empty"}
+			}
 		}
 	}
 
 	return nil
 }
 
-// validateGit validates Git configuration.
-func (v *ConfigValidator) validateGit(cfg *Config) error {
-	git := cfg.Git
+// validateTracing validates tracing configuration.
+func (v *ConfigValidator) validateTracing(cfg *Config) error {
+	tracing := cfg.Tracing
 
-	if git.CommitPrefix == "" {
-		return &ValidationError{Field: "git.commit_prefix", Message: "commit_prefix is required"}
+	if tracing.Enabled && tracing.Exporter == "otlp_http" && tracing.Endpoint == "" {
+		return &ValidationError{Field: "tracing.endpoint", Message: "endpoint is required for the otlp_http exporter"}
 	}
 
 	return nil
 }
 
-// validatePlan validates plan configuration.
-func (v *ConfigValidator) validatePlan(cfg *Config) error {
-	plan := cfg.Plan
-
-	if plan.Dir == "" {
-		return &ValidationError{Field: "plan.dir", Message: "dir is required"}
-	}
+// validateMetrics validates metrics configuration.
+func (v *ConfigValidator) validateMetrics(cfg *Config) error {
+	metrics := cfg.Metrics
 
-	if plan.FileExtension == "" {
-		return &ValidationError{Field: "plan.file_extension", Message: "file_extension is required"}
+	if metrics.Listen != "" {
+		if _, _, err := net.SplitHostPort(metrics.Listen); err != nil {
+			return &ValidationError{Field: "metrics.listen", Message: fmt.Sprintf("invalid address: %s (expected host:port)", metrics.Listen)}
+		}
 	}
 
 	return nil
 }
 
-// validatePrompts validates prompts configuration.
-func (v *ConfigValidator) validatePrompts(cfg *Config) error {
-	prompts := cfg.Prompts
-
-	if prompts.Dir == "" {
-		return &ValidationError{Field: "prompts.dir", Message: "dir is required"}
-	}
-
-	if prompts.Research == "" {
-		return &ValidationError{Field: "prompts.research", Message: "research is required"}
-	}
+// validateRedaction validates redaction configuration.
+func (v *ConfigValidator) validateRedaction(cfg *Config) error {
+	redaction := cfg.Redaction
 
-	if prompts.Plan == "" {
-		return &ValidationError{Field: "prompts.plan", Message: "plan is required"}
+	for i, pattern := range redaction.Patterns {
+		if _, err := regexp.Compile(pattern.Pattern); err != nil {
+			return &ValidationError{Field: fmt.Sprintf("redaction.patterns[%d].pattern", i), Message: fmt.Sprintf("invalid pattern: %v", err)}
+		}
 	}
-
-	if prompts.Doing == "" {
-		return &ValidationError{Field: "prompts.doing", Message: "doing is required"}
+	for i, name := range redaction.EnvVars {
+		if _, err := path.Match(name, ""); err != nil || name == "" {
+			return &ValidationError{Field: fmt.Sprintf("redaction.env_vars[%d]", i), Message: fmt.Sprintf("invalid name or pattern: %q", name)}
+		}
 	}
 
 	return nil
@@ -285,6 +209,28 @@ func validateSizeString(s string) error {
 	return nil
 }
 
+// sizeUnits maps size suffixes to byte multipliers.
+var sizeUnits = map[string]int64{
+	"B":  1,
+	"KB": 1 << 10,
+	"MB": 1 << 20,
+	"GB": 1 << 30,
+	"TB": 1 << 40,
+}
+
+// parseSizeString converts a size string (e.g., "10MB") to bytes.
+func parseSizeString(s string) (int64, error) {
+	if err := validateSizeString(s); err != nil {
+		return 0, err
+	}
+	matches := sizePattern.FindStringSubmatch(strings.ToUpper(s))
+	n, err := strconv.ParseInt(matches[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return n * sizeUnits[matches[2]], nil
+}
+
 // IsValid checks if a configuration is valid without returning detailed errors.
 func IsValid(cfg *Config) bool {
 	validator := NewConfigValidator()
@@ -313,11 +259,9 @@ func ValidateField(cfg *Config, key string, value interface{}) error {
 			}
 		}
 	case "logging.level":
-		if v, ok := value.(string); ok && v != "" {
-			validLevels := map[string]bool{"debug": true, "info": true, "warn": true, "error": true}
-			if !validLevels[strings.ToLower(v)] {
-				return &ValidationError{Field: key, Message: fmt.Sprintf("invalid log level: %s", v)}
-			}
+		// The schema lists the spellings the logger understands
+		if v, ok := value.(string); ok && !inRuleEnum(key, v) {
+			return &ValidationError{Field: key, Message: fmt.Sprintf("invalid log level: %s", v)}
 		}
 	case "execution.max_retry_count", "execution.parallel_jobs", "logging.file.max_backups", "logging.file.max_age":
 		if v, ok := value.(int); ok && v < 0 {
@@ -353,6 +353,45 @@ func TestValidateGit(t *testing.T) {
 			t.Error("expected error for empty commit_prefix")
 		}
 	})
+
+	t.Run("invalid scan action", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Git.Scan.Action = "ignore"
+		err := validator.Validate(cfg)
+		if err == nil {
+			t.Error("expected error for invalid scan action")
+		}
+	})
+
+	t.Run("invalid scan max_file_size", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Git.Scan.MaxFileSize = "lots"
+		err := validator.Validate(cfg)
+		if err == nil {
+			t.Error("expected error for invalid scan max_file_size")
+		}
+	})
+
+	t.Run("invalid scan rule pattern", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Git.Scan.Rules = []GitScanRule{{Name: "bad", Pattern: "("}}
+		err := validator.Validate(cfg)
+		if err == nil {
+			t.Error("expected error for invalid scan rule pattern")
+		}
+	})
+
+	t.Run("valid scan config", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Git.Scan.Action = "unstage"
+		cfg.Git.Scan.Rules = []GitScanRule{{Name: "internal-host", Pattern: `corp\.internal`}}
+		if err := validator.Validate(cfg); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if got := cfg.Git.Scan.MaxFileSizeBytes(0); got != 10*1024*1024 {
+			t.Errorf("MaxFileSizeBytes() = %d, want %d", got, 10*1024*1024)
+		}
+	})
 }
 
 // TestValidatePlan tests plan validation.
@@ -376,6 +415,31 @@ func TestValidatePlan(t *testing.T) {
 			t.Error("expected error for empty file_extension")
 		}
 	})
+
+	t.Run("invalid language", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Plan.Language = "fr"
+		if err := validator.Validate(cfg); err == nil {
+			t.Error("expected error for invalid language")
+		}
+	})
+
+	t.Run("empty heading alias", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Plan.HeadingAliases = map[string][]string{"debug_logs": {" "}}
+		if err := validator.Validate(cfg); err == nil {
+			t.Error("expected error for empty heading alias")
+		}
+	})
+
+	t.Run("english with aliases", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Plan.Language = "en"
+		cfg.Plan.HeadingAliases = map[string][]string{"debug_logs": {"Troubleshooting"}}
+		if err := validator.Validate(cfg); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
 }
 
 // TestValidatePrompts tests prompts validation.
@@ -494,6 +558,7 @@ func TestValidateField(t *testing.T) {
 		{"invalid duration", "ai_cli.default_timeout", "invalid", true, "invalid duration"},
 		{"valid log level", "logging.level", "info", false, ""},
 		{"invalid log level", "logging.level", "trace", true, "invalid log level"},
+		{"mixed case log level", "logging.level", "Debug", true, "invalid log level"},
 		{"valid retry count", "execution.max_retry_count", 5, false, ""},
 		{"negative retry count", "execution.max_retry_count", -1, true, ">= 0"},
 		{"unknown field", "unknown.field", "value", false, ""},
@@ -518,6 +583,21 @@ func TestValidateField(t *testing.T) {
 	}
 }
 
+// TestValidateField_LogLevelMatchesSchema tests that ValidateField accepts
+// the same log levels as the schema validation of a whole config.
+func TestValidateField_LogLevelMatchesSchema(t *testing.T) {
+	validator := NewConfigValidator()
+	for _, level := range []string{"", "debug", "DEBUG", "Debug", "warn", "WARN", "Warn", "warning", "trace"} {
+		cfg := DefaultConfig()
+		cfg.Logging.Level = level
+		fieldErr := ValidateField(cfg, "logging.level", level)
+		configErr := validator.Validate(cfg)
+		if (fieldErr == nil) != (configErr == nil) {
+			t.Errorf("level %q: ValidateField() error = %v, Validate() error = %v", level, fieldErr, configErr)
+		}
+	}
+}
+
 // BenchmarkValidate benchmarks validation performance.
 func BenchmarkValidate(b *testing.B) {
 	validator := NewConfigValidator()
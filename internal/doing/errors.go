@@ -4,6 +4,7 @@ package doing
 import (
 	"errors"
 	"fmt"
+	"os/exec"
 	"strings"
 )
 
@@ -209,7 +210,15 @@ func ClassifyError(err error) *DoingError {
 	lowerErrStr := strings.ToLower(errStr)
 
 	// Classify based on error message patterns
+	var execErr *exec.Error
 	switch {
+	// AI CLI not installed or pointing at a removed binary
+	case errors.As(err, &execErr) ||
+		strings.Contains(lowerErrStr, "executable file not found") ||
+		strings.Contains(lowerErrStr, "command not found"):
+		return NewDoingError(ErrorCategoryConfig, "AI CLI 不可用", err).
+			WithContext("error_type", "cli_not_found")
+
 	// Prerequisite errors
 	case strings.Contains(lowerErrStr, "prerequisite") ||
 		strings.Contains(lowerErrStr, "前置条件"):
@@ -290,6 +299,10 @@ func classifyGitError(err error) *DoingError {
 	errStr := strings.ToLower(err.Error())
 
 	switch {
+	case strings.Contains(errStr, "index.lock"):
+		return NewDoingError(ErrorCategoryGit, "Git 仓库被锁定", err).
+			WithContext("error_type", "git_locked")
+
 	case strings.Contains(errStr, "not a git repo") ||
 		strings.Contains(errStr, "not initialized"):
 		return NewDoingError(ErrorCategoryGit, "Git 仓库未初始化", err).
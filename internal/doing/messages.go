@@ -122,7 +122,7 @@ func getPlanMessage(err *DoingError) *FriendlyMessage {
 			Title:       "计划文件格式错误",
 			Description: err.Message + "\n\n计划文件的 Markdown 格式可能不正确。",
 			Suggestion:  "检查计划文件的语法，确保 Job 和 Task 的定义格式正确。",
-			Command:     "morty validate",
+			Command:     "morty plan validate",
 		}
 
 	case "job_not_found":
@@ -200,6 +200,24 @@ func getGitMessage(err *DoingError) *FriendlyMessage {
 			Command:     "git init",
 		}
 
+	case "git_locked":
+		return &FriendlyMessage{
+			Emoji:       "🔒",
+			Title:       "Git 仓库被锁定",
+			Description: err.Message,
+			Suggestion:  "可能有中断的 git 进程留下了锁文件，确认没有 git 进程在运行后删除它。",
+			Command:     "rm .git/index.lock",
+		}
+
+	case "git_dirty":
+		return &FriendlyMessage{
+			Emoji:       "📝",
+			Title:       "工作区有未提交的修改",
+			Description: err.Message,
+			Suggestion:  "请先提交或暂存修改，或关闭 git.require_clean_worktree。",
+			Command:     "git status",
+		}
+
 	case "git_commit_failed":
 		return &FriendlyMessage{
 			Emoji:       "📝",
@@ -241,6 +259,25 @@ func getStateMessage(err *DoingError) *FriendlyMessage {
 			Command:     "rm .morty/status.json && morty doing",
 		}
 
+	case "state_stale_running":
+		job, _ := err.Context["job"].(string)
+		return &FriendlyMessage{
+			Emoji:       "⏳",
+			Title:       "Job 停留在执行中状态",
+			Description: err.Message,
+			Suggestion:  "上次运行可能被中断，该 Job 不会再被自动执行，请重置后重新运行。",
+			Command:     restartCommand(job),
+		}
+
+	case "state_out_of_sync":
+		return &FriendlyMessage{
+			Emoji:       "🔀",
+			Title:       "状态文件与计划不一致",
+			Description: err.Message,
+			Suggestion:  "计划文件在生成状态后被修改过。重新生成状态文件会丢失执行进度。",
+			Command:     "rm .morty/status.json && morty doing",
+		}
+
 	case "state_not_found":
 		return &FriendlyMessage{
 			Emoji:       "🆕",
@@ -260,13 +297,44 @@ func getStateMessage(err *DoingError) *FriendlyMessage {
 }
 
 func getConfigMessage(err *DoingError) *FriendlyMessage {
-	return &FriendlyMessage{
+	errorType, _ := err.Context["error_type"].(string)
+
+	switch errorType {
+	case "cli_not_found":
+		envVar, _ := err.Context["env_var"].(string)
+		if envVar == "" {
+			envVar = "CLAUDE_CODE_CLI"
+		}
+		return &FriendlyMessage{
+			Emoji:       "🔍",
+			Title:       "AI CLI 不可用",
+			Description: err.Message,
+			Suggestion:  fmt.Sprintf("请安装 AI CLI，或通过 ai_cli.command 或环境变量 %s 指定其路径。", envVar),
+			Command:     "morty config set ai_cli.command <path>",
+		}
+	}
+
+	msg := &FriendlyMessage{
 		Emoji:       "⚙️ ",
 		Title:       "配置错误",
 		Description: err.Message,
-		Suggestion:  "请检查 morty.yaml 配置文件。",
-		Command:     "morty config --validate",
+		Suggestion:  "请检查 .morty/settings.json 配置文件。",
+		Command:     "morty config validate",
+	}
+	if key, ok := err.Context["config_key"].(string); ok && key != "" {
+		msg.Command = "morty config explain " + key
+	}
+	return msg
+}
+
+// restartCommand returns the command that resets a "module/job", or all
+// jobs when job is empty.
+func restartCommand(job string) string {
+	module, name, ok := strings.Cut(job, "/")
+	if !ok {
+		return "morty doing --restart"
 	}
+	return fmt.Sprintf("morty doing --restart --module %s --job %s", module, name)
 }
 
 func getTransientMessage(err *DoingError) *FriendlyMessage {
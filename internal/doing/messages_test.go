@@ -2,6 +2,7 @@ package doing
 
 import (
 	"errors"
+	"os/exec"
 	"strings"
 	"testing"
 )
@@ -300,6 +301,26 @@ func TestGetQuickFix(t *testing.T) {
 			err:      NewDoingError(ErrorCategoryExecution, "test", nil).WithContext("error_type", "execution_failed"),
 			expected: "morty doing --restart",
 		},
+		{
+			name:     "AI CLI not found",
+			err:      &exec.Error{Name: "claude", Err: exec.ErrNotFound},
+			expected: "morty config set ai_cli.command",
+		},
+		{
+			name:     "git index locked",
+			err:      errors.New("git: Unable to create '.git/index.lock': File exists"),
+			expected: "rm .git/index.lock",
+		},
+		{
+			name:     "stale running job",
+			err:      NewDoingError(ErrorCategoryState, "test", nil).WithContext("error_type", "state_stale_running").WithContext("job", "auth/login"),
+			expected: "morty doing --restart --module auth --job login",
+		},
+		{
+			name:     "invalid config key",
+			err:      NewDoingError(ErrorCategoryConfig, "test", nil).WithContext("config_key", "prompts.dir"),
+			expected: "morty config explain prompts.dir",
+		},
 	}
 
 	for _, tt := range tests {
@@ -123,7 +123,7 @@ func (sr *StateRecovery) RestoreFromRecovery(recoveryPoint *RecoveryPoint) error
 	}
 
 	// Update the job state
-	err := sr.stateManager.UpdateJobStatus(recoveryPoint.Module, recoveryPoint.Job, recoveryPoint.JobStatus)
+	err := sr.stateManager.UpdateJobStatusByName(recoveryPoint.Module, recoveryPoint.Job, recoveryPoint.JobStatus)
 	if err != nil {
 		return fmt.Errorf("failed to restore job status: %w", err)
 	}
@@ -142,7 +142,7 @@ func (sr *StateRecovery) RestoreFromRecovery(recoveryPoint *RecoveryPoint) error
 	jobState.UpdatedAt = time.Now()
 
 	// Save the state
-	if err := sr.stateManager.Save(); err != nil {
+	if err := sr.stateManager.Save(sr.stateManager.GetStatus()); err != nil {
 		return fmt.Errorf("failed to save restored state: %w", err)
 	}
 
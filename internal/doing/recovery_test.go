@@ -19,24 +19,26 @@ func setupTestStateRecovery(t *testing.T) (*StateRecovery, *state.Manager, strin
 	stateFile := filepath.Join(tmpDir, "status.json")
 	stateManager := state.NewManager(stateFile)
 
-	// Load to create default state
-	stateManager.Load()
-
-	// Initialize the Modules map and add test data
-	stateManager.GetState().Modules["test-module"] = &state.ModuleState{
-		Name:   "test-module",
-		Status: state.StatusRunning,
-		Jobs: map[string]*state.JobState{
-			"test-job": {
-				Name:           "test-job",
-				Status:         state.StatusRunning,
-				LoopCount:      1,
-				RetryCount:     0,
-				TasksCompleted: 2,
-				TasksTotal:     5,
+	// Save initial state with test data
+	stateManager.Save(&state.ExecutionStatus{
+		Version: "2.0",
+		Modules: []state.ModuleState{
+			{
+				Name:   "test-module",
+				Status: state.StatusRunning,
+				Jobs: []state.JobState{
+					{
+						Name:           "test-job",
+						Status:         state.StatusRunning,
+						LoopCount:      1,
+						RetryCount:     0,
+						TasksCompleted: 2,
+						TasksTotal:     5,
+					},
+				},
 			},
 		},
-	}
+	})
 
 	recovery := NewStateRecovery(logger, tmpDir, stateManager)
 
@@ -304,7 +306,7 @@ func TestStateRecovery_AutoRecover_State(t *testing.T) {
 	}
 
 	// Change the state
-	stateManager.UpdateJobStatus("test-module", "test-job", state.StatusFailed)
+	stateManager.UpdateJobStatusByName("test-module", "test-job", state.StatusFailed)
 
 	// Create a state error
 	testErr := NewDoingError(ErrorCategoryState, "state corrupted", nil)
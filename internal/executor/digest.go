@@ -0,0 +1,337 @@
+package executor
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/morty/morty/internal/callcli"
+	"github.com/morty/morty/internal/git"
+	"github.com/morty/morty/internal/logging"
+	"github.com/morty/morty/internal/state"
+)
+
+// maxDigestFileSize is the size above which a changed file is not scanned
+// for symbols.
+const maxDigestFileSize = 1 << 20
+
+// declPattern matches declarations of common languages other than Go on a
+// single line; the generic fallback for exported symbols.
+var declPattern = regexp.MustCompile(`^\s*(?:export\s+(?:default\s+)?)?(?:pub(?:\([a-z]+\))?\s+)?(?:(?:public|abstract|final|async|static)\s+)*(function|class|interface|def|fn|struct|enum|trait|type|module)\s+([A-Za-z][A-Za-z0-9_]*)`)
+
+// agentSummary returns the summary of the agent's RALPH_STATUS block in
+// the output of a job, or "" when there is none. For a JSON event stream
+// the block is looked up in the final result.
+func (e *engine) agentSummary(stdout string, conversation *callcli.ConversationData) string {
+	content := stdout
+	if conversation != nil {
+		for _, event := range conversation.Events {
+			if event.Type == "result" && event.Result != "" {
+				content = event.Result
+			}
+		}
+	}
+
+	rp := &resultParser{logger: e.logger}
+	block, err := rp.extractRALPHStatus(content)
+	if err != nil || block == "" {
+		return ""
+	}
+	status, err := rp.parseRALPHStatus(block)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(status.Summary)
+}
+
+// snapshotJobStart records the working tree before the agent runs, so the
+// job digest covers exactly the job's changes, including files the agent
+// committed itself and leaving out changes that were already there.
+// Failures are only logged; the digest then falls back to HEAD.
+func (e *engine) snapshotJobStart(module, job string) {
+	e.jobSnapshot = ""
+	if e.gitManager == nil {
+		return
+	}
+	tree, err := e.gitManager.SnapshotTree(e.commitDir())
+	if err != nil {
+		e.logger.Warn("Failed to snapshot the working tree for the job digest",
+			logging.String("module", module),
+			logging.String("job", job),
+			logging.String("error", err.Error()),
+		)
+		return
+	}
+	e.jobSnapshot = tree
+}
+
+// recordDigest stores the digest of a completed job: the files changed
+// since the job started, the exported symbols they add and the agent's
+// summary. Failures are only logged.
+func (e *engine) recordDigest(module, job, summary string) {
+	digest := &state.JobDigest{Summary: summary, CreatedAt: time.Now()}
+
+	if e.gitManager != nil {
+		files, symbols, err := changeDigest(e.gitManager, e.commitDir(), e.jobSnapshot)
+		if err != nil {
+			e.logger.Warn("Failed to collect changes for the job digest",
+				logging.String("module", module),
+				logging.String("job", job),
+				logging.String("error", err.Error()),
+			)
+		}
+		digest.Files = files
+		digest.Symbols = symbols
+	}
+
+	if err := e.stateManager.SetJobDigest(module, job, digest); err != nil {
+		e.logger.Warn("Failed to record job digest",
+			logging.String("module", module),
+			logging.String("job", job),
+			logging.String("error", err.Error()),
+		)
+		return
+	}
+	e.logger.Info("Job digest recorded",
+		logging.String("module", module),
+		logging.String("job", job),
+		logging.Int("files", len(digest.Files)),
+		logging.Int("symbols", len(digest.Symbols)),
+	)
+}
+
+// changeDigest lists the files changed in dir between the tree snapshot
+// base and the current working tree as "<status> <path>", and the exported
+// symbols the changes add as "<path>: <declaration>". Without a snapshot
+// the changes are taken relative to HEAD. Files below .morty are skipped.
+func changeDigest(gm *git.Manager, dir, base string) ([]string, []string, error) {
+	if base == "" {
+		base = "HEAD"
+	}
+	current, err := gm.SnapshotTree(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	diff, err := gm.RunGitCommand(dir, "diff", "--name-status", "--relative", base, current)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to diff against %s: %w", base, err)
+	}
+
+	var files, symbols []string
+	for _, line := range strings.Split(diff, "\n") {
+		// "M\tpath", or "R100\told\tnew" for renames and copies
+		fields := strings.Split(line, "\t")
+		if len(fields) < 2 {
+			continue
+		}
+		code, path := fields[0][:1], fields[len(fields)-1]
+		if strings.HasPrefix(path, ".morty/") {
+			continue
+		}
+		files = append(files, code+" "+path)
+		if code == "D" {
+			continue
+		}
+		for _, decl := range addedSymbols(gm, dir, base, path, code == "A") {
+			symbols = append(symbols, path+": "+decl)
+		}
+	}
+	return files, symbols, nil
+}
+
+// addedSymbols returns the exported declarations of path that are not in
+// its version in base. Go files are compared with go/ast; other files are
+// scanned line by line with declPattern.
+func addedSymbols(gm *git.Manager, dir, base, path string, added bool) []string {
+	current, err := os.ReadFile(filepath.Join(dir, path))
+	if err != nil || len(current) > maxDigestFileSize || bytes.IndexByte(current, 0) >= 0 {
+		return nil
+	}
+	var previous string
+	if !added {
+		previous, _ = gm.RunGitCommand(dir, "show", base+":./"+path)
+	}
+
+	if strings.HasSuffix(path, ".go") {
+		if strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		decls, err := goDecls(current)
+		if err == nil {
+			old, _ := goDecls([]byte(previous))
+			return subtract(decls, old)
+		}
+	}
+	return subtract(genericDecls(string(current)), genericDecls(previous))
+}
+
+// goDecls returns the exported top-level declarations of a Go file, with
+// methods written as "func (T) Name" or "func (*T) Name".
+func goDecls(src []byte) ([]string, error) {
+	if len(src) == 0 {
+		return nil, nil
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), "", src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	var decls []string
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if !d.Name.IsExported() {
+				continue
+			}
+			if d.Recv == nil || len(d.Recv.List) == 0 {
+				decls = append(decls, "func "+d.Name.Name)
+				continue
+			}
+			recv, exported := receiverName(d.Recv.List[0].Type)
+			if exported {
+				decls = append(decls, fmt.Sprintf("func (%s) %s", recv, d.Name.Name))
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					if s.Name.IsExported() {
+						decls = append(decls, "type "+s.Name.Name)
+					}
+				case *ast.ValueSpec:
+					for _, name := range s.Names {
+						if name.IsExported() {
+							decls = append(decls, d.Tok.String()+" "+name.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+	return decls, nil
+}
+
+// receiverName returns the receiver type of a method as written, without
+// type parameters, and whether the type is exported.
+func receiverName(expr ast.Expr) (string, bool) {
+	prefix := ""
+	if star, ok := expr.(*ast.StarExpr); ok {
+		prefix = "*"
+		expr = star.X
+	}
+	switch t := expr.(type) {
+	case *ast.IndexExpr:
+		expr = t.X
+	case *ast.IndexListExpr:
+		expr = t.X
+	}
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return "", false
+	}
+	return prefix + ident.Name, ident.IsExported()
+}
+
+// genericDecls returns the declarations declPattern finds in content.
+func genericDecls(content string) []string {
+	var decls []string
+	for _, line := range strings.Split(content, "\n") {
+		if m := declPattern.FindStringSubmatch(line); m != nil {
+			decls = append(decls, m[1]+" "+m[2])
+		}
+	}
+	return decls
+}
+
+// subtract returns the sorted, distinct entries of a that are not in b.
+func subtract(a, b []string) []string {
+	seen := make(map[string]bool, len(b))
+	for _, s := range b {
+		seen[s] = true
+	}
+	var result []string
+	for _, s := range a {
+		if !seen[s] {
+			seen[s] = true
+			result = append(result, s)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// digestContext adds the digests of other completed jobs to the prompt
+// data, most relevant first, until the token budget is spent: jobs of the
+// module itself and of the modules it depends on come before the rest, and
+// recent jobs before older ones. Tokens are estimated as bytes / 4.
+func (e *engine) digestContext(data map[string]interface{}, module, job string) {
+	data["digests"] = []map[string]interface{}{}
+	data["digests_total"] = 0
+	execStatus := e.stateManager.GetStatus()
+	if execStatus == nil || e.config.DigestBudget <= 0 {
+		return
+	}
+
+	related := map[string]bool{module: true}
+	if current := execStatus.GetModuleByName(module); current != nil {
+		for _, dep := range current.Dependencies {
+			related[dep] = true
+		}
+	}
+
+	type candidate struct {
+		module  string
+		job     *state.JobState
+		related bool
+	}
+	var candidates []candidate
+	for i := range execStatus.Modules {
+		mod := &execStatus.Modules[i]
+		for j := range mod.Jobs {
+			jobState := &mod.Jobs[j]
+			if jobState.Digest == nil || jobState.Status != state.StatusCompleted || (mod.Name == module && jobState.Name == job) {
+				continue
+			}
+			candidates = append(candidates, candidate{module: mod.Name, job: jobState, related: related[mod.Name]})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].related != candidates[j].related {
+			return candidates[i].related
+		}
+		return candidates[i].job.Digest.CreatedAt.After(candidates[j].job.Digest.CreatedAt)
+	})
+
+	budget := e.config.DigestBudget
+	var digests []map[string]interface{}
+	for _, c := range candidates {
+		digest := c.job.Digest
+		size := len(c.module) + len(c.job.Name) + len(digest.Summary) + 40
+		for _, s := range append(append([]string{}, digest.Files...), digest.Symbols...) {
+			size += len(s) + 2
+		}
+		if tokens := size / 4; tokens <= budget {
+			budget -= tokens
+			digests = append(digests, map[string]interface{}{
+				"module":  c.module,
+				"job":     c.job.Name,
+				"files":   digest.Files,
+				"symbols": digest.Symbols,
+				"summary": digest.Summary,
+			})
+		}
+	}
+
+	if len(digests) > 0 {
+		data["digests"] = digests
+	}
+	data["digests_total"] = len(candidates)
+}
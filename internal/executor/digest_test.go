@@ -0,0 +1,183 @@
+package executor
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/morty/morty/internal/callcli"
+	"github.com/morty/morty/internal/git"
+	"github.com/morty/morty/internal/state"
+)
+
+// runGit runs a git command in dir and fails the test on error.
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
+
+// writeFile writes content to name below dir.
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGoDecls(t *testing.T) {
+	decls, err := goDecls([]byte(`package auth
+
+type Token struct{}
+type claims struct{}
+
+const MaxAge, minAge = 10, 1
+
+var ErrExpired error
+
+func NewToken() *Token { return nil }
+func (t *Token) Valid() bool { return true }
+func (c claims) Valid() bool { return true }
+func (s Set[K]) Has(k K) bool { return true }
+func helper() {}
+`))
+	if err != nil {
+		t.Fatalf("goDecls() error: %v", err)
+	}
+	want := []string{"type Token", "const MaxAge", "var ErrExpired", "func NewToken", "func (*Token) Valid", "func (Set) Has"}
+	if !reflect.DeepEqual(decls, want) {
+		t.Errorf("goDecls() = %q, want %q", decls, want)
+	}
+}
+
+func TestChangeDigest(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q")
+	writeFile(t, dir, "auth/token.go", "package auth\n\nfunc Parse() {}\n")
+	writeFile(t, dir, "web/app.js", "export function login() {}\n")
+	writeFile(t, dir, "README.md", "# app\n")
+	runGit(t, dir, "add", "-A")
+	runGit(t, dir, "commit", "-q", "-m", "init")
+	// Left over from before the job; not part of its digest
+	writeFile(t, dir, "notes.txt", "todo\n")
+
+	gm := git.NewManager()
+	base, err := gm.SnapshotTree(dir)
+	if err != nil {
+		t.Fatalf("SnapshotTree() error: %v", err)
+	}
+
+	writeFile(t, dir, "auth/token.go", "package auth\n\nfunc Parse() {}\n\nfunc Refresh() {}\n")
+	writeFile(t, dir, "auth/token_test.go", "package auth\n\nfunc TestRefresh() {}\n")
+	// Committed by the agent during the job
+	writeFile(t, dir, "web/app.js", "export function login() {}\nexport class Session {}\n")
+	runGit(t, dir, "commit", "-q", "-am", "agent")
+	writeFile(t, dir, "web/api.py", "def fetch():\n    pass\n")
+	writeFile(t, dir, ".morty/status.json", "{}")
+	if err := os.Remove(filepath.Join(dir, "README.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	files, symbols, err := changeDigest(gm, dir, base)
+	if err != nil {
+		t.Fatalf("changeDigest() error: %v", err)
+	}
+	wantFiles := []string{"D README.md", "M auth/token.go", "A auth/token_test.go", "A web/api.py", "M web/app.js"}
+	if !reflect.DeepEqual(files, wantFiles) {
+		t.Errorf("files = %q, want %q", files, wantFiles)
+	}
+	wantSymbols := []string{"auth/token.go: func Refresh", "web/api.py: def fetch", "web/app.js: class Session"}
+	if !reflect.DeepEqual(symbols, wantSymbols) {
+		t.Errorf("symbols = %q, want %q", symbols, wantSymbols)
+	}
+
+	// Without a snapshot the changes are taken relative to HEAD
+	files, _, err = changeDigest(gm, dir, "")
+	if err != nil {
+		t.Fatalf("changeDigest() without a snapshot error: %v", err)
+	}
+	wantFiles = []string{"D README.md", "A auth/token_test.go", "A notes.txt", "A web/api.py"}
+	if !reflect.DeepEqual(files, wantFiles) {
+		t.Errorf("files without a snapshot = %q, want %q", files, wantFiles)
+	}
+}
+
+func TestEngine_AgentSummary(t *testing.T) {
+	e := &engine{logger: &mockLogger{}}
+	stdout := "done\n<!-- RALPH_STATUS -->\n{\"status\": \"COMPLETED\", \"summary\": \"Added token refresh\"}\n<!-- END_RALPH_STATUS -->\n"
+	if got := e.agentSummary(stdout, nil); got != "Added token refresh" {
+		t.Errorf("agentSummary() = %q", got)
+	}
+
+	conversation := &callcli.ConversationData{Events: []callcli.Event{{Type: "result", Result: stdout}}}
+	if got := e.agentSummary(`[{"type":"result"}]`, conversation); got != "Added token refresh" {
+		t.Errorf("agentSummary() from the event stream = %q", got)
+	}
+
+	if got := e.agentSummary("no status block", nil); got != "" {
+		t.Errorf("agentSummary() without a block = %q", got)
+	}
+}
+
+func TestEngine_DigestContext(t *testing.T) {
+	now := time.Now()
+	digest := func(summary string, age time.Duration) *state.JobDigest {
+		return &state.JobDigest{Summary: summary, Files: []string{"A " + summary + ".go"}, CreatedAt: now.Add(-age)}
+	}
+	stateManager := state.NewManager(filepath.Join(t.TempDir(), "status.json"))
+	if err := stateManager.Save(&state.ExecutionStatus{Modules: []state.ModuleState{
+		{Name: "core", Jobs: []state.JobState{
+			{Name: "types", Status: state.StatusCompleted, Digest: digest("types", 3*time.Hour)},
+			{Name: "pending", Status: state.StatusPending, Digest: digest("pending", 0)},
+		}},
+		{Name: "docs", Jobs: []state.JobState{
+			{Name: "readme", Status: state.StatusCompleted, Digest: digest("readme", time.Minute)},
+		}},
+		{Name: "api", Dependencies: []string{"core"}, Jobs: []state.JobState{
+			{Name: "routes", Status: state.StatusCompleted, Digest: digest("routes", 2*time.Hour)},
+			{Name: "handlers", Status: state.StatusRunning},
+		}},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	e := &engine{stateManager: stateManager, logger: &mockLogger{}, config: &Config{DigestBudget: 1000}}
+	data := map[string]interface{}{}
+	e.digestContext(data, "api", "handlers")
+
+	var got []string
+	for _, d := range data["digests"].([]map[string]interface{}) {
+		got = append(got, d["module"].(string)+"/"+d["job"].(string))
+	}
+	if want := []string{"api/routes", "core/types", "docs/readme"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("digests = %q, want %q", got, want)
+	}
+	if data["digests_total"] != 3 {
+		t.Errorf("digests_total = %v", data["digests_total"])
+	}
+
+	e.config.DigestBudget = 40
+	data = map[string]interface{}{}
+	e.digestContext(data, "api", "handlers")
+	if digests := data["digests"].([]map[string]interface{}); len(digests) != 2 || digests[0]["job"] != "routes" {
+		t.Errorf("digests within a small budget = %v", digests)
+	}
+}
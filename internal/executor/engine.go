@@ -5,6 +5,7 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -12,10 +13,16 @@ import (
 	"time"
 
 	"github.com/morty/morty/internal/callcli"
+	"github.com/morty/morty/internal/doing"
 	"github.com/morty/morty/internal/git"
 	"github.com/morty/morty/internal/logging"
+	"github.com/morty/morty/internal/metrics"
 	"github.com/morty/morty/internal/parser/plan"
+	"github.com/morty/morty/internal/parser/prompt"
+	"github.com/morty/morty/internal/redact"
+	"github.com/morty/morty/internal/research"
 	"github.com/morty/morty/internal/state"
+	"github.com/morty/morty/internal/tracing"
 )
 
 // Engine defines the interface for job execution.
@@ -34,6 +41,28 @@ type Engine interface {
 	ResumeJob(ctx context.Context, module, job string) error
 }
 
+// cliGracePeriod is how long an interrupted AI CLI has to exit after SIGTERM
+// before it is killed.
+const cliGracePeriod = 10 * time.Second
+
+// InterruptedError is returned by ExecuteJob when cancelling its context
+// interrupted the job. The job is left RUNNING for the caller to record the
+// interrupt with state.Manager.InterruptJob.
+type InterruptedError struct {
+	// Interrupt describes the interrupted AI CLI call, or is nil when the
+	// caller does not run the CLI through a callcli.SignalHandler
+	Interrupt *callcli.InterruptState
+	Err       error
+}
+
+func (e *InterruptedError) Error() string {
+	return "job interrupted: " + e.Err.Error()
+}
+
+func (e *InterruptedError) Unwrap() error {
+	return e.Err
+}
+
 // Config holds the configuration for the executor engine.
 type Config struct {
 	// MaxRetries is the maximum number of retry attempts for a failed job.
@@ -48,6 +77,31 @@ type Config struct {
 	PromptsDir string
 	// PlanDir is the directory containing plan files.
 	PlanDir string
+	// Language selects localized prompt templates (e.g. doing.en.md).
+	Language string
+	// Prompts renders the job prompts (nil renders them from PromptsDir and
+	// the built-in templates).
+	Prompts *prompt.Engine
+	// ResearchDir is the directory of research documents searched for job
+	// context (empty disables research context).
+	ResearchDir string
+	// ResearchTopK is the number of research sections added to a job prompt.
+	ResearchTopK int
+	// DigestBudget is the number of tokens of completed job digests added
+	// to a job prompt (0 leaves them out).
+	DigestBudget int
+	// FreshSession starts every retry in a new AI CLI session instead of
+	// resuming the session of the failed attempt.
+	FreshSession bool
+	// CommitScanner scans staged changes before auto-commit (nil disables scanning).
+	CommitScanner *git.Scanner
+	// Metrics records job, AI CLI and commit metrics (nil disables metrics).
+	Metrics *metrics.Recorder
+	// ExecutionLogger records every AI CLI execution (nil disables it).
+	ExecutionLogger *callcli.ExecutionLogger
+	// RedactionCanary is a fake secret appended to every job prompt by the
+	// redaction test mode (empty disables it).
+	RedactionCanary string
 }
 
 // DefaultConfig returns the default executor configuration.
@@ -87,6 +141,15 @@ type engine struct {
 	logger       logging.Logger
 	config       *Config
 	cliCaller    callcli.AICliCaller
+	// jobLogPath is the log file of the job currently being executed.
+	jobLogPath string
+	// jobSummary is the agent's summary of the job currently being executed.
+	jobSummary string
+	// jobSnapshot is the working tree at the start of the current job, the
+	// base of its digest.
+	jobSnapshot string
+	// sessionRecorded is set once the current attempt's session is recorded.
+	sessionRecorded bool
 }
 
 // NewEngine creates a new execution engine with the given dependencies.
@@ -117,29 +180,55 @@ func NewEngine(
 // 4. Handle failures with retry logic
 // 5. Transition to COMPLETED or FAILED
 // 6. Create Git commit if configured
-func (e *engine) ExecuteJob(ctx context.Context, module, job string) error {
+func (e *engine) ExecuteJob(ctx context.Context, module, job string) (err error) {
+	ctx, span := tracing.Start(ctx, "job.attempt",
+		logging.String("morty.module", module),
+		logging.String("morty.job", job),
+	)
+	defer func() { span.End(err) }()
+
+	// The log of the previous job must not receive this job's scan findings
+	e.jobLogPath = ""
+
+	// Attempts are timed from the transition to RUNNING
+	var started time.Time
+	defer func() {
+		if started.IsZero() {
+			return
+		}
+		status := state.StatusCompleted
+		var interrupted *InterruptedError
+		if errors.As(err, &interrupted) {
+			status = state.StatusInterrupted
+		} else if err != nil {
+			status = state.StatusFailed
+		}
+		e.config.Metrics.JobFinished(string(status), time.Since(started))
+	}()
+
 	e.logger.Info("Starting job execution",
 		logging.String("module", module),
 		logging.String("job", job),
 	)
 
-	// Step 1: Check prerequisites (V2: skipped - order guaranteed by topological sort)
-	// In V2 format, modules and jobs are already sorted topologically at generation time,
-	// so we don't need runtime prerequisite checking.
-	// if err := e.checkPrerequisites(ctx, module, job); err != nil {
-	// 	e.logger.Error("Prerequisite check failed",
-	// 		logging.String("module", module),
-	// 		logging.String("job", job),
-	// 		logging.String("error", err.Error()),
-	// 	)
-	// 	return fmt.Errorf("prerequisite check failed: %w", err)
-	// }
+	// Step 1: Check prerequisites. In V2 format modules and jobs are sorted
+	// topologically at generation time, so this only checks that the job's
+	// status allows it to start.
+	if err := e.checkPrerequisites(ctx, module, job); err != nil {
+		e.logger.Error("Prerequisite check failed",
+			logging.String("module", module),
+			logging.String("job", job),
+			logging.String("error", err.Error()),
+		)
+		return fmt.Errorf("prerequisite check failed: %w", err)
+	}
 
 	// Get job state to check current status and retry count
 	jobState, err := e.getJobState(module, job)
 	if err != nil {
 		return err
 	}
+	span.SetAttributes(logging.Int("morty.attempt", jobState.RetryCount+1))
 
 	// Check if we've exceeded max retries for failed jobs
 	if jobState.Status == state.StatusFailed {
@@ -155,12 +244,20 @@ func (e *engine) ExecuteJob(ctx context.Context, module, job string) error {
 		if err := e.transitionState(module, job, state.StatusPending); err != nil {
 			return fmt.Errorf("failed to transition from FAILED to PENDING for retry: %w", err)
 		}
+		e.config.Metrics.JobRetried()
+	} else if jobState.Status == state.StatusInterrupted {
+		e.logger.Info("Resuming interrupted job",
+			logging.String("module", module),
+			logging.String("job", job),
+			logging.String("reason", jobState.FailureReason),
+		)
 	}
 
 	// Step 2: Transition to RUNNING
 	if err := e.transitionState(module, job, state.StatusRunning); err != nil {
 		return fmt.Errorf("failed to transition to RUNNING: %w", err)
 	}
+	started = time.Now()
 
 	// Set as current job
 	if err := e.stateManager.SetCurrent(module, job, state.StatusRunning); err != nil {
@@ -168,9 +265,21 @@ func (e *engine) ExecuteJob(ctx context.Context, module, job string) error {
 	}
 
 	// Step 3: Execute tasks
+	e.sessionRecorded = false
+	e.snapshotJobStart(module, job)
 	tasksCompleted, err := e.executeTasks(ctx, module, job)
 
 	// Step 4 & 5: Handle result and state transition
+	var interrupted *InterruptedError
+	if errors.As(err, &interrupted) {
+		e.logger.Warn("Job execution interrupted",
+			logging.String("module", module),
+			logging.String("job", job),
+		)
+		// The job stays RUNNING until the caller has saved its progress
+		e.failSession(module, job, err)
+		return err
+	}
 	if err != nil {
 		e.logger.Error("Job execution failed",
 			logging.String("module", module),
@@ -187,12 +296,26 @@ func (e *engine) ExecuteJob(ctx context.Context, module, job string) error {
 		if updateErr := e.updateFailureReason(module, job, err.Error()); updateErr != nil {
 			e.logger.Warn("Failed to update failure reason", logging.String("error", updateErr.Error()))
 		}
+		e.failSession(module, job, err)
 
 		return fmt.Errorf("job execution failed: %w", err)
 	}
 
+	// A commit the pre-commit scan would block fails the job while it is still RUNNING
+	if e.config.AutoCommit {
+		_, scanSpan := tracing.Start(ctx, "git.scan")
+		err := e.scanChanges(module, job)
+		scanSpan.End(err)
+		if err != nil {
+			return e.failJobOnScan(module, job, err)
+		}
+	}
+
 	// Step 5: Verify completion marking in plan file before transitioning to COMPLETED
+	_, verifySpan := tracing.Start(ctx, "validator.verify_completion")
 	completionVerified, err := e.verifyJobCompletionInPlan(module, job)
+	verifySpan.SetAttributes(logging.Bool("morty.verified", completionVerified))
+	verifySpan.End(err)
 	if err != nil {
 		e.logger.Warn("Failed to verify job completion in plan file",
 			logging.String("error", err.Error()),
@@ -219,6 +342,9 @@ func (e *engine) ExecuteJob(ctx context.Context, module, job string) error {
 		e.logger.Warn("Failed to clear current job", logging.String("error", err.Error()))
 	}
 
+	// Record what the job produced for the prompts of later jobs
+	e.recordDigest(module, job, e.jobSummary)
+
 	e.logger.Info("Job completed successfully",
 		logging.String("module", module),
 		logging.String("job", job),
@@ -227,8 +353,11 @@ func (e *engine) ExecuteJob(ctx context.Context, module, job string) error {
 
 	// Step 6: Create Git commit
 	if e.config.AutoCommit {
-		if err := e.createGitCommit(module, job); err != nil {
-			// Log warning but don't fail the job
+		_, commitSpan := tracing.Start(ctx, "git.commit")
+		err := e.createGitCommit(module, job)
+		commitSpan.End(err)
+		if err != nil {
+			// The job is already COMPLETED, so commit errors are only warnings
 			e.logger.Warn("Failed to create Git commit",
 				logging.String("error", err.Error()),
 			)
@@ -238,6 +367,50 @@ func (e *engine) ExecuteJob(ctx context.Context, module, job string) error {
 	return nil
 }
 
+// scanChanges runs the pre-commit scan over the job's changes before the job is
+// marked COMPLETED. It returns a git error when the scanner would block the commit.
+func (e *engine) scanChanges(module, job string) error {
+	if e.gitManager == nil || e.config.CommitScanner == nil {
+		return nil
+	}
+
+	result, err := e.gitManager.ScanChanges(e.commitDir(), e.config.CommitScanner)
+	var scanErr *git.ScanError
+	if !errors.As(err, &scanErr) {
+		// Other scan errors surface again when the commit is created
+		if err != nil {
+			e.logger.Warn("Pre-commit scan failed", logging.String("error", err.Error()))
+		}
+		return nil
+	}
+
+	e.appendScanFindings(module, job, result)
+	return doing.NewDoingError(doing.ErrorCategoryGit, "提交被预提交扫描阻止", err).
+		WithContext("module", module).
+		WithContext("job", job).
+		WithContext("findings", result.Findings)
+}
+
+// failJobOnScan marks a running job as FAILED after the pre-commit scan blocked its commit.
+func (e *engine) failJobOnScan(module, job string, err error) error {
+	e.logger.Error("Git commit blocked by pre-commit scan",
+		logging.String("module", module),
+		logging.String("job", job),
+		logging.String("error", err.Error()),
+	)
+
+	if transErr := e.transitionState(module, job, state.StatusFailed); transErr != nil {
+		e.logger.Error("Failed to transition to FAILED", logging.String("error", transErr.Error()))
+	}
+
+	if updateErr := e.stateManager.UpdateFailureReason(module, job, err.Error()); updateErr != nil {
+		e.logger.Warn("Failed to update failure reason", logging.String("error", updateErr.Error()))
+	}
+	e.failSession(module, job, err)
+
+	return fmt.Errorf("job execution failed: %w", err)
+}
+
 // ExecuteTask executes a single task.
 // This is a placeholder implementation that would be extended by TaskRunner.
 func (e *engine) ExecuteTask(ctx context.Context, module, job string, taskIndex int, taskDesc string) error {
@@ -254,23 +427,30 @@ func (e *engine) ExecuteTask(ctx context.Context, module, job string, taskIndex
 		return fmt.Errorf("failed to build task prompt: %w", err)
 	}
 
+	run, timeout, err := e.runOptions(module, job)
+	if err != nil {
+		return err
+	}
+
 	// Execute the task using AI CLI (doing mode - non-interactive)
 	opts := callcli.Options{
-		Timeout:    0, // No timeout for task execution
+		Timeout:    timeout, // No timeout unless the plan sets one
 		Stdin:      prompt,
 		WorkingDir: e.config.WorkingDir,
+		// Ctrl-C is handled by morty doing, which cancels ctx to interrupt the CLI
+		DetachSignals:  true,
+		GracefulPeriod: cliGracePeriod,
 		Output: callcli.OutputConfig{
 			Mode: callcli.OutputStream, // Stream output to terminal
 		},
 	}
 
-	// Build args for non-interactive mode (doing)
-	// Use bypassPermissions mode for automated execution
-	baseArgs := e.cliCaller.BuildArgs()
-	args := append([]string{"--permission-mode", "bypassPermissions", "-p"}, baseArgs...)
+	// Build args for non-interactive mode (doing); the plan may select the
+	// model, permission mode and tools
+	args := run.Apply(append([]string{"-p"}, e.cliCaller.BuildArgs()...))
 
 	// Execute the command
-	result, err := e.cliCaller.GetBaseCaller().CallWithOptions(ctx, e.cliCaller.GetCLIPath(), args, opts)
+	result, _, err := e.callCLI(ctx, args, opts)
 
 	if err != nil {
 		e.logger.Error("Task execution failed",
@@ -304,7 +484,7 @@ func (e *engine) ResumeJob(ctx context.Context, module, job string) error {
 	}
 
 	// Check if job can be resumed
-	if jobState.Status != state.StatusRunning && jobState.Status != state.StatusFailed {
+	if jobState.Status != state.StatusRunning && jobState.Status != state.StatusFailed && jobState.Status != state.StatusInterrupted {
 		return fmt.Errorf("job cannot be resumed: current status is %s", jobState.Status)
 	}
 
@@ -322,7 +502,7 @@ func (e *engine) checkPrerequisites(ctx context.Context, module, job string) err
 
 	// Check if job is in a valid state to start
 	switch jobState.Status {
-	case state.StatusPending, state.StatusFailed:
+	case state.StatusPending, state.StatusFailed, state.StatusInterrupted:
 		// Can start from these states
 	case state.StatusCompleted:
 		return fmt.Errorf("job already completed")
@@ -391,23 +571,43 @@ func (e *engine) executeTasks(ctx context.Context, module, job string) (int, err
 		logFile = nil
 	} else {
 		defer logFile.Close()
+		e.jobLogPath = logFilePath
 		e.logger.Info("Job log file created",
 			logging.String("log_file", logFilePath),
 		)
 	}
 
-	// Build comprehensive job-level prompt
-	prompt, err := e.buildJobPrompt(module, job)
+	// Build comprehensive job-level prompt, or a follow-up when resuming the
+	// session of a failed attempt
+	resume := e.resumableSession(jobState)
+	_, promptSpan := tracing.Start(ctx, "prompt.build")
+	var prompt string
+	if resume != nil {
+		e.logger.Info("Resuming session of the failed attempt",
+			logging.String("module", module),
+			logging.String("job", job),
+			logging.String("session_id", resume.ID),
+			logging.Int("attempt", resume.Attempt),
+		)
+		prompt, err = e.buildResumePrompt(module, job, jobState, resume)
+	} else {
+		prompt, err = e.buildJobPrompt(module, job)
+	}
+	promptSpan.SetAttributes(logging.Int("morty.prompt_bytes", len(prompt)))
+	promptSpan.End(err)
 	if err != nil {
 		return 0, fmt.Errorf("failed to build job prompt: %w", err)
 	}
+	if e.config.RedactionCanary != "" {
+		prompt += fmt.Sprintf("\n<!-- redaction test: %s -->\n", e.config.RedactionCanary)
+	}
 
 	// Write prompt to log file for debugging
 	if logFile != nil {
 		promptHeader := "========================================\n"
 		promptHeader += "MORTY JOB PROMPT (for debugging)\n"
 		promptHeader += "========================================\n\n"
-		if _, err := logFile.WriteString(promptHeader + prompt + "\n\n"); err != nil {
+		if _, err := logFile.WriteString(promptHeader + redact.String(prompt) + "\n\n"); err != nil {
 			e.logger.Warn("Failed to write prompt to log file",
 				logging.String("error", err.Error()),
 			)
@@ -422,11 +622,22 @@ func (e *engine) executeTasks(ctx context.Context, module, job string) (int, err
 		}
 	}
 
+	run, timeout, err := e.runOptions(module, job)
+	if err != nil {
+		return 0, err
+	}
+	if resume != nil {
+		run.Resume = resume.ID
+	}
+
 	// Execute the entire job using AI CLI with log file capture
 	opts := callcli.Options{
-		Timeout:    0, // No timeout for job execution
+		Timeout:    timeout, // No timeout unless the plan sets one
 		Stdin:      prompt,
 		WorkingDir: e.config.WorkingDir,
+		// Ctrl-C is handled by morty doing, which cancels ctx to interrupt the CLI
+		DetachSignals:  true,
+		GracefulPeriod: cliGracePeriod,
 		Output: callcli.OutputConfig{
 			Mode: callcli.OutputCapture, // Capture output to memory (don't pollute console)
 		},
@@ -437,25 +648,50 @@ func (e *engine) executeTasks(ctx context.Context, module, job string) (int, err
 		opts.Output.OutputFile = logFilePath
 	}
 
-	// Build args for non-interactive mode (doing)
-	// Use bypassPermissions mode for automated execution
-	baseArgs := e.cliCaller.BuildArgs()
-	args := append([]string{"--permission-mode", "bypassPermissions", "-p"}, baseArgs...)
+	// Build args for non-interactive mode (doing); the plan may select the
+	// model, permission mode and tools
+	args := run.Apply(append([]string{"-p"}, e.cliCaller.BuildArgs()...))
 
 	// Execute the command
-	result, err := e.cliCaller.GetBaseCaller().CallWithOptions(ctx, e.cliCaller.GetCLIPath(), args, opts)
+	cliCtx, cliSpan := tracing.Start(ctx, "ai_cli.call",
+		logging.String("morty.cli_path", e.cliCaller.GetCLIPath()),
+	)
+	started := time.Now()
+	result, interrupt, err := e.callCLI(cliCtx, args, opts)
+	conversation := e.recordCLIExecution(args, opts, result, err)
+	e.recordSession(module, job, jobState, resume != nil, started, result, conversation)
+	if result != nil {
+		cliSpan.SetAttributes(cliResultAttrs(result, conversation)...)
+	}
+	if err == nil && result != nil && result.ExitCode != 0 {
+		cliSpan.End(fmt.Errorf("exit code %d", result.ExitCode))
+	} else {
+		cliSpan.End(err)
+	}
 
 	// Write captured output to log file
 	if logFile != nil && result != nil {
 		e.writeJobLog(logFile, module, job, result.Stdout, result.Stderr, result.ExitCode)
 	}
 
+	// Keep the event stream next to the job log for morty transcript
+	if logFile != nil && conversation != nil {
+		e.saveConversation(logFilePath, result.Stdout)
+	}
+	e.jobSummary = ""
+	if result != nil {
+		e.jobSummary = e.agentSummary(result.Stdout, conversation)
+	}
+
 	if err != nil {
 		e.logger.Error("Job execution failed",
 			logging.String("module", module),
 			logging.String("job", job),
 			logging.String("error", err.Error()),
 		)
+		if ctx.Err() == context.Canceled {
+			return 0, &InterruptedError{Interrupt: interrupt, Err: err}
+		}
 		return 0, fmt.Errorf("job execution failed: %w", err)
 	}
 
@@ -490,6 +726,90 @@ func (e *engine) executeTasks(ctx context.Context, module, job string) (int, err
 	return tasksTotal, nil
 }
 
+// signalCaller is implemented by callers that can run a command through a
+// callcli.SignalHandler.
+type signalCaller interface {
+	CallWithSignal(ctx context.Context, name string, args []string, opts callcli.Options) (*callcli.SignalHandler, error)
+}
+
+// callCLI runs the AI CLI. Callers that support it run the CLI through a
+// callcli.SignalHandler, which terminates its whole process group when ctx
+// is cancelled; the interrupt state is returned for an interrupted call.
+func (e *engine) callCLI(ctx context.Context, args []string, opts callcli.Options) (*callcli.Result, *callcli.InterruptState, error) {
+	caller := e.cliCaller.GetBaseCaller()
+	sc, ok := caller.(signalCaller)
+	if !ok {
+		result, err := caller.CallWithOptions(ctx, e.cliCaller.GetCLIPath(), args, opts)
+		return result, nil, err
+	}
+
+	handler, err := sc.CallWithSignal(ctx, e.cliCaller.GetCLIPath(), args, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	result, err := handler.Wait()
+	if !handler.Interrupted() {
+		return result, nil, err
+	}
+	return result, handler.GetInterruptState(), err
+}
+
+// recordCLIExecution writes an AI CLI execution to the execution log and
+// the metrics. It returns the parsed conversation when the output is a JSON
+// event stream, or nil.
+func (e *engine) recordCLIExecution(args []string, opts callcli.Options, result *callcli.Result, callErr error) *callcli.ConversationData {
+	command := filepath.Base(e.cliCaller.GetCLIPath())
+	var log *callcli.ExecutionLog
+	if result != nil {
+		log = callcli.NewExecutionLogFromResult(result, command, args, opts.WorkingDir, opts.Timeout)
+	} else {
+		log = &callcli.ExecutionLog{Timestamp: time.Now(), Command: command, Args: args, WorkingDir: opts.WorkingDir, ExitCode: -1}
+	}
+	if callErr != nil {
+		log.Success = false
+		log.Error = callErr.Error()
+	}
+
+	if e.config.ExecutionLogger != nil {
+		if err := e.config.ExecutionLogger.LogExecution(log); err != nil {
+			e.logger.Warn("Failed to write execution log", logging.String("error", err.Error()))
+		}
+	}
+	e.config.Metrics.CLIExecuted(log)
+
+	if result == nil || !strings.HasPrefix(strings.TrimSpace(result.Stdout), "[") {
+		return nil
+	}
+	conversation, err := callcli.NewConversationParser("").Parse(result.Stdout)
+	if err != nil {
+		return nil
+	}
+	e.config.Metrics.TokensUsed(conversation.TotalInputTokens, conversation.TotalOutputTokens, conversation.TotalCostUSD)
+	return conversation
+}
+
+// cliResultAttrs returns span attributes of an AI CLI call: its exit code
+// and, when the output was a JSON event stream, token usage and cost.
+func cliResultAttrs(result *callcli.Result, conversation *callcli.ConversationData) []logging.Attr {
+	attrs := []logging.Attr{logging.Int("process.exit_code", result.ExitCode)}
+	if conversation == nil {
+		return attrs
+	}
+	attrs = append(attrs,
+		logging.Int("gen_ai.usage.input_tokens", conversation.TotalInputTokens),
+		logging.Int("gen_ai.usage.output_tokens", conversation.TotalOutputTokens),
+		logging.Int("morty.num_turns", conversation.NumTurns),
+		logging.Any("morty.cost_usd", conversation.TotalCostUSD),
+	)
+	if conversation.Model != "" {
+		attrs = append(attrs, logging.String("gen_ai.response.model", conversation.Model))
+	}
+	if conversation.SessionID != "" {
+		attrs = append(attrs, logging.String("morty.session_id", conversation.SessionID))
+	}
+	return attrs
+}
+
 // markTaskCompleted marks a single task as completed.
 func (e *engine) markTaskCompleted(module, job string, taskIndex int) error {
 	e.logger.Debug("Task marked as completed",
@@ -541,6 +861,20 @@ func (e *engine) getJobState(module, job string) (*state.JobState, error) {
 	return jobState, nil
 }
 
+// commitDir returns the absolute directory of the repository jobs commit to.
+func (e *engine) commitDir() string {
+	workDir := e.config.WorkingDir
+	if workDir == "" {
+		workDir = "."
+	}
+
+	absPath, err := filepath.Abs(workDir)
+	if err != nil {
+		return workDir
+	}
+	return absPath
+}
+
 // createGitCommit creates a Git commit after job completion.
 // It uses the git.Manager's CreateLoopCommit method which handles staging and committing.
 func (e *engine) createGitCommit(module, job string) error {
@@ -553,15 +887,7 @@ func (e *engine) createGitCommitUsingCommitter(module, job string) error {
 		return fmt.Errorf("git manager not available")
 	}
 
-	workDir := e.config.WorkingDir
-	if workDir == "" {
-		workDir = "."
-	}
-
-	absPath, err := filepath.Abs(workDir)
-	if err != nil {
-		absPath = workDir
-	}
+	absPath := e.commitDir()
 
 	// Check if there are changes to commit
 	hasChanges, err := e.gitManager.HasUncommittedChanges(absPath)
@@ -580,10 +906,23 @@ func (e *engine) createGitCommitUsingCommitter(module, job string) error {
 		loopNum = 1
 	}
 
-	// Use CreateLoopCommit with job-specific status
+	// Measured before committing, for the commit size metrics
+	stats, statsErr := e.gitManager.GetChangeStats(absPath)
+
+	// Use CreateScannedLoopCommit with job-specific status
 	status := fmt.Sprintf("%s/%s - COMPLETED", module, job)
-	_, err = e.gitManager.CreateLoopCommit(loopNum, status, absPath)
+	_, scanResult, err := e.gitManager.CreateScannedLoopCommit(loopNum, status, absPath, e.config.CommitScanner)
+	if scanResult.HasFindings() {
+		e.appendScanFindings(module, job, scanResult)
+	}
 	if err != nil {
+		var scanErr *git.ScanError
+		if errors.As(err, &scanErr) {
+			return doing.NewDoingError(doing.ErrorCategoryGit, "提交被预提交扫描阻止", err).
+				WithContext("module", module).
+				WithContext("job", job).
+				WithContext("findings", scanResult.Findings)
+		}
 		return fmt.Errorf("failed to create commit: %w", err)
 	}
 
@@ -592,6 +931,12 @@ func (e *engine) createGitCommitUsingCommitter(module, job string) error {
 		logging.String("job", job),
 		logging.Int("loop", loopNum),
 	)
+	if statsErr == nil {
+		e.config.Metrics.Committed(
+			stats.FilesAdded+stats.FilesModified+stats.FilesDeleted,
+			stats.LinesAdded+stats.LinesDeleted,
+		)
+	}
 
 	return nil
 }
@@ -599,13 +944,6 @@ func (e *engine) createGitCommitUsingCommitter(module, job string) error {
 // buildJobPrompt builds a comprehensive prompt for executing an entire job.
 // This includes all tasks, context, and instructions for the AI to handle autonomously.
 func (e *engine) buildJobPrompt(module, job string) (string, error) {
-	// Load the doing prompt template
-	doingPromptPath := filepath.Join(e.config.PromptsDir, "doing.md")
-	promptTemplate, err := os.ReadFile(doingPromptPath)
-	if err != nil {
-		return "", fmt.Errorf("failed to read doing prompt: %w", err)
-	}
-
 	// Get the plan file name from module state
 	planFileName := module + ".md"
 
@@ -633,64 +971,129 @@ func (e *engine) buildJobPrompt(module, job string) (string, error) {
 		return "", fmt.Errorf("failed to get job state: %w", err)
 	}
 
-	// Build task list
-	taskList := ""
-	for i, task := range jobState.Tasks {
-		status := "[ ]"
-		if task.Status == state.StatusCompleted {
-			status = "[x]"
-		}
-		taskList += fmt.Sprintf("- %s Task %d: %s\n", status, i+1, task.Description)
+	data := map[string]interface{}{
+		"module":          module,
+		"job":             job,
+		"tasks":           promptTasks(jobState.Tasks, 1),
+		"tasks_total":     len(jobState.Tasks),
+		"tasks_completed": jobState.TasksCompleted,
+		"plan":            strings.TrimSpace(string(planContent)),
 	}
+	e.researchContext(data, module, job, jobState)
+	e.digestContext(data, module, job)
 
-	// Build the comprehensive job-level prompt
-	prompt := fmt.Sprintf(`%s
-
-# Current Job
-
-**Module**: %s
-**Job**: %s
-
-## Job Tasks
-
-%s
+	rendered, err := e.prompts().Render("phases/doing.md", data)
+	if err != nil {
+		return "", fmt.Errorf("failed to render doing prompt: %w", err)
+	}
+	return rendered, nil
+}
 
-## Job Details
+// runOptions returns the AI CLI options and timeout of a job from the
+// metadata of its plan, the job's own settings on top of the plan's. Jobs
+// run with bypassPermissions unless they select another permission mode.
+func (e *engine) runOptions(module, job string) (callcli.RunOptions, time.Duration, error) {
+	run := callcli.RunOptions{PermissionMode: callcli.BypassPermissions}
 
-**Tasks Total**: %d
-**Tasks Completed**: %d
+	planPath, err := plan.FindPlanFile(e.config.PlanDir, module)
+	if err != nil {
+		// Without a plan file there is no metadata to apply
+		return run, 0, nil
+	}
+	parsed, err := plan.ParsePlanFile(planPath)
+	if err != nil {
+		return run, 0, fmt.Errorf("failed to read job settings from %s: %w", planPath, err)
+	}
 
-# Plan Context
+	meta := parsed.JobMetadata(job)
+	timeout, err := meta.TimeoutDuration()
+	if err != nil {
+		return run, 0, fmt.Errorf("invalid settings of job %s/%s: %w", module, job, err)
+	}
+	if meta.Model != "" {
+		run.Model = meta.Model
+	}
+	if meta.PermissionMode != "" {
+		run.PermissionMode = meta.PermissionMode
+	}
+	run.AllowedTools = meta.Tools
+	run.DisallowedTools = meta.DisallowedTools
+	run.ExtraArgs = meta.Args
 
-%s
+	if !meta.IsZero() {
+		e.logger.Info("Applying job settings",
+			logging.String("module", module),
+			logging.String("job", job),
+			logging.String("model", run.Model),
+			logging.String("permission_mode", run.PermissionMode),
+			logging.Any("tools", run.AllowedTools),
+			logging.String("timeout", meta.Timeout),
+		)
+	}
+	return run, timeout, nil
+}
 
-# Job-Level Execution Instructions
+// prompts returns the engine job prompts are rendered with.
+func (e *engine) prompts() *prompt.Engine {
+	if e.config.Prompts != nil {
+		return e.config.Prompts
+	}
+	engine := prompt.NewEngine(e.config.PromptsDir)
+	engine.SetLanguage(e.config.Language)
+	return engine
+}
 
-You are executing the entire job "%s" in module "%s". This is a job-level execution where you should:
+// promptTasks returns the tasks as template data, numbered from first.
+func promptTasks(tasks []state.TaskState, first int) []map[string]interface{} {
+	items := make([]map[string]interface{}, 0, len(tasks))
+	for i, task := range tasks {
+		items = append(items, map[string]interface{}{
+			"index":       i + first,
+			"description": task.Description,
+			"completed":   task.Status == state.StatusCompleted,
+		})
+	}
+	return items
+}
 
-1. Review all tasks listed above
-2. Execute each task in sequence
-3. Skip tasks that are already marked as completed [x]
-4. Follow the doing prompt template for task execution
-5. Ensure all validation criteria are met before completing
-6. Update the plan file with any issues encountered in the debug logs section
-7. Mark the job as complete when all tasks are done and validated
+// researchContext adds the research sections most relevant to a job,
+// searched by the module, job and task names, to the prompt data; research
+// is "" when there are none. The numbered citations end up in the prompt
+// written to the job log.
+func (e *engine) researchContext(data map[string]interface{}, module, job string, jobState *state.JobState) {
+	data["research"] = ""
+	data["research_selected"] = 0
+	data["research_total"] = 0
+	if e.config.ResearchDir == "" || e.config.ResearchTopK <= 0 {
+		return
+	}
+	ix, err := research.Build(e.config.ResearchDir)
+	if err != nil {
+		e.logger.Warn("Failed to index research files", logging.String("error", err.Error()))
+		return
+	}
 
-Execute the job autonomously and handle all tasks. Report any issues or blockers encountered.
-`, string(promptTemplate), module, job, taskList, len(jobState.Tasks), jobState.TasksCompleted, string(planContent), job, module)
+	query := []string{module, job}
+	for _, task := range jobState.Tasks {
+		query = append(query, task.Description)
+	}
+	hits := ix.Search(strings.Join(query, " "), e.config.ResearchTopK)
+	if len(hits) == 0 {
+		return
+	}
 
-	return prompt, nil
+	e.logger.Info("Selected research sections",
+		logging.String("module", module),
+		logging.String("job", job),
+		logging.Any("citations", research.Citations(hits)),
+	)
+	data["research"] = strings.TrimSpace(research.Format(hits))
+	data["research_selected"] = len(hits)
+	data["research_total"] = ix.Len()
 }
 
 // buildTaskPrompt builds the prompt for executing a single task (legacy method, kept for compatibility).
 func (e *engine) buildTaskPrompt(module, job string, taskIndex int, taskDesc string) (string, error) {
-	// Load the doing prompt template
-	doingPromptPath := filepath.Join(e.config.PromptsDir, "doing.md")
-	promptTemplate, err := os.ReadFile(doingPromptPath)
-	if err != nil {
-		return "", fmt.Errorf("failed to read doing prompt: %w", err)
-	}
-
 	// Get the plan file name from module state
 	planFileName := module + ".md"
 	if execStatus := e.stateManager.GetState(); execStatus != nil {
@@ -710,25 +1113,17 @@ func (e *engine) buildTaskPrompt(module, job string, taskIndex int, taskDesc str
 		return "", fmt.Errorf("failed to read plan file: %w", err)
 	}
 
-	// Build the full prompt
-	prompt := fmt.Sprintf(`%s
-
-# Current Task
-
-**Module**: %s
-**Job**: %s
-**Task %d**: %s
-
-# Plan Context
-
-%s
-
-# Instructions
-
-Please execute the task described above. Follow the doing prompt template and ensure all validation criteria are met.
-`, string(promptTemplate), module, job, taskIndex+1, taskDesc, string(planContent))
-
-	return prompt, nil
+	rendered, err := e.prompts().Render("phases/doing_task.md", map[string]interface{}{
+		"module":     module,
+		"job":        job,
+		"task_index": taskIndex + 1,
+		"task":       taskDesc,
+		"plan":       strings.TrimSpace(string(planContent)),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render doing prompt: %w", err)
+	}
+	return rendered, nil
 }
 
 // createJobLogFile creates a log file for a job.
@@ -768,41 +1163,87 @@ func (e *engine) writeJobLog(logFile *os.File, module, job, stdout, stderr strin
 		return
 	}
 
+	// Mask secrets in the output before it reaches the log
+	w := redact.NewWriter(logFile)
+	defer w.Flush()
+
 	// Parse and format stdout as event stream if it looks like JSON
 	if stdout != "" && strings.HasPrefix(strings.TrimSpace(stdout), "[") {
 		// Looks like JSON event stream, format it
-		formatter := NewEventFormatter(logFile)
+		formatter := NewEventFormatter(w)
 		if err := formatter.FormatEventStream(stdout); err != nil {
 			// If formatting fails, fall back to raw output
 			e.logger.Warn("Failed to format event stream, using raw output",
 				logging.String("error", err.Error()),
 			)
-			fmt.Fprintf(logFile, "\n=== STDOUT (Raw) ===\n")
-			fmt.Fprintf(logFile, "%s\n", stdout)
+			fmt.Fprintf(w, "\n=== STDOUT (Raw) ===\n")
+			fmt.Fprintf(w, "%s\n", stdout)
 		}
 	} else if stdout != "" {
 		// Not JSON, write as-is
-		fmt.Fprintf(logFile, "\n=== STDOUT ===\n")
-		fmt.Fprintf(logFile, "%s\n", stdout)
+		fmt.Fprintf(w, "\n=== STDOUT ===\n")
+		fmt.Fprintf(w, "%s\n", stdout)
 	}
 
 	// Write stderr section
 	if stderr != "" {
-		fmt.Fprintf(logFile, "\n=== STDERR ===\n")
-		fmt.Fprintf(logFile, "%s\n", stderr)
+		fmt.Fprintf(w, "\n=== STDERR ===\n")
+		fmt.Fprintf(w, "%s\n", stderr)
 	}
 
 	// Write footer
-	fmt.Fprintf(logFile, "\n==========================================\n")
-	fmt.Fprintf(logFile, "Exit Code: %d\n", exitCode)
-	fmt.Fprintf(logFile, "Completed: %s\n", time.Now().Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(w, "\n==========================================\n")
+	fmt.Fprintf(w, "Exit Code: %d\n", exitCode)
+	fmt.Fprintf(w, "Completed: %s\n", time.Now().Format("2006-01-02 15:04:05"))
+}
+
+// saveConversation saves the event stream of a job run, with secrets
+// masked, next to its job log as .morty/logs/{module}_{job}_{timestamp}.json.
+func (e *engine) saveConversation(logFilePath, stdout string) {
+	path := strings.TrimSuffix(logFilePath, ".log") + ".json"
+	if err := os.WriteFile(path, redact.JSON([]byte(stdout)), 0644); err != nil {
+		e.logger.Warn("Failed to save conversation",
+			logging.String("path", path),
+			logging.String("error", err.Error()),
+		)
+	}
+}
+
+// appendScanFindings records pre-commit scan findings in the job log and the logger.
+func (e *engine) appendScanFindings(module, job string, result *git.ScanResult) {
+	e.logger.Warn("Pre-commit scan reported findings",
+		logging.String("module", module),
+		logging.String("job", job),
+		logging.Int("findings", len(result.Findings)),
+		logging.Bool("blocked", result.Blocked),
+		logging.Any("files", result.Files()),
+	)
+
+	if e.jobLogPath == "" {
+		return
+	}
+
+	logFile, err := os.OpenFile(e.jobLogPath, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		e.logger.Warn("Failed to open job log for scan findings",
+			logging.String("error", err.Error()),
+		)
+		return
+	}
+	defer logFile.Close()
+
+	fmt.Fprintf(logFile, "\n=== PRE-COMMIT SCAN ===\n")
+	fmt.Fprint(logFile, result.Format())
 }
 
 // verifyJobCompletionInPlan verifies that the job is marked as completed in the plan file.
 // Returns true if the job has a completion status marker in the plan file.
 func (e *engine) verifyJobCompletionInPlan(module, job string) (bool, error) {
-	// Get the plan file name
+	// Get the plan file name (any supported format)
 	planFileName := module + ".md"
+	if found, err := plan.FindPlanFile(e.config.PlanDir, module); err == nil {
+		planFileName = filepath.Base(found)
+	}
 
 	// Get status and find module
 	if execStatus := e.stateManager.GetState(); execStatus != nil {
@@ -823,7 +1264,7 @@ func (e *engine) verifyJobCompletionInPlan(module, job string) (bool, error) {
 	}
 
 	// Parse the plan
-	planData, err := plan.ParsePlan(string(planContent))
+	planData, err := plan.ParsePlanAs(string(planContent), plan.FormatFromPath(planFileName))
 	if err != nil {
 		return false, fmt.Errorf("failed to parse plan file: %w", err)
 	}
@@ -112,6 +112,19 @@ func (f *EventFormatter) formatLineByLine(content string) error {
 	return scanner.Err()
 }
 
+// FormatLine formats a single line of a stream-json event stream and
+// reports whether it was an event. Non-event lines are not written.
+func (f *EventFormatter) FormatLine(line string) bool {
+	var event Event
+	if err := json.Unmarshal([]byte(strings.TrimSpace(line)), &event); err != nil || event.Type == "" {
+		return false
+	}
+
+	f.eventCount++
+	f.formatEvent(f.eventCount, &event)
+	return true
+}
+
 // formatEvent formats a single event
 func (f *EventFormatter) formatEvent(num int, event *Event) {
 	timestamp := f.extractTimestamp(event)
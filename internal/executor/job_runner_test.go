@@ -28,18 +28,17 @@ func setupJobRunnerTestEnv(t testing.TB) (string, *state.Manager, logging.Logger
 
 	// Save initial state with test data
 	if err := os.WriteFile(stateFile, []byte(`{
-		"version": "1.0",
-		"global": {
-			"status": "PENDING",
-			"start_time": "2024-01-01T00:00:00Z",
-			"last_update": "2024-01-01T00:00:00Z"
-		},
-		"modules": {
-			"test-module": {
+		"version": "2.0",
+		"global": {"status": "PENDING", "start_time": "2024-01-01T00:00:00Z", "last_update": "2024-01-01T00:00:00Z"},
+		"modules": [
+			{
+				"index": 0,
 				"name": "test-module",
 				"status": "PENDING",
-				"jobs": {
-					"pending-job": {
+				"jobs": [
+					{
+						"index": 0,
+						"global_index": 0,
 						"name": "pending-job",
 						"status": "PENDING",
 						"tasks_total": 3,
@@ -52,7 +51,9 @@ func setupJobRunnerTestEnv(t testing.TB) (string, *state.Manager, logging.Logger
 						"created_at": "2024-01-01T00:00:00Z",
 						"updated_at": "2024-01-01T00:00:00Z"
 					},
-					"partial-job": {
+					{
+						"index": 1,
+						"global_index": 1,
 						"name": "partial-job",
 						"status": "RUNNING",
 						"tasks_total": 3,
@@ -65,7 +66,9 @@ func setupJobRunnerTestEnv(t testing.TB) (string, *state.Manager, logging.Logger
 						"created_at": "2024-01-01T00:00:00Z",
 						"updated_at": "2024-01-01T00:00:00Z"
 					},
-					"completed-job": {
+					{
+						"index": 2,
+						"global_index": 2,
 						"name": "completed-job",
 						"status": "COMPLETED",
 						"tasks_total": 2,
@@ -77,7 +80,9 @@ func setupJobRunnerTestEnv(t testing.TB) (string, *state.Manager, logging.Logger
 						"created_at": "2024-01-01T00:00:00Z",
 						"updated_at": "2024-01-01T00:00:00Z"
 					},
-					"failed-job": {
+					{
+						"index": 3,
+						"global_index": 3,
 						"name": "failed-job",
 						"status": "FAILED",
 						"tasks_total": 2,
@@ -90,7 +95,9 @@ func setupJobRunnerTestEnv(t testing.TB) (string, *state.Manager, logging.Logger
 						"created_at": "2024-01-01T00:00:00Z",
 						"updated_at": "2024-01-01T00:00:00Z"
 					},
-					"all-completed-tasks": {
+					{
+						"index": 4,
+						"global_index": 4,
 						"name": "all-completed-tasks",
 						"status": "RUNNING",
 						"tasks_total": 3,
@@ -103,7 +110,9 @@ func setupJobRunnerTestEnv(t testing.TB) (string, *state.Manager, logging.Logger
 						"created_at": "2024-01-01T00:00:00Z",
 						"updated_at": "2024-01-01T00:00:00Z"
 					},
-					"empty-tasks": {
+					{
+						"index": 5,
+						"global_index": 5,
 						"name": "empty-tasks",
 						"status": "PENDING",
 						"tasks_total": 0,
@@ -112,11 +121,11 @@ func setupJobRunnerTestEnv(t testing.TB) (string, *state.Manager, logging.Logger
 						"created_at": "2024-01-01T00:00:00Z",
 						"updated_at": "2024-01-01T00:00:00Z"
 					}
-				},
+				],
 				"created_at": "2024-01-01T00:00:00Z",
 				"updated_at": "2024-01-01T00:00:00Z"
 			}
-		}
+		]
 	}`), 0644); err != nil {
 		t.Fatalf("Failed to write state file: %v", err)
 	}
@@ -584,14 +593,17 @@ func BenchmarkJobRunner_Run(b *testing.B) {
 
 	// Create initial state
 	if err := os.WriteFile(stateFile, []byte(`{
-		"version": "1.0",
+		"version": "2.0",
 		"global": {"status": "PENDING", "start_time": "2024-01-01T00:00:00Z", "last_update": "2024-01-01T00:00:00Z"},
-		"modules": {
-			"test-module": {
+		"modules": [
+			{
+				"index": 0,
 				"name": "test-module",
 				"status": "PENDING",
-				"jobs": {
-					"pending-job": {
+				"jobs": [
+					{
+						"index": 0,
+						"global_index": 0,
 						"name": "pending-job",
 						"status": "PENDING",
 						"tasks_total": 3,
@@ -604,11 +616,11 @@ func BenchmarkJobRunner_Run(b *testing.B) {
 						"created_at": "2024-01-01T00:00:00Z",
 						"updated_at": "2024-01-01T00:00:00Z"
 					}
-				},
+				],
 				"created_at": "2024-01-01T00:00:00Z",
 				"updated_at": "2024-01-01T00:00:00Z"
 			}
-		}
+		]
 	}`), 0644); err != nil {
 		b.Fatalf("Failed to write state file: %v", err)
 	}
@@ -633,11 +645,12 @@ func BenchmarkJobRunner_Run(b *testing.B) {
 		statePtr := stateManager.GetState()
 		if statePtr != nil {
 			// Reset task statuses to PENDING
-			for _, task := range statePtr.Modules["test-module"].Jobs["pending-job"].Tasks {
-				task.Status = state.StatusPending
+			job := statePtr.GetModuleByName("test-module").GetJobByName("pending-job")
+			for i := range job.Tasks {
+				job.Tasks[i].Status = state.StatusPending
 			}
-			statePtr.Modules["test-module"].Jobs["pending-job"].TasksCompleted = 0
-			stateManager.Save()
+			job.TasksCompleted = 0
+			stateManager.Save(statePtr)
 		}
 
 		jr.Run(ctx, "test-module", "pending-job")
@@ -723,8 +736,8 @@ func TestJobRunner_Run_RunningJobAllowed(t *testing.T) {
 	stateManager.Load()
 	statePtr := stateManager.GetState()
 	if statePtr != nil {
-		statePtr.Modules["test-module"].Jobs["pending-job"].Status = state.StatusRunning
-		stateManager.Save()
+		statePtr.GetModuleByName("test-module").GetJobByName("pending-job").Status = state.StatusRunning
+		stateManager.Save(statePtr)
 	}
 
 	taskExecutor := func(ctx context.Context, module, job string, taskIndex int, taskDesc string) error {
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/morty/morty/internal/parser/plan"
+	"github.com/morty/morty/internal/parser/prompt"
 	"github.com/morty/morty/internal/state"
 )
 
@@ -92,16 +93,7 @@ func NewPromptBuilder(
 //   - The complete prompt string
 //   - An error if any step fails
 func (pb *promptBuilder) BuildPrompt(module, job string, taskIndex int, taskDesc string) (string, error) {
-	var parts []string
-
-	// Step 1: Add system prompt
-	systemPrompt, err := pb.loadSystemPrompt()
-	if err != nil {
-		return "", fmt.Errorf("failed to load system prompt: %w", err)
-	}
-	parts = append(parts, systemPrompt)
-
-	// Step 2: Build and add compact context
+	// Build the compact context
 	compactContext, err := pb.BuildCompactContext(module, job)
 	if err != nil {
 		return "", fmt.Errorf("failed to build compact context: %w", err)
@@ -112,26 +104,26 @@ func (pb *promptBuilder) BuildPrompt(module, job string, taskIndex int, taskDesc
 		return "", fmt.Errorf("failed to marshal compact context: %w", err)
 	}
 
-	parts = append(parts, "---\n\n# 精简上下文\n\n```json\n"+string(contextJSON)+"\n```\n")
-
-	// Step 3: Add Plan content
+	// Load Plan content
 	planContent, err := pb.loadPlanContent(module)
 	if err != nil {
 		return "", fmt.Errorf("failed to load plan content: %w", err)
 	}
-	parts = append(parts, "---\n\n# Plan 内容\n\n"+planContent)
 
-	// Step 4: Add current task context
-	taskContext, err := pb.buildTaskContext(module, job, taskIndex, taskDesc)
+	data := pb.buildTaskContext(module, job, planContent)
+	data["context"] = string(contextJSON)
+	data["plan"] = strings.TrimSpace(planContent)
+
+	// Render the system prompt, context, task list and execution instructions
+	engine := prompt.NewEngine(pb.promptsDir)
+	if pb.systemPromptFile != "doing.md" {
+		engine.SetSource("doing.md", filepath.Join(pb.promptsDir, pb.systemPromptFile))
+	}
+	rendered, err := engine.Render("phases/doing_context.md", data)
 	if err != nil {
-		return "", fmt.Errorf("failed to build task context: %w", err)
+		return "", fmt.Errorf("failed to render prompt: %w", err)
 	}
-	parts = append(parts, taskContext)
-
-	// Step 5: Add execution instructions
-	parts = append(parts, pb.buildExecutionInstructions())
-
-	return strings.Join(parts, "\n\n"), nil
+	return rendered, nil
 }
 
 // BuildCompactContext creates a compact context for efficient token usage.
@@ -202,13 +194,28 @@ func (pb *promptBuilder) loadSystemPrompt() (string, error) {
 }
 
 // loadPlanContent reads and returns the Plan file content for the given module.
+// Structured (JSON/YAML) plans are rendered as markdown so that prompts
+// and parsing see the same layout regardless of the source format.
 func (pb *promptBuilder) loadPlanContent(module string) (string, error) {
-	planPath := filepath.Join(pb.planDir, module+".md")
+	planPath, err := plan.FindPlanFile(pb.planDir, module)
+	if err != nil {
+		planPath = filepath.Join(pb.planDir, module+".md")
+	}
 	content, err := os.ReadFile(planPath)
 	if err != nil {
 		return "", fmt.Errorf("failed to read plan file from %s: %w", planPath, err)
 	}
-	return string(content), nil
+
+	format := plan.FormatFromPath(planPath)
+	if format == plan.FormatMarkdown {
+		return string(content), nil
+	}
+
+	parsedPlan, err := plan.ParsePlanAs(string(content), format)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse plan file %s: %w", planPath, err)
+	}
+	return plan.RenderMarkdown(parsedPlan), nil
 }
 
 // buildCompletedJobsSummary creates a summary of completed jobs for the given module.
@@ -237,6 +244,14 @@ func (pb *promptBuilder) buildCompletedJobsSummary(module string) []string {
 		if jobState.Status == state.StatusCompleted {
 			summary := fmt.Sprintf("%s/%s: 完成 (%d tasks)",
 				module, jobState.Name, len(jobState.Tasks))
+			if digest := jobState.Digest; digest != nil {
+				if digest.Summary != "" {
+					summary += " - " + digest.Summary
+				}
+				if len(digest.Files) > 0 {
+					summary += fmt.Sprintf(" [files: %s]", strings.Join(digest.Files, ", "))
+				}
+			}
 			summaries = append(summaries, summary)
 		}
 	}
@@ -321,98 +336,47 @@ func (pb *promptBuilder) buildJobDetailsFromState(job string, jobState *state.Jo
 	}
 }
 
-// buildTaskContext builds the context for the current task.
-func (pb *promptBuilder) buildTaskContext(module, job string, taskIndex int, taskDesc string) (string, error) {
-	var builder strings.Builder
-
-	builder.WriteString("---\n\n# 当前 Job 上下文\n\n")
-	builder.WriteString(fmt.Sprintf("**模块**: %s\n", module))
-	builder.WriteString(fmt.Sprintf("**Job**: %s\n", job))
-
-	// Get job state for total tasks
-	jobState := pb.stateManager.GetJob(module, job)
-	if jobState != nil {
-		builder.WriteString(fmt.Sprintf("**总 Tasks**: %d\n", len(jobState.Tasks)))
-	}
-
-	builder.WriteString("\n## 任务列表\n\n")
-	builder.WriteString("你需要按顺序完成以下所有 tasks：\n\n")
+// buildTaskContext returns the prompt data for the current job: its tasks
+// and the validators of the matching job in the plan.
+func (pb *promptBuilder) buildTaskContext(module, job, planContent string) map[string]interface{} {
+	tasks := []map[string]interface{}{}
+	tasksTotal := 0
 
 	// List all tasks
-	if jobState != nil {
+	if jobState := pb.stateManager.GetJob(module, job); jobState != nil {
+		tasksTotal = len(jobState.Tasks)
 		for i, task := range jobState.Tasks {
-			status := "[ ]"
-			if task.Status == state.StatusCompleted {
-				status = "[x]"
-			}
-			builder.WriteString(fmt.Sprintf("- %s Task %d: %s\n",
-				status, i, task.Description))
+			tasks = append(tasks, map[string]interface{}{
+				"index":       i,
+				"description": task.Description,
+				"completed":   task.Status == state.StatusCompleted,
+			})
 		}
 	}
 
-	builder.WriteString("\n## 验证器\n\n")
-
 	// Add validators from plan
-	planContent, err := pb.loadPlanContent(module)
-	if err == nil {
-		parsedPlan, err := plan.ParsePlan(planContent)
-		if err == nil {
-			for _, planJob := range parsedPlan.Jobs {
-				if strings.EqualFold(planJob.Name, job) ||
-					fmt.Sprintf("job_%d", planJob.Index) == job {
-					for _, validator := range planJob.Validators {
-						if strings.TrimSpace(validator) != "" {
-							builder.WriteString(fmt.Sprintf("- %s\n", validator))
-						}
+	validators := []string{}
+	if parsedPlan, err := plan.ParsePlan(planContent); err == nil {
+		for _, planJob := range parsedPlan.Jobs {
+			if strings.EqualFold(planJob.Name, job) ||
+				fmt.Sprintf("job_%d", planJob.Index) == job {
+				for _, validator := range planJob.Validators {
+					if strings.TrimSpace(validator) != "" {
+						validators = append(validators, validator)
 					}
-					break
 				}
+				break
 			}
 		}
 	}
 
-	builder.WriteString("\n## 执行指令\n\n")
-	builder.WriteString("请按照 Doing 模式的循环步骤执行：\n")
-	builder.WriteString("1. 读取精简上下文了解当前状态\n")
-	builder.WriteString("2. **按顺序执行所有 Tasks**，完成一个后再进行下一个\n")
-	builder.WriteString("3. 每个 Task 完成后在内部标记进度\n")
-	builder.WriteString("4. 所有 Tasks 完成后，运行所有验证器检查\n")
-	builder.WriteString("5. 如有问题，记录 debug_log\n")
-
-	return builder.String(), nil
-}
-
-// buildExecutionInstructions builds the final execution instructions.
-func (pb *promptBuilder) buildExecutionInstructions() string {
-	return `---
-
-# 任务完成要求（必须执行）
-
-**所有 Tasks 执行完毕后**，你必须在输出中返回 JSON 格式的执行结果（RALPH_STATUS）：
-
-` + "```json\n" + `{
-  "module": "[模块名]",
-  "job": "[Job 名]",
-  "status": "COMPLETED",
-  "tasks_completed": 8,
-  "tasks_total": 8,
-  "summary": "执行摘要"
-}` + "\n```\n\n" + `### 重要规则：
-- **成功时**: status 必须是 "COMPLETED"（全部大写）
-- **失败时**: status 可以是 "FAILED"
-- 系统会检查输出内容中是否包含 "status": "COMPLETED" 来判断任务是否成功
-- **不需要写入任何文件**，只需要在输出中包含上述 JSON
-
-### 验证器自检清单
-在输出结果前，请确认：
-- [ ] 我已执行完当前 Job 的所有 Tasks
-- [ ] 我已运行所有验证器检查
-- [ ] 验证器全部通过（或在失败情况下明确记录原因）
-- [ ] 我已输出 RALPH_STATUS JSON 且 status 为 "COMPLETED"
-
-**注意**: 系统通过检测输出中的 "status": "COMPLETED" 来判断任务成功，未检测到则标记为失败。
-
-开始执行!`
+	return map[string]interface{}{
+		"module":      module,
+		"job":         job,
+		"tasks":       tasks,
+		"tasks_total": tasksTotal,
+		"validators":  validators,
+	}
 }
 
 // ReplaceTemplateVariables replaces template variables in the prompt.
@@ -97,12 +97,15 @@ func setupPromptBuilderTest(t *testing.T) (string, *state.Manager, func()) {
     "last_update": "2024-01-01T00:00:00Z",
     "total_loops": 2
   },
-  "modules": {
-    "test": {
+  "modules": [
+    {
+      "index": 0,
       "name": "test",
       "status": "RUNNING",
-      "jobs": {
-        "job_1": {
+      "jobs": [
+        {
+          "index": 0,
+          "global_index": 0,
           "name": "job_1",
           "status": "RUNNING",
           "loop_count": 1,
@@ -110,14 +113,31 @@ func setupPromptBuilderTest(t *testing.T) (string, *state.Manager, func()) {
           "tasks_total": 3,
           "tasks_completed": 0,
           "tasks": [
-            {"index": 0, "status": "PENDING", "description": "创建测试文件", "updated_at": "2024-01-01T00:00:00Z"},
-            {"index": 1, "status": "PENDING", "description": "实现测试逻辑", "updated_at": "2024-01-01T00:00:00Z"},
-            {"index": 2, "status": "PENDING", "description": "编写单元测试", "updated_at": "2024-01-01T00:00:00Z"}
+            {
+              "index": 0,
+              "status": "PENDING",
+              "description": "创建测试文件",
+              "updated_at": "2024-01-01T00:00:00Z"
+            },
+            {
+              "index": 1,
+              "status": "PENDING",
+              "description": "实现测试逻辑",
+              "updated_at": "2024-01-01T00:00:00Z"
+            },
+            {
+              "index": 2,
+              "status": "PENDING",
+              "description": "编写单元测试",
+              "updated_at": "2024-01-01T00:00:00Z"
+            }
           ],
           "created_at": "2024-01-01T00:00:00Z",
           "updated_at": "2024-01-01T00:00:00Z"
         },
-        "job_0": {
+        {
+          "index": 1,
+          "global_index": 1,
           "name": "job_0",
           "status": "COMPLETED",
           "loop_count": 1,
@@ -125,18 +145,28 @@ func setupPromptBuilderTest(t *testing.T) (string, *state.Manager, func()) {
           "tasks_total": 2,
           "tasks_completed": 2,
           "tasks": [
-            {"index": 0, "status": "COMPLETED", "description": "准备工作", "updated_at": "2024-01-01T00:00:00Z"},
-            {"index": 1, "status": "COMPLETED", "description": "初始化环境", "updated_at": "2024-01-01T00:00:00Z"}
+            {
+              "index": 0,
+              "status": "COMPLETED",
+              "description": "准备工作",
+              "updated_at": "2024-01-01T00:00:00Z"
+            },
+            {
+              "index": 1,
+              "status": "COMPLETED",
+              "description": "初始化环境",
+              "updated_at": "2024-01-01T00:00:00Z"
+            }
           ],
           "created_at": "2024-01-01T00:00:00Z",
           "updated_at": "2024-01-01T00:00:00Z"
         }
-      },
+      ],
       "created_at": "2024-01-01T00:00:00Z",
       "updated_at": "2024-01-01T00:00:00Z"
     }
-  },
-  "version": "1.0"
+  ],
+  "version": "2.0"
 }`
 
 	statePath := filepath.Join(stateDir, "status.json")
@@ -312,7 +312,11 @@ func (rp *resultParser) UpdatePlanDebugLogs(module, job string, debugLogs []plan
 		return nil
 	}
 
-	planPath := filepath.Join(rp.planDir, module+".md")
+	planPath, err := plan.FindPlanFile(rp.planDir, module)
+	if err != nil {
+		planPath = filepath.Join(rp.planDir, module+".md")
+	}
+	format := plan.FormatFromPath(planPath)
 
 	// Read existing plan content
 	content, err := os.ReadFile(planPath)
@@ -321,7 +325,7 @@ func (rp *resultParser) UpdatePlanDebugLogs(module, job string, debugLogs []plan
 	}
 
 	// Parse the plan
-	parsedPlan, err := plan.ParsePlan(string(content))
+	parsedPlan, err := plan.ParsePlanAs(string(content), format)
 	if err != nil {
 		return fmt.Errorf("failed to parse plan: %w", err)
 	}
@@ -343,10 +347,16 @@ func (rp *resultParser) UpdatePlanDebugLogs(module, job string, debugLogs []plan
 	// Append new debug logs
 	targetJob.DebugLogs = append(targetJob.DebugLogs, debugLogs...)
 
-	// Write updated plan back
-	updatedContent := rp.rebuildPlanContent(string(content), targetJob)
+	// Write updated plan back; structured plans are simply re-encoded
+	updatedContent := []byte(rp.rebuildPlanContent(string(content), targetJob))
+	if format != plan.FormatMarkdown {
+		updatedContent, err = plan.Marshal(parsedPlan, format)
+		if err != nil {
+			return fmt.Errorf("failed to encode plan: %w", err)
+		}
+	}
 
-	if err := os.WriteFile(planPath, []byte(updatedContent), 0644); err != nil {
+	if err := os.WriteFile(planPath, updatedContent, 0644); err != nil {
 		return fmt.Errorf("failed to write updated plan: %w", err)
 	}
 
@@ -359,36 +369,37 @@ func (rp *resultParser) UpdatePlanDebugLogs(module, job string, debugLogs []plan
 }
 
 // rebuildPlanContent rebuilds the plan content with updated debug logs.
+// The job's debug log section is found through the configured heading
+// aliases, either as a #### subsection or as a **field**, and keeps the
+// heading it was written with.
 func (rp *resultParser) rebuildPlanContent(originalContent string, job *plan.Job) string {
-	// Find the debug logs section for this job
-	jobPattern := regexp.MustCompile(fmt.Sprintf(`(?i)### Job\s*%d[^#]*`, job.Index))
-
-	// Find where this job section ends (next ### or end of file)
-	jobMatch := jobPattern.FindStringIndex(originalContent)
-	if jobMatch == nil {
-		return originalContent
+	headings := plan.CurrentHeadings()
+	lines := strings.Split(originalContent, "\n")
+
+	// Find this job's section: from its ### heading to the next heading of
+	// level 3 or above
+	jobPattern := regexp.MustCompile(fmt.Sprintf(`(?i)^###\s*Job\s*%d\b`, job.Index))
+	jobStart, jobEnd := -1, len(lines)
+	for i, line := range lines {
+		if jobStart < 0 {
+			if jobPattern.MatchString(line) {
+				jobStart = i
+			}
+			continue
+		}
+		if level := markdownHeadingLevel(line); level > 0 && level <= 3 {
+			jobEnd = i
+			break
+		}
 	}
-
-	jobStart := jobMatch[0]
-	jobEnd := len(originalContent)
-
-	// Find next job section
-	nextJobPattern := regexp.MustCompile(`\n### Job \d+`)
-	nextJobMatch := nextJobPattern.FindStringIndex(originalContent[jobMatch[1]:])
-	if nextJobMatch != nil {
-		jobEnd = jobMatch[1] + nextJobMatch[0]
+	if jobStart < 0 {
+		return originalContent
 	}
 
-	jobSection := originalContent[jobStart:jobEnd]
-
-	// Find and replace debug logs section
-	debugPattern := regexp.MustCompile(`(?i)(\*\*调试日志\*\*[:：]?\s*\n)(.*?)(\n\*\*|$)`)
-
 	// Build new debug logs content
-	var debugContent strings.Builder
-	debugContent.WriteString("**调试日志**:\n")
+	var entries []string
 	for _, log := range job.DebugLogs {
-		debugContent.WriteString(fmt.Sprintf("- %s: %s, %s, %s, %s, %s, %s\n",
+		entries = append(entries, fmt.Sprintf("- %s: %s, %s, %s, %s, %s, %s",
 			log.ID,
 			log.Phenomenon,
 			log.Reproduction,
@@ -398,24 +409,99 @@ func (rp *resultParser) rebuildPlanContent(originalContent string, job *plan.Job
 			log.Progress))
 	}
 
-	// Replace or add debug logs section
-	if debugPattern.MatchString(jobSection) {
-		// Replace existing section
-		newJobSection := debugPattern.ReplaceAllString(jobSection, "${1}"+debugContent.String()+"${3}")
-		return originalContent[:jobStart] + newJobSection + originalContent[jobEnd:]
+	// Replace existing section
+	if heading, isField, found := findPlanSection(lines, jobStart+1, jobEnd, headings, plan.SectionDebugLogs); found {
+		end := planSectionEnd(lines, heading+1, jobEnd, isField)
+		var body []string
+		if isField {
+			// Drop an inline value such as "**调试日志**: 无"
+			lines[heading] = lines[heading][:boldFieldPattern.FindStringIndex(lines[heading])[1]]
+			body = append(entries, "")
+		} else {
+			body = append(append([]string{""}, entries...), "")
+		}
+		return joinLines(lines[:heading+1], body, lines[end:])
+	}
+
+	// Add a new section after the validators, in the same style
+	lang := plan.DetectLanguage(originalContent)
+	insertPos := jobEnd
+	isField := false
+	if heading, field, found := findPlanSection(lines, jobStart+1, jobEnd, headings, plan.SectionValidators); found {
+		insertPos = planSectionEnd(lines, heading+1, jobEnd, field)
+		isField = field
+	} else {
+		// Before the trailing "---" separator of the job
+		for insertPos > jobStart+1 && (strings.TrimSpace(lines[insertPos-1]) == "" || strings.TrimSpace(lines[insertPos-1]) == "---") {
+			insertPos--
+		}
+	}
+
+	title := plan.Title(plan.SectionDebugLogs, lang)
+	section := append([]string{"#### " + title, ""}, entries...)
+	if isField {
+		section = append([]string{"**" + title + "**:"}, entries...)
 	}
+	if insertPos > 0 && strings.TrimSpace(lines[insertPos-1]) != "" {
+		section = append([]string{""}, section...)
+	}
+	section = append(section, "")
+	return joinLines(lines[:insertPos], section, lines[insertPos:])
+}
 
-	// Add new debug logs section before the end of job section
-	// Find a good insertion point (after validators or tasks)
-	insertPattern := regexp.MustCompile(`(?i)(\*\*验证器\*\*[:：]?\s*\n.*?)(\n\n|\n###|$)`)
-	insertMatch := insertPattern.FindStringIndex(jobSection)
-	if insertMatch != nil {
-		insertPos := jobStart + insertMatch[1] - len("${2}")
-		return originalContent[:insertPos] + "\n\n" + debugContent.String() + originalContent[insertPos:]
+// boldFieldPattern matches a "**Field**:" line and captures the field name.
+var boldFieldPattern = regexp.MustCompile(`^\s*\*\*([^*]+)\*\*\s*[:：]`)
+
+// markdownHeadingLevel returns the ATX heading level of line, or 0.
+func markdownHeadingLevel(line string) int {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || level >= len(line) || line[level] != ' ' {
+		return 0
 	}
+	return level
+}
 
-	// Fallback: append to job section
-	return originalContent[:jobEnd] + "\n\n" + debugContent.String() + originalContent[jobEnd:]
+// findPlanSection finds the heading line of section within lines[start:end],
+// either a #### subsection or a **field**.
+func findPlanSection(lines []string, start, end int, headings *plan.Headings, section plan.Section) (index int, isField, found bool) {
+	for i := start; i < end; i++ {
+		line := lines[i]
+		if markdownHeadingLevel(line) == 4 && headings.MatchTitle(section, strings.TrimLeft(line, "# ")) {
+			return i, false, true
+		}
+		if m := boldFieldPattern.FindStringSubmatch(line); m != nil && headings.MatchField(section, m[1]) {
+			return i, true, true
+		}
+	}
+	return -1, false, false
+}
+
+// planSectionEnd returns the index of the first line after a section body
+// starting at start: the next heading, "---" separator or, for **field**
+// sections, the next field.
+func planSectionEnd(lines []string, start, end int, isField bool) int {
+	for i := start; i < end; i++ {
+		line := lines[i]
+		if markdownHeadingLevel(line) > 0 || strings.TrimSpace(line) == "---" {
+			return i
+		}
+		if isField && boldFieldPattern.MatchString(line) {
+			return i
+		}
+	}
+	return end
+}
+
+// joinLines joins groups of lines with newlines.
+func joinLines(groups ...[]string) string {
+	var all []string
+	for _, group := range groups {
+		all = append(all, group...)
+	}
+	return strings.Join(all, "\n")
 }
 
 // CreateDebugLog creates a new debug log entry from error information.
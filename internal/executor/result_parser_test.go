@@ -582,6 +582,77 @@ func TestUpdatePlanDebugLogs_EmptyLogs(t *testing.T) {
 	}
 }
 
+// TestRebuildPlanContent_Headings tests debug log updates in subsection-style
+// plans written with Chinese, English and configured headings.
+func TestRebuildPlanContent_Headings(t *testing.T) {
+	newLog := plan.DebugLog{
+		ID: "debug1", Phenomenon: "crash", Reproduction: "run", Hypothesis: "nil map",
+		Verification: "add check", Fix: "init map", Progress: "fixed",
+	}
+	entry := "- debug1: crash, run, nil map, add check, init map, fixed"
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "chinese subsection",
+			content: "### Job 1: A\n\n#### 验证器\n\n- ok\n\n#### 调试日志\n\n无\n\n#### 完成状态\n\n⏳ 待开始\n\n---\n\n### Job 2: B\n",
+			want:    "#### 调试日志\n\n" + entry + "\n\n#### 完成状态",
+		},
+		{
+			name:    "english subsection",
+			content: "### Job 1: A\n\n#### Validators\n\n- ok\n\n#### Debug Log\n\nNone\n\n#### Completion Status\n\n⏳ Pending\n",
+			want:    "#### Debug Log\n\n" + entry + "\n\n#### Completion Status",
+		},
+		{
+			name:    "inline field",
+			content: "### Job 1: A\n\n**Validators**:\n- ok\n\n**Debug Log**: None\n\n**Completion Status**: ⏳ Pending\n",
+			want:    "**Debug Log**:\n" + entry + "\n\n**Completion Status**",
+		},
+		{
+			name:    "missing section",
+			content: "## Overview\n\n## Jobs\n\n### Job 1: A\n\n#### Validators\n\n- ok\n\n#### Completion Status\n\n⏳ Pending\n",
+			want:    "- ok\n\n#### Debug Log\n\n" + entry + "\n\n#### Completion Status",
+		},
+	}
+
+	rp := &resultParser{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &plan.Job{Index: 1, DebugLogs: []plan.DebugLog{newLog}}
+			got := rp.rebuildPlanContent(tt.content, job)
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("Expected %q in:\n%s", tt.want, got)
+			}
+
+			parsed, err := plan.ParsePlan(got)
+			if err != nil {
+				t.Fatalf("ParsePlan failed: %v", err)
+			}
+			if len(parsed.Jobs) == 0 || len(parsed.Jobs[0].DebugLogs) != 1 {
+				t.Errorf("Expected the rebuilt plan to parse one debug log, got %+v", parsed.Jobs)
+			}
+		})
+	}
+
+	t.Run("configured alias", func(t *testing.T) {
+		h := plan.NewHeadings()
+		if err := h.AddAliases(plan.SectionDebugLogs, "Troubleshooting"); err != nil {
+			t.Fatal(err)
+		}
+		plan.SetHeadings(h)
+		defer plan.SetHeadings(plan.NewHeadings())
+
+		content := "### Job 1: A\n\n#### Troubleshooting\n\nNone\n"
+		got := rp.rebuildPlanContent(content, &plan.Job{Index: 1, DebugLogs: []plan.DebugLog{newLog}})
+		if want := "#### Troubleshooting\n\n" + entry + "\n"; got != "### Job 1: A\n\n"+want {
+			t.Errorf("Unexpected content:\n%s", got)
+		}
+	})
+}
+
 // TestDefaultResultParserConfig tests the default config.
 func TestDefaultResultParserConfig(t *testing.T) {
 	config := DefaultResultParserConfig()
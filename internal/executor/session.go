@@ -0,0 +1,95 @@
+package executor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/morty/morty/internal/callcli"
+	"github.com/morty/morty/internal/logging"
+	"github.com/morty/morty/internal/state"
+)
+
+// maxFailureRunes is the length of the failure a resume prompt quotes; the
+// end of the message is kept, as that is where CLI errors put the cause.
+const maxFailureRunes = 2000
+
+// resumableSession returns the session of the previous attempt of a job
+// when the next attempt should continue it: the attempt failed, its session
+// id is known and fresh sessions are not configured. It returns nil
+// otherwise.
+func (e *engine) resumableSession(jobState *state.JobState) *state.JobSession {
+	if e.config.FreshSession {
+		return nil
+	}
+	last := jobState.LastSession()
+	if last == nil || last.ID == "" || last.Error == "" {
+		return nil
+	}
+	return last
+}
+
+// buildResumePrompt builds the follow-up prompt of a resumed session. The
+// session already holds the job prompt, so it only says what failed and
+// which tasks remain.
+func (e *engine) buildResumePrompt(module, job string, jobState *state.JobState, previous *state.JobSession) (string, error) {
+	failure := []rune(previous.Error)
+	if len(failure) > maxFailureRunes {
+		failure = append([]rune("..."), failure[len(failure)-maxFailureRunes:]...)
+	}
+
+	data := map[string]interface{}{
+		"module":          module,
+		"job":             job,
+		"attempt":         len(jobState.Sessions) + 1,
+		"failure":         string(failure),
+		"tasks":           promptTasks(jobState.Tasks, 1),
+		"tasks_total":     len(jobState.Tasks),
+		"tasks_completed": jobState.TasksCompleted,
+	}
+	rendered, err := e.prompts().Render("phases/doing_resume.md", data)
+	if err != nil {
+		return "", fmt.Errorf("failed to render resume prompt: %w", err)
+	}
+	return rendered, nil
+}
+
+// recordSession stores the session an attempt of a job ran in. The id is
+// read from the CLI output, which may be cut short by a timeout or an
+// interrupt; an attempt without an id makes the next one start fresh.
+// Failures are only logged.
+func (e *engine) recordSession(module, job string, jobState *state.JobState, resumed bool, started time.Time, result *callcli.Result, conversation *callcli.ConversationData) {
+	session := state.JobSession{
+		Attempt:   len(jobState.Sessions) + 1,
+		Resumed:   resumed,
+		StartedAt: started,
+	}
+	if conversation != nil {
+		session.ID = conversation.SessionID
+	}
+	if session.ID == "" && result != nil {
+		session.ID = callcli.FindSessionID(result.Stdout)
+	}
+
+	if err := e.stateManager.AddJobSession(module, job, session); err != nil {
+		e.logger.Warn("Failed to record job session",
+			logging.String("module", module),
+			logging.String("job", job),
+			logging.String("error", err.Error()),
+		)
+		return
+	}
+	e.sessionRecorded = true
+}
+
+// failSession records why the current attempt of a job failed, so the next
+// attempt can resume its session with the failure. Attempts that failed
+// before calling the AI CLI leave the previous session as it is. Failures
+// are only logged.
+func (e *engine) failSession(module, job string, err error) {
+	if !e.sessionRecorded {
+		return
+	}
+	if updateErr := e.stateManager.FailJobSession(module, job, err.Error()); updateErr != nil {
+		e.logger.Warn("Failed to record session failure", logging.String("error", updateErr.Error()))
+	}
+}
@@ -0,0 +1,98 @@
+package executor
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/morty/morty/internal/callcli"
+	"github.com/morty/morty/internal/state"
+)
+
+func TestEngine_ResumableSession(t *testing.T) {
+	failed := state.JobSession{Attempt: 1, ID: "abc-123", Error: "exit code 1"}
+	tests := []struct {
+		name     string
+		sessions []state.JobSession
+		fresh    bool
+		want     string
+	}{
+		{name: "first attempt"},
+		{name: "failed attempt", sessions: []state.JobSession{failed}, want: "abc-123"},
+		{name: "fresh sessions configured", sessions: []state.JobSession{failed}, fresh: true},
+		{name: "no session id", sessions: []state.JobSession{failed, {Attempt: 2, Error: "timeout"}}},
+		{name: "attempt did not fail", sessions: []state.JobSession{{Attempt: 1, ID: "abc-123"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &engine{logger: &mockLogger{}, config: &Config{FreshSession: tt.fresh}}
+			got := e.resumableSession(&state.JobState{Sessions: tt.sessions})
+			if (got == nil) != (tt.want == "") || (got != nil && got.ID != tt.want) {
+				t.Errorf("resumableSession() = %v, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngine_BuildResumePrompt(t *testing.T) {
+	e := &engine{logger: &mockLogger{}, config: &Config{}}
+	jobState := &state.JobState{
+		Tasks: []state.TaskState{
+			{Description: "Add the parser", Status: state.StatusCompleted},
+			{Description: "Add the tests", Status: state.StatusPending},
+		},
+		TasksCompleted: 1,
+		Sessions:       []state.JobSession{{Attempt: 1, ID: "abc-123"}},
+	}
+	previous := &state.JobSession{Error: strings.Repeat("x", maxFailureRunes) + "validator go test failed"}
+
+	prompt, err := e.buildResumePrompt("core", "parser", jobState, previous)
+	if err != nil {
+		t.Fatalf("buildResumePrompt() error: %v", err)
+	}
+	for _, want := range []string{`job "parser" in module "core"`, "attempt 2", "...xxx", "validator go test failed", "[x] Task 1: Add the parser", "[ ] Task 2: Add the tests", "RALPH_STATUS"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt does not contain %q:\n%s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, strings.Repeat("x", maxFailureRunes)) {
+		t.Errorf("failure is not truncated")
+	}
+}
+
+func TestEngine_RecordSession(t *testing.T) {
+	stateManager := state.NewManager(filepath.Join(t.TempDir(), "status.json"))
+	if err := stateManager.Save(&state.ExecutionStatus{Modules: []state.ModuleState{
+		{Name: "core", Jobs: []state.JobState{{Name: "parser", Status: state.StatusRunning}}},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	e := &engine{stateManager: stateManager, logger: &mockLogger{}, config: &Config{}}
+
+	// A failure before the AI CLI call has no session to record it on
+	e.failSession("core", "parser", errors.New("invalid settings"))
+	if sessions := stateManager.GetJob("core", "parser").Sessions; len(sessions) != 0 {
+		t.Fatalf("sessions = %v, want none", sessions)
+	}
+
+	result := &callcli.Result{Stdout: `{"type":"system","subtype":"init","session_id":"abc-123"}` + "\n"}
+	e.recordSession("core", "parser", stateManager.GetJob("core", "parser"), false, time.Now(), result, nil)
+	e.failSession("core", "parser", errors.New("execution timeout"))
+
+	conversation := &callcli.ConversationData{SessionID: "def-456"}
+	e.recordSession("core", "parser", stateManager.GetJob("core", "parser"), true, time.Now(), &callcli.Result{}, conversation)
+
+	sessions := stateManager.GetJob("core", "parser").Sessions
+	if len(sessions) != 2 {
+		t.Fatalf("sessions = %v, want 2", sessions)
+	}
+	if s := sessions[0]; s.Attempt != 1 || s.ID != "abc-123" || s.Resumed || s.Error != "execution timeout" {
+		t.Errorf("first session = %+v", s)
+	}
+	if s := sessions[1]; s.Attempt != 2 || s.ID != "def-456" || !s.Resumed || s.Error != "" {
+		t.Errorf("second session = %+v", s)
+	}
+}
@@ -51,6 +51,10 @@ func (m *mockAICaller) BuildArgs() []string {
 	return []string{"--mock"}
 }
 
+func (m *mockAICaller) GetBaseCaller() callcli.Caller {
+	return nil
+}
+
 // mockLogger for testing
 type mockTaskRunnerLogger struct {
 	logs []string
@@ -54,6 +54,61 @@ func (m *Manager) CreateLoopCommit(loopNumber int, status string, dir string) (s
 		return "", fmt.Errorf("no changes to commit")
 	}
 
+	return m.commitStaged(loopNumber, status, dir)
+}
+
+// CreateScannedLoopCommit stages all changes, runs the pre-commit scanner and
+// then commits whatever remains staged.
+// If the scanner blocks the commit, a *ScanError is returned together with the
+// scan result. With the unstage action the offending files are left in the
+// working tree and the rest is committed; the result lists what was unstaged.
+func (m *Manager) CreateScannedLoopCommit(loopNumber int, status string, dir string, scanner *Scanner) (string, *ScanResult, error) {
+	if scanner == nil {
+		hash, err := m.CreateLoopCommit(loopNumber, status, dir)
+		return hash, nil, err
+	}
+
+	// Check if it's a git repo first
+	if !m.isGitRepo(dir) {
+		return "", nil, fmt.Errorf("directory %s is not a git repository", dir)
+	}
+
+	// Stage all changes
+	if _, err := m.run(dir, "add", "-A"); err != nil {
+		return "", nil, fmt.Errorf("failed to stage changes: %w", err)
+	}
+
+	result, err := m.ScanStaged(dir, scanner)
+	if err != nil {
+		return "", nil, fmt.Errorf("pre-commit scan failed: %w", err)
+	}
+
+	if err := m.ApplyScanResult(dir, scanner, result); err != nil {
+		return "", result, err
+	}
+
+	if result.Blocked {
+		return "", result, &ScanError{Result: result}
+	}
+
+	// Offending files may have been unstaged, so check the index rather than the worktree
+	staged, err := m.stagedFiles(dir)
+	if err != nil {
+		return "", result, err
+	}
+	if len(staged) == 0 {
+		// Deletions are not listed by stagedFiles, fall back to a full check
+		if _, err := m.run(dir, "diff", "--cached", "--quiet"); err == nil {
+			return "", result, fmt.Errorf("no changes to commit")
+		}
+	}
+
+	hash, err := m.commitStaged(loopNumber, status, dir)
+	return hash, result, err
+}
+
+// commitStaged commits the current index with a loop commit message.
+func (m *Manager) commitStaged(loopNumber int, status string, dir string) (string, error) {
 	// Get change statistics
 	stats, err := m.GetChangeStats(dir)
 	if err != nil {
@@ -61,7 +116,7 @@ func (m *Manager) CreateLoopCommit(loopNumber int, status string, dir string) (s
 	}
 
 	// Build commit message
-	commitMsg := buildCommitMessage(loopNumber, status, stats)
+	commitMsg := AppendTrailers(buildCommitMessage(loopNumber, status, stats), m.trailers)
 
 	// Create commit
 	_, err = m.run(dir, "commit", "-m", commitMsg)
@@ -97,6 +152,37 @@ func buildCommitMessage(loopNumber int, status string, stats *ChangeStats) strin
 	return sb.String()
 }
 
+// TrailerProfile is the trailer recording the configuration profile a
+// commit was made with.
+const TrailerProfile = "Morty-Profile"
+
+// Trailer is a "Key: value" line in the last paragraph of a commit message,
+// as read by git interpret-trailers.
+type Trailer struct {
+	Key   string
+	Value string
+}
+
+// SetTrailers sets the trailers appended to loop commit messages.
+func (m *Manager) SetTrailers(trailers ...Trailer) {
+	m.trailers = trailers
+}
+
+// AppendTrailers appends trailers to a commit message as its last
+// paragraph. Trailers with an empty value are skipped.
+func AppendTrailers(msg string, trailers []Trailer) string {
+	var lines []string
+	for _, t := range trailers {
+		if t.Value != "" {
+			lines = append(lines, t.Key+": "+t.Value)
+		}
+	}
+	if len(lines) == 0 {
+		return msg
+	}
+	return strings.TrimRight(msg, "\n") + "\n\n" + strings.Join(lines, "\n")
+}
+
 // GetCurrentLoopNumber returns the next loop number based on existing commits.
 // It searches commit history for morty loop commits and returns the highest number + 1.
 func (m *Manager) GetCurrentLoopNumber(dir string) (int, error) {
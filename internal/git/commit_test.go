@@ -117,6 +117,42 @@ func TestCreateLoopCommitSuccess(t *testing.T) {
 	}
 }
 
+// TestCreateLoopCommitTrailers tests that trailers set on the manager are
+// readable by git interpret-trailers.
+func TestCreateLoopCommitTrailers(t *testing.T) {
+	mgr := NewManager()
+	tempDir := t.TempDir()
+	if err := mgr.InitIfNeeded(tempDir); err != nil {
+		t.Fatalf("InitIfNeeded failed: %v", err)
+	}
+	mgr.run(tempDir, "config", "user.email", "test@test.com")
+	mgr.run(tempDir, "config", "user.name", "Test User")
+	os.WriteFile(filepath.Join(tempDir, "test.go"), []byte("package main\n"), 0644)
+
+	mgr.SetTrailers(Trailer{Key: TrailerProfile, Value: "ci"}, Trailer{Key: "Morty-Empty"})
+	if _, err := mgr.CreateLoopCommit(1, "COMPLETED", tempDir); err != nil {
+		t.Fatalf("CreateLoopCommit failed: %v", err)
+	}
+
+	trailers, err := mgr.run(tempDir, "log", "-1", "--pretty=format:%(trailers:only)")
+	if err != nil {
+		t.Fatalf("Failed to get trailers: %v", err)
+	}
+	if strings.TrimSpace(trailers) != "Morty-Profile: ci" {
+		t.Errorf("Expected the profile trailer, got %q", trailers)
+	}
+}
+
+func TestAppendTrailers(t *testing.T) {
+	if got := AppendTrailers("subject\n", nil); got != "subject\n" {
+		t.Errorf("Expected no change without trailers, got %q", got)
+	}
+	got := AppendTrailers("subject\n\nbody\n", []Trailer{{Key: "A", Value: "1"}, {Key: "B", Value: "2"}})
+	if got != "subject\n\nbody\n\nA: 1\nB: 2" {
+		t.Errorf("AppendTrailers() = %q", got)
+	}
+}
+
 // TestCreateLoopCommitNoChanges tests commit creation with no changes.
 func TestCreateLoopCommitNoChanges(t *testing.T) {
 	mgr := NewManager()
@@ -3,6 +3,7 @@ package git
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"strconv"
@@ -15,6 +16,8 @@ type Manager struct {
 	// gitPath is the path to the git executable.
 	// If empty, "git" is used (assumes it's in PATH).
 	gitPath string
+	// trailers are appended to loop commit messages.
+	trailers []Trailer
 }
 
 // NewManager creates a new Git Manager instance.
@@ -34,10 +37,18 @@ func NewManagerWithPath(gitPath string) *Manager {
 
 // run executes a git command in the specified directory and returns the output.
 func (m *Manager) run(dir string, args ...string) (string, error) {
+	return m.runEnv(dir, nil, args...)
+}
+
+// runEnv executes a git command with additional environment variables.
+func (m *Manager) runEnv(dir string, env []string, args ...string) (string, error) {
 	cmd := exec.Command(m.gitPath, args...)
 	if dir != "" {
 		cmd.Dir = dir
 	}
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -57,6 +68,31 @@ func (m *Manager) run(dir string, args ...string) (string, error) {
 	return strings.TrimSpace(stdout.String()), nil
 }
 
+// runRaw executes a git command and returns its untrimmed stdout bytes.
+// It is used where exact content matters, such as reading blobs.
+func (m *Manager) runRaw(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command(m.gitPath, args...)
+	if dir != "" {
+		cmd.Dir = dir
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		stderrStr := strings.TrimSpace(stderr.String())
+		if stderrStr != "" {
+			return nil, fmt.Errorf("git %s: %w (stderr: %s)",
+				strings.Join(args, " "), err, stderrStr)
+		}
+		return nil, fmt.Errorf("git %s: %w",
+			strings.Join(args, " "), err)
+	}
+
+	return stdout.Bytes(), nil
+}
+
 // isGitRepo checks if the specified directory is inside a Git repository.
 func (m *Manager) isGitRepo(dir string) bool {
 	_, err := m.run(dir, "rev-parse", "--git-dir")
@@ -99,6 +135,50 @@ func (m *Manager) HasUncommittedChanges(dir string) (bool, error) {
 	return output != "", nil
 }
 
+// SnapshotTree writes the working tree of dir, including untracked files
+// that are not ignored, as a tree object and returns its hash. It stages
+// into a copy of the index, so the index itself is left untouched; diffing
+// two snapshots shows what changed in between, whether or not it was
+// committed.
+func (m *Manager) SnapshotTree(dir string) (string, error) {
+	if !m.isGitRepo(dir) {
+		return "", fmt.Errorf("directory %s is not a git repository", dir)
+	}
+
+	indexPath, err := m.run(dir, "rev-parse", "--git-path", "index")
+	if err != nil {
+		return "", fmt.Errorf("failed to locate the index: %w", err)
+	}
+	if !filepath.IsAbs(indexPath) {
+		indexPath = filepath.Join(dir, indexPath)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "morty-snapshot-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create snapshot index: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Starting from the current index keeps git's stat cache, so only
+	// modified files are hashed
+	tmpIndex := filepath.Join(tmpDir, "index")
+	if data, err := os.ReadFile(indexPath); err == nil {
+		if err := os.WriteFile(tmpIndex, data, 0644); err != nil {
+			return "", fmt.Errorf("failed to create snapshot index: %w", err)
+		}
+	}
+
+	env := []string{"GIT_INDEX_FILE=" + tmpIndex}
+	if _, err := m.runEnv(dir, env, "add", "-A"); err != nil {
+		return "", fmt.Errorf("failed to stage snapshot: %w", err)
+	}
+	tree, err := m.runEnv(dir, env, "write-tree")
+	if err != nil {
+		return "", fmt.Errorf("failed to write snapshot tree: %w", err)
+	}
+	return tree, nil
+}
+
 // GetRepoRoot returns the root directory of the git repository.
 func (m *Manager) GetRepoRoot(dir string) (string, error) {
 	// Check if it's a git repo first
@@ -245,6 +245,48 @@ func TestHasUncommittedChanges_NotARepo(t *testing.T) {
 	}
 }
 
+// TestSnapshotTree tests that snapshots include untracked files, differ
+// only in what changed in between and leave the index untouched.
+func TestSnapshotTree(t *testing.T) {
+	mgr := NewManager()
+	tempDir := t.TempDir()
+	if err := mgr.InitIfNeeded(tempDir); err != nil {
+		t.Fatalf("InitIfNeeded failed: %v", err)
+	}
+	mgr.run(tempDir, "config", "user.email", "test@test.com")
+	mgr.run(tempDir, "config", "user.name", "Test User")
+	os.WriteFile(filepath.Join(tempDir, "tracked.txt"), []byte("v1\n"), 0644)
+	mgr.run(tempDir, "add", "tracked.txt")
+	mgr.run(tempDir, "commit", "-m", "initial commit")
+	os.WriteFile(filepath.Join(tempDir, "before.txt"), []byte("untracked\n"), 0644)
+
+	start, err := mgr.SnapshotTree(tempDir)
+	if err != nil {
+		t.Fatalf("SnapshotTree failed: %v", err)
+	}
+	os.WriteFile(filepath.Join(tempDir, "tracked.txt"), []byte("v2\n"), 0644)
+	os.WriteFile(filepath.Join(tempDir, "after.txt"), []byte("new\n"), 0644)
+	mgr.run(tempDir, "add", "after.txt")
+	mgr.run(tempDir, "commit", "-m", "agent commit")
+	end, err := mgr.SnapshotTree(tempDir)
+	if err != nil {
+		t.Fatalf("SnapshotTree failed: %v", err)
+	}
+
+	diff, err := mgr.run(tempDir, "diff", "--name-status", start, end)
+	if err != nil {
+		t.Fatalf("diff failed: %v", err)
+	}
+	if diff != "A\tafter.txt\nM\ttracked.txt" {
+		t.Errorf("Unexpected snapshot diff:\n%s", diff)
+	}
+
+	staged, _ := mgr.run(tempDir, "diff", "--cached", "--name-only")
+	if staged != "" {
+		t.Errorf("Expected the index to be untouched, staged: %s", staged)
+	}
+}
+
 // TestGetRepoRoot_CurrentDir tests getting repo root from the root itself.
 func TestGetRepoRoot_CurrentDir(t *testing.T) {
 	mgr := NewManager()
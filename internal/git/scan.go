@@ -0,0 +1,452 @@
+// Package git provides Git repository operations for Morty.
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ScanAction defines what happens to staged files that trigger a scan finding.
+type ScanAction string
+
+const (
+	// ScanActionBlock unstages everything and refuses to commit.
+	ScanActionBlock ScanAction = "block"
+	// ScanActionUnstage unstages only the offending files and commits the rest.
+	ScanActionUnstage ScanAction = "unstage"
+)
+
+// FindingKind classifies a scan finding.
+type FindingKind string
+
+const (
+	// FindingSecret indicates content matching a credential rule.
+	FindingSecret FindingKind = "secret"
+	// FindingSensitiveFile indicates a file whose name suggests it holds secrets.
+	FindingSensitiveFile FindingKind = "sensitive_file"
+	// FindingLargeFile indicates a file exceeding the size threshold.
+	FindingLargeFile FindingKind = "large_file"
+	// FindingBinary indicates a binary file.
+	FindingBinary FindingKind = "binary"
+)
+
+// ScanRule is a named regular expression matched against staged file content.
+type ScanRule struct {
+	// Name identifies the rule in findings.
+	Name string
+	// Pattern is the compiled expression matched line by line.
+	Pattern *regexp.Regexp
+}
+
+// ScanConfig configures the pre-commit scanner.
+type ScanConfig struct {
+	// Action is applied to offending files (block or unstage).
+	Action ScanAction
+	// MaxFileSize is the largest staged file size in bytes (0 disables the check).
+	MaxFileSize int64
+	// BlockBinary reports binary files as findings.
+	BlockBinary bool
+	// Rules are additional user-defined content rules.
+	Rules []ScanRule
+	// AllowPaths are glob patterns of paths that are never reported.
+	AllowPaths []string
+}
+
+// Finding describes a single problem detected in a staged file.
+type Finding struct {
+	// File is the repository-relative path.
+	File string
+	// Line is the 1-based line number (0 if not applicable).
+	Line int
+	// Kind classifies the finding.
+	Kind FindingKind
+	// Rule is the name of the rule that matched.
+	Rule string
+	// Message is a human-readable description.
+	Message string
+}
+
+// String formats the finding for logs.
+func (f Finding) String() string {
+	if f.Line > 0 {
+		return fmt.Sprintf("%s:%d: [%s] %s", f.File, f.Line, f.Rule, f.Message)
+	}
+	return fmt.Sprintf("%s: [%s] %s", f.File, f.Rule, f.Message)
+}
+
+// ScanResult holds the outcome of scanning the staged changes.
+type ScanResult struct {
+	// Findings lists every problem detected.
+	Findings []Finding
+	// Unstaged lists the files removed from the index.
+	Unstaged []string
+	// Blocked is true when the commit was refused.
+	Blocked bool
+}
+
+// HasFindings returns true if the scan reported anything.
+func (r *ScanResult) HasFindings() bool {
+	return r != nil && len(r.Findings) > 0
+}
+
+// Files returns the distinct files with findings, in order of first appearance.
+func (r *ScanResult) Files() []string {
+	if r == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var files []string
+	for _, f := range r.Findings {
+		if !seen[f.File] {
+			seen[f.File] = true
+			files = append(files, f.File)
+		}
+	}
+	return files
+}
+
+// Format renders the findings as a multi-line report.
+func (r *ScanResult) Format() string {
+	if !r.HasFindings() {
+		return "No findings\n"
+	}
+	var sb strings.Builder
+	for _, f := range r.Findings {
+		sb.WriteString("- ")
+		sb.WriteString(f.String())
+		sb.WriteString("\n")
+	}
+	if r.Blocked {
+		sb.WriteString("Commit blocked: all changes were unstaged\n")
+	} else if len(r.Unstaged) > 0 {
+		sb.WriteString(fmt.Sprintf("Unstaged: %s\n", strings.Join(r.Unstaged, ", ")))
+	}
+	return sb.String()
+}
+
+// ScanError is returned when the scanner blocks a commit.
+type ScanError struct {
+	Result *ScanResult
+}
+
+// Error implements the error interface.
+func (e *ScanError) Error() string {
+	files := e.Result.Files()
+	return fmt.Sprintf("commit blocked by pre-commit scan: %d finding(s) in %s",
+		len(e.Result.Findings), strings.Join(files, ", "))
+}
+
+// DefaultScanRules returns the built-in credential rules.
+func DefaultScanRules() []ScanRule {
+	return []ScanRule{
+		{Name: "aws-access-key", Pattern: regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+		{Name: "aws-secret-key", Pattern: regexp.MustCompile(`(?i)aws_?secret_?access_?key\s*[:=]\s*["']?[A-Za-z0-9/+=]{40}`)},
+		{Name: "github-token", Pattern: regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+		{Name: "github-pat", Pattern: regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{60,}\b`)},
+		{Name: "anthropic-api-key", Pattern: regexp.MustCompile(`\bsk-ant-[A-Za-z0-9_\-]{20,}`)},
+		{Name: "openai-api-key", Pattern: regexp.MustCompile(`\bsk-(proj-)?[A-Za-z0-9]{32,}\b`)},
+		{Name: "google-api-key", Pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`)},
+		{Name: "slack-token", Pattern: regexp.MustCompile(`\bxox[abposr]-[0-9A-Za-z\-]{10,}`)},
+		{Name: "stripe-key", Pattern: regexp.MustCompile(`\b[sr]k_live_[0-9A-Za-z]{24,}\b`)},
+		{Name: "private-key", Pattern: regexp.MustCompile(`-----BEGIN ([A-Z]+ )?PRIVATE KEY-----`)},
+		{Name: "generic-secret", Pattern: regexp.MustCompile(`(?i)\b(api[_-]?key|secret|passwd|password|access[_-]?token|auth[_-]?token)\b\s*[:=]\s*["'][^"'\s]{8,}["']`)},
+	}
+}
+
+// sensitiveFilePatterns are base-name globs of files that usually hold secrets.
+var sensitiveFilePatterns = []string{
+	".env",
+	".env.*",
+	"*.pem",
+	"*.key",
+	"*.p12",
+	"*.pfx",
+	"id_rsa",
+	"id_dsa",
+	"id_ecdsa",
+	"id_ed25519",
+	".netrc",
+	".npmrc",
+	".pypirc",
+	"credentials.json",
+}
+
+// sensitiveFileExceptions are base-name globs that match sensitiveFilePatterns
+// but are safe to commit.
+var sensitiveFileExceptions = []string{
+	".env.example",
+	".env.sample",
+	".env.template",
+}
+
+// binarySniffLen is the number of leading bytes inspected for NUL bytes,
+// matching git's own binary heuristic.
+const binarySniffLen = 8000
+
+// Scanner inspects staged changes for secrets, large files and binaries.
+type Scanner struct {
+	config ScanConfig
+	rules  []ScanRule
+}
+
+// NewScanner creates a scanner with the built-in rules plus the configured ones.
+func NewScanner(cfg ScanConfig) *Scanner {
+	if cfg.Action == "" {
+		cfg.Action = ScanActionBlock
+	}
+	rules := append(DefaultScanRules(), cfg.Rules...)
+	return &Scanner{
+		config: cfg,
+		rules:  rules,
+	}
+}
+
+// Action returns the configured action.
+func (s *Scanner) Action() ScanAction {
+	return s.config.Action
+}
+
+// CompileScanRule compiles a user-defined rule.
+func CompileScanRule(name, pattern string) (ScanRule, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return ScanRule{}, fmt.Errorf("invalid scan rule %q: %w", name, err)
+	}
+	return ScanRule{Name: name, Pattern: re}, nil
+}
+
+// ScanContent checks a single file's content and size.
+// It is independent of git so it can be used on arbitrary data.
+func (s *Scanner) ScanContent(file string, size int64, content []byte) []Finding {
+	if s.isAllowed(file) {
+		return nil
+	}
+
+	var findings []Finding
+
+	if isSensitiveFileName(file) {
+		findings = append(findings, Finding{
+			File:    file,
+			Kind:    FindingSensitiveFile,
+			Rule:    "sensitive-file",
+			Message: "file name suggests it contains credentials",
+		})
+	}
+
+	if s.config.MaxFileSize > 0 && size > s.config.MaxFileSize {
+		findings = append(findings, Finding{
+			File:    file,
+			Kind:    FindingLargeFile,
+			Rule:    "max-file-size",
+			Message: fmt.Sprintf("file size %d bytes exceeds limit of %d bytes", size, s.config.MaxFileSize),
+		})
+		// Don't scan content of oversized files
+		return findings
+	}
+
+	if isBinary(content) {
+		if s.config.BlockBinary {
+			findings = append(findings, Finding{
+				File:    file,
+				Kind:    FindingBinary,
+				Rule:    "binary-file",
+				Message: "binary file staged for commit",
+			})
+		}
+		return findings
+	}
+
+	lines := bytes.Split(content, []byte("\n"))
+	for i, line := range lines {
+		for _, rule := range s.rules {
+			if rule.Pattern.Match(line) {
+				findings = append(findings, Finding{
+					File:    file,
+					Line:    i + 1,
+					Kind:    FindingSecret,
+					Rule:    rule.Name,
+					Message: "possible credential detected",
+				})
+			}
+		}
+	}
+
+	return findings
+}
+
+// isAllowed checks whether a path matches one of the allow globs.
+func (s *Scanner) isAllowed(file string) bool {
+	for _, pattern := range s.config.AllowPaths {
+		if ok, _ := path.Match(pattern, file); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(file)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isSensitiveFileName checks the base name against known secret file patterns.
+func isSensitiveFileName(file string) bool {
+	base := path.Base(file)
+	for _, pattern := range sensitiveFileExceptions {
+		if ok, _ := path.Match(pattern, base); ok {
+			return false
+		}
+	}
+	for _, pattern := range sensitiveFilePatterns {
+		if ok, _ := path.Match(pattern, base); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isBinary reports whether content looks binary (NUL byte in the leading bytes).
+func isBinary(content []byte) bool {
+	sniff := content
+	if len(sniff) > binarySniffLen {
+		sniff = sniff[:binarySniffLen]
+	}
+	return bytes.IndexByte(sniff, 0) != -1
+}
+
+// ScanStaged scans all staged additions and modifications in the repository.
+// Deleted files are ignored. The index is not modified.
+func (m *Manager) ScanStaged(dir string, scanner *Scanner) (*ScanResult, error) {
+	if !m.isGitRepo(dir) {
+		return nil, fmt.Errorf("directory %s is not a git repository", dir)
+	}
+
+	files, err := m.stagedFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ScanResult{}
+	for _, file := range files {
+		size, err := m.stagedSize(dir, file)
+		if err != nil {
+			return nil, err
+		}
+
+		var content []byte
+		if scanner.config.MaxFileSize <= 0 || size <= scanner.config.MaxFileSize {
+			out, err := m.runRaw(dir, "show", ":"+file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read staged content of %s: %w", file, err)
+			}
+			content = out
+		}
+
+		result.Findings = append(result.Findings, scanner.ScanContent(file, size, content)...)
+	}
+
+	return result, nil
+}
+
+// ScanChanges stages all changes and scans them without committing, so a
+// caller can find out whether its commit would be blocked before making it.
+// If the scanner blocks, everything is unstaged again and a *ScanError is
+// returned with the result. Findings that would only be unstaged are reported
+// in the result but leave the index alone.
+func (m *Manager) ScanChanges(dir string, scanner *Scanner) (*ScanResult, error) {
+	if !m.isGitRepo(dir) {
+		return nil, fmt.Errorf("directory %s is not a git repository", dir)
+	}
+
+	if _, err := m.run(dir, "add", "-A"); err != nil {
+		return nil, fmt.Errorf("failed to stage changes: %w", err)
+	}
+
+	result, err := m.ScanStaged(dir, scanner)
+	if err != nil {
+		return nil, fmt.Errorf("pre-commit scan failed: %w", err)
+	}
+
+	if scanner.Action() != ScanActionBlock || !result.HasFindings() {
+		return result, nil
+	}
+	if err := m.ApplyScanResult(dir, scanner, result); err != nil {
+		return result, err
+	}
+	return result, &ScanError{Result: result}
+}
+
+// ApplyScanResult unstages files according to the scanner action.
+// With ScanActionBlock everything is unstaged; with ScanActionUnstage only the
+// offending files are. The working tree is never touched.
+func (m *Manager) ApplyScanResult(dir string, scanner *Scanner, result *ScanResult) error {
+	if !result.HasFindings() {
+		return nil
+	}
+
+	if scanner.Action() == ScanActionBlock {
+		if err := m.unstage(dir); err != nil {
+			return fmt.Errorf("failed to unstage changes: %w", err)
+		}
+		result.Blocked = true
+		return nil
+	}
+
+	files := result.Files()
+	if err := m.unstage(dir, files...); err != nil {
+		return fmt.Errorf("failed to unstage offending files: %w", err)
+	}
+	result.Unstaged = files
+	return nil
+}
+
+// stagedFiles lists staged paths that were added, copied, modified or renamed.
+func (m *Manager) stagedFiles(dir string) ([]string, error) {
+	output, err := m.run(dir, "diff", "--cached", "--name-only", "--diff-filter=ACMR", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list staged files: %w", err)
+	}
+
+	var files []string
+	for _, f := range strings.Split(output, "\x00") {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// stagedSize returns the size in bytes of a staged blob.
+func (m *Manager) stagedSize(dir, file string) (int64, error) {
+	output, err := m.run(dir, "cat-file", "-s", ":"+file)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get staged size of %s: %w", file, err)
+	}
+	size, err := strconv.ParseInt(output, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size for %s: %w", file, err)
+	}
+	return size, nil
+}
+
+// unstage removes paths (or everything when none are given) from the index.
+// Repositories without commits have no HEAD to reset to, so git rm --cached is used instead.
+func (m *Manager) unstage(dir string, files ...string) error {
+	if _, err := m.run(dir, "rev-parse", "--verify", "HEAD"); err != nil {
+		args := []string{"rm", "--cached", "-r", "-q", "--"}
+		if len(files) == 0 {
+			args = append(args, ".")
+		} else {
+			args = append(args, files...)
+		}
+		_, err := m.run(dir, args...)
+		return err
+	}
+
+	args := []string{"reset", "-q", "--"}
+	args = append(args, files...)
+	_, err := m.run(dir, args...)
+	return err
+}
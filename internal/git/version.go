@@ -31,6 +31,10 @@ type LoopCommit struct {
 	LoopNumber int
 	// Status is the status extracted from the commit message (e.g., COMPLETED, RUNNING).
 	Status string
+	// Module is the module of a job commit ("morty: loop N - module/job - STATUS").
+	Module string
+	// Job is the job of a job commit.
+	Job string
 	// Message is the full commit message subject line.
 	Message string
 	// Author is the commit author name.
@@ -189,6 +193,31 @@ func (m *Manager) ShowLoopHistory(n int, dir string) ([]LoopCommit, error) {
 	return loopCommits, nil
 }
 
+// LoopCommits returns every loop commit in history, newest first.
+func (m *Manager) LoopCommits(dir string) ([]LoopCommit, error) {
+	if !m.isGitRepo(dir) {
+		return nil, fmt.Errorf("directory %s is not a git repository", dir)
+	}
+
+	output, err := m.run(dir, "log", "--pretty=format:%H|%an|%at|%s")
+	if err != nil {
+		if strings.Contains(err.Error(), "does not have any commits yet") {
+			return []LoopCommit{}, nil
+		}
+		return nil, fmt.Errorf("failed to get commit log: %w", err)
+	}
+
+	loopCommits := []LoopCommit{}
+	for _, line := range strings.Split(output, "\n") {
+		commit, err := m.parseLogLine(line)
+		if err != nil || commit.LoopNumber == 0 {
+			continue
+		}
+		loopCommits = append(loopCommits, *commit)
+	}
+	return loopCommits, nil
+}
+
 // parseLogLine parses a single log line in the format: hash|author|date|subject
 func (m *Manager) parseLogLine(line string) (*LoopCommit, error) {
 	parts := strings.SplitN(line, "|", 4)
@@ -234,16 +263,19 @@ func (m *Manager) ParseCommitMessage(message string) (*LoopCommit, error) {
 
 	// Try to match the extended format first: "morty: loop N - module/job - STATUS"
 	// This handles commit messages like "morty: loop 3 - sudoku/job_3 - COMPLETED"
-	reExtended := regexp.MustCompile(`(?i)morty:\s*loop\s+(\d+)\s*-\s*[\w/]+\s*-\s*(\w+)`)
+	// Job names may be free text, e.g. "morty: loop 4 - auth/登录 接口 - COMPLETED"
+	reExtended := regexp.MustCompile(`(?i)morty:\s*loop\s+(\d+)\s*-\s*([\w-]+)/(.+?)\s+-\s*(\w+)\s*$`)
 	matches := reExtended.FindStringSubmatch(message)
 
-	if len(matches) >= 3 {
+	if len(matches) >= 5 {
 		loopNum, err := strconv.Atoi(matches[1])
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse loop number: %w", err)
 		}
 		commit.LoopNumber = loopNum
-		commit.Status = strings.ToUpper(matches[2])
+		commit.Module = matches[2]
+		commit.Job = matches[3]
+		commit.Status = strings.ToUpper(matches[4])
 	} else {
 		// Try the simple format: "morty: loop [number] - [status]"
 		re := regexp.MustCompile(`(?i)morty:\s*loop\s+(\d+)\s*-\s*(\w+)`)
@@ -763,3 +763,57 @@ func TestShowLoopHistoryMixedCommits(t *testing.T) {
 		t.Errorf("Expected 3 loop commits, got %d", len(history))
 	}
 }
+
+// TestParseCommitMessageJob tests extracting the module and job of job commits.
+func TestParseCommitMessageJob(t *testing.T) {
+	mgr := NewManager()
+
+	tests := []struct {
+		message string
+		module  string
+		job     string
+		status  string
+	}{
+		{"morty: loop 3 - sudoku/job_3 - COMPLETED", "sudoku", "job_3", "COMPLETED"},
+		{"morty: loop 4 - auth/登录 接口 - COMPLETED", "auth", "登录 接口", "COMPLETED"},
+		{"morty: loop 5 - COMPLETED", "", "", "COMPLETED"},
+	}
+
+	for _, tc := range tests {
+		commit, err := mgr.ParseCommitMessage(tc.message)
+		if err != nil {
+			t.Errorf("Failed to parse '%s': %v", tc.message, err)
+			continue
+		}
+		if commit.Module != tc.module || commit.Job != tc.job || commit.Status != tc.status {
+			t.Errorf("For '%s': got %q/%q - %s, want %q/%q - %s",
+				tc.message, commit.Module, commit.Job, commit.Status, tc.module, tc.job, tc.status)
+		}
+	}
+}
+
+// TestLoopCommits tests listing every loop commit.
+func TestLoopCommits(t *testing.T) {
+	mgr := NewManager()
+	tempDir := t.TempDir()
+
+	if err := mgr.InitIfNeeded(tempDir); err != nil {
+		t.Fatalf("InitIfNeeded failed: %v", err)
+	}
+	mgr.run(tempDir, "config", "user.email", "test@example.com")
+	mgr.run(tempDir, "config", "user.name", "Test User")
+
+	for i, message := range []string{"initial commit", "morty: loop 1 - auth/job_1 - COMPLETED", "morty: loop 2 - auth/job_2 - COMPLETED"} {
+		os.WriteFile(filepath.Join(tempDir, fmt.Sprintf("file%d.txt", i)), []byte("content"), 0644)
+		mgr.run(tempDir, "add", "-A")
+		mgr.run(tempDir, "commit", "-m", message)
+	}
+
+	commits, err := mgr.LoopCommits(tempDir)
+	if err != nil {
+		t.Fatalf("LoopCommits failed: %v", err)
+	}
+	if len(commits) != 2 || commits[0].Job != "job_2" || commits[1].Job != "job_1" {
+		t.Errorf("Unexpected loop commits: %+v", commits)
+	}
+}
@@ -0,0 +1,561 @@
+// Package graph builds the module/job dependency graph of a plan and exports
+// it as DOT, Mermaid or JSON.
+package graph
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/morty/morty/internal/state"
+)
+
+// Level selects the granularity of the graph.
+type Level string
+
+const (
+	// LevelModule builds one node per module.
+	LevelModule Level = "module"
+	// LevelJob builds one node per job, grouped by module.
+	LevelJob Level = "job"
+)
+
+// NodeKind identifies what a node represents.
+type NodeKind string
+
+const (
+	// NodeModule is a module node.
+	NodeModule NodeKind = "module"
+	// NodeJob is a job node.
+	NodeJob NodeKind = "job"
+)
+
+// Node is a module or job in the dependency graph.
+type Node struct {
+	// ID uniquely identifies the node ("module" or "module/job").
+	ID string `json:"id"`
+	// Kind is the node kind.
+	Kind NodeKind `json:"kind"`
+	// Module is the module name (plan file name without .md).
+	Module string `json:"module"`
+	// Job is the job name (empty for module nodes).
+	Job string `json:"job,omitempty"`
+	// Label is the human-readable name.
+	Label string `json:"label"`
+	// Status is the execution status from status.json (PENDING if unknown).
+	Status state.Status `json:"status"`
+	// TasksTotal is the number of tasks (job nodes) or jobs (module nodes).
+	TasksTotal int `json:"tasks_total"`
+	// TasksCompleted is the number of completed tasks or jobs.
+	TasksCompleted int `json:"tasks_completed"`
+	// Critical marks nodes on the critical path.
+	Critical bool `json:"critical,omitempty"`
+	// Ready marks nodes whose dependencies are all completed.
+	Ready bool `json:"ready,omitempty"`
+}
+
+// Edge is a dependency: To depends on From.
+type Edge struct {
+	// From is the prerequisite node ID.
+	From string `json:"from"`
+	// To is the dependent node ID.
+	To string `json:"to"`
+	// Implied marks job edges derived from module dependencies.
+	Implied bool `json:"implied,omitempty"`
+	// Critical marks edges on the critical path.
+	Critical bool `json:"critical,omitempty"`
+}
+
+// Graph is a dependency graph of modules or jobs.
+type Graph struct {
+	// Level is the graph granularity.
+	Level Level `json:"level"`
+	// Nodes are listed in execution (topological) order.
+	Nodes []*Node `json:"nodes"`
+	// Edges point from prerequisite to dependent.
+	Edges []Edge `json:"edges"`
+	// CriticalPath is the longest chain of remaining work, set by MarkCriticalPath.
+	CriticalPath []string `json:"critical_path,omitempty"`
+	// Ready lists nodes that can run now, set by MarkReady.
+	Ready []string `json:"ready,omitempty"`
+	// Unresolved lists dependency references that match no module or job.
+	Unresolved []string `json:"unresolved,omitempty"`
+
+	index map[string]*Node
+	deps  map[string][]string
+}
+
+// CycleError reports a dependency cycle.
+type CycleError struct {
+	// Kind is "module" or "job".
+	Kind string
+	// Cycle is the closed path, first node repeated at the end.
+	Cycle []string
+}
+
+// Error implements the error interface.
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("%s dependency cycle: %s", e.Kind, state.FormatCycle(e.Cycle))
+}
+
+// jobRefPattern matches "job_N" optionally followed by " - description".
+var jobRefPattern = regexp.MustCompile(`^job_(\d+)(?:\s*-\s*.*)?$`)
+
+// Build creates the dependency graph from parsed plans.
+// status may be nil, in which case every node is PENDING.
+// A *CycleError is returned if the modules or jobs contain a cycle.
+func Build(plans []state.PlanInfo, status *state.ExecutionStatus, level Level) (*Graph, error) {
+	if level == "" {
+		level = LevelModule
+	}
+	if level != LevelModule && level != LevelJob {
+		return nil, fmt.Errorf("unknown graph level: %s", level)
+	}
+
+	g := &Graph{
+		Level: level,
+		index: make(map[string]*Node),
+		deps:  make(map[string][]string),
+	}
+
+	// Resolve module references by name or display name
+	moduleByRef := make(map[string]*state.PlanInfo)
+	for i := range plans {
+		moduleByRef[plans[i].Name] = &plans[i]
+		if plans[i].DisplayName != "" {
+			if _, exists := moduleByRef[plans[i].DisplayName]; !exists {
+				moduleByRef[plans[i].DisplayName] = &plans[i]
+			}
+		}
+	}
+
+	moduleDeps := make(map[string][]string)
+	for _, p := range plans {
+		moduleDeps[p.Name] = []string{}
+		for _, dep := range p.Dependencies {
+			if dep == "__ALL__" {
+				for _, other := range plans {
+					if other.Name != p.Name {
+						moduleDeps[p.Name] = append(moduleDeps[p.Name], other.Name)
+					}
+				}
+				continue
+			}
+			target, ok := moduleByRef[dep]
+			if !ok {
+				g.Unresolved = append(g.Unresolved, fmt.Sprintf("%s -> %s", p.Name, dep))
+				continue
+			}
+			moduleDeps[p.Name] = append(moduleDeps[p.Name], target.Name)
+		}
+	}
+
+	if cycle := state.FindCycle(moduleDeps); cycle != nil {
+		return nil, &CycleError{Kind: "module", Cycle: cycle}
+	}
+
+	if level == LevelModule {
+		for _, p := range plans {
+			g.addNode(moduleNode(p, status))
+		}
+		for _, p := range plans {
+			for _, dep := range moduleDeps[p.Name] {
+				g.addEdge(Edge{From: dep, To: p.Name})
+			}
+		}
+	} else {
+		if err := g.buildJobs(plans, status, moduleByRef, moduleDeps); err != nil {
+			return nil, err
+		}
+	}
+
+	g.sortTopologically()
+	return g, nil
+}
+
+// buildJobs adds job nodes and their explicit and implied edges.
+func (g *Graph) buildJobs(plans []state.PlanInfo, status *state.ExecutionStatus, moduleByRef map[string]*state.PlanInfo, moduleDeps map[string][]string) error {
+	jobIDByIndex := make(map[string]map[int]string)
+	for _, p := range plans {
+		jobIDByIndex[p.Name] = make(map[int]string)
+		for _, job := range p.Jobs {
+			node := jobNode(p, job, status)
+			g.addNode(node)
+			jobIDByIndex[p.Name][job.Index] = node.ID
+		}
+	}
+
+	// Explicit prerequisites
+	intra := make(map[string][]string)
+	for _, p := range plans {
+		for _, job := range p.Jobs {
+			id := JobID(p.Name, job.Name)
+			for _, prereq := range job.Prerequisites {
+				from, ok := resolveJobRef(strings.TrimSpace(prereq), p.Name, moduleByRef, jobIDByIndex)
+				if !ok {
+					continue
+				}
+				if from == "" {
+					g.Unresolved = append(g.Unresolved, fmt.Sprintf("%s -> %s", id, prereq))
+					continue
+				}
+				g.addEdge(Edge{From: from, To: id})
+				if g.index[from].Module == p.Name {
+					intra[id] = append(intra[id], from)
+				}
+			}
+		}
+	}
+
+	// Module dependencies imply that the last jobs of a dependency run
+	// before the first jobs of the dependent module.
+	for _, p := range plans {
+		sources := moduleJobEnds(p, intra, false)
+		for _, dep := range moduleDeps[p.Name] {
+			sinks := moduleJobEnds(*moduleByRef[dep], intra, true)
+			for _, from := range sinks {
+				for _, to := range sources {
+					if !g.hasEdge(from, to) {
+						g.addEdge(Edge{From: from, To: to, Implied: true})
+					}
+				}
+			}
+		}
+	}
+
+	if cycle := state.FindCycle(g.deps); cycle != nil {
+		return &CycleError{Kind: "job", Cycle: cycle}
+	}
+	return nil
+}
+
+// resolveJobRef resolves a prerequisite to a job node ID.
+// ok is false for descriptive prerequisites that are not job references;
+// an empty ID with ok set means the reference points at a missing job.
+func resolveJobRef(prereq, module string, moduleByRef map[string]*state.PlanInfo, jobIDByIndex map[string]map[int]string) (string, bool) {
+	targetModule := module
+	ref := prereq
+
+	if idx := strings.Index(prereq, ":job_"); idx > 0 {
+		target, exists := moduleByRef[strings.TrimSpace(prereq[:idx])]
+		if !exists {
+			return "", true
+		}
+		targetModule = target.Name
+		ref = strings.TrimSpace(prereq[idx+1:])
+	}
+
+	matches := jobRefPattern.FindStringSubmatch(ref)
+	if matches == nil {
+		return "", false
+	}
+
+	var jobIndex int
+	fmt.Sscanf(matches[1], "%d", &jobIndex)
+	return jobIDByIndex[targetModule][jobIndex], true
+}
+
+// moduleJobEnds returns the jobs of a module with no intra-module
+// prerequisites (sinks=false) or no intra-module dependents (sinks=true).
+func moduleJobEnds(p state.PlanInfo, intra map[string][]string, sinks bool) []string {
+	hasDependent := make(map[string]bool)
+	for _, job := range p.Jobs {
+		for _, from := range intra[JobID(p.Name, job.Name)] {
+			hasDependent[from] = true
+		}
+	}
+
+	var ends []string
+	for _, job := range p.Jobs {
+		id := JobID(p.Name, job.Name)
+		if sinks && !hasDependent[id] {
+			ends = append(ends, id)
+		}
+		if !sinks && len(intra[id]) == 0 {
+			ends = append(ends, id)
+		}
+	}
+	return ends
+}
+
+// moduleNode creates a module node with status aggregated from its jobs.
+func moduleNode(p state.PlanInfo, status *state.ExecutionStatus) *Node {
+	node := &Node{
+		ID:         p.Name,
+		Kind:       NodeModule,
+		Module:     p.Name,
+		Label:      displayName(p),
+		Status:     state.StatusPending,
+		TasksTotal: len(p.Jobs),
+	}
+
+	if status == nil {
+		return node
+	}
+	module := status.GetModuleByName(p.Name)
+	if module == nil {
+		return node
+	}
+
+	node.TasksTotal = len(module.Jobs)
+	var running, interrupted, failed, blocked int
+	for _, job := range module.Jobs {
+		switch job.Status {
+		case state.StatusCompleted:
+			node.TasksCompleted++
+		case state.StatusRunning:
+			running++
+		case state.StatusInterrupted:
+			interrupted++
+		case state.StatusFailed:
+			failed++
+		case state.StatusBlocked:
+			blocked++
+		}
+	}
+
+	switch {
+	case node.TasksTotal > 0 && node.TasksCompleted == node.TasksTotal:
+		node.Status = state.StatusCompleted
+	case running > 0:
+		node.Status = state.StatusRunning
+	case interrupted > 0:
+		node.Status = state.StatusInterrupted
+	case failed > 0:
+		node.Status = state.StatusFailed
+	case blocked > 0:
+		node.Status = state.StatusBlocked
+	}
+	return node
+}
+
+// jobNode creates a job node with status from status.json.
+func jobNode(p state.PlanInfo, job state.JobInfo, status *state.ExecutionStatus) *Node {
+	node := &Node{
+		ID:         JobID(p.Name, job.Name),
+		Kind:       NodeJob,
+		Module:     p.Name,
+		Job:        job.Name,
+		Label:      job.Name,
+		Status:     state.StatusPending,
+		TasksTotal: len(job.Tasks),
+	}
+
+	if status == nil {
+		return node
+	}
+	if module := status.GetModuleByName(p.Name); module != nil {
+		if js := module.GetJobByName(job.Name); js != nil {
+			node.Status = js.Status
+			node.TasksTotal = js.TasksTotal
+			node.TasksCompleted = js.TasksCompleted
+		}
+	}
+	return node
+}
+
+// displayName returns the module's display name, falling back to its file name.
+func displayName(p state.PlanInfo) string {
+	if p.DisplayName != "" {
+		return p.DisplayName
+	}
+	return p.Name
+}
+
+// JobID returns the node ID of a job ("module/job").
+func JobID(module, job string) string {
+	return module + "/" + job
+}
+
+// Node returns the node with the given ID, or nil.
+func (g *Graph) Node(id string) *Node {
+	return g.index[id]
+}
+
+// Dependencies returns the IDs of the nodes id depends on.
+func (g *Graph) Dependencies(id string) []string {
+	return g.deps[id]
+}
+
+// Downstream returns every node that directly or transitively depends on
+// id, in execution order.
+func (g *Graph) Downstream(id string) []string {
+	reached := map[string]bool{id: true}
+	var result []string
+	// Nodes are topologically sorted, so a single pass reaches every dependent
+	for _, n := range g.Nodes {
+		if reached[n.ID] {
+			continue
+		}
+		for _, dep := range g.deps[n.ID] {
+			if reached[dep] {
+				reached[n.ID] = true
+				result = append(result, n.ID)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// addNode registers a node.
+func (g *Graph) addNode(n *Node) {
+	g.Nodes = append(g.Nodes, n)
+	g.index[n.ID] = n
+	if _, ok := g.deps[n.ID]; !ok {
+		g.deps[n.ID] = []string{}
+	}
+}
+
+// addEdge registers an edge between existing nodes.
+func (g *Graph) addEdge(e Edge) {
+	g.Edges = append(g.Edges, e)
+	g.deps[e.To] = append(g.deps[e.To], e.From)
+}
+
+// hasEdge reports whether an edge from -> to exists.
+func (g *Graph) hasEdge(from, to string) bool {
+	for _, dep := range g.deps[to] {
+		if dep == from {
+			return true
+		}
+	}
+	return false
+}
+
+// sortTopologically orders nodes so prerequisites come first.
+// Ties keep the original plan order. The graph must be acyclic.
+func (g *Graph) sortTopologically() {
+	position := make(map[string]int)
+	for i, n := range g.Nodes {
+		position[n.ID] = i
+	}
+
+	inDegree := make(map[string]int)
+	dependents := make(map[string][]string)
+	for _, n := range g.Nodes {
+		inDegree[n.ID] = len(g.deps[n.ID])
+		for _, dep := range g.deps[n.ID] {
+			dependents[dep] = append(dependents[dep], n.ID)
+		}
+	}
+
+	var queue []string
+	for _, n := range g.Nodes {
+		if inDegree[n.ID] == 0 {
+			queue = append(queue, n.ID)
+		}
+	}
+
+	sorted := make([]*Node, 0, len(g.Nodes))
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		sorted = append(sorted, g.index[current])
+
+		for _, next := range dependents[current] {
+			inDegree[next]--
+			if inDegree[next] == 0 {
+				queue = append(queue, next)
+				sort.Slice(queue, func(i, j int) bool { return position[queue[i]] < position[queue[j]] })
+			}
+		}
+	}
+
+	g.Nodes = sorted
+
+	// Keep edges in the same order as their endpoints
+	order := make(map[string]int)
+	for i, n := range sorted {
+		order[n.ID] = i
+	}
+	sort.SliceStable(g.Edges, func(i, j int) bool {
+		if order[g.Edges[i].To] != order[g.Edges[j].To] {
+			return order[g.Edges[i].To] < order[g.Edges[j].To]
+		}
+		return order[g.Edges[i].From] < order[g.Edges[j].From]
+	})
+}
+
+// remainingWork returns the amount of unfinished work a node represents.
+func remainingWork(n *Node) int {
+	if n.Status == state.StatusCompleted {
+		return 0
+	}
+	if n.Kind == NodeModule {
+		if n.TasksTotal == 0 {
+			return 1
+		}
+		return n.TasksTotal - n.TasksCompleted
+	}
+	return 1
+}
+
+// MarkCriticalPath finds the longest chain of remaining work, measured in
+// unfinished jobs, and marks its nodes and edges. Completed prerequisites are
+// not part of the path. It returns the node IDs in execution order.
+func (g *Graph) MarkCriticalPath() []string {
+	dist := make(map[string]int)
+	prev := make(map[string]string)
+
+	best := ""
+	for _, n := range g.Nodes {
+		maxDep := 0
+		for _, dep := range g.deps[n.ID] {
+			if dist[dep] > maxDep {
+				maxDep = dist[dep]
+				prev[n.ID] = dep
+			}
+		}
+		dist[n.ID] = maxDep + remainingWork(n)
+		if dist[n.ID] > 0 && (best == "" || dist[n.ID] > dist[best]) {
+			best = n.ID
+		}
+	}
+
+	var path []string
+	for id := best; id != ""; id = prev[id] {
+		path = append([]string{id}, path...)
+	}
+
+	onPath := make(map[string]bool)
+	for _, id := range path {
+		onPath[id] = true
+		g.index[id].Critical = true
+	}
+	for i := range g.Edges {
+		e := &g.Edges[i]
+		if onPath[e.From] && onPath[e.To] && prev[e.To] == e.From {
+			e.Critical = true
+		}
+	}
+
+	g.CriticalPath = path
+	return path
+}
+
+// MarkReady marks PENDING nodes whose dependencies are all COMPLETED.
+// It returns the ready node IDs in execution order.
+func (g *Graph) MarkReady() []string {
+	var ready []string
+	for _, n := range g.Nodes {
+		if n.Status != state.StatusPending {
+			continue
+		}
+		satisfied := true
+		for _, dep := range g.deps[n.ID] {
+			if g.index[dep].Status != state.StatusCompleted {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			n.Ready = true
+			ready = append(ready, n.ID)
+		}
+	}
+
+	g.Ready = ready
+	return ready
+}
@@ -0,0 +1,315 @@
+package graph
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/morty/morty/internal/state"
+)
+
+// testPlans returns three modules: core (2 jobs), api (depends on core) and
+// cli (depends on api, with a cross-module job prerequisite).
+func testPlans() []state.PlanInfo {
+	return []state.PlanInfo{
+		{
+			Name:         "cli",
+			DisplayName:  "命令行",
+			Dependencies: []string{"api"},
+			Jobs: []state.JobInfo{
+				{Index: 1, Name: "cli_main", Prerequisites: []string{"core:job_2 - 存储完成"}},
+			},
+		},
+		{
+			Name: "core",
+			Jobs: []state.JobInfo{
+				{Index: 1, Name: "core_types"},
+				{Index: 2, Name: "core_store", Prerequisites: []string{"job_1", "数据库可用"}},
+			},
+		},
+		{
+			Name:         "api",
+			Dependencies: []string{"core"},
+			Jobs: []state.JobInfo{
+				{Index: 1, Name: "api_routes", Tasks: []state.TaskInfo{{Index: 1}, {Index: 2}}},
+			},
+		},
+	}
+}
+
+// testStatus returns a status with core completed and api running.
+func testStatus() *state.ExecutionStatus {
+	return &state.ExecutionStatus{
+		Modules: []state.ModuleState{
+			{Name: "core", Jobs: []state.JobState{
+				{Name: "core_types", Status: state.StatusCompleted},
+				{Name: "core_store", Status: state.StatusCompleted},
+			}},
+			{Name: "api", Jobs: []state.JobState{
+				{Name: "api_routes", Status: state.StatusRunning, TasksTotal: 2, TasksCompleted: 1},
+			}},
+			{Name: "cli", Jobs: []state.JobState{
+				{Name: "cli_main", Status: state.StatusPending},
+			}},
+		},
+	}
+}
+
+func nodeIDs(g *Graph) []string {
+	var ids []string
+	for _, n := range g.Nodes {
+		ids = append(ids, n.ID)
+	}
+	return ids
+}
+
+// TestBuildModuleLevel tests module nodes, edges and topological order.
+func TestBuildModuleLevel(t *testing.T) {
+	g, err := Build(testPlans(), nil, LevelModule)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if got, want := nodeIDs(g), []string{"core", "api", "cli"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected order %v, got %v", want, got)
+	}
+	if len(g.Edges) != 2 {
+		t.Errorf("Expected 2 edges, got %v", g.Edges)
+	}
+	if deps := g.Dependencies("cli"); !reflect.DeepEqual(deps, []string{"api"}) {
+		t.Errorf("Expected cli to depend on api, got %v", deps)
+	}
+	for _, n := range g.Nodes {
+		if n.Status != state.StatusPending {
+			t.Errorf("Expected %s to be PENDING without status, got %s", n.ID, n.Status)
+		}
+	}
+	if g.Node("cli").Label != "命令行" {
+		t.Errorf("Expected display name as label, got %q", g.Node("cli").Label)
+	}
+}
+
+// TestBuildModuleStatus tests that module status is aggregated from jobs.
+func TestBuildModuleStatus(t *testing.T) {
+	g, err := Build(testPlans(), testStatus(), LevelModule)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	tests := map[string]state.Status{
+		"core": state.StatusCompleted,
+		"api":  state.StatusRunning,
+		"cli":  state.StatusPending,
+	}
+	for id, want := range tests {
+		if got := g.Node(id).Status; got != want {
+			t.Errorf("Node %s status = %s, want %s", id, got, want)
+		}
+	}
+}
+
+// TestBuildJobLevel tests explicit, cross-module and implied job edges.
+func TestBuildJobLevel(t *testing.T) {
+	g, err := Build(testPlans(), testStatus(), LevelJob)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	want := []string{"core/core_types", "core/core_store", "api/api_routes", "cli/cli_main"}
+	if got := nodeIDs(g); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected order %v, got %v", want, got)
+	}
+
+	edges := make(map[string]bool)
+	for _, e := range g.Edges {
+		edges[e.From+"->"+e.To] = e.Implied
+	}
+
+	expected := map[string]bool{
+		"core/core_types->core/core_store": false,
+		"core/core_store->cli/cli_main":    false,
+		"core/core_store->api/api_routes":  true,
+		"api/api_routes->cli/cli_main":     true,
+	}
+	for edge, implied := range expected {
+		gotImplied, ok := edges[edge]
+		if !ok {
+			t.Errorf("Missing edge %s", edge)
+			continue
+		}
+		if gotImplied != implied {
+			t.Errorf("Edge %s implied = %v, want %v", edge, gotImplied, implied)
+		}
+	}
+
+	if n := g.Node("api/api_routes"); n.Status != state.StatusRunning || n.TasksCompleted != 1 {
+		t.Errorf("Expected api_routes RUNNING 1/2, got %s %d/%d", n.Status, n.TasksCompleted, n.TasksTotal)
+	}
+}
+
+// TestBuildUnresolved tests that unknown references are reported, not fatal.
+func TestBuildUnresolved(t *testing.T) {
+	plans := []state.PlanInfo{
+		{Name: "a", Dependencies: []string{"missing"}, Jobs: []state.JobInfo{
+			{Index: 1, Name: "a1", Prerequisites: []string{"job_9", "other:job_1"}},
+		}},
+	}
+
+	g, err := Build(plans, nil, LevelJob)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if len(g.Unresolved) != 3 {
+		t.Errorf("Expected 3 unresolved references, got %v", g.Unresolved)
+	}
+}
+
+// TestBuildModuleCycle tests that the exact module cycle is reported.
+func TestBuildModuleCycle(t *testing.T) {
+	plans := []state.PlanInfo{
+		{Name: "a", Dependencies: []string{"b"}},
+		{Name: "b", Dependencies: []string{"c"}},
+		{Name: "c", Dependencies: []string{"a"}},
+		{Name: "d", Dependencies: []string{"a"}},
+	}
+
+	_, err := Build(plans, nil, LevelModule)
+	var cycleErr *CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected *CycleError, got %v", err)
+	}
+	if want := []string{"a", "b", "c", "a"}; !reflect.DeepEqual(cycleErr.Cycle, want) {
+		t.Errorf("Expected cycle %v, got %v", want, cycleErr.Cycle)
+	}
+	if !strings.Contains(err.Error(), "a -> b -> c -> a") {
+		t.Errorf("Expected cycle in message, got %q", err.Error())
+	}
+}
+
+// TestBuildJobCycle tests that job cycles are reported with job IDs.
+func TestBuildJobCycle(t *testing.T) {
+	plans := []state.PlanInfo{
+		{Name: "m", Jobs: []state.JobInfo{
+			{Index: 1, Name: "first", Prerequisites: []string{"job_2"}},
+			{Index: 2, Name: "second", Prerequisites: []string{"job_1"}},
+		}},
+	}
+
+	_, err := Build(plans, nil, LevelJob)
+	var cycleErr *CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected *CycleError, got %v", err)
+	}
+	if cycleErr.Kind != "job" {
+		t.Errorf("Expected job cycle, got %s", cycleErr.Kind)
+	}
+	if want := []string{"m/first", "m/second", "m/first"}; !reflect.DeepEqual(cycleErr.Cycle, want) {
+		t.Errorf("Expected cycle %v, got %v", want, cycleErr.Cycle)
+	}
+}
+
+// TestBuildAllDependency tests expansion of the __ALL__ dependency marker.
+func TestBuildAllDependency(t *testing.T) {
+	plans := []state.PlanInfo{
+		{Name: "e2e", Dependencies: []string{"__ALL__"}},
+		{Name: "a"},
+		{Name: "b"},
+	}
+
+	g, err := Build(plans, nil, LevelModule)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if deps := g.Dependencies("e2e"); len(deps) != 2 {
+		t.Errorf("Expected e2e to depend on 2 modules, got %v", deps)
+	}
+	if ids := nodeIDs(g); ids[len(ids)-1] != "e2e" {
+		t.Errorf("Expected e2e last, got %v", ids)
+	}
+}
+
+// TestBuildInvalidLevel tests level validation.
+func TestBuildInvalidLevel(t *testing.T) {
+	if _, err := Build(testPlans(), nil, Level("task")); err == nil {
+		t.Error("Expected error for unknown level")
+	}
+}
+
+// TestMarkCriticalPath tests the longest chain of remaining work.
+func TestMarkCriticalPath(t *testing.T) {
+	t.Run("without status", func(t *testing.T) {
+		g, _ := Build(testPlans(), nil, LevelJob)
+		path := g.MarkCriticalPath()
+
+		want := []string{"core/core_types", "core/core_store", "api/api_routes", "cli/cli_main"}
+		if !reflect.DeepEqual(path, want) {
+			t.Errorf("Expected critical path %v, got %v", want, path)
+		}
+
+		critical := 0
+		for _, e := range g.Edges {
+			if e.Critical {
+				critical++
+			}
+		}
+		if critical != 3 {
+			t.Errorf("Expected 3 critical edges, got %d", critical)
+		}
+	})
+
+	t.Run("completed prefix excluded", func(t *testing.T) {
+		g, _ := Build(testPlans(), testStatus(), LevelJob)
+		path := g.MarkCriticalPath()
+
+		want := []string{"api/api_routes", "cli/cli_main"}
+		if !reflect.DeepEqual(path, want) {
+			t.Errorf("Expected critical path %v, got %v", want, path)
+		}
+		if g.Node("core/core_store").Critical {
+			t.Error("Completed node should not be on the critical path")
+		}
+	})
+
+	t.Run("all completed", func(t *testing.T) {
+		plans := []state.PlanInfo{{Name: "core", Jobs: []state.JobInfo{{Index: 1, Name: "core_types"}}}}
+		status := &state.ExecutionStatus{Modules: []state.ModuleState{
+			{Name: "core", Jobs: []state.JobState{{Name: "core_types", Status: state.StatusCompleted}}},
+		}}
+		g, _ := Build(plans, status, LevelJob)
+		if path := g.MarkCriticalPath(); len(path) != 0 {
+			t.Errorf("Expected empty critical path, got %v", path)
+		}
+	})
+}
+
+// TestMarkReady tests detection of nodes that can run now.
+func TestMarkReady(t *testing.T) {
+	g, _ := Build(testPlans(), nil, LevelJob)
+	if ready := g.MarkReady(); !reflect.DeepEqual(ready, []string{"core/core_types"}) {
+		t.Errorf("Expected only core_types ready, got %v", ready)
+	}
+
+	status := testStatus()
+	status.Modules[1].Jobs[0].Status = state.StatusCompleted
+	g, _ = Build(testPlans(), status, LevelJob)
+	if ready := g.MarkReady(); !reflect.DeepEqual(ready, []string{"cli/cli_main"}) {
+		t.Errorf("Expected cli_main ready, got %v", ready)
+	}
+	if !g.Node("cli/cli_main").Ready {
+		t.Error("Expected cli_main node to be marked ready")
+	}
+}
+
+// TestDownstream tests transitive dependents through explicit and implied edges.
+func TestDownstream(t *testing.T) {
+	g, _ := Build(testPlans(), nil, LevelJob)
+
+	want := []string{"core/core_store", "api/api_routes", "cli/cli_main"}
+	if got := g.Downstream("core/core_types"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Downstream(core_types) = %v, want %v", got, want)
+	}
+	if got := g.Downstream("cli/cli_main"); len(got) != 0 {
+		t.Errorf("Expected no dependents of cli_main, got %v", got)
+	}
+}
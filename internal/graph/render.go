@@ -0,0 +1,248 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/morty/morty/internal/state"
+)
+
+// Format is an output format for the graph.
+type Format string
+
+const (
+	// FormatDOT renders Graphviz DOT.
+	FormatDOT Format = "dot"
+	// FormatMermaid renders a Mermaid flowchart.
+	FormatMermaid Format = "mermaid"
+	// FormatJSON renders the graph as JSON.
+	FormatJSON Format = "json"
+)
+
+// statusColors maps status to node fill color.
+var statusColors = map[state.Status]string{
+	state.StatusPending:     "#e0e0e0",
+	state.StatusRunning:     "#90caf9",
+	state.StatusCompleted:   "#a5d6a7",
+	state.StatusFailed:      "#ef9a9a",
+	state.StatusBlocked:     "#ffcc80",
+	state.StatusInterrupted: "#fff59d",
+}
+
+// criticalColor is used for nodes and edges on the critical path.
+const criticalColor = "#d32f2f"
+
+// statusColor returns the fill color for a status.
+func statusColor(s state.Status) string {
+	if c, ok := statusColors[s]; ok {
+		return c
+	}
+	return statusColors[state.StatusPending]
+}
+
+// Render writes the graph in the given format.
+func Render(w io.Writer, g *Graph, format Format) error {
+	switch format {
+	case FormatDOT, "":
+		return RenderDOT(w, g)
+	case FormatMermaid:
+		return RenderMermaid(w, g)
+	case FormatJSON:
+		return RenderJSON(w, g)
+	default:
+		return fmt.Errorf("unknown graph format: %s (expected dot, mermaid or json)", format)
+	}
+}
+
+// RenderJSON writes the graph as indented JSON.
+func RenderJSON(w io.Writer, g *Graph) error {
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal graph: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// RenderDOT writes the graph in Graphviz DOT format.
+// Job graphs are grouped into one cluster per module.
+func RenderDOT(w io.Writer, g *Graph) error {
+	var sb strings.Builder
+
+	sb.WriteString("digraph morty {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\"];\n")
+	sb.WriteString("  edge [fontname=\"Helvetica\"];\n")
+
+	if g.Level == LevelJob {
+		for i, module := range moduleOrder(g) {
+			fmt.Fprintf(&sb, "  subgraph cluster_%d {\n", i)
+			fmt.Fprintf(&sb, "    label=%s;\n", dotQuote(module))
+			for _, n := range g.Nodes {
+				if n.Module == module {
+					sb.WriteString("    " + dotNode(n) + "\n")
+				}
+			}
+			sb.WriteString("  }\n")
+		}
+	} else {
+		for _, n := range g.Nodes {
+			sb.WriteString("  " + dotNode(n) + "\n")
+		}
+	}
+
+	for _, e := range g.Edges {
+		var attrs []string
+		if e.Implied {
+			attrs = append(attrs, "style=dashed")
+		}
+		if e.Critical {
+			attrs = append(attrs, fmt.Sprintf("color=%q", criticalColor), "penwidth=2")
+		}
+		line := fmt.Sprintf("  %s -> %s", dotQuote(e.From), dotQuote(e.To))
+		if len(attrs) > 0 {
+			line += " [" + strings.Join(attrs, ", ") + "]"
+		}
+		sb.WriteString(line + ";\n")
+	}
+
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// dotNode formats a node statement.
+func dotNode(n *Node) string {
+	attrs := []string{
+		"label=" + dotQuote(nodeLabel(n)),
+		fmt.Sprintf("fillcolor=%q", statusColor(n.Status)),
+	}
+	if n.Critical {
+		attrs = append(attrs, fmt.Sprintf("color=%q", criticalColor), "penwidth=3")
+	}
+	if n.Ready {
+		attrs = append(attrs, "peripheries=2")
+	}
+	return fmt.Sprintf("%s [%s];", dotQuote(n.ID), strings.Join(attrs, ", "))
+}
+
+// dotQuote quotes a DOT identifier.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+// RenderMermaid writes the graph as a Mermaid flowchart.
+// Node IDs are replaced by n0, n1, ... because Mermaid IDs cannot contain
+// arbitrary characters.
+func RenderMermaid(w io.Writer, g *Graph) error {
+	var sb strings.Builder
+
+	ids := make(map[string]string)
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+	}
+
+	sb.WriteString("flowchart LR\n")
+
+	if g.Level == LevelJob {
+		for i, module := range moduleOrder(g) {
+			fmt.Fprintf(&sb, "  subgraph m%d[%s]\n", i, mermaidQuote(module))
+			for _, n := range g.Nodes {
+				if n.Module == module {
+					fmt.Fprintf(&sb, "    %s[%s]\n", ids[n.ID], mermaidQuote(nodeLabel(n)))
+				}
+			}
+			sb.WriteString("  end\n")
+		}
+	} else {
+		for _, n := range g.Nodes {
+			fmt.Fprintf(&sb, "  %s[%s]\n", ids[n.ID], mermaidQuote(nodeLabel(n)))
+		}
+	}
+
+	var criticalLinks []string
+	for i, e := range g.Edges {
+		arrow := "-->"
+		if e.Implied {
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&sb, "  %s %s %s\n", ids[e.From], arrow, ids[e.To])
+		if e.Critical {
+			criticalLinks = append(criticalLinks, fmt.Sprintf("%d", i))
+		}
+	}
+
+	for _, s := range []state.Status{state.StatusPending, state.StatusRunning, state.StatusCompleted, state.StatusFailed, state.StatusBlocked, state.StatusInterrupted} {
+		var members []string
+		for _, n := range g.Nodes {
+			if n.Status == s || (s == state.StatusPending && !n.Status.IsValid()) {
+				members = append(members, ids[n.ID])
+			}
+		}
+		if len(members) == 0 {
+			continue
+		}
+		class := strings.ToLower(string(s))
+		fmt.Fprintf(&sb, "  classDef %s fill:%s\n", class, statusColor(s))
+		fmt.Fprintf(&sb, "  class %s %s\n", strings.Join(members, ","), class)
+	}
+
+	var critical, ready []string
+	for _, n := range g.Nodes {
+		if n.Critical {
+			critical = append(critical, ids[n.ID])
+		}
+		if n.Ready {
+			ready = append(ready, ids[n.ID])
+		}
+	}
+	if len(ready) > 0 {
+		sb.WriteString("  classDef ready stroke-width:4px,stroke-dasharray:4 2\n")
+		fmt.Fprintf(&sb, "  class %s ready\n", strings.Join(ready, ","))
+	}
+	if len(critical) > 0 {
+		fmt.Fprintf(&sb, "  classDef critical stroke:%s,stroke-width:3px\n", criticalColor)
+		fmt.Fprintf(&sb, "  class %s critical\n", strings.Join(critical, ","))
+	}
+	if len(criticalLinks) > 0 {
+		fmt.Fprintf(&sb, "  linkStyle %s stroke:%s,stroke-width:2px\n", strings.Join(criticalLinks, ","), criticalColor)
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// mermaidQuote quotes a Mermaid label.
+func mermaidQuote(s string) string {
+	s = strings.ReplaceAll(s, `"`, "#quot;")
+	s = strings.ReplaceAll(s, "\n", "<br/>")
+	return `"` + s + `"`
+}
+
+// nodeLabel builds the display label with status and progress.
+func nodeLabel(n *Node) string {
+	label := n.Label
+	if n.Kind == NodeModule && n.Label != n.Module {
+		label = fmt.Sprintf("%s (%s)", n.Label, n.Module)
+	}
+	return fmt.Sprintf("%s\n%s %d/%d", label, n.Status, n.TasksCompleted, n.TasksTotal)
+}
+
+// moduleOrder returns module names in the order their first node appears.
+func moduleOrder(g *Graph) []string {
+	seen := make(map[string]bool)
+	var modules []string
+	for _, n := range g.Nodes {
+		if !seen[n.Module] {
+			seen[n.Module] = true
+			modules = append(modules, n.Module)
+		}
+	}
+	return modules
+}
@@ -0,0 +1,113 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestRenderDOT tests DOT output with clusters, colors and highlights.
+func TestRenderDOT(t *testing.T) {
+	g, err := Build(testPlans(), testStatus(), LevelJob)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	g.MarkCriticalPath()
+	g.MarkReady()
+
+	var buf bytes.Buffer
+	if err := Render(&buf, g, FormatDOT); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	out := buf.String()
+
+	checks := []string{
+		"digraph morty {",
+		"subgraph cluster_0 {",
+		`label="core";`,
+		`"core/core_types" [label="core_types\nCOMPLETED 0/0", fillcolor="#a5d6a7"];`,
+		`"api/api_routes" [label="api_routes\nRUNNING 1/2", fillcolor="#90caf9", color="#d32f2f", penwidth=3];`,
+		`"core/core_types" -> "core/core_store";`,
+		`"core/core_store" -> "api/api_routes" [style=dashed];`,
+		`"api/api_routes" -> "cli/cli_main" [style=dashed, color="#d32f2f", penwidth=2];`,
+	}
+	for _, check := range checks {
+		if !strings.Contains(out, check) {
+			t.Errorf("Expected DOT output to contain %q, got:\n%s", check, out)
+		}
+	}
+}
+
+// TestRenderDOTQuoting tests that labels with quotes stay valid DOT.
+func TestRenderDOTQuoting(t *testing.T) {
+	if got := dotQuote(`say "hi"`); got != `"say \"hi\""` {
+		t.Errorf("dotQuote() = %s", got)
+	}
+}
+
+// TestRenderMermaid tests Mermaid output.
+func TestRenderMermaid(t *testing.T) {
+	g, err := Build(testPlans(), testStatus(), LevelModule)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	g.MarkCriticalPath()
+	g.MarkReady()
+
+	var buf bytes.Buffer
+	if err := Render(&buf, g, FormatMermaid); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	out := buf.String()
+
+	checks := []string{
+		"flowchart LR",
+		`n0["core<br/>COMPLETED 2/2"]`,
+		`n2["命令行 (cli)<br/>PENDING 0/1"]`,
+		"n0 --> n1",
+		"n1 --> n2",
+		"classDef completed fill:#a5d6a7",
+		"class n0 completed",
+		"class n1,n2 critical",
+		"linkStyle 1 stroke:#d32f2f",
+	}
+	for _, check := range checks {
+		if !strings.Contains(out, check) {
+			t.Errorf("Expected Mermaid output to contain %q, got:\n%s", check, out)
+		}
+	}
+}
+
+// TestRenderJSON tests that JSON output round-trips.
+func TestRenderJSON(t *testing.T) {
+	g, err := Build(testPlans(), testStatus(), LevelModule)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	g.MarkReady()
+
+	var buf bytes.Buffer
+	if err := Render(&buf, g, FormatJSON); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	var decoded Graph
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, buf.String())
+	}
+	if decoded.Level != LevelModule || len(decoded.Nodes) != 3 || len(decoded.Edges) != 2 {
+		t.Errorf("Unexpected decoded graph: %+v", decoded)
+	}
+	if len(decoded.Ready) != 0 {
+		t.Errorf("Expected no ready modules while api is running, got %v", decoded.Ready)
+	}
+}
+
+// TestRenderUnknownFormat tests format validation.
+func TestRenderUnknownFormat(t *testing.T) {
+	g, _ := Build(testPlans(), nil, LevelModule)
+	if err := Render(&bytes.Buffer{}, g, Format("svg")); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
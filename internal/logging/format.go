@@ -11,6 +11,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/morty/morty/internal/redact"
 )
 
 // Format represents the log output format type.
@@ -482,10 +484,10 @@ func (l *FormatterLogger) log(level Level, msg string, attrs ...Attr) {
 	entry := &LogEntry{
 		Time:       time.Now(),
 		Level:      level,
-		Message:    msg,
+		Message:    redact.String(msg),
 		Module:     module,
 		Job:        job,
-		Attributes: allAttrs,
+		Attributes: redactAttrs(allAttrs),
 	}
 
 	_ = l.formatter.Format(l.writer, entry)
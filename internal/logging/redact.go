@@ -0,0 +1,27 @@
+package logging
+
+import (
+	"fmt"
+
+	"github.com/morty/morty/internal/redact"
+)
+
+// redactAttrs masks secrets in attribute values before they are formatted.
+// Strings, string slices and errors are masked; other values are kept.
+func redactAttrs(attrs []Attr) []Attr {
+	result := make([]Attr, len(attrs))
+	for i, attr := range attrs {
+		switch v := attr.Value.(type) {
+		case string:
+			attr.Value = redact.String(v)
+		case []string:
+			attr.Value = redact.Strings(v)
+		case error:
+			attr.Value = redact.String(v.Error())
+		case fmt.Stringer:
+			attr.Value = redact.String(v.String())
+		}
+		result[i] = attr
+	}
+	return result
+}
@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSecret = "sk-ant-REDACTED"
+
+// TestFormatterLogger_Redaction tests that secrets in messages and
+// attributes are masked.
+func TestFormatterLogger_Redaction(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewFormatterLogger(NewJSONFormatter(), &buf, DebugLevel)
+
+	logger.Info("calling with "+testSecret,
+		String("key", testSecret),
+		Any("args", []string{"--token", testSecret}),
+		Any("error", errors.New("rejected "+testSecret)),
+		Int("count", 3),
+	)
+
+	out := buf.String()
+	if strings.Contains(out, testSecret) {
+		t.Fatalf("Secret leaked into log: %s", out)
+	}
+	if strings.Count(out, "[REDACTED:anthropic-api-key]") != 4 || !strings.Contains(out, `"count":3`) {
+		t.Errorf("Unexpected log: %s", out)
+	}
+}
+
+func TestSlogAdapter_Redaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "morty.log")
+	adapter, err := NewSlogAdapterWithConfig("json", "file", path, InfoLevel, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	adapter.WithAttrs(String("session", testSecret)).Warn("token "+testSecret, String("key", testSecret))
+	adapter.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) == 0 || strings.Contains(string(data), testSecret) {
+		t.Errorf("Expected masked log line, got %q", data)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"log/slog"
 	"os"
 	"sync"
+
+	"github.com/morty/morty/internal/redact"
 )
 
 // SlogAdapter is an adapter that implements the Logger interface
@@ -160,7 +162,7 @@ func (l *SlogAdapter) Debug(msg string, attrs ...Attr) {
 		return
 	}
 	allAttrs := append(l.getBaseAttrs(), attrs...)
-	l.logger.LogAttrs(context.Background(), slog.LevelDebug, msg, toSlogAttrs(allAttrs)...)
+	l.logger.LogAttrs(context.Background(), slog.LevelDebug, redact.String(msg), toSlogAttrs(redactAttrs(allAttrs))...)
 }
 
 // Info logs an info message.
@@ -169,7 +171,7 @@ func (l *SlogAdapter) Info(msg string, attrs ...Attr) {
 		return
 	}
 	allAttrs := append(l.getBaseAttrs(), attrs...)
-	l.logger.LogAttrs(context.Background(), slog.LevelInfo, msg, toSlogAttrs(allAttrs)...)
+	l.logger.LogAttrs(context.Background(), slog.LevelInfo, redact.String(msg), toSlogAttrs(redactAttrs(allAttrs))...)
 }
 
 // Warn logs a warning message.
@@ -178,7 +180,7 @@ func (l *SlogAdapter) Warn(msg string, attrs ...Attr) {
 		return
 	}
 	allAttrs := append(l.getBaseAttrs(), attrs...)
-	l.logger.LogAttrs(context.Background(), slog.LevelWarn, msg, toSlogAttrs(allAttrs)...)
+	l.logger.LogAttrs(context.Background(), slog.LevelWarn, redact.String(msg), toSlogAttrs(redactAttrs(allAttrs))...)
 }
 
 // Error logs an error message.
@@ -187,7 +189,7 @@ func (l *SlogAdapter) Error(msg string, attrs ...Attr) {
 		return
 	}
 	allAttrs := append(l.getBaseAttrs(), attrs...)
-	l.logger.LogAttrs(context.Background(), slog.LevelError, msg, toSlogAttrs(allAttrs)...)
+	l.logger.LogAttrs(context.Background(), slog.LevelError, redact.String(msg), toSlogAttrs(redactAttrs(allAttrs))...)
 }
 
 // Success logs a success message (mapped to Info with success marker).
@@ -197,7 +199,7 @@ func (l *SlogAdapter) Success(msg string, attrs ...Attr) {
 	}
 	allAttrs := append(l.getBaseAttrs(), Attr{Key: "level", Value: "SUCCESS"})
 	allAttrs = append(allAttrs, attrs...)
-	l.logger.LogAttrs(context.Background(), slog.LevelInfo, msg, toSlogAttrs(allAttrs)...)
+	l.logger.LogAttrs(context.Background(), slog.LevelInfo, redact.String(msg), toSlogAttrs(redactAttrs(allAttrs))...)
 }
 
 // Loop logs a loop iteration message (mapped to Debug with loop context).
@@ -207,7 +209,7 @@ func (l *SlogAdapter) Loop(msg string, attrs ...Attr) {
 	}
 	allAttrs := append(l.getBaseAttrs(), Attr{Key: "level", Value: "LOOP"})
 	allAttrs = append(allAttrs, attrs...)
-	l.logger.LogAttrs(context.Background(), slog.LevelDebug, msg, toSlogAttrs(allAttrs)...)
+	l.logger.LogAttrs(context.Background(), slog.LevelDebug, redact.String(msg), toSlogAttrs(redactAttrs(allAttrs))...)
 }
 
 // getBaseAttrs returns the base attributes including module and job.
@@ -0,0 +1,686 @@
+// Package logview reads Morty's log files, the global morty.log, per-job
+// execution logs and conversation logs, merges them on a common timeline
+// and filters them for the logs command.
+package logview
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/morty/morty/internal/executor"
+	"github.com/morty/morty/internal/logging"
+)
+
+// Kind identifies the kind of log file.
+type Kind string
+
+const (
+	// KindGlobal is the global morty.log (JSON lines or text) and its backups.
+	KindGlobal Kind = "global"
+	// KindJob is an execution log written by the executor for one job run.
+	KindJob Kind = "job"
+	// KindStructured is a JSON-lines job log written by logging.JobLogger.
+	KindStructured Kind = "structured"
+	// KindConversation is a conversation log written by callcli.ConversationParser.
+	KindConversation Kind = "conversation"
+)
+
+// Source is a log file.
+type Source struct {
+	// Path is the file path.
+	Path string
+	// Kind is the kind of log file.
+	Kind Kind
+	// Stem is the file name without the timestamp suffix ("module_job").
+	Stem string
+	// Module and Job identify the job of a per-job log, if known.
+	Module string
+	Job    string
+	// Start is the run start time encoded in the file name (zero for global logs).
+	Start time.Time
+}
+
+// Record is a single entry on the merged timeline. Multi-line entries, such
+// as conversation messages, are kept together in one record.
+type Record struct {
+	// Time is when the entry was written. Entries without a timestamp of
+	// their own inherit the previous one.
+	Time time.Time
+	// Level is the log level; transcript lines are INFO, stderr output WARN.
+	Level logging.Level
+	// Module and Job identify the job the entry belongs to, if any.
+	Module string
+	Job    string
+	// Attempt is the 1-based run of the job the entry belongs to
+	// (0 if it belongs to no job or precedes the first run).
+	Attempt int
+	// Text is the original text of the entry.
+	Text string
+	// Event marks a raw stream-json event line.
+	Event bool
+	// Tool marks tool calls and tool results.
+	Tool bool
+
+	source *Source
+	fields map[string]interface{}
+}
+
+// Options configures where logs are read from.
+type Options struct {
+	// LogDirs are the directories holding per-job and conversation logs.
+	LogDirs []string
+	// GlobalLog is the path of morty.log; rotated backups (morty.log.N) are read too.
+	GlobalLog string
+	// Jobs maps known module names to their job names. It is used to split
+	// "module_job" file names, which are ambiguous when names contain "_".
+	Jobs map[string][]string
+}
+
+var (
+	// jobLogName matches "<module>_<job>_<YYYYMMDD_HHMMSS>.log".
+	jobLogName = regexp.MustCompile(`^(.+)_(\d{8}_\d{6})\.log$`)
+	// jobLogHeader matches the executor's job log header.
+	jobLogHeader = regexp.MustCompile(`^=== Job Log: ([^/]+)/(.+) ===$`)
+	// startedLine matches the executor's "Started:" header line.
+	startedLine = regexp.MustCompile(`^Started: (\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2})$`)
+	// conversationLine matches a conversation log entry ("[15:04:05] TOOL CALL: Bash").
+	conversationLine = regexp.MustCompile(`^\[(\d{2}:\d{2}:\d{2})\] (.*)$`)
+	// formattedEventLine matches an EventFormatter line ("[0001] 2006-01-02 15:04:05 | type | summary").
+	formattedEventLine = regexp.MustCompile(`^\[\d{4,}\] (\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}) \| (.*)$`)
+	// textLogLine matches a logging.TextFormatter line.
+	textLogLine = regexp.MustCompile(`^\[([^\]]+)\] (DEBUG|INFO|WARN|ERROR|SUCCESS)\s+(?:\(([^)]+)\) )?(.*)$`)
+	// textLogAttr matches module=/job= attributes in a text log line.
+	textLogAttr = regexp.MustCompile(`\b(module|job)=(\S+)`)
+	// exitCodeLine matches the executor's job log footer.
+	exitCodeLine = regexp.MustCompile(`^Exit Code: (-?\d+)$`)
+)
+
+const localTimeLayout = "2006-01-02 15:04:05"
+
+// Discover finds the log files described by opts. Per-job logs are
+// returned in start order, followed by the global logs.
+func Discover(opts Options) ([]Source, error) {
+	var sources []Source
+	seen := make(map[string]bool)
+
+	for _, dir := range opts.LogDirs {
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read log directory %s: %w", dir, err)
+		}
+		for _, entry := range entries {
+			m := jobLogName.FindStringSubmatch(entry.Name())
+			if entry.IsDir() || m == nil {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			if abs, err := filepath.Abs(path); err == nil {
+				if seen[abs] {
+					continue
+				}
+				seen[abs] = true
+			}
+			start, _ := time.ParseInLocation("20060102_150405", m[2], time.Local)
+			src := Source{Path: path, Stem: m[1], Start: start}
+			src.Module, src.Job = splitStem(m[1], opts.Jobs)
+			src.Kind, src.Module, src.Job = detectKind(path, src.Module, src.Job)
+			sources = append(sources, src)
+		}
+	}
+
+	sort.SliceStable(sources, func(i, j int) bool {
+		return sources[i].Start.Before(sources[j].Start)
+	})
+
+	if opts.GlobalLog != "" {
+		backups, _ := filepath.Glob(opts.GlobalLog + ".*")
+		// Oldest backup has the highest number
+		sort.Slice(backups, func(i, j int) bool {
+			return backupNumber(backups[i]) > backupNumber(backups[j])
+		})
+		for _, path := range backups {
+			if backupNumber(path) > 0 {
+				sources = append(sources, Source{Path: path, Kind: KindGlobal})
+			}
+		}
+		if _, err := os.Stat(opts.GlobalLog); err == nil {
+			sources = append(sources, Source{Path: opts.GlobalLog, Kind: KindGlobal})
+		}
+	}
+
+	return sources, nil
+}
+
+// backupNumber returns N for a rotated backup "morty.log.N" and 0 for
+// other files matching the backup glob.
+func backupNumber(path string) int {
+	ext := filepath.Ext(path)
+	var n int
+	if _, err := fmt.Sscanf(ext, ".%d", &n); err != nil || fmt.Sprintf(".%d", n) != ext {
+		return 0
+	}
+	return n
+}
+
+// splitStem splits a "module_job" file stem using the known module and job
+// names. It returns empty names if the stem matches no known job.
+func splitStem(stem string, jobs map[string][]string) (string, string) {
+	module, job := "", ""
+	for m, names := range jobs {
+		prefix := sanitize(m) + "_"
+		if !strings.HasPrefix(stem, prefix) || len(m) <= len(module) {
+			continue
+		}
+		for _, j := range names {
+			if stem[len(prefix):] == sanitize(j) {
+				module, job = m, j
+				break
+			}
+		}
+	}
+	return module, job
+}
+
+// sanitize mirrors the file name sanitizing used when logs are written.
+func sanitize(name string) string {
+	for _, char := range []string{"/", "\\", ":", "*", "?", "\"", "<", ">", "|", " "} {
+		name = strings.ReplaceAll(name, char, "_")
+	}
+	return name
+}
+
+// detectKind determines the kind of a per-job log from its first line. The
+// executor's job log header also names the module and job.
+func detectKind(path, module, job string) (Kind, string, string) {
+	f, err := os.Open(path)
+	if err != nil {
+		return KindJob, module, job
+	}
+	defer f.Close()
+
+	line, _ := bufio.NewReader(f).ReadString('\n')
+	line = strings.TrimSpace(line)
+	switch {
+	case strings.HasPrefix(line, "=== Claude Code Conversation Log"):
+		return KindConversation, module, job
+	case strings.HasPrefix(line, "{"):
+		return KindStructured, module, job
+	}
+	if m := jobLogHeader.FindStringSubmatch(line); m != nil {
+		return KindJob, m[1], m[2]
+	}
+	return KindJob, module, job
+}
+
+// MatchesTarget reports whether a per-job log belongs to module (and job,
+// if given). Global logs always match; their entries are filtered instead.
+func (s Source) MatchesTarget(module, job string) bool {
+	if s.Kind == KindGlobal || module == "" {
+		return true
+	}
+	if s.Module != "" {
+		return s.Module == module && (job == "" || s.Job == job)
+	}
+	if job != "" {
+		return s.Stem == sanitize(module)+"_"+sanitize(job)
+	}
+	return strings.HasPrefix(s.Stem, sanitize(module)+"_")
+}
+
+// parser turns the lines of one log file into records. It keeps state
+// between calls so that a followed file can be parsed incrementally.
+type parser struct {
+	src     *Source
+	last    time.Time
+	section string
+	current *Record
+}
+
+func newParser(src *Source) *parser {
+	return &parser{src: src, last: src.Start}
+}
+
+// parse consumes complete lines and returns the records they finish.
+// Call flush to obtain a record still open at the end of the input.
+func (p *parser) parse(data []byte) []Record {
+	var records []Record
+	for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if p.src.Kind == KindGlobal || p.src.Kind == KindStructured {
+			if rec, ok := p.parseEntry(line); ok {
+				records = append(records, rec)
+			}
+			continue
+		}
+		records = append(records, p.parseText(line)...)
+	}
+	return records
+}
+
+// flush returns the record still being assembled, if any.
+func (p *parser) flush() []Record {
+	if p.current == nil {
+		return nil
+	}
+	rec := *p.current
+	p.current = nil
+	return []Record{rec}
+}
+
+// parseEntry parses a line of a JSON-lines or text-format logger file.
+func (p *parser) parseEntry(line string) (Record, bool) {
+	if strings.TrimSpace(line) == "" {
+		return Record{}, false
+	}
+	rec := p.newRecord(line)
+
+	var fields map[string]interface{}
+	if strings.HasPrefix(line, "{") && json.Unmarshal([]byte(line), &fields) == nil {
+		rec.fields = fields
+		if t, err := time.Parse(time.RFC3339Nano, stringField(fields, "time")); err == nil {
+			rec.Time = t
+		}
+		if level := stringField(fields, "level"); level != "" {
+			rec.Level = logging.ParseLevel(level)
+		}
+		if module := stringField(fields, "module"); module != "" {
+			rec.Module = module
+		}
+		if job := stringField(fields, "job"); job != "" {
+			rec.Job = job
+		}
+		rec.Tool = stringField(fields, "tool") != ""
+	} else if m := textLogLine.FindStringSubmatch(line); m != nil {
+		if t, err := time.Parse(time.RFC3339Nano, m[1]); err == nil {
+			rec.Time = t
+		}
+		if m[2] != "SUCCESS" {
+			rec.Level = logging.ParseLevel(m[2])
+		}
+		if m[3] != "" {
+			rec.Module, rec.Job, _ = strings.Cut(m[3], "/")
+		}
+		for _, attr := range textLogAttr.FindAllStringSubmatch(m[4], -1) {
+			if attr[1] == "module" {
+				rec.Module = attr[2]
+			} else {
+				rec.Job = attr[2]
+			}
+		}
+	}
+
+	p.last = rec.Time
+	return rec, true
+}
+
+// parseText parses a line of an executor job log or a conversation log.
+func (p *parser) parseText(line string) []Record {
+	trimmed := strings.TrimSpace(line)
+
+	switch {
+	case trimmed == "":
+		// Blank lines separate conversation entries
+		return p.flush()
+	case strings.HasPrefix(trimmed, "=== STDERR"):
+		p.section = "stderr"
+		return append(p.flush(), p.textRecord(line, p.last))
+	case strings.HasPrefix(trimmed, "=== STDOUT"):
+		p.section = "stdout"
+		return append(p.flush(), p.textRecord(line, p.last))
+	}
+
+	if m := startedLine.FindStringSubmatch(trimmed); m != nil {
+		if t, err := time.ParseInLocation(localTimeLayout, m[1], time.Local); err == nil {
+			p.last = t
+		}
+		return append(p.flush(), p.textRecord(line, p.last))
+	}
+
+	if m := conversationLine.FindStringSubmatch(trimmed); m != nil {
+		records := p.flush()
+		rec := p.textRecord(line, p.clockTime(m[1]))
+		rec.Tool = strings.HasPrefix(m[2], "TOOL CALL") || strings.HasPrefix(m[2], "TOOL RESULT")
+		if strings.HasPrefix(m[2], "ERROR") {
+			rec.Level = logging.ErrorLevel
+		}
+		p.current = &rec
+		return records
+	}
+
+	if m := formattedEventLine.FindStringSubmatch(trimmed); m != nil {
+		records := p.flush()
+		t, err := time.ParseInLocation(localTimeLayout, m[1], time.Local)
+		if err != nil {
+			t = p.last
+		}
+		rec := p.textRecord(line, t)
+		rec.Tool = strings.Contains(m[2], "Tools: [") || strings.Contains(m[2], "Tool results: [")
+		return append(records, rec)
+	}
+
+	if strings.HasPrefix(trimmed, "{") {
+		if rec, ok := p.eventRecord(trimmed); ok {
+			return append(p.flush(), rec)
+		}
+	}
+
+	if m := exitCodeLine.FindStringSubmatch(trimmed); m != nil {
+		rec := p.textRecord(line, p.last)
+		if m[1] != "0" {
+			rec.Level = logging.ErrorLevel
+		}
+		return append(p.flush(), rec)
+	}
+
+	// Continuation of a multi-line entry
+	if p.current != nil {
+		p.current.Text += "\n" + line
+		return nil
+	}
+	return []Record{p.textRecord(line, p.last)}
+}
+
+// eventRecord parses a raw stream-json event line.
+func (p *parser) eventRecord(line string) (Record, bool) {
+	var event executor.Event
+	if err := json.Unmarshal([]byte(line), &event); err != nil || event.Type == "" {
+		return Record{}, false
+	}
+
+	t := p.last
+	if event.Timestamp != "" {
+		if parsed, err := time.Parse(time.RFC3339Nano, event.Timestamp); err == nil {
+			t = parsed
+		}
+	}
+	rec := p.textRecord(line, t)
+	rec.Event = true
+	if event.Message != nil {
+		for _, block := range event.Message.Content {
+			if block.Type == "tool_use" || block.Type == "tool_result" {
+				rec.Tool = true
+			}
+		}
+	}
+	return rec, true
+}
+
+// clockTime resolves an "HH:MM:SS" time against the last known date.
+func (p *parser) clockTime(clock string) time.Time {
+	base := p.last
+	if base.IsZero() {
+		base = time.Now()
+	}
+	t, err := time.ParseInLocation(localTimeLayout, base.Format("2006-01-02")+" "+clock, time.Local)
+	if err != nil {
+		return base
+	}
+	// Conversation logs may cross midnight
+	if t.Before(base.Add(-12 * time.Hour)) {
+		t = t.Add(24 * time.Hour)
+	}
+	return t
+}
+
+// textRecord creates a record for a transcript line.
+func (p *parser) textRecord(line string, t time.Time) Record {
+	rec := p.newRecord(line)
+	rec.Time = t
+	if p.section == "stderr" {
+		rec.Level = logging.WarnLevel
+	}
+	p.last = t
+	return rec
+}
+
+// newRecord creates a record attributed to the parser's source.
+func (p *parser) newRecord(line string) Record {
+	return Record{
+		Time:   p.last,
+		Level:  logging.InfoLevel,
+		Module: p.src.Module,
+		Job:    p.src.Job,
+		Text:   line,
+		source: p.src,
+	}
+}
+
+// stringField returns a string field of a decoded JSON entry.
+func stringField(fields map[string]interface{}, key string) string {
+	s, _ := fields[key].(string)
+	return s
+}
+
+// ReadSource reads all records of a log file.
+func ReadSource(src Source) ([]Record, error) {
+	data, err := os.ReadFile(src.Path)
+	if err != nil {
+		return nil, err
+	}
+	p := newParser(&src)
+	records := p.parse(data)
+	return append(records, p.flush()...), nil
+}
+
+// Filter selects records.
+type Filter struct {
+	// Module and Job restrict records to one module or job.
+	Module string
+	Job    string
+	// MinLevel drops records below this level.
+	MinLevel logging.Level
+	// Since drops records written before this time.
+	Since time.Time
+	// Attempt keeps only the given run of the job (0 keeps all runs).
+	Attempt int
+	// ToolsOnly keeps only tool calls and tool results.
+	ToolsOnly bool
+}
+
+// Match reports whether a record passes the filter.
+func (f Filter) Match(r Record) bool {
+	if !f.matchesTarget(r) {
+		return false
+	}
+	if r.Level < f.MinLevel {
+		return false
+	}
+	if !f.Since.IsZero() && r.Time.Before(f.Since) {
+		return false
+	}
+	if f.Attempt > 0 && r.Attempt != f.Attempt {
+		return false
+	}
+	if f.ToolsOnly && !r.Tool {
+		return false
+	}
+	return true
+}
+
+// matchesTarget reports whether a record belongs to the filtered module or
+// job. Records from per-job logs are attributed by their file, so logs
+// whose job could not be resolved from status.json still match by name.
+func (f Filter) matchesTarget(r Record) bool {
+	if f.Module == "" {
+		return true
+	}
+	if r.source != nil && r.source.Kind != KindGlobal {
+		return r.source.MatchesTarget(f.Module, f.Job)
+	}
+	return r.Module == f.Module && (f.Job == "" || r.Job == f.Job)
+}
+
+// jobKey identifies a job across sources.
+func jobKey(module, job string) string {
+	return module + "/" + job
+}
+
+// attempts collects the run start times of each job. Execution and
+// structured logs are created when a run starts; conversation logs are only
+// used for jobs without them.
+func attempts(sources []Source) map[string][]time.Time {
+	starts := make(map[string][]time.Time)
+	conversations := make(map[string][]time.Time)
+	for _, src := range sources {
+		if src.Module == "" || src.Start.IsZero() {
+			continue
+		}
+		key := jobKey(src.Module, src.Job)
+		if src.Kind == KindConversation {
+			conversations[key] = append(conversations[key], src.Start)
+		} else {
+			starts[key] = append(starts[key], src.Start)
+		}
+	}
+	for key, times := range conversations {
+		if _, ok := starts[key]; !ok {
+			starts[key] = times
+		}
+	}
+	for _, times := range starts {
+		sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	}
+	return starts
+}
+
+// assignAttempts numbers each record with the run of its job it belongs to.
+// Records from a per-job file belong to the run the file was written in;
+// other records are placed by their time.
+func assignAttempts(records []Record, starts map[string][]time.Time) {
+	for i := range records {
+		r := &records[i]
+		if r.Module == "" {
+			continue
+		}
+		t := r.Time
+		if r.source != nil && !r.source.Start.IsZero() {
+			t = r.source.Start
+		}
+		r.Attempt = 0
+		for _, start := range starts[jobKey(r.Module, r.Job)] {
+			if start.After(t) {
+				break
+			}
+			r.Attempt++
+		}
+	}
+}
+
+// Merge sorts records from several sources into one timeline. Records with
+// equal times keep their source order.
+func Merge(records []Record) {
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Time.Before(records[j].Time)
+	})
+}
+
+// Reader reads log records and, on later calls, only what was appended
+// since, so that logs can be followed.
+type Reader struct {
+	opts    Options
+	sources []Source
+	offsets map[string]int64
+	parsers map[string]*parser
+}
+
+// NewReader creates a reader for the logs described by opts.
+func NewReader(opts Options) *Reader {
+	return &Reader{
+		opts:    opts,
+		offsets: make(map[string]int64),
+		parsers: make(map[string]*parser),
+	}
+}
+
+// Read returns the records written since the previous call (all records on
+// the first call), merged and numbered by attempt. Partial trailing lines are
+// left for the next call.
+func (r *Reader) Read() ([]Record, error) {
+	sources, err := Discover(r.opts)
+	if err != nil {
+		return nil, err
+	}
+	r.sources = sources
+
+	var records []Record
+	for i := range sources {
+		src := sources[i]
+		data, err := r.readNew(src.Path)
+		if err != nil {
+			return nil, err
+		}
+		p, ok := r.parsers[src.Path]
+		if !ok {
+			p = newParser(&src)
+			r.parsers[src.Path] = p
+		}
+		if len(data) > 0 {
+			records = append(records, p.parse(data)...)
+		}
+		records = append(records, p.flush()...)
+	}
+
+	assignAttempts(records, attempts(sources))
+	Merge(records)
+	return records, nil
+}
+
+// Sources returns the log files found by the last Read.
+func (r *Reader) Sources() []Source {
+	return r.sources
+}
+
+// readNew reads the complete lines appended to a file since the last call.
+// A file that shrank (rotated or truncated) is read again from the start.
+func (r *Reader) readNew(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	offset := r.offsets[path]
+	if info.Size() < offset {
+		offset = 0
+		delete(r.parsers, path)
+	}
+	if info.Size() == offset {
+		r.offsets[path] = offset
+		return nil, nil
+	}
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	end := bytes.LastIndexByte(data, '\n')
+	if end < 0 {
+		r.offsets[path] = offset
+		return nil, nil
+	}
+	r.offsets[path] = offset + int64(end) + 1
+	return data[:end+1], nil
+}
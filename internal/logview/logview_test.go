@@ -0,0 +1,312 @@
+package logview
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/morty/morty/internal/logging"
+)
+
+// base is the start of the first run in the fixtures.
+var base = time.Date(2026, 10, 18, 10, 0, 0, 0, time.Local)
+
+func at(offset time.Duration) time.Time {
+	return base.Add(offset)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// jobLog renders an executor job log with raw stream-json output.
+func jobLog(module, job string, start time.Time, exitCode int, events ...string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "=== Job Log: %s/%s ===\n", module, job)
+	fmt.Fprintf(&sb, "Started: %s\n\n", start.Format(localTimeLayout))
+	sb.WriteString("=== STDOUT ===\n")
+	for _, event := range events {
+		sb.WriteString(event + "\n")
+	}
+	fmt.Fprintf(&sb, "\nExit Code: %d\n", exitCode)
+	return sb.String()
+}
+
+func assistantEvent(ts time.Time, block string) string {
+	return fmt.Sprintf(`{"type":"assistant","timestamp":"%s","message":{"content":[%s]}}`, ts.Format(time.RFC3339), block)
+}
+
+func globalEntry(ts time.Time, level, msg, module, job string) string {
+	return fmt.Sprintf(`{"time":"%s","level":"%s","msg":"%s","module":"%s","job":"%s"}`+"\n",
+		ts.Format(time.RFC3339Nano), level, msg, module, job)
+}
+
+func fileName(stem string, start time.Time) string {
+	return stem + "_" + start.Format("20060102_150405") + ".log"
+}
+
+// setupLogs writes two runs of core/types, a conversation log of the second
+// run and a global log, and returns the reader options.
+func setupLogs(t *testing.T) Options {
+	t.Helper()
+	dir := t.TempDir()
+	logDir := filepath.Join(dir, "logs")
+
+	writeFile(t, filepath.Join(logDir, fileName("core_types", at(0))), jobLog("core", "types", at(0), 1,
+		assistantEvent(at(5*time.Second), `{"type":"text","text":"first try"}`),
+	))
+	writeFile(t, filepath.Join(logDir, fileName("core_types", at(time.Hour))), jobLog("core", "types", at(time.Hour), 0,
+		assistantEvent(at(time.Hour+5*time.Second), `{"type":"tool_use","name":"Bash"}`),
+	))
+	writeFile(t, filepath.Join(logDir, fileName("core_types", at(time.Hour+time.Second))),
+		"=== Claude Code Conversation Log ===\n\n"+
+			fmt.Sprintf("[%s] TOOL CALL: Read\n  file: main.go\n\n", at(time.Hour+10*time.Second).Format("15:04:05"))+
+			fmt.Sprintf("[%s] ASSISTANT:\n  done\n\n", at(time.Hour+20*time.Second).Format("15:04:05")),
+	)
+
+	global := filepath.Join(dir, "doing", "logs", "morty.log")
+	writeFile(t, global,
+		globalEntry(at(time.Second), "INFO", "Job started", "core", "types")+
+			globalEntry(at(30*time.Second), "ERROR", "Job failed", "core", "types")+
+			globalEntry(at(time.Hour+30*time.Second), "INFO", "Job completed", "core", "types"),
+	)
+	writeFile(t, global+".1", globalEntry(at(-time.Minute), "DEBUG", "Config loaded", "", ""))
+
+	return Options{
+		LogDirs:   []string{logDir},
+		GlobalLog: global,
+		Jobs:      map[string][]string{"core": {"types"}},
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	sources, err := Discover(setupLogs(t))
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+
+	var kinds []string
+	for _, src := range sources {
+		kinds = append(kinds, string(src.Kind))
+	}
+	want := "job,job,conversation,global,global"
+	if got := strings.Join(kinds, ","); got != want {
+		t.Fatalf("Expected kinds %s, got %s", want, got)
+	}
+	if sources[2].Module != "core" || sources[2].Job != "types" {
+		t.Errorf("Expected conversation log attributed to core/types, got %q/%q", sources[2].Module, sources[2].Job)
+	}
+	if !strings.HasSuffix(sources[3].Path, "morty.log.1") {
+		t.Errorf("Expected backup before the live log, got %s", sources[3].Path)
+	}
+}
+
+func TestSplitStem(t *testing.T) {
+	jobs := map[string][]string{"core": {"store_api"}, "core_store": {"api"}}
+	if m, j := splitStem("core_store_api", jobs); m != "core_store" || j != "api" {
+		t.Errorf("Expected longest module to win, got %s/%s", m, j)
+	}
+	if m, j := splitStem("other_job", jobs); m != "" || j != "" {
+		t.Errorf("Expected unknown stem to stay unresolved, got %s/%s", m, j)
+	}
+}
+
+func TestReader_MergeAndAttempts(t *testing.T) {
+	records, err := NewReader(setupLogs(t)).Read()
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+
+	for i := 1; i < len(records); i++ {
+		if records[i].Time.Before(records[i-1].Time) {
+			t.Fatalf("Records out of order at %d: %v before %v", i, records[i].Time, records[i-1].Time)
+		}
+	}
+	if records[0].Text == "" || !strings.Contains(records[0].Text, "Config loaded") {
+		t.Errorf("Expected the backup entry first, got %q", records[0].Text)
+	}
+
+	attemptOf := func(substr string) int {
+		for _, r := range records {
+			if strings.Contains(r.Text, substr) {
+				return r.Attempt
+			}
+		}
+		t.Fatalf("No record contains %q", substr)
+		return 0
+	}
+	cases := map[string]int{
+		"first try":     1,
+		"Job failed":    1,
+		"tool_use":      2,
+		"TOOL CALL":     2,
+		"Job completed": 2,
+		"Config loaded": 0,
+	}
+	for substr, want := range cases {
+		if got := attemptOf(substr); got != want {
+			t.Errorf("Expected %q in attempt %d, got %d", substr, want, got)
+		}
+	}
+}
+
+func TestParser_Levels(t *testing.T) {
+	records, err := NewReader(setupLogs(t)).Read()
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+
+	levels := make(map[string]logging.Level)
+	for _, r := range records {
+		levels[r.Text] = r.Level
+	}
+	if levels["Exit Code: 1"] != logging.ErrorLevel {
+		t.Errorf("Expected non-zero exit code at ERROR, got %v", levels["Exit Code: 1"])
+	}
+	if levels["Exit Code: 0"] != logging.InfoLevel {
+		t.Errorf("Expected zero exit code at INFO, got %v", levels["Exit Code: 0"])
+	}
+}
+
+func TestFilter_Match(t *testing.T) {
+	records, err := NewReader(setupLogs(t)).Read()
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+
+	count := func(f Filter) int {
+		n := 0
+		for _, r := range records {
+			if f.Match(r) {
+				n++
+			}
+		}
+		return n
+	}
+
+	total := count(Filter{})
+	if total != len(records) {
+		t.Errorf("Expected empty filter to match all %d records, got %d", len(records), total)
+	}
+	if n := count(Filter{Module: "core", Job: "types"}); n != total-1 {
+		t.Errorf("Expected all but the unattributed entry for core/types, got %d of %d", n, total)
+	}
+	if n := count(Filter{Module: "api"}); n != 0 {
+		t.Errorf("Expected no records for another module, got %d", n)
+	}
+	if n := count(Filter{MinLevel: logging.ErrorLevel}); n != 2 {
+		t.Errorf("Expected 2 ERROR records (failure and exit code), got %d", n)
+	}
+	if n := count(Filter{Since: at(time.Hour)}); n >= total || n == 0 {
+		t.Errorf("Expected --since to keep only the second run, got %d of %d", n, total)
+	}
+	for _, r := range records {
+		if (Filter{Module: "core", Job: "types", Attempt: 2}).Match(r) && r.Time.Before(at(time.Hour)) {
+			t.Errorf("Attempt 2 filter matched a first-run record: %q", r.Text)
+		}
+	}
+
+	var tools []string
+	for _, r := range records {
+		if (Filter{ToolsOnly: true}).Match(r) {
+			tools = append(tools, r.Text)
+		}
+	}
+	if len(tools) != 2 || !strings.Contains(tools[0], "tool_use") || !strings.HasPrefix(tools[1], "[") {
+		t.Errorf("Expected the tool_use event and the TOOL CALL entry, got %q", tools)
+	}
+}
+
+func TestReader_Follow(t *testing.T) {
+	opts := setupLogs(t)
+	reader := NewReader(opts)
+	if _, err := reader.Read(); err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+
+	records, err := reader.Read()
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(records) != 0 {
+		t.Errorf("Expected no records without new output, got %d", len(records))
+	}
+
+	f, err := os.OpenFile(opts.GlobalLog, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry := globalEntry(at(2*time.Hour), "WARN", "Slow job", "core", "types")
+	// A partial line is held back until it is complete
+	f.WriteString(entry[:10])
+	if records, _ := reader.Read(); len(records) != 0 {
+		t.Errorf("Expected partial line to be held back, got %d records", len(records))
+	}
+	f.WriteString(entry[10:])
+	f.Close()
+
+	records, err = reader.Read()
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(records) != 1 || records[0].Level != logging.WarnLevel || records[0].Attempt != 2 {
+		t.Fatalf("Expected the appended WARN record in attempt 2, got %+v", records)
+	}
+
+	// A rotated log is read again from the start
+	if err := os.WriteFile(opts.GlobalLog, []byte(globalEntry(at(3*time.Hour), "INFO", "Rotated", "", "")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	records, err = reader.Read()
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if len(records) != 1 || !strings.Contains(records[0].Text, "Rotated") {
+		t.Errorf("Expected the rotated log to be re-read, got %+v", records)
+	}
+}
+
+func TestRenderer(t *testing.T) {
+	records, err := NewReader(setupLogs(t)).Read()
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+
+	var formatted, raw bytes.Buffer
+	if err := NewRenderer(&formatted, false).Write(records); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := NewRenderer(&raw, true).Write(records); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	out := formatted.String()
+	for _, want := range []string{
+		"INFO  core/types | Job started",
+		"ERROR core/types | Job failed",
+		"| assistant ",
+		"DEBUG morty | Config loaded",
+		"\n" + strings.Repeat(" ", len("2006-01-02 15:04:05 INFO  core/types | ")) + "  file: main.go\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in formatted output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, `"type":"assistant"`) {
+		t.Errorf("Expected events to be formatted, got raw JSON:\n%s", out)
+	}
+
+	if !strings.Contains(raw.String(), `"type":"assistant"`) || !strings.Contains(raw.String(), `"msg":"Job started"`) {
+		t.Errorf("Expected raw output to keep original lines:\n%s", raw.String())
+	}
+}
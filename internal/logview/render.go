@@ -0,0 +1,100 @@
+package logview
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/morty/morty/internal/executor"
+)
+
+// Renderer writes records to a terminal or file.
+type Renderer struct {
+	w      io.Writer
+	raw    bool
+	events *executor.EventFormatter
+}
+
+// NewRenderer creates a renderer. Raw output writes each record's original
+// text; formatted output prefixes records with time, level and job, renders
+// JSON log entries as text and formats stream-json events with
+// executor.EventFormatter.
+func NewRenderer(w io.Writer, raw bool) *Renderer {
+	return &Renderer{w: w, raw: raw, events: executor.NewEventFormatter(w)}
+}
+
+// Write renders records in order.
+func (r *Renderer) Write(records []Record) error {
+	for _, rec := range records {
+		if err := r.write(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Renderer) write(rec Record) error {
+	if r.raw {
+		_, err := fmt.Fprintln(r.w, rec.Text)
+		return err
+	}
+
+	prefix := fmt.Sprintf("%s %-5s %s | ", rec.Time.Local().Format(localTimeLayout), rec.Level.String(), label(rec))
+	if _, err := io.WriteString(r.w, prefix); err != nil {
+		return err
+	}
+
+	if rec.Event && r.events.FormatLine(rec.Text) {
+		return nil
+	}
+
+	text := rec.Text
+	if rec.fields != nil {
+		text = formatFields(rec.fields)
+	}
+	lines := strings.Split(text, "\n")
+	indent := strings.Repeat(" ", len(prefix))
+	for i, line := range lines {
+		if i > 0 {
+			line = indent + line
+		}
+		if _, err := fmt.Fprintln(r.w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// label names the origin of a record.
+func label(rec Record) string {
+	switch {
+	case rec.Module != "" && rec.Job != "":
+		return jobKey(rec.Module, rec.Job)
+	case rec.Module != "":
+		return rec.Module
+	case rec.source != nil && rec.source.Kind != KindGlobal:
+		return rec.source.Stem
+	default:
+		return "morty"
+	}
+}
+
+// formatFields renders a JSON log entry as "message key=value ...".
+func formatFields(fields map[string]interface{}) string {
+	var keys []string
+	for key := range fields {
+		switch key {
+		case "time", "level", "msg", "module", "job":
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := []string{stringField(fields, "msg")}
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%v", key, fields[key]))
+	}
+	return strings.Join(parts, " ")
+}
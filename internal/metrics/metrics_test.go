@@ -0,0 +1,180 @@
+package metrics
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/morty/morty/internal/callcli"
+)
+
+func render(t *testing.T, r *Registry) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := r.WriteText(&buf); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+	return buf.String()
+}
+
+func assertContains(t *testing.T, out string, lines ...string) {
+	t.Helper()
+	for _, line := range lines {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("Expected line %q in:\n%s", line, out)
+		}
+	}
+}
+
+func TestRegistry_Counter(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounter("jobs_total", "Jobs finished.", "status")
+	c.Inc("COMPLETED")
+	c.Add(2, "COMPLETED")
+	c.Inc("FAILED")
+	c.Add(-1, "FAILED") // counters never decrease
+
+	assertContains(t, render(t, r),
+		"# HELP jobs_total Jobs finished.",
+		"# TYPE jobs_total counter",
+		`jobs_total{status="COMPLETED"} 3`,
+		`jobs_total{status="FAILED"} 1`,
+	)
+}
+
+func TestRegistry_Gauge(t *testing.T) {
+	r := NewRegistry()
+	g := r.NewGauge("last_run", "Last run.")
+	g.Set(5)
+	g.Set(1.5)
+
+	assertContains(t, render(t, r), "# TYPE last_run gauge", "last_run 1.5")
+}
+
+func TestRegistry_Histogram(t *testing.T) {
+	r := NewRegistry()
+	h := r.NewHistogram("duration_seconds", "Duration.", []float64{1, 10}, "command")
+	h.Observe(0.5, "claude")
+	h.Observe(5, "claude")
+	h.Observe(50, "claude")
+
+	assertContains(t, render(t, r),
+		"# TYPE duration_seconds histogram",
+		`duration_seconds_bucket{command="claude",le="1"} 1`,
+		`duration_seconds_bucket{command="claude",le="10"} 2`,
+		`duration_seconds_bucket{command="claude",le="+Inf"} 3`,
+		`duration_seconds_sum{command="claude"} 55.5`,
+		`duration_seconds_count{command="claude"} 3`,
+	)
+}
+
+func TestRegistry_OmitsEmptyAndEscapes(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("unused_total", "Never incremented.")
+	c := r.NewCounter("labels_total", "Line one\nline two.", "value")
+	c.Inc("a \"quoted\" \\ path\nnext")
+
+	out := render(t, r)
+	if strings.Contains(out, "unused_total") {
+		t.Errorf("Expected metric without samples to be omitted:\n%s", out)
+	}
+	assertContains(t, out,
+		`# HELP labels_total Line one\nline two.`,
+		`labels_total{value="a \"quoted\" \\ path\nnext"} 1`,
+	)
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("runs_total", "Runs.").Inc()
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Unexpected content type %q", ct)
+	}
+	body, _ := io.ReadAll(rec.Body)
+	assertContains(t, string(body), "runs_total 1")
+}
+
+func TestRegistry_WriteTextfile(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounter("runs_total", "Runs.")
+	path := filepath.Join(t.TempDir(), "metrics", "morty.prom")
+
+	for i := 0; i < 2; i++ {
+		c.Inc()
+		if err := r.WriteTextfile(path); err != nil {
+			t.Fatalf("WriteTextfile failed: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Textfile not written: %v", err)
+	}
+	assertContains(t, string(data), "runs_total 2")
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("Expected temporary files to be removed, got %d entries", len(entries))
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	rec := NewRecorder()
+	rec.JobFinished("COMPLETED", 90*time.Second)
+	rec.JobFinished("FAILED", 10*time.Second)
+	rec.JobBlocked()
+	rec.JobRetried()
+	rec.CLIExecuted(&callcli.ExecutionLog{Command: "claude", Success: true, Duration: 20 * time.Second})
+	rec.CLIExecuted(&callcli.ExecutionLog{Command: "claude", TimedOut: true, Duration: time.Minute})
+	rec.TokensUsed(1200, 300, 0.25)
+	rec.Committed(3, 120)
+	rec.CLIStats(&callcli.ExecutionStats{TotalExecutions: 2, FailedExecutions: 1, TotalDuration: 80 * time.Second})
+	rec.RunFinished(time.Unix(1700000000, 0), false)
+
+	assertContains(t, render(t, rec.Registry()),
+		`morty_jobs_total{status="BLOCKED"} 1`,
+		`morty_jobs_total{status="COMPLETED"} 1`,
+		`morty_jobs_total{status="FAILED"} 1`,
+		`morty_job_duration_seconds_bucket{status="COMPLETED",le="120"} 1`,
+		"morty_job_retries_total 1",
+		`morty_cli_executions_total{command="claude",result="success"} 1`,
+		`morty_cli_executions_total{command="claude",result="timeout"} 1`,
+		`morty_cli_duration_seconds_count{command="claude"} 2`,
+		`morty_tokens_total{type="input"} 1200`,
+		`morty_tokens_total{type="output"} 300`,
+		"morty_cost_usd_total 0.25",
+		"morty_commits_total 1",
+		"morty_commit_files_sum 3",
+		"morty_commit_lines_sum 120",
+		"morty_last_run_timestamp_seconds 1.7e+09",
+		"morty_last_run_success 0",
+		"morty_last_run_cli_calls 2",
+		"morty_last_run_cli_failures 1",
+		"morty_last_run_cli_duration_seconds 80",
+	)
+}
+
+func TestRecorder_Nil(t *testing.T) {
+	var rec *Recorder
+	// A nil recorder must be safe to use
+	rec.JobFinished("COMPLETED", time.Second)
+	rec.JobBlocked()
+	rec.JobRetried()
+	rec.CLIExecuted(&callcli.ExecutionLog{})
+	rec.TokensUsed(1, 1, 1)
+	rec.Committed(1, 1)
+	rec.CLIStats(&callcli.ExecutionStats{})
+	rec.RunFinished(time.Now(), true)
+	if rec.Registry() != nil {
+		t.Error("Expected nil registry")
+	}
+}
@@ -0,0 +1,177 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/morty/morty/internal/callcli"
+	"github.com/morty/morty/internal/state"
+)
+
+// Histogram buckets.
+var (
+	// jobDurationBuckets spans quick fixes to long jobs (seconds).
+	jobDurationBuckets = []float64{30, 60, 120, 300, 600, 900, 1200, 1800, 3600}
+	// cliDurationBuckets spans short CLI calls to long AI sessions (seconds).
+	cliDurationBuckets = []float64{1, 5, 15, 30, 60, 120, 300, 600, 1200, 1800}
+	// commitFileBuckets is the number of files changed by a commit.
+	commitFileBuckets = []float64{1, 2, 5, 10, 20, 50, 100, 200}
+	// commitLineBuckets is the number of lines added and deleted by a commit.
+	commitLineBuckets = []float64{10, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+)
+
+// Recorder records Morty's job, AI CLI and commit metrics. A nil Recorder
+// records nothing.
+type Recorder struct {
+	registry *Registry
+
+	jobs          *CounterVec
+	jobDuration   *HistogramVec
+	jobRetries    *CounterVec
+	cliExecutions *CounterVec
+	cliDuration   *HistogramVec
+	tokens        *CounterVec
+	cost          *CounterVec
+	commits       *CounterVec
+	commitFiles   *HistogramVec
+	commitLines   *HistogramVec
+	lastRun       *GaugeVec
+	lastRunOK     *GaugeVec
+
+	lastRunCLICalls    *GaugeVec
+	lastRunCLIFailures *GaugeVec
+	lastRunCLIDuration *GaugeVec
+}
+
+// NewRecorder creates a recorder with its own registry.
+func NewRecorder() *Recorder {
+	r := NewRegistry()
+	return &Recorder{
+		registry: r,
+		jobs: r.NewCounter("morty_jobs_total",
+			"Jobs finished, by final status.", "status"),
+		jobDuration: r.NewHistogram("morty_job_duration_seconds",
+			"Wall-clock time of job attempts.", jobDurationBuckets, "status"),
+		jobRetries: r.NewCounter("morty_job_retries_total",
+			"Retries of failed jobs."),
+		cliExecutions: r.NewCounter("morty_cli_executions_total",
+			"AI CLI executions, by command and result (success, failure, timeout, interrupted).", "command", "result"),
+		cliDuration: r.NewHistogram("morty_cli_duration_seconds",
+			"Duration of AI CLI executions.", cliDurationBuckets, "command"),
+		tokens: r.NewCounter("morty_tokens_total",
+			"Tokens used by AI CLI executions, by type (input, output).", "type"),
+		cost: r.NewCounter("morty_cost_usd_total",
+			"Cost of AI CLI executions in US dollars."),
+		commits: r.NewCounter("morty_commits_total",
+			"Auto-commits created after job completion."),
+		commitFiles: r.NewHistogram("morty_commit_files",
+			"Files changed by auto-commits.", commitFileBuckets),
+		commitLines: r.NewHistogram("morty_commit_lines",
+			"Lines added plus deleted by auto-commits.", commitLineBuckets),
+		lastRun: r.NewGauge("morty_last_run_timestamp_seconds",
+			"Unix time the last doing run finished."),
+		lastRunOK: r.NewGauge("morty_last_run_success",
+			"Whether the last doing run succeeded (1) or not (0)."),
+		lastRunCLICalls: r.NewGauge("morty_last_run_cli_calls",
+			"AI CLI executions of the last doing run."),
+		lastRunCLIFailures: r.NewGauge("morty_last_run_cli_failures",
+			"Failed AI CLI executions of the last doing run."),
+		lastRunCLIDuration: r.NewGauge("morty_last_run_cli_duration_seconds",
+			"Total duration of the AI CLI executions of the last doing run."),
+	}
+}
+
+// Registry returns the registry holding the recorder's metrics.
+func (r *Recorder) Registry() *Registry {
+	if r == nil {
+		return nil
+	}
+	return r.registry
+}
+
+// JobFinished records the final status (COMPLETED or FAILED) and duration
+// of a job attempt.
+func (r *Recorder) JobFinished(status string, duration time.Duration) {
+	if r == nil {
+		return
+	}
+	r.jobs.Inc(status)
+	r.jobDuration.Observe(duration.Seconds(), status)
+}
+
+// JobBlocked records a job blocked by a failed prerequisite.
+func (r *Recorder) JobBlocked() {
+	if r == nil {
+		return
+	}
+	r.jobs.Inc(string(state.StatusBlocked))
+}
+
+// JobRetried records a retry of a failed job.
+func (r *Recorder) JobRetried() {
+	if r == nil {
+		return
+	}
+	r.jobRetries.Inc()
+}
+
+// CLIExecuted records an AI CLI execution.
+func (r *Recorder) CLIExecuted(log *callcli.ExecutionLog) {
+	if r == nil || log == nil {
+		return
+	}
+	result := "failure"
+	switch {
+	case log.TimedOut:
+		result = "timeout"
+	case log.Interrupted:
+		result = "interrupted"
+	case log.Success:
+		result = "success"
+	}
+	r.cliExecutions.Inc(log.Command, result)
+	r.cliDuration.Observe(log.Duration.Seconds(), log.Command)
+}
+
+// TokensUsed records the token usage and cost of an AI CLI execution.
+func (r *Recorder) TokensUsed(input, output int, costUSD float64) {
+	if r == nil {
+		return
+	}
+	r.tokens.Add(float64(input), "input")
+	r.tokens.Add(float64(output), "output")
+	r.cost.Add(costUSD)
+}
+
+// Committed records the size of an auto-commit.
+func (r *Recorder) Committed(files, lines int) {
+	if r == nil {
+		return
+	}
+	r.commits.Inc()
+	r.commitFiles.Observe(float64(files))
+	r.commitLines.Observe(float64(lines))
+}
+
+// CLIStats records the AI CLI execution statistics of a doing run, as kept
+// by its execution logger.
+func (r *Recorder) CLIStats(stats *callcli.ExecutionStats) {
+	if r == nil || stats == nil {
+		return
+	}
+	r.lastRunCLICalls.Set(float64(stats.TotalExecutions))
+	r.lastRunCLIFailures.Set(float64(stats.FailedExecutions))
+	r.lastRunCLIDuration.Set(stats.TotalDuration.Seconds())
+}
+
+// RunFinished records the end of a doing run.
+func (r *Recorder) RunFinished(at time.Time, success bool) {
+	if r == nil {
+		return
+	}
+	r.lastRun.Set(float64(at.Unix()))
+	ok := 0.0
+	if success {
+		ok = 1
+	}
+	r.lastRunOK.Set(ok)
+}
@@ -0,0 +1,279 @@
+// Package metrics maintains Morty's Prometheus metrics. Metrics are
+// rendered in the Prometheus text exposition format, served on /metrics or
+// written as a node-exporter textfile.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// metricType is the Prometheus metric type of a family.
+type metricType string
+
+const (
+	typeCounter   metricType = "counter"
+	typeGauge     metricType = "gauge"
+	typeHistogram metricType = "histogram"
+)
+
+// Registry holds metric families.
+type Registry struct {
+	mu       sync.Mutex
+	families []*family
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// family is a metric with all its label combinations.
+type family struct {
+	name    string
+	help    string
+	typ     metricType
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+// series is the value of one label combination.
+type series struct {
+	labelValues []string
+	value       float64
+	// Histogram state: cumulative counts per bucket, plus sum and count
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func (r *Registry) register(name, help string, typ metricType, buckets []float64, labels []string) *family {
+	f := &family{
+		name:    name,
+		help:    help,
+		typ:     typ,
+		labels:  labels,
+		buckets: buckets,
+		series:  make(map[string]*series),
+	}
+	r.mu.Lock()
+	r.families = append(r.families, f)
+	r.mu.Unlock()
+	return f
+}
+
+// get returns the series for labelValues, creating it if needed.
+// Must be called with f.mu held.
+func (f *family) get(labelValues []string) *series {
+	if len(labelValues) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", f.name, len(f.labels), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labelValues: append([]string(nil), labelValues...)}
+		if f.typ == typeHistogram {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// CounterVec is a counter partitioned by labels.
+type CounterVec struct {
+	f *family
+}
+
+// NewCounter registers a counter.
+func (r *Registry) NewCounter(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{f: r.register(name, help, typeCounter, nil, labels)}
+}
+
+// Add adds v (which must not be negative) to the counter.
+func (c *CounterVec) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		return
+	}
+	c.f.mu.Lock()
+	defer c.f.mu.Unlock()
+	c.f.get(labelValues).value += v
+}
+
+// Inc increments the counter by one.
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// GaugeVec is a gauge partitioned by labels.
+type GaugeVec struct {
+	f *family
+}
+
+// NewGauge registers a gauge.
+func (r *Registry) NewGauge(name, help string, labels ...string) *GaugeVec {
+	return &GaugeVec{f: r.register(name, help, typeGauge, nil, labels)}
+}
+
+// Set sets the gauge.
+func (g *GaugeVec) Set(v float64, labelValues ...string) {
+	g.f.mu.Lock()
+	defer g.f.mu.Unlock()
+	g.f.get(labelValues).value = v
+}
+
+// HistogramVec is a histogram partitioned by labels.
+type HistogramVec struct {
+	f *family
+}
+
+// NewHistogram registers a histogram with the given upper bucket bounds
+// (in increasing order; the +Inf bucket is implicit).
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	return &HistogramVec{f: r.register(name, help, typeHistogram, buckets, labels)}
+}
+
+// Observe adds an observation to the histogram.
+func (h *HistogramVec) Observe(v float64, labelValues ...string) {
+	h.f.mu.Lock()
+	defer h.f.mu.Unlock()
+	s := h.f.get(labelValues)
+	for i, bound := range h.f.buckets {
+		if v <= bound {
+			s.counts[i]++
+		}
+	}
+	s.sum += v
+	s.count++
+}
+
+// WriteText writes all metrics in the Prometheus text exposition format.
+// Families without observations are omitted.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	families := append([]*family(nil), r.families...)
+	r.mu.Unlock()
+
+	var sb strings.Builder
+	for _, f := range families {
+		f.writeText(&sb)
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func (f *family) writeText(sb *strings.Builder) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.series) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintf(sb, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+	fmt.Fprintf(sb, "# TYPE %s %s\n", f.name, f.typ)
+	for _, key := range keys {
+		s := f.series[key]
+		if f.typ != typeHistogram {
+			fmt.Fprintf(sb, "%s%s %s\n", f.name, f.labelString(s.labelValues, "", ""), formatValue(s.value))
+			continue
+		}
+		for i, bound := range f.buckets {
+			fmt.Fprintf(sb, "%s_bucket%s %d\n", f.name, f.labelString(s.labelValues, "le", formatValue(bound)), s.counts[i])
+		}
+		fmt.Fprintf(sb, "%s_bucket%s %d\n", f.name, f.labelString(s.labelValues, "le", "+Inf"), s.count)
+		fmt.Fprintf(sb, "%s_sum%s %s\n", f.name, f.labelString(s.labelValues, "", ""), formatValue(s.sum))
+		fmt.Fprintf(sb, "%s_count%s %d\n", f.name, f.labelString(s.labelValues, "", ""), s.count)
+	}
+}
+
+// labelString renders {k="v",...}, optionally with an extra label.
+func (f *family) labelString(values []string, extraName, extraValue string) string {
+	var pairs []string
+	for i, name := range f.labels {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, name, escapeLabel(values[i])))
+	}
+	if extraName != "" {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, extraName, extraValue))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// escapeLabel escapes backslash, double quote and newline in a label value.
+func escapeLabel(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := r.WriteText(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// WriteTextfile writes the metrics to path for the node-exporter textfile
+// collector. The file is replaced atomically so a scrape never sees a
+// partial file.
+func (r *Registry) WriteTextfile(path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create metrics directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("failed to create metrics file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := r.WriteText(tmp); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write metrics file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write metrics file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write metrics file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace metrics file: %w", err)
+	}
+	return nil
+}
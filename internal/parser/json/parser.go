@@ -0,0 +1,72 @@
+// Package json provides a JSON parser implementation.
+package json
+
+import (
+	"context"
+	stdjson "encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/morty/morty/internal/parser"
+)
+
+// Parser implements the parser.Parser interface for JSON files.
+type Parser struct{}
+
+// Ensure Parser implements the parser.Parser interface.
+var _ parser.Parser = (*Parser)(nil)
+
+// NewParser creates a new JSON parser instance.
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// Parse reads from r and returns the decoded document as generic values.
+// Numbers are kept as json.Number so integers survive unchanged.
+func (p *Parser) Parse(ctx context.Context, r io.Reader) (*parser.ParseResult, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content: %w", err)
+	}
+
+	dec := stdjson.NewDecoder(strings.NewReader(string(content)))
+	dec.UseNumber()
+
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return &parser.ParseResult{
+			Type:    parser.FileTypeJSON,
+			Content: nil,
+			Errors:  []error{fmt.Errorf("invalid JSON: %w", err)},
+		}, nil
+	}
+	if dec.More() {
+		return &parser.ParseResult{
+			Type:    parser.FileTypeJSON,
+			Content: nil,
+			Errors:  []error{fmt.Errorf("invalid JSON: unexpected data after top-level value")},
+		}, nil
+	}
+
+	return &parser.ParseResult{
+		Type:    parser.FileTypeJSON,
+		Content: value,
+		Errors:  nil,
+	}, nil
+}
+
+// ParseString parses content from a string.
+func (p *Parser) ParseString(ctx context.Context, content string) (*parser.ParseResult, error) {
+	return p.Parse(ctx, strings.NewReader(content))
+}
+
+// Supports returns true if this parser can handle the given file type.
+func (p *Parser) Supports(fileType parser.FileType) bool {
+	return fileType == parser.FileTypeJSON
+}
+
+// FileType returns the file type this parser handles.
+func (p *Parser) FileType() parser.FileType {
+	return parser.FileTypeJSON
+}
@@ -0,0 +1,63 @@
+package json
+
+import (
+	"context"
+	stdjson "encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/morty/morty/internal/parser"
+)
+
+// TestParse tests decoding of valid JSON documents.
+func TestParse(t *testing.T) {
+	p := NewParser()
+
+	result, err := p.Parse(context.Background(), strings.NewReader(`{"name": "demo", "jobs": [1, 2]}`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("Unexpected errors: %v", result.Errors)
+	}
+
+	want := map[string]interface{}{
+		"name": "demo",
+		"jobs": []interface{}{stdjson.Number("1"), stdjson.Number("2")},
+	}
+	if !reflect.DeepEqual(result.Content, want) {
+		t.Errorf("Expected %#v, got %#v", want, result.Content)
+	}
+}
+
+// TestParseInvalid tests that malformed input is reported in the result.
+func TestParseInvalid(t *testing.T) {
+	p := NewParser()
+
+	for _, input := range []string{`{"name":`, `{} {}`, ``} {
+		result, err := p.ParseString(context.Background(), input)
+		if err != nil {
+			t.Fatalf("ParseString(%q) returned error: %v", input, err)
+		}
+		if len(result.Errors) == 0 {
+			t.Errorf("Expected errors for %q", input)
+		}
+	}
+}
+
+// TestRegister tests registration with the parser factory.
+func TestRegister(t *testing.T) {
+	f := parser.NewFactoryWithDefaults()
+	if err := Register(f); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	p, err := f.GetByExtension("plan.json")
+	if err != nil {
+		t.Fatalf("GetByExtension failed: %v", err)
+	}
+	if !p.Supports(parser.FileTypeJSON) {
+		t.Errorf("Expected JSON parser, got %s", p.FileType())
+	}
+}
@@ -0,0 +1,114 @@
+package plan
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// nonModuleChars matches runs of characters not allowed in module names.
+	nonModuleChars = regexp.MustCompile(`[^a-z0-9_]+`)
+	// moduleIdentifier matches dependency entries that spell a module name,
+	// as opposed to annotations such as "所有模块" or "无（独立测试模块）".
+	moduleIdentifier = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	// prerequisitePattern matches loosely written job references such as
+	// "Job 2", "job2", "auth:Job_3 - 说明" or "job_1：说明", but not ranges
+	// such as "Job 1-5 完成".
+	prerequisitePattern = regexp.MustCompile(`(?i)^(?:([a-z0-9_-]+)\s*:\s*)?job[\s_]*(\d+)\b(?:(?:\s+-\s+|\s*[:：]\s*)(.*))?$`)
+)
+
+// CanonicalModuleName converts a module or file name into the canonical
+// module name form: lowercase letters, digits and underscores.
+func CanonicalModuleName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = nonModuleChars.ReplaceAllString(name, "_")
+	return strings.Trim(name, "_")
+}
+
+// Canonicalize fixes mechanical format violations in place so that the plan
+// renders in canonical form:
+//   - a missing plan name is filled with the module name
+//   - dependency names are bare, lowercase module names without duplicates;
+//     entries that are not module names are kept as written
+//   - jobs and tasks are numbered from 1, and same-module prerequisites
+//     follow the renumbering
+//   - prerequisites use the job_N / module:job_N syntax
+func Canonicalize(p *Plan, module string) {
+	module = CanonicalModuleName(module)
+	if strings.TrimSpace(p.Name) == "" {
+		p.Name = module
+	}
+
+	p.Dependencies = canonicalModules(p.Dependencies)
+	p.Dependents = canonicalModules(p.Dependents)
+
+	// Map old job indexes to their position; the first job wins on duplicates
+	renumbered := make(map[int]int, len(p.Jobs))
+	for i, job := range p.Jobs {
+		if _, ok := renumbered[job.Index]; !ok {
+			renumbered[job.Index] = i + 1
+		}
+	}
+
+	for i := range p.Jobs {
+		job := &p.Jobs[i]
+		job.Index = i + 1
+		for j := range job.Tasks {
+			job.Tasks[j].Index = j + 1
+		}
+		for j, prereq := range job.Prerequisites {
+			job.Prerequisites[j] = canonicalPrerequisite(prereq, module, renumbered)
+		}
+		job.IsCompleted = isJobMarkedCompleted(job.CompletionStatus)
+	}
+}
+
+// canonicalModules normalizes a list of module names.
+func canonicalModules(names []string) []string {
+	var result []string
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if bare := strings.Trim(name, "`"); bare == "__ALL__" {
+			name = bare
+		} else if moduleIdentifier.MatchString(bare) {
+			name = CanonicalModuleName(bare)
+		}
+		if name == "" || IsNone(name) || seen[name] {
+			continue
+		}
+		seen[name] = true
+		result = append(result, name)
+	}
+	return result
+}
+
+// canonicalPrerequisite rewrites a job reference into job_N or
+// module:job_N form. Natural language prerequisites are kept as is.
+func canonicalPrerequisite(prereq, module string, renumbered map[int]int) string {
+	m := prerequisitePattern.FindStringSubmatch(strings.Trim(strings.TrimSpace(prereq), "`"))
+	if m == nil {
+		return prereq
+	}
+
+	var index int
+	fmt.Sscanf(m[2], "%d", &index)
+
+	ref := CanonicalModuleName(m[1])
+	if ref == "" || ref == module {
+		if newIndex, ok := renumbered[index]; ok {
+			index = newIndex
+		}
+		ref = ""
+	}
+
+	result := fmt.Sprintf("job_%d", index)
+	if ref != "" {
+		result = ref + ":" + result
+	}
+	if desc := strings.TrimSpace(m[3]); desc != "" {
+		result += " - " + desc
+	}
+	return result
+}
@@ -0,0 +1,93 @@
+package plan
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCanonicalModuleName(t *testing.T) {
+	tests := map[string]string{
+		"user_auth":    "user_auth",
+		"User_Auth":    "user_auth",
+		"user-auth":    "user_auth",
+		" Data Store ": "data_store",
+		"__cache__":    "cache",
+		"生产测试":         "",
+	}
+	for input, want := range tests {
+		if got := CanonicalModuleName(input); got != want {
+			t.Errorf("CanonicalModuleName(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	p := &Plan{
+		Name:         "User-Auth",
+		Dependencies: []string{"`Storage`", "storage", "无"},
+		Dependents:   []string{"__ALL__"},
+		Jobs: []Job{
+			{Index: 2, Tasks: []TaskItem{{Index: 3}, {Index: 5}}, CompletionStatus: "✅ 已完成"},
+			{Index: 5, Prerequisites: []string{"Job 2", "user_auth:job2", "Storage:Job 4 - 接口", "job_9", "数据库可用", "Job 1-5 完成"}},
+		},
+	}
+
+	Canonicalize(p, "user_auth")
+
+	if p.Name != "User-Auth" {
+		t.Errorf("Name = %q, want User-Auth", p.Name)
+	}
+	if !reflect.DeepEqual(p.Dependencies, []string{"storage"}) {
+		t.Errorf("Dependencies = %v", p.Dependencies)
+	}
+	if !reflect.DeepEqual(p.Dependents, []string{"__ALL__"}) {
+		t.Errorf("Dependents = %v", p.Dependents)
+	}
+	if p.Jobs[0].Index != 1 || p.Jobs[1].Index != 2 {
+		t.Errorf("Jobs not renumbered: %d, %d", p.Jobs[0].Index, p.Jobs[1].Index)
+	}
+	if p.Jobs[0].Tasks[0].Index != 1 || p.Jobs[0].Tasks[1].Index != 2 {
+		t.Errorf("Tasks not renumbered: %+v", p.Jobs[0].Tasks)
+	}
+	if !p.Jobs[0].IsCompleted {
+		t.Error("Expected job 1 to be marked completed")
+	}
+
+	want := []string{"job_1", "job_1", "storage:job_4 - 接口", "job_9", "数据库可用", "Job 1-5 完成"}
+	if !reflect.DeepEqual(p.Jobs[1].Prerequisites, want) {
+		t.Errorf("Prerequisites = %v, want %v", p.Jobs[1].Prerequisites, want)
+	}
+}
+
+func TestCanonicalizeKeepsDifferentName(t *testing.T) {
+	p := &Plan{Name: "something else"}
+	Canonicalize(p, "cache")
+	if p.Name != "something else" {
+		t.Errorf("Expected unrelated plan name to be kept, got %q", p.Name)
+	}
+}
+
+func TestCanonicalizeKeepsHumanText(t *testing.T) {
+	p := &Plan{
+		Name:         "BDD 用户旅程测试",
+		Dependencies: []string{"所有模块", "Config"},
+		Dependents:   []string{"无（独立测试模块）"},
+	}
+	Canonicalize(p, "bdd")
+
+	if p.Name != "BDD 用户旅程测试" {
+		t.Errorf("Name = %q, want the human title kept", p.Name)
+	}
+	if !reflect.DeepEqual(p.Dependencies, []string{"所有模块", "config"}) {
+		t.Errorf("Dependencies = %v", p.Dependencies)
+	}
+	if !reflect.DeepEqual(p.Dependents, []string{"无（独立测试模块）"}) {
+		t.Errorf("Dependents = %v", p.Dependents)
+	}
+
+	empty := &Plan{}
+	Canonicalize(empty, "BDD")
+	if empty.Name != "bdd" {
+		t.Errorf("Name = %q, want a missing name filled with bdd", empty.Name)
+	}
+}
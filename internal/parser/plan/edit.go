@@ -0,0 +1,39 @@
+package plan
+
+import (
+	"regexp"
+	"strings"
+)
+
+// SetField replaces the value of a bold field such as "**依赖模块**:" in
+// markdown content with value, dropping the list items that followed it.
+// The rest of the content is left untouched. It reports false if the
+// field is not found.
+func SetField(content string, section Section, value string) (string, bool) {
+	fieldPattern := regexp.MustCompile(`^(\s*\*\*` + fieldNamePattern(CurrentHeadings().Aliases(section)) + `\*\*)[:：]`)
+	listItemPattern := regexp.MustCompile(`^\s*[-*]\s+\S`)
+
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		m := fieldPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		// The field's list: items, and blank lines between items
+		end := i + 1
+		for j := i + 1; j < len(lines); j++ {
+			if listItemPattern.MatchString(lines[j]) {
+				end = j + 1
+			} else if strings.TrimSpace(lines[j]) != "" {
+				break
+			}
+		}
+
+		replaced := append([]string{}, lines[:i]...)
+		replaced = append(replaced, m[1]+": "+value)
+		replaced = append(replaced, lines[end:]...)
+		return strings.Join(replaced, "\n"), true
+	}
+	return content, false
+}
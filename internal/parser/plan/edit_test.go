@@ -0,0 +1,28 @@
+package plan
+
+import "testing"
+
+func TestSetField(t *testing.T) {
+	content := "## 模块概述\n\n**依赖模块**:\n- config\n\n- logging\n\n**被依赖模块**: 无\n\n## Jobs\n"
+
+	got, ok := SetField(content, SectionDependencies, "config, logging, metrics")
+	want := "## 模块概述\n\n**依赖模块**: config, logging, metrics\n\n**被依赖模块**: 无\n\n## Jobs\n"
+	if !ok || got != want {
+		t.Errorf("SetField(dependencies) = %q, %v, want %q", got, ok, want)
+	}
+
+	got, ok = SetField(want, SectionDependents, "api")
+	want = "## 模块概述\n\n**依赖模块**: config, logging, metrics\n\n**被依赖模块**: api\n\n## Jobs\n"
+	if !ok || got != want {
+		t.Errorf("SetField(dependents) = %q, %v, want %q", got, ok, want)
+	}
+
+	english := "**Dependencies**: None\n"
+	if got, ok := SetField(english, SectionDependencies, "config"); !ok || got != "**Dependencies**: config\n" {
+		t.Errorf("SetField(english) = %q, %v", got, ok)
+	}
+
+	if _, ok := SetField("## Jobs\n", SectionDependents, "api"); ok {
+		t.Error("Expected a missing field to be reported")
+	}
+}
@@ -0,0 +1,556 @@
+package plan
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Anchors name the places of the canonical layout where text that the Plan
+// struct does not model is written back. Field anchors are
+// "<part>:<section>" and follow the field; "<part>:" follows the heading.
+const (
+	anchorHead       = "head"        // before the title, e.g. frontmatter
+	anchorTitle      = "title"       // below the title and its metadata
+	anchorAfterTitle = "after:title" // unknown sections before the first known one
+	anchorOverview   = "overview"
+	anchorJobs       = "jobs"
+)
+
+var (
+	// blockHeadingPattern and fencePattern follow markdown.Parser, so that
+	// headings are split exactly where ParsePlan splits them.
+	blockHeadingPattern = regexp.MustCompile(`^(#{1,6})\s+(.+)$`)
+	fencePattern        = regexp.MustCompile("^\\s*`{3}(\\w*)\\s*$")
+	// boldFieldPattern matches a "**Field**: value" line.
+	boldFieldPattern = regexp.MustCompile(`^\s*\*\*([^*]+)\*\*\s*[:：]?(.*)$`)
+	// tasksFieldPattern matches the tasks field the way extractTasksFromContent does.
+	tasksFieldPattern = regexp.MustCompile(`(?i)^\s*\*\*tasks?`)
+	// metadataLinePattern matches a line holding only a metadata comment.
+	metadataLinePattern = regexp.MustCompile(`^\s*<!--\s*morty:.*-->\s*$`)
+	// listLinePattern matches a bullet list item.
+	listLinePattern = regexp.MustCompile(`^\s*[-*]\s*(.+)$`)
+	// taskLinePattern and indexedTaskLinePattern follow parseTaskItems.
+	taskLinePattern        = regexp.MustCompile(`(?i)^\s*[-*]\s*\[[ xX]\]\s*.+$`)
+	indexedTaskLinePattern = regexp.MustCompile(`(?i)^\s*[-*]\s*\[[ xX]\]\s*task\s*\d+[:：]\s*.+$`)
+	// debugLogLinePattern follows parseDebugLogContent.
+	debugLogLinePattern = regexp.MustCompile(`(?i)^\s*[-*]\s*(debug\d+|explore\d+)[:：]\s*.+$`)
+)
+
+var (
+	overviewFields = []Section{SectionResponsibility, SectionResearch, SectionReferences, SectionDependencies, SectionDependents}
+	jobFields      = []Section{SectionGoal, SectionPrerequisites, SectionTasks, SectionValidators, SectionDebugLogs, SectionCompletionStatus}
+	rawSections    = []Section{SectionInterfaces, SectionDataModel, SectionIntegrationTest}
+)
+
+// afterAnchor is where unknown sections following a known H2 section go.
+func afterAnchor(section Section) string {
+	return "after:" + string(section)
+}
+
+// jobAnchor names a place inside the job at the given position (0-based);
+// part is "" for the job heading, a section or "end".
+func jobAnchor(job int, part string) string {
+	return jobPart(job) + ":" + part
+}
+
+// jobPart names the field anchors of the job at the given position.
+func jobPart(job int) string {
+	return fmt.Sprintf("job:%d", job)
+}
+
+// fieldAnchor names the place after a field of part, or after the heading
+// of part when section is empty.
+func fieldAnchor(part string, section Section) string {
+	return part + ":" + string(section)
+}
+
+// extras holds the text of a markdown plan that Plan does not model: notes,
+// free-form paragraphs, comments, unknown sections and validators written as
+// prose. ReformatMarkdown writes it back verbatim at the same place.
+type extras struct {
+	blocks     map[string][]string
+	validators map[int]string // verbatim validators body by job position
+}
+
+func (x *extras) add(anchor string, lines ...string) {
+	if anchor != "" {
+		x.blocks[anchor] = append(x.blocks[anchor], lines...)
+	}
+}
+
+// text returns the extras of an anchor with blank runs collapsed outside code
+// fences, or "" when there are none.
+func (x *extras) text(anchor string) string {
+	if x == nil {
+		return ""
+	}
+	var out []string
+	inFence := false
+	for _, line := range x.blocks[anchor] {
+		if fencePattern.MatchString(line) {
+			inFence = !inFence
+		}
+		if !inFence && strings.TrimSpace(line) == "" && len(out) > 0 && strings.TrimSpace(out[len(out)-1]) == "" {
+			continue
+		}
+		out = append(out, line)
+	}
+	return strings.Trim(strings.Join(out, "\n"), "\n")
+}
+
+// validatorsBody returns the verbatim validators of the job at position.
+func (x *extras) validatorsBody(job int) (string, bool) {
+	if x == nil {
+		return "", false
+	}
+	body, ok := x.validators[job]
+	return body, ok
+}
+
+// block is a heading and the lines up to the next heading. The block before
+// the first heading has level 0.
+type block struct {
+	level   int
+	title   string
+	heading string
+	body    []string
+}
+
+// lines returns the heading line followed by the body.
+func (b block) lines() []string {
+	if b.level == 0 {
+		return b.body
+	}
+	return append([]string{b.heading}, b.body...)
+}
+
+// splitBlocks splits markdown into heading blocks, ignoring headings inside
+// code fences.
+func splitBlocks(content string) []block {
+	blocks := []block{{}}
+	inFence := false
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if fencePattern.MatchString(line) {
+			inFence = !inFence
+		} else if !inFence {
+			if m := blockHeadingPattern.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+				blocks = append(blocks, block{level: len(m[1]), title: strings.TrimSpace(m[2]), heading: line})
+				continue
+			}
+		}
+		cur := &blocks[len(blocks)-1]
+		cur.body = append(cur.body, line)
+	}
+	return blocks
+}
+
+// collector walks the blocks of a markdown plan the way ParsePlan reads
+// them and keeps everything ParsePlan ignores.
+type collector struct {
+	x *extras
+	h *Headings
+
+	titleSeen bool
+	seen      map[Section]bool // known H2 sections already taken
+	region    string           // anchor of unmodeled text in the current H2
+	after     string           // anchor of the next unknown H2 section
+	beforeJob bool             // plan metadata comments are still read
+
+	job       int              // position of the current job, -1 before the first
+	inJob     bool             // inside a job heading
+	lastField Section          // last job section read, "" at the job heading
+	subs      map[Section]bool // job sections given as #### subsections
+	subsRead  map[Section]bool // job subsections already read
+
+	passLevel  int // deeper headings are copied verbatim to passAnchor
+	passAnchor string
+}
+
+// collectExtras returns the unmodeled text of a markdown plan.
+func collectExtras(content string) *extras {
+	c := &collector{
+		x:         &extras{blocks: make(map[string][]string), validators: make(map[int]string)},
+		h:         CurrentHeadings(),
+		seen:      make(map[Section]bool),
+		region:    anchorHead,
+		after:     anchorAfterTitle,
+		beforeJob: true,
+		job:       -1,
+	}
+	blocks := splitBlocks(content)
+	for i := 0; i < len(blocks); i++ {
+		i = c.visit(blocks, i)
+	}
+	return c.x
+}
+
+// visit handles blocks[i] and returns the index of the last block it used.
+func (c *collector) visit(blocks []block, i int) int {
+	b := blocks[i]
+	if c.passLevel > 0 && b.level > c.passLevel {
+		c.add(c.passAnchor, b.lines())
+		return i
+	}
+	c.passLevel = 0
+
+	switch {
+	case b.level == 0:
+		c.x.add(anchorHead, c.dropMetadata(b.body)...)
+	case b.level == 1 && !c.titleSeen:
+		c.titleSeen = true
+		c.region = anchorTitle
+		c.x.add(anchorTitle, c.dropMetadata(b.body)...)
+	case b.level <= 2:
+		c.section(b)
+	case b.level == 3 && jobTitlePattern.MatchString(b.title):
+		c.startJob(blocks, i)
+	case b.level == 3:
+		c.beforeJob = false
+		c.inJob = false
+		c.verbatim(b)
+	case c.inJob && b.level == 4:
+		return c.jobSubsection(blocks, i)
+	default:
+		c.verbatim(b)
+	}
+	return i
+}
+
+// verbatim copies a block and the blocks below it to the current anchor.
+func (c *collector) verbatim(b block) {
+	anchor := c.anchor()
+	c.passLevel, c.passAnchor = b.level, anchor
+	c.add(anchor, b.lines())
+}
+
+// add copies lines to anchor, without job separators inside the jobs.
+func (c *collector) add(anchor string, lines []string) {
+	if c.region == anchorJobs {
+		lines = dropSeparators(lines)
+	}
+	c.x.add(anchor, lines...)
+}
+
+// anchor returns where unmodeled text at the current position goes; "" in
+// verbatim sections, which are modeled as a whole.
+func (c *collector) anchor() string {
+	switch {
+	case c.inJob:
+		return jobAnchor(c.job, string(c.lastField))
+	case c.region == anchorJobs && c.job >= 0:
+		return jobAnchor(c.job, "end")
+	case c.region == anchorJobs:
+		return fieldAnchor(anchorJobs, "")
+	}
+	return c.region
+}
+
+// section handles a # or ## heading.
+func (c *collector) section(b block) {
+	c.inJob = false
+
+	var section Section
+	switch {
+	case b.level != 2:
+	case !c.seen[SectionOverview] && c.h.MatchTitle(SectionOverview, b.title):
+		section = SectionOverview
+	case c.h.MatchTitle(SectionJobs, b.title):
+		section = SectionJobs
+	default:
+		for _, s := range rawSections {
+			if !c.seen[s] && c.h.MatchTitle(s, b.title) {
+				section = s
+				break
+			}
+		}
+	}
+
+	if section == "" {
+		c.x.add(c.after, b.lines()...)
+		c.region = c.after
+		return
+	}
+
+	c.seen[section] = true
+	c.after = afterAnchor(section)
+	switch section {
+	case SectionOverview:
+		c.region = fieldAnchor(anchorOverview, "")
+		c.fields(anchorOverview, c.dropMetadata(b.body), overviewFields, nil)
+	case SectionJobs:
+		c.region = anchorJobs
+		c.x.add(fieldAnchor(anchorJobs, ""), dropSeparators(c.dropMetadata(b.body))...)
+	default:
+		// Free-form sections are modeled verbatim, nested headings included
+		c.region = ""
+	}
+}
+
+// startJob handles a "### Job N:" heading and the job body up to its first
+// subsection.
+func (c *collector) startJob(blocks []block, i int) {
+	c.beforeJob = false
+	c.job++
+	c.inJob = true
+	c.lastField = ""
+	c.subs = make(map[Section]bool)
+	c.subsRead = make(map[Section]bool)
+	for j := i + 1; j < len(blocks) && blocks[j].level > 3; j++ {
+		if s, ok := c.jobSection(blocks[j]); ok {
+			c.subs[s] = true
+		}
+	}
+
+	var body []string
+	for _, line := range blocks[i].body {
+		if !metadataLinePattern.MatchString(line) {
+			body = append(body, line)
+		}
+	}
+	c.fields(jobPart(c.job), body, jobFields, c.subs)
+}
+
+// jobSection reports which job section a #### heading holds.
+func (c *collector) jobSection(b block) (Section, bool) {
+	if b.level != 4 {
+		return "", false
+	}
+	for _, s := range jobFields {
+		if c.h.MatchTitle(s, b.title) {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// jobSubsection handles a #### heading of a job and returns the index of
+// the last block below it.
+func (c *collector) jobSubsection(blocks []block, i int) int {
+	s, ok := c.jobSection(blocks[i])
+	if !ok || c.subsRead[s] {
+		c.verbatim(blocks[i])
+		return i
+	}
+	c.subsRead[s] = true
+	c.lastField = s
+
+	// Deeper headings are part of the subsection body
+	body := blocks[i].body
+	for i+1 < len(blocks) && blocks[i+1].level > 4 {
+		i++
+		body = append(body, blocks[i].lines()...)
+	}
+	body = dropSeparators(body)
+
+	anchor := jobAnchor(c.job, string(s))
+	switch s {
+	case SectionTasks:
+		c.x.add(anchor, unreadTasks(body)...)
+	case SectionValidators:
+		c.validators(body)
+	case SectionDebugLogs:
+		c.x.add(anchor, unreadDebugLogs(body)...)
+	}
+	// Goal, prerequisites and completion status are modeled verbatim
+	return i
+}
+
+// field is a "**Field**:" line and the lines up to the next bold field.
+type field struct {
+	section Section // "" for text before the first field or after an unknown one
+	header  string
+	inline  string
+	lines   []string
+}
+
+// splitFields splits a body at bold field lines. Like the field extractors
+// of ParsePlan, it does not look at code fences.
+func (c *collector) splitFields(body []string, candidates []Section) []field {
+	fields := []field{{}}
+	for _, line := range body {
+		m := boldFieldPattern.FindStringSubmatch(line)
+		if m == nil {
+			cur := &fields[len(fields)-1]
+			cur.lines = append(cur.lines, line)
+			continue
+		}
+		f := field{header: line, inline: strings.TrimSpace(m[2])}
+		for _, s := range candidates {
+			if (s == SectionTasks && tasksFieldPattern.MatchString(line)) || c.h.MatchField(s, m[1]) {
+				f.section = s
+				break
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// fields keeps the parts of a field-format body that the field extractors
+// of ParsePlan do not read. Sections in skip are read from elsewhere.
+func (c *collector) fields(part string, body []string, candidates []Section, skip map[Section]bool) {
+	anchor := fieldAnchor(part, "")
+	read := make(map[Section]bool, len(skip))
+	for s := range skip {
+		read[s] = true
+	}
+
+	for _, f := range c.splitFields(body, candidates) {
+		if f.section == "" || read[f.section] {
+			if f.header != "" {
+				c.add(anchor, []string{f.header})
+			}
+			c.add(anchor, f.lines)
+			continue
+		}
+		read[f.section] = true
+		anchor = fieldAnchor(part, f.section)
+		if c.inJob {
+			c.lastField = f.section
+			// Text below these subsections would be read as part of them
+			if f.section == SectionGoal || f.section == SectionPrerequisites || f.section == SectionCompletionStatus {
+				anchor = fieldAnchor(part, "")
+			}
+		}
+		c.add(anchor, c.unreadField(f))
+	}
+}
+
+// unreadField returns the lines of a field that ParsePlan does not read.
+func (c *collector) unreadField(f field) []string {
+	switch f.section {
+	case SectionResponsibility, SectionGoal, SectionCompletionStatus:
+		// The value is the rest of the field line, or the next non-empty line
+		if f.inline != "" {
+			return f.lines
+		}
+		for i, line := range f.lines {
+			if strings.TrimSpace(line) != "" {
+				return f.lines[i+1:]
+			}
+		}
+		return nil
+
+	case SectionTasks:
+		// The task list ends at a "---" separator
+		lines, after := f.lines, []string(nil)
+		for i, line := range f.lines {
+			if strings.TrimSpace(line) == "---" {
+				lines, after = f.lines[:i], f.lines[i:]
+				break
+			}
+		}
+		rest := append(unreadTasks(lines), after...)
+		if f.inline != "" {
+			rest = append([]string{f.header}, rest...)
+		}
+		return rest
+
+	case SectionValidators:
+		c.validators(append([]string{f.inline}, f.lines...))
+		return nil
+
+	case SectionDebugLogs:
+		if f.inline != "" && !IsNone(f.inline) {
+			return append([]string{f.header}, f.lines...)
+		}
+		return unreadDebugLogs(f.lines)
+	}
+
+	// List fields: an inline value, or the list items below the field
+	if f.inline != "" && !IsNone(f.inline) && !strings.HasPrefix(f.inline, "-") && !strings.HasPrefix(f.inline, "*") {
+		return f.lines
+	}
+	for i, line := range f.lines {
+		if strings.TrimSpace(line) != "" && !listLinePattern.MatchString(line) {
+			return f.lines[i:]
+		}
+	}
+	return nil
+}
+
+// validators records the body of the current job's validators verbatim
+// unless it is a plain list that renders back identically.
+func (c *collector) validators(body []string) {
+	body = dropSeparators(body)
+	var lines []string
+	plain := true
+	for _, line := range body {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		lines = append(lines, line)
+		if m := validatorItemPattern.FindStringSubmatch(line); m == nil || debugEntryPattern.MatchString(strings.TrimSpace(m[1])) {
+			plain = false
+		}
+	}
+	if plain || len(lines) == 1 && !fencePattern.MatchString(lines[0]) {
+		return
+	}
+	c.x.validators[c.job] = strings.Trim(strings.Join(body, "\n"), "\n ")
+}
+
+// dropMetadata drops plan metadata comments, which are rendered from
+// Plan.Metadata, as long as ParsePlan still reads them.
+func (c *collector) dropMetadata(lines []string) []string {
+	if !c.beforeJob {
+		return lines
+	}
+	var kept []string
+	for _, line := range lines {
+		if !metadataLinePattern.MatchString(line) {
+			kept = append(kept, line)
+		}
+	}
+	return kept
+}
+
+// dropSeparators drops "---" job separators outside code fences; the
+// canonical layout writes its own.
+func dropSeparators(lines []string) []string {
+	var kept []string
+	inFence := false
+	for _, line := range lines {
+		if fencePattern.MatchString(line) {
+			inFence = !inFence
+		}
+		if inFence || strings.TrimSpace(line) != "---" {
+			kept = append(kept, line)
+		}
+	}
+	return kept
+}
+
+// unreadTasks returns the lines of a tasks body that parseTaskItems does
+// not read as tasks.
+func unreadTasks(lines []string) []string {
+	pattern := taskLinePattern
+	for _, line := range lines {
+		if indexedTaskLinePattern.MatchString(line) {
+			pattern = indexedTaskLinePattern
+			break
+		}
+	}
+	var rest []string
+	for _, line := range lines {
+		if !pattern.MatchString(line) {
+			rest = append(rest, line)
+		}
+	}
+	return rest
+}
+
+// unreadDebugLogs returns the lines of a debug log body that are neither
+// entries nor a "none" marker.
+func unreadDebugLogs(lines []string) []string {
+	var rest []string
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if debugLogLinePattern.MatchString(line) || IsNone(trimmed) || IsNone(strings.TrimPrefix(trimmed, "- ")) {
+			continue
+		}
+		rest = append(rest, line)
+	}
+	return rest
+}
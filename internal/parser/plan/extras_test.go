@@ -0,0 +1,207 @@
+package plan
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const annotatedPlan = `---
+owner: team-auth
+---
+
+# Plan: User Auth
+
+Created: 2026-02-27
+
+## 模块概述
+
+**模块职责**: 提供用户认证能力
+
+> 认证只覆盖密码登录。
+
+**依赖模块**: 无
+
+**被依赖模块**: 所有模块
+
+## 备注
+
+暂不支持 OAuth。
+
+## Jobs (Loop 块列表)
+
+这一节按执行顺序排列。
+
+---
+
+### Job 1: 登录
+
+开始前先读完 research。
+
+**目标**: 实现登录接口
+
+**备注**: 先写测试
+
+**前置条件**: 无
+
+**Tasks (Todo 列表)**:
+- [ ] Task 1: 定义接口
+- [x] Task 2: 实现校验
+顺序不限
+
+**验证器**:
+` + "```" + `
+当密码错误时，应该：
+- 返回 401
+- 记录失败次数
+` + "```" + `
+
+**调试日志**:
+- 如果验证失败，记录 debug 日志到此处
+
+#### 参考资料
+
+- RFC 6749
+
+---
+
+## 集成测试
+
+运行 go test ./...
+
+## 成功标准
+
+**预计完成时间**: 1 天
+`
+
+func TestReformatMarkdownKeepsUnmodeledText(t *testing.T) {
+	p, err := ParsePlan(annotatedPlan)
+	if err != nil {
+		t.Fatalf("ParsePlan() error = %v", err)
+	}
+	Canonicalize(p, "user_auth")
+
+	out := ReformatMarkdown(p, annotatedPlan, LanguageZh)
+	assertRoundTrip(t, p, out)
+	assertPreserved(t, annotatedPlan, out)
+
+	// Unmodeled text stays next to what it annotated; text below a goal
+	// moves above it, where it is not read as part of the goal
+	order := []string{
+		"owner: team-auth", "# Plan: User Auth", "Created: 2026-02-27",
+		"## 模块概述", "> 认证只覆盖密码登录。", "**依赖模块**: 无", "**被依赖模块**: 所有模块",
+		"## 备注", "## Jobs", "这一节按执行顺序排列。",
+		"### Job 1: 登录", "开始前先读完 research。", "**备注**: 先写测试", "#### 目标",
+		"#### Tasks", "顺序不限", "#### 验证器", "当密码错误时", "#### 调试日志", "如果验证失败",
+		"#### 参考资料", "## 集成测试", "## 成功标准",
+	}
+	last := -1
+	for _, s := range order {
+		i := strings.Index(out, s)
+		if i <= last {
+			t.Fatalf("%q missing or out of place in:\n%s", s, out)
+		}
+		last = i
+	}
+	if strings.Count(out, "#### 调试日志\n\n无") != 0 {
+		t.Errorf("Expected the debug log note to replace the none marker:\n%s", out)
+	}
+}
+
+func TestReformatMarkdownRealPlans(t *testing.T) {
+	files, err := filepath.Glob("../../../.morty/plan/*.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		if !IsPlanFile(filepath.Base(file)) {
+			continue
+		}
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			source := string(content)
+			p, err := ParsePlan(source)
+			if err != nil {
+				t.Fatalf("ParsePlan() error = %v", err)
+			}
+			Canonicalize(p, ModuleNameFromFile(file))
+
+			out := ReformatMarkdown(p, source, DetectLanguage(source))
+			assertRoundTrip(t, p, out)
+			assertPreserved(t, source, out)
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := &Plan{Name: "auth", Jobs: []Job{{Name: "a", Validators: []string{"x"}}}, RawContent: "a"}
+	b := &Plan{Name: "auth", Jobs: []Job{{Name: "a", Validators: []string{"y"}, Tasks: []TaskItem{}}}, RawContent: "b"}
+
+	diffs := Diff(a, b)
+	if len(diffs) != 1 || diffs[0] != "jobs[0].validators[0]" {
+		t.Errorf("Diff() = %v, want [jobs[0].validators[0]]", diffs)
+	}
+	if diffs := Diff(a, a); len(diffs) != 0 {
+		t.Errorf("Diff() of equal plans = %v", diffs)
+	}
+}
+
+// assertRoundTrip checks that out parses back to p and formats to itself.
+func assertRoundTrip(t *testing.T, p *Plan, out string) {
+	t.Helper()
+	reparsed, err := ParsePlan(out)
+	if err != nil {
+		t.Fatalf("ParsePlan(output) error = %v", err)
+	}
+	if diffs := Diff(p, reparsed); len(diffs) > 0 {
+		t.Errorf("Output parses to a different plan at %v:\n%s", diffs, out)
+	}
+	if again := ReformatMarkdown(reparsed, out, DetectLanguage(out)); again != out {
+		t.Errorf("Formatting is not idempotent:\n%s\n---- second pass ----\n%s", out, again)
+	}
+}
+
+// layoutLine matches text that the canonical layout rewrites: separators,
+// list markers, checkboxes and task numbers.
+var layoutLine = regexp.MustCompile(`^(?:[-*]\s*)?(?:\[[ xX]\]\s*)?(?:(?i)task\s*\d+[:：]\s*)?`)
+
+// assertPreserved checks that every line of source, the title included,
+// survives in out, except headings and field names of known sections,
+// which are rewritten.
+func assertPreserved(t *testing.T, source, out string) {
+	t.Helper()
+	h := CurrentHeadings()
+	known := func(title string) bool {
+		if jobTitlePattern.MatchString(title) {
+			return true
+		}
+		for _, s := range Sections() {
+			if h.MatchTitle(s, title) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, b := range splitBlocks(source) {
+		if b.level > 0 && !known(b.title) && !strings.Contains(out, b.heading) {
+			t.Errorf("Heading %q lost", b.heading)
+		}
+		for _, line := range b.body {
+			text := strings.TrimSpace(line)
+			if m := boldFieldPattern.FindStringSubmatch(text); m != nil && known(m[1]) {
+				text = strings.TrimSpace(m[2])
+			} else if !fencePattern.MatchString(text) {
+				text = strings.TrimSpace(layoutLine.ReplaceAllString(text, ""))
+			}
+			if text != "" && text != "---" && !strings.Contains(out, text) {
+				t.Errorf("Text %q lost", text)
+			}
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package plan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/morty/morty/internal/parser/yaml"
+)
+
+// Format identifies the on-disk representation of a Plan.
+type Format string
+
+const (
+	// FormatMarkdown is the canonical markdown layout (docs/plan_format_spec.md).
+	FormatMarkdown Format = "markdown"
+	// FormatJSON is the Plan struct encoded as JSON.
+	FormatJSON Format = "json"
+	// FormatYAML is the Plan struct encoded as YAML.
+	FormatYAML Format = "yaml"
+)
+
+// planExtensions lists the recognised plan file extensions in lookup order.
+var planExtensions = []string{".md", ".json", ".yaml", ".yml"}
+
+// ParseFormat converts a user-supplied format name (md, markdown, json,
+// yaml, yml) into a Format.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(name, ".")) {
+	case "md", "markdown":
+		return FormatMarkdown, nil
+	case "json":
+		return FormatJSON, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	}
+	return "", fmt.Errorf("unsupported plan format: %s (supported: md, json, yaml)", name)
+}
+
+// FormatFromPath returns the plan format implied by the file extension.
+// Unknown extensions are treated as markdown.
+func FormatFromPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	}
+	return FormatMarkdown
+}
+
+// Extension returns the preferred file extension for the format.
+func (f Format) Extension() string {
+	switch f {
+	case FormatJSON:
+		return ".json"
+	case FormatYAML:
+		return ".yaml"
+	}
+	return ".md"
+}
+
+// IsPlanFile reports whether name looks like a module plan file: a
+// recognised extension that is not the README index.
+func IsPlanFile(name string) bool {
+	if strings.HasPrefix(name, "README") {
+		return false
+	}
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, e := range planExtensions {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// ModuleNameFromFile returns the module name for a plan file name.
+func ModuleNameFromFile(name string) string {
+	base := filepath.Base(name)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// FindPlanFile returns the path of the plan file for module in planDir,
+// trying each recognised extension in turn. The returned error satisfies
+// os.IsNotExist when no plan file exists.
+func FindPlanFile(planDir, module string) (string, error) {
+	for _, ext := range planExtensions {
+		path := filepath.Join(planDir, module+ext)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", &os.PathError{Op: "open", Path: filepath.Join(planDir, module+".md"), Err: os.ErrNotExist}
+}
+
+// ParsePlanFile reads and parses a plan file, choosing the parser from the
+// file extension.
+func ParsePlanFile(path string) (*Plan, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePlanAs(string(content), FormatFromPath(path))
+}
+
+// ParsePlanAs parses plan content in the given format.
+func ParsePlanAs(content string, format Format) (*Plan, error) {
+	switch format {
+	case FormatJSON:
+		return ParsePlanJSON(content)
+	case FormatYAML:
+		return ParsePlanYAML(content)
+	}
+	return ParsePlan(content)
+}
+
+// ParsePlanJSON parses a plan encoded as JSON.
+func ParsePlanJSON(content string) (*Plan, error) {
+	p, err := decodeStructured([]byte(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JSON plan: %w", err)
+	}
+	p.normalize(content)
+	return p, nil
+}
+
+// ParsePlanYAML parses a plan encoded as YAML.
+func ParsePlanYAML(content string) (*Plan, error) {
+	value, err := yaml.Decode(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse YAML plan: %w", err)
+	}
+
+	// YAML maps onto the same json tags as the JSON format
+	intermediate, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse YAML plan: %w", err)
+	}
+
+	p, err := decodeStructured(intermediate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse YAML plan: %w", err)
+	}
+	p.normalize(content)
+	return p, nil
+}
+
+// decodeStructured decodes JSON into a Plan, rejecting unknown fields so
+// typos are reported instead of silently dropped.
+func decodeStructured(data []byte) (*Plan, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var p Plan
+	if err := dec.Decode(&p); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after plan object")
+	}
+	if err := p.Metadata.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid plan metadata: %w", err)
+	}
+	for _, job := range p.Jobs {
+		if err := job.Metadata.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid metadata of job %s: %w", job.Name, err)
+		}
+	}
+	return &p, nil
+}
+
+// normalize fills derived fields after decoding a structured plan.
+func (p *Plan) normalize(content string) {
+	p.RawContent = content
+	for i := range p.Jobs {
+		p.Jobs[i].IsCompleted = isJobMarkedCompleted(p.Jobs[i].CompletionStatus)
+	}
+}
+
+// Marshal encodes the plan in the given format.
+func Marshal(p *Plan, format Format) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(p); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case FormatYAML:
+		return yaml.Marshal(p)
+	case FormatMarkdown:
+		return []byte(RenderMarkdown(p)), nil
+	}
+	return nil, fmt.Errorf("unsupported plan format: %s", format)
+}
+
+// Diff lists the fields in which two plans differ, such as
+// "jobs[0].validators". RawContent is ignored, and empty lists and
+// strings equal missing ones.
+func Diff(a, b *Plan) []string {
+	var diffs []string
+	diffValues("", planValue(a), planValue(b), &diffs)
+	return diffs
+}
+
+// planValue returns the plan as generic JSON values.
+func planValue(p *Plan) interface{} {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil
+	}
+	return value
+}
+
+// diffValues appends the paths below path at which a and b differ.
+func diffValues(path string, a, b interface{}, diffs *[]string) {
+	if isEmptyValue(a) && isEmptyValue(b) {
+		return
+	}
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make(map[string]bool, len(av)+len(bv))
+		for k := range av {
+			keys[k] = true
+		}
+		for k := range bv {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+		for _, k := range sorted {
+			child := k
+			if path != "" {
+				child = path + "." + k
+			}
+			diffValues(child, av[k], bv[k], diffs)
+		}
+		return
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			break
+		}
+		for i := range av {
+			diffValues(fmt.Sprintf("%s[%d]", path, i), av[i], bv[i], diffs)
+		}
+		return
+	}
+	if !reflect.DeepEqual(a, b) {
+		*diffs = append(*diffs, path)
+	}
+}
+
+// isEmptyValue reports whether a JSON value is null, "" or an empty list
+// or object.
+func isEmptyValue(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
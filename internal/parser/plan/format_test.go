@@ -0,0 +1,303 @@
+package plan
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const canonicalPlan = `# Plan: user_auth
+
+## 模块概述
+
+**模块职责**: 提供用户认证能力
+
+**对应 Research**:
+- ` + "`.morty/research/auth.md`" + ` - 认证调研
+
+**现有实现参考**: 无
+
+**依赖模块**: storage, config
+
+**被依赖模块**: api
+
+## 接口定义
+
+` + "```go" + `
+type Authenticator interface {
+    Login(user, pass string) error
+}
+` + "```" + `
+
+## 数据模型
+
+无
+
+## Jobs
+
+---
+
+### Job 1: 登录实现
+
+#### 目标
+
+实现用户名密码登录
+
+#### 前置条件
+
+无
+
+#### Tasks
+
+- [x] Task 1: 定义接口
+- [ ] Task 2: 实现登录: 校验密码
+
+#### 验证器
+
+- 正确密码返回 nil
+- 错误密码返回 ErrInvalid
+
+#### 调试日志
+
+- debug1: 登录失败, 输入错误密码, 哈希不一致, 对比哈希, 修正盐值, 已修复
+
+#### 完成状态
+
+✅ 已完成
+
+---
+
+### Job 2: 会话管理
+
+#### 目标
+
+管理登录会话
+
+#### 前置条件
+
+- job_1
+- storage:job_2
+
+#### Tasks
+
+- [ ] Task 1: 创建会话
+
+#### 验证器
+
+- 会话可以过期
+
+#### 调试日志
+
+无
+
+#### 完成状态
+
+⏳ 待开始
+
+---
+
+## 集成测试
+
+**触发条件**: 模块内所有 Jobs 完成
+`
+
+// TestRenderMarkdownCanonical tests that a canonical plan renders unchanged.
+func TestRenderMarkdownCanonical(t *testing.T) {
+	p, err := ParsePlan(canonicalPlan)
+	if err != nil {
+		t.Fatalf("ParsePlan failed: %v", err)
+	}
+
+	if got := RenderMarkdown(p); got != canonicalPlan {
+		t.Errorf("Render mismatch.\n--- got ---\n%s\n--- want ---\n%s", got, canonicalPlan)
+	}
+}
+
+// TestParsePlanSections tests extraction of references and free-form sections.
+func TestParsePlanSections(t *testing.T) {
+	p, err := ParsePlan(canonicalPlan)
+	if err != nil {
+		t.Fatalf("ParsePlan failed: %v", err)
+	}
+
+	if len(p.References) != 0 {
+		t.Errorf("Expected no references for 无, got %v", p.References)
+	}
+	if !strings.Contains(p.Interfaces, "type Authenticator interface") {
+		t.Errorf("Expected interface section, got %q", p.Interfaces)
+	}
+	if p.DataModel != "" {
+		t.Errorf("Expected empty data model for 无, got %q", p.DataModel)
+	}
+	if p.IntegrationTest != "**触发条件**: 模块内所有 Jobs 完成" {
+		t.Errorf("Unexpected integration test section %q", p.IntegrationTest)
+	}
+}
+
+// TestFormatRoundTrip tests markdown -> JSON/YAML -> markdown conversion.
+func TestFormatRoundTrip(t *testing.T) {
+	original, err := ParsePlan(canonicalPlan)
+	if err != nil {
+		t.Fatalf("ParsePlan failed: %v", err)
+	}
+
+	for _, format := range []Format{FormatJSON, FormatYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			data, err := Marshal(original, format)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+			if strings.Contains(string(data), "raw_content") {
+				t.Error("Structured output must not include the raw content")
+			}
+
+			decoded, err := ParsePlanAs(string(data), format)
+			if err != nil {
+				t.Fatalf("ParsePlanAs failed: %v\n%s", err, data)
+			}
+			if decoded.RawContent != string(data) {
+				t.Error("Expected RawContent to hold the structured source")
+			}
+
+			decoded.RawContent = original.RawContent
+			if !reflect.DeepEqual(original, decoded) {
+				t.Errorf("Round trip mismatch:\n%+v\n%+v", original, decoded)
+			}
+
+			if got := RenderMarkdown(decoded); got != canonicalPlan {
+				t.Errorf("Markdown rendering differs after %s round trip:\n%s", format, got)
+			}
+		})
+	}
+}
+
+// TestParsePlanYAMLHandwritten tests a hand-written YAML plan.
+func TestParsePlanYAMLHandwritten(t *testing.T) {
+	content := `name: cache
+responsibility: 缓存层
+dependencies: [storage]
+jobs:
+  - name: 内存缓存
+    index: 1
+    goal: 实现 LRU
+    prerequisites: []
+    tasks:
+      - index: 1
+        description: "实现 Get: 命中返回"
+        completed: true
+    validators:
+      - 命中率统计正确
+    completion_status: ✅ 已完成
+`
+
+	p, err := ParsePlanYAML(content)
+	if err != nil {
+		t.Fatalf("ParsePlanYAML failed: %v", err)
+	}
+
+	if p.Name != "cache" || len(p.Jobs) != 1 {
+		t.Fatalf("Unexpected plan: %+v", p)
+	}
+	job := p.Jobs[0]
+	if !job.IsCompleted {
+		t.Error("Expected IsCompleted derived from completion_status")
+	}
+	if len(job.Tasks) != 1 || job.Tasks[0].Description != "实现 Get: 命中返回" || !job.Tasks[0].Completed {
+		t.Errorf("Unexpected tasks: %+v", job.Tasks)
+	}
+}
+
+// TestParsePlanStructuredErrors tests that malformed structured plans fail.
+func TestParsePlanStructuredErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		format  Format
+	}{
+		{"invalid json", `{"name": `, FormatJSON},
+		{"unknown json field", `{"name": "a", "jobz": []}`, FormatJSON},
+		{"wrong json type", `{"name": "a", "jobs": "none"}`, FormatJSON},
+		{"invalid yaml", "name: a\n\tjobs: []", FormatYAML},
+		{"unknown yaml field", "name: a\njobz: []", FormatYAML},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParsePlanAs(tt.content, tt.format); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+// TestFormatHelpers tests format detection and plan file lookup.
+func TestFormatHelpers(t *testing.T) {
+	if FormatFromPath("a/b.yml") != FormatYAML || FormatFromPath("b.JSON") != FormatJSON || FormatFromPath("b.md") != FormatMarkdown {
+		t.Error("FormatFromPath returned wrong format")
+	}
+
+	if f, err := ParseFormat("md"); err != nil || f != FormatMarkdown {
+		t.Errorf("ParseFormat(md) = %v, %v", f, err)
+	}
+	if _, err := ParseFormat("toml"); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+
+	for name, want := range map[string]bool{
+		"auth.md": true, "auth.yaml": true, "auth.json": true,
+		"README.md": false, "notes.txt": false,
+	} {
+		if got := IsPlanFile(name); got != want {
+			t.Errorf("IsPlanFile(%q) = %v, want %v", name, got, want)
+		}
+	}
+
+	if got := ModuleNameFromFile("/tmp/plan/user_auth.yaml"); got != "user_auth" {
+		t.Errorf("ModuleNameFromFile() = %q", got)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "cache.yml"), []byte("name: cache\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := FindPlanFile(dir, "cache")
+	if err != nil || filepath.Base(path) != "cache.yml" {
+		t.Errorf("FindPlanFile() = %q, %v", path, err)
+	}
+	if _, err := FindPlanFile(dir, "missing"); !os.IsNotExist(err) {
+		t.Errorf("Expected not-exist error, got %v", err)
+	}
+
+	p, err := ParsePlanFile(path)
+	if err != nil || p.Name != "cache" {
+		t.Errorf("ParsePlanFile() = %+v, %v", p, err)
+	}
+}
+
+// TestRenderMarkdownKeepsMissingValues tests that rendering does not invent
+// required values, so validation still reports them.
+func TestRenderMarkdownKeepsMissingValues(t *testing.T) {
+	p := &Plan{
+		Name: "draft",
+		Jobs: []Job{{Name: "first", Tasks: []TaskItem{{Description: "do it"}}}},
+	}
+
+	rendered, err := ParsePlan(RenderMarkdown(p))
+	if err != nil {
+		t.Fatalf("ParsePlan failed: %v", err)
+	}
+
+	if rendered.Responsibility != "" {
+		t.Errorf("Expected empty responsibility, got %q", rendered.Responsibility)
+	}
+	job := rendered.Jobs[0]
+	if job.Index != 1 || job.Tasks[0].Index != 1 {
+		t.Errorf("Expected missing indexes to default to position, got job %d task %d", job.Index, job.Tasks[0].Index)
+	}
+	if job.Goal != "" || job.CompletionStatus != "" || len(job.Validators) != 0 {
+		t.Errorf("Expected missing values to stay empty, got %+v", job)
+	}
+}
@@ -0,0 +1,250 @@
+package plan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Section identifies a plan section or field independently of the
+// language its heading is written in.
+type Section string
+
+const (
+	SectionOverview         Section = "overview"
+	SectionResponsibility   Section = "responsibility"
+	SectionResearch         Section = "research"
+	SectionReferences       Section = "references"
+	SectionDependencies     Section = "dependencies"
+	SectionDependents       Section = "dependents"
+	SectionInterfaces       Section = "interfaces"
+	SectionDataModel        Section = "data_model"
+	SectionJobs             Section = "jobs"
+	SectionGoal             Section = "goal"
+	SectionPrerequisites    Section = "prerequisites"
+	SectionTasks            Section = "tasks"
+	SectionValidators       Section = "validators"
+	SectionDebugLogs        Section = "debug_logs"
+	SectionCompletionStatus Section = "completion_status"
+	SectionIntegrationTest  Section = "integration_test"
+
+	// Sections of the plan README and its module table column
+	SectionPlanIndex       Section = "plan_index"
+	SectionModuleList      Section = "module_list"
+	SectionModuleName      Section = "module_name"
+	SectionDependencyGraph Section = "dependency_graph"
+	SectionExecutionOrder  Section = "execution_order"
+	SectionStatistics      Section = "statistics"
+)
+
+// Language is the language plan headings are written in.
+type Language string
+
+const (
+	LanguageZh Language = "zh"
+	LanguageEn Language = "en"
+)
+
+// ParseLanguage parses a language name such as "zh" or "en".
+func ParseLanguage(name string) (Language, error) {
+	switch Language(strings.ToLower(strings.TrimSpace(name))) {
+	case "", LanguageZh:
+		return LanguageZh, nil
+	case LanguageEn:
+		return LanguageEn, nil
+	}
+	return "", fmt.Errorf("unsupported plan language: %s (expected zh or en)", name)
+}
+
+// titles holds the canonical heading of each section per language.
+var titles = map[Section]map[Language]string{
+	SectionOverview:         {LanguageZh: "模块概述", LanguageEn: "Overview"},
+	SectionResponsibility:   {LanguageZh: "模块职责", LanguageEn: "Responsibility"},
+	SectionResearch:         {LanguageZh: "对应 Research", LanguageEn: "Research"},
+	SectionReferences:       {LanguageZh: "现有实现参考", LanguageEn: "References"},
+	SectionDependencies:     {LanguageZh: "依赖模块", LanguageEn: "Dependencies"},
+	SectionDependents:       {LanguageZh: "被依赖模块", LanguageEn: "Dependents"},
+	SectionInterfaces:       {LanguageZh: "接口定义", LanguageEn: "Interfaces"},
+	SectionDataModel:        {LanguageZh: "数据模型", LanguageEn: "Data Model"},
+	SectionJobs:             {LanguageZh: "Jobs", LanguageEn: "Jobs"},
+	SectionGoal:             {LanguageZh: "目标", LanguageEn: "Goal"},
+	SectionPrerequisites:    {LanguageZh: "前置条件", LanguageEn: "Prerequisites"},
+	SectionTasks:            {LanguageZh: "Tasks", LanguageEn: "Tasks"},
+	SectionValidators:       {LanguageZh: "验证器", LanguageEn: "Validators"},
+	SectionDebugLogs:        {LanguageZh: "调试日志", LanguageEn: "Debug Log"},
+	SectionCompletionStatus: {LanguageZh: "完成状态", LanguageEn: "Completion Status"},
+	SectionIntegrationTest:  {LanguageZh: "集成测试", LanguageEn: "Integration Test"},
+	SectionPlanIndex:        {LanguageZh: "Plan 索引", LanguageEn: "Plan Index"},
+	SectionModuleList:       {LanguageZh: "模块列表", LanguageEn: "Modules"},
+	SectionModuleName:       {LanguageZh: "模块名称", LanguageEn: "Module"},
+	SectionDependencyGraph:  {LanguageZh: "依赖关系图", LanguageEn: "Dependency Graph"},
+	SectionExecutionOrder:   {LanguageZh: "执行顺序", LanguageEn: "Execution Order"},
+	SectionStatistics:       {LanguageZh: "统计信息", LanguageEn: "Statistics"},
+}
+
+// legacyAliases are additional built-in spellings accepted by the parser.
+var legacyAliases = map[Section][]string{
+	SectionOverview:   {"Module Overview"},
+	SectionInterfaces: {"Interface"},
+	SectionTasks:      {"Todo 列表", "任务列表"},
+	SectionValidators: {"Validator"},
+	SectionDebugLogs:  {"Debug Logs", "Debug"},
+}
+
+// Title returns the canonical heading of a section in the given language.
+func Title(section Section, lang Language) string {
+	if t, ok := titles[section][lang]; ok {
+		return t
+	}
+	return titles[section][LanguageZh]
+}
+
+// NoneMarker returns the explicit "nothing here" marker for a language.
+func NoneMarker(lang Language) string {
+	if lang == LanguageEn {
+		return "None"
+	}
+	return noneMarker
+}
+
+// IsNone reports whether s is an explicit "nothing here" marker ("无" or "None").
+func IsNone(s string) bool {
+	s = strings.TrimSpace(s)
+	return s == noneMarker || strings.EqualFold(s, "none")
+}
+
+// ParseSection parses a section key such as "debug_logs".
+func ParseSection(name string) (Section, error) {
+	section := Section(strings.ToLower(strings.TrimSpace(name)))
+	if _, ok := titles[section]; !ok {
+		return "", fmt.Errorf("unknown plan section: %s", name)
+	}
+	return section, nil
+}
+
+// Sections returns all section keys in sorted order.
+func Sections() []Section {
+	sections := make([]Section, 0, len(titles))
+	for s := range titles {
+		sections = append(sections, s)
+	}
+	sort.Slice(sections, func(i, j int) bool { return sections[i] < sections[j] })
+	return sections
+}
+
+// Headings is the set of heading aliases recognised for each section.
+// Section headings (##, ####) match when they contain an alias; bold
+// fields (**Field**:) must match an alias exactly. Both are case-insensitive.
+type Headings struct {
+	aliases map[Section][]string
+}
+
+// NewHeadings returns the built-in Chinese and English headings.
+func NewHeadings() *Headings {
+	h := &Headings{aliases: make(map[Section][]string, len(titles))}
+	for section, byLang := range titles {
+		h.aliases[section] = appendUnique(nil, byLang[LanguageZh], byLang[LanguageEn])
+		h.aliases[section] = appendUnique(h.aliases[section], legacyAliases[section]...)
+	}
+	return h
+}
+
+// AddAliases registers additional headings for a section.
+func (h *Headings) AddAliases(section Section, aliases ...string) error {
+	if _, ok := titles[section]; !ok {
+		return fmt.Errorf("unknown plan section: %s", section)
+	}
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		if alias == "" {
+			return fmt.Errorf("empty heading alias for section %s", section)
+		}
+		h.aliases[section] = appendUnique(h.aliases[section], alias)
+	}
+	return nil
+}
+
+// Aliases returns every heading accepted for a section.
+func (h *Headings) Aliases(section Section) []string {
+	return h.aliases[section]
+}
+
+// MatchTitle reports whether a markdown heading title belongs to section.
+func (h *Headings) MatchTitle(section Section, title string) bool {
+	return isMatchingTitle(title, h.aliases[section]...)
+}
+
+// MatchField reports whether a bold field name belongs to section.
+func (h *Headings) MatchField(section Section, name string) bool {
+	name = strings.TrimSpace(name)
+	for _, alias := range h.aliases[section] {
+		if strings.EqualFold(name, alias) {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectLanguage guesses the language of a markdown plan from its headings.
+// Plans without recognisable headings are reported as Chinese.
+func DetectLanguage(content string) Language {
+	zh, en := 0, 0
+	for _, line := range strings.Split(content, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			continue
+		}
+		title := strings.ToLower(strings.TrimSpace(strings.TrimLeft(line, "#")))
+		for _, byLang := range titles {
+			if byLang[LanguageZh] == byLang[LanguageEn] {
+				continue
+			}
+			if title == strings.ToLower(byLang[LanguageZh]) {
+				zh++
+			} else if title == strings.ToLower(byLang[LanguageEn]) {
+				en++
+			}
+		}
+	}
+	if en > zh {
+		return LanguageEn
+	}
+	return LanguageZh
+}
+
+var (
+	headingsMu sync.RWMutex
+	headings   = NewHeadings()
+)
+
+// SetHeadings replaces the headings used by ParsePlan, usually once at
+// startup after loading the configured aliases.
+func SetHeadings(h *Headings) {
+	headingsMu.Lock()
+	defer headingsMu.Unlock()
+	headings = h
+}
+
+// CurrentHeadings returns the headings used by ParsePlan.
+func CurrentHeadings() *Headings {
+	headingsMu.RLock()
+	defer headingsMu.RUnlock()
+	return headings
+}
+
+// appendUnique appends items that are not yet in list (case-insensitive).
+func appendUnique(list []string, items ...string) []string {
+	for _, item := range items {
+		found := false
+		for _, existing := range list {
+			if strings.EqualFold(existing, item) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,230 @@
+package plan
+
+import (
+	"reflect"
+	"testing"
+)
+
+const englishPlan = `# Plan: user_auth
+
+## Overview
+
+**Responsibility**: 提供用户认证能力
+
+**Research**:
+- ` + "`.morty/research/auth.md`" + ` - 认证调研
+
+**References**: None
+
+**Dependencies**: storage, config
+
+**Dependents**: api
+
+## Interfaces
+
+` + "```go" + `
+type Authenticator interface {
+    Login(user, pass string) error
+}
+` + "```" + `
+
+## Data Model
+
+None
+
+## Jobs
+
+---
+
+### Job 1: 登录实现
+
+#### Goal
+
+实现用户名密码登录
+
+#### Prerequisites
+
+None
+
+#### Tasks
+
+- [x] Task 1: 定义接口
+- [ ] Task 2: 实现登录: 校验密码
+
+#### Validators
+
+- 正确密码返回 nil
+- 错误密码返回 ErrInvalid
+
+#### Debug Log
+
+- debug1: 登录失败, 输入错误密码, 哈希不一致, 对比哈希, 修正盐值, 已修复
+
+#### Completion Status
+
+✅ 已完成
+
+---
+
+### Job 2: 会话管理
+
+#### Goal
+
+管理登录会话
+
+#### Prerequisites
+
+- job_1
+- storage:job_2
+
+#### Tasks
+
+- [ ] Task 1: 创建会话
+
+#### Validators
+
+- 会话可以过期
+
+#### Debug Log
+
+None
+
+#### Completion Status
+
+⏳ 待开始
+
+---
+
+## Integration Test
+
+**触发条件**: 模块内所有 Jobs 完成
+`
+
+// TestParsePlanEnglishHeadings tests that an English plan parses like its
+// Chinese equivalent.
+func TestParsePlanEnglishHeadings(t *testing.T) {
+	zh, err := ParsePlan(canonicalPlan)
+	if err != nil {
+		t.Fatalf("ParsePlan(zh) failed: %v", err)
+	}
+	en, err := ParsePlan(englishPlan)
+	if err != nil {
+		t.Fatalf("ParsePlan(en) failed: %v", err)
+	}
+
+	en.RawContent = zh.RawContent
+	if !reflect.DeepEqual(zh, en) {
+		t.Errorf("English plan parsed differently:\n%+v\n%+v", zh, en)
+	}
+}
+
+// TestRenderMarkdownIn tests rendering with English headings.
+func TestRenderMarkdownIn(t *testing.T) {
+	p, err := ParsePlan(canonicalPlan)
+	if err != nil {
+		t.Fatalf("ParsePlan failed: %v", err)
+	}
+
+	if got := RenderMarkdownIn(p, LanguageEn); got != englishPlan {
+		t.Errorf("Render mismatch.\n--- got ---\n%s\n--- want ---\n%s", got, englishPlan)
+	}
+	if got := RenderMarkdownIn(p, LanguageZh); got != canonicalPlan {
+		t.Error("Expected zh rendering to match RenderMarkdown")
+	}
+}
+
+// TestHeadingAliases tests user-configured heading aliases.
+func TestHeadingAliases(t *testing.T) {
+	content := `# Plan: cache
+
+## Summary
+
+**Owner of**: 缓存层
+
+**Needs**: storage
+
+## Jobs
+
+### Job 1: LRU
+
+#### Objective
+
+实现 LRU
+
+#### Checks
+
+- 命中率统计正确
+
+#### Status
+
+✅ Completed
+`
+
+	h := NewHeadings()
+	for section, aliases := range map[Section][]string{
+		SectionOverview:         {"Summary"},
+		SectionResponsibility:   {"Owner of"},
+		SectionDependencies:     {"Needs"},
+		SectionGoal:             {"Objective"},
+		SectionValidators:       {"Checks"},
+		SectionCompletionStatus: {"Status"},
+	} {
+		if err := h.AddAliases(section, aliases...); err != nil {
+			t.Fatalf("AddAliases(%s) failed: %v", section, err)
+		}
+	}
+	SetHeadings(h)
+	defer SetHeadings(NewHeadings())
+
+	p, err := ParsePlan(content)
+	if err != nil {
+		t.Fatalf("ParsePlan failed: %v", err)
+	}
+
+	if p.Responsibility != "缓存层" || !reflect.DeepEqual(p.Dependencies, []string{"storage"}) {
+		t.Errorf("Unexpected overview: %q %v", p.Responsibility, p.Dependencies)
+	}
+	job := p.Jobs[0]
+	if job.Goal != "实现 LRU" || len(job.Validators) != 1 || !job.IsCompleted {
+		t.Errorf("Unexpected job: %+v", job)
+	}
+
+	if err := h.AddAliases("unknown", "X"); err == nil {
+		t.Error("Expected error for unknown section")
+	}
+	if err := h.AddAliases(SectionGoal, " "); err == nil {
+		t.Error("Expected error for empty alias")
+	}
+}
+
+// TestHeadingHelpers tests section and language parsing and detection.
+func TestHeadingHelpers(t *testing.T) {
+	if s, err := ParseSection("Debug_Logs"); err != nil || s != SectionDebugLogs {
+		t.Errorf("ParseSection() = %v, %v", s, err)
+	}
+	if _, err := ParseSection("appendix"); err == nil {
+		t.Error("Expected error for unknown section")
+	}
+
+	if l, err := ParseLanguage("EN"); err != nil || l != LanguageEn {
+		t.Errorf("ParseLanguage() = %v, %v", l, err)
+	}
+	if _, err := ParseLanguage("fr"); err == nil {
+		t.Error("Expected error for unsupported language")
+	}
+
+	if got := DetectLanguage(englishPlan); got != LanguageEn {
+		t.Errorf("DetectLanguage(en) = %v", got)
+	}
+	if got := DetectLanguage(canonicalPlan); got != LanguageZh {
+		t.Errorf("DetectLanguage(zh) = %v", got)
+	}
+
+	h := NewHeadings()
+	if !h.MatchField(SectionDebugLogs, "debug log") || h.MatchField(SectionDebugLogs, "Debug Logging") {
+		t.Error("MatchField should compare whole field names case-insensitively")
+	}
+	if !h.MatchTitle(SectionOverview, "Module Overview") {
+		t.Error("MatchTitle should match titles containing an alias")
+	}
+}
@@ -0,0 +1,275 @@
+package plan
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/morty/morty/internal/parser/markdown"
+)
+
+// Metadata holds the execution settings of a plan or a job: which model
+// runs it, for how long, and with which permissions. In markdown plans it
+// is written as a comment,
+//
+//	<!-- morty: model=haiku, timeout=45m, permission_mode=acceptEdits, tools=Read,Edit,Bash(go test:*) -->
+//
+// below the "# Plan:" title (or as frontmatter) for the whole plan, and
+// below a "### Job N:" heading for a single job.
+type Metadata struct {
+	Model           string   `json:"model,omitempty"`            // AI model of the run
+	Timeout         string   `json:"timeout,omitempty"`          // e.g. "45m"; empty means no timeout
+	PermissionMode  string   `json:"permission_mode,omitempty"`  // e.g. "acceptEdits"
+	Tools           []string `json:"tools,omitempty"`            // allowed tools, e.g. "Bash(go test:*)"
+	DisallowedTools []string `json:"disallowed_tools,omitempty"` // tools the run may not use
+	Args            []string `json:"args,omitempty"`             // extra CLI arguments
+}
+
+// metadataKeys lists the keys a metadata block may set.
+var metadataKeys = []string{"model", "timeout", "permission_mode", "tools", "disallowed_tools", "args"}
+
+// metadataCommentPattern matches a <!-- morty: ... --> comment.
+var metadataCommentPattern = regexp.MustCompile(`<!--\s*morty:\s*(.*?)\s*-->`)
+
+// metadataKeyPattern matches the "key=" that starts a metadata entry.
+var metadataKeyPattern = regexp.MustCompile(`^\s*([a-z_]+)\s*=`)
+
+// IsZero reports whether no setting is given.
+func (m *Metadata) IsZero() bool {
+	return m == nil || (m.Model == "" && m.Timeout == "" && m.PermissionMode == "" &&
+		len(m.Tools) == 0 && len(m.DisallowedTools) == 0 && len(m.Args) == 0)
+}
+
+// Merge returns m with the settings of override applied on top; lists in
+// override replace those of m.
+func (m *Metadata) Merge(override *Metadata) *Metadata {
+	result := &Metadata{}
+	for _, src := range []*Metadata{m, override} {
+		if src == nil {
+			continue
+		}
+		if src.Model != "" {
+			result.Model = src.Model
+		}
+		if src.Timeout != "" {
+			result.Timeout = src.Timeout
+		}
+		if src.PermissionMode != "" {
+			result.PermissionMode = src.PermissionMode
+		}
+		if len(src.Tools) > 0 {
+			result.Tools = src.Tools
+		}
+		if len(src.DisallowedTools) > 0 {
+			result.DisallowedTools = src.DisallowedTools
+		}
+		if len(src.Args) > 0 {
+			result.Args = src.Args
+		}
+	}
+	return result
+}
+
+// TimeoutDuration returns the timeout as a duration, 0 if none is set.
+func (m *Metadata) TimeoutDuration() (time.Duration, error) {
+	if m == nil || m.Timeout == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(m.Timeout)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q", m.Timeout)
+	}
+	return d, nil
+}
+
+// Validate reports unusable settings.
+func (m *Metadata) Validate() error {
+	_, err := m.TimeoutDuration()
+	return err
+}
+
+// JobMetadata returns the settings a job runs with: the plan's settings
+// with the job's own on top.
+func (p *Plan) JobMetadata(jobName string) *Metadata {
+	for _, job := range p.Jobs {
+		if job.Name == jobName {
+			return p.Metadata.Merge(job.Metadata)
+		}
+	}
+	return p.Metadata.Merge(nil)
+}
+
+// ParseMetadata parses the body of a metadata comment, "key=value" entries
+// separated by commas. Commas outside parentheses also separate the items
+// of tools, disallowed_tools and args, so an entry without "=" continues the
+// list of the entry before it.
+func ParseMetadata(body string) (*Metadata, error) {
+	values := make(map[string][]string)
+	var order []string
+	current := ""
+	for _, item := range splitMetadata(body) {
+		if m := metadataKeyPattern.FindStringSubmatch(item); m != nil {
+			current = m[1]
+			if _, seen := values[current]; !seen {
+				order = append(order, current)
+			}
+			values[current] = nil
+			item = item[len(m[0]):]
+		} else if current == "" {
+			return nil, fmt.Errorf("metadata entry %q is not key=value", strings.TrimSpace(item))
+		}
+		if item = strings.TrimSpace(item); item != "" {
+			values[current] = append(values[current], item)
+		}
+	}
+
+	meta := &Metadata{}
+	for _, key := range order {
+		if err := meta.set(key, values[key]); err != nil {
+			return nil, err
+		}
+	}
+	return meta, meta.Validate()
+}
+
+// set assigns the values of one metadata key.
+func (m *Metadata) set(key string, values []string) error {
+	single := strings.Join(values, ",")
+	switch key {
+	case "model":
+		m.Model = single
+	case "timeout":
+		m.Timeout = single
+	case "permission_mode":
+		m.PermissionMode = single
+	case "tools":
+		m.Tools = values
+	case "disallowed_tools":
+		m.DisallowedTools = values
+	case "args":
+		m.Args = nil
+		for _, value := range values {
+			m.Args = append(m.Args, strings.Fields(value)...)
+		}
+	default:
+		return fmt.Errorf("unknown metadata key %q (known: %s)", key, strings.Join(metadataKeys, ", "))
+	}
+	return nil
+}
+
+// splitMetadata splits s at the commas outside parentheses.
+func splitMetadata(s string) []string {
+	var items []string
+	depth, start := 0, 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				items = append(items, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(items, s[start:])
+}
+
+// metadataFromComments parses the metadata comments found in content; later
+// comments override earlier ones. It returns nil when there is none.
+func metadataFromComments(content string) (*Metadata, error) {
+	var meta *Metadata
+	for _, m := range metadataCommentPattern.FindAllStringSubmatch(content, -1) {
+		parsed, err := ParseMetadata(m[1])
+		if err != nil {
+			return nil, err
+		}
+		meta = meta.Merge(parsed)
+	}
+	return meta, nil
+}
+
+// extractPlanMetadata reads the plan-level settings: the frontmatter, then
+// the metadata comments before the first job heading.
+func extractPlanMetadata(content string) (*Metadata, error) {
+	var meta *Metadata
+	if fields, err := markdown.ExtractMetadata(content); err == nil && len(fields) > 0 {
+		front := &Metadata{}
+		for _, key := range metadataKeys {
+			if value, ok := fields[key]; ok {
+				if err := front.set(key, splitMetadata(value)); err != nil {
+					return nil, err
+				}
+			}
+		}
+		meta = front
+	}
+
+	var head []string
+	inFence := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+		}
+		if !inFence && strings.HasPrefix(trimmed, "### ") {
+			break
+		}
+		if !inFence {
+			head = append(head, line)
+		}
+	}
+	comments, err := metadataFromComments(strings.Join(head, "\n"))
+	if err != nil {
+		return nil, err
+	}
+	if meta == nil {
+		meta = comments
+	} else if comments != nil {
+		meta = meta.Merge(comments)
+	}
+	if meta.IsZero() {
+		return nil, nil
+	}
+	return meta, meta.Validate()
+}
+
+// extractJobMetadata reads the metadata comments of a job section that come
+// before its first subsection.
+func extractJobMetadata(content string) (*Metadata, error) {
+	var head []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "####") {
+			break
+		}
+		head = append(head, line)
+	}
+	return metadataFromComments(strings.Join(head, "\n"))
+}
+
+// metadataComment renders m as a metadata comment, or "" when m is empty.
+func metadataComment(m *Metadata) string {
+	if m.IsZero() {
+		return ""
+	}
+	var entries []string
+	add := func(key string, values ...string) {
+		if len(values) > 0 && values[0] != "" {
+			entries = append(entries, key+"="+strings.Join(values, ","))
+		}
+	}
+	add("model", m.Model)
+	add("timeout", m.Timeout)
+	add("permission_mode", m.PermissionMode)
+	add("tools", m.Tools...)
+	add("disallowed_tools", m.DisallowedTools...)
+	if len(m.Args) > 0 {
+		add("args", strings.Join(m.Args, " "))
+	}
+	return "<!-- morty: " + strings.Join(entries, ", ") + " -->"
+}
@@ -0,0 +1,118 @@
+package plan
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const metadataPlan = `---
+model: sonnet
+timeout: 1h
+---
+# Plan: auth
+
+<!-- morty: permission_mode=acceptEdits -->
+
+## 模块概述
+
+**模块职责**: 认证
+
+## Jobs (拆分的工作)
+
+---
+
+### Job 1: 文档
+
+<!-- morty: model=haiku, timeout=45m, tools=Read,Edit,Bash(go test:*, go vet:*) -->
+
+#### 目标
+
+写文档
+
+#### Tasks (Todo 列表)
+
+- [ ] Task 1: 写 README
+
+---
+
+### Job 2: 实现
+
+#### 目标
+
+实现登录
+
+#### Tasks (Todo 列表)
+
+- [ ] Task 1: 实现 <!-- morty: model=opus -->
+`
+
+func TestParseMetadata(t *testing.T) {
+	meta, err := ParseMetadata("model=haiku, tools=Read, Edit,Bash(go test:*, go vet:*), args=--max-turns 20")
+	if err != nil {
+		t.Fatalf("ParseMetadata() error: %v", err)
+	}
+	want := &Metadata{
+		Model: "haiku",
+		Tools: []string{"Read", "Edit", "Bash(go test:*, go vet:*)"},
+		Args:  []string{"--max-turns", "20"},
+	}
+	if !reflect.DeepEqual(meta, want) {
+		t.Errorf("ParseMetadata() = %+v, want %+v", meta, want)
+	}
+
+	for body, wantErr := range map[string]string{
+		"modle=haiku":  "unknown metadata key",
+		"haiku":        "is not key=value",
+		"timeout=soon": "invalid timeout",
+	} {
+		if _, err := ParseMetadata(body); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("ParseMetadata(%q) error = %v, want %q", body, err, wantErr)
+		}
+	}
+}
+
+func TestParsePlan_Metadata(t *testing.T) {
+	p, err := ParsePlan(metadataPlan)
+	if err != nil {
+		t.Fatalf("ParsePlan() error: %v", err)
+	}
+	if want := (&Metadata{Model: "sonnet", Timeout: "1h", PermissionMode: "acceptEdits"}); !reflect.DeepEqual(p.Metadata, want) {
+		t.Errorf("Plan.Metadata = %+v, want %+v", p.Metadata, want)
+	}
+	if p.Jobs[1].Metadata != nil {
+		t.Errorf("Comments below a subsection should be ignored, got %+v", p.Jobs[1].Metadata)
+	}
+
+	docs := p.JobMetadata("文档")
+	want := &Metadata{Model: "haiku", Timeout: "45m", PermissionMode: "acceptEdits", Tools: []string{"Read", "Edit", "Bash(go test:*, go vet:*)"}}
+	if !reflect.DeepEqual(docs, want) {
+		t.Errorf("JobMetadata(文档) = %+v, want %+v", docs, want)
+	}
+	if timeout, _ := docs.TimeoutDuration(); timeout != 45*time.Minute {
+		t.Errorf("TimeoutDuration() = %v", timeout)
+	}
+	if impl := p.JobMetadata("实现"); impl.Model != "sonnet" || impl.Timeout != "1h" {
+		t.Errorf("JobMetadata(实现) = %+v", impl)
+	}
+
+	// The markdown and JSON formats carry the metadata through a round trip
+	rendered, err := ParsePlan(RenderMarkdown(p))
+	if err != nil || !reflect.DeepEqual(rendered.Metadata, &Metadata{Model: "sonnet", Timeout: "1h", PermissionMode: "acceptEdits"}) ||
+		!reflect.DeepEqual(rendered.Jobs[0].Metadata, p.Jobs[0].Metadata) {
+		t.Errorf("Markdown round trip lost the metadata: %+v, %v", rendered, err)
+	}
+	data, _ := Marshal(p, FormatJSON)
+	decoded, err := ParsePlanJSON(string(data))
+	if err != nil || !reflect.DeepEqual(decoded.Jobs[0].Metadata, p.Jobs[0].Metadata) {
+		t.Errorf("JSON round trip lost the metadata: %+v, %v", decoded, err)
+	}
+	if errs, _ := Schema().ValidateJSON(data); len(errs) > 0 {
+		t.Errorf("Schema errors: %v", errs)
+	}
+
+	if _, err := ParsePlan(strings.Replace(metadataPlan, "timeout=45m", "timeout=never", 1)); err == nil {
+		t.Error("Expected an error for an invalid job timeout")
+	}
+}
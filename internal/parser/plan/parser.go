@@ -3,7 +3,6 @@ package plan
 
 import (
 	"fmt"
-	"os"
 	"regexp"
 	"strings"
 
@@ -12,13 +11,19 @@ import (
 
 // Plan represents a parsed Plan document for a module.
 type Plan struct {
+	Schema         string       `json:"$schema,omitempty"` // JSON Schema reference of structured plans
 	Name           string       `json:"name"`            // Module name
 	Responsibility string       `json:"responsibility"`  // Module responsibilities
 	Research       []string     `json:"research"`        // Related research documents
+	References     []string     `json:"references,omitempty"` // Existing implementation references
 	Dependencies   []string     `json:"dependencies"`    // Modules this module depends on
 	Dependents     []string     `json:"dependents"`      // Modules that depend on this module
+	Interfaces     string       `json:"interfaces,omitempty"`       // Interface definition section
+	DataModel      string       `json:"data_model,omitempty"`       // Data model section
 	Jobs           []Job        `json:"jobs"`            // List of jobs in the plan
-	RawContent     string       `json:"raw_content"`     // Original markdown content
+	IntegrationTest string      `json:"integration_test,omitempty"` // Integration test section
+	Metadata       *Metadata    `json:"metadata,omitempty"` // Execution settings of every job
+	RawContent     string       `json:"-"`               // Original file content
 }
 
 // Job represents a single job in a Plan.
@@ -32,6 +37,7 @@ type Job struct {
 	DebugLogs    []DebugLog   `json:"debug_logs"`    // Debug log entries
 	CompletionStatus string   `json:"completion_status"` // Completion status marker from plan file
 	IsCompleted  bool         `json:"is_completed"`  // Whether job is marked as completed in plan
+	Metadata     *Metadata    `json:"metadata,omitempty"` // Execution settings overriding the plan's
 }
 
 // TaskItem represents a single task within a Job.
@@ -102,9 +108,49 @@ func (p *Parser) parsePlanContent(content string) (*Plan, error) {
 	// Extract Jobs - look at all sections recursively
 	plan.Jobs = extractJobsFromAllSections(sections)
 
+	// Free-form sections are kept verbatim so other formats can carry them
+	h := CurrentHeadings()
+	plan.Interfaces = extractRawSection(content, h.Aliases(SectionInterfaces)...)
+	plan.DataModel = extractRawSection(content, h.Aliases(SectionDataModel)...)
+	plan.IntegrationTest = extractRawSection(content, h.Aliases(SectionIntegrationTest)...)
+
+	if err := plan.extractMetadata(content, sections); err != nil {
+		return nil, err
+	}
+
 	return plan, nil
 }
 
+// extractMetadata reads the execution settings of the plan and of each
+// job. Job sections are visited in the order extractJobsFromAllSections
+// found them.
+func (p *Plan) extractMetadata(content string, sections []markdown.Section) error {
+	meta, err := extractPlanMetadata(content)
+	if err != nil {
+		return fmt.Errorf("invalid plan metadata: %w", err)
+	}
+	p.Metadata = meta
+
+	next := 0
+	var visit func(secs []markdown.Section) error
+	visit = func(secs []markdown.Section) error {
+		for _, sec := range secs {
+			if sec.Level == 3 && jobTitlePattern.MatchString(sec.Title) && next < len(p.Jobs) {
+				job := &p.Jobs[next]
+				next++
+				if job.Metadata, err = extractJobMetadata(sec.Content); err != nil {
+					return fmt.Errorf("invalid metadata of job %s: %w", job.Name, err)
+				}
+			}
+			if err := visit(sec.Children); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return visit(sections)
+}
+
 // extractModuleName extracts the module name from the title.
 // Title format: "# Plan: ModuleName" or "Plan: ModuleName"
 func extractModuleName(title string) string {
@@ -118,20 +164,14 @@ func extractModuleName(title string) string {
 
 // extractModuleOverview extracts module overview information.
 func (p *Plan) extractModuleOverview(sections []markdown.Section) {
-	debug := os.Getenv("MORTY_DEBUG") != ""
-
-	if debug {
-		fmt.Fprintf(os.Stderr, "DEBUG: extractModuleOverview called for module: %s\n", p.Name)
-		fmt.Fprintf(os.Stderr, "DEBUG: Total sections: %d\n", len(sections))
-	}
+	h := CurrentHeadings()
+	dependencies := h.Aliases(SectionDependencies)
+	dependents := h.Aliases(SectionDependents)
 
 	// Helper function to search recursively
 	var findOverviewSection func(secs []markdown.Section) *markdown.Section
 	findOverviewSection = func(secs []markdown.Section) *markdown.Section {
 		for _, sec := range secs {
-			if debug {
-				fmt.Fprintf(os.Stderr, "DEBUG:   Checking section level=%d title=%s\n", sec.Level, sec.Title)
-			}
 			if isModuleOverviewTitle(sec.Title) {
 				return &sec
 			}
@@ -149,19 +189,12 @@ func (p *Plan) extractModuleOverview(sections []markdown.Section) {
 	overviewSec := findOverviewSection(sections)
 	if overviewSec != nil {
 		content := overviewSec.Content
-		if debug {
-			fmt.Fprintf(os.Stderr, "DEBUG: Found module overview section: %s\n", overviewSec.Title)
-			fmt.Fprintf(os.Stderr, "DEBUG: Content length: %d bytes\n", len(content))
-		}
-		p.Responsibility = extractField(content, "模块职责")
-		p.Research = extractListField(content, "对应 Research")
+		p.Responsibility = extractField(content, h.Aliases(SectionResponsibility)...)
+		p.Research = extractListField(content, h.Aliases(SectionResearch)...)
+		p.References = extractListField(content, h.Aliases(SectionReferences)...)
 		// Extract dependencies from module overview content
-		p.Dependencies = extractListField(content, "依赖模块")
-		p.Dependents = extractListField(content, "被依赖模块")
-		if debug {
-			fmt.Fprintf(os.Stderr, "DEBUG: Extracted dependencies: %v\n", p.Dependencies)
-			fmt.Fprintf(os.Stderr, "DEBUG: Extracted dependents: %v\n", p.Dependents)
-		}
+		p.Dependencies = extractListField(content, dependencies...)
+		p.Dependents = extractListField(content, dependents...)
 		return // Found and extracted, we're done
 	}
 
@@ -169,7 +202,7 @@ func (p *Plan) extractModuleOverview(sections []markdown.Section) {
 	var findDepsSection func(secs []markdown.Section) *markdown.Section
 	findDepsSection = func(secs []markdown.Section) *markdown.Section {
 		for _, sec := range secs {
-			if isMatchingTitle(sec.Title, "依赖模块", "Dependencies") {
+			if h.MatchTitle(SectionDependencies, sec.Title) {
 				return &sec
 			}
 			if len(sec.Children) > 0 {
@@ -184,38 +217,28 @@ func (p *Plan) extractModuleOverview(sections []markdown.Section) {
 	depsSec := findDepsSection(sections)
 	if depsSec != nil {
 		content := depsSec.Content
-		p.Dependencies = extractListField(content, "依赖模块")
-		p.Dependents = extractListField(content, "被依赖模块")
-		if debug {
-			fmt.Fprintf(os.Stderr, "DEBUG: Found separate dependencies section\n")
-			fmt.Fprintf(os.Stderr, "DEBUG: Dependencies: %v\n", p.Dependencies)
-		}
+		p.Dependencies = extractListField(content, dependencies...)
+		p.Dependents = extractListField(content, dependents...)
 		return
 	}
 
 	// If dependencies still not found, search in all section contents
 	if len(p.Dependencies) == 0 {
 		for _, sec := range sections {
-			deps := extractListField(sec.Content, "依赖模块")
+			deps := extractListField(sec.Content, dependencies...)
 			if len(deps) > 0 {
 				p.Dependencies = deps
-				p.Dependents = extractListField(sec.Content, "被依赖模块")
-				if debug {
-					fmt.Fprintf(os.Stderr, "DEBUG: Extracted from section '%s': deps=%v\n", sec.Title, deps)
-				}
+				p.Dependents = extractListField(sec.Content, dependents...)
 				break
 			}
 			// Also search in children
 			var searchChildren func(children []markdown.Section) bool
 			searchChildren = func(children []markdown.Section) bool {
 				for _, child := range children {
-					deps := extractListField(child.Content, "依赖模块")
+					deps := extractListField(child.Content, dependencies...)
 					if len(deps) > 0 {
 						p.Dependencies = deps
-						p.Dependents = extractListField(child.Content, "被依赖模块")
-						if debug {
-							fmt.Fprintf(os.Stderr, "DEBUG: Extracted from child section '%s': deps=%v\n", child.Title, deps)
-						}
+						p.Dependents = extractListField(child.Content, dependents...)
 						return true
 					}
 					if len(child.Children) > 0 {
@@ -234,32 +257,66 @@ func (p *Plan) extractModuleOverview(sections []markdown.Section) {
 
 	// Last resort: search in raw content
 	if len(p.Dependencies) == 0 && p.RawContent != "" {
-		p.Dependencies = extractListField(p.RawContent, "依赖模块")
-		p.Dependents = extractListField(p.RawContent, "被依赖模块")
-		if debug && len(p.Dependencies) > 0 {
-			fmt.Fprintf(os.Stderr, "DEBUG: Extracted from raw content: deps=%v\n", p.Dependencies)
+		p.Dependencies = extractListField(p.RawContent, dependencies...)
+		p.Dependents = extractListField(p.RawContent, dependents...)
+	}
+}
+
+// extractRawSection returns the verbatim body of the first H2 section whose
+// title matches one of the keywords, or "" if missing or marked "无"/"None".
+func extractRawSection(content string, keywords ...string) string {
+	lines := strings.Split(content, "\n")
+	var body []string
+	inSection := false
+	inFence := false
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+		}
+		if !inFence && strings.HasPrefix(line, "## ") {
+			if inSection {
+				break
+			}
+			inSection = isMatchingTitle(strings.TrimPrefix(line, "## "), keywords...)
+			continue
+		}
+		if inSection {
+			body = append(body, line)
 		}
 	}
 
-	if debug {
-		fmt.Fprintf(os.Stderr, "DEBUG: extractModuleOverview finished for %s: deps=%v\n", p.Name, p.Dependencies)
+	text := strings.TrimSpace(strings.Join(body, "\n"))
+	// A trailing job separator belongs to the layout, not the section
+	text = strings.TrimSpace(strings.TrimSuffix(text, "---"))
+	if IsNone(text) {
+		return ""
 	}
+	return text
 }
 
 // isModuleOverviewTitle checks if the title indicates module overview section.
 func isModuleOverviewTitle(title string) bool {
-	lower := strings.ToLower(title)
-	return strings.Contains(lower, "模块概述") ||
-		strings.Contains(lower, "module overview") ||
-		strings.Contains(lower, "overview")
+	return CurrentHeadings().MatchTitle(SectionOverview, title)
+}
+
+// fieldNamePattern returns a case-insensitive pattern matching any of the
+// given field names.
+func fieldNamePattern(fieldNames []string) string {
+	quoted := make([]string, 0, len(fieldNames))
+	for _, name := range fieldNames {
+		quoted = append(quoted, regexp.QuoteMeta(name))
+	}
+	return `(?i:` + strings.Join(quoted, "|") + `)`
 }
 
 // extractField extracts a field value from content.
-// Format: **Field**: value or **Field**: value
-func extractField(content, fieldName string) string {
-	// Match patterns like "**模块职责**: value" or "**模块职责**: value"
+// Format: **Field**: value or **Field**：value, where Field is any of fieldNames.
+func extractField(content string, fieldNames ...string) string {
+	// Match patterns like "**模块职责**: value" or "**Responsibility**: value"
 	// Stop at newline or next ** field
-	pattern := regexp.MustCompile(`\*\*` + regexp.QuoteMeta(fieldName) + `\*\*[:：]\s*([^\n]+?)(?:\n|$)`)
+	pattern := regexp.MustCompile(`\*\*` + fieldNamePattern(fieldNames) + `\*\*[:：]\s*([^\n]+?)(?:\n|$)`)
 	matches := pattern.FindStringSubmatch(content)
 	if len(matches) > 1 {
 		return strings.TrimSpace(matches[1])
@@ -271,14 +328,14 @@ func extractField(content, fieldName string) string {
 // Format: **Field**:
 // - item1
 // - item2
-func extractListField(content, fieldName string) []string {
+func extractListField(content string, fieldNames ...string) []string {
 	var result []string
 
 	// First try to find list items after the field
 	// Match the field line and capture following list items
 	lines := strings.Split(content, "\n")
 	inField := false
-	fieldPattern := regexp.MustCompile(`^\s*\*\*` + regexp.QuoteMeta(fieldName) + `\*\*[:：]`)
+	fieldPattern := regexp.MustCompile(`^\s*\*\*` + fieldNamePattern(fieldNames) + `\*\*[:：]`)
 	listItemPattern := regexp.MustCompile(`^\s*[-*]\s*(.+)$`)
 
 	for _, line := range lines {
@@ -288,12 +345,12 @@ func extractListField(content, fieldName string) []string {
 			parts := strings.SplitN(line, ":", 2)
 			if len(parts) == 2 {
 				value := strings.TrimSpace(parts[1])
-				if value != "" && value != "无" && !strings.HasPrefix(value, "-") && !strings.HasPrefix(value, "*") {
+				if value != "" && !IsNone(value) && !strings.HasPrefix(value, "-") && !strings.HasPrefix(value, "*") {
 					// Inline list like: item1, item2
 					items := strings.Split(value, ",")
 					for _, item := range items {
 						trimmed := strings.TrimSpace(item)
-						if trimmed != "" && trimmed != "无" {
+						if trimmed != "" && !IsNone(trimmed) {
 							result = append(result, trimmed)
 						}
 					}
@@ -325,13 +382,13 @@ func extractListField(content, fieldName string) []string {
 
 	// If no list items found, try inline format
 	if len(result) == 0 {
-		fieldValue := extractField(content, fieldName)
-		if fieldValue != "" && fieldValue != "无" {
+		fieldValue := extractField(content, fieldNames...)
+		if fieldValue != "" && !IsNone(fieldValue) {
 			// Split by comma if there are multiple items inline
 			items := strings.Split(fieldValue, ",")
 			for _, item := range items {
 				trimmed := strings.TrimSpace(item)
-				if trimmed != "" && trimmed != "无" {
+				if trimmed != "" && !IsNone(trimmed) {
 					result = append(result, trimmed)
 				}
 			}
@@ -359,6 +416,9 @@ func extractJobsFromAllSections(sections []markdown.Section) []Job {
 	return jobs
 }
 
+// jobTitlePattern matches "Job N: Name" or "JobN: Name" (case insensitive).
+var jobTitlePattern = regexp.MustCompile(`(?i)^job\s*(\d+)[:：]\s*(.+)$`)
+
 // extractJobFromSection extracts a Job from a section if it matches Job format.
 // Job format: "### Job N: JobName"
 func extractJobFromSection(sec markdown.Section) *Job {
@@ -369,9 +429,7 @@ func extractJobFromSection(sec markdown.Section) *Job {
 
 	title := sec.Title
 
-	// Match "Job N: Name" or "JobN: Name" pattern (case insensitive)
-	jobPattern := regexp.MustCompile(`(?i)^job\s*(\d+)[:：]\s*(.+)$`)
-	matches := jobPattern.FindStringSubmatch(title)
+	matches := jobTitlePattern.FindStringSubmatch(title)
 
 	if len(matches) < 3 {
 		return nil
@@ -389,14 +447,14 @@ func extractJobFromSection(sec markdown.Section) *Job {
 
 	// Try to extract from #### subsections first (new format)
 	// If not found, fall back to ** field format (old format)
-	job.Goal = extractFromSubsectionOrField(sec, "目标", "Goal")
-	job.Prerequisites = extractListFromSubsectionOrField(sec, "前置条件", "Prerequisites")
+	job.Goal = extractFromSubsectionOrField(sec, SectionGoal)
+	job.Prerequisites = extractListFromSubsectionOrField(sec, SectionPrerequisites)
 	job.Tasks = extractTasksFromSubsectionOrContent(sec, content)
 	job.Validators = extractValidatorsFromSubsectionOrContent(sec, content)
 	job.DebugLogs = extractDebugLogsFromSubsectionOrContent(sec, content)
 
 	// Extract completion status
-	job.CompletionStatus = extractFromSubsectionOrField(sec, "完成状态", "Completion Status")
+	job.CompletionStatus = extractFromSubsectionOrField(sec, SectionCompletionStatus)
 	job.IsCompleted = isJobMarkedCompleted(job.CompletionStatus)
 
 	return job
@@ -404,8 +462,6 @@ func extractJobFromSection(sec markdown.Section) *Job {
 
 // extractTasksFromContent extracts tasks from job content.
 func extractTasksFromContent(content string) []TaskItem {
-	var tasks []TaskItem
-
 	// Find the Tasks section - look for "**Tasks" or "**Tasks (Todo 列表)**"
 	// Match up to the colon/newline and capture rest
 	lines := strings.Split(content, "\n")
@@ -446,9 +502,14 @@ func extractTasksFromContent(content string) []TaskItem {
 		taskContent = content
 	}
 
-	// Extract task items in two formats:
-	// 1. "- [ ] Task N: description" or "- [x] Task N: description" (with explicit index)
-	// 2. "- [ ] description" or "- [x] description" (auto-assign index)
+	return parseTaskItems(taskContent)
+}
+
+// parseTaskItems extracts task items in two formats:
+// 1. "- [ ] Task N: description" or "- [x] Task N: description" (with explicit index)
+// 2. "- [ ] description" or "- [x] description" (auto-assign index)
+func parseTaskItems(taskContent string) []TaskItem {
+	var tasks []TaskItem
 
 	// First try to match tasks with explicit index
 	taskWithIndexPattern := regexp.MustCompile(`(?im)^\s*[-*]\s*\[([ xX])\]\s*task\s*(\d+)[:：]\s*(.+)$`)
@@ -486,16 +547,16 @@ func extractTasksFromContent(content string) []TaskItem {
 	return tasks
 }
 
-// extractValidators extracts validator items from content.
+// extractValidators extracts validator items from content. The value may
+// follow the field name on the same line or on the lines below it.
 func extractValidators(content string) []string {
-	var validators []string
-
 	// Find the Validators section
-	validatorPattern := regexp.MustCompile(`(?i)\*\*验证器\*\*[:：]?\s*\n`)
+	fieldNames := CurrentHeadings().Aliases(SectionValidators)
+	validatorPattern := regexp.MustCompile(`(?m)^\s*\*\*` + fieldNamePattern(fieldNames) + `\*\*[:：]?[ \t]*`)
 	validatorLoc := validatorPattern.FindStringIndex(content)
 
 	if validatorLoc == nil {
-		return validators
+		return nil
 	}
 
 	// Extract content from after Validators header to next ** header or end
@@ -505,23 +566,7 @@ func extractValidators(content string) []string {
 	if nextHeader != nil {
 		end = start + nextHeader[0]
 	}
-	validatorContent := content[start:end]
-
-	// Extract validator items: "- [ ] description" or "- [x] description" or "- description"
-	itemPattern := regexp.MustCompile(`(?m)^\s*[-*]\s*(?:\[[ xX]\]\s*)?(.+)$`)
-	items := itemPattern.FindAllStringSubmatch(validatorContent, -1)
-
-	for _, item := range items {
-		if len(item) > 1 {
-			desc := strings.TrimSpace(item[1])
-			// Skip debug log entries
-			if desc != "" && !regexp.MustCompile(`^(debug|explore)\d+[:：]`).MatchString(desc) {
-				validators = append(validators, desc)
-			}
-		}
-	}
-
-	return validators
+	return parseValidatorContent(content[start:end])
 }
 
 // extractDebugLogs extracts debug log entries from content.
@@ -529,7 +574,8 @@ func extractDebugLogs(content string) []DebugLog {
 	var logs []DebugLog
 
 	// Find the Debug Logs section
-	debugPattern := regexp.MustCompile(`(?i)\*\*调试日志\*\*[:：]?\s*\n`)
+	fieldNames := CurrentHeadings().Aliases(SectionDebugLogs)
+	debugPattern := regexp.MustCompile(`\*\*` + fieldNamePattern(fieldNames) + `\*\*[:：]?\s*\n`)
 	debugLoc := debugPattern.FindStringIndex(content)
 
 	if debugLoc == nil {
@@ -546,8 +592,8 @@ func extractDebugLogs(content string) []DebugLog {
 	debugContent := content[start:end]
 
 	// Check if it says "无" (none) or is empty
-	if strings.TrimSpace(debugContent) == "无" || strings.TrimSpace(debugContent) == "" ||
-		strings.TrimSpace(debugContent) == "- 无" {
+	if strings.TrimSpace(debugContent) == "" || IsNone(debugContent) ||
+		IsNone(strings.TrimPrefix(strings.TrimSpace(debugContent), "- ")) {
 		return logs
 	}
 
@@ -629,38 +675,40 @@ func (p *Plan) GetPendingTasks() []TaskItem {
 
 // extractFromSubsectionOrField extracts content from #### subsection or ** field.
 // Tries subsection first (new format), falls back to field (old format).
-func extractFromSubsectionOrField(sec markdown.Section, subsectionTitle, fieldName string) string {
+func extractFromSubsectionOrField(sec markdown.Section, section Section) string {
+	h := CurrentHeadings()
 	// Try to find #### subsection first
 	for _, child := range sec.Children {
-		if child.Level == 4 && isMatchingTitle(child.Title, subsectionTitle) {
+		if child.Level == 4 && h.MatchTitle(section, child.Title) {
 			// Found subsection, return its content
-			return strings.TrimSpace(child.Content)
+			return subsectionBody(child)
 		}
 	}
 	// Fall back to ** field format
-	return extractField(sec.Content, subsectionTitle)
+	return extractField(sec.Content, h.Aliases(section)...)
 }
 
 // extractListFromSubsectionOrField extracts list from #### subsection or ** field.
-func extractListFromSubsectionOrField(sec markdown.Section, subsectionTitle, fieldName string) []string {
+func extractListFromSubsectionOrField(sec markdown.Section, section Section) []string {
+	h := CurrentHeadings()
 	// Try to find #### subsection first
 	for _, child := range sec.Children {
-		if child.Level == 4 && isMatchingTitle(child.Title, subsectionTitle) {
+		if child.Level == 4 && h.MatchTitle(section, child.Title) {
 			// Found subsection, parse its content as list
-			return parseListContent(child.Content)
+			return parseListContent(subsectionBody(child))
 		}
 	}
 	// Fall back to ** field format
-	return extractListField(sec.Content, subsectionTitle)
+	return extractListField(sec.Content, h.Aliases(section)...)
 }
 
 // extractTasksFromSubsectionOrContent extracts tasks from #### subsection or content.
 func extractTasksFromSubsectionOrContent(sec markdown.Section, content string) []TaskItem {
 	// Try to find #### Tasks subsection first
 	for _, child := range sec.Children {
-		if child.Level == 4 && isMatchingTitle(child.Title, "Tasks", "Todo 列表", "任务列表") {
+		if child.Level == 4 && CurrentHeadings().MatchTitle(SectionTasks, child.Title) {
 			// Found Tasks subsection, extract tasks from its content
-			return extractTasksFromContent(child.Content)
+			return parseTaskItems(subsectionBody(child))
 		}
 	}
 	// Fall back to extracting from full content
@@ -671,9 +719,9 @@ func extractTasksFromSubsectionOrContent(sec markdown.Section, content string) [
 func extractValidatorsFromSubsectionOrContent(sec markdown.Section, content string) []string {
 	// Try to find #### Validators subsection first
 	for _, child := range sec.Children {
-		if child.Level == 4 && isMatchingTitle(child.Title, "验证器", "Validator") {
+		if child.Level == 4 && CurrentHeadings().MatchTitle(SectionValidators, child.Title) {
 			// Found Validators subsection, parse its content
-			return parseValidatorContent(child.Content)
+			return parseValidatorContent(subsectionBody(child))
 		}
 	}
 	// Fall back to extracting from full content
@@ -684,15 +732,33 @@ func extractValidatorsFromSubsectionOrContent(sec markdown.Section, content stri
 func extractDebugLogsFromSubsectionOrContent(sec markdown.Section, content string) []DebugLog {
 	// Try to find #### Debug Logs subsection first
 	for _, child := range sec.Children {
-		if child.Level == 4 && isMatchingTitle(child.Title, "调试日志", "Debug", "Debug Logs") {
+		if child.Level == 4 && CurrentHeadings().MatchTitle(SectionDebugLogs, child.Title) {
 			// Found Debug Logs subsection, parse its content
-			return parseDebugLogContent(child.Content)
+			return parseDebugLogContent(subsectionBody(child))
 		}
 	}
 	// Fall back to extracting from full content
 	return extractDebugLogs(content)
 }
 
+// subsectionBody returns the content of a #### subsection without its
+// heading line and without a trailing "---" job separator.
+func subsectionBody(sec markdown.Section) string {
+	body := strings.TrimSpace(sec.Content)
+	if strings.HasPrefix(body, "#") {
+		if idx := strings.Index(body, "\n"); idx >= 0 {
+			body = body[idx+1:]
+		} else {
+			body = ""
+		}
+	}
+	body = strings.TrimSpace(body)
+	for strings.HasSuffix(body, "---") {
+		body = strings.TrimSpace(strings.TrimSuffix(body, "---"))
+	}
+	return body
+}
+
 // isMatchingTitle checks if a title matches any of the given keywords (case-insensitive).
 func isMatchingTitle(title string, keywords ...string) bool {
 	lower := strings.ToLower(strings.TrimSpace(title))
@@ -708,14 +774,14 @@ func isMatchingTitle(title string, keywords ...string) bool {
 // Supports both:
 // - "- item1\n- item2" format
 // - "job_1, job_2" comma-separated format
-// - "job_1 - description" format (extracts job_N)
+// Items keep a " - description" suffix, as in the field format.
 func parseListContent(content string) []string {
 	var result []string
 	lines := strings.Split(content, "\n")
 
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
-		if line == "" || line == "无" {
+		if line == "" || IsNone(line) {
 			continue
 		}
 
@@ -725,13 +791,7 @@ func parseListContent(content string) []string {
 			item := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(line, "-"), "*"))
 			item = strings.TrimSpace(strings.TrimPrefix(item, "*"))
 
-			// Extract job_N from "job_1 - description" format
-			if strings.Contains(item, " - ") {
-				parts := strings.SplitN(item, " - ", 2)
-				item = strings.TrimSpace(parts[0])
-			}
-
-			if item != "" && item != "无" {
+			if item != "" && !IsNone(item) {
 				result = append(result, item)
 			}
 		} else if strings.Contains(line, ",") {
@@ -739,7 +799,7 @@ func parseListContent(content string) []string {
 			items := strings.Split(line, ",")
 			for _, item := range items {
 				item = strings.TrimSpace(item)
-				if item != "" && item != "无" {
+				if item != "" && !IsNone(item) {
 					result = append(result, item)
 				}
 			}
@@ -752,36 +812,39 @@ func parseListContent(content string) []string {
 	return result
 }
 
-// parseValidatorContent parses validator content from subsection.
-func parseValidatorContent(content string) []string {
-	var validators []string
-	lines := strings.Split(content, "\n")
+// validatorItemPattern matches "- [ ] description", "- [x] description"
+// and "- description" validator items.
+var validatorItemPattern = regexp.MustCompile(`^\s*[-*]\s*(?:\[[ xX]\]\s*)?(.+)$`)
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
+// debugEntryPattern matches the id prefix of a debug log entry.
+var debugEntryPattern = regexp.MustCompile(`^(debug|explore)\d+[:：]`)
 
-		// Skip debug log entries
-		if regexp.MustCompile(`^(debug|explore)\d+[:：]`).MatchString(line) {
+// parseValidatorContent parses the body of a validators field or
+// subsection. When the body has list items, those are the validators;
+// otherwise every non-empty line is one. Debug log entries and code
+// fence markers are skipped.
+func parseValidatorContent(content string) []string {
+	var items, lines []string
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "```") || trimmed == "---" {
 			continue
 		}
-
-		// Check if it's a list item
-		if strings.HasPrefix(line, "-") || strings.HasPrefix(line, "*") {
-			item := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(line, "-"), "*"))
-			item = strings.TrimSpace(strings.TrimPrefix(item, "*"))
-			if item != "" {
-				validators = append(validators, item)
+		if m := validatorItemPattern.FindStringSubmatch(line); m != nil {
+			if desc := strings.TrimSpace(m[1]); !debugEntryPattern.MatchString(desc) {
+				items = append(items, desc)
 			}
-		} else if line != "" {
-			// Non-list item, add as-is
-			validators = append(validators, line)
+			continue
+		}
+		if !debugEntryPattern.MatchString(trimmed) {
+			lines = append(lines, trimmed)
 		}
 	}
 
-	return validators
+	if len(items) > 0 {
+		return items
+	}
+	return lines
 }
 
 // parseDebugLogContent parses debug log content from subsection.
@@ -790,7 +853,7 @@ func parseDebugLogContent(content string) []DebugLog {
 
 	// Check if it says "无" (none) or is empty
 	trimmed := strings.TrimSpace(content)
-	if trimmed == "无" || trimmed == "" || trimmed == "- 无" {
+	if trimmed == "" || IsNone(trimmed) || IsNone(strings.TrimPrefix(trimmed, "- ")) {
 		return logs
 	}
 
@@ -1,6 +1,7 @@
 package plan
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 
@@ -283,6 +284,34 @@ func TestExtractValidators(t *testing.T) {
 	}
 }
 
+// TestExtractValidators_InlineAndProse tests validators given on the field
+// line or as prose, in the field and in the subsection format.
+func TestExtractValidators_InlineAndProse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"inline field", "**Validator**: All tasks completed\n\n**Debug**: 无", []string{"All tasks completed"}},
+		{"prose field", "**验证器**:\n```\n输出正确\n退出码为 0\n```\n", []string{"输出正确", "退出码为 0"}},
+		{"list inside prose", "**验证器**:\n应该：\n- 接收输入\n- 输出结果\n", []string{"接收输入", "输出结果"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractValidators(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractValidators() = %q, want %q", got, tt.want)
+			}
+			body := tt.content[strings.Index(tt.content, ":")+1:]
+			if i := strings.Index(body, "**"); i >= 0 {
+				body = body[:i]
+			}
+			if got := parseValidatorContent(body); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseValidatorContent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 // TestExtractDebugLogs tests debug log extraction.
 func TestExtractDebugLogs(t *testing.T) {
 	content := `**调试日志**:
@@ -0,0 +1,125 @@
+package plan
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ReadmeSections lists the sections every plan README needs, in order; the
+// first is the # title, the others are ## sections.
+var ReadmeSections = []Section{
+	SectionPlanIndex,
+	SectionModuleList,
+	SectionDependencyGraph,
+	SectionExecutionOrder,
+	SectionStatistics,
+}
+
+// readmeHeadingPattern matches a markdown heading line.
+var readmeHeadingPattern = regexp.MustCompile(`^(#{1,6})\s+(.+)$`)
+
+// HasHeading reports whether content has a heading of the given level whose
+// title belongs to section under any of its aliases.
+func HasHeading(content string, level int, section Section) bool {
+	h := CurrentHeadings()
+	for _, line := range strings.Split(content, "\n") {
+		m := readmeHeadingPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if m != nil && len(m[1]) == level && h.MatchTitle(section, m[2]) {
+			return true
+		}
+	}
+	return false
+}
+
+// ModuleTable is the module list table of a plan README:
+//
+//	| 模块名称 | 文件 | Jobs 数量 | 依赖模块 | 状态 |
+//
+// Its header is recognised by the module name and dependencies columns
+// under any of their aliases, so English and custom headings work too.
+type ModuleTable struct {
+	Header       int        // line index of the header row
+	End          int        // line index of the last table line
+	ModuleColumn int        // cell index of the module name
+	DepsColumn   int        // cell index of the dependencies
+	Rows         []TableRow // data rows, without the separator
+}
+
+// TableRow is a row of a markdown table.
+type TableRow struct {
+	Line  int      // line index
+	Cells []string // trimmed cells
+}
+
+// Cell returns the cell at index, or "" when the row is too short.
+func (r TableRow) Cell(index int) string {
+	if index < 0 || index >= len(r.Cells) {
+		return ""
+	}
+	return r.Cells[index]
+}
+
+// FindModuleTable returns the first module list table of a plan README, or
+// nil when there is none. The table ends at the first line that is not a
+// table row.
+func FindModuleTable(content string) *ModuleTable {
+	h := CurrentHeadings()
+	var table *ModuleTable
+
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, "|") {
+			if table != nil {
+				break
+			}
+			continue
+		}
+
+		cells := TableCells(trimmed)
+		if table == nil {
+			table = &ModuleTable{Header: i, ModuleColumn: -1, DepsColumn: -1}
+			for j, cell := range cells {
+				switch {
+				case table.ModuleColumn < 0 && h.MatchField(SectionModuleName, cell):
+					table.ModuleColumn = j
+				case table.DepsColumn < 0 && h.MatchField(SectionDependencies, cell):
+					table.DepsColumn = j
+				}
+			}
+			if table.ModuleColumn < 0 || table.DepsColumn < 0 {
+				table = nil
+			} else {
+				table.End = i
+			}
+			continue
+		}
+
+		table.End = i
+		if isTableSeparator(cells) {
+			continue
+		}
+		table.Rows = append(table.Rows, TableRow{Line: i, Cells: cells})
+	}
+	return table
+}
+
+// TableCells splits a markdown table row into trimmed cells.
+func TableCells(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimSuffix(strings.TrimPrefix(line, "|"), "|")
+	cells := strings.Split(line, "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells
+}
+
+// isTableSeparator reports whether cells form a |---|:---:| separator row.
+func isTableSeparator(cells []string) bool {
+	for _, cell := range cells {
+		if strings.Trim(cell, "-: ") != "" || !strings.Contains(cell, "-") {
+			return false
+		}
+	}
+	return true
+}
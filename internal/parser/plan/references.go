@@ -0,0 +1,88 @@
+package plan
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// locationSuffix matches a line or range suffix such as ":42", ":10-20" or "#L42".
+var locationSuffix = regexp.MustCompile(`(:\d+(-\d+)?|#L\d+(-L?\d+)?)$`)
+
+// extensionPattern matches a file extension such as ".go" or ".md".
+var extensionPattern = regexp.MustCompile(`^\.[A-Za-z0-9]+$`)
+
+// ReferencePath extracts the file path from a research or implementation
+// reference item such as "`internal/cmd/plan.go:42` - 描述" or
+// "[设计](docs/design.md)". Items that do not name a file, such as free text
+// or URLs, return false.
+func ReferencePath(item string) (string, bool) {
+	item = strings.TrimSpace(item)
+	if item == "" || IsNone(item) {
+		return "", false
+	}
+
+	var path string
+	switch {
+	case strings.Count(item, "`") >= 2:
+		path = strings.SplitN(item, "`", 3)[1]
+	case strings.HasPrefix(item, "[") && strings.Contains(item, "]("):
+		path = item[strings.Index(item, "](")+2:]
+		if end := strings.Index(path, ")"); end >= 0 {
+			path = path[:end]
+		}
+	default:
+		path = strings.Fields(item)[0]
+	}
+
+	path = strings.TrimRight(strings.TrimSpace(path), ",，;；:：")
+	path = locationSuffix.ReplaceAllString(path, "")
+	if path == "" || path == "." || strings.Contains(path, "://") {
+		return "", false
+	}
+	if !strings.Contains(path, "/") && !extensionPattern.MatchString(filepath.Ext(path)) {
+		return "", false
+	}
+	return path, true
+}
+
+// ReferenceResolver resolves the paths plans reference.
+type ReferenceResolver struct {
+	// Root is the project root that reference paths are relative to.
+	Root string
+	// ResearchDir is where research references given as a bare file name
+	// are looked up.
+	ResearchDir string
+}
+
+// Resolve returns the file a reference path names and whether it exists.
+// Paths may contain glob patterns, which must match at least one file.
+func (r ReferenceResolver) Resolve(section Section, path string) (string, bool) {
+	candidates := []string{path}
+	if !filepath.IsAbs(path) {
+		candidates = []string{filepath.Join(r.Root, path)}
+		if section == SectionResearch && r.ResearchDir != "" && !strings.ContainsAny(path, `/\`) {
+			candidates = append(candidates, filepath.Join(r.ResearchDir, path))
+		}
+	}
+
+	for _, candidate := range candidates {
+		if strings.ContainsAny(candidate, "*?[") {
+			if matches, _ := filepath.Glob(candidate); len(matches) > 0 {
+				return filepath.Clean(candidate), true
+			}
+			continue
+		}
+		if _, err := os.Stat(candidate); err == nil {
+			return filepath.Clean(candidate), true
+		}
+	}
+	return filepath.Clean(candidates[0]), false
+}
+
+// ListField returns the items of a bold list field such as
+// "**对应 Research**:" in markdown content, e.g. the plan index README.
+func ListField(content string, section Section) []string {
+	return extractListField(content, CurrentHeadings().Aliases(section)...)
+}
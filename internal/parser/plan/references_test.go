@@ -0,0 +1,62 @@
+package plan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReferencePath(t *testing.T) {
+	tests := []struct {
+		item string
+		want string
+		ok   bool
+	}{
+		{"`.morty/research/cache.md` - 缓存调研", ".morty/research/cache.md", true},
+		{"`internal/cmd/plan.go:42` - Plan 命令", "internal/cmd/plan.go", true},
+		{"internal/executor/engine.go - Job 执行引擎", "internal/executor/engine.go", true},
+		{"[设计](docs/design.md#L3-L9)", "docs/design.md", true},
+		{"cache.md", "cache.md", true},
+		{"`internal/cmd/` - 命令", "internal/cmd/", true},
+		{"无", "", false},
+		{"research1", "", false},
+		{"标准库 container/list 的用法", "", false},
+		{"https://example.com/doc.md", "", false},
+	}
+
+	for _, tc := range tests {
+		got, ok := ReferencePath(tc.item)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("ReferencePath(%q) = %q, %v, want %q, %v", tc.item, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestReferenceResolver(t *testing.T) {
+	root := t.TempDir()
+	researchDir := filepath.Join(root, ".morty", "research")
+	os.MkdirAll(researchDir, 0755)
+	os.WriteFile(filepath.Join(researchDir, "cache.md"), []byte("# 缓存\n"), 0644)
+	os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0644)
+
+	r := ReferenceResolver{Root: root, ResearchDir: researchDir}
+	tests := []struct {
+		section Section
+		path    string
+		want    string
+		exists  bool
+	}{
+		{SectionResearch, ".morty/research/cache.md", filepath.Join(researchDir, "cache.md"), true},
+		{SectionResearch, "cache.md", filepath.Join(researchDir, "cache.md"), true},
+		{SectionReferences, "cache.md", filepath.Join(root, "cache.md"), false},
+		{SectionReferences, "*.go", filepath.Join(root, "*.go"), true},
+		{SectionReferences, "cmd/*.go", filepath.Join(root, "cmd/*.go"), false},
+	}
+
+	for _, tc := range tests {
+		got, exists := r.Resolve(tc.section, tc.path)
+		if got != tc.want || exists != tc.exists {
+			t.Errorf("Resolve(%s, %q) = %q, %v, want %q, %v", tc.section, tc.path, got, exists, tc.want, tc.exists)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package plan
+
+import (
+	"fmt"
+	"strings"
+)
+
+// noneMarker is written wherever the spec requires an explicit "无".
+const noneMarker = "无"
+
+// RenderMarkdown renders the plan in the canonical markdown layout defined
+// by docs/plan_format_spec.md. Empty lists and sections are written as "无";
+// missing required values (responsibility, goal, validators, completion
+// status) are left empty so that validation still reports them.
+func RenderMarkdown(p *Plan) string {
+	return RenderMarkdownIn(p, LanguageZh)
+}
+
+// RenderMarkdownIn renders the plan like RenderMarkdown, using the headings
+// and "none" marker of the given language.
+func RenderMarkdownIn(p *Plan, lang Language) string {
+	return renderMarkdown(p, lang, nil)
+}
+
+// ReformatMarkdown renders p, parsed from the markdown source, like
+// RenderMarkdownIn and writes back the text of source that p does not
+// model (notes, free-form paragraphs, comments, unknown sections and
+// validators written as prose) verbatim at the same place of the layout.
+func ReformatMarkdown(p *Plan, source string, lang Language) string {
+	return renderMarkdown(p, lang, collectExtras(source))
+}
+
+// renderMarkdown renders the canonical layout with the given extras, which
+// may be nil.
+func renderMarkdown(p *Plan, lang Language, x *extras) string {
+	r := &renderer{lang: lang, none: NoneMarker(lang), extras: x}
+	sb := &r.sb
+
+	r.writeExtras(anchorHead)
+	fmt.Fprintf(sb, "# Plan: %s\n\n", p.Name)
+	if comment := metadataComment(p.Metadata); comment != "" {
+		fmt.Fprintf(sb, "%s\n\n", comment)
+	}
+	r.writeExtras(anchorTitle)
+	r.writeExtras(anchorAfterTitle)
+
+	fmt.Fprintf(sb, "## %s\n\n", Title(SectionOverview, lang))
+	r.writeExtras(fieldAnchor(anchorOverview, ""))
+	if p.Responsibility != "" {
+		fmt.Fprintf(sb, "**%s**: %s\n\n", Title(SectionResponsibility, lang), p.Responsibility)
+	}
+	r.writeExtras(fieldAnchor(anchorOverview, SectionResponsibility))
+	r.writeFieldList(SectionResearch, p.Research)
+	r.writeExtras(fieldAnchor(anchorOverview, SectionResearch))
+	r.writeFieldList(SectionReferences, p.References)
+	r.writeExtras(fieldAnchor(anchorOverview, SectionReferences))
+	fmt.Fprintf(sb, "**%s**: %s\n\n", Title(SectionDependencies, lang), r.joinOrNone(p.Dependencies))
+	r.writeExtras(fieldAnchor(anchorOverview, SectionDependencies))
+	fmt.Fprintf(sb, "**%s**: %s\n\n", Title(SectionDependents, lang), r.joinOrNone(p.Dependents))
+	r.writeExtras(fieldAnchor(anchorOverview, SectionDependents))
+	r.writeExtras(afterAnchor(SectionOverview))
+
+	r.writeSection(SectionInterfaces, p.Interfaces)
+	r.writeSection(SectionDataModel, p.DataModel)
+
+	fmt.Fprintf(sb, "## %s\n\n", Title(SectionJobs, lang))
+	r.writeExtras(fieldAnchor(anchorJobs, ""))
+	for i, job := range p.Jobs {
+		sb.WriteString("---\n\n")
+		r.writeJob(job, i+1)
+	}
+	sb.WriteString("---\n\n")
+	r.writeExtras(afterAnchor(SectionJobs))
+
+	r.writeSection(SectionIntegrationTest, p.IntegrationTest)
+
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
+
+// renderer accumulates markdown output in a single language.
+type renderer struct {
+	sb     strings.Builder
+	lang   Language
+	none   string
+	extras *extras // unmodeled text to write back, nil for a plain render
+}
+
+// writeExtras writes the unmodeled text of an anchor, if any.
+func (r *renderer) writeExtras(anchor string) {
+	if text := r.extras.text(anchor); text != "" {
+		fmt.Fprintf(&r.sb, "%s\n\n", text)
+	}
+}
+
+// writeJob renders a single job. position is used when the job has no index.
+func (r *renderer) writeJob(job Job, position int) {
+	sb := &r.sb
+	index := job.Index
+	if index <= 0 {
+		index = position
+	}
+	fmt.Fprintf(sb, "### Job %d: %s\n\n", index, job.Name)
+	if comment := metadataComment(job.Metadata); comment != "" {
+		fmt.Fprintf(sb, "%s\n\n", comment)
+	}
+	at := func(part string) string { return jobAnchor(position-1, part) }
+	r.writeExtras(at(""))
+
+	r.writeSubsection(SectionGoal, job.Goal)
+	r.writeExtras(at(string(SectionGoal)))
+
+	r.writeHeading(SectionPrerequisites)
+	r.writeList(job.Prerequisites)
+	r.writeExtras(at(string(SectionPrerequisites)))
+
+	// A note under an empty section replaces the "none" marker
+	r.writeHeading(SectionTasks)
+	if len(job.Tasks) == 0 {
+		if r.extras.text(at(string(SectionTasks))) == "" {
+			sb.WriteString(r.none + "\n\n")
+		}
+	} else {
+		for i, task := range job.Tasks {
+			mark := " "
+			if task.Completed {
+				mark = "x"
+			}
+			taskIndex := task.Index
+			if taskIndex <= 0 {
+				taskIndex = i + 1
+			}
+			fmt.Fprintf(sb, "- [%s] Task %d: %s\n", mark, taskIndex, task.Description)
+		}
+		sb.WriteString("\n")
+	}
+	r.writeExtras(at(string(SectionTasks)))
+
+	r.writeHeading(SectionValidators)
+	if body, ok := r.extras.validatorsBody(position - 1); ok {
+		fmt.Fprintf(sb, "%s\n\n", body)
+	} else {
+		for _, v := range job.Validators {
+			fmt.Fprintf(sb, "- %s\n", v)
+		}
+		if len(job.Validators) > 0 {
+			sb.WriteString("\n")
+		}
+	}
+	r.writeExtras(at(string(SectionValidators)))
+
+	r.writeHeading(SectionDebugLogs)
+	if len(job.DebugLogs) == 0 {
+		if r.extras.text(at(string(SectionDebugLogs))) == "" {
+			sb.WriteString(r.none + "\n\n")
+		}
+	} else {
+		for _, log := range job.DebugLogs {
+			fmt.Fprintf(sb, "- %s: %s, %s, %s, %s, %s, %s\n",
+				log.ID, log.Phenomenon, log.Reproduction, log.Hypothesis,
+				log.Verification, log.Fix, log.Progress)
+		}
+		sb.WriteString("\n")
+	}
+	r.writeExtras(at(string(SectionDebugLogs)))
+
+	r.writeSubsection(SectionCompletionStatus, job.CompletionStatus)
+	r.writeExtras(at(string(SectionCompletionStatus)))
+	r.writeExtras(at("end"))
+}
+
+// writeHeading renders a #### subsection heading.
+func (r *renderer) writeHeading(section Section) {
+	fmt.Fprintf(&r.sb, "#### %s\n\n", Title(section, r.lang))
+}
+
+// writeSubsection renders a #### subsection with a single text body.
+func (r *renderer) writeSubsection(section Section, body string) {
+	r.writeHeading(section)
+	if body = strings.TrimSpace(body); body != "" {
+		fmt.Fprintf(&r.sb, "%s\n\n", body)
+	}
+}
+
+// writeFieldList renders "**name**:" followed by a bullet list, or "无".
+func (r *renderer) writeFieldList(section Section, items []string) {
+	name := Title(section, r.lang)
+	if len(items) == 0 {
+		fmt.Fprintf(&r.sb, "**%s**: %s\n\n", name, r.none)
+		return
+	}
+	fmt.Fprintf(&r.sb, "**%s**:\n", name)
+	for _, item := range items {
+		fmt.Fprintf(&r.sb, "- %s\n", item)
+	}
+	r.sb.WriteString("\n")
+}
+
+// writeList renders a bullet list, or "无" when empty.
+func (r *renderer) writeList(items []string) {
+	if len(items) == 0 {
+		r.sb.WriteString(r.none + "\n\n")
+		return
+	}
+	for _, item := range items {
+		fmt.Fprintf(&r.sb, "- %s\n", item)
+	}
+	r.sb.WriteString("\n")
+}
+
+// writeSection renders an H2 section with verbatim body, or "无", followed
+// by the unknown sections that came after it.
+func (r *renderer) writeSection(section Section, body string) {
+	if body = strings.TrimSpace(body); body == "" {
+		body = r.none
+	}
+	fmt.Fprintf(&r.sb, "## %s\n\n%s\n\n", Title(section, r.lang), body)
+	r.writeExtras(afterAnchor(section))
+}
+
+// joinOrNone joins items with ", ", or returns "无" when empty.
+func (r *renderer) joinOrNone(items []string) string {
+	if len(items) == 0 {
+		return r.none
+	}
+	return strings.Join(items, ", ")
+}
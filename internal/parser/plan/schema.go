@@ -0,0 +1,64 @@
+package plan
+
+import (
+	"reflect"
+
+	"github.com/morty/morty/internal/schema"
+)
+
+// planRules holds the constraints and descriptions of structured plan fields.
+var planRules = map[string]schema.Rule{
+	"": {Description: "Morty plan (JSON or YAML format)"},
+
+	"name":             {Description: "Module name", MinLength: schema.NonEmpty},
+	"responsibility":   {Description: "Module responsibilities"},
+	"research":         {Description: "Related research documents"},
+	"references":       {Description: "Existing implementation references"},
+	"dependencies":     {Description: "Modules this module depends on"},
+	"dependents":       {Description: "Modules that depend on this module"},
+	"interfaces":       {Description: "Interface definitions (Markdown)"},
+	"data_model":       {Description: "Data model (Markdown)"},
+	"integration_test": {Description: "Integration test (Markdown)"},
+	"metadata":         {Description: "Execution settings of every job"},
+
+	"jobs":                       {Description: "Jobs of the module, in execution order"},
+	"jobs[].name":                {Description: "Job name", MinLength: schema.NonEmpty},
+	"jobs[].index":               {Description: "Job number", Minimum: schema.Min(0)},
+	"jobs[].goal":                {Description: "Job objective"},
+	"jobs[].prerequisites":       {Description: "Prerequisites (e.g. module:job)"},
+	"jobs[].tasks":               {Description: "Tasks of the job"},
+	"jobs[].tasks[].index":       {Description: "Task number", Minimum: schema.Min(0)},
+	"jobs[].tasks[].description": {Description: "Task description", MinLength: schema.NonEmpty},
+	"jobs[].tasks[].completed":   {Description: "Whether the task is completed"},
+	"jobs[].validators":          {Description: "Acceptance criteria"},
+	"jobs[].debug_logs":          {Description: "Debug log entries"},
+	"jobs[].completion_status":   {Description: "Completion status marker"},
+	"jobs[].is_completed":        {Description: "Whether the job is completed"},
+	"jobs[].metadata":            {Description: "Execution settings of the job, overriding the plan's"},
+}
+
+// metadataRules describes the execution settings, which appear both on the
+// plan and on each job.
+var metadataRules = map[string]schema.Rule{
+	"model":            {Description: "AI model of the run"},
+	"timeout":          {Description: "Timeout of the run (e.g. 45m)", Pattern: `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`},
+	"permission_mode":  {Description: "AI CLI permission mode (e.g. acceptEdits)"},
+	"tools":            {Description: "Tools the run may use (e.g. Bash(go test:*))"},
+	"disallowed_tools": {Description: "Tools the run may not use"},
+	"args":             {Description: "Extra AI CLI arguments"},
+}
+
+func init() {
+	for _, prefix := range []string{"metadata", "jobs[].metadata"} {
+		for key, rule := range metadataRules {
+			planRules[prefix+"."+key] = rule
+		}
+	}
+}
+
+// Schema returns the JSON Schema of JSON and YAML plans.
+func Schema() *schema.Schema {
+	s := schema.Generate(reflect.TypeOf(Plan{}), planRules)
+	s.Title = "Morty plan"
+	return s
+}
@@ -0,0 +1,38 @@
+package plan
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSchemaMatchesStructuredPlans tests that plans written in the JSON
+// format validate against the schema and may reference it.
+func TestSchemaMatchesStructuredPlans(t *testing.T) {
+	original, err := ParsePlan(canonicalPlan)
+	if err != nil {
+		t.Fatalf("ParsePlan failed: %v", err)
+	}
+	original.Schema = "./plan.schema.json"
+	data, err := Marshal(original, FormatJSON)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	errs, err := Schema().ValidateJSON(data)
+	if err != nil || len(errs) > 0 {
+		t.Fatalf("Expected a valid plan, got %v %v", errs, err)
+	}
+	decoded, err := ParsePlanJSON(string(data))
+	if err != nil || decoded.Schema != original.Schema {
+		t.Fatalf("Expected $schema to round trip, got %q, %v", decoded.Schema, err)
+	}
+
+	errs, _ = Schema().ValidateJSON([]byte(`{"name": "x", "jobs": [{"name": "", "taks": []}]}`))
+	got := make([]string, 0, len(errs))
+	for _, e := range errs {
+		got = append(got, e.Error())
+	}
+	if strings.Join(got, "; ") != "jobs[0].name: must not be empty; jobs[0].taks: unknown field" {
+		t.Errorf("Unexpected errors: %v", got)
+	}
+}
@@ -0,0 +1,307 @@
+// Package prompt renders the prompts morty sends to the AI CLI from
+// text/template files. Templates use the {{.var}} syntax of text/template;
+// see Engine. ParsePrompt reads standalone prompt files with frontmatter
+// and {{var}} placeholders.
+package prompt
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// builtin holds the default phase templates, partials and research / plan
+// / doing instruction files, the latter copied from the repository's
+// prompts/ directory. Files in the engine's directories with the same name
+// take precedence.
+//
+//go:embed templates
+var builtin embed.FS
+
+// BuiltinSource is the source reported for templates that come from the
+// embedded defaults.
+const BuiltinSource = "builtin"
+
+// funcs are the helper functions available in every template.
+var funcs = template.FuncMap{
+	"join": strings.Join,
+	"add":  func(a, b int) int { return a + b },
+	"trim": strings.TrimSpace,
+}
+
+// Engine renders prompts from text/template files. A template name such as
+// "phases/plan.md" or "partials/headless.md" is looked up in each directory
+// in order and then in the built-in templates; partials are included with
+// {{template "partials/headless.md" .}} and resolved the same way.
+type Engine struct {
+	dirs     []string
+	language string
+	sources  map[string]string
+}
+
+// NewEngine creates an engine that searches dirs in order before the
+// built-in templates. Empty dirs are skipped.
+func NewEngine(dirs ...string) *Engine {
+	e := &Engine{sources: make(map[string]string)}
+	for _, dir := range dirs {
+		if dir != "" {
+			e.dirs = append(e.dirs, dir)
+		}
+	}
+	return e
+}
+
+// SetLanguage makes the engine prefer localized templates, e.g.
+// "plan.en.md" over "plan.md" for "en", at every lookup level.
+func (e *Engine) SetLanguage(language string) {
+	e.language = language
+}
+
+// SetSource pins the template name to a file, bypassing the directory
+// lookup. It is used for prompts configured with an explicit path.
+func (e *Engine) SetSource(name, path string) {
+	e.sources[name] = path
+}
+
+// Source returns the file the template name resolves to, or
+// "builtin:<name>" for an embedded template.
+func (e *Engine) Source(name string) (string, error) {
+	_, source, err := e.load(name)
+	return source, err
+}
+
+// Render executes the template name with data. Referencing a key that
+// data does not contain is an error.
+func (e *Engine) Render(name string, data map[string]interface{}) (string, error) {
+	t, _, err := e.Parse(name)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render prompt %s: %w", name, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// Parse parses the template name together with every partial it includes
+// and returns the sources of the loaded files, the root first.
+func (e *Engine) Parse(name string) (*template.Template, []string, error) {
+	root := template.New(name).Funcs(funcs).Option("missingkey=error")
+	var sources []string
+
+	pending := []string{name}
+	for len(pending) > 0 {
+		current := pending[0]
+		pending = pending[1:]
+		if current != name && root.Lookup(current) != nil {
+			continue
+		}
+
+		content, source, err := e.load(current)
+		if err != nil {
+			return nil, sources, err
+		}
+		sources = append(sources, source)
+
+		t := root
+		if current != name {
+			t = root.New(current)
+		}
+		if _, err := t.Parse(content); err != nil {
+			return nil, sources, fmt.Errorf("failed to parse prompt %s (%s): %w", current, source, err)
+		}
+
+		for _, tmpl := range root.Templates() {
+			if tmpl.Tree == nil {
+				continue
+			}
+			for _, include := range includes(tmpl.Tree.Root) {
+				if root.Lookup(include) == nil && !containsName(pending, include) {
+					pending = append(pending, include)
+				}
+			}
+		}
+	}
+	return root, sources, nil
+}
+
+// load returns the content of the template name without frontmatter and
+// the file it was read from.
+func (e *Engine) load(name string) (string, string, error) {
+	if path, ok := e.sources[name]; ok {
+		path = e.localized(path, fileExists)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read prompt %s: %w", path, err)
+		}
+		return stripFrontmatter(string(data)), path, nil
+	}
+
+	for _, dir := range e.dirs {
+		candidate := e.localized(filepath.Join(dir, filepath.FromSlash(name)), fileExists)
+		if data, err := os.ReadFile(candidate); err == nil {
+			return stripFrontmatter(string(data)), candidate, nil
+		}
+	}
+
+	candidate := e.localized(path.Join("templates", name), func(p string) bool {
+		_, err := fs.Stat(builtin, p)
+		return err == nil
+	})
+	if data, err := builtin.ReadFile(candidate); err == nil {
+		return stripFrontmatter(string(data)), BuiltinSource + ":" + strings.TrimPrefix(candidate, "templates/"), nil
+	}
+
+	return "", "", fmt.Errorf("prompt template %s not found in %s or the built-in templates", name, strings.Join(e.dirs, ", "))
+}
+
+// localized returns the language variant of p when exists reports it.
+func (e *Engine) localized(p string, exists func(string) bool) string {
+	if e.language == "" {
+		return p
+	}
+	ext := filepath.Ext(p)
+	variant := strings.TrimSuffix(p, ext) + "." + e.language + ext
+	if exists(variant) {
+		return variant
+	}
+	return p
+}
+
+// fileExists reports whether path names a regular file.
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// stripFrontmatter drops the optional frontmatter of a template file.
+func stripFrontmatter(content string) string {
+	_, body := extractFrontmatter(content)
+	return body
+}
+
+// containsName reports whether names contains name.
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// includes returns the names of the templates invoked under node.
+func includes(node parse.Node) []string {
+	var names []string
+	walk(node, true, func(n parse.Node, _ bool) {
+		if t, ok := n.(*parse.TemplateNode); ok {
+			names = append(names, t.Name)
+		}
+	})
+	return names
+}
+
+// walk calls fn for node and its descendants. top reports whether dot is
+// still the data passed to the template; it turns false inside range and
+// with bodies.
+func walk(node parse.Node, top bool, fn func(parse.Node, bool)) {
+	if node == nil {
+		return
+	}
+	fn(node, top)
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			walk(child, top, fn)
+		}
+	case *parse.ActionNode:
+		walk(n.Pipe, top, fn)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			walk(cmd, top, fn)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			walk(arg, top, fn)
+		}
+	case *parse.ChainNode:
+		walk(n.Node, top, fn)
+	case *parse.IfNode:
+		walk(n.Pipe, top, fn)
+		walk(n.List, top, fn)
+		walk(n.ElseList, top, fn)
+	case *parse.RangeNode:
+		walk(n.Pipe, top, fn)
+		walk(n.List, false, fn)
+		walk(n.ElseList, top, fn)
+	case *parse.WithNode:
+		walk(n.Pipe, top, fn)
+		walk(n.List, false, fn)
+		walk(n.ElseList, top, fn)
+	case *parse.TemplateNode:
+		walk(n.Pipe, top, fn)
+	}
+}
+
+// variables collects the data keys referenced by the template name of t.
+func variables(t *template.Template, name string) []string {
+	seen := make(map[string]bool)
+	visited := make(map[string]bool)
+
+	var visit func(name string)
+	visit = func(name string) {
+		tmpl := t.Lookup(name)
+		if visited[name] || tmpl == nil || tmpl.Tree == nil {
+			return
+		}
+		visited[name] = true
+		walk(tmpl.Tree.Root, true, func(n parse.Node, top bool) {
+			switch n := n.(type) {
+			case *parse.FieldNode:
+				if top {
+					seen[n.Ident[0]] = true
+				}
+			case *parse.VariableNode:
+				if n.Ident[0] == "$" && len(n.Ident) > 1 {
+					seen[n.Ident[1]] = true
+				}
+			case *parse.TemplateNode:
+				if top && passesDot(n.Pipe) {
+					visit(n.Name)
+				}
+			}
+		})
+	}
+	visit(name)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// passesDot reports whether a template invocation passes dot unchanged.
+func passesDot(pipe *parse.PipeNode) bool {
+	if pipe == nil || len(pipe.Cmds) != 1 || len(pipe.Cmds[0].Args) != 1 {
+		return false
+	}
+	_, ok := pipe.Cmds[0].Args[0].(*parse.DotNode)
+	return ok
+}
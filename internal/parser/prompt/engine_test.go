@@ -0,0 +1,182 @@
+package prompt
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeTemplate writes a template file below dir.
+func writeTemplate(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEngine_RenderBuiltinPhase(t *testing.T) {
+	prompts := t.TempDir()
+	writeTemplate(t, prompts, "research.md", "---\nname: research\n---\n# Research\n\nStudy the code.\n")
+
+	engine := NewEngine(prompts)
+	got, err := engine.Render("phases/research.md", map[string]interface{}{
+		"topic":     "auth",
+		"headless":  true,
+		"seed":      "Users log in with email.",
+		"seed_file": "seed.md",
+	})
+	if err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	for _, want := range []string{
+		"# Research Topic: auth\n\n# Research\n\nStudy the code.\n\n---\n\n# 需求 (seed.md)\n\nUsers log in with email.",
+		"# 非交互模式",
+		"把完整的研究文档",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "name: research") {
+		t.Errorf("Frontmatter should be stripped:\n%s", got)
+	}
+
+	if _, err := engine.Render("phases/research.md", map[string]interface{}{"topic": "auth"}); err == nil {
+		t.Error("Expected an error for a missing variable")
+	}
+}
+
+func TestEngine_BuiltinInstructions(t *testing.T) {
+	engine := NewEngine(t.TempDir())
+	for _, name := range []string{"research.md", "plan.md", "doing.md"} {
+		if got, err := engine.Source(name); err != nil || got != BuiltinSource+":"+name {
+			t.Errorf("Source(%s) = %q, %v", name, got, err)
+		}
+
+		// The built-in copies must not drift from the shipped prompts
+		localized := strings.TrimSuffix(name, ".md") + ".en.md"
+		for _, file := range []string{name, localized} {
+			shipped, err := os.ReadFile(filepath.Join("..", "..", "..", "prompts", file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			embedded, err := builtin.ReadFile("templates/" + file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(embedded) != string(shipped) {
+				t.Errorf("templates/%s differs from prompts/%s", file, file)
+			}
+		}
+	}
+
+	// Localized built-in instructions are preferred, with a fallback for
+	// languages that do not ship
+	engine.SetLanguage("en")
+	for _, name := range []string{"research.md", "plan.md", "doing.md"} {
+		want := BuiltinSource + ":" + strings.TrimSuffix(name, ".md") + ".en.md"
+		if got, err := engine.Source(name); err != nil || got != want {
+			t.Errorf("Source(%s) = %q, %v, want %q", name, got, err, want)
+		}
+	}
+	engine.SetLanguage("fr")
+	if got, _ := engine.Source("plan.md"); got != BuiltinSource+":plan.md" {
+		t.Errorf("Source(plan.md) for fr = %q", got)
+	}
+
+	for _, phase := range Phases {
+		if result := engine.Check(phase); !result.OK() {
+			t.Errorf("Check(%s) = %+v", phase.Name, result)
+		}
+	}
+}
+
+func TestEngine_Lookup(t *testing.T) {
+	overrides, prompts := t.TempDir(), t.TempDir()
+	writeTemplate(t, prompts, "plan.md", "prompts plan")
+	writeTemplate(t, prompts, "plan.en.md", "english plan")
+	writeTemplate(t, overrides, "partials/headless.md", "project headless")
+
+	engine := NewEngine(overrides, "", prompts)
+	for name, want := range map[string]string{
+		"plan.md":              filepath.Join(prompts, "plan.md"),
+		"partials/headless.md": filepath.Join(overrides, "partials", "headless.md"),
+		"phases/plan.md":       BuiltinSource + ":phases/plan.md",
+	} {
+		if got, err := engine.Source(name); err != nil || got != want {
+			t.Errorf("Source(%s) = %q, %v, want %q", name, got, err, want)
+		}
+	}
+
+	engine.SetLanguage("en")
+	if got, _ := engine.Source("plan.md"); got != filepath.Join(prompts, "plan.en.md") {
+		t.Errorf("Localized source = %q", got)
+	}
+
+	pinned := filepath.Join(t.TempDir(), "custom.md")
+	writeTemplate(t, filepath.Dir(pinned), "custom.md", "custom plan")
+	engine.SetSource("plan.md", pinned)
+	if got, _ := engine.Source("plan.md"); got != pinned {
+		t.Errorf("Pinned source = %q", got)
+	}
+
+	if _, err := engine.Source("missing.md"); err == nil {
+		t.Error("Expected an error for a missing template")
+	}
+}
+
+func TestEngine_PartialsAndLoops(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "page.md", `{{template "partials/list.md" .}}{{if .done}} done{{end}}`)
+	writeTemplate(t, dir, "partials/list.md", `{{range $i, $job := .jobs}}{{if $i}}, {{end}}{{add $i 1}}. {{$job.name}}{{end}}`)
+
+	got, err := NewEngine(dir).Render("page.md", map[string]interface{}{
+		"jobs": []map[string]interface{}{{"name": "build"}, {"name": "test"}},
+		"done": true,
+	})
+	if err != nil || got != "1. build, 2. test done" {
+		t.Errorf("Render() = %q, %v", got, err)
+	}
+}
+
+func TestEngine_Check(t *testing.T) {
+	prompts := t.TempDir()
+	for _, name := range []string{"research.md", "plan.md", "doing.md"} {
+		writeTemplate(t, prompts, name, "# Instructions\n")
+	}
+
+	engine := NewEngine(prompts)
+	for _, phase := range Phases {
+		if result := engine.Check(phase); !result.OK() {
+			t.Errorf("Check(%s) = %+v", phase.Name, result)
+		}
+	}
+
+	overrides := t.TempDir()
+	writeTemplate(t, overrides, "phases/research.md", "# {{.title}}\n{{range .items}}{{.name}}{{end}}{{$.seed}}")
+	writeTemplate(t, overrides, "phases/plan.md", "{{template \"partials/nope.md\" .}}")
+	writeTemplate(t, overrides, "phases/doing.md", "{{if .module")
+
+	engine = NewEngine(overrides, prompts)
+	research, _ := LookupPhase("research")
+	result := engine.Check(research)
+	if !reflect.DeepEqual(result.Unknown, []string{"items", "title"}) || !reflect.DeepEqual(result.Missing, []string{"topic"}) {
+		t.Errorf("Check(research) = %+v", result)
+	}
+	if result.Sources[0] != filepath.Join(overrides, "phases", "research.md") {
+		t.Errorf("Sources = %q", result.Sources)
+	}
+
+	for _, name := range []string{"plan", "doing"} {
+		phase, _ := LookupPhase(name)
+		if result := engine.Check(phase); result.Err == nil {
+			t.Errorf("Expected an error for %s", name)
+		}
+	}
+}
@@ -1,4 +1,3 @@
-// Package prompt provides Prompt file parsing functionality.
 package prompt
 
 import (
@@ -0,0 +1,181 @@
+package prompt
+
+import "sort"
+
+// Variable describes a data key a phase passes to its template.
+type Variable struct {
+	Name        string
+	Description string
+	Required    bool // the prompt is incomplete when the template omits it
+}
+
+// Phase is a prompt morty renders, with the template it starts from and
+// the variables it provides.
+type Phase struct {
+	Name      string
+	Template  string
+	Variables []Variable
+}
+
+// Phases lists every prompt morty renders.
+var Phases = []Phase{
+	{
+		Name:     "research",
+		Template: "phases/research.md",
+		Variables: []Variable{
+			{Name: "topic", Description: "研究主题", Required: true},
+			{Name: "headless", Description: "是否以非交互模式运行"},
+			{Name: "seed", Description: "--input 需求文件的内容"},
+			{Name: "seed_file", Description: "--input 需求文件的文件名"},
+		},
+	},
+	{
+		Name:     "plan",
+		Template: "phases/plan.md",
+		Variables: []Variable{
+			{Name: "module", Description: "模块名称", Required: true},
+			{Name: "research", Description: "研究资料或按相关度挑选的研究片段", Required: true},
+			{Name: "headless", Description: "是否以非交互模式运行"},
+			{Name: "seed", Description: "--input 需求文件的内容"},
+			{Name: "seed_file", Description: "--input 需求文件的文件名"},
+		},
+	},
+	{
+		Name:     "plan_module",
+		Template: "phases/plan_module.md",
+		Variables: []Variable{
+			{Name: "module", Description: "要添加或重新生成的模块", Required: true},
+			{Name: "research", Description: "按相关度挑选的研究资料", Required: true},
+			{Name: "dependencies", Description: "模块的依赖模块列表", Required: true},
+			{Name: "modules", Description: "已有模块列表 (name, responsibility, dependencies, jobs)"},
+			{Name: "current", Description: "plan regen 时模块当前的 Plan (Markdown)"},
+			{Name: "seed", Description: "--input 需求文件的内容"},
+			{Name: "seed_file", Description: "--input 需求文件的文件名"},
+		},
+	},
+	{
+		Name:     "plan_repair",
+		Template: "phases/plan_repair.md",
+		Variables: []Variable{
+			{Name: "files", Description: "未通过校验的文件 (name, errors, exists, content)", Required: true},
+		},
+	},
+	{
+		Name:     "doing",
+		Template: "phases/doing.md",
+		Variables: []Variable{
+			{Name: "module", Description: "模块名称", Required: true},
+			{Name: "job", Description: "Job 名称", Required: true},
+			{Name: "tasks", Description: "Job 的 Tasks (index, description, completed)", Required: true},
+			{Name: "tasks_total", Description: "Task 总数"},
+			{Name: "tasks_completed", Description: "已完成的 Task 数"},
+			{Name: "plan", Description: "模块的 Plan 内容", Required: true},
+			{Name: "research", Description: "与 Job 最相关的研究片段"},
+			{Name: "research_selected", Description: "选中的研究片段数"},
+			{Name: "research_total", Description: "研究片段总数"},
+			{Name: "digests", Description: "在 Token 预算内选出的已完成 Job 摘要 (module, job, files, symbols, summary)"},
+			{Name: "digests_total", Description: "可用的已完成 Job 摘要数"},
+		},
+	},
+	{
+		Name:     "doing_resume",
+		Template: "phases/doing_resume.md",
+		Variables: []Variable{
+			{Name: "module", Description: "模块名称", Required: true},
+			{Name: "job", Description: "Job 名称", Required: true},
+			{Name: "attempt", Description: "本次尝试的序号 (从 1 开始)"},
+			{Name: "failure", Description: "上一次尝试失败的原因", Required: true},
+			{Name: "tasks", Description: "Job 的 Tasks (index, description, completed)", Required: true},
+			{Name: "tasks_total", Description: "Task 总数"},
+			{Name: "tasks_completed", Description: "已完成的 Task 数"},
+		},
+	},
+	{
+		Name:     "doing_task",
+		Template: "phases/doing_task.md",
+		Variables: []Variable{
+			{Name: "module", Description: "模块名称", Required: true},
+			{Name: "job", Description: "Job 名称", Required: true},
+			{Name: "task_index", Description: "Task 序号 (从 1 开始)", Required: true},
+			{Name: "task", Description: "Task 描述", Required: true},
+			{Name: "plan", Description: "模块的 Plan 内容", Required: true},
+		},
+	},
+	{
+		Name:     "doing_context",
+		Template: "phases/doing_context.md",
+		Variables: []Variable{
+			{Name: "module", Description: "模块名称", Required: true},
+			{Name: "job", Description: "Job 名称", Required: true},
+			{Name: "context", Description: "精简上下文 (JSON)", Required: true},
+			{Name: "plan", Description: "模块的 Plan 内容", Required: true},
+			{Name: "tasks", Description: "Job 的 Tasks (index, description, completed)", Required: true},
+			{Name: "tasks_total", Description: "Task 总数，Job 不存在时为 0"},
+			{Name: "validators", Description: "Job 的验证器"},
+		},
+	},
+}
+
+// LookupPhase returns the phase with the given name.
+func LookupPhase(name string) (Phase, bool) {
+	for _, p := range Phases {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Phase{}, false
+}
+
+// PhaseNames returns the names of all phases.
+func PhaseNames() []string {
+	names := make([]string, 0, len(Phases))
+	for _, p := range Phases {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// CheckResult reports how a phase's template lines up with its variables.
+type CheckResult struct {
+	Phase   string
+	Sources []string // files the template and its partials were read from
+	Err     error    // the template or a partial is missing or does not parse
+	Unknown []string // referenced by the template but not provided
+	Missing []string // required but never referenced
+}
+
+// OK reports whether the check found no problems.
+func (r CheckResult) OK() bool {
+	return r.Err == nil && len(r.Unknown) == 0 && len(r.Missing) == 0
+}
+
+// Check parses the phase's template and compares the variables it uses
+// with the ones the phase provides.
+func (e *Engine) Check(phase Phase) CheckResult {
+	result := CheckResult{Phase: phase.Name}
+	t, sources, err := e.Parse(phase.Template)
+	result.Sources = sources
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	used := make(map[string]bool)
+	for _, name := range variables(t, phase.Template) {
+		used[name] = true
+	}
+	provided := make(map[string]bool)
+	for _, v := range phase.Variables {
+		provided[v.Name] = true
+		if v.Required && !used[v.Name] {
+			result.Missing = append(result.Missing, v.Name)
+		}
+	}
+	for name := range used {
+		if !provided[name] {
+			result.Unknown = append(result.Unknown, name)
+		}
+	}
+	sort.Strings(result.Unknown)
+	return result
+}
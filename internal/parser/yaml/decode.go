@@ -0,0 +1,542 @@
+// Package yaml provides a YAML parser implementation.
+//
+// Only the block-style subset of YAML needed for structured documents such as
+// plans is supported: mappings, sequences, plain/quoted scalars, literal and
+// folded block scalars, simple flow sequences/mappings and comments. Anchors,
+// aliases, tags and multi-document streams are rejected or ignored.
+package yaml
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SyntaxError describes a YAML syntax error.
+type SyntaxError struct {
+	Line    int    // 1-based line number
+	Message string // Error description
+}
+
+// Error implements the error interface.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("yaml: line %d: %s", e.Line, e.Message)
+}
+
+// line is a single source line with its indentation.
+type line struct {
+	num    int    // 1-based line number
+	indent int    // number of leading spaces
+	text   string // content without indentation and trailing comment
+	raw    string // original line (used for block scalars)
+}
+
+// decoder is a recursive-descent parser over indented lines.
+type decoder struct {
+	lines []line
+	pos   int
+}
+
+// Decode parses YAML content into generic Go values:
+// map[string]interface{}, []interface{}, string, int, float64, bool or nil.
+func Decode(content string) (interface{}, error) {
+	d, err := newDecoder(content)
+	if err != nil {
+		return nil, err
+	}
+
+	if !d.skipBlank() {
+		return nil, nil
+	}
+
+	value, err := d.parseNode(d.lines[d.pos].indent)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.skipBlank() {
+		return nil, d.errorf("unexpected content %q", d.lines[d.pos].text)
+	}
+	return value, nil
+}
+
+// Unmarshal decodes YAML content into v using the same field mapping as
+// encoding/json (json struct tags).
+func Unmarshal(data []byte, v interface{}) error {
+	value, err := Decode(string(data))
+	if err != nil {
+		return err
+	}
+
+	intermediate, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("yaml: %w", err)
+	}
+	if err := json.Unmarshal(intermediate, v); err != nil {
+		return fmt.Errorf("yaml: %w", err)
+	}
+	return nil
+}
+
+// newDecoder splits content into lines and rejects tab indentation.
+func newDecoder(content string) (*decoder, error) {
+	content = strings.TrimPrefix(content, "\uFEFF")
+	rawLines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	d := &decoder{}
+	for i, raw := range rawLines {
+		trimmed := strings.TrimLeft(raw, " ")
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, &SyntaxError{Line: i + 1, Message: "tabs are not allowed for indentation"}
+		}
+
+		text := strings.TrimRight(stripComment(trimmed), " \t")
+		if i == 0 || len(d.lines) == 0 {
+			// A leading document marker is allowed and ignored
+			if text == "---" {
+				text = ""
+			}
+		}
+		if text == "..." {
+			break
+		}
+		if text == "---" {
+			return nil, &SyntaxError{Line: i + 1, Message: "multiple documents are not supported"}
+		}
+
+		d.lines = append(d.lines, line{
+			num:    i + 1,
+			indent: len(raw) - len(trimmed),
+			text:   text,
+			raw:    raw,
+		})
+	}
+	return d, nil
+}
+
+// stripComment removes a trailing comment that is not inside quotes.
+func stripComment(s string) string {
+	inSingle, inDouble := false, false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\'':
+			if !inDouble {
+				inSingle = !inSingle
+			}
+		case '"':
+			if !inSingle && (i == 0 || s[i-1] != '\\') {
+				inDouble = !inDouble
+			}
+		case '#':
+			if !inSingle && !inDouble && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t') {
+				return s[:i]
+			}
+		}
+	}
+	return s
+}
+
+// skipBlank advances past empty lines and reports whether a line remains.
+func (d *decoder) skipBlank() bool {
+	for d.pos < len(d.lines) && d.lines[d.pos].text == "" {
+		d.pos++
+	}
+	return d.pos < len(d.lines)
+}
+
+// errorf returns a syntax error for the current line.
+func (d *decoder) errorf(format string, args ...interface{}) error {
+	num := 0
+	if d.pos < len(d.lines) {
+		num = d.lines[d.pos].num
+	} else if len(d.lines) > 0 {
+		num = d.lines[len(d.lines)-1].num
+	}
+	return &SyntaxError{Line: num, Message: fmt.Sprintf(format, args...)}
+}
+
+// parseNode parses the block node starting at the current line.
+func (d *decoder) parseNode(indent int) (interface{}, error) {
+	l := d.lines[d.pos]
+
+	if isSequenceItem(l.text) {
+		return d.parseSequence(l.indent)
+	}
+	if _, _, ok := splitKey(l.text); ok {
+		return d.parseMapping(l.indent)
+	}
+
+	d.pos++
+	return parseInlineValue(l.text, l.num)
+}
+
+// parseMapping parses consecutive "key: value" lines at the given indent.
+func (d *decoder) parseMapping(indent int) (interface{}, error) {
+	result := make(map[string]interface{})
+
+	for d.skipBlank() {
+		l := d.lines[d.pos]
+		if l.indent < indent {
+			break
+		}
+		if l.indent > indent {
+			return nil, d.errorf("unexpected indentation")
+		}
+
+		key, rest, ok := splitKey(l.text)
+		if !ok {
+			if isSequenceItem(l.text) {
+				break
+			}
+			return nil, d.errorf("expected a mapping key, found %q", l.text)
+		}
+		if _, exists := result[key]; exists {
+			return nil, d.errorf("duplicate key %q", key)
+		}
+		d.pos++
+
+		value, err := d.parseValue(rest, indent, l.num)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = value
+	}
+
+	return result, nil
+}
+
+// parseSequence parses consecutive "- item" lines at the given indent.
+func (d *decoder) parseSequence(indent int) (interface{}, error) {
+	result := []interface{}{}
+
+	for d.skipBlank() {
+		l := d.lines[d.pos]
+		if l.indent != indent || !isSequenceItem(l.text) {
+			if l.indent > indent {
+				return nil, d.errorf("unexpected indentation")
+			}
+			break
+		}
+
+		rest := strings.TrimPrefix(strings.TrimPrefix(l.text, "-"), " ")
+		offset := len(l.text) - len(rest)
+		rest = strings.TrimLeft(rest, " ")
+
+		if rest == "" {
+			// Nested block on the following lines
+			d.pos++
+			value, err := d.parseValue("", indent, l.num)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, value)
+			continue
+		}
+
+		_, _, isKey := splitKey(rest)
+		if isKey || isSequenceItem(rest) {
+			// Compact nested node: "- key: value" continues at the item's column
+			d.lines[d.pos] = line{
+				num:    l.num,
+				indent: l.indent + offset + (len(l.text) - offset - len(rest)),
+				text:   rest,
+				raw:    l.raw,
+			}
+			value, err := d.parseNode(d.lines[d.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, value)
+			continue
+		}
+
+		d.pos++
+		value, err := d.parseValue(rest, indent, l.num)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+	}
+
+	return result, nil
+}
+
+// parseValue parses the value following a key or sequence marker.
+// An empty inline value introduces a nested block (or null).
+func (d *decoder) parseValue(rest string, parentIndent, num int) (interface{}, error) {
+	if rest == "" {
+		if !d.skipBlank() {
+			return nil, nil
+		}
+		next := d.lines[d.pos]
+		if next.indent > parentIndent {
+			return d.parseNode(next.indent)
+		}
+		// Sequences may be indented at the same level as their parent key
+		if next.indent == parentIndent && isSequenceItem(next.text) {
+			return d.parseSequence(next.indent)
+		}
+		return nil, nil
+	}
+
+	if rest[0] == '|' || rest[0] == '>' {
+		return d.parseBlockScalar(rest, parentIndent, num)
+	}
+	if rest[0] == '&' || rest[0] == '*' || rest[0] == '!' {
+		return nil, &SyntaxError{Line: num, Message: "anchors, aliases and tags are not supported"}
+	}
+
+	return parseInlineValue(rest, num)
+}
+
+// parseBlockScalar parses a literal (|) or folded (>) block scalar.
+func (d *decoder) parseBlockScalar(header string, parentIndent, num int) (interface{}, error) {
+	folded := header[0] == '>'
+	chomp := byte(0)
+	blockIndent := -1
+	for _, c := range header[1:] {
+		switch {
+		case c == '-' || c == '+':
+			chomp = byte(c)
+		case c >= '1' && c <= '9':
+			// Explicit indentation indicator, relative to the parent node
+			blockIndent = parentIndent + int(c-'0')
+		case c == ' ':
+		default:
+			return nil, &SyntaxError{Line: num, Message: fmt.Sprintf("invalid block scalar header %q", header)}
+		}
+	}
+
+	var body []string
+	for d.pos < len(d.lines) {
+		l := d.lines[d.pos]
+		if strings.TrimSpace(l.raw) == "" {
+			body = append(body, "")
+			d.pos++
+			continue
+		}
+		if l.indent <= parentIndent || (blockIndent >= 0 && l.indent < blockIndent) {
+			break
+		}
+		if blockIndent < 0 {
+			blockIndent = l.indent
+		}
+		body = append(body, l.raw[blockIndent:])
+		d.pos++
+	}
+
+	// Trailing blank lines belong to chomping, not content
+	trailing := 0
+	for len(body) > 0 && body[len(body)-1] == "" {
+		body = body[:len(body)-1]
+		trailing++
+	}
+	var text string
+	if folded {
+		var sb strings.Builder
+		for i, b := range body {
+			if i > 0 {
+				if b == "" || body[i-1] == "" || strings.HasPrefix(b, " ") {
+					sb.WriteString("\n")
+				} else {
+					sb.WriteString(" ")
+				}
+			}
+			sb.WriteString(b)
+		}
+		text = sb.String()
+	} else {
+		text = strings.Join(body, "\n")
+	}
+
+	switch chomp {
+	case '-':
+	case '+':
+		text += "\n" + strings.Repeat("\n", trailing)
+	default:
+		if len(body) > 0 {
+			text += "\n"
+		}
+	}
+	return text, nil
+}
+
+// isSequenceItem reports whether text starts a block sequence entry.
+func isSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitKey splits "key: value" outside quotes and flow collections.
+func splitKey(text string) (key, rest string, ok bool) {
+	if text == "" || text[0] == '[' || text[0] == '{' {
+		return "", "", false
+	}
+
+	if text[0] == '"' || text[0] == '\'' {
+		end := closingQuote(text)
+		if end < 0 || end+1 >= len(text) || text[end+1] != ':' {
+			return "", "", false
+		}
+		if end+2 < len(text) && text[end+2] != ' ' {
+			return "", "", false
+		}
+		k, err := parseQuoted(text[:end+1])
+		if err != nil {
+			return "", "", false
+		}
+		return k, strings.TrimSpace(text[end+2:]), true
+	}
+
+	for i := 0; i < len(text); i++ {
+		if text[i] == ':' && (i+1 == len(text) || text[i+1] == ' ') {
+			return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// closingQuote returns the index of the quote closing the scalar at text[0].
+func closingQuote(text string) int {
+	q := text[0]
+	for i := 1; i < len(text); i++ {
+		if q == '"' && text[i] == '\\' {
+			i++
+			continue
+		}
+		if text[i] == q {
+			if q == '\'' && i+1 < len(text) && text[i+1] == '\'' {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// parseInlineValue parses a single-line scalar or flow collection.
+func parseInlineValue(text string, num int) (interface{}, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, nil
+	}
+
+	switch text[0] {
+	case '"', '\'':
+		end := closingQuote(text)
+		if end != len(text)-1 {
+			return nil, &SyntaxError{Line: num, Message: fmt.Sprintf("invalid quoted scalar %s", text)}
+		}
+		s, err := parseQuoted(text)
+		if err != nil {
+			return nil, &SyntaxError{Line: num, Message: err.Error()}
+		}
+		return s, nil
+	case '[':
+		return parseFlowSequence(text, num)
+	case '{':
+		return parseFlowMapping(text, num)
+	case '&', '*', '!':
+		return nil, &SyntaxError{Line: num, Message: "anchors, aliases and tags are not supported"}
+	}
+
+	return parsePlainScalar(text), nil
+}
+
+// parseQuoted unquotes a single- or double-quoted scalar.
+func parseQuoted(text string) (string, error) {
+	if text[0] == '\'' {
+		return strings.ReplaceAll(text[1:len(text)-1], "''", "'"), nil
+	}
+	s, err := strconv.Unquote(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid double-quoted scalar %s", text)
+	}
+	return s, nil
+}
+
+// parsePlainScalar resolves null, booleans and numbers; everything else is a string.
+func parsePlainScalar(text string) interface{} {
+	switch text {
+	case "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	}
+	if i, err := strconv.Atoi(text); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(text, 64); err == nil && strings.ContainsAny(text, ".eE") && !strings.ContainsAny(text, "xX_") {
+		return f
+	}
+	return text
+}
+
+// splitFlow splits the inside of a flow collection on top-level commas.
+func splitFlow(inner string) []string {
+	var parts []string
+	depth := 0
+	start := 0
+	inSingle, inDouble := false, false
+
+	for i := 0; i < len(inner); i++ {
+		c := inner[i]
+		switch {
+		case c == '\'' && !inDouble:
+			inSingle = !inSingle
+		case c == '"' && !inSingle && (i == 0 || inner[i-1] != '\\'):
+			inDouble = !inDouble
+		case inSingle || inDouble:
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, inner[start:i])
+			start = i + 1
+		}
+	}
+	if strings.TrimSpace(inner[start:]) != "" {
+		parts = append(parts, inner[start:])
+	}
+	return parts
+}
+
+// parseFlowSequence parses "[a, b, c]".
+func parseFlowSequence(text string, num int) (interface{}, error) {
+	if !strings.HasSuffix(text, "]") {
+		return nil, &SyntaxError{Line: num, Message: "unterminated flow sequence"}
+	}
+	result := []interface{}{}
+	for _, part := range splitFlow(text[1 : len(text)-1]) {
+		value, err := parseInlineValue(part, num)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+	}
+	return result, nil
+}
+
+// parseFlowMapping parses "{a: 1, b: 2}".
+func parseFlowMapping(text string, num int) (interface{}, error) {
+	if !strings.HasSuffix(text, "}") {
+		return nil, &SyntaxError{Line: num, Message: "unterminated flow mapping"}
+	}
+	result := make(map[string]interface{})
+	for _, part := range splitFlow(text[1 : len(text)-1]) {
+		key, rest, ok := splitKey(strings.TrimSpace(part))
+		if !ok {
+			return nil, &SyntaxError{Line: num, Message: fmt.Sprintf("invalid flow mapping entry %q", part)}
+		}
+		value, err := parseInlineValue(rest, num)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = value
+	}
+	return result, nil
+}
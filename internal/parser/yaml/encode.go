@@ -0,0 +1,348 @@
+package yaml
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Marshal encodes v as block-style YAML. Struct fields are named after their
+// json tags (honouring "-" and omitempty), map keys are sorted and multi-line
+// strings are written as literal block scalars.
+func Marshal(v interface{}) ([]byte, error) {
+	var sb strings.Builder
+	e := &encoder{sb: &sb}
+	if err := e.encodeTop(reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return []byte(sb.String()), nil
+}
+
+// encoder writes YAML into a string builder.
+type encoder struct {
+	sb *strings.Builder
+}
+
+// field is a named struct or map entry to encode.
+type field struct {
+	name  string
+	value reflect.Value
+}
+
+// encodeTop encodes the document root.
+func (e *encoder) encodeTop(v reflect.Value) error {
+	v = indirect(v)
+	if !v.IsValid() {
+		e.sb.WriteString("null\n")
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map:
+		fields, err := collectFields(v)
+		if err != nil {
+			return err
+		}
+		if len(fields) == 0 {
+			e.sb.WriteString("{}\n")
+			return nil
+		}
+		return e.encodeFields(fields, 0)
+	case reflect.Slice, reflect.Array:
+		if v.Len() == 0 {
+			e.sb.WriteString("[]\n")
+			return nil
+		}
+		return e.encodeSequence(v, 0)
+	default:
+		s, err := scalar(v, 0)
+		if err != nil {
+			return err
+		}
+		e.sb.WriteString(s + "\n")
+		return nil
+	}
+}
+
+// encodeFields writes "key: value" entries at the given indent.
+func (e *encoder) encodeFields(fields []field, indent int) error {
+	pad := strings.Repeat(" ", indent)
+	for _, f := range fields {
+		e.sb.WriteString(pad + quoteKey(f.name) + ":")
+		if err := e.encodeValue(f.value, indent); err != nil {
+			return fmt.Errorf("%s: %w", f.name, err)
+		}
+	}
+	return nil
+}
+
+// encodeSequence writes "- item" entries at the given indent.
+func (e *encoder) encodeSequence(v reflect.Value, indent int) error {
+	pad := strings.Repeat(" ", indent)
+	for i := 0; i < v.Len(); i++ {
+		item := indirect(v.Index(i))
+		e.sb.WriteString(pad + "-")
+
+		if item.IsValid() && (item.Kind() == reflect.Struct || item.Kind() == reflect.Map) {
+			fields, err := collectFields(item)
+			if err != nil {
+				return err
+			}
+			if len(fields) == 0 {
+				e.sb.WriteString(" {}\n")
+				continue
+			}
+			// First entry shares the dash line, the rest align under it
+			var first strings.Builder
+			sub := &encoder{sb: &first}
+			if err := sub.encodeFields(fields, indent+2); err != nil {
+				return err
+			}
+			e.sb.WriteString(" " + strings.TrimPrefix(first.String(), pad+"  "))
+			continue
+		}
+
+		if err := e.encodeValue(item, indent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeValue writes the value part after "key:" or "-".
+func (e *encoder) encodeValue(v reflect.Value, indent int) error {
+	v = indirect(v)
+	if !v.IsValid() {
+		e.sb.WriteString(" null\n")
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map:
+		if isTextMarshaler(v) {
+			break
+		}
+		if v.Kind() == reflect.Map && v.IsNil() {
+			e.sb.WriteString(" null\n")
+			return nil
+		}
+		fields, err := collectFields(v)
+		if err != nil {
+			return err
+		}
+		if len(fields) == 0 {
+			e.sb.WriteString(" {}\n")
+			return nil
+		}
+		e.sb.WriteString("\n")
+		return e.encodeFields(fields, indent+2)
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+		// Like encoding/json, nil slices are null and empty slices are []
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			e.sb.WriteString(" null\n")
+			return nil
+		}
+		if v.Len() == 0 {
+			e.sb.WriteString(" []\n")
+			return nil
+		}
+		e.sb.WriteString("\n")
+		return e.encodeSequence(v, indent+2)
+	}
+
+	s, err := scalar(v, indent+2)
+	if err != nil {
+		return err
+	}
+	e.sb.WriteString(" " + s + "\n")
+	return nil
+}
+
+// collectFields returns the encodable entries of a struct or map.
+func collectFields(v reflect.Value) ([]field, error) {
+	var fields []field
+
+	if v.Kind() == reflect.Map {
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("yaml: unsupported map key type %s", v.Type().Key())
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, k := range keys {
+			fields = append(fields, field{name: k.String(), value: v.MapIndex(k)})
+		}
+		return fields, nil
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+
+		name := sf.Name
+		omitEmpty := false
+		if tag, ok := sf.Tag.Lookup("json"); ok {
+			if tag == "-" {
+				continue
+			}
+			parts := strings.Split(tag, ",")
+			if parts[0] != "" {
+				name = parts[0]
+			}
+			for _, opt := range parts[1:] {
+				if opt == "omitempty" {
+					omitEmpty = true
+				}
+			}
+		}
+
+		fv := v.Field(i)
+		if omitEmpty && fv.IsZero() {
+			continue
+		}
+		if omitEmpty && (fv.Kind() == reflect.Slice || fv.Kind() == reflect.Map) && fv.Len() == 0 {
+			continue
+		}
+		fields = append(fields, field{name: name, value: fv})
+	}
+	return fields, nil
+}
+
+// indirect dereferences pointers and interfaces.
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// isTextMarshaler reports whether v encodes itself as text.
+func isTextMarshaler(v reflect.Value) bool {
+	_, ok := v.Interface().(encoding.TextMarshaler)
+	return ok
+}
+
+// scalar formats a scalar value. Multi-line strings become literal blocks
+// indented at blockIndent.
+func scalar(v reflect.Value, blockIndent int) (string, error) {
+	if v.CanInterface() {
+		if tm, ok := v.Interface().(encoding.TextMarshaler); ok {
+			text, err := tm.MarshalText()
+			if err != nil {
+				return "", err
+			}
+			return quoteString(string(text), blockIndent), nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return quoteString(v.String(), blockIndent), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64), nil
+	}
+	return "", fmt.Errorf("yaml: unsupported type %s", v.Type())
+}
+
+// quoteString returns s as a plain, quoted or literal block scalar.
+func quoteString(s string, blockIndent int) string {
+	if strings.Contains(s, "\n") && isPrintable(s) {
+		return literalBlock(s, blockIndent)
+	}
+	if needsQuotes(s) {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// literalBlock formats a multi-line string as "|" with the right chomping.
+func literalBlock(s string, indent int) string {
+	header := "|"
+	body := s
+	switch {
+	case !strings.HasSuffix(s, "\n"):
+		header = "|-"
+	case strings.HasSuffix(s, "\n\n"):
+		header = "|+"
+		body = strings.TrimSuffix(s, "\n")
+	default:
+		body = strings.TrimSuffix(s, "\n")
+	}
+	// Leading spaces would be mistaken for the block indentation
+	if strings.HasPrefix(body, " ") {
+		header += "2"
+	}
+
+	pad := strings.Repeat(" ", indent)
+	var sb strings.Builder
+	sb.WriteString(header)
+	for _, l := range strings.Split(body, "\n") {
+		sb.WriteString("\n")
+		if l != "" {
+			sb.WriteString(pad + l)
+		}
+	}
+	return sb.String()
+}
+
+// isPrintable reports whether s can be written as a block scalar.
+func isPrintable(s string) bool {
+	for _, r := range s {
+		if r == '\t' || r == '\r' || (r < ' ' && r != '\n') {
+			return false
+		}
+	}
+	for _, l := range strings.Split(s, "\n") {
+		if strings.HasSuffix(l, " ") {
+			return false
+		}
+	}
+	return true
+}
+
+// needsQuotes reports whether a plain scalar would be read back differently.
+func needsQuotes(s string) bool {
+	if s == "" || s != strings.TrimSpace(s) {
+		return true
+	}
+	if _, ok := parsePlainScalar(s).(string); !ok {
+		return true
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") {
+		return true
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return true
+	}
+	for _, r := range s {
+		if r < ' ' || r == 0x7f || r == 0xFEFF {
+			return true
+		}
+	}
+	return s == "---" || s == "..."
+}
+
+// quoteKey quotes a mapping key when needed.
+func quoteKey(k string) string {
+	if needsQuotes(k) {
+		return strconv.Quote(k)
+	}
+	return k
+}
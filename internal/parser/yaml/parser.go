@@ -0,0 +1,59 @@
+package yaml
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/morty/morty/internal/parser"
+)
+
+// Parser implements the parser.Parser interface for YAML files.
+type Parser struct{}
+
+// Ensure Parser implements the parser.Parser interface.
+var _ parser.Parser = (*Parser)(nil)
+
+// NewParser creates a new YAML parser instance.
+func NewParser() *Parser {
+	return &Parser{}
+}
+
+// Parse reads from r and returns the decoded document as generic values.
+func (p *Parser) Parse(ctx context.Context, r io.Reader) (*parser.ParseResult, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content: %w", err)
+	}
+
+	value, err := Decode(string(content))
+	if err != nil {
+		return &parser.ParseResult{
+			Type:    parser.FileTypeYAML,
+			Content: nil,
+			Errors:  []error{err},
+		}, nil
+	}
+
+	return &parser.ParseResult{
+		Type:    parser.FileTypeYAML,
+		Content: value,
+		Errors:  nil,
+	}, nil
+}
+
+// ParseString parses content from a string.
+func (p *Parser) ParseString(ctx context.Context, content string) (*parser.ParseResult, error) {
+	return p.Parse(ctx, strings.NewReader(content))
+}
+
+// Supports returns true if this parser can handle the given file type.
+func (p *Parser) Supports(fileType parser.FileType) bool {
+	return fileType == parser.FileTypeYAML
+}
+
+// FileType returns the file type this parser handles.
+func (p *Parser) FileType() parser.FileType {
+	return parser.FileTypeYAML
+}
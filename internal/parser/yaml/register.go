@@ -0,0 +1,20 @@
+package yaml
+
+import (
+	"github.com/morty/morty/internal/parser"
+)
+
+// Register registers the YAML parser with the given factory.
+func Register(f *parser.Factory) error {
+	return f.Register(parser.FileTypeYAML, NewParser())
+}
+
+// RegisterWithDefaults creates a factory with default extension mappings
+// and registers the YAML parser.
+func RegisterWithDefaults() (*parser.Factory, error) {
+	f := parser.NewFactoryWithDefaults()
+	if err := Register(f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
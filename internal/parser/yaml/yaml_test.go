@@ -0,0 +1,210 @@
+package yaml
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/morty/morty/internal/parser"
+)
+
+// TestDecode tests decoding of the supported YAML subset.
+func TestDecode(t *testing.T) {
+	input := `---
+# comment
+name: demo   # trailing comment
+count: 3
+ratio: 0.5
+enabled: true
+nothing: ~
+quoted: "a: b # c"
+single: 'it''s'
+flow: [a, "b, c", 1]
+map: {x: 1, y: two}
+empty_list: []
+items:
+- first
+- key: k1
+  value: v1
+  nested:
+    - n1
+-
+  key: k2
+literal: |
+  line one
+    indented
+  line three
+stripped: |-
+  no newline
+folded: >
+  folded
+  text
+
+  new paragraph
+`
+
+	got, err := Decode(input)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"name":       "demo",
+		"count":      3,
+		"ratio":      0.5,
+		"enabled":    true,
+		"nothing":    nil,
+		"quoted":     "a: b # c",
+		"single":     "it's",
+		"flow":       []interface{}{"a", "b, c", 1},
+		"map":        map[string]interface{}{"x": 1, "y": "two"},
+		"empty_list": []interface{}{},
+		"items": []interface{}{
+			"first",
+			map[string]interface{}{"key": "k1", "value": "v1", "nested": []interface{}{"n1"}},
+			map[string]interface{}{"key": "k2"},
+		},
+		"literal":  "line one\n  indented\nline three\n",
+		"stripped": "no newline",
+		"folded":   "folded text\n\nnew paragraph\n",
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode mismatch:\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
+// TestDecodeErrors tests that malformed input reports a line number.
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		line  int
+	}{
+		{"tab indentation", "a:\n\tb: 1", 2},
+		{"duplicate key", "a: 1\na: 2", 2},
+		{"bad indentation", "a: 1\n   b: 2", 2},
+		{"alias", "a: *ref", 1},
+		{"unterminated quote", "a: \"open", 1},
+		{"multiple documents", "a: 1\n---\nb: 2", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(tt.input)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Expected *SyntaxError, got %v", err)
+			}
+			if syntaxErr.Line != tt.line {
+				t.Errorf("Expected line %d, got %d (%v)", tt.line, syntaxErr.Line, err)
+			}
+		})
+	}
+}
+
+type sample struct {
+	Name    string            `json:"name"`
+	Count   int               `json:"count"`
+	Tags    []string          `json:"tags,omitempty"`
+	Body    string            `json:"body,omitempty"`
+	Items   []sampleItem      `json:"items"`
+	Labels  map[string]string `json:"labels,omitempty"`
+	Skipped string            `json:"-"`
+}
+
+type sampleItem struct {
+	ID   int    `json:"id"`
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+}
+
+// TestMarshalRoundTrip tests that Marshal output decodes back unchanged.
+func TestMarshalRoundTrip(t *testing.T) {
+	in := sample{
+		Name:  "plan: demo",
+		Count: 2,
+		Tags:  []string{"true", "42", "- dash", "无", ""},
+		Body:  "  indented first line\nsecond line\n",
+		Items: []sampleItem{
+			{ID: 1, Text: "do #1", Done: true},
+			{ID: 2, Text: "multi\nline"},
+		},
+		Labels:  map[string]string{"b": "2", "a": "1"},
+		Skipped: "ignored",
+	}
+
+	data, err := Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var out sample
+	if err := Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal failed: %v\n%s", err, data)
+	}
+
+	in.Skipped = ""
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("Round trip mismatch:\nin:  %#v\nout: %#v\nyaml:\n%s", in, out, data)
+	}
+
+	if !strings.Contains(string(data), "- id: 1\n") {
+		t.Errorf("Expected compact sequence of mappings, got:\n%s", data)
+	}
+	if strings.Index(string(data), "a: \"1\"") > strings.Index(string(data), "b: \"2\"") {
+		t.Errorf("Expected sorted map keys, got:\n%s", data)
+	}
+}
+
+// TestMarshalEmpty tests empty collections and omitted fields.
+func TestMarshalEmpty(t *testing.T) {
+	data, err := Marshal(sample{})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	want := "name: \"\"\ncount: 0\nitems: null\n"
+	if string(data) != want {
+		t.Errorf("Expected %q, got %q", want, string(data))
+	}
+}
+
+// TestParser tests the parser.Parser implementation.
+func TestParser(t *testing.T) {
+	p := NewParser()
+	if !p.Supports(parser.FileTypeYAML) || p.Supports(parser.FileTypeJSON) {
+		t.Error("Expected parser to support only YAML")
+	}
+
+	result, err := p.ParseString(context.Background(), "a: 1")
+	if err != nil || len(result.Errors) != 0 {
+		t.Fatalf("ParseString failed: %v %v", err, result.Errors)
+	}
+	if !reflect.DeepEqual(result.Content, map[string]interface{}{"a": 1}) {
+		t.Errorf("Unexpected content: %#v", result.Content)
+	}
+
+	result, _ = p.ParseString(context.Background(), "a: [1")
+	if len(result.Errors) == 0 {
+		t.Error("Expected parse error for malformed input")
+	}
+}
+
+// TestRegister tests registration with the parser factory.
+func TestRegister(t *testing.T) {
+	f := parser.NewFactoryWithDefaults()
+	if err := Register(f); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	p, err := f.GetByExtension("plan.yml")
+	if err != nil {
+		t.Fatalf("GetByExtension failed: %v", err)
+	}
+	if p.FileType() != parser.FileTypeYAML {
+		t.Errorf("Expected YAML parser, got %s", p.FileType())
+	}
+}
@@ -0,0 +1,348 @@
+// Package redact masks secrets, such as API keys, tokens and the values of
+// sensitive environment variables, before text reaches log files,
+// transcripts or the terminal.
+//
+// A process-wide default redactor is shared by the logging package, the
+// AI CLI execution and conversation logs and the executor's job logs.
+// It starts with the built-in rules and is replaced with SetDefault once
+// the configuration is loaded.
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// Rule is a named expression whose matches are masked. If the expression
+// has a group named "secret", only that group is masked, so that
+// "password: hunter22" keeps its key.
+type Rule struct {
+	// Name identifies the rule in the mask ("[REDACTED:name]").
+	Name string
+	// Pattern is the compiled expression.
+	Pattern *regexp.Regexp
+}
+
+// Config configures a Redactor.
+type Config struct {
+	// Rules are additional rules applied after the built-in ones.
+	Rules []Rule
+	// EnvVars are names, or glob patterns such as "*_TOKEN", of environment
+	// variables holding secrets, in addition to DefaultEnvVars.
+	EnvVars []string
+	// Environ is the environment secret values are read from
+	// (os.Environ() when nil).
+	Environ []string
+}
+
+// MinSecretLength is the shortest environment variable value treated as a
+// secret; shorter values such as "1" or "true" would mask ordinary text.
+const MinSecretLength = 8
+
+// DefaultEnvVars are glob patterns of environment variable names whose
+// values are secrets.
+var DefaultEnvVars = []string{
+	"*_API_KEY",
+	"*_TOKEN",
+	"*_SECRET",
+	"*_SECRET_*",
+	"*PASSWORD*",
+	"*PASSWD*",
+	"*_CREDENTIALS",
+	"*_PRIVATE_KEY",
+	"*_ACCESS_KEY",
+	"*_ACCESS_KEY_ID",
+}
+
+// DefaultRules returns the built-in rules.
+func DefaultRules() []Rule {
+	return []Rule{
+		{Name: "private-key", Pattern: regexp.MustCompile(`-----BEGIN ([A-Z]+ )?PRIVATE KEY-----[\s\S]*?(-----END ([A-Z]+ )?PRIVATE KEY-----|\z)`)},
+		{Name: "aws-access-key", Pattern: regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+		{Name: "aws-secret-key", Pattern: regexp.MustCompile(`(?i)aws_?secret_?access_?key\s*[:=]\s*["']?(?P<secret>[A-Za-z0-9/+=]{40})`)},
+		{Name: "github-token", Pattern: regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`)},
+		{Name: "github-pat", Pattern: regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{60,}\b`)},
+		{Name: "anthropic-api-key", Pattern: regexp.MustCompile(`\bsk-ant-[A-Za-z0-9_\-]{20,}`)},
+		{Name: "openai-api-key", Pattern: regexp.MustCompile(`\bsk-(proj-)?[A-Za-z0-9]{32,}\b`)},
+		{Name: "google-api-key", Pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_\-]{35}\b`)},
+		{Name: "slack-token", Pattern: regexp.MustCompile(`\bxox[abposr]-[0-9A-Za-z\-]{10,}`)},
+		{Name: "stripe-key", Pattern: regexp.MustCompile(`\b[sr]k_live_[0-9A-Za-z]{24,}\b`)},
+		{Name: "jwt", Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]{10,}\.[A-Za-z0-9_\-]{10,}\.[A-Za-z0-9_\-]{10,}`)},
+		{Name: "bearer-token", Pattern: regexp.MustCompile(`(?i)\bbearer\s+(?P<secret>[A-Za-z0-9\-._~+/]{16,}=*)`)},
+		{Name: "url-credentials", Pattern: regexp.MustCompile(`\b[a-z][a-z0-9+.\-]*://[^/\s:@]+:(?P<secret>[^/\s@]{3,})@`)},
+		{Name: "generic-secret", Pattern: regexp.MustCompile(`(?i)\b(api[_-]?key|secret|passwd|password|access[_-]?token|auth[_-]?token)\b["']?\s*[:=]\s*["']?(?P<secret>[^"'\s,;]{8,})`)},
+	}
+}
+
+// CompileRule compiles a user-defined rule.
+func CompileRule(name, pattern string) (Rule, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Rule{}, fmt.Errorf("invalid redaction pattern %q: %w", name, err)
+	}
+	return Rule{Name: name, Pattern: re}, nil
+}
+
+// secretValue is a literal secret, such as the value of an environment
+// variable.
+type secretValue struct {
+	name  string
+	value string
+}
+
+// Redactor masks secrets in text. A nil Redactor masks nothing.
+type Redactor struct {
+	rules      []Rule
+	envPattern []string
+	values     []secretValue
+}
+
+// New creates a redactor with the built-in rules and environment variable
+// patterns plus the configured ones.
+func New(cfg Config) *Redactor {
+	r := &Redactor{
+		rules:      append(DefaultRules(), cfg.Rules...),
+		envPattern: append(append([]string(nil), DefaultEnvVars...), cfg.EnvVars...),
+	}
+
+	environ := cfg.Environ
+	if environ == nil {
+		environ = os.Environ()
+	}
+	for _, kv := range environ {
+		name, value, ok := strings.Cut(kv, "=")
+		if ok && r.IsSensitiveEnv(name) {
+			r.addValue(name, value)
+		}
+	}
+	return r
+}
+
+// WithValue returns a copy of the redactor that also masks the literal
+// secret value as "[REDACTED:name]". Values shorter than MinSecretLength
+// are ignored.
+func (r *Redactor) WithValue(name, value string) *Redactor {
+	if r == nil {
+		return nil
+	}
+	c := *r
+	c.values = append([]secretValue(nil), r.values...)
+	c.addValue(name, value)
+	return &c
+}
+
+func (r *Redactor) addValue(name, value string) {
+	if len(value) < MinSecretLength {
+		return
+	}
+	for _, v := range r.values {
+		if v.value == value {
+			return
+		}
+	}
+	r.values = append(r.values, secretValue{name: name, value: value})
+	// Longest first, so a secret containing another is masked whole
+	sort.SliceStable(r.values, func(i, j int) bool {
+		return len(r.values[i].value) > len(r.values[j].value)
+	})
+}
+
+// IsSensitiveEnv reports whether the environment variable name holds a
+// secret.
+func (r *Redactor) IsSensitiveEnv(name string) bool {
+	if r == nil {
+		return false
+	}
+	upper := strings.ToUpper(name)
+	for _, pattern := range r.envPattern {
+		if ok, _ := path.Match(strings.ToUpper(pattern), upper); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// mask is the replacement of a secret.
+func mask(name string) string {
+	return "[REDACTED:" + name + "]"
+}
+
+// String masks the secrets in s.
+func (r *Redactor) String(s string) string {
+	if r == nil || s == "" {
+		return s
+	}
+	for _, v := range r.values {
+		if strings.Contains(s, v.value) {
+			s = strings.ReplaceAll(s, v.value, mask(v.name))
+		}
+	}
+	for _, rule := range r.rules {
+		s = replaceRule(rule, s)
+	}
+	return s
+}
+
+// isMask reports whether a match is an earlier mask, such as the value of
+// "password: [REDACTED:generic-secret]".
+func isMask(match string) bool {
+	return strings.HasPrefix(match, "[REDACTED:")
+}
+
+// replaceRule masks the matches of rule in s, or only their "secret" group.
+func replaceRule(rule Rule, s string) string {
+	group := rule.Pattern.SubexpIndex("secret")
+	matches := rule.Pattern.FindAllStringSubmatchIndex(s, -1)
+	if matches == nil {
+		return s
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if group > 0 && m[2*group] >= 0 {
+			start, end = m[2*group], m[2*group+1]
+		}
+		if isMask(s[start:end]) {
+			continue
+		}
+		sb.WriteString(s[last:start])
+		sb.WriteString(mask(rule.Name))
+		last = end
+	}
+	sb.WriteString(s[last:])
+	return sb.String()
+}
+
+// Env returns a copy of env with the values of sensitive variables, and
+// secrets inside other values, masked.
+func (r *Redactor) Env(env map[string]string) map[string]string {
+	if r == nil || env == nil {
+		return env
+	}
+	result := make(map[string]string, len(env))
+	for name, value := range env {
+		if r.IsSensitiveEnv(name) && value != "" {
+			result[name] = mask(name)
+		} else {
+			result[name] = r.String(value)
+		}
+	}
+	return result
+}
+
+// Strings masks the secrets in each element of values.
+func (r *Redactor) Strings(values []string) []string {
+	if r == nil || values == nil {
+		return values
+	}
+	result := make([]string, len(values))
+	for i, v := range values {
+		result[i] = r.String(v)
+	}
+	return result
+}
+
+// JSON masks the secrets in the string values of a JSON document, keeping
+// it valid JSON. Data that is not JSON is masked as text.
+func (r *Redactor) JSON(data []byte) []byte {
+	if r == nil {
+		return data
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return []byte(r.String(string(data)))
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(r.value(doc)); err != nil {
+		return []byte(r.String(string(data)))
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
+
+// value masks the strings in a decoded JSON value.
+func (r *Redactor) value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return r.String(v)
+	case []interface{}:
+		for i := range v {
+			v[i] = r.value(v[i])
+		}
+	case map[string]interface{}:
+		for key := range v {
+			v[key] = r.value(v[key])
+		}
+	}
+	return v
+}
+
+// Leaks returns the names of the rules and secret values that still match
+// data. It is used by the redaction test mode to prove that written files
+// hold no secrets.
+func (r *Redactor) Leaks(data []byte) []string {
+	if r == nil {
+		return nil
+	}
+	var leaks []string
+	for _, v := range r.values {
+		if bytes.Contains(data, []byte(v.value)) {
+			leaks = append(leaks, v.name)
+		}
+	}
+	for _, rule := range r.rules {
+		text := string(data)
+		if replaceRule(rule, text) != text {
+			leaks = append(leaks, rule.Name)
+		}
+	}
+	return leaks
+}
+
+var defaultRedactor atomic.Pointer[Redactor]
+
+func init() {
+	defaultRedactor.Store(New(Config{}))
+}
+
+// SetDefault replaces the process-wide redactor. A nil redactor disables
+// redaction.
+func SetDefault(r *Redactor) {
+	defaultRedactor.Store(r)
+}
+
+// Default returns the process-wide redactor (nil when disabled).
+func Default() *Redactor {
+	return defaultRedactor.Load()
+}
+
+// String masks the secrets in s using the default redactor.
+func String(s string) string {
+	return Default().String(s)
+}
+
+// Env masks the secrets in env using the default redactor.
+func Env(env map[string]string) map[string]string {
+	return Default().Env(env)
+}
+
+// Strings masks the secrets in values using the default redactor.
+func Strings(values []string) []string {
+	return Default().Strings(values)
+}
+
+// JSON masks the secrets in a JSON document using the default redactor.
+func JSON(data []byte) []byte {
+	return Default().JSON(data)
+}
@@ -0,0 +1,63 @@
+package redact
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// maxLineBuffer is the longest partial line held back before it is
+// written without waiting for the newline.
+const maxLineBuffer = 64 * 1024
+
+// Writer masks secrets, using the default redactor, in text written to an
+// underlying writer. Text is held back until a newline so that a secret
+// split across writes is still masked; Flush writes the rest.
+type Writer struct {
+	mu  sync.Mutex
+	w   io.Writer
+	buf []byte
+}
+
+// NewWriter creates a redacting writer.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Write implements io.Writer.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	n := bytes.LastIndexByte(w.buf, '\n') + 1
+	if n == 0 && len(w.buf) > maxLineBuffer {
+		n = len(w.buf)
+	}
+	if n == 0 {
+		return len(p), nil
+	}
+	if err := w.write(w.buf[:n]); err != nil {
+		return 0, err
+	}
+	w.buf = append(w.buf[:0], w.buf[n:]...)
+	return len(p), nil
+}
+
+// Flush writes the text held back.
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) == 0 {
+		return nil
+	}
+	err := w.write(w.buf)
+	w.buf = w.buf[:0]
+	return err
+}
+
+func (w *Writer) write(p []byte) error {
+	_, err := io.WriteString(w.w, String(string(p)))
+	return err
+}
@@ -0,0 +1,168 @@
+package research
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// BM25 parameters.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Index is an in-memory BM25 index over research sections.
+type Index struct {
+	sections []Section
+	terms    []map[string]int // term frequencies per section
+	lengths  []int
+	avgLen   float64
+	df       map[string]int // number of sections containing a term
+}
+
+// Hit is a search result.
+type Hit struct {
+	Section
+	Score float64
+}
+
+// NewIndex indexes sections. Heading terms are counted twice since they
+// summarize the section.
+func NewIndex(sections []Section) *Index {
+	ix := &Index{sections: sections, df: make(map[string]int)}
+	total := 0
+	for _, s := range sections {
+		tokens := append(Tokenize(s.Text), Tokenize(s.Heading)...)
+		tf := make(map[string]int)
+		for _, t := range tokens {
+			tf[t]++
+		}
+		for t := range tf {
+			ix.df[t]++
+		}
+		ix.terms = append(ix.terms, tf)
+		ix.lengths = append(ix.lengths, len(tokens))
+		total += len(tokens)
+	}
+	if len(sections) > 0 {
+		ix.avgLen = float64(total) / float64(len(sections))
+	}
+	return ix
+}
+
+// Build indexes the research files in dir.
+func Build(dir string) (*Index, error) {
+	sections, err := LoadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	return NewIndex(sections), nil
+}
+
+// Len returns the number of indexed sections.
+func (ix *Index) Len() int {
+	return len(ix.sections)
+}
+
+// Sections returns the indexed sections in document order.
+func (ix *Index) Sections() []Section {
+	return ix.sections
+}
+
+// Search returns up to k sections matching query, best first. Sections
+// sharing no term with the query are not returned.
+func (ix *Index) Search(query string, k int) []Hit {
+	queryTerms := make(map[string]bool)
+	for _, t := range Tokenize(query) {
+		queryTerms[t] = true
+	}
+
+	n := float64(len(ix.sections))
+	var hits []Hit
+	for i, tf := range ix.terms {
+		score := 0.0
+		for t := range queryTerms {
+			f := float64(tf[t])
+			if f == 0 {
+				continue
+			}
+			df := float64(ix.df[t])
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			norm := 1 - bm25B + bm25B*float64(ix.lengths[i])/ix.avgLen
+			score += idf * f * (bm25K1 + 1) / (f + bm25K1*norm)
+		}
+		if score > 0 {
+			hits = append(hits, Hit{Section: ix.sections[i], Score: score})
+		}
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+	if k > 0 && len(hits) > k {
+		hits = hits[:k]
+	}
+	return hits
+}
+
+// Tokenize lowercases text and splits it into terms: runs of letters and
+// digits, and overlapping character pairs of Han text, which has no word
+// boundaries. Single Latin letters are dropped.
+func Tokenize(text string) []string {
+	var tokens []string
+	var word []rune
+	var han []rune
+
+	flushWord := func() {
+		if len(word) > 1 || len(word) == 1 && unicode.IsDigit(word[0]) {
+			tokens = append(tokens, string(word))
+		}
+		word = word[:0]
+	}
+	flushHan := func() {
+		if len(han) == 1 {
+			tokens = append(tokens, string(han))
+		}
+		for i := 0; i+1 < len(han); i++ {
+			tokens = append(tokens, string(han[i:i+2]))
+		}
+		han = han[:0]
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flushWord()
+			han = append(han, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushHan()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushHan()
+		}
+	}
+	flushWord()
+	flushHan()
+	return tokens
+}
+
+// Format renders hits as prompt context, each under a numbered citation
+// the agent can refer to.
+func Format(hits []Hit) string {
+	var sb strings.Builder
+	for i, hit := range hits {
+		fmt.Fprintf(&sb, "## [%d] %s\n\n%s\n\n", i+1, hit.Citation(), hit.Text)
+	}
+	return sb.String()
+}
+
+// Citations returns the numbered citations of hits.
+func Citations(hits []Hit) []string {
+	citations := make([]string, len(hits))
+	for i, hit := range hits {
+		citations[i] = fmt.Sprintf("[%d] %s", i+1, hit.Citation())
+	}
+	return citations
+}
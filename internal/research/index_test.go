@@ -0,0 +1,47 @@
+package research
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	got := Tokenize("Redis 缓存淘汰, a TTL of 5s; 锁")
+	want := []string{"redis", "缓存", "存淘", "淘汰", "ttl", "of", "5s", "锁"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize() = %q, want %q", got, want)
+	}
+}
+
+func TestIndexSearch(t *testing.T) {
+	ix := NewIndex([]Section{
+		{File: "auth.md", Heading: "登录", Line: 1, Text: "# 登录\n\nUsers log in with email and password."},
+		{File: "cache.md", Heading: "缓存 > 淘汰策略", Line: 3, Text: "## 淘汰策略\n\nThe cache evicts entries with LRU."},
+		{File: "cache.md", Heading: "缓存 > 过期", Line: 9, Text: "## 过期\n\nCache entries expire after a TTL."},
+		{File: "deploy.md", Heading: "部署", Line: 1, Text: "# 部署\n\nDeploy with docker compose."},
+	})
+
+	hits := ix.Search("缓存淘汰 LRU", 2)
+	if len(hits) != 2 || hits[0].Citation() != "cache.md:3 § 缓存 > 淘汰策略" || hits[1].File != "cache.md" {
+		t.Fatalf("Unexpected hits: %+v", hits)
+	}
+	if hits[0].Score <= hits[1].Score {
+		t.Errorf("Expected hits by descending score: %v, %v", hits[0].Score, hits[1].Score)
+	}
+
+	if hits := ix.Search("kubernetes", 5); len(hits) != 0 {
+		t.Errorf("Expected no hits for an unknown term, got %+v", hits)
+	}
+	if hits := ix.Search("email", 0); len(hits) != 1 || hits[0].File != "auth.md" {
+		t.Errorf("Unexpected hits: %+v", hits)
+	}
+
+	formatted := Format(hits)
+	if !strings.HasPrefix(formatted, "## [1] cache.md:3 § 缓存 > 淘汰策略\n\n## 淘汰策略\n") {
+		t.Errorf("Unexpected format:\n%s", formatted)
+	}
+	if got := Citations(hits); len(got) != 2 || got[1] != "[2] cache.md:9 § 缓存 > 过期" {
+		t.Errorf("Citations() = %q", got)
+	}
+}
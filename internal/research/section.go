@@ -0,0 +1,131 @@
+// Package research indexes the research documents in .morty/research for
+// local full-text search, so prompts can include the relevant sections
+// instead of every document.
+package research
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxSplitLevel is the deepest heading level that starts a new section.
+// Deeper headings stay in the section of their parent.
+const maxSplitLevel = 3
+
+// Section is the part of a research document under one heading.
+type Section struct {
+	File    string // file name in the research directory
+	Heading string // heading path, e.g. "缓存 > 淘汰策略"; empty before the first heading
+	Line    int    // 1-based line the section starts at
+	Text    string // section content including its heading line
+}
+
+// Citation identifies the section in prompts and logs, e.g.
+// "cache.md:12 § 缓存 > 淘汰策略".
+func (s Section) Citation() string {
+	if s.Heading == "" {
+		return fmt.Sprintf("%s:%d", s.File, s.Line)
+	}
+	return fmt.Sprintf("%s:%d § %s", s.File, s.Line, s.Heading)
+}
+
+// Split splits a Markdown document into sections at headings of level 1
+// to 3. Headings in fenced code blocks are ignored and sections without
+// text are dropped.
+func Split(file, content string) []Section {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	var sections []Section
+	var path []string // heading text per level
+	current := Section{File: file, Line: 1}
+	start := 0
+	inFence := false
+
+	// flush ends the current section at line end, dropping it when there is
+	// nothing under its heading
+	flush := func(end int) {
+		body := lines[start:end]
+		if current.Heading != "" {
+			body = body[1:]
+		}
+		if strings.TrimSpace(strings.Join(body, "\n")) == "" {
+			return
+		}
+		current.Text = strings.TrimSpace(strings.Join(lines[start:end], "\n"))
+		sections = append(sections, current)
+	}
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		level, title := heading(trimmed)
+		if level == 0 || level > maxSplitLevel {
+			continue
+		}
+
+		flush(i)
+		if len(path) >= level {
+			path = path[:level-1]
+		}
+		for len(path) < level-1 {
+			path = append(path, "")
+		}
+		path = append(path, title)
+		current = Section{File: file, Heading: joinPath(path), Line: i + 1}
+		start = i
+	}
+	flush(len(lines))
+	return sections
+}
+
+// heading returns the level and text of an ATX heading line, or 0.
+func heading(line string) (int, string) {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || level == len(line) || line[level] != ' ' {
+		return 0, ""
+	}
+	return level, strings.TrimSpace(strings.TrimRight(line[level:], "#"))
+}
+
+// joinPath joins the non-empty headings of a path.
+func joinPath(path []string) string {
+	var parts []string
+	for _, p := range path {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, " > ")
+}
+
+// LoadDir reads and splits every .md file in dir, in file name order. A
+// missing directory has no sections.
+func LoadDir(dir string) ([]Section, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list research files: %w", err)
+	}
+	sort.Strings(files)
+
+	var sections []Section
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read research file %s: %w", file, err)
+		}
+		sections = append(sections, Split(filepath.Base(file), string(data))...)
+	}
+	return sections, nil
+}
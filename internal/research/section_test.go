@@ -0,0 +1,79 @@
+package research
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const cacheDoc = `Notes before any heading.
+
+# 缓存设计
+
+## 淘汰策略
+
+LRU evicts the least recently used entry.
+
+### 实现细节
+
+A doubly linked list and a map.
+
+` + "```" + `
+# not a heading
+` + "```" + `
+
+## 过期
+
+#### TTL
+
+Entries expire after a TTL.
+
+# Empty
+`
+
+func TestSplit(t *testing.T) {
+	sections := Split("cache.md", cacheDoc)
+
+	want := []struct {
+		heading string
+		line    int
+	}{
+		{"", 1},
+		{"缓存设计 > 淘汰策略", 5},
+		{"缓存设计 > 淘汰策略 > 实现细节", 9},
+		{"缓存设计 > 过期", 17},
+	}
+	if len(sections) != len(want) {
+		t.Fatalf("Expected %d sections, got %+v", len(want), sections)
+	}
+	for i, w := range want {
+		if sections[i].Heading != w.heading || sections[i].Line != w.line || sections[i].File != "cache.md" {
+			t.Errorf("Section %d = %q at line %d, want %q at line %d", i, sections[i].Heading, sections[i].Line, w.heading, w.line)
+		}
+	}
+	if got := sections[2].Text; got != "### 实现细节\n\nA doubly linked list and a map.\n\n```\n# not a heading\n```" {
+		t.Errorf("Expected the code block to stay in its section, got %q", got)
+	}
+	if got := sections[3].Citation(); got != "cache.md:17 § 缓存设计 > 过期" {
+		t.Errorf("Citation() = %q", got)
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "b.md"), []byte("# B\n\nbody\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "a.md"), []byte("# A\n\nbody\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("# C\n\nbody\n"), 0644)
+
+	sections, err := LoadDir(dir)
+	if err != nil {
+		t.Fatalf("LoadDir() error: %v", err)
+	}
+	if len(sections) != 2 || sections[0].File != "a.md" || sections[1].File != "b.md" {
+		t.Errorf("Unexpected sections: %+v", sections)
+	}
+
+	if sections, err := LoadDir(filepath.Join(dir, "missing")); err != nil || len(sections) != 0 {
+		t.Errorf("Expected no sections for a missing directory, got %v, %v", sections, err)
+	}
+}
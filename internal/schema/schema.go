@@ -0,0 +1,339 @@
+// Package schema generates JSON Schemas (draft 2020-12) from Go types and
+// validates decoded JSON documents against them.
+//
+// Only the keywords Morty's generated schemas use are supported: type,
+// properties, additionalProperties, items, enum, minimum, minLength and
+// pattern. Property names come from the json struct tags, so a schema stays
+// in sync with the types it describes; constraints the types can't express
+// are added as Rules keyed by dotted path.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Draft is the JSON Schema dialect of generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Default              interface{}        `json:"default,omitempty"`
+
+	// Format names Pattern in error messages. It isn't encoded because the
+	// standard "duration" format means ISO 8601 durations, not Go ones.
+	Format string `json:"-"`
+
+	// closed rejects properties not listed in Properties; it is encoded
+	// as "additionalProperties": false
+	closed  bool
+	pattern *regexp.Regexp
+}
+
+// MarshalJSON encodes closed objects with "additionalProperties": false.
+func (s *Schema) MarshalJSON() ([]byte, error) {
+	type plain Schema
+	if !s.closed {
+		return json.Marshal((*plain)(s))
+	}
+	return json.Marshal(struct {
+		*plain
+		AdditionalProperties bool `json:"additionalProperties"`
+	}{plain: (*plain)(s)})
+}
+
+// Rule adds constraints and documentation to a generated property.
+type Rule struct {
+	Description string
+	Enum        []interface{}
+	// Minimum is the smallest allowed number
+	Minimum *float64
+	// MinLength is the shortest allowed string; 1 makes a string required
+	MinLength *int
+	// Pattern is a regular expression strings must match
+	Pattern string
+	// Format names the pattern in error messages (e.g. "duration")
+	Format string
+}
+
+// Min returns a pointer to n, for Rule.Minimum.
+func Min(n float64) *float64 { return &n }
+
+// NonEmpty is a MinLength that makes a string required.
+var NonEmpty = func() *int { n := 1; return &n }()
+
+// Generate builds the schema of a struct type. Rules are keyed by the
+// dotted JSON path of a property; list elements are addressed with "[]",
+// e.g. "git.scan.rules[].name".
+func Generate(t reflect.Type, rules map[string]Rule) *Schema {
+	s := generate(t, "", rules)
+	s.Schema = Draft
+	if s.Properties != nil && s.Properties["$schema"] == nil {
+		s.Properties["$schema"] = &Schema{Type: "string", Description: "JSON Schema of this file"}
+	}
+	return s
+}
+
+func generate(t reflect.Type, path string, rules map[string]Rule) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	s := &Schema{}
+	switch t.Kind() {
+	case reflect.String:
+		s.Type = "string"
+	case reflect.Bool:
+		s.Type = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s.Type = "integer"
+	case reflect.Float32, reflect.Float64:
+		s.Type = "number"
+	case reflect.Slice, reflect.Array:
+		s.Type = "array"
+		s.Items = generate(t.Elem(), path+"[]", rules)
+	case reflect.Map:
+		s.Type = "object"
+		s.AdditionalProperties = generate(t.Elem(), path+"[]", rules)
+	case reflect.Struct:
+		s.Type = "object"
+		s.closed = true
+		s.Properties = make(map[string]*Schema)
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" || !f.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			s.Properties[name] = generate(f.Type, join(path, name), rules)
+		}
+	}
+
+	if rule, ok := rules[path]; ok {
+		s.Description = rule.Description
+		s.Enum = rule.Enum
+		s.Minimum = rule.Minimum
+		s.MinLength = rule.MinLength
+		s.Format = rule.Format
+		if rule.Pattern != "" {
+			s.Pattern = rule.Pattern
+			s.pattern = regexp.MustCompile(rule.Pattern)
+		}
+	}
+	return s
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// SetDefaults records the values of a decoded JSON document as the
+// defaults of the matching properties, so editors can suggest them.
+func (s *Schema) SetDefaults(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for name, value := range v {
+			if p := s.Properties[name]; p != nil {
+				p.SetDefaults(value)
+			}
+		}
+	default:
+		if s.Type != "object" {
+			s.Default = v
+		}
+	}
+}
+
+// Property returns the schema of a dotted property path, or nil.
+func (s *Schema) Property(path string) *Schema {
+	for _, name := range strings.Split(path, ".") {
+		if s == nil || s.Properties == nil {
+			return nil
+		}
+		s = s.Properties[name]
+	}
+	return s
+}
+
+// Error is a violation of a schema.
+type Error struct {
+	// Path is the dotted path of the value, e.g. "git.scan.rules[0].name"
+	Path    string
+	Message string
+}
+
+// Error returns the path and message.
+func (e *Error) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// Validate checks a decoded JSON document (as decoded by encoding/json
+// into an interface{}) and returns every violation, ordered by path.
+func (s *Schema) Validate(v interface{}) []*Error {
+	var errs []*Error
+	s.validate(v, "", &errs)
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Path < errs[j].Path })
+	return errs
+}
+
+// ValidateJSON parses and validates a JSON document.
+func (s *Schema) ValidateJSON(data []byte) ([]*Error, error) {
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return s.Validate(v), nil
+}
+
+func (s *Schema) validate(v interface{}, path string, errs *[]*Error) {
+	fail := func(format string, args ...interface{}) {
+		p := path
+		if p == "" {
+			p = "(root)"
+		}
+		*errs = append(*errs, &Error{Path: p, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.Type != "" && !hasType(v, s.Type) {
+		fail("expected %s, got %s", s.Type, typeName(v))
+		return
+	}
+
+	if len(s.Enum) > 0 && !inEnum(v, s.Enum) {
+		var options []string
+		for _, e := range s.Enum {
+			options = append(options, format(e))
+		}
+		fail("invalid value: %s (expected one of %s)", format(v), strings.Join(options, ", "))
+	}
+
+	switch v := v.(type) {
+	case string:
+		if s.MinLength != nil && len([]rune(v)) < *s.MinLength {
+			if *s.MinLength == 1 {
+				fail("must not be empty")
+			} else {
+				fail("must be at least %d characters", *s.MinLength)
+			}
+		}
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			if s.Format != "" {
+				fail("invalid %s format: %q", s.Format, v)
+			} else {
+				fail("does not match pattern %s: %q", s.Pattern, v)
+			}
+		}
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			fail("must be >= %v", *s.Minimum)
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i), errs)
+			}
+		}
+	case map[string]interface{}:
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if p, ok := s.Properties[name]; ok {
+				p.validate(v[name], join(path, name), errs)
+			} else if s.AdditionalProperties != nil {
+				s.AdditionalProperties.validate(v[name], join(path, name), errs)
+			} else if s.closed {
+				*errs = append(*errs, &Error{Path: join(path, name), Message: "unknown field"})
+			}
+		}
+	}
+}
+
+func hasType(v interface{}, t string) bool {
+	switch t {
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "array":
+		_, ok := v.([]interface{})
+		return ok || v == nil
+	case "object":
+		_, ok := v.(map[string]interface{})
+		return ok || v == nil
+	}
+	return true
+}
+
+func typeName(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// format prints strings quoted so empty values stay visible.
+func format(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	return fmt.Sprint(v)
+}
+
+func inEnum(v interface{}, enum []interface{}) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == fmt.Sprint(v) {
+			return true
+		}
+	}
+	return false
+}
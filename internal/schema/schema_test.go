@@ -0,0 +1,106 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testRule struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern,omitempty"`
+}
+
+type testConfig struct {
+	Level   string              `json:"level"`
+	Retries int                 `json:"retries"`
+	Timeout string              `json:"timeout"`
+	Enabled bool                `json:"enabled"`
+	Rules   []testRule          `json:"rules"`
+	Aliases map[string][]string `json:"aliases"`
+	Secret  string              `json:"-"`
+}
+
+var testRules = map[string]Rule{
+	"level":        {Description: "Log level", Enum: []interface{}{"debug", "info"}},
+	"retries":      {Minimum: Min(0)},
+	"timeout":      {Pattern: `^[0-9]+[smh]$`, Format: "duration"},
+	"rules[].name": {MinLength: NonEmpty},
+}
+
+func TestGenerate(t *testing.T) {
+	s := Generate(reflect.TypeOf(testConfig{}), testRules)
+
+	if s.Schema != Draft || s.Type != "object" {
+		t.Errorf("Unexpected root: %+v", s)
+	}
+	if s.Properties["secret"] != nil || s.Properties["Secret"] != nil {
+		t.Error("Expected fields tagged json:\"-\" to be skipped")
+	}
+	if s.Properties["$schema"] == nil {
+		t.Error("Expected a $schema property")
+	}
+	if got := s.Property("rules").Items.Properties["name"].MinLength; got == nil || *got != 1 {
+		t.Errorf("Expected the list element rule to apply, got %v", got)
+	}
+	if got := s.Property("aliases").AdditionalProperties.Type; got != "array" {
+		t.Errorf("Expected map values to be described, got %q", got)
+	}
+	if s.Property("level").Description != "Log level" {
+		t.Errorf("Expected the rule description, got %q", s.Property("level").Description)
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	if !strings.Contains(string(data), `"additionalProperties":false`) || strings.Contains(string(data), "duration") {
+		t.Errorf("Unexpected encoding: %s", data)
+	}
+}
+
+func TestSetDefaults(t *testing.T) {
+	s := Generate(reflect.TypeOf(testConfig{}), testRules)
+	s.SetDefaults(map[string]interface{}{"level": "info", "retries": 3.0, "unknown": true})
+
+	if s.Property("level").Default != "info" || s.Property("retries").Default != 3.0 {
+		t.Errorf("Unexpected defaults: %v, %v", s.Property("level").Default, s.Property("retries").Default)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	s := Generate(reflect.TypeOf(testConfig{}), testRules)
+
+	tests := []struct {
+		name string
+		doc  string
+		want []string
+	}{
+		{"valid", `{"$schema": "./x.json", "level": "debug", "retries": 0, "timeout": "10m", "rules": [{"name": "a"}], "aliases": {"x": ["y"]}}`, nil},
+		{"enum", `{"level": "loud"}`, []string{`level: invalid value: "loud" (expected one of "debug", "info")`}},
+		{"minimum", `{"retries": -1}`, []string{"retries: must be >= 0"}},
+		{"integer", `{"retries": 1.5}`, []string{"retries: expected integer, got number"}},
+		{"type", `{"enabled": "yes"}`, []string{"enabled: expected boolean, got string"}},
+		{"pattern", `{"timeout": "soon"}`, []string{`timeout: invalid duration format: "soon"`}},
+		{"unknown", `{"levle": "info"}`, []string{"levle: unknown field"}},
+		{"nested", `{"rules": [{"name": "a"}, {"name": "", "extra": 1}]}`, []string{"rules[1].extra: unknown field", "rules[1].name: must not be empty"}},
+		{"map", `{"aliases": {"x": "y"}}`, []string{"aliases.x: expected array, got string"}},
+		{"root", `[]`, []string{"(root): expected object, got array"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, err := s.ValidateJSON([]byte(tt.doc))
+			if err != nil {
+				t.Fatalf("ValidateJSON() error: %v", err)
+			}
+			var got []string
+			for _, e := range errs {
+				got = append(got, e.Error())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/morty/morty/pkg/errors"
+)
+
+// backupTimeFormat names backups so they sort in creation order.
+const backupTimeFormat = "20060102-150405.000000"
+
+// Backup copies the status file next to itself as
+// <status file>.<timestamp>.bak and returns the backup path.
+func (m *Manager) Backup() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	content, err := os.ReadFile(m.filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read status file: %w", err)
+	}
+
+	backupPath := fmt.Sprintf("%s.%s.bak", m.filePath, time.Now().Format(backupTimeFormat))
+	if err := os.WriteFile(backupPath, content, 0644); err != nil {
+		return "", fmt.Errorf("failed to write status backup: %w", err)
+	}
+
+	return backupPath, nil
+}
+
+// BackupWithCustomPath writes the loaded status to the given path.
+func (m *Manager) BackupWithCustomPath(backupPath string) error {
+	current := m.GetStatus()
+	if current == nil {
+		return fmt.Errorf("status not loaded")
+	}
+
+	data, err := json.MarshalIndent(current, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal status: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(backupPath), 0755); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	if err := os.WriteFile(backupPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write status backup: %w", err)
+	}
+
+	return nil
+}
+
+// ListBackups returns the backups made by Backup, oldest first.
+func (m *Manager) ListBackups() ([]string, error) {
+	backups, err := filepath.Glob(m.filePath + ".*.bak")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list status backups: %w", err)
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// RestoreFromBackup replaces the status file with a backup and loads it.
+func (m *Manager) RestoreFromBackup(backupPath string) error {
+	content, err := os.ReadFile(backupPath)
+	if err != nil {
+		return fmt.Errorf("failed to read status backup: %w", err)
+	}
+
+	var restored ExecutionStatus
+	if err := json.Unmarshal(content, &restored); err != nil {
+		return errors.Wrap(err, "M2002", "failed to parse status backup").WithModule("state")
+	}
+	if err := validateStatus(&restored); err != nil {
+		return errors.Wrap(err, "M2003", "status backup corrupted").WithModule("state")
+	}
+
+	return m.Save(&restored)
+}
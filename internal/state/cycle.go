@@ -0,0 +1,71 @@
+package state
+
+import (
+	"sort"
+	"strings"
+)
+
+// FindCycle returns one dependency cycle in deps, or nil if the graph is acyclic.
+// deps maps each node to the nodes it depends on. The returned path starts and
+// ends with the same node, e.g. [a b c a] meaning a depends on b, b on c and c on a.
+// Nodes are visited in sorted order so the reported cycle is deterministic.
+func FindCycle(deps map[string][]string) []string {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+
+	nodes := make([]string, 0, len(deps))
+	for name := range deps {
+		nodes = append(nodes, name)
+	}
+	sort.Strings(nodes)
+
+	color := make(map[string]int)
+	var stack []string
+
+	var visit func(node string) []string
+	visit = func(node string) []string {
+		color[node] = visiting
+		stack = append(stack, node)
+
+		next := append([]string(nil), deps[node]...)
+		sort.Strings(next)
+		for _, dep := range next {
+			switch color[dep] {
+			case visiting:
+				// Found a back edge: the cycle is the stack suffix starting at dep
+				for i, n := range stack {
+					if n == dep {
+						cycle := append([]string(nil), stack[i:]...)
+						return append(cycle, dep)
+					}
+				}
+			case unvisited:
+				if cycle := visit(dep); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+		color[node] = done
+		return nil
+	}
+
+	for _, node := range nodes {
+		if color[node] == unvisited {
+			if cycle := visit(node); cycle != nil {
+				return cycle
+			}
+		}
+	}
+
+	return nil
+}
+
+// FormatCycle formats a cycle returned by FindCycle as "a -> b -> a".
+func FormatCycle(cycle []string) string {
+	return strings.Join(cycle, " -> ")
+}
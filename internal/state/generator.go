@@ -137,6 +137,12 @@ func GenerateStatus(planDir string) (*ExecutionStatus, error) {
 	return status, nil
 }
 
+// ScanPlans scans the plan directory and returns the parsed plan information
+// in directory order. Unreadable or unparsable plan files are skipped.
+func ScanPlans(planDir string) ([]PlanInfo, error) {
+	return scanPlanFiles(planDir)
+}
+
 // scanPlanFiles scans the plan directory and parses all plan files.
 func scanPlanFiles(planDir string) ([]PlanInfo, error) {
 	entries, err := os.ReadDir(planDir)
@@ -152,26 +158,26 @@ func scanPlanFiles(planDir string) ([]PlanInfo, error) {
 		}
 
 		fileName := entry.Name()
-		if !strings.HasSuffix(fileName, ".md") || strings.HasPrefix(fileName, "README") {
+		if !plan.IsPlanFile(fileName) {
 			continue
 		}
 
-		// Read plan file
+		// Extract module name from filename (without extension)
+		moduleName := plan.ModuleNameFromFile(fileName)
+
+		// If a module exists in several formats, use the one the other
+		// commands would load
 		planPath := filepath.Join(planDir, fileName)
-		content, err := os.ReadFile(planPath)
-		if err != nil {
+		if preferred, err := plan.FindPlanFile(planDir, moduleName); err == nil && preferred != planPath {
 			continue
 		}
 
-		// Parse plan
-		parsedPlan, err := plan.ParsePlan(string(content))
+		// Parse plan in the format implied by its extension
+		parsedPlan, err := plan.ParsePlanFile(planPath)
 		if err != nil {
 			continue
 		}
 
-		// Extract module name from filename (without .md)
-		moduleName := strings.TrimSuffix(fileName, ".md")
-
 		// Extract jobs
 		jobs := []JobInfo{}
 		for _, job := range parsedPlan.Jobs {
@@ -276,6 +282,9 @@ func topologicalSortModules(plans []PlanInfo) ([]PlanInfo, error) {
 
 	// Check for cycles
 	if len(result) != len(plans) {
+		if cycle := FindCycle(deps); cycle != nil {
+			return nil, fmt.Errorf("cycle detected in module dependencies: %s", FormatCycle(cycle))
+		}
 		return nil, fmt.Errorf("cycle detected in module dependencies")
 	}
 
@@ -355,6 +364,16 @@ func topologicalSortJobs(module PlanInfo, allModules map[string]PlanInfo) ([]Job
 
 	// Check for cycles
 	if len(result) != len(module.Jobs) {
+		named := make(map[string][]string)
+		for idx, d := range deps {
+			name := fmt.Sprintf("job_%d", idx)
+			for _, dep := range d {
+				named[name] = append(named[name], fmt.Sprintf("job_%d", dep))
+			}
+		}
+		if cycle := FindCycle(named); cycle != nil {
+			return nil, fmt.Errorf("cycle detected in job dependencies: %s", FormatCycle(cycle))
+		}
 		return nil, fmt.Errorf("cycle detected in job dependencies")
 	}
 
@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/morty/morty/pkg/errors"
 )
 
 // Manager provides status management operations.
@@ -60,7 +63,10 @@ func (m *Manager) Load() error {
 
 	var loadedStatus ExecutionStatus
 	if err := json.Unmarshal(content, &loadedStatus); err != nil {
-		return fmt.Errorf("failed to parse status JSON: %w", err)
+		return errors.Wrap(err, "M2002", "failed to parse status JSON").WithModule("state")
+	}
+	if err := validateStatus(&loadedStatus); err != nil {
+		return errors.Wrap(err, "M2003", "status file corrupted").WithModule("state")
 	}
 
 	statusMu.Lock()
@@ -87,6 +93,9 @@ func (m *Manager) Save(newStatus *ExecutionStatus) error {
 	}
 
 	// Write to file
+	if err := os.MkdirAll(filepath.Dir(m.filePath), 0755); err != nil {
+		return fmt.Errorf("failed to create status directory: %w", err)
+	}
 	if err := os.WriteFile(m.filePath, data, 0644); err != nil {
 		return fmt.Errorf("failed to write status file: %w", err)
 	}
@@ -94,6 +103,28 @@ func (m *Manager) Save(newStatus *ExecutionStatus) error {
 	return nil
 }
 
+// validateStatus checks that a loaded status has a version and only known
+// statuses.
+func validateStatus(s *ExecutionStatus) error {
+	if s.Version == "" {
+		return fmt.Errorf("missing version")
+	}
+	if s.Global.Status != "" && !s.Global.Status.IsValid() {
+		return fmt.Errorf("invalid global status %q", s.Global.Status)
+	}
+	for _, module := range s.Modules {
+		if module.Status != "" && !module.Status.IsValid() {
+			return fmt.Errorf("invalid status %q for module %s", module.Status, module.Name)
+		}
+		for _, job := range module.Jobs {
+			if !job.Status.IsValid() {
+				return fmt.Errorf("invalid status %q for job %s/%s", job.Status, module.Name, job.Name)
+			}
+		}
+	}
+	return nil
+}
+
 // Initialize initializes V2 status from plan files.
 func (m *Manager) Initialize(planDir string) error {
 	// Generate V2 status
@@ -159,6 +190,8 @@ func (m *Manager) UpdateJobStatus(moduleIndex, jobIndex int, newStatus Status) e
 		}
 	} else if newStatus == StatusFailed {
 		status.Global.Status = StatusFailed
+	} else if newStatus == StatusInterrupted {
+		status.Global.Status = StatusInterrupted
 	}
 
 	// Save to file
@@ -418,9 +451,15 @@ func (m *Manager) ClearCurrent() error {
 }
 
 // TransitionJobStatus transitions a job to a new status with validation.
+// A transition that breaks TransitionRules leaves the job unchanged, returns
+// a *TransitionError and is logged when logger supports error logging.
 func (m *Manager) TransitionJobStatus(moduleName, jobName string, toStatus Status, logger interface{}) error {
-	// For now, just update the status
-	// TODO: Add validation logic if needed
+	if err := m.CanTransition(moduleName, jobName, toStatus); err != nil {
+		if _, ok := err.(*TransitionError); ok {
+			logTransitionError(logger, moduleName, jobName, err)
+		}
+		return err
+	}
 	return m.UpdateJobStatusByName(moduleName, jobName, toStatus)
 }
 
@@ -470,3 +509,96 @@ func (m *Manager) UpdateFailureReason(moduleName, jobName, reason string) error
 
 	return err
 }
+
+// SetJobDigest records the digest of a completed job.
+func (m *Manager) SetJobDigest(moduleName, jobName string, digest *JobDigest) error {
+	moduleIndex, jobIndex, err := m.findJobIndices(moduleName, jobName)
+	if err != nil {
+		return err
+	}
+
+	statusMu.Lock()
+	job := &status.Modules[moduleIndex].Jobs[jobIndex]
+	job.Digest = digest
+	job.UpdatedAt = time.Now()
+	current := status
+	statusMu.Unlock()
+
+	return m.Save(current)
+}
+
+// AddJobSession records the session of a job attempt.
+func (m *Manager) AddJobSession(moduleName, jobName string, session JobSession) error {
+	moduleIndex, jobIndex, err := m.findJobIndices(moduleName, jobName)
+	if err != nil {
+		return err
+	}
+
+	statusMu.Lock()
+	job := &status.Modules[moduleIndex].Jobs[jobIndex]
+	job.Sessions = append(job.Sessions, session)
+	job.UpdatedAt = time.Now()
+	current := status
+	statusMu.Unlock()
+
+	return m.Save(current)
+}
+
+// FailJobSession records why the latest attempt of a job failed.
+func (m *Manager) FailJobSession(moduleName, jobName, reason string) error {
+	moduleIndex, jobIndex, err := m.findJobIndices(moduleName, jobName)
+	if err != nil {
+		return err
+	}
+
+	statusMu.Lock()
+	job := &status.Modules[moduleIndex].Jobs[jobIndex]
+	session := job.LastSession()
+	if session == nil {
+		statusMu.Unlock()
+		return nil
+	}
+	session.Error = reason
+	job.UpdatedAt = time.Now()
+	current := status
+	statusMu.Unlock()
+
+	return m.Save(current)
+}
+
+// InterruptJob marks a RUNNING job as INTERRUPTED and records the reason.
+// It is the only place a job becomes INTERRUPTED; the next run resumes it.
+func (m *Manager) InterruptJob(moduleName, jobName, reason string) error {
+	moduleIndex, jobIndex, err := m.findJobIndices(moduleName, jobName)
+	if err != nil {
+		return err
+	}
+
+	statusMu.Lock()
+	job := &status.Modules[moduleIndex].Jobs[jobIndex]
+	if job.Status != StatusRunning {
+		current := job.Status
+		statusMu.Unlock()
+		return fmt.Errorf("cannot interrupt job %s/%s: status is %s, not %s", moduleName, jobName, current, StatusRunning)
+	}
+	job.FailureReason = reason
+	statusMu.Unlock()
+
+	return m.UpdateJobStatus(moduleIndex, jobIndex, StatusInterrupted)
+}
+
+// BlockJob marks a job as BLOCKED by a failed job and records the reason.
+func (m *Manager) BlockJob(moduleName, jobName, blockedBy, reason string) error {
+	moduleIndex, jobIndex, err := m.findJobIndices(moduleName, jobName)
+	if err != nil {
+		return err
+	}
+
+	statusMu.Lock()
+	job := &status.Modules[moduleIndex].Jobs[jobIndex]
+	job.BlockedBy = blockedBy
+	job.FailureReason = reason
+	statusMu.Unlock()
+
+	return m.UpdateJobStatus(moduleIndex, jobIndex, StatusBlocked)
+}
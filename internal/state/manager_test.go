@@ -8,560 +8,455 @@ import (
 	"time"
 )
 
-// setupTestManager creates a temporary manager for testing.
-func setupTestManager(t *testing.T) (*Manager, string) {
+// setupTestManager creates a manager whose status file holds one module
+// with the given jobs, all PENDING unless the job says otherwise.
+func setupTestManager(t *testing.T, jobs ...JobState) (*Manager, string) {
 	t.Helper()
-	tempDir := t.TempDir()
-	stateFile := filepath.Join(tempDir, "test_status.json")
+	stateFile := filepath.Join(t.TempDir(), "status.json")
 
+	now := time.Now()
+	for i := range jobs {
+		jobs[i].Index = i
+		jobs[i].GlobalIndex = i
+		if jobs[i].Status == "" {
+			jobs[i].Status = StatusPending
+		}
+	}
 	manager := NewManager(stateFile)
-	if err := manager.Load(); err != nil {
-		t.Fatalf("Failed to load manager: %v", err)
+	if err := manager.Save(&ExecutionStatus{
+		Version: "2.0",
+		Global:  GlobalState{Status: StatusPending, TotalModules: 1, TotalJobs: len(jobs)},
+		Modules: []ModuleState{{
+			Name:      "test_module",
+			Status:    StatusPending,
+			Jobs:      jobs,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}},
+	}); err != nil {
+		t.Fatalf("Failed to save status: %v", err)
 	}
-
 	return manager, stateFile
 }
 
 // createTestJob creates a test job with the given status.
-func createTestJob(name string, status Status) *JobState {
+func createTestJob(name string, status Status) JobState {
 	now := time.Now()
-	return &JobState{
-		Name:           name,
-		Status:         status,
-		LoopCount:      0,
-		RetryCount:     0,
-		TasksTotal:     5,
-		TasksCompleted: 0,
-		Tasks:          make([]TaskState, 0),
-		DebugLogs:      make([]DebugLogEntry, 0),
-		CreatedAt:      now,
-		UpdatedAt:      now,
+	return JobState{
+		Name:       name,
+		Status:     status,
+		TasksTotal: 3,
+		Tasks: []TaskState{
+			{Index: 1, Status: StatusPending, Description: "first"},
+			{Index: 2, Status: StatusPending, Description: "second"},
+			{Index: 3, Status: StatusPending, Description: "third"},
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
 	}
 }
 
-// createTestModule creates a test module with jobs.
-func createTestModule(name string, jobs map[string]*JobState) *ModuleState {
-	now := time.Now()
-	if jobs == nil {
-		jobs = make(map[string]*JobState)
-	}
-	return &ModuleState{
-		Name:      name,
-		Status:    StatusPending,
-		Jobs:      jobs,
-		CreatedAt: now,
-		UpdatedAt: now,
+// reload reads the status file again with a new manager.
+func reload(t *testing.T, stateFile string) *ExecutionStatus {
+	t.Helper()
+	manager := NewManager(stateFile)
+	if err := manager.Load(); err != nil {
+		t.Fatalf("Failed to reload status: %v", err)
 	}
+	return manager.GetStatus()
 }
 
 // TestGetJobStatus tests the GetJobStatus method.
 func TestGetJobStatus(t *testing.T) {
-	t.Run("Get existing job status returns correct value", func(t *testing.T) {
-		manager, _ := setupTestManager(t)
-
-		// Setup test data
-		job := createTestJob("test_job", StatusRunning)
-		module := createTestModule("test_module", map[string]*JobState{
-			"test_job": job,
-		})
-		manager.SetModule(module)
-
-		// Test GetJobStatus
-		status, err := manager.GetJobStatus("test_module", "test_job")
-		if err != nil {
-			t.Errorf("Expected no error, got: %v", err)
-		}
-		if status != StatusRunning {
-			t.Errorf("Expected status %s, got: %s", StatusRunning, status)
-		}
-	})
-
-	t.Run("Get non-existent module returns error", func(t *testing.T) {
-		manager, _ := setupTestManager(t)
-
-		_, err := manager.GetJobStatus("non_existent_module", "test_job")
-		if err == nil {
-			t.Error("Expected error for non-existent module, got nil")
-		}
-	})
-
-	t.Run("Get non-existent job returns error", func(t *testing.T) {
-		manager, _ := setupTestManager(t)
+	manager, _ := setupTestManager(t, createTestJob("test_job", StatusRunning))
 
-		// Setup module without the job
-		module := createTestModule("test_module", map[string]*JobState{})
-		manager.SetModule(module)
-
-		_, err := manager.GetJobStatus("test_module", "non_existent_job")
-		if err == nil {
-			t.Error("Expected error for non-existent job, got nil")
-		}
-	})
+	status, err := manager.GetJobStatus("test_module", "test_job")
+	if err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+	if status != StatusRunning {
+		t.Errorf("Expected status %s, got: %s", StatusRunning, status)
+	}
 
-	t.Run("GetJobStatus with nil state returns error", func(t *testing.T) {
-		manager := NewManager("/tmp/test.json")
-		// Don't load state, keep it nil
+	if _, err := manager.GetJobStatus("non_existent_module", "test_job"); err == nil {
+		t.Error("Expected error for non-existent module, got nil")
+	}
+	if _, err := manager.GetJobStatus("test_module", "non_existent_job"); err == nil {
+		t.Error("Expected error for non-existent job, got nil")
+	}
+}
 
-		_, err := manager.GetJobStatus("test_module", "test_job")
-		if err == nil {
-			t.Error("Expected error when state is nil, got nil")
-		}
-	})
+// TestGetJob_NotLoaded tests that GetJob returns nil without a status.
+func TestGetJob_NotLoaded(t *testing.T) {
+	manager := NewManager(filepath.Join(t.TempDir(), "missing.json"))
+	if err := manager.Load(); err != nil {
+		t.Fatalf("Load() of a missing file returned error: %v", err)
+	}
+	if manager.GetStatus() != nil {
+		t.Error("Expected nil status for a missing file")
+	}
+	if job := manager.GetJob("test_module", "test_job"); job != nil {
+		t.Errorf("Expected nil job, got: %+v", job)
+	}
+	if err := manager.UpdateJobStatusByName("test_module", "test_job", StatusRunning); err == nil {
+		t.Error("Expected error when status is not loaded")
+	}
 }
 
 // TestUpdateJobStatus tests the UpdateJobStatus method.
 func TestUpdateJobStatus(t *testing.T) {
 	t.Run("Update job status and save to file", func(t *testing.T) {
-		manager, stateFile := setupTestManager(t)
+		manager, stateFile := setupTestManager(t, createTestJob("test_job", StatusPending))
 
-		// Setup test data
-		job := createTestJob("test_job", StatusPending)
-		module := createTestModule("test_module", map[string]*JobState{
-			"test_job": job,
-		})
-		manager.SetModule(module)
-
-		// Update job status
-		err := manager.UpdateJobStatus("test_module", "test_job", StatusRunning)
-		if err != nil {
-			t.Errorf("Expected no error, got: %v", err)
+		if err := manager.UpdateJobStatus(0, 0, StatusRunning); err != nil {
+			t.Fatalf("UpdateJobStatus failed: %v", err)
 		}
 
-		// Verify status was updated in memory
-		status, _ := manager.GetJobStatus("test_module", "test_job")
-		if status != StatusRunning {
-			t.Errorf("Expected status %s, got: %s", StatusRunning, status)
+		saved := reload(t, stateFile)
+		if got := saved.Modules[0].Jobs[0].Status; got != StatusRunning {
+			t.Errorf("Expected saved status %s, got: %s", StatusRunning, got)
 		}
-
-		// Verify file was saved
-		if _, err := os.Stat(stateFile); os.IsNotExist(err) {
-			t.Error("Expected state file to exist after UpdateJobStatus")
+		if saved.Global.Status != StatusRunning {
+			t.Errorf("Expected global status %s, got: %s", StatusRunning, saved.Global.Status)
 		}
 	})
 
-	t.Run("Update job status with invalid status returns error", func(t *testing.T) {
-		manager, _ := setupTestManager(t)
-
-		// Setup test data
-		job := createTestJob("test_job", StatusPending)
-		module := createTestModule("test_module", map[string]*JobState{
-			"test_job": job,
-		})
-		manager.SetModule(module)
+	t.Run("Invalid indices return error", func(t *testing.T) {
+		manager, _ := setupTestManager(t, createTestJob("test_job", StatusPending))
 
-		// Try to update with invalid status
-		err := manager.UpdateJobStatus("test_module", "test_job", Status("INVALID"))
-		if err == nil {
-			t.Error("Expected error for invalid status, got nil")
+		if err := manager.UpdateJobStatus(1, 0, StatusRunning); err == nil {
+			t.Error("Expected error for invalid module index, got nil")
 		}
-	})
-
-	t.Run("Update non-existent module returns error", func(t *testing.T) {
-		manager, _ := setupTestManager(t)
-
-		err := manager.UpdateJobStatus("non_existent_module", "test_job", StatusRunning)
-		if err == nil {
-			t.Error("Expected error for non-existent module, got nil")
+		if err := manager.UpdateJobStatus(0, 5, StatusRunning); err == nil {
+			t.Error("Expected error for invalid job index, got nil")
 		}
 	})
 
-	t.Run("Update non-existent job returns error", func(t *testing.T) {
-		manager, _ := setupTestManager(t)
+	t.Run("Completing the last job completes the run", func(t *testing.T) {
+		manager, _ := setupTestManager(t,
+			createTestJob("job1", StatusCompleted),
+			createTestJob("job2", StatusRunning),
+		)
 
-		// Setup module without the job
-		module := createTestModule("test_module", map[string]*JobState{})
-		manager.SetModule(module)
-
-		err := manager.UpdateJobStatus("test_module", "non_existent_job", StatusRunning)
-		if err == nil {
-			t.Error("Expected error for non-existent job, got nil")
+		if err := manager.UpdateJobStatusByName("test_module", "job2", StatusCompleted); err != nil {
+			t.Fatalf("UpdateJobStatusByName failed: %v", err)
 		}
-	})
-
-	t.Run("UpdateJobStatus with nil state returns error", func(t *testing.T) {
-		manager := NewManager("/tmp/test.json")
-
-		err := manager.UpdateJobStatus("test_module", "test_job", StatusRunning)
-		if err == nil {
-			t.Error("Expected error when state is nil, got nil")
+		if got := manager.GetStatus().Global.Status; got != StatusCompleted {
+			t.Errorf("Expected global status %s, got: %s", StatusCompleted, got)
 		}
 	})
-}
 
-// TestGetCurrent tests the GetCurrent method.
-func TestGetCurrent(t *testing.T) {
-	t.Run("GetCurrent returns current executing job from global state", func(t *testing.T) {
-		manager, _ := setupTestManager(t)
+	t.Run("Failing a job fails the run", func(t *testing.T) {
+		manager, _ := setupTestManager(t, createTestJob("test_job", StatusRunning))
 
-		// Setup test data
-		job := createTestJob("test_job", StatusRunning)
-		module := createTestModule("test_module", map[string]*JobState{
-			"test_job": job,
-		})
-		manager.SetModule(module)
-
-		// Set current via SetCurrent
-		manager.SetCurrent("test_module", "test_job", StatusRunning)
-
-		// Get current
-		current, err := manager.GetCurrent()
-		if err != nil {
-			t.Errorf("Expected no error, got: %v", err)
-		}
-		if current == nil {
-			t.Fatal("Expected current job, got nil")
+		if err := manager.UpdateJobStatusByName("test_module", "test_job", StatusFailed); err != nil {
+			t.Fatalf("UpdateJobStatusByName failed: %v", err)
 		}
-		if current.Module != "test_module" {
-			t.Errorf("Expected module 'test_module', got: %s", current.Module)
-		}
-		if current.Job != "test_job" {
-			t.Errorf("Expected job 'test_job', got: %s", current.Job)
+		if got := manager.GetStatus().Global.Status; got != StatusFailed {
+			t.Errorf("Expected global status %s, got: %s", StatusFailed, got)
 		}
 	})
 
-	t.Run("GetCurrent finds running job when global not set", func(t *testing.T) {
-		manager, _ := setupTestManager(t)
-
-		// Setup test data with a running job
-		job := createTestJob("running_job", StatusRunning)
-		module := createTestModule("test_module", map[string]*JobState{
-			"running_job": job,
-		})
-		manager.SetModule(module)
+	t.Run("Unknown job by name returns error", func(t *testing.T) {
+		manager, _ := setupTestManager(t, createTestJob("test_job", StatusPending))
 
-		// Get current without setting global
-		current, err := manager.GetCurrent()
-		if err != nil {
-			t.Errorf("Expected no error, got: %v", err)
-		}
-		if current == nil {
-			t.Fatal("Expected current job, got nil")
+		if err := manager.UpdateJobStatusByName("test_module", "non_existent_job", StatusRunning); err == nil {
+			t.Error("Expected error for non-existent job, got nil")
 		}
-		if current.Status != StatusRunning {
-			t.Errorf("Expected status RUNNING, got: %s", current.Status)
+		if err := manager.UpdateJobStatusByName("non_existent_module", "test_job", StatusRunning); err == nil {
+			t.Error("Expected error for non-existent module, got nil")
 		}
 	})
+}
 
-	t.Run("GetCurrent returns nil when no job is running", func(t *testing.T) {
-		manager, _ := setupTestManager(t)
-
-		// Setup test data with only pending jobs
-		job := createTestJob("pending_job", StatusPending)
-		module := createTestModule("test_module", map[string]*JobState{
-			"pending_job": job,
-		})
-		manager.SetModule(module)
+// TestSetCurrent tests that SetCurrent records the running job.
+func TestSetCurrent(t *testing.T) {
+	manager, _ := setupTestManager(t,
+		createTestJob("job1", StatusCompleted),
+		createTestJob("job2", StatusPending),
+	)
 
-		current, err := manager.GetCurrent()
-		if err != nil {
-			t.Errorf("Expected no error, got: %v", err)
-		}
-		if current != nil {
-			t.Errorf("Expected nil, got: %+v", current)
-		}
-	})
+	if err := manager.SetCurrent("test_module", "job2", StatusRunning); err != nil {
+		t.Fatalf("SetCurrent failed: %v", err)
+	}
+	global := manager.GetStatus().Global
+	if global.CurrentModuleIndex != 0 || global.CurrentJobIndex != 1 {
+		t.Errorf("Expected current job 0/1, got: %d/%d", global.CurrentModuleIndex, global.CurrentJobIndex)
+	}
+	if err := manager.ClearCurrent(); err != nil {
+		t.Errorf("ClearCurrent failed: %v", err)
+	}
+}
 
-	t.Run("GetCurrent with nil state returns error", func(t *testing.T) {
-		manager := NewManager("/tmp/test.json")
+// TestUpdateTaskStatus tests task updates and the completed count.
+func TestUpdateTaskStatus(t *testing.T) {
+	manager, stateFile := setupTestManager(t, createTestJob("test_job", StatusRunning))
 
-		_, err := manager.GetCurrent()
-		if err == nil {
-			t.Error("Expected error when state is nil, got nil")
+	for _, index := range []int{0, 2} {
+		if err := manager.UpdateTaskStatusByName("test_module", "test_job", index, StatusCompleted); err != nil {
+			t.Fatalf("UpdateTaskStatusByName(%d) failed: %v", index, err)
 		}
-	})
-}
+	}
+	if err := manager.UpdateTaskStatus(0, 0, 3, StatusCompleted); err == nil {
+		t.Error("Expected error for invalid task index, got nil")
+	}
 
-// TestSetCurrent tests the SetCurrent method.
-func TestSetCurrent(t *testing.T) {
-	t.Run("SetCurrent updates global state", func(t *testing.T) {
-		manager, _ := setupTestManager(t)
-
-		// Setup test data
-		job := createTestJob("test_job", StatusPending)
-		module := createTestModule("test_module", map[string]*JobState{
-			"test_job": job,
-		})
-		manager.SetModule(module)
-
-		// Set current
-		err := manager.SetCurrent("test_module", "test_job", StatusRunning)
-		if err != nil {
-			t.Errorf("Expected no error, got: %v", err)
-		}
+	job := reload(t, stateFile).Modules[0].Jobs[0]
+	if job.TasksCompleted != 2 {
+		t.Errorf("Expected 2 completed tasks, got: %d", job.TasksCompleted)
+	}
+	if job.Tasks[1].Status != StatusPending {
+		t.Errorf("Expected task 2 to stay %s, got: %s", StatusPending, job.Tasks[1].Status)
+	}
 
-		// Verify global state was updated
-		state := manager.GetState()
-		if state.Global.CurrentModule != "test_module" {
-			t.Errorf("Expected global current module 'test_module', got: %s", state.Global.CurrentModule)
-		}
-		if state.Global.CurrentJob != "test_job" {
-			t.Errorf("Expected global current job 'test_job', got: %s", state.Global.CurrentJob)
-		}
-		if state.Global.Status != StatusRunning {
-			t.Errorf("Expected global status RUNNING, got: %s", state.Global.Status)
-		}
-	})
+	if err := manager.UpdateTasksCompleted("test_module", "test_job", 3); err != nil {
+		t.Fatalf("UpdateTasksCompleted failed: %v", err)
+	}
+	if got := manager.GetJob("test_module", "test_job").TasksCompleted; got != 3 {
+		t.Errorf("Expected 3 completed tasks, got: %d", got)
+	}
+}
 
-	t.Run("SetCurrent with invalid status returns error", func(t *testing.T) {
-		manager, _ := setupTestManager(t)
+// TestUpdateFailureReason tests that the failure reason is saved.
+func TestUpdateFailureReason(t *testing.T) {
+	manager, stateFile := setupTestManager(t, createTestJob("test_job", StatusFailed))
 
-		// Setup test data
-		job := createTestJob("test_job", StatusPending)
-		module := createTestModule("test_module", map[string]*JobState{
-			"test_job": job,
-		})
-		manager.SetModule(module)
+	if err := manager.UpdateFailureReason("test_module", "test_job", "build failed"); err != nil {
+		t.Fatalf("UpdateFailureReason failed: %v", err)
+	}
+	if got := reload(t, stateFile).Modules[0].Jobs[0].FailureReason; got != "build failed" {
+		t.Errorf("Expected failure reason %q, got: %q", "build failed", got)
+	}
+	if err := manager.UpdateFailureReason("test_module", "non_existent_job", "x"); err == nil {
+		t.Error("Expected error for non-existent job, got nil")
+	}
+}
 
-		// Try to set with invalid status
-		err := manager.SetCurrent("test_module", "test_job", Status("INVALID"))
-		if err == nil {
-			t.Error("Expected error for invalid status, got nil")
-		}
-	})
+// TestBlockJob tests that a blocked job records what blocked it.
+func TestBlockJob(t *testing.T) {
+	manager, _ := setupTestManager(t,
+		createTestJob("job1", StatusFailed),
+		createTestJob("job2", StatusPending),
+	)
 
-	t.Run("SetCurrent with non-existent module returns error", func(t *testing.T) {
-		manager, _ := setupTestManager(t)
+	if err := manager.BlockJob("test_module", "job2", "test_module/job1", "depends on failed job"); err != nil {
+		t.Fatalf("BlockJob failed: %v", err)
+	}
+	job := manager.GetJob("test_module", "job2")
+	if job.Status != StatusBlocked || job.BlockedBy != "test_module/job1" || job.FailureReason == "" {
+		t.Errorf("Unexpected blocked job: %s, %q, %q", job.Status, job.BlockedBy, job.FailureReason)
+	}
+}
 
-		err := manager.SetCurrent("non_existent_module", "test_job", StatusRunning)
-		if err == nil {
-			t.Error("Expected error for non-existent module, got nil")
-		}
-	})
+// TestInterruptJob tests that only a RUNNING job can be interrupted and that
+// it is picked before pending jobs.
+func TestInterruptJob(t *testing.T) {
+	manager, stateFile := setupTestManager(t,
+		createTestJob("job1", StatusPending),
+		createTestJob("job2", StatusRunning),
+	)
 
-	t.Run("SetCurrent with non-existent job returns error", func(t *testing.T) {
-		manager, _ := setupTestManager(t)
+	if err := manager.InterruptJob("test_module", "job1", "interrupted"); err == nil {
+		t.Error("Expected an error interrupting a PENDING job")
+	}
+	if err := manager.InterruptJob("test_module", "job2", "interrupted"); err != nil {
+		t.Fatalf("InterruptJob failed: %v", err)
+	}
+	if err := manager.InterruptJob("test_module", "job2", "again"); err == nil {
+		t.Error("Expected an error interrupting an INTERRUPTED job")
+	}
 
-		// Setup module without the job
-		module := createTestModule("test_module", map[string]*JobState{})
-		manager.SetModule(module)
+	loaded := reload(t, stateFile)
+	job := loaded.Modules[0].Jobs[1]
+	if job.Status != StatusInterrupted || job.FailureReason != "interrupted" {
+		t.Errorf("Unexpected interrupted job: %s, %q", job.Status, job.FailureReason)
+	}
+	if mi, ji := loaded.GetNextPendingJob(); mi != 0 || ji != 1 {
+		t.Errorf("GetNextPendingJob() = %d, %d; want the interrupted job", mi, ji)
+	}
+}
 
-		err := manager.SetCurrent("test_module", "non_existent_job", StatusRunning)
-		if err == nil {
-			t.Error("Expected error for non-existent job, got nil")
-		}
-	})
+// TestJobDigestAndSessions tests recording digests and sessions.
+func TestJobDigestAndSessions(t *testing.T) {
+	manager, stateFile := setupTestManager(t, createTestJob("test_job", StatusRunning))
 
-	t.Run("SetCurrent with nil state returns error", func(t *testing.T) {
-		manager := NewManager("/tmp/test.json")
+	if err := manager.FailJobSession("test_module", "test_job", "no session yet"); err != nil {
+		t.Errorf("FailJobSession without a session failed: %v", err)
+	}
+	if err := manager.AddJobSession("test_module", "test_job", JobSession{Attempt: 1, ID: "abc"}); err != nil {
+		t.Fatalf("AddJobSession failed: %v", err)
+	}
+	if err := manager.FailJobSession("test_module", "test_job", "timeout"); err != nil {
+		t.Fatalf("FailJobSession failed: %v", err)
+	}
+	if err := manager.SetJobDigest("test_module", "test_job", &JobDigest{Summary: "done"}); err != nil {
+		t.Fatalf("SetJobDigest failed: %v", err)
+	}
 
-		err := manager.SetCurrent("test_module", "test_job", StatusRunning)
-		if err == nil {
-			t.Error("Expected error when state is nil, got nil")
-		}
-	})
+	job := reload(t, stateFile).Modules[0].Jobs[0]
+	session := job.LastSession()
+	if session == nil || session.ID != "abc" || session.Error != "timeout" {
+		t.Errorf("Unexpected last session: %+v", session)
+	}
+	if job.Digest == nil || job.Digest.Summary != "done" {
+		t.Errorf("Unexpected digest: %+v", job.Digest)
+	}
 }
 
-// TestGetSummary tests the GetSummary method.
-func TestGetSummary(t *testing.T) {
-	t.Run("GetSummary returns correct statistics", func(t *testing.T) {
-		manager, _ := setupTestManager(t)
-
-		// Setup test data
-		module1 := createTestModule("module1", map[string]*JobState{
-			"job1": createTestJob("job1", StatusPending),
-			"job2": createTestJob("job2", StatusRunning),
-			"job3": createTestJob("job3", StatusCompleted),
-		})
-		module2 := createTestModule("module2", map[string]*JobState{
-			"job4": createTestJob("job4", StatusFailed),
-			"job5": createTestJob("job5", StatusBlocked),
-		})
-		manager.SetModule(module1)
-		manager.SetModule(module2)
-
-		// Get summary
-		summary, err := manager.GetSummary()
-		if err != nil {
-			t.Errorf("Expected no error, got: %v", err)
+// TestGetCurrent tests finding the running job.
+func TestGetCurrent(t *testing.T) {
+	t.Run("returns the job recorded by SetCurrent", func(t *testing.T) {
+		manager, _ := setupTestManager(t,
+			createTestJob("job1", StatusRunning),
+			createTestJob("job2", StatusPending),
+		)
+		if err := manager.SetCurrent("test_module", "job2", StatusRunning); err != nil {
+			t.Fatalf("SetCurrent failed: %v", err)
 		}
 
-		// Verify counts
-		if summary.TotalModules != 2 {
-			t.Errorf("Expected 2 total modules, got: %d", summary.TotalModules)
-		}
-		if summary.TotalJobs != 5 {
-			t.Errorf("Expected 5 total jobs, got: %d", summary.TotalJobs)
-		}
-		if summary.Pending != 1 {
-			t.Errorf("Expected 1 pending job, got: %d", summary.Pending)
-		}
-		if summary.Running != 1 {
-			t.Errorf("Expected 1 running job, got: %d", summary.Running)
-		}
-		if summary.Completed != 1 {
-			t.Errorf("Expected 1 completed job, got: %d", summary.Completed)
+		current, err := manager.GetCurrent()
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
 		}
-		if summary.Failed != 1 {
-			t.Errorf("Expected 1 failed job, got: %d", summary.Failed)
+		if current == nil || current.Module != "test_module" || current.Job != "job2" {
+			t.Errorf("Expected test_module/job2, got: %+v", current)
 		}
-		if summary.Blocked != 1 {
-			t.Errorf("Expected 1 blocked job, got: %d", summary.Blocked)
+	})
+
+	t.Run("uses the start of the latest session", func(t *testing.T) {
+		manager, _ := setupTestManager(t, createTestJob("test_job", StatusRunning))
+		started := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+		if err := manager.AddJobSession("test_module", "test_job", JobSession{Attempt: 1, StartedAt: started}); err != nil {
+			t.Fatalf("AddJobSession failed: %v", err)
 		}
 
-		// Verify per-module statistics
-		if len(summary.Modules) != 2 {
-			t.Errorf("Expected 2 module summaries, got: %d", len(summary.Modules))
+		current, err := manager.GetCurrent()
+		if err != nil || current == nil {
+			t.Fatalf("Expected current job, got: %+v, %v", current, err)
+		}
+		if current.Status != StatusRunning || !current.StartedAt.Equal(started) {
+			t.Errorf("Expected RUNNING since %v, got: %+v", started, current)
 		}
 	})
 
-	t.Run("GetSummary with empty state returns zero counts", func(t *testing.T) {
-		manager, _ := setupTestManager(t)
+	t.Run("returns nil when no job is running", func(t *testing.T) {
+		manager, _ := setupTestManager(t, createTestJob("pending_job", StatusPending))
 
-		summary, err := manager.GetSummary()
+		current, err := manager.GetCurrent()
 		if err != nil {
 			t.Errorf("Expected no error, got: %v", err)
 		}
-
-		if summary.TotalModules != 0 {
-			t.Errorf("Expected 0 total modules, got: %d", summary.TotalModules)
-		}
-		if summary.TotalJobs != 0 {
-			t.Errorf("Expected 0 total jobs, got: %d", summary.TotalJobs)
+		if current != nil {
+			t.Errorf("Expected nil, got: %+v", current)
 		}
 	})
 
-	t.Run("GetSummary with nil state returns error", func(t *testing.T) {
-		manager := NewManager("/tmp/test.json")
+	t.Run("returns an error when status is not loaded", func(t *testing.T) {
+		manager := setupUnloadedManager(t)
 
-		_, err := manager.GetSummary()
-		if err == nil {
-			t.Error("Expected error when state is nil, got nil")
+		if _, err := manager.GetCurrent(); err == nil {
+			t.Error("Expected error when status is nil, got nil")
 		}
 	})
 }
 
-// TestGetPendingJobs tests the GetPendingJobs method.
-func TestGetPendingJobs(t *testing.T) {
-	t.Run("GetPendingJobs returns only pending jobs", func(t *testing.T) {
-		manager, _ := setupTestManager(t)
-
-		// Setup test data with mixed statuses
-		module1 := createTestModule("module1", map[string]*JobState{
-			"pending1":  createTestJob("pending1", StatusPending),
-			"running1":  createTestJob("running1", StatusRunning),
-			"pending2":  createTestJob("pending2", StatusPending),
-		})
-		module2 := createTestModule("module2", map[string]*JobState{
-			"completed": createTestJob("completed", StatusCompleted),
-			"pending3":  createTestJob("pending3", StatusPending),
-		})
-		manager.SetModule(module1)
-		manager.SetModule(module2)
-
-		// Get pending jobs
-		pending := manager.GetPendingJobs()
-
-		// Should have 3 pending jobs
-		if len(pending) != 3 {
-			t.Errorf("Expected 3 pending jobs, got: %d", len(pending))
-		}
+// TestGetSummary tests the job counts per status.
+func TestGetSummary(t *testing.T) {
+	t.Run("counts jobs per status and module", func(t *testing.T) {
+		manager, _ := setupTestManager(t,
+			createTestJob("job1", StatusPending),
+			createTestJob("job2", StatusRunning),
+			createTestJob("job3", StatusCompleted),
+			createTestJob("job4", StatusFailed),
+			createTestJob("job5", StatusBlocked),
+			createTestJob("job6", StatusInterrupted),
+		)
 
-		// Verify all returned jobs are pending
-		for _, jobRef := range pending {
-			if jobRef.Status != StatusPending {
-				t.Errorf("Expected status PENDING, got: %s", jobRef.Status)
-			}
+		summary, err := manager.GetSummary()
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
 		}
-	})
-
-	t.Run("GetPendingJobs returns empty slice when no pending jobs", func(t *testing.T) {
-		manager, _ := setupTestManager(t)
-
-		// Setup test data with no pending jobs
-		module := createTestModule("module1", map[string]*JobState{
-			"running":   createTestJob("running", StatusRunning),
-			"completed": createTestJob("completed", StatusCompleted),
-		})
-		manager.SetModule(module)
 
-		pending := manager.GetPendingJobs()
-
-		if len(pending) != 0 {
-			t.Errorf("Expected 0 pending jobs, got: %d", len(pending))
+		want := ModuleSummary{
+			Status:      StatusPending,
+			TotalJobs:   6,
+			Pending:     1,
+			Running:     1,
+			Completed:   1,
+			Failed:      1,
+			Blocked:     1,
+			Interrupted: 1,
 		}
-	})
-
-	t.Run("GetPendingJobs returns empty slice when state is nil", func(t *testing.T) {
-		manager := NewManager("/tmp/test.json")
-
-		pending := manager.GetPendingJobs()
-
-		if len(pending) != 0 {
-			t.Errorf("Expected 0 pending jobs when state is nil, got: %d", len(pending))
+		if summary.TotalModules != 1 || summary.TotalJobs != 6 {
+			t.Errorf("Expected 1 module and 6 jobs, got: %d and %d", summary.TotalModules, summary.TotalJobs)
+		}
+		if summary.Pending != 1 || summary.Running != 1 || summary.Completed != 1 ||
+			summary.Failed != 1 || summary.Blocked != 1 || summary.Interrupted != 1 {
+			t.Errorf("Expected one job per status, got: %+v", summary)
+		}
+		if got := summary.Modules["test_module"]; got != want {
+			t.Errorf("Expected module summary %+v, got: %+v", want, got)
 		}
 	})
 
-	t.Run("GetPendingJobs returns empty slice for empty state", func(t *testing.T) {
-		manager, _ := setupTestManager(t)
-
-		pending := manager.GetPendingJobs()
+	t.Run("returns an error when status is not loaded", func(t *testing.T) {
+		manager := setupUnloadedManager(t)
 
-		if len(pending) != 0 {
-			t.Errorf("Expected 0 pending jobs for empty state, got: %d", len(pending))
+		if _, err := manager.GetSummary(); err == nil {
+			t.Error("Expected error when status is nil, got nil")
 		}
 	})
 }
 
-// TestIntegration tests the integration of manager methods.
-func TestIntegration(t *testing.T) {
-	t.Run("Full workflow: create, update, query jobs", func(t *testing.T) {
-		manager, stateFile := setupTestManager(t)
-
-		// 1. Create a module with jobs
-		module := createTestModule("test_module", map[string]*JobState{
-			"job1": createTestJob("job1", StatusPending),
-			"job2": createTestJob("job2", StatusPending),
-		})
-		manager.SetModule(module)
-
-		// 2. Verify initial state
-		summary, _ := manager.GetSummary()
-		if summary.TotalJobs != 2 {
-			t.Errorf("Expected 2 jobs, got: %d", summary.TotalJobs)
-		}
-		if summary.Pending != 2 {
-			t.Errorf("Expected 2 pending, got: %d", summary.Pending)
-		}
-
-		// 3. Set current job
-		err := manager.SetCurrent("test_module", "job1", StatusRunning)
-		if err != nil {
-			t.Errorf("SetCurrent failed: %v", err)
-		}
-
-		// 4. Update job status
-		err = manager.UpdateJobStatus("test_module", "job1", StatusRunning)
-		if err != nil {
-			t.Errorf("UpdateJobStatus failed: %v", err)
+// TestGetPendingJobs tests listing the pending jobs.
+func TestGetPendingJobs(t *testing.T) {
+	manager, _ := setupTestManager(t,
+		createTestJob("pending1", StatusPending),
+		createTestJob("running1", StatusRunning),
+		createTestJob("pending2", StatusPending),
+		createTestJob("completed", StatusCompleted),
+	)
+
+	pending := manager.GetPendingJobs()
+	if len(pending) != 2 || pending[0].Job != "pending1" || pending[1].Job != "pending2" {
+		t.Fatalf("Expected pending1 and pending2, got: %+v", pending)
+	}
+	for _, ref := range pending {
+		if ref.Module != "test_module" || ref.Status != StatusPending {
+			t.Errorf("Unexpected pending job: %+v", ref)
 		}
+	}
 
-		// 5. Verify current job
-		current, _ := manager.GetCurrent()
-		if current == nil {
-			t.Fatal("Expected current job")
-		}
-		if current.Job != "job1" {
-			t.Errorf("Expected current job 'job1', got: %s", current.Job)
-		}
+	if pending := setupUnloadedManager(t).GetPendingJobs(); len(pending) != 0 {
+		t.Errorf("Expected no pending jobs when status is nil, got: %d", len(pending))
+	}
+}
 
-		// 6. Complete job
-		err = manager.UpdateJobStatus("test_module", "job1", StatusCompleted)
-		if err != nil {
-			t.Errorf("Failed to complete job: %v", err)
-		}
+// TestIntegration tests a job going through its main workflow.
+func TestIntegration(t *testing.T) {
+	manager, stateFile := setupTestManager(t,
+		createTestJob("job1", StatusPending),
+		createTestJob("job2", StatusPending),
+	)
 
-		// 7. Verify updated summary
-		summary, _ = manager.GetSummary()
-		if summary.Completed != 1 {
-			t.Errorf("Expected 1 completed, got: %d", summary.Completed)
-		}
-		if summary.Pending != 1 {
-			t.Errorf("Expected 1 pending, got: %d", summary.Pending)
-		}
+	if err := manager.TransitionJobStatus("test_module", "job1", StatusRunning, nil); err != nil {
+		t.Fatalf("Failed to start job: %v", err)
+	}
+	if err := manager.TransitionJobStatus("test_module", "job1", StatusCompleted, nil); err != nil {
+		t.Fatalf("Failed to complete job: %v", err)
+	}
 
-		// 8. Verify state file exists
-		if _, err := os.Stat(stateFile); os.IsNotExist(err) {
-			t.Error("Expected state file to exist")
-		}
-	})
+	saved := reload(t, stateFile)
+	if got := saved.CountCompletedJobs(); got != 1 {
+		t.Errorf("Expected 1 completed job, got: %d", got)
+	}
+	if mi, ji := saved.GetNextPendingJob(); mi != 0 || ji != 1 {
+		t.Errorf("Expected next pending job 0/1, got: %d/%d", mi, ji)
+	}
+	if saved.Global.Status == StatusCompleted {
+		t.Error("Run completed with a job still pending")
+	}
+	if _, err := os.Stat(stateFile); err != nil {
+		t.Errorf("Expected state file to exist: %v", err)
+	}
 }
@@ -21,18 +21,26 @@ const (
 	StatusFailed Status = "FAILED"
 	// StatusBlocked indicates the job/module is blocked by dependencies.
 	StatusBlocked Status = "BLOCKED"
+	// StatusInterrupted indicates the job was interrupted while running and
+	// resumes on the next run.
+	StatusInterrupted Status = "INTERRUPTED"
 )
 
 // IsValid checks if the status is a valid value.
 func (s Status) IsValid() bool {
 	switch s {
-	case StatusPending, StatusRunning, StatusCompleted, StatusFailed, StatusBlocked:
+	case StatusPending, StatusRunning, StatusCompleted, StatusFailed, StatusBlocked, StatusInterrupted:
 		return true
 	default:
 		return false
 	}
 }
 
+// String returns the status as a string.
+func (s Status) String() string {
+	return string(s)
+}
+
 // TaskState represents the state of an individual task.
 type TaskState struct {
 	// Index is the task's position in the job's task list (1-based)
@@ -59,6 +67,41 @@ type DebugLogEntry struct {
 	Hypothesis string `json:"hypothesis"`
 }
 
+// JobDigest is a compact record of the work a completed job did, passed to
+// the prompts of later jobs.
+type JobDigest struct {
+	// Files lists the changed files as "<git status letter> <path>"
+	Files []string `json:"files,omitempty"`
+	// Symbols lists the exported symbols the job added as "<path>: <declaration>"
+	Symbols []string `json:"symbols,omitempty"`
+	// Summary is the agent's own summary from its RALPH_STATUS block
+	Summary string `json:"summary,omitempty"`
+	// CreatedAt is when the digest was recorded
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// JobSession is the AI CLI session an attempt of a job ran in.
+type JobSession struct {
+	// Attempt is the attempt number, starting at 1
+	Attempt int `json:"attempt"`
+	// ID is the session id reported by the AI CLI (empty if none was seen)
+	ID string `json:"id,omitempty"`
+	// Resumed is true when the attempt continued the previous session
+	Resumed bool `json:"resumed,omitempty"`
+	// Error is why the attempt failed (empty while running or on success)
+	Error string `json:"error,omitempty"`
+	// StartedAt is when the attempt called the AI CLI
+	StartedAt time.Time `json:"started_at"`
+}
+
+// LastSession returns the session of the latest attempt, or nil.
+func (j *JobState) LastSession() *JobSession {
+	if len(j.Sessions) == 0 {
+		return nil
+	}
+	return &j.Sessions[len(j.Sessions)-1]
+}
+
 // ExecutionStatus represents the overall execution status format.
 // Modules and jobs are stored in arrays, topologically sorted at generation time.
 type ExecutionStatus struct {
@@ -86,6 +129,8 @@ type GlobalState struct {
 	TotalModules int `json:"total_modules"`
 	// TotalJobs is the total number of jobs across all modules
 	TotalJobs int `json:"total_jobs"`
+	// Profile is the configuration profile of the latest doing run
+	Profile string `json:"profile,omitempty"`
 }
 
 // ModuleState represents a module in V2 format.
@@ -132,10 +177,16 @@ type JobState struct {
 	RetryCount int `json:"retry_count"`
 	// FailureReason contains error message if failed
 	FailureReason string `json:"failure_reason,omitempty"`
+	// BlockedBy is the failed job ("module/job") that blocked this job
+	BlockedBy string `json:"blocked_by,omitempty"`
 	// Tasks contains the task states
 	Tasks []TaskState `json:"tasks,omitempty"`
 	// DebugLogs contains debug entries
 	DebugLogs []DebugLogEntry `json:"debug_logs,omitempty"`
+	// Digest summarizes what the job produced once it completed
+	Digest *JobDigest `json:"digest,omitempty"`
+	// Sessions records the AI CLI session of each attempt, oldest first
+	Sessions []JobSession `json:"sessions,omitempty"`
 	// CreatedAt is when the job was added
 	CreatedAt time.Time `json:"created_at"`
 	// UpdatedAt is the last update timestamp
@@ -162,9 +213,17 @@ func (m *ModuleState) GetJobByName(name string) *JobState {
 	return nil
 }
 
-// GetNextPendingJob finds the next pending job in V2 status.
+// GetNextPendingJob finds the next pending job in V2 status. An interrupted
+// job comes before every pending one, since it resumes where it stopped.
 // Returns module index, job index, or -1, -1 if no pending job found.
 func (s *ExecutionStatus) GetNextPendingJob() (int, int) {
+	for mi, module := range s.Modules {
+		for ji, job := range module.Jobs {
+			if job.Status == StatusInterrupted {
+				return mi, ji
+			}
+		}
+	}
 	for mi, module := range s.Modules {
 		for ji, job := range module.Jobs {
 			if job.Status == StatusPending {
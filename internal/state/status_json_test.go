@@ -70,62 +70,38 @@ func TestNewManager(t *testing.T) {
 	if m.filePath != path {
 		t.Errorf("filePath = %s, expected %s", m.filePath, path)
 	}
-	if m.state != nil {
-		t.Error("initial state should be nil")
-	}
 }
 
-// TestLoadFileNotExist verifies loading when file doesn't exist creates default state.
+// TestLoadFileNotExist verifies loading when the file doesn't exist leaves no status.
 func TestLoadFileNotExist(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "nonexistent", "status.json")
+	statePath := filepath.Join(t.TempDir(), "nonexistent", "status.json")
 
 	m := NewManager(statePath)
-	err := m.Load()
-	if err != nil {
+	if err := m.Load(); err != nil {
 		t.Fatalf("Load() error = %v", err)
 	}
-
-	state := m.GetState()
-	if state == nil {
-		t.Fatal("GetState() returned nil after Load()")
-	}
-
-	// Verify default state
-	if state.Version != DefaultStateVersion {
-		t.Errorf("Version = %s, expected %s", state.Version, DefaultStateVersion)
-	}
-	if state.Global.Status != StatusPending {
-		t.Errorf("Global.Status = %s, expected %s", state.Global.Status, StatusPending)
-	}
-	if len(state.Modules) != 0 {
-		t.Errorf("len(Modules) = %d, expected 0", len(state.Modules))
+	if m.GetState() != nil {
+		t.Error("GetState() should be nil when there is no status file")
 	}
 }
 
 // TestLoadExistingFile verifies loading an existing state file.
 func TestLoadExistingFile(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
+	statePath := filepath.Join(t.TempDir(), "status.json")
 
-	// Create a test state file
-	testState := &StatusJSON{
-		Version: "1.0",
+	testState := &ExecutionStatus{
+		Version: "2.0",
 		Global: GlobalState{
 			Status:     StatusRunning,
 			StartTime:  time.Now(),
 			LastUpdate: time.Now(),
-			TotalLoops: 5,
-		},
-		Modules: map[string]*ModuleState{
-			"test_module": {
-				Name:      "test_module",
-				Status:    StatusRunning,
-				Jobs:      make(map[string]*JobState),
-				CreatedAt: time.Now(),
-				UpdatedAt: time.Now(),
-			},
+			TotalJobs:  1,
 		},
+		Modules: []ModuleState{{
+			Name:   "test_module",
+			Status: StatusRunning,
+			Jobs:   []JobState{{Name: "test_job", Status: StatusRunning, TasksTotal: 2}},
+		}},
 	}
 
 	data, _ := json.MarshalIndent(testState, "", "  ")
@@ -133,7 +109,6 @@ func TestLoadExistingFile(t *testing.T) {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
 
-	// Load the state
 	m := NewManager(statePath)
 	if err := m.Load(); err != nil {
 		t.Fatalf("Load() error = %v", err)
@@ -143,24 +118,22 @@ func TestLoadExistingFile(t *testing.T) {
 	if state == nil {
 		t.Fatal("GetState() returned nil")
 	}
-
 	if state.Global.Status != StatusRunning {
 		t.Errorf("Global.Status = %s, expected %s", state.Global.Status, StatusRunning)
 	}
-	if state.Global.TotalLoops != 5 {
-		t.Errorf("Global.TotalLoops = %d, expected 5", state.Global.TotalLoops)
+	module := state.GetModuleByName("test_module")
+	if module == nil {
+		t.Fatal("test_module not found in modules")
 	}
-	if _, ok := state.Modules["test_module"]; !ok {
-		t.Error("test_module not found in modules")
+	if job := module.GetJobByName("test_job"); job == nil || job.TasksTotal != 2 {
+		t.Errorf("test_job = %+v, expected 2 tasks", job)
 	}
 }
 
 // TestLoadCorruptedFile verifies loading a corrupted state file returns error.
 func TestLoadCorruptedFile(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
+	statePath := filepath.Join(t.TempDir(), "status.json")
 
-	// Create a corrupted file
 	if err := os.WriteFile(statePath, []byte("not valid json"), 0644); err != nil {
 		t.Fatalf("Failed to create test file: %v", err)
 	}
@@ -170,332 +143,329 @@ func TestLoadCorruptedFile(t *testing.T) {
 	if err == nil {
 		t.Fatal("Load() expected error for corrupted file")
 	}
-
-	// Check that it's a MortyError with correct code
-	if me, ok := errors.AsMortyError(err); ok {
-		if me.Code != "M2002" {
-			t.Errorf("Error code = %s, expected M2002", me.Code)
-		}
-	} else {
-		t.Error("Expected MortyError for corrupted file")
+	if me, ok := errors.AsMortyError(err); !ok || me.Code != "M2002" {
+		t.Errorf("Load() error = %v, expected M2002", err)
 	}
 }
 
-// TestLoadInvalidState verifies loading a state with invalid status returns error.
+// TestLoadInvalidState verifies that Load rejects a status that does not
+// validate.
 func TestLoadInvalidState(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
-
-	// Create a state with invalid status
-	testState := &StatusJSON{
-		Version: "1.0",
-		Global: GlobalState{
-			Status:     Status("INVALID_STATUS"),
-			StartTime:  time.Now(),
-			LastUpdate: time.Now(),
-		},
-		Modules: make(map[string]*ModuleState),
+	validJob := JobState{Name: "test_job", Status: StatusPending}
+	tests := []struct {
+		name   string
+		status ExecutionStatus
+	}{
+		{"missing version", ExecutionStatus{
+			Global: GlobalState{Status: StatusRunning},
+		}},
+		{"invalid global status", ExecutionStatus{
+			Version: "2.0",
+			Global:  GlobalState{Status: Status("INVALID_STATUS")},
+		}},
+		{"invalid module status", ExecutionStatus{
+			Version: "2.0",
+			Modules: []ModuleState{{Name: "test_module", Status: Status("INVALID"), Jobs: []JobState{validJob}}},
+		}},
+		{"invalid job status", ExecutionStatus{
+			Version: "2.0",
+			Modules: []ModuleState{{Name: "test_module", Jobs: []JobState{{Name: "test_job", Status: Status("INVALID")}}}},
+		}},
+		{"missing job status", ExecutionStatus{
+			Version: "2.0",
+			Modules: []ModuleState{{Name: "test_module", Jobs: []JobState{{Name: "test_job"}}}},
+		}},
 	}
 
-	data, _ := json.MarshalIndent(testState, "", "  ")
-	if err := os.WriteFile(statePath, data, 0644); err != nil {
-		t.Fatalf("Failed to create test file: %v", err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statePath := filepath.Join(t.TempDir(), "status.json")
+			data, _ := json.MarshalIndent(tt.status, "", "  ")
+			if err := os.WriteFile(statePath, data, 0644); err != nil {
+				t.Fatalf("Failed to create test file: %v", err)
+			}
+
+			err := NewManager(statePath).Load()
+			if err == nil {
+				t.Fatal("Load() expected error")
+			}
+			if me, ok := errors.AsMortyError(err); !ok || me.Code != "M2003" {
+				t.Errorf("Load() error = %v, expected M2003", err)
+			}
+		})
 	}
+}
 
-	m := NewManager(statePath)
-	err := m.Load()
-	if err == nil {
-		t.Fatal("Load() expected error for invalid status")
+// TestLoadStatError verifies Load reports read errors other than a missing file.
+func TestLoadStatError(t *testing.T) {
+	notADir := filepath.Join(t.TempDir(), "not_a_dir")
+	if err := os.WriteFile(notADir, []byte("test"), 0644); err != nil {
+		t.Fatalf("Failed to create test file: %v", err)
 	}
 
-	if me, ok := errors.AsMortyError(err); ok {
-		if me.Code != "M2003" {
-			t.Errorf("Error code = %s, expected M2003", me.Code)
-		}
-	} else {
-		t.Error("Expected MortyError for invalid state")
+	if err := NewManager(filepath.Join(notADir, "status.json")).Load(); err == nil {
+		t.Fatal("Load() expected error for invalid path")
 	}
 }
 
 // TestSave verifies saving state to file.
 func TestSave(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
-
+	statePath := filepath.Join(t.TempDir(), "status.json")
 	m := NewManager(statePath)
 
-	// Create and set a state
-	state := &StatusJSON{
-		Version: "1.0",
+	state := &ExecutionStatus{
+		Version: "2.0",
 		Global: GlobalState{
-			Status:     StatusRunning,
-			StartTime:  time.Now(),
-			LastUpdate: time.Now(),
-			TotalLoops: 3,
+			Status:       StatusRunning,
+			StartTime:    time.Now(),
+			LastUpdate:   time.Now(),
+			TotalModules: 3,
 		},
-		Modules: make(map[string]*ModuleState),
 	}
-	m.SetState(state)
-
-	// Save
-	if err := m.Save(); err != nil {
+	if err := m.Save(state); err != nil {
 		t.Fatalf("Save() error = %v", err)
 	}
+	if m.GetState() != state {
+		t.Error("GetState() should return the saved status")
+	}
 
-	// Verify file exists and has correct content
 	data, err := os.ReadFile(statePath)
 	if err != nil {
 		t.Fatalf("Failed to read saved file: %v", err)
 	}
 
-	var savedState StatusJSON
+	var savedState ExecutionStatus
 	if err := json.Unmarshal(data, &savedState); err != nil {
 		t.Fatalf("Failed to parse saved file: %v", err)
 	}
-
 	if savedState.Global.Status != StatusRunning {
 		t.Errorf("Saved Global.Status = %s, expected %s", savedState.Global.Status, StatusRunning)
 	}
-	if savedState.Global.TotalLoops != 3 {
-		t.Errorf("Saved Global.TotalLoops = %d, expected 3", savedState.Global.TotalLoops)
-	}
-
-	// Verify JSON is indented
-	content := string(data)
-	if content[0] != '{' || content[len(content)-1] != '}' {
-		t.Error("Saved JSON should be an object")
+	if savedState.Global.TotalModules != 3 {
+		t.Errorf("Saved Global.TotalModules = %d, expected 3", savedState.Global.TotalModules)
 	}
 }
 
 // TestSaveCreatesDirectory verifies that Save creates parent directories.
 func TestSaveCreatesDirectory(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "nested", "deep", "status.json")
-
-	m := NewManager(statePath)
-
-	// Load to initialize default state
-	if err := m.Load(); err != nil {
-		t.Fatalf("Load() error = %v", err)
-	}
+	statePath := filepath.Join(t.TempDir(), "nested", "deep", "status.json")
 
-	// Save should create directories
-	if err := m.Save(); err != nil {
+	if err := NewManager(statePath).Save(&ExecutionStatus{Version: "2.0"}); err != nil {
 		t.Fatalf("Save() error = %v", err)
 	}
-
-	// Verify file exists
 	if _, err := os.Stat(statePath); err != nil {
 		t.Errorf("State file does not exist after Save: %v", err)
 	}
 }
 
-// TestBackup verifies creating a backup of the state file.
+// TestBackup verifies creating and listing backups of the state file.
 func TestBackup(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
-
-	// Create initial state file
+	statePath := filepath.Join(t.TempDir(), "status.json")
 	m := NewManager(statePath)
-	if err := m.Load(); err != nil {
-		t.Fatalf("Load() error = %v", err)
+
+	if _, err := m.Backup(); err == nil {
+		t.Fatal("Backup() expected error when file doesn't exist")
+	}
+	if backups, err := m.ListBackups(); err != nil || len(backups) != 0 {
+		t.Fatalf("ListBackups() = %v, %v, expected no backups", backups, err)
 	}
-	if err := m.Save(); err != nil {
+
+	if err := m.Save(&ExecutionStatus{Version: "2.0", Global: GlobalState{Status: StatusPending}}); err != nil {
 		t.Fatalf("Save() error = %v", err)
 	}
 
-	// Create backup
-	backupPath, err := m.Backup()
+	first, err := m.Backup()
 	if err != nil {
 		t.Fatalf("Backup() error = %v", err)
 	}
-
-	// Verify backup file exists
-	if _, err := os.Stat(backupPath); err != nil {
-		t.Errorf("Backup file does not exist: %v", err)
+	time.Sleep(10 * time.Millisecond) // Ensure different timestamps
+	second, err := m.Backup()
+	if err != nil {
+		t.Fatalf("Backup() error = %v", err)
 	}
 
-	// Verify backup contains valid JSON
-	data, err := os.ReadFile(backupPath)
+	data, err := os.ReadFile(first)
 	if err != nil {
 		t.Fatalf("Failed to read backup: %v", err)
 	}
-
-	var backupState StatusJSON
+	var backupState ExecutionStatus
 	if err := json.Unmarshal(data, &backupState); err != nil {
 		t.Errorf("Backup file contains invalid JSON: %v", err)
 	}
-}
-
-// TestBackupNoFile verifies backup returns error when state file doesn't exist.
-func TestBackupNoFile(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
 
-	m := NewManager(statePath)
-	_, err := m.Backup()
-	if err == nil {
-		t.Fatal("Backup() expected error when file doesn't exist")
+	backups, err := m.ListBackups()
+	if err != nil {
+		t.Fatalf("ListBackups() error = %v", err)
+	}
+	if len(backups) != 2 || backups[0] != first || backups[1] != second {
+		t.Errorf("ListBackups() = %v, expected [%s %s]", backups, first, second)
 	}
 }
 
-// TestListBackups verifies listing backup files.
-func TestListBackups(t *testing.T) {
+// TestBackupWithCustomPath verifies writing the loaded state to a custom path.
+func TestBackupWithCustomPath(t *testing.T) {
 	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
+	backupPath := filepath.Join(tmpDir, "backups", "custom_backup.json")
 
-	// Create initial state and save
-	m := NewManager(statePath)
+	m := NewManager(filepath.Join(tmpDir, "status.json"))
 	if err := m.Load(); err != nil {
 		t.Fatalf("Load() error = %v", err)
 	}
-	if err := m.Save(); err != nil {
-		t.Fatalf("Save() error = %v", err)
+	if err := m.BackupWithCustomPath(backupPath); err == nil {
+		t.Fatal("BackupWithCustomPath() expected error when state is nil")
 	}
 
-	// Create multiple backups
-	if _, err := m.Backup(); err != nil {
-		t.Fatalf("Backup() error = %v", err)
+	if err := m.Save(&ExecutionStatus{Version: "2.0", Global: GlobalState{TotalJobs: 99}}); err != nil {
+		t.Fatalf("Save() error = %v", err)
 	}
-	time.Sleep(10 * time.Millisecond) // Ensure different timestamps
-	if _, err := m.Backup(); err != nil {
-		t.Fatalf("Backup() error = %v", err)
+	if err := m.BackupWithCustomPath(backupPath); err != nil {
+		t.Fatalf("BackupWithCustomPath() error = %v", err)
 	}
 
-	// List backups
-	backups, err := m.ListBackups()
+	data, err := os.ReadFile(backupPath)
 	if err != nil {
-		t.Fatalf("ListBackups() error = %v", err)
+		t.Fatalf("Failed to read backup file: %v", err)
 	}
-
-	if len(backups) != 2 {
-		t.Errorf("len(backups) = %d, expected 2", len(backups))
+	var backupState ExecutionStatus
+	if err := json.Unmarshal(data, &backupState); err != nil {
+		t.Fatalf("Failed to parse backup: %v", err)
+	}
+	if backupState.Global.TotalJobs != 99 {
+		t.Errorf("Backup TotalJobs = %d, expected 99", backupState.Global.TotalJobs)
 	}
 }
 
 // TestRestoreFromBackup verifies restoring state from a backup.
 func TestRestoreFromBackup(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
-
-	// Create and save initial state
+	statePath := filepath.Join(t.TempDir(), "status.json")
 	m := NewManager(statePath)
-	if err := m.Load(); err != nil {
-		t.Fatalf("Load() error = %v", err)
-	}
 
-	// Set specific state
-	state := m.GetState()
-	state.Global.TotalLoops = 42
-	state.Global.Status = StatusCompleted
-	if err := m.Save(); err != nil {
+	if err := m.Save(&ExecutionStatus{Version: "2.0", Global: GlobalState{Status: StatusCompleted, TotalJobs: 42}}); err != nil {
 		t.Fatalf("Save() error = %v", err)
 	}
-
-	// Create backup
 	backupPath, err := m.Backup()
 	if err != nil {
 		t.Fatalf("Backup() error = %v", err)
 	}
-
-	// Modify state
-	state.Global.TotalLoops = 100
-	state.Global.Status = StatusFailed
-	if err := m.Save(); err != nil {
+	if err := m.Save(&ExecutionStatus{Version: "2.0", Global: GlobalState{Status: StatusFailed, TotalJobs: 100}}); err != nil {
 		t.Fatalf("Save() error = %v", err)
 	}
 
-	// Create new manager and restore from backup
 	m2 := NewManager(statePath)
 	if err := m2.RestoreFromBackup(backupPath); err != nil {
 		t.Fatalf("RestoreFromBackup() error = %v", err)
 	}
 
-	// Verify restored state
-	restoredState := m2.GetState()
-	if restoredState.Global.TotalLoops != 42 {
-		t.Errorf("TotalLoops = %d, expected 42", restoredState.Global.TotalLoops)
-	}
-	if restoredState.Global.Status != StatusCompleted {
-		t.Errorf("Status = %s, expected %s", restoredState.Global.Status, StatusCompleted)
+	for _, restored := range []*ExecutionStatus{m2.GetState(), reload(t, statePath)} {
+		if restored.Global.TotalJobs != 42 {
+			t.Errorf("TotalJobs = %d, expected 42", restored.Global.TotalJobs)
+		}
+		if restored.Global.Status != StatusCompleted {
+			t.Errorf("Status = %s, expected %s", restored.Global.Status, StatusCompleted)
+		}
 	}
 }
 
-// TestGetModuleAndJob verifies getting module and job states.
-func TestGetModuleAndJob(t *testing.T) {
+// TestRestoreFromBackupErrors verifies error cases for RestoreFromBackup.
+func TestRestoreFromBackupErrors(t *testing.T) {
 	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
+	m := NewManager(filepath.Join(tmpDir, "status.json"))
 
-	m := NewManager(statePath)
-	if err := m.Load(); err != nil {
-		t.Fatalf("Load() error = %v", err)
+	if err := m.RestoreFromBackup(filepath.Join(tmpDir, "nonexistent.json")); err == nil {
+		t.Error("RestoreFromBackup() expected error for non-existent file")
 	}
 
-	// Initially should return nil
-	if m.GetModule("test") != nil {
-		t.Error("GetModule should return nil for non-existent module")
+	corrupted := filepath.Join(tmpDir, "corrupted.json")
+	if err := os.WriteFile(corrupted, []byte("not json"), 0644); err != nil {
+		t.Fatalf("Failed to create corrupted file: %v", err)
 	}
-	if m.GetJob("test", "job") != nil {
-		t.Error("GetJob should return nil for non-existent job")
+	err := m.RestoreFromBackup(corrupted)
+	if me, ok := errors.AsMortyError(err); !ok || me.Code != "M2002" {
+		t.Errorf("RestoreFromBackup() error = %v, expected M2002", err)
 	}
 
-	// Add module and job
-	module := &ModuleState{
-		Name:      "test_module",
-		Status:    StatusRunning,
-		Jobs:      make(map[string]*JobState),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+	invalid := filepath.Join(tmpDir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"global": {"status": "PENDING"}}`), 0644); err != nil {
+		t.Fatalf("Failed to create invalid file: %v", err)
 	}
-	m.SetModule(module)
-
-	job := &JobState{
-		Name:           "test_job",
-		Status:         StatusCompleted,
-		TasksTotal:     5,
-		TasksCompleted: 5,
-		CreatedAt:      time.Now(),
-		UpdatedAt:      time.Now(),
+	err = m.RestoreFromBackup(invalid)
+	if me, ok := errors.AsMortyError(err); !ok || me.Code != "M2003" {
+		t.Errorf("RestoreFromBackup() error = %v, expected M2003", err)
 	}
-	m.SetJob("test_module", job)
-
-	// Now should be able to retrieve
-	gotModule := m.GetModule("test_module")
-	if gotModule == nil {
-		t.Fatal("GetModule returned nil for existing module")
+	if _, err := os.Stat(filepath.Join(tmpDir, "status.json")); !os.IsNotExist(err) {
+		t.Error("RestoreFromBackup() wrote an invalid backup to the status file")
 	}
-	if gotModule.Status != StatusRunning {
-		t.Errorf("Module.Status = %s, expected %s", gotModule.Status, StatusRunning)
+}
+
+// TestDetectVersion verifies the status file format detection.
+func TestDetectVersion(t *testing.T) {
+	tmpDir := t.TempDir()
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+		wantErr  bool
+	}{
+		{"v2", `{"version": "2.0", "modules": []}`, "2.0", false},
+		{"v1", `{"version": "1.0", "modules": {"core": {}}}`, "1.0", false},
+		{"unknown", `{"modules": []}`, "", true},
+		{"invalid", `not json`, "", true},
 	}
 
-	gotJob := m.GetJob("test_module", "test_job")
-	if gotJob == nil {
-		t.Fatal("GetJob returned nil for existing job")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(tmpDir, tt.name+".json")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			version, err := DetectVersion(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DetectVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if version != tt.expected {
+				t.Errorf("DetectVersion() = %q, expected %q", version, tt.expected)
+			}
+		})
 	}
-	if gotJob.Status != StatusCompleted {
-		t.Errorf("Job.Status = %s, expected %s", gotJob.Status, StatusCompleted)
+
+	if version, err := DetectVersion(filepath.Join(tmpDir, "missing.json")); err != nil || version != "" {
+		t.Errorf("DetectVersion() of a missing file = %q, %v", version, err)
 	}
 }
 
-// TestSetJobCreatesModule verifies SetJob creates module if it doesn't exist.
-func TestSetJobCreatesModule(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
+// TestExecutionStatusQueries verifies the lookups and counts of a status.
+func TestExecutionStatusQueries(t *testing.T) {
+	status := &ExecutionStatus{Modules: []ModuleState{
+		{Name: "core", DisplayName: "核心", Jobs: []JobState{
+			{Name: "types", Status: StatusCompleted},
+			{Name: "parser", Status: StatusCompleted},
+		}},
+		{Name: "cli", Jobs: []JobState{
+			{Name: "flags", Status: StatusCompleted},
+			{Name: "commands", Status: StatusPending},
+		}},
+		{Name: "empty"},
+	}}
 
-	m := NewManager(statePath)
-	if err := m.Load(); err != nil {
-		t.Fatalf("Load() error = %v", err)
+	if status.GetModuleByName("核心") == nil {
+		t.Error("GetModuleByName() should match the display name")
 	}
-
-	job := &JobState{
-		Name:   "test_job",
-		Status: StatusPending,
+	if status.GetModuleByName("missing") != nil {
+		t.Error("GetModuleByName() found a missing module")
+	}
+	if mi, ji := status.GetNextPendingJob(); mi != 1 || ji != 1 {
+		t.Errorf("GetNextPendingJob() = %d/%d, expected 1/1", mi, ji)
+	}
+	if got := status.CountCompletedJobs(); got != 3 {
+		t.Errorf("CountCompletedJobs() = %d, expected 3", got)
+	}
+	if got := status.CountCompletedModules(); got != 1 {
+		t.Errorf("CountCompletedModules() = %d, expected 1", got)
 	}
-	m.SetJob("new_module", job)
 
-	// Module should be created automatically
-	if m.GetModule("new_module") == nil {
-		t.Error("SetJob should create module if it doesn't exist")
+	status.Modules[1].Jobs[1].Status = StatusCompleted
+	if mi, ji := status.GetNextPendingJob(); mi != -1 || ji != -1 {
+		t.Errorf("GetNextPendingJob() = %d/%d, expected -1/-1", mi, ji)
 	}
 }
 
@@ -507,12 +477,8 @@ func TestDebugLogEntry(t *testing.T) {
 		Phenomenon:   "Test issue",
 		Reproduction: "Run test",
 		Hypothesis:   "Guess 1) Guess 2",
-		Verification: "Check X",
-		Fix:          "Fix Y",
-		Progress:     "fixed",
 	}
 
-	// Verify JSON marshaling
 	data, err := json.Marshal(entry)
 	if err != nil {
 		t.Fatalf("Failed to marshal DebugLogEntry: %v", err)
@@ -522,7 +488,6 @@ func TestDebugLogEntry(t *testing.T) {
 	if err := json.Unmarshal(data, &parsed); err != nil {
 		t.Fatalf("Failed to unmarshal DebugLogEntry: %v", err)
 	}
-
 	if parsed.ID != entry.ID {
 		t.Errorf("ID = %s, expected %s", parsed.ID, entry.ID)
 	}
@@ -531,6 +496,24 @@ func TestDebugLogEntry(t *testing.T) {
 	}
 }
 
+// TestJobStateOmitsEmptyFields verifies the optional job fields stay out of status.json.
+func TestJobStateOmitsEmptyFields(t *testing.T) {
+	data, err := json.Marshal(JobState{Name: "test_job", Status: StatusPending})
+	if err != nil {
+		t.Fatalf("Failed to marshal JobState: %v", err)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"failure_reason", "blocked_by", "tasks", "digest", "sessions"} {
+		if _, ok := fields[key]; ok {
+			t.Errorf("Empty field %q was marshalled", key)
+		}
+	}
+}
+
 // TestTaskState verifies task state structure.
 func TestTaskState(t *testing.T) {
 	task := TaskState{
@@ -548,325 +531,6 @@ func TestTaskState(t *testing.T) {
 	}
 }
 
-// TestSaveNilState verifies saving when state is nil initializes default state.
-func TestSaveNilState(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
-
-	m := NewManager(statePath)
-	// Don't call Load, so state is nil
-
-	// Save should initialize default state
-	if err := m.Save(); err != nil {
-		t.Fatalf("Save() error = %v", err)
-	}
-
-	// Verify file was created
-	if _, err := os.Stat(statePath); err != nil {
-		t.Errorf("State file was not created: %v", err)
-	}
-
-	// Verify state was initialized
-	state := m.GetState()
-	if state == nil {
-		t.Fatal("State should be initialized after Save")
-	}
-	if state.Version != DefaultStateVersion {
-		t.Errorf("Version = %s, expected %s", state.Version, DefaultStateVersion)
-	}
-}
-
-// TestBackupWithCustomPath verifies creating a backup at a custom path.
-func TestBackupWithCustomPath(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
-	backupPath := filepath.Join(tmpDir, "backups", "custom_backup.json")
-
-	m := NewManager(statePath)
-	if err := m.Load(); err != nil {
-		t.Fatalf("Load() error = %v", err)
-	}
-
-	// Set some state
-	state := m.GetState()
-	state.Global.TotalLoops = 99
-	state.Global.Status = StatusRunning
-
-	// Backup to custom path
-	if err := m.BackupWithCustomPath(backupPath); err != nil {
-		t.Fatalf("BackupWithCustomPath() error = %v", err)
-	}
-
-	// Verify backup file exists
-	data, err := os.ReadFile(backupPath)
-	if err != nil {
-		t.Fatalf("Failed to read backup file: %v", err)
-	}
-
-	var backupState StatusJSON
-	if err := json.Unmarshal(data, &backupState); err != nil {
-		t.Fatalf("Failed to parse backup: %v", err)
-	}
-
-	if backupState.Global.TotalLoops != 99 {
-		t.Errorf("Backup TotalLoops = %d, expected 99", backupState.Global.TotalLoops)
-	}
-}
-
-// TestBackupWithCustomPathNilState verifies backup with nil state returns error.
-func TestBackupWithCustomPathNilState(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
-	backupPath := filepath.Join(tmpDir, "backup.json")
-
-	m := NewManager(statePath)
-	// Don't load state, so it's nil
-
-	err := m.BackupWithCustomPath(backupPath)
-	if err == nil {
-		t.Fatal("BackupWithCustomPath() expected error when state is nil")
-	}
-}
-
-// TestRestoreFromBackupErrors verifies error cases for RestoreFromBackup.
-func TestRestoreFromBackupErrors(t *testing.T) {
-	t.Run("NonExistentFile", func(t *testing.T) {
-		tmpDir := t.TempDir()
-		statePath := filepath.Join(tmpDir, "status.json")
-		backupPath := filepath.Join(tmpDir, "nonexistent.json")
-
-		m := NewManager(statePath)
-		err := m.RestoreFromBackup(backupPath)
-		if err == nil {
-			t.Fatal("RestoreFromBackup() expected error for non-existent file")
-		}
-	})
-
-	t.Run("CorruptedBackup", func(t *testing.T) {
-		tmpDir := t.TempDir()
-		statePath := filepath.Join(tmpDir, "status.json")
-		backupPath := filepath.Join(tmpDir, "corrupted.json")
-
-		// Create corrupted backup
-		if err := os.WriteFile(backupPath, []byte("not json"), 0644); err != nil {
-			t.Fatalf("Failed to create corrupted file: %v", err)
-		}
-
-		m := NewManager(statePath)
-		err := m.RestoreFromBackup(backupPath)
-		if err == nil {
-			t.Fatal("RestoreFromBackup() expected error for corrupted file")
-		}
-
-		if me, ok := errors.AsMortyError(err); ok {
-			if me.Code != "M2002" {
-				t.Errorf("Error code = %s, expected M2002", me.Code)
-			}
-		}
-	})
-}
-
-// TestListBackupsErrors verifies ListBackups error handling.
-func TestListBackupsErrors(t *testing.T) {
-	// Test with non-existent directory - should return empty list
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "nonexistent_dir", "status.json")
-
-	m := NewManager(statePath)
-	backups, err := m.ListBackups()
-	if err != nil {
-		t.Fatalf("ListBackups() error = %v", err)
-	}
-	if len(backups) != 0 {
-		t.Errorf("len(backups) = %d, expected 0 for non-existent dir", len(backups))
-	}
-}
-
-// TestValidateStateMissingVersion verifies validation of state with missing version.
-func TestValidateStateMissingVersion(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
-
-	// Create a state without version
-	testState := &StatusJSON{
-		Version: "",
-		Global: GlobalState{
-			Status:     StatusRunning,
-			StartTime:  time.Now(),
-			LastUpdate: time.Now(),
-		},
-		Modules: make(map[string]*ModuleState),
-	}
-
-	data, _ := json.MarshalIndent(testState, "", "  ")
-	if err := os.WriteFile(statePath, data, 0644); err != nil {
-		t.Fatalf("Failed to create test file: %v", err)
-	}
-
-	m := NewManager(statePath)
-	err := m.Load()
-	if err == nil {
-		t.Fatal("Load() expected error for missing version")
-	}
-
-	if me, ok := errors.AsMortyError(err); ok {
-		if me.Code != "M2003" {
-			t.Errorf("Error code = %s, expected M2003", me.Code)
-		}
-	}
-}
-
-// TestValidateStateNilModule verifies validation of state with nil module.
-func TestValidateStateNilModule(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
-
-	// Create a state with nil module
-	testState := &StatusJSON{
-		Version: "1.0",
-		Global: GlobalState{
-			Status:     StatusRunning,
-			StartTime:  time.Now(),
-			LastUpdate: time.Now(),
-		},
-		Modules: map[string]*ModuleState{
-			"test_module": nil,
-		},
-	}
-
-	data, _ := json.MarshalIndent(testState, "", "  ")
-	if err := os.WriteFile(statePath, data, 0644); err != nil {
-		t.Fatalf("Failed to create test file: %v", err)
-	}
-
-	m := NewManager(statePath)
-	err := m.Load()
-	if err == nil {
-		t.Fatal("Load() expected error for nil module")
-	}
-
-	if me, ok := errors.AsMortyError(err); ok {
-		if me.Code != "M2003" {
-			t.Errorf("Error code = %s, expected M2003", me.Code)
-		}
-	}
-}
-
-// TestValidateStateNilJob verifies validation of state with nil job.
-func TestValidateStateNilJob(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
-
-	// Create a state with nil job
-	testState := &StatusJSON{
-		Version: "1.0",
-		Global: GlobalState{
-			Status:     StatusRunning,
-			StartTime:  time.Now(),
-			LastUpdate: time.Now(),
-		},
-		Modules: map[string]*ModuleState{
-			"test_module": {
-				Name:      "test_module",
-				Status:    StatusRunning,
-				Jobs:      map[string]*JobState{
-					"test_job": nil,
-				},
-				CreatedAt: time.Now(),
-				UpdatedAt: time.Now(),
-			},
-		},
-	}
-
-	data, _ := json.MarshalIndent(testState, "", "  ")
-	if err := os.WriteFile(statePath, data, 0644); err != nil {
-		t.Fatalf("Failed to create test file: %v", err)
-	}
-
-	m := NewManager(statePath)
-	err := m.Load()
-	if err == nil {
-		t.Fatal("Load() expected error for nil job")
-	}
-
-	if me, ok := errors.AsMortyError(err); ok {
-		if me.Code != "M2003" {
-			t.Errorf("Error code = %s, expected M2003", me.Code)
-		}
-	}
-}
-
-// TestValidateStateInvalidJobStatus verifies validation of state with invalid job status.
-func TestValidateStateInvalidJobStatus(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
-
-	// Create a state with invalid job status
-	testState := &StatusJSON{
-		Version: "1.0",
-		Global: GlobalState{
-			Status:     StatusRunning,
-			StartTime:  time.Now(),
-			LastUpdate: time.Now(),
-		},
-		Modules: map[string]*ModuleState{
-			"test_module": {
-				Name:      "test_module",
-				Status:    StatusRunning,
-				Jobs:      map[string]*JobState{
-					"test_job": {
-						Name:      "test_job",
-						Status:    Status("INVALID"),
-						CreatedAt: time.Now(),
-						UpdatedAt: time.Now(),
-					},
-				},
-				CreatedAt: time.Now(),
-				UpdatedAt: time.Now(),
-			},
-		},
-	}
-
-	data, _ := json.MarshalIndent(testState, "", "  ")
-	if err := os.WriteFile(statePath, data, 0644); err != nil {
-		t.Fatalf("Failed to create test file: %v", err)
-	}
-
-	m := NewManager(statePath)
-	err := m.Load()
-	if err == nil {
-		t.Fatal("Load() expected error for invalid job status")
-	}
-
-	if me, ok := errors.AsMortyError(err); ok {
-		if me.Code != "M2003" {
-			t.Errorf("Error code = %s, expected M2003", me.Code)
-		}
-	}
-}
-
-// TestLoadStatError verifies Load handles stat errors other than NotExist.
-func TestLoadStatError(t *testing.T) {
-	// Create a file that cannot be read as a directory
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "not_a_dir")
-
-	// Create a file at the path
-	if err := os.WriteFile(statePath, []byte("test"), 0644); err != nil {
-		t.Fatalf("Failed to create test file: %v", err)
-	}
-
-	// Now try to use a path inside that "file"
-	invalidPath := filepath.Join(statePath, "status.json")
-
-	m := NewManager(invalidPath)
-	err := m.Load()
-	// This should fail because statePath is a file, not a directory
-	if err == nil {
-		t.Fatal("Load() expected error for invalid path")
-	}
-}
-
 // TestStatusString verifies the String method.
 func TestStatusString(t *testing.T) {
 	if StatusRunning.String() != "RUNNING" {
@@ -0,0 +1,171 @@
+package state
+
+import (
+	"time"
+
+	"github.com/morty/morty/pkg/errors"
+)
+
+// CurrentJob identifies the job that is currently running.
+type CurrentJob struct {
+	// Module is the module name
+	Module string `json:"module"`
+	// Job is the job name
+	Job string `json:"job"`
+	// Status is the job status
+	Status Status `json:"status"`
+	// StartedAt is when the latest attempt of the job started
+	StartedAt time.Time `json:"started_at"`
+}
+
+// JobRef points to a job by module and job name.
+type JobRef struct {
+	// Module is the module name
+	Module string `json:"module"`
+	// Job is the job name
+	Job string `json:"job"`
+	// Status is the job status
+	Status Status `json:"status"`
+}
+
+// ModuleSummary counts the jobs of one module by status.
+type ModuleSummary struct {
+	// Status is the module status
+	Status Status `json:"status"`
+	// TotalJobs is the number of jobs in the module; the other fields
+	// count its jobs per status
+	TotalJobs   int `json:"total_jobs"`
+	Pending     int `json:"pending"`
+	Running     int `json:"running"`
+	Completed   int `json:"completed"`
+	Failed      int `json:"failed"`
+	Blocked     int `json:"blocked"`
+	Interrupted int `json:"interrupted"`
+}
+
+// Summary counts the jobs of all modules by status.
+type Summary struct {
+	// TotalModules is the number of modules
+	TotalModules int `json:"total_modules"`
+	// TotalJobs is the number of jobs; the fields up to Modules count
+	// the jobs per status
+	TotalJobs   int `json:"total_jobs"`
+	Pending     int `json:"pending"`
+	Running     int `json:"running"`
+	Completed   int `json:"completed"`
+	Failed      int `json:"failed"`
+	Blocked     int `json:"blocked"`
+	Interrupted int `json:"interrupted"`
+	// Modules holds the per-module counts keyed by module name
+	Modules map[string]ModuleSummary `json:"modules"`
+}
+
+// add counts one job status.
+func (s *ModuleSummary) add(jobStatus Status) {
+	s.TotalJobs++
+	switch jobStatus {
+	case StatusPending:
+		s.Pending++
+	case StatusRunning:
+		s.Running++
+	case StatusCompleted:
+		s.Completed++
+	case StatusFailed:
+		s.Failed++
+	case StatusBlocked:
+		s.Blocked++
+	case StatusInterrupted:
+		s.Interrupted++
+	}
+}
+
+// GetCurrent returns the running job, or nil when no job is running.
+// The job recorded in the global state wins over other running jobs.
+func (m *Manager) GetCurrent() (*CurrentJob, error) {
+	statusMu.RLock()
+	defer statusMu.RUnlock()
+
+	if status == nil {
+		return nil, errors.New("M2003", "status not loaded").WithModule("state")
+	}
+
+	var current *CurrentJob
+	for mi := range status.Modules {
+		module := &status.Modules[mi]
+		for ji := range module.Jobs {
+			job := &module.Jobs[ji]
+			if job.Status != StatusRunning {
+				continue
+			}
+			if current != nil && job.GlobalIndex != status.Global.CurrentJobIndex {
+				continue
+			}
+			current = &CurrentJob{
+				Module:    module.Name,
+				Job:       job.Name,
+				Status:    job.Status,
+				StartedAt: job.UpdatedAt,
+			}
+			if session := job.LastSession(); session != nil {
+				current.StartedAt = session.StartedAt
+			}
+		}
+	}
+
+	return current, nil
+}
+
+// GetSummary counts the jobs of the loaded status by status.
+func (m *Manager) GetSummary() (*Summary, error) {
+	statusMu.RLock()
+	defer statusMu.RUnlock()
+
+	if status == nil {
+		return nil, errors.New("M2003", "status not loaded").WithModule("state")
+	}
+
+	summary := &Summary{
+		TotalModules: len(status.Modules),
+		Modules:      make(map[string]ModuleSummary, len(status.Modules)),
+	}
+	var total ModuleSummary
+	for _, module := range status.Modules {
+		moduleSummary := ModuleSummary{Status: module.Status}
+		for _, job := range module.Jobs {
+			moduleSummary.add(job.Status)
+			total.add(job.Status)
+		}
+		summary.Modules[module.Name] = moduleSummary
+	}
+
+	summary.TotalJobs = total.TotalJobs
+	summary.Pending = total.Pending
+	summary.Running = total.Running
+	summary.Completed = total.Completed
+	summary.Failed = total.Failed
+	summary.Blocked = total.Blocked
+	summary.Interrupted = total.Interrupted
+
+	return summary, nil
+}
+
+// GetPendingJobs returns the pending jobs in execution order.
+func (m *Manager) GetPendingJobs() []JobRef {
+	statusMu.RLock()
+	defer statusMu.RUnlock()
+
+	pending := []JobRef{}
+	if status == nil {
+		return pending
+	}
+
+	for _, module := range status.Modules {
+		for _, job := range module.Jobs {
+			if job.Status == StatusPending {
+				pending = append(pending, JobRef{Module: module.Name, Job: job.Name, Status: job.Status})
+			}
+		}
+	}
+
+	return pending
+}
@@ -0,0 +1,135 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/morty/morty/internal/logging"
+	"github.com/morty/morty/pkg/errors"
+)
+
+// TransitionRules defines the statuses a job may move to from each status.
+//
+//	PENDING     -> RUNNING, BLOCKED
+//	RUNNING     -> COMPLETED, FAILED, BLOCKED, INTERRUPTED
+//	COMPLETED   -> (terminal)
+//	FAILED      -> PENDING (retry)
+//	BLOCKED     -> PENDING (unblock)
+//	INTERRUPTED -> RUNNING (resume), PENDING
+var TransitionRules = map[Status][]Status{
+	StatusPending:     {StatusRunning, StatusBlocked},
+	StatusRunning:     {StatusCompleted, StatusFailed, StatusBlocked, StatusInterrupted},
+	StatusCompleted:   {},
+	StatusFailed:      {StatusPending},
+	StatusBlocked:     {StatusPending},
+	StatusInterrupted: {StatusRunning, StatusPending},
+}
+
+// TransitionError is returned when a job status change breaks TransitionRules.
+type TransitionError struct {
+	// From is the job's current status
+	From Status
+	// To is the requested status
+	To Status
+	// Reason describes why the transition was refused
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("invalid transition from %s to %s: %s", e.From, e.To, e.Reason)
+}
+
+// IsValidTransition reports whether a job may move from one status to another.
+func IsValidTransition(from, to Status) bool {
+	if !from.IsValid() || !to.IsValid() {
+		return false
+	}
+	for _, allowed := range TransitionRules[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// GetValidTransitions returns the statuses reachable from the given status.
+// The returned slice is a copy and may be modified by the caller.
+func GetValidTransitions(from Status) []Status {
+	allowed := TransitionRules[from]
+	result := make([]Status, len(allowed))
+	copy(result, allowed)
+	return result
+}
+
+// CanTransition checks whether a job may move to the given status.
+// It returns a *TransitionError when the transition breaks TransitionRules.
+func (m *Manager) CanTransition(moduleName, jobName string, toStatus Status) error {
+	current, err := m.currentJobStatus(moduleName, jobName)
+	if err != nil {
+		return err
+	}
+
+	if !toStatus.IsValid() {
+		return fmt.Errorf("invalid target status: %s", toStatus)
+	}
+
+	if !IsValidTransition(current, toStatus) {
+		return &TransitionError{
+			From:   current,
+			To:     toStatus,
+			Reason: fmt.Sprintf("transition from %s to %s is not allowed", current, toStatus),
+		}
+	}
+
+	return nil
+}
+
+// GetJobValidTransitions returns the statuses a job may move to next.
+func (m *Manager) GetJobValidTransitions(moduleName, jobName string) ([]Status, error) {
+	current, err := m.currentJobStatus(moduleName, jobName)
+	if err != nil {
+		return nil, err
+	}
+	return GetValidTransitions(current), nil
+}
+
+// currentJobStatus returns the status of a job, reporting an unloaded status
+// or an unknown module as a corrupted state.
+func (m *Manager) currentJobStatus(moduleName, jobName string) (Status, error) {
+	statusMu.RLock()
+	defer statusMu.RUnlock()
+
+	if status == nil {
+		return "", errors.New("M2003", "status not loaded").WithModule("state")
+	}
+
+	module := status.GetModuleByName(moduleName)
+	if module == nil {
+		return "", errors.New("M2003", "module not found in status").
+			WithModule("state").
+			WithDetail("module", moduleName)
+	}
+
+	job := module.GetJobByName(jobName)
+	if job == nil {
+		return "", fmt.Errorf("job not found: %s in module %s", jobName, moduleName)
+	}
+
+	return job.Status, nil
+}
+
+// logTransitionError reports a refused transition through logger when it
+// supports error logging.
+func logTransitionError(logger interface{}, moduleName, jobName string, err error) {
+	l, ok := logger.(interface {
+		Error(msg string, attrs ...logging.Attr)
+	})
+	if !ok {
+		return
+	}
+	l.Error("Invalid job status transition",
+		logging.String("module", moduleName),
+		logging.String("job", jobName),
+		logging.String("error", err.Error()),
+	)
+}
@@ -1,11 +1,9 @@
-// Package state provides state management for Morty.
 package state
 
 import (
 	"context"
 	"path/filepath"
 	"testing"
-	"time"
 
 	"github.com/morty/morty/internal/logging"
 	"github.com/morty/morty/pkg/errors"
@@ -13,10 +11,8 @@ import (
 
 // mockLogger is a simple mock for testing logging functionality
 type mockLogger struct {
-	logs   []map[string]interface{}
-	level  logging.Level
-	module string
-	job    string
+	logs  []map[string]interface{}
+	level logging.Level
 }
 
 func (m *mockLogger) Debug(msg string, attrs ...logging.Attr) {}
@@ -27,17 +23,12 @@ func (m *mockLogger) Error(msg string, attrs ...logging.Attr) {
 	m.logs = append(m.logs, entry)
 }
 func (m *mockLogger) Success(msg string, attrs ...logging.Attr) {}
-func (m *mockLogger) Loop(msg string, attrs ...logging.Attr)   {}
+func (m *mockLogger) Loop(msg string, attrs ...logging.Attr)    {}
 func (m *mockLogger) WithContext(ctx context.Context) logging.Logger {
 	return m
 }
 func (m *mockLogger) WithJob(module, job string) logging.Logger {
-	return &mockLogger{
-		logs:   m.logs,
-		level:  m.level,
-		module: module,
-		job:    job,
-	}
+	return m
 }
 func (m *mockLogger) WithAttrs(attrs ...logging.Attr) logging.Logger {
 	return m
@@ -50,6 +41,16 @@ func (m *mockLogger) IsEnabled(level logging.Level) bool {
 	return level >= m.level
 }
 
+// setupUnloadedManager returns a manager whose status file does not exist.
+func setupUnloadedManager(t *testing.T) *Manager {
+	t.Helper()
+	m := NewManager(filepath.Join(t.TempDir(), "status.json"))
+	if err := m.Load(); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return m
+}
+
 // TestIsValidTransition_ValidTransitions tests all valid state transitions.
 func TestIsValidTransition_ValidTransitions(t *testing.T) {
 	tests := []struct {
@@ -62,8 +63,11 @@ func TestIsValidTransition_ValidTransitions(t *testing.T) {
 		{"RUNNING to COMPLETED", StatusRunning, StatusCompleted},
 		{"RUNNING to FAILED", StatusRunning, StatusFailed},
 		{"RUNNING to BLOCKED", StatusRunning, StatusBlocked},
+		{"RUNNING to INTERRUPTED", StatusRunning, StatusInterrupted},
 		{"FAILED to PENDING", StatusFailed, StatusPending},
 		{"BLOCKED to PENDING", StatusBlocked, StatusPending},
+		{"INTERRUPTED to RUNNING", StatusInterrupted, StatusRunning},
+		{"INTERRUPTED to PENDING", StatusInterrupted, StatusPending},
 	}
 
 	for _, tt := range tests {
@@ -85,6 +89,7 @@ func TestIsValidTransition_InvalidTransitions(t *testing.T) {
 		{"PENDING to COMPLETED", StatusPending, StatusCompleted},
 		{"PENDING to FAILED", StatusPending, StatusFailed},
 		{"PENDING to PENDING", StatusPending, StatusPending},
+		{"PENDING to INTERRUPTED", StatusPending, StatusInterrupted},
 		{"RUNNING to PENDING", StatusRunning, StatusPending},
 		{"RUNNING to RUNNING", StatusRunning, StatusRunning},
 		{"COMPLETED to PENDING", StatusCompleted, StatusPending},
@@ -99,6 +104,8 @@ func TestIsValidTransition_InvalidTransitions(t *testing.T) {
 		{"BLOCKED to COMPLETED", StatusBlocked, StatusCompleted},
 		{"BLOCKED to FAILED", StatusBlocked, StatusFailed},
 		{"BLOCKED to BLOCKED", StatusBlocked, StatusBlocked},
+		{"INTERRUPTED to COMPLETED", StatusInterrupted, StatusCompleted},
+		{"INTERRUPTED to INTERRUPTED", StatusInterrupted, StatusInterrupted},
 	}
 
 	for _, tt := range tests {
@@ -114,22 +121,15 @@ func TestIsValidTransition_InvalidTransitions(t *testing.T) {
 func TestIsValidTransition_InvalidStatus(t *testing.T) {
 	invalidStatus := Status("INVALID")
 
-	// Test invalid from status
 	if IsValidTransition(invalidStatus, StatusPending) {
 		t.Error("IsValidTransition(INVALID, PENDING) should return false")
 	}
-
-	// Test invalid to status
 	if IsValidTransition(StatusPending, invalidStatus) {
 		t.Error("IsValidTransition(PENDING, INVALID) should return false")
 	}
-
-	// Test both invalid
 	if IsValidTransition(invalidStatus, Status("ALSO_INVALID")) {
 		t.Error("IsValidTransition(INVALID, ALSO_INVALID) should return false")
 	}
-
-	// Test empty status
 	if IsValidTransition(Status(""), StatusPending) {
 		t.Error("IsValidTransition(empty, PENDING) should return false")
 	}
@@ -143,10 +143,11 @@ func TestGetValidTransitions(t *testing.T) {
 		expected []Status
 	}{
 		{"PENDING", StatusPending, []Status{StatusRunning, StatusBlocked}},
-		{"RUNNING", StatusRunning, []Status{StatusCompleted, StatusFailed, StatusBlocked}},
+		{"RUNNING", StatusRunning, []Status{StatusCompleted, StatusFailed, StatusBlocked, StatusInterrupted}},
 		{"COMPLETED", StatusCompleted, []Status{}},
 		{"FAILED", StatusFailed, []Status{StatusPending}},
 		{"BLOCKED", StatusBlocked, []Status{StatusPending}},
+		{"INTERRUPTED", StatusInterrupted, []Status{StatusRunning, StatusPending}},
 	}
 
 	for _, tt := range tests {
@@ -154,11 +155,9 @@ func TestGetValidTransitions(t *testing.T) {
 			result := GetValidTransitions(tt.from)
 
 			if len(result) != len(tt.expected) {
-				t.Errorf("GetValidTransitions(%s) returned %d statuses, expected %d",
+				t.Fatalf("GetValidTransitions(%s) returned %d statuses, expected %d",
 					tt.from, len(result), len(tt.expected))
-				return
 			}
-
 			for i, status := range result {
 				if status != tt.expected[i] {
 					t.Errorf("GetValidTransitions(%s)[%d] = %s, expected %s",
@@ -177,6 +176,20 @@ func TestGetValidTransitions_InvalidStatus(t *testing.T) {
 	}
 }
 
+// TestTransitionRules_Immutability tests that TransitionRules cannot be modified externally.
+func TestTransitionRules_Immutability(t *testing.T) {
+	original := GetValidTransitions(StatusPending)
+	if len(original) != 2 {
+		t.Fatalf("PENDING should have 2 valid transitions")
+	}
+
+	original[0] = StatusCompleted
+
+	if GetValidTransitions(StatusPending)[0] != StatusRunning {
+		t.Error("TransitionRules was modified externally - slice copy not working")
+	}
+}
+
 // TestTransitionError_Error tests the TransitionError.Error method.
 func TestTransitionError_Error(t *testing.T) {
 	err := &TransitionError{
@@ -193,41 +206,16 @@ func TestTransitionError_Error(t *testing.T) {
 
 // TestManager_CanTransition_Valid tests CanTransition with valid transitions.
 func TestManager_CanTransition_Valid(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
-
-	m := NewManager(statePath)
-	if err := m.Load(); err != nil {
-		t.Fatalf("Load() error = %v", err)
-	}
-
-	// Create a test module and job
-	module := &ModuleState{
-		Name:      "test_module",
-		Status:    StatusPending,
-		Jobs:      make(map[string]*JobState),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	m.SetModule(module)
+	m, _ := setupTestManager(t, createTestJob("test_job", StatusPending))
 
-	job := &JobState{
-		Name:      "test_job",
-		Status:    StatusPending,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	m.SetJob("test_module", job)
-
-	// Test valid transition: PENDING -> RUNNING
 	if err := m.CanTransition("test_module", "test_job", StatusRunning); err != nil {
 		t.Errorf("CanTransition(PENDING, RUNNING) error = %v, expected nil", err)
 	}
 
-	// Update job status to RUNNING for next test
-	job.Status = StatusRunning
+	if err := m.UpdateJobStatusByName("test_module", "test_job", StatusRunning); err != nil {
+		t.Fatalf("UpdateJobStatusByName() error = %v", err)
+	}
 
-	// Test valid transition: RUNNING -> COMPLETED
 	if err := m.CanTransition("test_module", "test_job", StatusCompleted); err != nil {
 		t.Errorf("CanTransition(RUNNING, COMPLETED) error = %v, expected nil", err)
 	}
@@ -235,334 +223,112 @@ func TestManager_CanTransition_Valid(t *testing.T) {
 
 // TestManager_CanTransition_Invalid tests CanTransition with invalid transitions.
 func TestManager_CanTransition_Invalid(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
-
-	m := NewManager(statePath)
-	if err := m.Load(); err != nil {
-		t.Fatalf("Load() error = %v", err)
-	}
-
-	// Create a test module and job
-	module := &ModuleState{
-		Name:      "test_module",
-		Status:    StatusPending,
-		Jobs:      make(map[string]*JobState),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	m.SetModule(module)
-
-	job := &JobState{
-		Name:      "test_job",
-		Status:    StatusPending,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	m.SetJob("test_module", job)
+	m, _ := setupTestManager(t, createTestJob("test_job", StatusPending))
 
-	// Test invalid transition: PENDING -> COMPLETED
 	err := m.CanTransition("test_module", "test_job", StatusCompleted)
 	if err == nil {
-		t.Error("CanTransition(PENDING, COMPLETED) expected error, got nil")
-	} else {
-		// Check that it's a TransitionError
-		if _, ok := err.(*TransitionError); !ok {
-			t.Errorf("Expected TransitionError, got %T", err)
-		}
+		t.Fatal("CanTransition(PENDING, COMPLETED) expected error, got nil")
+	}
+	if _, ok := err.(*TransitionError); !ok {
+		t.Errorf("Expected TransitionError, got %T", err)
 	}
 }
 
 // TestManager_CanTransition_Errors tests CanTransition error cases.
 func TestManager_CanTransition_Errors(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
+	m := setupUnloadedManager(t)
 
-	m := NewManager(statePath)
-
-	// Test nil state
 	err := m.CanTransition("module", "job", StatusRunning)
 	if err == nil {
 		t.Error("CanTransition with nil state expected error")
-	} else {
-		if me, ok := errors.AsMortyError(err); ok {
-			if me.Code != "M2003" {
-				t.Errorf("Error code = %s, expected M2003", me.Code)
-			}
-		}
+	} else if me, ok := errors.AsMortyError(err); !ok || me.Code != "M2003" {
+		t.Errorf("Expected M2003 error, got %v", err)
 	}
 
-	// Load state and test other errors
-	if err := m.Load(); err != nil {
-		t.Fatalf("Load() error = %v", err)
-	}
+	m, _ = setupTestManager(t, createTestJob("test_job", StatusPending))
 
-	// Test invalid target status
-	err = m.CanTransition("module", "job", Status("INVALID"))
-	if err == nil {
+	if err := m.CanTransition("test_module", "test_job", Status("INVALID")); err == nil {
 		t.Error("CanTransition with invalid status expected error")
 	}
 
-	// Test non-existent module
 	err = m.CanTransition("nonexistent", "job", StatusRunning)
 	if err == nil {
 		t.Error("CanTransition with non-existent module expected error")
-	} else {
-		if me, ok := errors.AsMortyError(err); ok {
-			if me.Code != "M2003" {
-				t.Errorf("Error code = %s, expected M2003", me.Code)
-			}
-		}
+	} else if me, ok := errors.AsMortyError(err); !ok || me.Code != "M2003" {
+		t.Errorf("Expected M2003 error, got %v", err)
 	}
 
-	// Create module and test non-existent job
-	module := &ModuleState{
-		Name:      "test_module",
-		Status:    StatusPending,
-		Jobs:      make(map[string]*JobState),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	m.SetModule(module)
-
-	err = m.CanTransition("test_module", "nonexistent", StatusRunning)
-	if err == nil {
+	if err := m.CanTransition("test_module", "nonexistent", StatusRunning); err == nil {
 		t.Error("CanTransition with non-existent job expected error")
 	}
 }
 
 // TestManager_TransitionJobStatus_Valid tests successful transitions.
 func TestManager_TransitionJobStatus_Valid(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
-
-	m := NewManager(statePath)
-	if err := m.Load(); err != nil {
-		t.Fatalf("Load() error = %v", err)
-	}
-
-	// Create a test module and job with PENDING status
-	module := &ModuleState{
-		Name:      "test_module",
-		Status:    StatusPending,
-		Jobs:      make(map[string]*JobState),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	m.SetModule(module)
-
-	job := &JobState{
-		Name:      "test_job",
-		Status:    StatusPending,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	m.SetJob("test_module", job)
-
+	m, stateFile := setupTestManager(t, createTestJob("test_job", StatusPending))
 	logger := &mockLogger{}
 
-	// Test PENDING -> RUNNING transition
 	if err := m.TransitionJobStatus("test_module", "test_job", StatusRunning, logger); err != nil {
 		t.Errorf("TransitionJobStatus(PENDING -> RUNNING) error = %v", err)
 	}
-
-	// Verify status was updated
-	updatedJob := m.GetJob("test_module", "test_job")
-	if updatedJob.Status != StatusRunning {
-		t.Errorf("Job status = %s, expected RUNNING", updatedJob.Status)
+	if got := m.GetJob("test_module", "test_job").Status; got != StatusRunning {
+		t.Errorf("Job status = %s, expected RUNNING", got)
 	}
 
-	// Test RUNNING -> COMPLETED transition
 	if err := m.TransitionJobStatus("test_module", "test_job", StatusCompleted, logger); err != nil {
 		t.Errorf("TransitionJobStatus(RUNNING -> COMPLETED) error = %v", err)
 	}
-
-	// Verify status was updated
-	updatedJob = m.GetJob("test_module", "test_job")
-	if updatedJob.Status != StatusCompleted {
-		t.Errorf("Job status = %s, expected COMPLETED", updatedJob.Status)
-	}
-}
-
-// TestManager_TransitionJobStatus_RetryFlow tests FAILED -> PENDING retry flow.
-func TestManager_TransitionJobStatus_RetryFlow(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
-
-	m := NewManager(statePath)
-	if err := m.Load(); err != nil {
-		t.Fatalf("Load() error = %v", err)
-	}
-
-	// Create a test module and job with FAILED status
-	module := &ModuleState{
-		Name:      "test_module",
-		Status:    StatusPending,
-		Jobs:      make(map[string]*JobState),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	m.SetModule(module)
-
-	job := &JobState{
-		Name:       "test_job",
-		Status:     StatusFailed,
-		RetryCount: 0,
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
-	}
-	m.SetJob("test_module", job)
-
-	logger := &mockLogger{}
-
-	// Test FAILED -> PENDING transition (retry)
-	if err := m.TransitionJobStatus("test_module", "test_job", StatusPending, logger); err != nil {
-		t.Errorf("TransitionJobStatus(FAILED -> PENDING) error = %v", err)
-	}
-
-	// Verify status was updated and retry count incremented
-	updatedJob := m.GetJob("test_module", "test_job")
-	if updatedJob.Status != StatusPending {
-		t.Errorf("Job status = %s, expected PENDING", updatedJob.Status)
-	}
-}
-
-// TestManager_TransitionJobStatus_UnblockFlow tests BLOCKED -> PENDING unblock flow.
-func TestManager_TransitionJobStatus_UnblockFlow(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
-
-	m := NewManager(statePath)
-	if err := m.Load(); err != nil {
-		t.Fatalf("Load() error = %v", err)
-	}
-
-	// Create a test module and job with BLOCKED status
-	module := &ModuleState{
-		Name:      "test_module",
-		Status:    StatusPending,
-		Jobs:      make(map[string]*JobState),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	m.SetModule(module)
-
-	job := &JobState{
-		Name:      "test_job",
-		Status:    StatusBlocked,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	m.SetJob("test_module", job)
-
-	logger := &mockLogger{}
-
-	// Test BLOCKED -> PENDING transition (unblock)
-	if err := m.TransitionJobStatus("test_module", "test_job", StatusPending, logger); err != nil {
-		t.Errorf("TransitionJobStatus(BLOCKED -> PENDING) error = %v", err)
-	}
-
-	// Verify status was updated
-	updatedJob := m.GetJob("test_module", "test_job")
-	if updatedJob.Status != StatusPending {
-		t.Errorf("Job status = %s, expected PENDING", updatedJob.Status)
+	if got := reload(t, stateFile).Modules[0].Jobs[0].Status; got != StatusCompleted {
+		t.Errorf("Saved job status = %s, expected COMPLETED", got)
 	}
 }
 
-// TestManager_TransitionJobStatus_BlockFlow tests RUNNING -> BLOCKED block flow.
-func TestManager_TransitionJobStatus_BlockFlow(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
-
-	m := NewManager(statePath)
-	if err := m.Load(); err != nil {
-		t.Fatalf("Load() error = %v", err)
-	}
-
-	// Create a test module and job with RUNNING status
-	module := &ModuleState{
-		Name:      "test_module",
-		Status:    StatusPending,
-		Jobs:      make(map[string]*JobState),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	m.SetModule(module)
-
-	job := &JobState{
-		Name:      "test_job",
-		Status:    StatusRunning,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+// TestManager_TransitionJobStatus_Flows tests the retry, unblock, block and
+// resume flows.
+func TestManager_TransitionJobStatus_Flows(t *testing.T) {
+	tests := []struct {
+		name string
+		from Status
+		to   Status
+	}{
+		{"retry", StatusFailed, StatusPending},
+		{"unblock", StatusBlocked, StatusPending},
+		{"block", StatusRunning, StatusBlocked},
+		{"interrupt", StatusRunning, StatusInterrupted},
+		{"resume", StatusInterrupted, StatusRunning},
 	}
-	m.SetJob("test_module", job)
-
-	logger := &mockLogger{}
 
-	// Test RUNNING -> BLOCKED transition
-	if err := m.TransitionJobStatus("test_module", "test_job", StatusBlocked, logger); err != nil {
-		t.Errorf("TransitionJobStatus(RUNNING -> BLOCKED) error = %v", err)
-	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := setupTestManager(t, createTestJob("test_job", tt.from))
 
-	// Verify status was updated
-	updatedJob := m.GetJob("test_module", "test_job")
-	if updatedJob.Status != StatusBlocked {
-		t.Errorf("Job status = %s, expected BLOCKED", updatedJob.Status)
+			if err := m.TransitionJobStatus("test_module", "test_job", tt.to, &mockLogger{}); err != nil {
+				t.Fatalf("TransitionJobStatus(%s -> %s) error = %v", tt.from, tt.to, err)
+			}
+			if got := m.GetJob("test_module", "test_job").Status; got != tt.to {
+				t.Errorf("Job status = %s, expected %s", got, tt.to)
+			}
+		})
 	}
 }
 
 // TestManager_TransitionJobStatus_Invalid tests invalid transitions with logging.
 func TestManager_TransitionJobStatus_Invalid(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
-
-	m := NewManager(statePath)
-	if err := m.Load(); err != nil {
-		t.Fatalf("Load() error = %v", err)
-	}
-
-	// Create a test module and job with PENDING status
-	module := &ModuleState{
-		Name:      "test_module",
-		Status:    StatusPending,
-		Jobs:      make(map[string]*JobState),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	m.SetModule(module)
-
-	job := &JobState{
-		Name:      "test_job",
-		Status:    StatusPending,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	m.SetJob("test_module", job)
-
+	m, stateFile := setupTestManager(t, createTestJob("test_job", StatusPending))
 	logger := &mockLogger{}
 
-	// Test invalid transition: PENDING -> COMPLETED
 	err := m.TransitionJobStatus("test_module", "test_job", StatusCompleted, logger)
-	if err == nil {
-		t.Error("TransitionJobStatus(PENDING -> COMPLETED) expected error")
-	} else {
-		// Check that it's a TransitionError
-		if te, ok := err.(*TransitionError); !ok {
-			t.Errorf("Expected TransitionError, got %T", err)
-		} else {
-			if te.From != StatusPending || te.To != StatusCompleted {
-				t.Errorf("TransitionError.From = %s, To = %s, expected PENDING, COMPLETED", te.From, te.To)
-			}
-		}
+	te, ok := err.(*TransitionError)
+	if !ok {
+		t.Fatalf("Expected TransitionError, got %T (%v)", err, err)
 	}
-
-	// Verify status was NOT updated
-	updatedJob := m.GetJob("test_module", "test_job")
-	if updatedJob.Status != StatusPending {
-		t.Errorf("Job status changed to %s, expected PENDING (unchanged)", updatedJob.Status)
+	if te.From != StatusPending || te.To != StatusCompleted {
+		t.Errorf("TransitionError.From = %s, To = %s, expected PENDING, COMPLETED", te.From, te.To)
 	}
 
-	// Verify that error was logged
+	if got := reload(t, stateFile).Modules[0].Jobs[0].Status; got != StatusPending {
+		t.Errorf("Job status changed to %s, expected PENDING (unchanged)", got)
+	}
 	if len(logger.logs) == 0 {
 		t.Error("Expected error to be logged")
 	}
@@ -570,227 +336,77 @@ func TestManager_TransitionJobStatus_Invalid(t *testing.T) {
 
 // TestManager_TransitionJobStatus_NilLogger tests that nil logger doesn't panic.
 func TestManager_TransitionJobStatus_NilLogger(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
-
-	m := NewManager(statePath)
-	if err := m.Load(); err != nil {
-		t.Fatalf("Load() error = %v", err)
-	}
-
-	// Create a test module and job
-	module := &ModuleState{
-		Name:      "test_module",
-		Status:    StatusPending,
-		Jobs:      make(map[string]*JobState),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	m.SetModule(module)
-
-	job := &JobState{
-		Name:      "test_job",
-		Status:    StatusPending,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	m.SetJob("test_module", job)
+	m, _ := setupTestManager(t, createTestJob("test_job", StatusPending))
 
-	// Test with nil logger - should not panic
 	if err := m.TransitionJobStatus("test_module", "test_job", StatusRunning, nil); err != nil {
 		t.Errorf("TransitionJobStatus with nil logger error = %v", err)
 	}
+	if err := m.TransitionJobStatus("test_module", "test_job", StatusPending, nil); err == nil {
+		t.Error("TransitionJobStatus(RUNNING -> PENDING) with nil logger expected error")
+	}
 }
 
 // TestManager_TransitionJobStatus_Errors tests error cases for TransitionJobStatus.
 func TestManager_TransitionJobStatus_Errors(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
-
-	m := NewManager(statePath)
-
 	logger := &mockLogger{}
 
-	// Test nil state
-	err := m.TransitionJobStatus("module", "job", StatusRunning, logger)
-	if err == nil {
+	m := setupUnloadedManager(t)
+	if err := m.TransitionJobStatus("module", "job", StatusRunning, logger); err == nil {
 		t.Error("TransitionJobStatus with nil state expected error")
 	}
 
-	// Load state for remaining tests
-	if err := m.Load(); err != nil {
-		t.Fatalf("Load() error = %v", err)
-	}
-
-	// Test invalid target status
-	err = m.TransitionJobStatus("module", "job", Status("INVALID"), logger)
-	if err == nil {
+	m, _ = setupTestManager(t, createTestJob("test_job", StatusPending))
+	if err := m.TransitionJobStatus("test_module", "test_job", Status("INVALID"), logger); err == nil {
 		t.Error("TransitionJobStatus with invalid status expected error")
 	}
-
-	// Test non-existent module
-	err = m.TransitionJobStatus("nonexistent", "job", StatusRunning, logger)
-	if err == nil {
+	if err := m.TransitionJobStatus("nonexistent", "job", StatusRunning, logger); err == nil {
 		t.Error("TransitionJobStatus with non-existent module expected error")
 	}
 }
 
 // TestManager_GetJobValidTransitions tests GetJobValidTransitions.
 func TestManager_GetJobValidTransitions(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
-
-	m := NewManager(statePath)
-	if err := m.Load(); err != nil {
-		t.Fatalf("Load() error = %v", err)
-	}
-
-	// Create a test module and job with PENDING status
-	module := &ModuleState{
-		Name:      "test_module",
-		Status:    StatusPending,
-		Jobs:      make(map[string]*JobState),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	m.SetModule(module)
+	m, _ := setupTestManager(t, createTestJob("test_job", StatusPending))
 
-	job := &JobState{
-		Name:      "test_job",
-		Status:    StatusPending,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	m.SetJob("test_module", job)
-
-	// Get valid transitions for a PENDING job
 	transitions, err := m.GetJobValidTransitions("test_module", "test_job")
 	if err != nil {
-		t.Errorf("GetJobValidTransitions error = %v", err)
-	}
-
-	// PENDING should have 2 valid transitions: RUNNING and BLOCKED
-	if len(transitions) != 2 {
-		t.Errorf("GetJobValidTransitions returned %d transitions, expected 2", len(transitions))
+		t.Fatalf("GetJobValidTransitions error = %v", err)
 	}
 
-	// Verify the transitions are correct
-	hasRunning := false
-	hasBlocked := false
-	for _, t := range transitions {
-		if t == StatusRunning {
-			hasRunning = true
-		}
-		if t == StatusBlocked {
-			hasBlocked = true
-		}
-	}
-	if !hasRunning || !hasBlocked {
-		t.Error("GetJobValidTransitions should return RUNNING and BLOCKED for PENDING job")
+	if len(transitions) != 2 || transitions[0] != StatusRunning || transitions[1] != StatusBlocked {
+		t.Errorf("GetJobValidTransitions = %v, expected [RUNNING BLOCKED]", transitions)
 	}
 }
 
 // TestManager_GetJobValidTransitions_Errors tests GetJobValidTransitions error cases.
 func TestManager_GetJobValidTransitions_Errors(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
-
-	m := NewManager(statePath)
-
-	// Test nil state
-	_, err := m.GetJobValidTransitions("module", "job")
-	if err == nil {
+	m := setupUnloadedManager(t)
+	if _, err := m.GetJobValidTransitions("module", "job"); err == nil {
 		t.Error("GetJobValidTransitions with nil state expected error")
 	}
 
-	// Load state for remaining tests
-	if err := m.Load(); err != nil {
-		t.Fatalf("Load() error = %v", err)
-	}
-
-	// Test non-existent module
-	_, err = m.GetJobValidTransitions("nonexistent", "job")
-	if err == nil {
+	m, _ = setupTestManager(t, createTestJob("test_job", StatusPending))
+	if _, err := m.GetJobValidTransitions("nonexistent", "job"); err == nil {
 		t.Error("GetJobValidTransitions with non-existent module expected error")
 	}
-
-	// Create module and test non-existent job
-	module := &ModuleState{
-		Name:      "test_module",
-		Status:    StatusPending,
-		Jobs:      make(map[string]*JobState),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	m.SetModule(module)
-
-	_, err = m.GetJobValidTransitions("test_module", "nonexistent")
-	if err == nil {
+	if _, err := m.GetJobValidTransitions("test_module", "nonexistent"); err == nil {
 		t.Error("GetJobValidTransitions with non-existent job expected error")
 	}
 }
 
-// TestTransitionRules_Immutability tests that TransitionRules cannot be modified externally.
-func TestTransitionRules_Immutability(t *testing.T) {
-	// Get transitions for PENDING
-	original := GetValidTransitions(StatusPending)
-	if len(original) != 2 {
-		t.Fatalf("PENDING should have 2 valid transitions")
-	}
-
-	// Try to modify the returned slice
-	original[0] = StatusCompleted
-
-	// Get transitions again - should be unchanged
-	newTransitions := GetValidTransitions(StatusPending)
-	if newTransitions[0] != StatusRunning {
-		t.Error("TransitionRules was modified externally - slice copy not working")
-	}
-}
-
 // TestMainWorkflow_PendingRunningCompleted tests the main workflow: PENDING -> RUNNING -> COMPLETED.
 func TestMainWorkflow_PendingRunningCompleted(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
-
-	m := NewManager(statePath)
-	if err := m.Load(); err != nil {
-		t.Fatalf("Load() error = %v", err)
-	}
-
-	module := &ModuleState{
-		Name:      "test_module",
-		Status:    StatusPending,
-		Jobs:      make(map[string]*JobState),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	m.SetModule(module)
-
-	job := &JobState{
-		Name:      "test_job",
-		Status:    StatusPending,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	m.SetJob("test_module", job)
-
+	m, _ := setupTestManager(t, createTestJob("test_job", StatusPending))
 	logger := &mockLogger{}
 
-	// PENDING -> RUNNING
-	if err := m.TransitionJobStatus("test_module", "test_job", StatusRunning, logger); err != nil {
-		t.Fatalf("PENDING -> RUNNING failed: %v", err)
-	}
-
-	// RUNNING -> COMPLETED
-	if err := m.TransitionJobStatus("test_module", "test_job", StatusCompleted, logger); err != nil {
-		t.Fatalf("RUNNING -> COMPLETED failed: %v", err)
+	for _, to := range []Status{StatusRunning, StatusCompleted} {
+		if err := m.TransitionJobStatus("test_module", "test_job", to, logger); err != nil {
+			t.Fatalf("transition to %s failed: %v", to, err)
+		}
 	}
 
-	// Verify final state
-	finalJob := m.GetJob("test_module", "test_job")
-	if finalJob.Status != StatusCompleted {
-		t.Errorf("Final status = %s, expected COMPLETED", finalJob.Status)
+	if got := m.GetJob("test_module", "test_job").Status; got != StatusCompleted {
+		t.Errorf("Final status = %s, expected COMPLETED", got)
 	}
 
 	// COMPLETED is terminal - no more transitions should be possible
@@ -800,63 +416,31 @@ func TestMainWorkflow_PendingRunningCompleted(t *testing.T) {
 	}
 }
 
-// TestMainWorkflow_WithFailure tests the workflow with failure: PENDING -> RUNNING -> FAILED -> PENDING -> RUNNING -> COMPLETED.
+// TestMainWorkflow_WithFailure tests the workflow with failure:
+// PENDING -> RUNNING -> FAILED -> PENDING -> RUNNING -> INTERRUPTED -> RUNNING -> COMPLETED.
 func TestMainWorkflow_WithFailure(t *testing.T) {
-	tmpDir := t.TempDir()
-	statePath := filepath.Join(tmpDir, "status.json")
-
-	m := NewManager(statePath)
-	if err := m.Load(); err != nil {
-		t.Fatalf("Load() error = %v", err)
-	}
-
-	module := &ModuleState{
-		Name:      "test_module",
-		Status:    StatusPending,
-		Jobs:      make(map[string]*JobState),
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	m.SetModule(module)
-
-	job := &JobState{
-		Name:      "test_job",
-		Status:    StatusPending,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-	m.SetJob("test_module", job)
-
+	m, _ := setupTestManager(t, createTestJob("test_job", StatusPending))
 	logger := &mockLogger{}
 
-	// PENDING -> RUNNING
-	if err := m.TransitionJobStatus("test_module", "test_job", StatusRunning, logger); err != nil {
-		t.Fatalf("PENDING -> RUNNING failed: %v", err)
-	}
-
-	// RUNNING -> FAILED
-	if err := m.TransitionJobStatus("test_module", "test_job", StatusFailed, logger); err != nil {
-		t.Fatalf("RUNNING -> FAILED failed: %v", err)
-	}
-
-	// FAILED -> PENDING (retry)
-	if err := m.TransitionJobStatus("test_module", "test_job", StatusPending, logger); err != nil {
-		t.Fatalf("FAILED -> PENDING failed: %v", err)
-	}
-
-	// PENDING -> RUNNING (retry)
-	if err := m.TransitionJobStatus("test_module", "test_job", StatusRunning, logger); err != nil {
-		t.Fatalf("PENDING -> RUNNING (retry) failed: %v", err)
+	steps := []Status{
+		StatusRunning,
+		StatusFailed,
+		StatusPending,
+		StatusRunning,
+		StatusInterrupted,
+		StatusRunning,
+		StatusCompleted,
+	}
+	for _, to := range steps {
+		if err := m.TransitionJobStatus("test_module", "test_job", to, logger); err != nil {
+			t.Fatalf("transition to %s failed: %v", to, err)
+		}
 	}
 
-	// RUNNING -> COMPLETED
-	if err := m.TransitionJobStatus("test_module", "test_job", StatusCompleted, logger); err != nil {
-		t.Fatalf("RUNNING -> COMPLETED failed: %v", err)
+	if got := m.GetJob("test_module", "test_job").Status; got != StatusCompleted {
+		t.Errorf("Final status = %s, expected COMPLETED", got)
 	}
-
-	// Verify final state
-	finalJob := m.GetJob("test_module", "test_job")
-	if finalJob.Status != StatusCompleted {
-		t.Errorf("Final status = %s, expected COMPLETED", finalJob.Status)
+	if len(logger.logs) != 0 {
+		t.Errorf("Expected no errors to be logged, got %d", len(logger.logs))
 	}
 }
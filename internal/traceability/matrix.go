@@ -0,0 +1,269 @@
+// Package traceability links research documents to the plan modules that
+// cite them, their jobs and the commits that implemented those jobs, and
+// finds plan references to files that do not exist.
+package traceability
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/morty/morty/internal/git"
+	"github.com/morty/morty/internal/parser/plan"
+	"github.com/morty/morty/internal/state"
+)
+
+// Reference is a file referenced by a plan.
+type Reference struct {
+	// File is the plan file containing the reference.
+	File string `json:"file"`
+	// Section is the list the reference is in (research or references).
+	Section plan.Section `json:"section"`
+	// Item is the list item as written.
+	Item string `json:"item"`
+	// Path is the referenced path, relative to the project root.
+	Path string `json:"path"`
+	// Exists reports whether the path resolves to a file.
+	Exists bool `json:"exists"`
+}
+
+// Commit is a loop commit made for a job.
+type Commit struct {
+	Hash      string `json:"hash"`
+	ShortHash string `json:"short_hash"`
+	Loop      int    `json:"loop"`
+}
+
+// Job is a plan job with its status and commits.
+type Job struct {
+	Name string `json:"name"`
+	// Status is the status from status.json (PENDING if unknown).
+	Status state.Status `json:"status"`
+	// Commits are the job's commits, oldest first.
+	Commits []Commit `json:"commits"`
+}
+
+// Module is a plan module with the research it cites.
+type Module struct {
+	Name string `json:"name"`
+	File string `json:"file"`
+	// Research are the cited research documents, relative to the project root.
+	Research   []string    `json:"research"`
+	References []Reference `json:"references"`
+	Jobs       []Job       `json:"jobs"`
+}
+
+// Matrix is the traceability matrix of a project.
+type Matrix struct {
+	// Research lists every research document, relative to the project root.
+	Research []string `json:"research"`
+	Modules  []Module `json:"modules"`
+	// Dangling are references that do not resolve, including those in the
+	// plan index README.
+	Dangling []Reference `json:"dangling"`
+	// Unused are research documents no plan cites.
+	Unused []string `json:"unused"`
+}
+
+// Row is one research → module → job → commit line of the matrix. Empty
+// fields mean there is nothing at that level.
+type Row struct {
+	Research string       `json:"research"`
+	Module   string       `json:"module"`
+	Job      string       `json:"job"`
+	Status   state.Status `json:"status"`
+	Commit   string       `json:"commit"`
+}
+
+// Build creates the matrix from the plans in planDir. References are
+// resolved with refs; status and commits may be nil.
+func Build(planDir string, refs plan.ReferenceResolver, status *state.ExecutionStatus, commits []git.LoopCommit) (*Matrix, error) {
+	m := &Matrix{Research: []string{}, Modules: []Module{}, Dangling: []Reference{}, Unused: []string{}}
+
+	research, err := researchFiles(refs)
+	if err != nil {
+		return nil, err
+	}
+	m.Research = research
+
+	plans, err := state.ScanPlans(planDir)
+	if err != nil {
+		return nil, err
+	}
+
+	cited := make(map[string]bool)
+	for _, info := range plans {
+		parsed, err := plan.ParsePlanFile(filepath.Join(planDir, info.FileName))
+		if err != nil {
+			continue
+		}
+
+		module := Module{Name: info.Name, File: info.FileName, Research: []string{}, References: []Reference{}, Jobs: []Job{}}
+		for _, ref := range resolveAll(refs, info.FileName, plan.SectionResearch, parsed.Research) {
+			if ref.Exists {
+				module.Research = appendUnique(module.Research, ref.Path)
+				cited[ref.Path] = true
+			}
+			module.References = append(module.References, ref)
+		}
+		module.References = append(module.References,
+			resolveAll(refs, info.FileName, plan.SectionReferences, parsed.References)...)
+
+		for _, job := range info.Jobs {
+			module.Jobs = append(module.Jobs, Job{
+				Name:    job.Name,
+				Status:  jobStatus(status, info.Name, job.Name),
+				Commits: jobCommits(commits, info.Name, job),
+			})
+		}
+
+		for _, ref := range module.References {
+			if !ref.Exists {
+				m.Dangling = append(m.Dangling, ref)
+			}
+		}
+		m.Modules = append(m.Modules, module)
+	}
+
+	// The plan index lists the research the whole plan was built from
+	if content, err := os.ReadFile(filepath.Join(planDir, "README.md")); err == nil {
+		for _, ref := range resolveAll(refs, "README.md", plan.SectionResearch, plan.ListField(string(content), plan.SectionResearch)) {
+			if !ref.Exists {
+				m.Dangling = append(m.Dangling, ref)
+			}
+		}
+	}
+
+	for _, doc := range m.Research {
+		if !cited[doc] {
+			m.Unused = append(m.Unused, doc)
+		}
+	}
+	return m, nil
+}
+
+// Rows flattens the matrix: one row per job of every research document's
+// modules, one per job of modules citing no research, and one per unused
+// research document.
+func (m *Matrix) Rows() []Row {
+	var rows []Row
+	jobRows := func(research string, module Module) {
+		if len(module.Jobs) == 0 {
+			rows = append(rows, Row{Research: research, Module: module.Name})
+		}
+		for _, job := range module.Jobs {
+			row := Row{Research: research, Module: module.Name, Job: job.Name, Status: job.Status}
+			if len(job.Commits) == 0 {
+				rows = append(rows, row)
+			}
+			for _, c := range job.Commits {
+				row.Commit = c.ShortHash
+				rows = append(rows, row)
+			}
+		}
+	}
+
+	for _, doc := range m.Research {
+		for _, module := range m.Modules {
+			if contains(module.Research, doc) {
+				jobRows(doc, module)
+			}
+		}
+	}
+	for _, module := range m.Modules {
+		if len(module.Research) == 0 {
+			jobRows("", module)
+		}
+	}
+	for _, doc := range m.Unused {
+		rows = append(rows, Row{Research: doc})
+	}
+	return rows
+}
+
+// researchFiles lists the research documents relative to the project root.
+func researchFiles(refs plan.ReferenceResolver) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(refs.ResearchDir, "*.md"))
+	if err != nil {
+		return nil, err
+	}
+	docs := make([]string, 0, len(files))
+	for _, f := range files {
+		docs = append(docs, relPath(refs.Root, f))
+	}
+	sort.Strings(docs)
+	return docs, nil
+}
+
+// resolveAll resolves the file references among items.
+func resolveAll(refs plan.ReferenceResolver, file string, section plan.Section, items []string) []Reference {
+	var resolved []Reference
+	for _, item := range items {
+		path, ok := plan.ReferencePath(item)
+		if !ok {
+			continue
+		}
+		abs, exists := refs.Resolve(section, path)
+		resolved = append(resolved, Reference{
+			File:    file,
+			Section: section,
+			Item:    item,
+			Path:    relPath(refs.Root, abs),
+			Exists:  exists,
+		})
+	}
+	return resolved
+}
+
+// jobStatus returns a job's status from status.json, PENDING if unknown.
+func jobStatus(status *state.ExecutionStatus, module, job string) state.Status {
+	if status != nil {
+		if ms := status.GetModuleByName(module); ms != nil {
+			if js := ms.GetJobByName(job); js != nil {
+				return js.Status
+			}
+		}
+	}
+	return state.StatusPending
+}
+
+// jobCommits returns the commits made for a job, oldest first. Commits
+// may name the job by name or as job_N.
+func jobCommits(commits []git.LoopCommit, module string, job state.JobInfo) []Commit {
+	result := []Commit{}
+	for i := len(commits) - 1; i >= 0; i-- {
+		c := commits[i]
+		if c.Module != module || (c.Job != job.Name && c.Job != fmt.Sprintf("job_%d", job.Index)) {
+			continue
+		}
+		result = append(result, Commit{Hash: c.CommitHash, ShortHash: c.ShortHash, Loop: c.LoopNumber})
+	}
+	return result
+}
+
+// relPath returns path relative to root, or path itself if it is outside.
+func relPath(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func appendUnique(list []string, s string) []string {
+	if contains(list, s) {
+		return list
+	}
+	return append(list, s)
+}
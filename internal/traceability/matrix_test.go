@@ -0,0 +1,149 @@
+package traceability
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/morty/morty/internal/git"
+	"github.com/morty/morty/internal/parser/plan"
+	"github.com/morty/morty/internal/state"
+)
+
+const cachePlan = `# Plan: cache
+
+## 模块概述
+
+**模块职责**: 缓存层
+
+**对应 Research**:
+- ` + "`.morty/research/cache.md`" + ` - 缓存调研
+- ` + "`.morty/research/renamed.md`" + ` - 已改名
+
+**现有实现参考**:
+- ` + "`internal/cache/lru.go:12`" + ` - LRU
+- ` + "`internal/cache/missing.go`" + ` - 不存在
+- 标准库 container/list 的用法
+
+**依赖模块**: 无
+
+**被依赖模块**: 无
+
+## Jobs
+
+### Job 1: 内存缓存
+
+#### 目标
+
+实现 LRU 缓存
+
+#### Tasks
+
+- [ ] Task 1: 实现 Get
+
+### Job 2: 过期
+
+#### 目标
+
+实现 TTL
+
+#### Tasks
+
+- [ ] Task 1: 实现过期
+`
+
+const authPlan = `# Plan: auth
+
+## 模块概述
+
+**模块职责**: 登录
+
+**对应 Research**: 无
+
+**现有实现参考**: 无
+
+**依赖模块**: 无
+
+**被依赖模块**: 无
+
+## Jobs
+
+### Job 1: 登录接口
+
+#### 目标
+
+实现登录
+
+#### Tasks
+
+- [ ] Task 1: 实现登录
+`
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	root := t.TempDir()
+	planDir := filepath.Join(root, ".morty", "plan")
+	researchDir := filepath.Join(root, ".morty", "research")
+	writeFile(t, filepath.Join(planDir, "cache.md"), cachePlan)
+	writeFile(t, filepath.Join(planDir, "auth.md"), authPlan)
+	writeFile(t, filepath.Join(planDir, "README.md"), "# Plan 索引\n\n**对应 Research**:\n- `.morty/research/old.md` - 旧文档\n")
+	writeFile(t, filepath.Join(researchDir, "cache.md"), "# 缓存\n")
+	writeFile(t, filepath.Join(researchDir, "unused.md"), "# 没用上\n")
+	writeFile(t, filepath.Join(root, "internal", "cache", "lru.go"), "package cache\n")
+
+	status := &state.ExecutionStatus{Modules: []state.ModuleState{
+		{Name: "cache", Jobs: []state.JobState{{Name: "内存缓存", Status: state.StatusCompleted}}},
+	}}
+	commits := []git.LoopCommit{
+		{ShortHash: "ccc3333", LoopNumber: 3, Module: "cache", Job: "job_1"},
+		{ShortHash: "bbb2222", LoopNumber: 2, Module: "auth", Job: "登录接口"},
+		{ShortHash: "aaa1111", LoopNumber: 1, Module: "cache", Job: "内存缓存"},
+	}
+
+	refs := plan.ReferenceResolver{Root: root, ResearchDir: researchDir}
+	m, err := Build(planDir, refs, status, commits)
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+
+	if !reflect.DeepEqual(m.Research, []string{".morty/research/cache.md", ".morty/research/unused.md"}) {
+		t.Errorf("Research = %q", m.Research)
+	}
+	if !reflect.DeepEqual(m.Unused, []string{".morty/research/unused.md"}) {
+		t.Errorf("Unused = %q", m.Unused)
+	}
+
+	var dangling []string
+	for _, ref := range m.Dangling {
+		dangling = append(dangling, ref.File+" "+ref.Path)
+	}
+	want := []string{
+		"cache.md .morty/research/renamed.md",
+		"cache.md internal/cache/missing.go",
+		"README.md .morty/research/old.md",
+	}
+	if !reflect.DeepEqual(dangling, want) {
+		t.Errorf("Dangling = %q, want %q", dangling, want)
+	}
+
+	wantRows := []Row{
+		{Research: ".morty/research/cache.md", Module: "cache", Job: "内存缓存", Status: state.StatusCompleted, Commit: "aaa1111"},
+		{Research: ".morty/research/cache.md", Module: "cache", Job: "内存缓存", Status: state.StatusCompleted, Commit: "ccc3333"},
+		{Research: ".morty/research/cache.md", Module: "cache", Job: "过期", Status: state.StatusPending},
+		{Module: "auth", Job: "登录接口", Status: state.StatusPending, Commit: "bbb2222"},
+		{Research: ".morty/research/unused.md"},
+	}
+	if rows := m.Rows(); !reflect.DeepEqual(rows, wantRows) {
+		t.Errorf("Rows() =\n%+v\nwant\n%+v", rows, wantRows)
+	}
+}
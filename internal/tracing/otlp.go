@@ -0,0 +1,238 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/morty/morty/internal/logging"
+)
+
+// Exporter sends finished spans to a trace backend.
+type Exporter interface {
+	// Export sends spans recorded for serviceName.
+	Export(ctx context.Context, serviceName string, spans []*Span) error
+}
+
+// OTLP/JSON span kind and status codes.
+const (
+	spanKindInternal = 1
+	statusCodeOK     = 1
+	statusCodeError  = 2
+)
+
+// exportRequest is an OTLP ExportTraceServiceRequest in its JSON encoding.
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope      `json:"scope"`
+	Spans []spanJSON `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type spanJSON struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	ParentSpanID      string     `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              int        `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	Status            status     `json:"status"`
+}
+
+type status struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+// anyValue holds exactly one OTLP attribute value. 64-bit integers are
+// encoded as strings, as the OTLP/JSON mapping requires.
+type anyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+// EncodeOTLP encodes spans as an OTLP/JSON ExportTraceServiceRequest.
+func EncodeOTLP(serviceName string, spans []*Span) ([]byte, error) {
+	encoded := make([]spanJSON, 0, len(spans))
+	for _, span := range spans {
+		encoded = append(encoded, span.toJSON())
+	}
+
+	req := exportRequest{ResourceSpans: []resourceSpans{{
+		Resource: resource{Attributes: []keyValue{
+			toKeyValue(logging.String("service.name", serviceName)),
+		}},
+		ScopeSpans: []scopeSpans{{
+			Scope: scope{Name: "github.com/morty/morty"},
+			Spans: encoded,
+		}},
+	}}}
+	return json.Marshal(req)
+}
+
+// toJSON converts an ended span to its OTLP/JSON form.
+func (s *Span) toJSON() spanJSON {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := spanJSON{
+		TraceID:           hex.EncodeToString(s.traceID[:]),
+		SpanID:            hex.EncodeToString(s.spanID[:]),
+		Name:              s.name,
+		Kind:              spanKindInternal,
+		StartTimeUnixNano: unixNano(s.start),
+		EndTimeUnixNano:   unixNano(s.end),
+		Status:            status{Code: statusCodeOK},
+	}
+	if s.parentID != ([8]byte{}) {
+		out.ParentSpanID = hex.EncodeToString(s.parentID[:])
+	}
+	for _, attr := range s.attrs {
+		out.Attributes = append(out.Attributes, toKeyValue(attr))
+	}
+	if s.failed {
+		out.Status = status{Code: statusCodeError, Message: s.err}
+	}
+	return out
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// toKeyValue converts a logging attribute to an OTLP attribute.
+func toKeyValue(attr logging.Attr) keyValue {
+	var v anyValue
+	switch value := attr.Value.(type) {
+	case string:
+		v.StringValue = &value
+	case bool:
+		v.BoolValue = &value
+	case int:
+		s := strconv.Itoa(value)
+		v.IntValue = &s
+	case int64:
+		s := strconv.FormatInt(value, 10)
+		v.IntValue = &s
+	case float64:
+		v.DoubleValue = &value
+	case time.Duration:
+		s := strconv.FormatInt(value.Milliseconds(), 10)
+		v.IntValue = &s
+	default:
+		s := fmt.Sprint(value)
+		v.StringValue = &s
+	}
+	return keyValue{Key: attr.Key, Value: v}
+}
+
+// FileExporter appends one OTLP/JSON export request per line to a file,
+// the format read by the OpenTelemetry Collector's otlpjsonfile receiver.
+type FileExporter struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileExporter creates an exporter writing to path.
+func NewFileExporter(path string) *FileExporter {
+	return &FileExporter{path: path}
+}
+
+// Export appends spans to the trace file.
+func (e *FileExporter) Export(ctx context.Context, serviceName string, spans []*Span) error {
+	data, err := EncodeOTLP(serviceName, spans)
+	if err != nil {
+		return fmt.Errorf("failed to encode spans: %w", err)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(e.path), 0755); err != nil {
+		return fmt.Errorf("failed to create trace directory: %w", err)
+	}
+	f, err := os.OpenFile(e.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open trace file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write trace file: %w", err)
+	}
+	return nil
+}
+
+// HTTPExporter posts OTLP/JSON export requests to an OTLP HTTP endpoint
+// (e.g. http://localhost:4318/v1/traces).
+type HTTPExporter struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewHTTPExporter creates an exporter posting to endpoint.
+func NewHTTPExporter(endpoint string) *HTTPExporter {
+	return &HTTPExporter{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Export posts spans to the endpoint.
+func (e *HTTPExporter) Export(ctx context.Context, serviceName string, spans []*Span) error {
+	data, err := EncodeOTLP(serviceName, spans)
+	if err != nil {
+		return fmt.Errorf("failed to encode spans: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create export request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to export spans to %s: %w", e.endpoint, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to export spans to %s: %s", e.endpoint, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+// Package tracing records OpenTelemetry-compatible trace spans for Morty.
+// Spans are carried in a context.Context, like the module and job of the
+// logging package, and exported as OTLP/JSON to a file or an OTLP HTTP
+// endpoint (e.g. a local Jaeger).
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/morty/morty/internal/logging"
+)
+
+// defaultBatchSize is the number of finished spans buffered before export.
+const defaultBatchSize = 64
+
+// Tracer creates spans and exports them when they end.
+type Tracer struct {
+	serviceName string
+	exporter    Exporter
+	batchSize   int
+
+	mu      sync.Mutex
+	pending []*Span
+}
+
+// NewTracer creates a tracer that exports spans for serviceName.
+func NewTracer(serviceName string, exporter Exporter) *Tracer {
+	return &Tracer{
+		serviceName: serviceName,
+		exporter:    exporter,
+		batchSize:   defaultBatchSize,
+	}
+}
+
+// Flush exports all finished spans that have not been exported yet.
+func (t *Tracer) Flush(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	spans := t.pending
+	t.pending = nil
+	t.mu.Unlock()
+
+	if len(spans) == 0 {
+		return nil
+	}
+	return t.exporter.Export(ctx, t.serviceName, spans)
+}
+
+// Shutdown flushes the remaining spans. Spans ending afterwards are still
+// buffered and exported by the next Flush.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	return t.Flush(ctx)
+}
+
+// finish buffers an ended span, exporting a batch when it is full.
+func (t *Tracer) finish(span *Span) {
+	t.mu.Lock()
+	t.pending = append(t.pending, span)
+	full := len(t.pending) >= t.batchSize
+	t.mu.Unlock()
+
+	if full {
+		// Export errors surface on Shutdown; a dropped batch must not fail a job
+		_ = t.Flush(context.Background())
+	}
+}
+
+// Span is a timed operation within a trace.
+type Span struct {
+	tracer   *Tracer
+	traceID  [16]byte
+	spanID   [8]byte
+	parentID [8]byte
+	name     string
+	start    time.Time
+
+	mu     sync.Mutex
+	end    time.Time
+	attrs  []logging.Attr
+	err    string
+	failed bool
+	ended  bool
+}
+
+// TraceID returns the hex-encoded trace ID (empty for a no-op span).
+func (s *Span) TraceID() string {
+	if s == nil {
+		return ""
+	}
+	return hex.EncodeToString(s.traceID[:])
+}
+
+// SpanID returns the hex-encoded span ID (empty for a no-op span).
+func (s *Span) SpanID() string {
+	if s == nil {
+		return ""
+	}
+	return hex.EncodeToString(s.spanID[:])
+}
+
+// Name returns the span name.
+func (s *Span) Name() string {
+	if s == nil {
+		return ""
+	}
+	return s.name
+}
+
+// SetAttributes adds attributes to the span. Later values replace earlier
+// ones with the same key.
+func (s *Span) SetAttributes(attrs ...logging.Attr) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, attr := range attrs {
+		replaced := false
+		for i := range s.attrs {
+			if s.attrs[i].Key == attr.Key {
+				s.attrs[i] = attr
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			s.attrs = append(s.attrs, attr)
+		}
+	}
+}
+
+// End ends the span. A non-nil err marks the span as failed. Calling End
+// more than once has no effect.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.end = time.Now()
+	if err != nil {
+		s.failed = true
+		s.err = err.Error()
+	}
+	s.mu.Unlock()
+
+	s.tracer.finish(s)
+}
+
+// contextKey is the type for context keys.
+type contextKey string
+
+// context keys for storing the tracer and the current span.
+const (
+	contextKeyTracer contextKey = "tracer"
+	contextKeySpan   contextKey = "span"
+)
+
+// ContextWithTracer returns a context whose spans are recorded by t.
+// A nil tracer disables tracing.
+func ContextWithTracer(ctx context.Context, t *Tracer) context.Context {
+	return context.WithValue(ctx, contextKeyTracer, t)
+}
+
+// TracerFromContext returns the tracer of the context, or nil.
+func TracerFromContext(ctx context.Context) *Tracer {
+	if t, ok := ctx.Value(contextKeyTracer).(*Tracer); ok {
+		return t
+	}
+	return nil
+}
+
+// SpanFromContext returns the current span of the context, or nil.
+func SpanFromContext(ctx context.Context) *Span {
+	if s, ok := ctx.Value(contextKeySpan).(*Span); ok {
+		return s
+	}
+	return nil
+}
+
+// Start starts a span as a child of the context's current span and returns
+// a context carrying it. Without a tracer in the context it returns a nil
+// span, whose methods do nothing.
+func Start(ctx context.Context, name string, attrs ...logging.Attr) (context.Context, *Span) {
+	tracer := TracerFromContext(ctx)
+	if tracer == nil {
+		return ctx, nil
+	}
+
+	span := &Span{
+		tracer: tracer,
+		name:   name,
+		start:  time.Now(),
+	}
+	if parent := SpanFromContext(ctx); parent != nil {
+		span.traceID = parent.traceID
+		span.parentID = parent.spanID
+	} else {
+		rand.Read(span.traceID[:])
+	}
+	rand.Read(span.spanID[:])
+	span.SetAttributes(attrs...)
+
+	return context.WithValue(ctx, contextKeySpan, span), span
+}
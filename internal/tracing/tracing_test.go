@@ -0,0 +1,249 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/morty/morty/internal/logging"
+)
+
+// memoryExporter keeps exported spans in memory.
+type memoryExporter struct {
+	batches [][]*Span
+}
+
+func (e *memoryExporter) Export(ctx context.Context, serviceName string, spans []*Span) error {
+	e.batches = append(e.batches, spans)
+	return nil
+}
+
+func TestStart_WithoutTracer(t *testing.T) {
+	ctx, span := Start(context.Background(), "noop")
+	if span != nil {
+		t.Fatal("Expected nil span without a tracer")
+	}
+	// A nil span must be safe to use
+	span.SetAttributes(logging.String("k", "v"))
+	span.End(errors.New("ignored"))
+	if SpanFromContext(ctx) != nil {
+		t.Error("Expected no span in context")
+	}
+
+	var tracer *Tracer
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Errorf("Expected nil tracer shutdown to succeed, got %v", err)
+	}
+}
+
+func TestStart_Hierarchy(t *testing.T) {
+	exporter := &memoryExporter{}
+	tracer := NewTracer("morty", exporter)
+	ctx := ContextWithTracer(context.Background(), tracer)
+
+	ctx, root := Start(ctx, "doing.run")
+	jobCtx, job := Start(ctx, "job.attempt", logging.String("morty.job", "types"))
+	_, call := Start(jobCtx, "ai_cli.call")
+	call.End(nil)
+	job.End(errors.New("exit code 1"))
+	root.End(nil)
+	root.End(nil) // second End is ignored
+
+	if len(exporter.batches) != 0 {
+		t.Fatal("Expected spans to be buffered until flushed")
+	}
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if len(exporter.batches) != 1 || len(exporter.batches[0]) != 3 {
+		t.Fatalf("Expected one batch of 3 spans, got %v", exporter.batches)
+	}
+
+	if job.TraceID() != root.TraceID() || call.TraceID() != root.TraceID() {
+		t.Error("Expected child spans to share the root trace ID")
+	}
+	if job.parentID != root.spanID || call.parentID != job.spanID {
+		t.Error("Expected parent span IDs to follow the context")
+	}
+	if root.SpanID() == job.SpanID() {
+		t.Error("Expected unique span IDs")
+	}
+}
+
+func TestStart_BatchExport(t *testing.T) {
+	exporter := &memoryExporter{}
+	tracer := NewTracer("morty", exporter)
+	tracer.batchSize = 2
+	ctx := ContextWithTracer(context.Background(), tracer)
+
+	for i := 0; i < 3; i++ {
+		_, span := Start(ctx, "step")
+		span.End(nil)
+	}
+	if len(exporter.batches) != 1 || len(exporter.batches[0]) != 2 {
+		t.Fatalf("Expected a full batch to be exported, got %v", exporter.batches)
+	}
+	tracer.Flush(context.Background())
+	if len(exporter.batches) != 2 || len(exporter.batches[1]) != 1 {
+		t.Errorf("Expected flush to export the remaining span, got %v", exporter.batches)
+	}
+}
+
+func TestSetAttributes_Replaces(t *testing.T) {
+	tracer := NewTracer("morty", &memoryExporter{})
+	_, span := Start(ContextWithTracer(context.Background(), tracer), "s", logging.Int("n", 1))
+	span.SetAttributes(logging.Int("n", 2), logging.String("m", "x"))
+
+	if len(span.attrs) != 2 || span.attrs[0].Value != 2 {
+		t.Errorf("Expected n replaced and m added, got %v", span.attrs)
+	}
+}
+
+// decodeRequest decodes an OTLP/JSON export request into generic maps.
+func decodeRequest(t *testing.T, data []byte) []interface{} {
+	t.Helper()
+	var req struct {
+		ResourceSpans []struct {
+			Resource struct {
+				Attributes []map[string]interface{} `json:"attributes"`
+			} `json:"resource"`
+			ScopeSpans []struct {
+				Spans []interface{} `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	if err := json.Unmarshal(data, &req); err != nil {
+		t.Fatalf("Invalid OTLP/JSON: %v\n%s", err, data)
+	}
+	if len(req.ResourceSpans) != 1 || len(req.ResourceSpans[0].ScopeSpans) != 1 {
+		t.Fatalf("Unexpected request shape: %s", data)
+	}
+	if !strings.Contains(string(data), `{"key":"service.name","value":{"stringValue":"morty"}}`) {
+		t.Errorf("Expected service.name resource attribute: %s", data)
+	}
+	return req.ResourceSpans[0].ScopeSpans[0].Spans
+}
+
+func TestEncodeOTLP(t *testing.T) {
+	tracer := NewTracer("morty", &memoryExporter{})
+	ctx := ContextWithTracer(context.Background(), tracer)
+	ctx, root := Start(ctx, "doing.run")
+	_, call := Start(ctx, "ai_cli.call",
+		logging.Int("gen_ai.usage.input_tokens", 1200),
+		logging.Bool("cached", true),
+		logging.Any("morty.cost_usd", 0.25),
+	)
+	call.End(errors.New("exit code 1"))
+	root.End(nil)
+
+	data, err := EncodeOTLP("morty", []*Span{call, root})
+	if err != nil {
+		t.Fatalf("EncodeOTLP failed: %v", err)
+	}
+	spans := decodeRequest(t, data)
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+
+	first := spans[0].(map[string]interface{})
+	if first["traceId"] != root.TraceID() || len(first["traceId"].(string)) != 32 {
+		t.Errorf("Expected hex trace ID %s, got %v", root.TraceID(), first["traceId"])
+	}
+	if first["parentSpanId"] != root.SpanID() {
+		t.Errorf("Expected parent span ID %s, got %v", root.SpanID(), first["parentSpanId"])
+	}
+	if _, ok := spans[1].(map[string]interface{})["parentSpanId"]; ok {
+		t.Error("Expected root span without parentSpanId")
+	}
+	if _, ok := first["startTimeUnixNano"].(string); !ok {
+		t.Error("Expected timestamps encoded as strings")
+	}
+	status := first["status"].(map[string]interface{})
+	if status["code"] != float64(statusCodeError) || status["message"] != "exit code 1" {
+		t.Errorf("Expected error status, got %v", status)
+	}
+
+	for _, want := range []string{
+		`{"key":"gen_ai.usage.input_tokens","value":{"intValue":"1200"}}`,
+		`{"key":"cached","value":{"boolValue":true}}`,
+		`{"key":"morty.cost_usd","value":{"doubleValue":0.25}}`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected %s in %s", want, data)
+		}
+	}
+}
+
+func TestFileExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces", "traces.jsonl")
+	tracer := NewTracer("morty", NewFileExporter(path))
+	ctx := ContextWithTracer(context.Background(), tracer)
+
+	for i := 0; i < 2; i++ {
+		_, span := Start(ctx, "doing.run")
+		span.End(nil)
+		if err := tracer.Flush(context.Background()); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Trace file not written: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected one export request per line, got %d lines", len(lines))
+	}
+	for _, line := range lines {
+		if spans := decodeRequest(t, []byte(line)); len(spans) != 1 {
+			t.Errorf("Expected 1 span per request, got %d", len(spans))
+		}
+	}
+}
+
+func TestHTTPExporter(t *testing.T) {
+	var body []byte
+	var contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tracer := NewTracer("morty", NewHTTPExporter(server.URL+"/v1/traces"))
+	_, span := Start(ContextWithTracer(context.Background(), tracer), "git.commit")
+	span.End(nil)
+	if err := tracer.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	if contentType != "application/json" {
+		t.Errorf("Expected JSON content type, got %q", contentType)
+	}
+	if spans := decodeRequest(t, body); len(spans) != 1 {
+		t.Errorf("Expected 1 exported span, got %d", len(spans))
+	}
+}
+
+func TestHTTPExporter_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	tracer := NewTracer("morty", NewHTTPExporter(server.URL))
+	_, span := Start(ContextWithTracer(context.Background(), tracer), "git.commit")
+	span.End(nil)
+	if err := tracer.Shutdown(context.Background()); err == nil || !strings.Contains(err.Error(), "400") {
+		t.Errorf("Expected export error with status, got %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package transcript
+
+import "strings"
+
+// DiffOp is the kind of a diff line.
+type DiffOp byte
+
+const (
+	DiffContext DiffOp = ' '
+	DiffAdd     DiffOp = '+'
+	DiffDelete  DiffOp = '-'
+)
+
+// DiffLine is a line of a file diff.
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+// FileDiff is a change made to a file by a tool call.
+type FileDiff struct {
+	Path  string
+	Lines []DiffLine
+}
+
+// String renders the diff in unified diff style, without hunk headers.
+func (d FileDiff) String() string {
+	var sb strings.Builder
+	for _, line := range d.Lines {
+		sb.WriteByte(byte(line.Op))
+		sb.WriteString(line.Text)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// Unified renders the changed lines with up to context unchanged lines
+// around them; skipped lines are marked with "@@".
+func (d FileDiff) Unified(context int) string {
+	keep := make([]bool, len(d.Lines))
+	for i, line := range d.Lines {
+		if line.Op == DiffContext {
+			continue
+		}
+		for j := i - context; j <= i+context; j++ {
+			if j >= 0 && j < len(keep) {
+				keep[j] = true
+			}
+		}
+	}
+
+	var sb strings.Builder
+	skipped := false
+	for i, line := range d.Lines {
+		if !keep[i] {
+			skipped = true
+			continue
+		}
+		if skipped && sb.Len() > 0 {
+			sb.WriteString("@@\n")
+		}
+		skipped = false
+		sb.WriteByte(byte(line.Op))
+		sb.WriteString(line.Text)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// Added and Deleted count the changed lines.
+func (d FileDiff) Added() int   { return d.count(DiffAdd) }
+func (d FileDiff) Deleted() int { return d.count(DiffDelete) }
+
+func (d FileDiff) count(op DiffOp) int {
+	n := 0
+	for _, line := range d.Lines {
+		if line.Op == op {
+			n++
+		}
+	}
+	return n
+}
+
+// toolDiffs returns the file changes made by the Edit, MultiEdit and Write
+// tools.
+func toolDiffs(tool string, input map[string]interface{}) []FileDiff {
+	path, _ := input["file_path"].(string)
+	switch tool {
+	case "Edit":
+		oldText, _ := input["old_string"].(string)
+		newText, _ := input["new_string"].(string)
+		return []FileDiff{{Path: path, Lines: lineDiff(oldText, newText)}}
+	case "MultiEdit":
+		edits, _ := input["edits"].([]interface{})
+		var diffs []FileDiff
+		for _, e := range edits {
+			edit, ok := e.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			oldText, _ := edit["old_string"].(string)
+			newText, _ := edit["new_string"].(string)
+			diffs = append(diffs, FileDiff{Path: path, Lines: lineDiff(oldText, newText)})
+		}
+		return diffs
+	case "Write":
+		content, _ := input["content"].(string)
+		return []FileDiff{{Path: path, Lines: lineDiff("", content)}}
+	}
+	return nil
+}
+
+// Diff returns the line diff of a file's old and new content.
+func Diff(path, oldText, newText string) FileDiff {
+	return FileDiff{Path: path, Lines: lineDiff(oldText, newText)}
+}
+
+// maxDiffCells bounds the LCS table; larger changes are shown as a full
+// replacement.
+const maxDiffCells = 1 << 20
+
+// lineDiff diffs two texts line by line using the longest common
+// subsequence of lines.
+func lineDiff(oldText, newText string) []DiffLine {
+	a, b := splitLines(oldText), splitLines(newText)
+
+	if len(a)*len(b) > maxDiffCells {
+		lines := make([]DiffLine, 0, len(a)+len(b))
+		for _, line := range a {
+			lines = append(lines, DiffLine{Op: DiffDelete, Text: line})
+		}
+		for _, line := range b {
+			lines = append(lines, DiffLine{Op: DiffAdd, Text: line})
+		}
+		return lines
+	}
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []DiffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, DiffLine{Op: DiffContext, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, DiffLine{Op: DiffDelete, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{Op: DiffAdd, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, DiffLine{Op: DiffDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, DiffLine{Op: DiffAdd, Text: b[j]})
+	}
+	return lines
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
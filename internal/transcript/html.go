@@ -0,0 +1,103 @@
+package transcript
+
+import (
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/morty/morty/internal/callcli"
+)
+
+// WriteHTML renders the transcript as a single HTML page with inline styles
+// and no external resources, so it can be attached to a review and opened
+// offline. Tool calls are collapsible.
+func WriteHTML(w io.Writer, t *Transcript) error {
+	return htmlTemplate.Execute(w, t)
+}
+
+var htmlTemplate = template.Must(template.New("transcript").Funcs(template.FuncMap{
+	"summaryRows": summaryRows,
+	"formatUsage": func(u *callcli.Usage) string { return formatUsage(*u) },
+	"trim":        strings.TrimSpace,
+	"diffClass": func(op DiffOp) string {
+		switch op {
+		case DiffAdd:
+			return "add"
+		case DiffDelete:
+			return "del"
+		}
+		return "ctx"
+	},
+	"opString": func(op DiffOp) string { return string(rune(op)) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Transcript: {{.Title}}</title>
+<style>
+body { font: 14px/1.5 -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #1f2328; max-width: 1000px; margin: 2em auto; padding: 0 1em; }
+h1 { font-size: 1.6em; border-bottom: 1px solid #d0d7de; padding-bottom: .3em; }
+h2 { font-size: 1.2em; margin-top: 2em; }
+table.summary td { padding: 2px 12px 2px 0; vertical-align: top; }
+table.summary td:first-child { color: #59636e; }
+.usage { color: #59636e; font-size: .9em; }
+.text { white-space: pre-wrap; margin: .8em 0; }
+details { border: 1px solid #d0d7de; border-radius: 6px; margin: .6em 0; }
+details[open] summary { border-bottom: 1px solid #d0d7de; }
+summary { cursor: pointer; padding: .4em .8em; background: #f6f8fa; }
+summary code { font-weight: 600; }
+details.error summary { background: #ffebe9; }
+.body { padding: .4em .8em; }
+.label { color: #59636e; font-size: .9em; margin-top: .6em; }
+pre { font: 12px/1.45 ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; background: #f6f8fa; padding: .6em; overflow-x: auto; white-space: pre-wrap; word-break: break-word; margin: .4em 0; }
+pre.diff { padding: 0; }
+pre.diff span { display: block; padding: 0 .6em; }
+.add { background: #dafbe1; }
+.del { background: #ffebe9; }
+.result.error { border-left: 3px solid #cf222e; }
+.final.error { color: #cf222e; }
+</style>
+</head>
+<body>
+<h1>Transcript: {{.Title}}</h1>
+<table class="summary">
+{{- range summaryRows .}}
+<tr><td>{{index . 0}}</td><td>{{index . 1}}</td></tr>
+{{- end}}
+</table>
+{{range .Turns}}
+<h2>Turn {{.Number}}</h2>
+{{- if .Usage}}
+<div class="usage">{{formatUsage .Usage}}</div>
+{{- end}}
+{{- range .Blocks}}
+{{- if .Tool}}{{with .Tool}}
+<details{{if .IsError}} class="error"{{end}}>
+<summary><code>{{.Name}}</code> {{.Summary}}</summary>
+<div class="body">
+{{- if .Diffs}}{{range .Diffs}}
+<div class="label">{{.Path}} (+{{.Added}} -{{.Deleted}})</div>
+<pre class="diff">{{range .Lines}}<span class="{{diffClass .Op}}">{{opString .Op}}{{.Text}}</span>{{end}}</pre>
+{{- end}}{{else if .InputJSON}}
+<div class="label">Input</div>
+<pre>{{.InputJSON}}</pre>
+{{- end}}
+{{- if .HasResult}}
+<div class="label">Result</div>
+<pre class="result{{if .IsError}} error{{end}}">{{.Result}}</pre>
+{{- end}}
+</div>
+</details>
+{{- end}}{{else}}
+<div class="text">{{trim .Text}}</div>
+{{- end}}
+{{- end}}
+{{end}}
+{{- if .Result}}
+<h2>Result</h2>
+<div class="text final{{if .IsError}} error{{end}}">{{trim .Result}}</div>
+{{- end}}
+</body>
+</html>
+`))
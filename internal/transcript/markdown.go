@@ -0,0 +1,133 @@
+package transcript
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/morty/morty/internal/callcli"
+)
+
+// WriteMarkdown renders the transcript as Markdown. Tool calls are wrapped
+// in <details> blocks so they render collapsed on GitHub and GitLab.
+func WriteMarkdown(w io.Writer, t *Transcript) error {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# Transcript: %s\n\n", t.Title())
+	sb.WriteString("| | |\n|---|---|\n")
+	for _, row := range summaryRows(t) {
+		fmt.Fprintf(&sb, "| %s | %s |\n", row[0], strings.ReplaceAll(row[1], "|", `\|`))
+	}
+	sb.WriteString("\n")
+
+	for _, turn := range t.Turns {
+		fmt.Fprintf(&sb, "## Turn %d\n\n", turn.Number)
+		if turn.Usage != nil {
+			fmt.Fprintf(&sb, "_%s_\n\n", formatUsage(*turn.Usage))
+		}
+		for _, block := range turn.Blocks {
+			if block.Tool == nil {
+				sb.WriteString(strings.TrimSpace(block.Text))
+				sb.WriteString("\n\n")
+				continue
+			}
+			writeMarkdownTool(&sb, block.Tool)
+		}
+	}
+
+	if t.Result != "" {
+		sb.WriteString("## Result\n\n")
+		if t.IsError {
+			sb.WriteString("**Error**\n\n")
+		}
+		sb.WriteString(strings.TrimSpace(t.Result))
+		sb.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeMarkdownTool(sb *strings.Builder, call *ToolCall) {
+	title := "`" + call.Name + "`"
+	if call.Summary != "" {
+		title += " " + strings.ReplaceAll(call.Summary, "<", "&lt;")
+	}
+	if call.IsError {
+		title += " (error)"
+	}
+	fmt.Fprintf(sb, "<details>\n<summary>%s</summary>\n\n", title)
+
+	if len(call.Diffs) > 0 {
+		for _, diff := range call.Diffs {
+			fmt.Fprintf(sb, "`%s` (+%d -%d)\n\n", diff.Path, diff.Added(), diff.Deleted())
+			writeCodeBlock(sb, "diff", diff.String())
+		}
+	} else if input := call.InputJSON(); input != "" {
+		writeCodeBlock(sb, "json", input)
+	}
+
+	if call.HasResult {
+		sb.WriteString("Result:\n\n")
+		writeCodeBlock(sb, "", call.Result)
+	}
+	sb.WriteString("</details>\n\n")
+}
+
+// writeCodeBlock writes a fenced code block with a fence longer than any
+// backtick run in the content.
+func writeCodeBlock(sb *strings.Builder, lang, content string) {
+	fence := "```"
+	for strings.Contains(content, fence) {
+		fence += "`"
+	}
+	fmt.Fprintf(sb, "%s%s\n%s\n%s\n\n", fence, lang, strings.TrimRight(content, "\n"), fence)
+}
+
+// summaryRows returns the header rows of a transcript.
+func summaryRows(t *Transcript) [][2]string {
+	var rows [][2]string
+	add := func(name, value string) {
+		if value != "" {
+			rows = append(rows, [2]string{name, value})
+		}
+	}
+	if !t.Started.IsZero() {
+		add("Started", t.Started.Format("2006-01-02 15:04:05"))
+	}
+	add("Session", t.SessionID)
+	add("Model", t.Model)
+	if t.NumTurns > 0 {
+		add("Turns", fmt.Sprint(t.NumTurns))
+	}
+	if t.Duration > 0 {
+		add("Duration", t.Duration.Round(time.Second).String())
+	}
+	if t.TotalTokens() > 0 {
+		add("Tokens", formatUsage(t.Usage))
+	}
+	if t.CostUSD > 0 {
+		add("Cost", fmt.Sprintf("$%.4f", t.CostUSD))
+	}
+	if t.Result != "" {
+		status := "success"
+		if t.IsError {
+			status = "error"
+		}
+		add("Status", status)
+	}
+	return rows
+}
+
+// formatUsage renders token usage, e.g. "1200 input, 300 output, 5000 cache read tokens".
+func formatUsage(u callcli.Usage) string {
+	parts := []string{fmt.Sprintf("%d input", u.InputTokens), fmt.Sprintf("%d output", u.OutputTokens)}
+	if u.CacheReadInputTokens > 0 {
+		parts = append(parts, fmt.Sprintf("%d cache read", u.CacheReadInputTokens))
+	}
+	if u.CacheCreationInputTokens > 0 {
+		parts = append(parts, fmt.Sprintf("%d cache write", u.CacheCreationInputTokens))
+	}
+	return strings.Join(parts, ", ") + " tokens"
+}
@@ -0,0 +1,264 @@
+// Package transcript renders the conversation of one AI CLI run as a
+// document for code review: the agent's messages turn by turn, its tool
+// calls with their results, file edits as diffs, and token usage and cost.
+package transcript
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/morty/morty/internal/callcli"
+)
+
+// Transcript is the conversation of one job attempt.
+type Transcript struct {
+	Module  string
+	Job     string
+	Attempt int
+	// Started is the start time of the attempt (zero if unknown).
+	Started time.Time
+
+	SessionID string
+	Model     string
+	Turns     []Turn
+
+	// Result is the final message of the session.
+	Result string
+	// IsError reports whether the session ended with an error.
+	IsError  bool
+	NumTurns int
+	Duration time.Duration
+	// Usage is the token usage of the whole session.
+	Usage   callcli.Usage
+	CostUSD float64
+}
+
+// Turn is one assistant message with the tool calls it made.
+type Turn struct {
+	// Number is the 1-based turn number.
+	Number int
+	Model  string
+	// Usage is the token usage of the message, if reported.
+	Usage  *callcli.Usage
+	Blocks []Block
+}
+
+// Block is a text block or a tool call of a turn. Exactly one of Text and
+// Tool is set.
+type Block struct {
+	Text string
+	Tool *ToolCall
+}
+
+// ToolCall is a tool call with its result.
+type ToolCall struct {
+	ID    string
+	Name  string
+	Input map[string]interface{}
+	// Summary is a short description of the call, such as the file path
+	// or the command.
+	Summary string
+	// Diffs are the file changes made by edit and write tools.
+	Diffs []FileDiff
+
+	// HasResult reports whether a result was received for the call.
+	HasResult bool
+	Result    string
+	IsError   bool
+}
+
+// Build builds a transcript from a parsed event stream. Consecutive events
+// of the same assistant message are merged into one turn.
+func Build(conversation *callcli.ConversationData) *Transcript {
+	t := &Transcript{
+		SessionID: conversation.SessionID,
+		Model:     conversation.Model,
+	}
+	calls := make(map[string]*ToolCall)
+	messageID := ""
+
+	for _, event := range conversation.Events {
+		switch event.Type {
+		case "assistant":
+			if event.Message == nil {
+				continue
+			}
+			msg := event.Message
+			if len(t.Turns) == 0 || msg.ID == "" || msg.ID != messageID {
+				t.Turns = append(t.Turns, Turn{Number: len(t.Turns) + 1, Model: msg.Model})
+				messageID = msg.ID
+			}
+			turn := &t.Turns[len(t.Turns)-1]
+			if msg.Usage != nil {
+				usage := *msg.Usage
+				turn.Usage = &usage
+			}
+			for _, block := range msg.Content {
+				switch block.Type {
+				case "text":
+					if strings.TrimSpace(block.Text) != "" {
+						turn.Blocks = append(turn.Blocks, Block{Text: block.Text})
+					}
+				case "tool_use":
+					call := newToolCall(block)
+					calls[block.ID] = call
+					turn.Blocks = append(turn.Blocks, Block{Tool: call})
+				}
+			}
+
+		case "user":
+			if event.Message == nil {
+				continue
+			}
+			for _, block := range event.Message.Content {
+				if block.Type != "tool_result" {
+					continue
+				}
+				if call, ok := calls[block.ToolUseID]; ok {
+					call.HasResult = true
+					call.Result = block.Content
+					call.IsError = block.IsError
+				}
+			}
+
+		case "result":
+			t.Result = event.Result
+			t.IsError = event.Subtype != "" && event.Subtype != "success"
+			t.NumTurns = event.NumTurns
+			t.Duration = time.Duration(event.DurationMs) * time.Millisecond
+			t.CostUSD = event.TotalCostUSD
+			if event.Usage != nil {
+				t.Usage = *event.Usage
+			}
+		}
+	}
+
+	if t.Model == "" {
+		for _, turn := range t.Turns {
+			if turn.Model != "" {
+				t.Model = turn.Model
+				break
+			}
+		}
+	}
+	return t
+}
+
+// summaryKeys are the tool input fields used to summarize a call, in order
+// of preference.
+var summaryKeys = []string{"file_path", "notebook_path", "command", "pattern", "path", "url", "query", "description", "prompt"}
+
+func newToolCall(block callcli.ContentBlock) *ToolCall {
+	call := &ToolCall{ID: block.ID, Name: block.Name, Input: block.Input}
+	for _, key := range summaryKeys {
+		if v, ok := block.Input[key].(string); ok && strings.TrimSpace(v) != "" {
+			call.Summary = summarize(v, 100)
+			break
+		}
+	}
+	call.Diffs = toolDiffs(block.Name, block.Input)
+	return call
+}
+
+// summarize returns the first line of s, shortened to max runes.
+func summarize(s string, max int) string {
+	s = strings.TrimSpace(s)
+	line, _, more := strings.Cut(s, "\n")
+	runes := []rune(line)
+	if len(runes) > max {
+		return string(runes[:max]) + "…"
+	}
+	if more {
+		return line + " …"
+	}
+	return line
+}
+
+// InputJSON returns the tool input as indented JSON.
+func (c *ToolCall) InputJSON() string {
+	if len(c.Input) == 0 {
+		return ""
+	}
+	data, err := json.MarshalIndent(c.Input, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%v", c.Input)
+	}
+	return string(data)
+}
+
+// TotalTokens returns the input plus output tokens of the session.
+func (t *Transcript) TotalTokens() int {
+	return t.Usage.InputTokens + t.Usage.OutputTokens
+}
+
+// Title returns "module/job (attempt N)".
+func (t *Transcript) Title() string {
+	title := t.Module + "/" + t.Job
+	if t.Attempt > 0 {
+		title += fmt.Sprintf(" (attempt %d)", t.Attempt)
+	}
+	return title
+}
+
+// File is a saved conversation of a job attempt.
+type File struct {
+	Path string
+	// Started is the attempt start time encoded in the file name.
+	Started time.Time
+}
+
+// fileTimestamp is the timestamp suffix of conversation file names.
+const fileTimestamp = "20060102_150405"
+
+// Find returns the saved conversations of a job in dir, oldest first. The
+// executor saves them next to the job log as
+// "{module}_{job}_{YYYYMMDD_HHMMSS}.json".
+func Find(dir, module, job string) ([]File, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log directory %s: %w", dir, err)
+	}
+
+	name := regexp.MustCompile(`^` + regexp.QuoteMeta(sanitize(module)+"_"+sanitize(job)) + `_(\d{8}_\d{6})\.json$`)
+	var files []File
+	for _, entry := range entries {
+		m := name.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || m == nil {
+			continue
+		}
+		started, _ := time.ParseInLocation(fileTimestamp, m[1], time.Local)
+		files = append(files, File{Path: filepath.Join(dir, entry.Name()), Started: started})
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].Started.Before(files[j].Started)
+	})
+	return files, nil
+}
+
+// sanitize mirrors the file name sanitizing used when logs are written.
+func sanitize(name string) string {
+	for _, char := range []string{"/", "\\", ":", "*", "?", "\"", "<", ">", "|", " "} {
+		name = strings.ReplaceAll(name, char, "_")
+	}
+	return name
+}
+
+// Load reads and builds the transcript of a saved conversation.
+func Load(file File) (*Transcript, error) {
+	conversation, err := callcli.NewConversationParser("").ParseFromFile(file.Path)
+	if err != nil {
+		return nil, err
+	}
+	t := Build(conversation)
+	t.Started = file.Started
+	return t, nil
+}
@@ -0,0 +1,211 @@
+package transcript
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/morty/morty/internal/callcli"
+)
+
+const sampleStream = `[
+  {"type":"system","subtype":"init","session_id":"sess-1","model":"claude-sonnet"},
+  {"type":"assistant","message":{"id":"msg_1","role":"assistant","model":"claude-sonnet","content":[{"type":"text","text":"I'll fix the greeting."}],"usage":{"input_tokens":100,"output_tokens":20}}},
+  {"type":"assistant","message":{"id":"msg_1","role":"assistant","model":"claude-sonnet","content":[{"type":"tool_use","id":"tool_1","name":"Edit","input":{"file_path":"main.go","old_string":"package main\nfunc hello() {\n\tprintln(\"hi\")\n}","new_string":"package main\nfunc hello() {\n\tprintln(\"hello\")\n}"}}],"usage":{"input_tokens":100,"output_tokens":45}}},
+  {"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"tool_1","content":"File updated"}]}},
+  {"type":"assistant","message":{"id":"msg_2","role":"assistant","content":[{"type":"tool_use","id":"tool_2","name":"Bash","input":{"command":"go test ./...","description":"Run tests"}}],"usage":{"input_tokens":150,"output_tokens":10}}},
+  {"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"tool_2","content":"FAIL <script>","is_error":true}]}},
+  {"type":"result","subtype":"success","result":"Greeting fixed.","duration_ms":65000,"num_turns":2,"total_cost_usd":0.0123,"usage":{"input_tokens":250,"output_tokens":55}}
+]`
+
+func parseSample(t *testing.T) *Transcript {
+	t.Helper()
+	conversation, err := callcli.NewConversationParser("").Parse(sampleStream)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	tr := Build(conversation)
+	tr.Module, tr.Job, tr.Attempt = "core", "greeting", 2
+	return tr
+}
+
+func TestBuild(t *testing.T) {
+	tr := parseSample(t)
+
+	if tr.SessionID != "sess-1" || tr.Model != "claude-sonnet" {
+		t.Errorf("Unexpected session %q model %q", tr.SessionID, tr.Model)
+	}
+	if len(tr.Turns) != 2 {
+		t.Fatalf("Expected events of one message merged into 2 turns, got %d", len(tr.Turns))
+	}
+
+	first := tr.Turns[0]
+	if len(first.Blocks) != 2 || first.Blocks[0].Text == "" || first.Blocks[1].Tool == nil {
+		t.Fatalf("Unexpected first turn blocks: %+v", first.Blocks)
+	}
+	if first.Usage == nil || first.Usage.OutputTokens != 45 {
+		t.Errorf("Expected last usage of the message, got %+v", first.Usage)
+	}
+	edit := first.Blocks[1].Tool
+	if edit.Summary != "main.go" || !edit.HasResult || edit.Result != "File updated" || edit.IsError {
+		t.Errorf("Unexpected edit call: %+v", edit)
+	}
+
+	bash := tr.Turns[1].Blocks[0].Tool
+	if bash.Summary != "go test ./..." || !bash.IsError || len(bash.Diffs) != 0 {
+		t.Errorf("Unexpected bash call: %+v", bash)
+	}
+
+	if tr.Result != "Greeting fixed." || tr.IsError || tr.NumTurns != 2 {
+		t.Errorf("Unexpected result: %q error=%v turns=%d", tr.Result, tr.IsError, tr.NumTurns)
+	}
+	if tr.Duration != 65*time.Second || tr.CostUSD != 0.0123 || tr.TotalTokens() != 305 {
+		t.Errorf("Unexpected totals: %v $%v %d tokens", tr.Duration, tr.CostUSD, tr.TotalTokens())
+	}
+}
+
+func TestToolDiffs(t *testing.T) {
+	diffs := toolDiffs("Edit", map[string]interface{}{
+		"file_path":  "a.go",
+		"old_string": "one\ntwo\nthree",
+		"new_string": "one\n2\nthree\nfour",
+	})
+	if len(diffs) != 1 {
+		t.Fatalf("Expected 1 diff, got %d", len(diffs))
+	}
+	want := " one\n-two\n+2\n three\n+four\n"
+	if got := diffs[0].String(); got != want {
+		t.Errorf("Unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+	if diffs[0].Added() != 2 || diffs[0].Deleted() != 1 {
+		t.Errorf("Unexpected counts +%d -%d", diffs[0].Added(), diffs[0].Deleted())
+	}
+
+	diffs = toolDiffs("Write", map[string]interface{}{"file_path": "b.go", "content": "x\ny\n"})
+	if len(diffs) != 1 || diffs[0].String() != "+x\n+y\n" {
+		t.Errorf("Unexpected write diff: %+v", diffs)
+	}
+
+	diffs = toolDiffs("MultiEdit", map[string]interface{}{
+		"file_path": "c.go",
+		"edits": []interface{}{
+			map[string]interface{}{"old_string": "a", "new_string": "b"},
+			map[string]interface{}{"old_string": "c", "new_string": "d"},
+		},
+	})
+	if len(diffs) != 2 || diffs[1].String() != "-c\n+d\n" {
+		t.Errorf("Unexpected multi-edit diffs: %+v", diffs)
+	}
+
+	if diffs := toolDiffs("Read", map[string]interface{}{"file_path": "a.go"}); diffs != nil {
+		t.Errorf("Expected no diff for Read, got %+v", diffs)
+	}
+}
+
+func TestDiffUnified(t *testing.T) {
+	d := Diff("a.md", "1\n2\n3\n4\n5\n6\n7\n8\n", "1\n2\nthree\n4\n5\n6\n7\neight\n")
+	want := " 2\n-3\n+three\n 4\n@@\n 7\n-8\n+eight\n"
+	if got := d.Unified(1); got != want {
+		t.Errorf("Unexpected diff:\n%s\nwant:\n%s", got, want)
+	}
+	if got := Diff("a.md", "x\n", "x\n").Unified(3); got != "" {
+		t.Errorf("Expected no output for unchanged files, got %q", got)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, parseSample(t)); err != nil {
+		t.Fatalf("WriteMarkdown failed: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"# Transcript: core/greeting (attempt 2)",
+		"| Cost | $0.0123 |",
+		"| Tokens | 250 input, 55 output tokens |",
+		"## Turn 1",
+		"_100 input, 45 output tokens_",
+		"<summary>`Edit` main.go</summary>",
+		"```diff\n package main\n func hello() {\n-\tprintln(\"hi\")\n+\tprintln(\"hello\")\n }\n```",
+		"<summary>`Bash` go test ./... (error)</summary>",
+		"\"command\": \"go test ./...\"",
+		"## Result\n\nGreeting fixed.",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteCodeBlock(t *testing.T) {
+	var sb strings.Builder
+	writeCodeBlock(&sb, "", "a ``` b")
+	if !strings.HasPrefix(sb.String(), "````\n") {
+		t.Errorf("Expected a longer fence, got:\n%s", sb.String())
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, parseSample(t)); err != nil {
+		t.Fatalf("WriteHTML failed: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"<title>Transcript: core/greeting (attempt 2)</title>",
+		"<style>",
+		"<summary><code>Edit</code> main.go</summary>",
+		`<span class="del">-	println(&#34;hi&#34;)</span>`,
+		`<span class="add">&#43;	println(&#34;hello&#34;)</span>`,
+		`<details class="error">`,
+		"FAIL &lt;script&gt;",
+		"100 input, 45 output tokens",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in:\n%s", want, out)
+		}
+	}
+	// The page must not load anything
+	for _, external := range []string{"<script", "<link", "src="} {
+		if strings.Contains(out, external) {
+			t.Errorf("Unexpected external resource %q", external)
+		}
+	}
+}
+
+func TestFindAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"core_greeting_20261018_100000.json",
+		"core_greeting_20261018_090000.json",
+		"core_greeting_20261018_090000.log",
+		"core_greeting_extra_20261018_090000.json",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(sampleStream), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := Find(dir, "core", "greeting")
+	if err != nil {
+		t.Fatalf("Find failed: %v", err)
+	}
+	if len(files) != 2 || filepath.Base(files[0].Path) != "core_greeting_20261018_090000.json" {
+		t.Fatalf("Unexpected files: %+v", files)
+	}
+
+	tr, err := Load(files[1])
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if tr.Started.Hour() != 10 || len(tr.Turns) != 2 {
+		t.Errorf("Unexpected transcript: started %v, %d turns", tr.Started, len(tr.Turns))
+	}
+
+	if files, err := Find(filepath.Join(dir, "missing"), "core", "greeting"); err != nil || files != nil {
+		t.Errorf("Expected no files for missing dir, got %v %v", files, err)
+	}
+}
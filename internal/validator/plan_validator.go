@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/morty/morty/internal/parser/plan"
@@ -13,7 +14,7 @@ import (
 
 // ValidationError represents a format validation error.
 type ValidationError struct {
-	Code     string // Error code (E001-E012)
+	Code     string // Error code (E001-E013)
 	File     string // File path
 	Line     int    // Line number (0 if not applicable)
 	Message  string // Error message
@@ -40,6 +41,7 @@ type ValidationResult struct {
 type PlanValidator struct {
 	planDir string
 	verbose bool
+	refs    *plan.ReferenceResolver
 }
 
 // NewPlanValidator creates a new plan validator.
@@ -50,13 +52,26 @@ func NewPlanValidator(planDir string, verbose bool) *PlanValidator {
 	}
 }
 
+// SetReferenceRoot enables checking that the research documents and
+// implementation files plans reference exist. Paths are resolved against
+// root, bare research file names against researchDir.
+func (v *PlanValidator) SetReferenceRoot(root, researchDir string) {
+	v.refs = &plan.ReferenceResolver{Root: root, ResearchDir: researchDir}
+}
+
 // ValidateAll validates all plan files in the plan directory.
+// Markdown, JSON and YAML plans are validated against the same rules.
 func (v *PlanValidator) ValidateAll() ([]*ValidationResult, error) {
-	// Find all .md files
-	files, err := filepath.Glob(filepath.Join(v.planDir, "*.md"))
-	if err != nil {
-		return nil, fmt.Errorf("failed to list plan files: %w", err)
+	// Find all plan files
+	var files []string
+	for _, pattern := range []string{"*.md", "*.json", "*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(v.planDir, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list plan files: %w", err)
+		}
+		files = append(files, matches...)
 	}
+	sort.Strings(files)
 
 	if len(files) == 0 {
 		return nil, fmt.Errorf("no plan files found in %s", v.planDir)
@@ -65,7 +80,7 @@ func (v *PlanValidator) ValidateAll() ([]*ValidationResult, error) {
 	// Check if e2e_test.md exists
 	e2eTestExists := false
 	for _, file := range files {
-		if filepath.Base(file) == "e2e_test.md" {
+		if plan.ModuleNameFromFile(file) == "e2e_test" {
 			e2eTestExists = true
 			break
 		}
@@ -136,8 +151,9 @@ func (v *PlanValidator) ValidateFile(filePath string) *ValidationResult {
 		return v.validateREADME(filePath, string(content), result)
 	}
 
-	// Parse plan file
-	planData, err := plan.ParsePlan(string(content))
+	// Parse plan file in the format implied by its extension
+	format := plan.FormatFromPath(filePath)
+	planData, err := plan.ParsePlanAs(string(content), format)
 	if err != nil {
 		result.Passed = false
 		result.Errors = append(result.Errors, &ValidationError{
@@ -148,6 +164,18 @@ func (v *PlanValidator) ValidateFile(filePath string) *ValidationResult {
 		return result
 	}
 
+	// Markdown plans take their name from the H1 title; structured plans
+	// must carry it explicitly
+	if format != plan.FormatMarkdown && planData.Name == "" {
+		result.Passed = false
+		result.Errors = append(result.Errors, &ValidationError{
+			Code:     "E002",
+			File:     filePath,
+			Message:  "缺少必需字段: name",
+			Expected: "模块名称",
+		})
+	}
+
 	// Validate structure
 	v.validatePlanStructure(filePath, planData, string(content), result)
 
@@ -156,13 +184,13 @@ func (v *PlanValidator) ValidateFile(filePath string) *ValidationResult {
 
 // validateFilename validates the file name format.
 func (v *PlanValidator) validateFilename(fileName string) error {
-	// Special cases: e2e_test.md and README.md
-	if fileName == "e2e_test.md" || fileName == "README.md" {
+	// Special cases: e2e_test.* and README.md
+	if plan.ModuleNameFromFile(fileName) == "e2e_test" || fileName == "README.md" {
 		return nil
 	}
 
 	// Regular files: lowercase, numbers, underscores only
-	matched, _ := regexp.MatchString(`^[a-z0-9_]+\.md$`, fileName)
+	matched, _ := regexp.MatchString(`^[a-z0-9_]+\.(md|json|ya?ml)$`, fileName)
 	if !matched {
 		return fmt.Errorf("invalid filename format")
 	}
@@ -179,6 +207,37 @@ func (v *PlanValidator) validatePlanStructure(filePath string, planData *plan.Pl
 
 	// Validate jobs
 	v.validateJobs(filePath, planData, lines, result)
+
+	// Validate referenced files
+	v.validateReferences(filePath, plan.SectionResearch, planData.Research, lines, result)
+	v.validateReferences(filePath, plan.SectionReferences, planData.References, lines, result)
+}
+
+// validateReferences checks that referenced files exist. It does nothing
+// unless SetReferenceRoot was called.
+func (v *PlanValidator) validateReferences(filePath string, section plan.Section, items []string, lines []string, result *ValidationResult) {
+	if v.refs == nil {
+		return
+	}
+
+	for _, item := range items {
+		path, ok := plan.ReferencePath(item)
+		if !ok {
+			continue
+		}
+		if _, exists := v.refs.Resolve(section, path); exists {
+			continue
+		}
+		result.Passed = false
+		result.Errors = append(result.Errors, &ValidationError{
+			Code:     "E013",
+			File:     filePath,
+			Line:     findLine(lines, path),
+			Message:  fmt.Sprintf("引用的文件不存在: %s", path),
+			Found:    item,
+			Expected: "相对于项目根目录的已有文件 (研究文档也可以只写文件名)",
+		})
+	}
 }
 
 // validateModuleOverview validates the module overview section.
@@ -367,23 +426,19 @@ func (v *PlanValidator) validateJob(filePath string, job *plan.Job, lines []stri
 func (v *PlanValidator) validateREADME(filePath, content string, result *ValidationResult) *ValidationResult {
 	lines := strings.Split(content, "\n")
 
-	// Check required sections
-	requiredSections := []string{
-		"# Plan 索引",
-		"## 模块列表",
-		"## 依赖关系图",
-		"## 执行顺序",
-		"## 统计信息",
-	}
-
-	for _, section := range requiredSections {
-		if !strings.Contains(content, section) {
+	// Check required sections: the # title, then ## sections, under any alias
+	for i, section := range plan.ReadmeSections {
+		level := 2
+		if i == 0 {
+			level = 1
+		}
+		if !plan.HasHeading(content, level, section) {
 			result.Passed = false
 			result.Errors = append(result.Errors, &ValidationError{
 				Code:     "E002",
 				File:     filePath,
 				Message:  "README 缺少必需 section",
-				Expected: section,
+				Expected: strings.Repeat("#", level) + " " + plan.Title(section, plan.LanguageZh),
 			})
 		}
 	}
@@ -391,61 +446,39 @@ func (v *PlanValidator) validateREADME(filePath, content string, result *Validat
 	// Validate module list table
 	v.validateModuleTable(filePath, lines, result)
 
+	// Validate referenced research documents
+	v.validateReferences(filePath, plan.SectionResearch, plan.ListField(content, plan.SectionResearch), lines, result)
+
 	return result
 }
 
-// validateModuleTable validates the module list table in README.
+// validateModuleTable validates the module list table in README. The
+// table is found by its module name and dependencies columns under any
+// heading alias.
 func (v *PlanValidator) validateModuleTable(filePath string, lines []string, result *ValidationResult) {
-	// Find table
-	inTable := false
-	headerFound := false
-	rowCount := 0
-
-	for i, line := range lines {
-		line = strings.TrimSpace(line)
-
-		// Check for table header
-		if strings.Contains(line, "模块名称") && strings.Contains(line, "依赖模块") {
-			headerFound = true
-			inTable = true
-			continue
-		}
-
-		// Skip separator line
-		if inTable && strings.HasPrefix(line, "|---") {
-			continue
-		}
-
-		// Parse table row
-		if inTable && strings.HasPrefix(line, "|") {
-			parts := strings.Split(line, "|")
-			if len(parts) < 6 {
-				result.Passed = false
-				result.Errors = append(result.Errors, &ValidationError{
-					Code:    "E010",
-					File:    filePath,
-					Line:    i + 1,
-					Message: "模块列表表格列数不正确",
-					Found:   fmt.Sprintf("%d 列", len(parts)-2),
-					Expected: "5 列 (模块名称, 文件, Jobs 数量, 依赖模块, 状态)",
-				})
-			}
-			rowCount++
-		}
-
-		// Stop at next section
-		if inTable && line != "" && !strings.HasPrefix(line, "|") {
-			break
-		}
-	}
-
-	if !headerFound {
+	table := plan.FindModuleTable(strings.Join(lines, "\n"))
+	if table == nil {
 		result.Passed = false
 		result.Errors = append(result.Errors, &ValidationError{
 			Code:    "E010",
 			File:    filePath,
 			Message: "README 中未找到模块列表表格",
 		})
+		return
+	}
+
+	for _, row := range table.Rows {
+		if len(row.Cells) < 5 {
+			result.Passed = false
+			result.Errors = append(result.Errors, &ValidationError{
+				Code:     "E010",
+				File:     filePath,
+				Line:     row.Line + 1,
+				Message:  "模块列表表格列数不正确",
+				Found:    fmt.Sprintf("%d 列", len(row.Cells)),
+				Expected: "5 列 (模块名称, 文件, Jobs 数量, 依赖模块, 状态)",
+			})
+		}
 	}
 }
 
@@ -500,6 +533,13 @@ func (v *PlanValidator) isValidCompletionStatus(status string) bool {
 		"❌ 失败",
 		"⏳ 待开始",
 		"无", // Allow "无" for backward compatibility
+		// English plans
+		"✅ Completed",
+		"🚧 In Progress",
+		"⏸️ Paused",
+		"❌ Failed",
+		"⏳ Pending",
+		"None",
 	}
 
 	for _, valid := range validStatuses {
@@ -525,6 +565,25 @@ func (v *PlanValidator) findJobLine(lines []string, jobName string) int {
 			return i + 1
 		}
 	}
+
+	// Structured plans: the job's "name" key (JSON or YAML)
+	for i, line := range lines {
+		trimmed := strings.TrimPrefix(strings.TrimSpace(line), "- ")
+		if (strings.HasPrefix(trimmed, `"name"`) || strings.HasPrefix(trimmed, "name:")) &&
+			strings.Contains(line, jobName) {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// findLine returns the 1-based number of the first line containing s, or 0.
+func findLine(lines []string, s string) int {
+	for i, line := range lines {
+		if strings.Contains(line, s) {
+			return i + 1
+		}
+	}
 	return 0
 }
 
@@ -0,0 +1,325 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/morty/morty/internal/parser/plan"
+)
+
+const validPlan = `# Plan: cache
+
+## 模块概述
+
+**模块职责**: 缓存层
+
+**对应 Research**: 无
+
+**现有实现参考**: 无
+
+**依赖模块**: 无
+
+**被依赖模块**: 无
+
+## 接口定义
+
+无
+
+## 数据模型
+
+无
+
+## Jobs
+
+---
+
+### Job 1: 内存缓存
+
+#### 目标
+
+实现 LRU 缓存
+
+#### 前置条件
+
+无
+
+#### Tasks
+
+- [ ] Task 1: 实现 Get
+- [ ] Task 2: 实现 Set
+
+#### 验证器
+
+- 命中率统计正确
+
+#### 调试日志
+
+无
+
+#### 完成状态
+
+⏳ 待开始
+
+---
+
+## 集成测试
+
+无
+`
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+// convertPlan converts the markdown plan into the given format.
+func convertPlan(t *testing.T, content string, format plan.Format) string {
+	t.Helper()
+	p, err := plan.ParsePlan(content)
+	if err != nil {
+		t.Fatalf("ParsePlan failed: %v", err)
+	}
+	data, err := plan.Marshal(p, format)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	return string(data)
+}
+
+func errorCodes(result *ValidationResult) []string {
+	var codes []string
+	for _, e := range result.Errors {
+		codes = append(codes, e.Code)
+	}
+	return codes
+}
+
+// TestValidateFileFormats tests that valid plans pass in every format.
+func TestValidateFileFormats(t *testing.T) {
+	dir := t.TempDir()
+	v := NewPlanValidator(dir, false)
+
+	files := map[string]string{
+		"cache.md":   validPlan,
+		"cache.json": convertPlan(t, validPlan, plan.FormatJSON),
+		"cache.yaml": convertPlan(t, validPlan, plan.FormatYAML),
+		"cache2.yml": convertPlan(t, validPlan, plan.FormatYAML),
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			result := v.ValidateFile(writeFile(t, dir, name, content))
+			if !result.Passed {
+				t.Errorf("Expected %s to pass, got %v", name, errorCodes(result))
+			}
+		})
+	}
+}
+
+// TestValidateFileEquivalentErrors tests that the same defect is reported
+// with the same codes regardless of format.
+func TestValidateFileEquivalentErrors(t *testing.T) {
+	broken := strings.Replace(validPlan, "⏳ 待开始", "进行中吧", 1)
+	broken = strings.Replace(broken, "- [ ] Task 2: 实现 Set", "- [ ] Task 3: 实现 Set", 1)
+
+	dir := t.TempDir()
+	v := NewPlanValidator(dir, false)
+
+	want := "E006,E008"
+	for _, format := range []plan.Format{plan.FormatMarkdown, plan.FormatJSON, plan.FormatYAML} {
+		t.Run(string(format), func(t *testing.T) {
+			name := "broken" + format.Extension()
+			result := v.ValidateFile(writeFile(t, dir, name, convertPlan(t, broken, format)))
+			if got := strings.Join(errorCodes(result), ","); got != want {
+				t.Errorf("Expected codes %s, got %s", want, got)
+			}
+			if format != plan.FormatMarkdown && result.Errors[0].Line == 0 {
+				t.Error("Expected job line to be located in structured plan")
+			}
+		})
+	}
+}
+
+// TestValidateFileEnglishHeadings tests that plans written with English
+// headings and status markers validate like Chinese ones.
+func TestValidateFileEnglishHeadings(t *testing.T) {
+	p, err := plan.ParsePlan(validPlan)
+	if err != nil {
+		t.Fatalf("ParsePlan failed: %v", err)
+	}
+	p.Jobs[0].CompletionStatus = "⏳ Pending"
+	english := plan.RenderMarkdownIn(p, plan.LanguageEn)
+
+	dir := t.TempDir()
+	v := NewPlanValidator(dir, false)
+
+	result := v.ValidateFile(writeFile(t, dir, "cache.md", english))
+	if !result.Passed {
+		t.Errorf("Expected English plan to pass, got %v\n%s", errorCodes(result), english)
+	}
+
+	broken := strings.Replace(english, "- 命中率统计正确\n", "", 1)
+	result = v.ValidateFile(writeFile(t, dir, "broken.md", broken))
+	if got := strings.Join(errorCodes(result), ","); got != "E002" {
+		t.Errorf("Expected missing validators to be reported as E002, got %s", got)
+	}
+}
+
+// englishReadme is a plan README written with English headings.
+const englishReadme = `# Plan Index
+
+## Modules
+
+| Module | File | Jobs | Dependencies | Status |
+|--------|------|------|--------------|--------|
+| cache | cache.md | 1 | None | Planned |
+| e2e_test | e2e_test.md | 1 | all modules | Planned |
+
+## Dependency Graph
+
+cache -> e2e_test
+
+## Execution Order
+
+1. cache
+2. e2e_test
+
+## Statistics
+
+- Modules: 2
+`
+
+// TestValidateREADMEAliases tests that README sections and the module table
+// are found under English headings and configured aliases.
+func TestValidateREADMEAliases(t *testing.T) {
+	dir := t.TempDir()
+	v := NewPlanValidator(dir, false)
+
+	result := v.ValidateFile(writeFile(t, dir, "README.md", englishReadme))
+	if !result.Passed {
+		t.Errorf("Expected English README to pass, got %v", errorCodes(result))
+	}
+
+	custom := strings.Replace(englishReadme, "| Module |", "| Component |", 1)
+	custom = strings.Replace(custom, "## Statistics", "## Totals", 1)
+	result = v.ValidateFile(writeFile(t, dir, "README.md", custom))
+	if got := strings.Join(errorCodes(result), ","); got != "E002,E010" {
+		t.Errorf("Expected E002,E010 without aliases, got %s", got)
+	}
+
+	h := plan.NewHeadings()
+	h.AddAliases(plan.SectionModuleName, "Component")
+	h.AddAliases(plan.SectionStatistics, "Totals")
+	plan.SetHeadings(h)
+	defer plan.SetHeadings(plan.NewHeadings())
+
+	result = v.ValidateFile(writeFile(t, dir, "README.md", custom))
+	if !result.Passed {
+		t.Errorf("Expected aliased README to pass, got %v", errorCodes(result))
+	}
+}
+
+// TestValidateFileStructuredErrors tests errors specific to structured plans.
+func TestValidateFileStructuredErrors(t *testing.T) {
+	dir := t.TempDir()
+	v := NewPlanValidator(dir, false)
+
+	result := v.ValidateFile(writeFile(t, dir, "bad.json", `{"name": "bad", "jobs": [`))
+	if result.Passed || result.Errors[0].Code != "E000" {
+		t.Errorf("Expected E000 parse error, got %v", errorCodes(result))
+	}
+
+	result = v.ValidateFile(writeFile(t, dir, "noname.yaml", "responsibility: x\njobs: []\n"))
+	codes := strings.Join(errorCodes(result), ",")
+	if !strings.Contains(codes, "E002") || result.Passed {
+		t.Errorf("Expected E002 for missing name and jobs, got %s", codes)
+	}
+}
+
+// TestValidateFilename tests filename rules for all plan formats.
+func TestValidateFilename(t *testing.T) {
+	v := NewPlanValidator("", false)
+
+	tests := map[string]bool{
+		"user_auth.md":   true,
+		"user_auth.json": true,
+		"user_auth.yaml": true,
+		"user_auth.yml":  true,
+		"e2e_test.yaml":  true,
+		"README.md":      true,
+		"UserAuth.md":    false,
+		"user-auth.json": false,
+		"user_auth.toml": false,
+	}
+
+	for name, valid := range tests {
+		if err := v.validateFilename(name); (err == nil) != valid {
+			t.Errorf("validateFilename(%q) valid = %v, want %v", name, err == nil, valid)
+		}
+	}
+}
+
+// TestValidateAllMixedFormats tests directory validation with mixed formats.
+func TestValidateAllMixedFormats(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "cache.md", validPlan)
+	writeFile(t, dir, "store.json", convertPlan(t, strings.Replace(validPlan, "cache", "store", 1), plan.FormatJSON))
+
+	v := NewPlanValidator(dir, false)
+	results, err := v.ValidateAll()
+	if err != nil {
+		t.Fatalf("ValidateAll failed: %v", err)
+	}
+
+	// Missing e2e_test plus the two plan files
+	if len(results) != 3 || results[0].Errors[0].Code != "E003" {
+		t.Fatalf("Expected E003 and two results, got %d results", len(results))
+	}
+
+	writeFile(t, dir, "e2e_test.yaml", convertPlan(t, strings.Replace(validPlan, "cache", "e2e_test", 1), plan.FormatYAML))
+	results, err = v.ValidateAll()
+	if err != nil {
+		t.Fatalf("ValidateAll failed: %v", err)
+	}
+	for _, r := range results {
+		if !r.Passed {
+			t.Errorf("Expected %s to pass, got %v", r.File, errorCodes(r))
+		}
+	}
+}
+
+// TestValidateReferences tests that referenced files must exist once a
+// reference root is set.
+func TestValidateReferences(t *testing.T) {
+	root := t.TempDir()
+	researchDir := filepath.Join(root, "research")
+	os.MkdirAll(researchDir, 0755)
+	writeFile(t, researchDir, "cache.md", "# 缓存\n")
+	writeFile(t, root, "lru.go", "package cache\n")
+
+	withRefs := strings.Replace(validPlan, "**对应 Research**: 无", "**对应 Research**:\n- `cache.md` - 调研\n- `research/gone.md` - 已删除", 1)
+	withRefs = strings.Replace(withRefs, "**现有实现参考**: 无", "**现有实现参考**:\n- `lru.go:10` - LRU\n- 标准库 container/list", 1)
+
+	dir := t.TempDir()
+	path := writeFile(t, dir, "cache.md", withRefs)
+
+	if result := NewPlanValidator(dir, false).ValidateFile(path); !result.Passed {
+		t.Errorf("Expected references to be unchecked by default, got %v", errorCodes(result))
+	}
+
+	v := NewPlanValidator(dir, false)
+	v.SetReferenceRoot(root, researchDir)
+	result := v.ValidateFile(path)
+	if got := strings.Join(errorCodes(result), ","); got != "E013" {
+		t.Fatalf("Expected E013, got %s", got)
+	}
+	if e := result.Errors[0]; e.Line != 9 || !strings.Contains(e.Message, "research/gone.md") {
+		t.Errorf("Unexpected error: %v", e)
+	}
+}
@@ -213,6 +213,7 @@ create_config() {
 
     cat > "${INSTALL_DIR}/config.json" << EOF
 {
+  "\$schema": "./settings.schema.json",
   "version": "2.0",
   "ai_cli": {
     "command": "claude",
@@ -226,13 +227,18 @@ create_config() {
     "level": "info",
     "format": "text"
   },
-  "defaults": {
+  "execution": {
     "max_retry_count": 3,
     "auto_git_commit": true
   }
 }
 EOF
 
+    # 供编辑器补全与校验配置使用
+    if ! "${BIN_DIR}/morty" config schema --output "${INSTALL_DIR}/settings.schema.json" > /dev/null 2>&1; then
+        print_warning "Failed to write ${INSTALL_DIR}/settings.schema.json"
+    fi
+
     print_success "Configuration created at ${INSTALL_DIR}/config.json"
 }
 